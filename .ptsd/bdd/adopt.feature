@@ -18,8 +18,14 @@ Feature: Existing Project Bootstrap
     Then discovered artifacts are listed
     And no files are created or moved
 
-  Scenario: Adopt refuses if .ptsd already initialized
+  Scenario: Adopt re-runs incrementally
     Given .ptsd/ already exists with features.yaml
     When I run "ptsd adopt"
-    Then exit code is 1
-    And output contains "err:validation already initialized"
+    Then ptsd.yaml and registered features are kept
+    And only new features and .feature files are imported
+
+  Scenario: Map file assigns feature IDs
+    Given features.map.yaml maps .feature and test files to feature IDs
+    When I run "ptsd adopt --map features.map.yaml"
+    Then mapped .feature files are merged into .ptsd/bdd/<id>.feature
+    And mapped test files are recorded as test mappings
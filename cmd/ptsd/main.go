@@ -3,17 +3,38 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/veschin/ptsd/internal/cli"
 )
 
 func main() {
-	agentMode := false
+	agentMode, jsonMode := false, false
 	var filteredArgs []string
 
-	for _, arg := range os.Args[1:] {
+	args := os.Args[1:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		if arg == "--agent" || arg == "-agent" {
 			agentMode = true
+		} else if arg == "--wait-lock" || strings.HasPrefix(arg, "--wait-lock=") {
+			// Global: how long to wait for the project lock (e.g. 30s, 0).
+			value, ok := strings.CutPrefix(arg, "--wait-lock=")
+			if !ok && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				fmt.Fprintf(os.Stderr, "err:user invalid --wait-lock %q: use a duration such as 30s or 0\n", value)
+				os.Exit(2)
+			}
+			cli.SetLockWait(d)
+		} else if arg == "--json" && len(filteredArgs) == 0 {
+			// Global --json (before the command); commands such as
+			// `feature list --json` keep their own flag.
+			jsonMode = true
 		} else {
 			filteredArgs = append(filteredArgs, arg)
 		}
@@ -32,13 +53,30 @@ func main() {
 	}
 	subargs := filteredArgs[1:]
 
+	var exitCode int
+	if jsonMode && !cli.JSONPassthrough(cmd) {
+		// JSON output implies agent mode: no prompts, no colors.
+		cli.SetJSONOutput(true)
+		exitCode = cli.RunWithJSON(cmd, func() int {
+			return cli.RunLocked(cmd, subargs, true, func() int { return run(cmd, subargs, true) })
+		})
+	} else {
+		exitCode = cli.RunLocked(cmd, subargs, agentMode, func() int { return run(cmd, subargs, agentMode) })
+	}
+
+	os.Exit(exitCode)
+}
+
+func run(cmd string, subargs []string, agentMode bool) int {
 	var exitCode int
 	switch cmd {
 	case "init":
 		exitCode = cli.RunInit(subargs, agentMode)
 	case "adopt":
 		exitCode = cli.RunAdopt(subargs, agentMode)
-	case "feature":
+	case "deinit":
+		exitCode = cli.RunDeinit(subargs, agentMode)
+	case "feature", "features":
 		exitCode = cli.RunFeature(subargs, agentMode)
 	case "config":
 		exitCode = cli.RunConfig(subargs, agentMode)
@@ -54,30 +92,76 @@ func main() {
 		exitCode = cli.RunTest(subargs, agentMode)
 	case "status":
 		exitCode = cli.RunStatus(subargs, agentMode)
+	case "stage":
+		exitCode = cli.RunStage(subargs, agentMode)
 	case "validate":
 		exitCode = cli.RunValidate(subargs, agentMode)
+	case "lint":
+		exitCode = cli.RunLint(subargs, agentMode)
+	case "doctor":
+		exitCode = cli.RunDoctor(subargs, agentMode)
+	case "trace":
+		exitCode = cli.RunTrace(subargs, agentMode)
+	case "events":
+		exitCode = cli.RunEvents(subargs, agentMode)
+	case "audit":
+		exitCode = cli.RunAudit(subargs, agentMode)
+	case "verify-log":
+		exitCode = cli.RunVerifyLog(subargs, agentMode)
 	case "hooks":
 		exitCode = cli.RunHooks(subargs, agentMode)
 	case "review":
 		exitCode = cli.RunReview(subargs, agentMode)
 	case "skills":
 		exitCode = cli.RunSkills(subargs, agentMode)
+	case "templates":
+		exitCode = cli.RunTemplates(subargs, agentMode)
 	case "issues":
 		exitCode = cli.RunIssues(subargs, agentMode)
 	case "context":
 		exitCode = cli.RunContext(subargs, agentMode)
+	case "gate":
+		exitCode = cli.RunGate(subargs, agentMode)
 	case "gate-check":
 		exitCode = cli.RunGateCheck(subargs, agentMode)
 	case "auto-track":
 		exitCode = cli.RunAutoTrack(subargs, agentMode)
+	case "daemon":
+		exitCode = cli.RunDaemon(subargs, agentMode)
+	case "migrate":
+		exitCode = cli.RunMigrate(subargs, agentMode)
+	case "snapshot":
+		exitCode = cli.RunSnapshot(subargs, agentMode)
+	case "restore":
+		exitCode = cli.RunRestore(subargs, agentMode)
+	case "undo":
+		exitCode = cli.RunUndo(subargs, agentMode)
+	case "history":
+		exitCode = cli.RunHistory(subargs, agentMode)
+	case "remote":
+		exitCode = cli.RunRemote(subargs, agentMode)
+	case "all":
+		exitCode = cli.RunAll(subargs, agentMode)
+	case "report":
+		exitCode = cli.RunReport(subargs, agentMode)
 	case "help":
 		exitCode = cli.RunHelp(subargs, agentMode)
+	case "verify-hooks":
+		exitCode = cli.RunVerifyHooks(subargs, agentMode)
 	case "version":
 		exitCode = cli.RunVersion(subargs, agentMode)
+	case "completion":
+		exitCode = cli.RunCompletion(subargs, agentMode)
+	case "__complete":
+		// Hidden: called by the completion scripts.
+		exitCode = cli.RunComplete(subargs, agentMode)
 	default:
-		fmt.Fprintf(os.Stderr, "err:user unknown command: %s\n", cmd)
-		exitCode = 2
+		code, found := cli.RunPlugin(cmd, subargs, agentMode)
+		if !found {
+			fmt.Fprintf(os.Stderr, "err:user unknown command: %s\n", cmd)
+			code = 2
+		}
+		exitCode = code
 	}
-
-	os.Exit(exitCode)
+	return exitCode
 }
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
 )
 
 func getPtsdBinary(t *testing.T) string {
@@ -197,3 +201,171 @@ func TestMain_StatusRouted(t *testing.T) {
 		t.Errorf("expected status output, got: %s", out)
 	}
 }
+
+// Scenario: all aggregates status across workspaces
+// Given a root project with a nested workspace
+// When I run "ptsd all status --agent"
+// Then each workspace output is prefixed with its path
+func TestMain_AllStatus(t *testing.T) {
+	bin := getPtsdBinary(t)
+	dir := setupOutputProject(t)
+	nested := filepath.Join(dir, "services", "api", ".ptsd")
+	os.MkdirAll(nested, 0755)
+	os.WriteFile(filepath.Join(nested, "ptsd.yaml"), []byte("version: 1\n"), 0644)
+	os.WriteFile(filepath.Join(nested, "features.yaml"), []byte("features: []\n"), 0644)
+	os.WriteFile(filepath.Join(nested, "state.yaml"), []byte("features: {}\n"), 0644)
+
+	cmd := exec.Command(bin, "all", "status", "--agent")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("expected success, got error: %s", out)
+	}
+
+	output := string(out)
+	for _, prefix := range []string{"[.] ", "[" + filepath.Join("services", "api") + "] "} {
+		if !strings.Contains(output, prefix) {
+			t.Errorf("expected prefix %q in output, got: %s", prefix, output)
+		}
+	}
+}
+
+// Scenario: all passes the global flags to each workspace
+// Given another process holds the root workspace's lock
+// When I run "ptsd --json --wait-lock 100ms all validate"
+// Then one JSON document holds every workspace's own JSON result
+// And the locked workspace gives up after the forwarded wait
+func TestMain_AllForwardsGlobalFlags(t *testing.T) {
+	bin := getPtsdBinary(t)
+	dir := setupOutputProject(t)
+	nested := filepath.Join(dir, "services", "api", ".ptsd")
+	os.MkdirAll(nested, 0755)
+	os.WriteFile(filepath.Join(nested, "ptsd.yaml"), []byte("version: 1\n"), 0644)
+	os.WriteFile(filepath.Join(nested, "features.yaml"), []byte("features: []\n"), 0644)
+	os.WriteFile(filepath.Join(nested, "state.yaml"), []byte("features: {}\n"), 0644)
+	unlock, err := core.LockProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	start := time.Now()
+	cmd := exec.Command(bin, "--json", "--wait-lock", "100ms", "all", "validate")
+	cmd.Dir = dir
+	out, _ := cmd.Output()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("locked workspace did not use the forwarded --wait-lock: took %v", elapsed)
+	}
+
+	var env struct {
+		Schema string `json:"schema"`
+		Data   []struct {
+			Workspace string `json:"workspace"`
+			ExitCode  int    `json:"exit_code"`
+			Result    struct {
+				Schema string `json:"schema"`
+				Error  *struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &env); err != nil {
+		t.Fatalf("expected one JSON document, got %v: %s", err, out)
+	}
+	if env.Schema != "ptsd.all/v1" || len(env.Data) != 2 {
+		t.Fatalf("unexpected envelope: %s", out)
+	}
+	for _, ws := range env.Data {
+		if ws.Result.Schema == "" {
+			t.Errorf("workspace %s did not run with --json: %s", ws.Workspace, out)
+		}
+		if ws.Workspace == "." && (ws.ExitCode != 4 || ws.Result.Error == nil || !strings.Contains(ws.Result.Error.Message, "project is locked")) {
+			t.Errorf("expected the locked root to fail on the lock, got: %s", out)
+		}
+	}
+}
+
+// Scenario: all rejects unsupported commands
+// When I run "ptsd all init"
+// Then exit code is 2
+func TestMain_AllUnsupported(t *testing.T) {
+	bin := getPtsdBinary(t)
+	dir := setupOutputProject(t)
+	cmd := exec.Command(bin, "all", "init")
+	cmd.Dir = dir
+	cmd.CombinedOutput()
+	if code := cmd.ProcessState.ExitCode(); code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+}
+
+// Scenario: Unknown command runs a ptsd-<name> plugin from PATH
+// Given an executable ptsd-hello on PATH
+// When I run "ptsd hello world --agent"
+// Then the plugin runs with the project root and agent mode
+// And its exit code is ptsd's
+func TestMain_PluginDispatch(t *testing.T) {
+	bin := getPtsdBinary(t)
+	dir := setupOutputProject(t)
+	plugins := t.TempDir()
+	script := "#!/bin/sh\necho \"hello $* root=$PTSD_PROJECT_ROOT\"\nexit 7\n"
+	if err := os.WriteFile(filepath.Join(plugins, "ptsd-hello"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(bin, "hello", "world", "--agent")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "PATH="+plugins+string(os.PathListSeparator)+os.Getenv("PATH"))
+	out, _ := cmd.CombinedOutput()
+
+	if code := cmd.ProcessState.ExitCode(); code != 7 {
+		t.Errorf("expected the plugin's exit 7, got %d. output: %s", code, out)
+	}
+	if want := "hello --agent world root=" + dir; !strings.Contains(string(out), want) {
+		t.Errorf("expected %q in output, got: %s", want, out)
+	}
+}
+
+// Scenario: a mutating command waits for the project lock, then gives up
+// Given another process holds .ptsd/.lock
+// When I run "ptsd --wait-lock 100ms task list"
+// Then exit code is 4 and the error names the holder
+func TestMain_WaitLock(t *testing.T) {
+	bin := getPtsdBinary(t)
+	dir := setupOutputProject(t)
+	unlock, err := core.LockProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bin, "--wait-lock", "100ms", "task", "list", "--agent")
+	cmd.Dir = dir
+	out, _ := cmd.CombinedOutput()
+	if code := cmd.ProcessState.ExitCode(); code != 4 {
+		t.Errorf("expected exit 4 while locked, got %d: %s", code, out)
+	}
+	if !strings.Contains(string(out), "project is locked by pid") {
+		t.Errorf("expected lock holder in output, got: %s", out)
+	}
+
+	// Read-only commands do not wait.
+	cmd = exec.Command(bin, "--wait-lock=0", "status", "--agent")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("status should not need the lock: %s", out)
+	}
+
+	unlock()
+	cmd = exec.Command(bin, "--wait-lock", "100ms", "task", "list", "--agent")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("expected success after unlock, got: %s", out)
+	}
+
+	cmd = exec.Command(bin, "--wait-lock", "soon", "task", "list")
+	cmd.Dir = dir
+	out, _ = cmd.CombinedOutput()
+	if code := cmd.ProcessState.ExitCode(); code != 2 || !strings.Contains(string(out), "err:user invalid --wait-lock") {
+		t.Errorf("expected usage error, got %d: %s", code, out)
+	}
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// allCommands lists the commands that can be aggregated across workspaces.
+var allCommands = map[string]bool{
+	"status":   true,
+	"validate": true,
+	"test":     true,
+}
+
+// allWorkspaceJSON is one workspace's run under --json: Result is the
+// child's own --json document.
+type allWorkspaceJSON struct {
+	Workspace string          `json:"workspace"`
+	ExitCode  int             `json:"exit_code"`
+	Result    json.RawMessage `json:"result"`
+	Stderr    []string        `json:"stderr"`
+}
+
+type workspaceRun struct {
+	name   string
+	stdout string
+	stderr string
+	code   int
+}
+
+// RunAll handles `ptsd all <status|validate|test> [args...]`.
+// Runs the command in every workspace (directory with .ptsd/ptsd.yaml) in parallel,
+// prefixes output lines with the workspace path, and exits with the highest exit code.
+func RunAll(args []string, agentMode bool) int {
+	if len(args) == 0 || !allCommands[args[0]] {
+		return renderError(agentMode, "user", "usage: ptsd all <status|validate|test> [args...]")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	workspaces, err := core.DiscoverWorkspaces(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if len(workspaces) == 0 {
+		return renderError(agentMode, "config", "no workspaces found (no .ptsd/ptsd.yaml under "+cwd+")")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	// Children get the global flags: the lock wait, and --json so each
+	// workspace's result can be collected into one document.
+	cmdArgs := []string{"--wait-lock=" + core.LockWait().String()}
+	if jsonOutput {
+		cmdArgs = append(cmdArgs, "--json")
+	}
+	cmdArgs = append(cmdArgs, args...)
+	if agentMode {
+		cmdArgs = append(cmdArgs, "--agent")
+	}
+
+	runs := make([]workspaceRun, len(workspaces))
+	var wg sync.WaitGroup
+	for i, ws := range workspaces {
+		wg.Add(1)
+		go func(i int, ws string) {
+			defer wg.Done()
+			runs[i] = runInWorkspace(exe, cwd, ws, cmdArgs)
+		}(i, ws)
+	}
+	wg.Wait()
+
+	exitCode := 0
+	if jsonOutput {
+		out := make([]allWorkspaceJSON, 0, len(runs))
+		for _, r := range runs {
+			ws := allWorkspaceJSON{Workspace: r.name, ExitCode: r.code, Stderr: []string{}}
+			if result := []byte(strings.TrimSpace(r.stdout)); json.Valid(result) {
+				ws.Result = result
+			}
+			if text := strings.TrimRight(r.stderr, "\n"); text != "" {
+				ws.Stderr = strings.Split(text, "\n")
+			}
+			out = append(out, ws)
+			exitCode = max(exitCode, r.code)
+		}
+		printJSON(agentMode, "all", out)
+		return exitCode
+	}
+	for _, r := range runs {
+		printPrefixed(os.Stdout, r.name, r.stdout)
+		printPrefixed(os.Stderr, r.name, r.stderr)
+		if r.code > exitCode {
+			exitCode = r.code
+		}
+	}
+	return exitCode
+}
+
+func runInWorkspace(exe, root, ws string, args []string) workspaceRun {
+	name, err := filepath.Rel(root, ws)
+	if err != nil {
+		name = ws
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(exe, args...)
+	cmd.Dir = ws
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	code := 0
+	if err := cmd.Run(); err != nil {
+		code = 4
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			code = exitErr.ExitCode()
+		} else {
+			stderr.WriteString("err:io " + err.Error() + "\n")
+		}
+	}
+
+	return workspaceRun{name: name, stdout: stdout.String(), stderr: stderr.String(), code: code}
+}
+
+func printPrefixed(f *os.File, name, out string) {
+	out = strings.TrimRight(out, "\n")
+	if out == "" {
+		return
+	}
+	for _, line := range strings.Split(out, "\n") {
+		fmt.Fprintf(f, "[%s] %s\n", name, line)
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunAudit handles the `ptsd audit` command.
+// Subcommands:
+//
+//	ptsd audit agent-compliance [--days N] [--gap DURATION] [--json]
+func RunAudit(args []string, agentMode bool) int {
+	if len(args) == 0 || args[0] != "agent-compliance" {
+		return renderError(agentMode, "user", "usage: ptsd audit agent-compliance [--days N] [--gap DURATION] [--json]")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+	return runAgentCompliance(args[1:], cwd, agentMode)
+}
+
+type complianceJSON struct {
+	Since    string                  `json:"since"`
+	Until    string                  `json:"until"`
+	Score    int                     `json:"score"`
+	Sessions []complianceSessionJSON `json:"sessions"`
+}
+
+type complianceSessionJSON struct {
+	Start    string                `json:"start"`
+	End      string                `json:"end"`
+	ToolUses int                   `json:"tool_uses"`
+	Commits  int                   `json:"commits"`
+	Score    int                   `json:"score"`
+	Checks   []complianceCheckJSON `json:"checks"`
+}
+
+type complianceCheckJSON struct {
+	Name       string `json:"name"`
+	Applicable bool   `json:"applicable"`
+	Passed     bool   `json:"passed"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+func runAgentCompliance(args []string, cwd string, agentMode bool) int {
+	days, gap := 7, 30*time.Minute
+	jsonOut := jsonOutput
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--json":
+			jsonOut = true
+		case "--days":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "audit", "--days requires a numeric value")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				return usageError(agentMode, "audit", fmt.Sprintf("invalid --days value %q: must be a positive integer", args[i+1]))
+			}
+			days = n
+			i++
+		case "--gap":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "audit", "--gap requires a duration such as 30m")
+			}
+			d, err := time.ParseDuration(args[i+1])
+			if err != nil || d <= 0 {
+				return usageError(agentMode, "audit", fmt.Sprintf("invalid --gap value %q: use a duration such as 30m or 1h", args[i+1]))
+			}
+			gap = d
+			i++
+		default:
+			return usageError(agentMode, "audit", "unknown flag: "+args[i])
+		}
+	}
+
+	until := time.Now()
+	r, err := core.AgentCompliance(cwd, until.AddDate(0, 0, -days), until, gap)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	if jsonOut {
+		out := complianceJSON{Since: r.Since.UTC().Format(time.RFC3339), Until: r.Until.UTC().Format(time.RFC3339),
+			Score: r.Score, Sessions: []complianceSessionJSON{}}
+		for _, s := range r.Sessions {
+			sj := complianceSessionJSON{Start: s.Start.UTC().Format(time.RFC3339), End: s.End.UTC().Format(time.RFC3339),
+				ToolUses: s.ToolUses, Commits: s.Commits, Score: s.Score, Checks: []complianceCheckJSON{}}
+			for _, c := range s.Checks {
+				sj.Checks = append(sj.Checks, complianceCheckJSON{Name: c.Name, Applicable: c.Applicable, Passed: c.Passed, Detail: c.Detail})
+			}
+			out.Sessions = append(out.Sessions, sj)
+		}
+		return printJSON(agentMode, "audit.agent-compliance", out)
+	}
+
+	for _, s := range r.Sessions {
+		if agentMode {
+			line := fmt.Sprintf("session start=%s end=%s tools=%d commits=%d", s.Start.UTC().Format(time.RFC3339), s.End.UTC().Format(time.RFC3339), s.ToolUses, s.Commits)
+			for _, c := range s.Checks {
+				line += " " + c.Name + "=" + checkVerdict(c)
+			}
+			fmt.Printf("%s score=%d\n", line, s.Score)
+			continue
+		}
+		fmt.Printf("%s  %-8s tools=%-4d commits=%-3d score=%d%%\n", s.Start.Local().Format("2006-01-02 15:04"),
+			durationShort(s.End.Sub(s.Start)), s.ToolUses, s.Commits, s.Score)
+		var notes []string
+		for _, c := range s.Checks {
+			note := c.Name + " " + checkVerdict(c)
+			if c.Detail != "" {
+				note += " (" + c.Detail + ")"
+			}
+			notes = append(notes, note)
+		}
+		fmt.Println("  " + strings.Join(notes, ", "))
+	}
+	if agentMode {
+		fmt.Printf("compliance sessions=%d score=%d\n", len(r.Sessions), r.Score)
+	} else if len(r.Sessions) == 0 {
+		fmt.Printf("No agent sessions in the last %d day(s).\n", days)
+	} else {
+		fmt.Printf("\n%d session(s) in the last %d day(s), compliance %d%%\n", len(r.Sessions), days, r.Score)
+	}
+	return 0
+}
+
+func checkVerdict(c core.ComplianceCheck) string {
+	switch {
+	case !c.Applicable:
+		return "n/a"
+	case c.Passed:
+		return "pass"
+	default:
+		return "fail"
+	}
+}
+
+// durationShort formats a session length as 45m or 2h05m.
+func durationShort(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
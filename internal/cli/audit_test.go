@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunAudit_Usage(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	withDir(t, dir, func() {
+		if code := RunAudit(nil, true); code != 2 {
+			t.Errorf("expected exit 2 without subcommand, got %d", code)
+		}
+		if code := RunAudit([]string{"agent-compliance", "--gap", "soon"}, true); code != 2 {
+			t.Errorf("expected exit 2 for bad --gap, got %d", code)
+		}
+	})
+}
+
+func TestRunAudit_AgentComplianceCountsContextSession(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+
+	var code int
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			RunContext(nil, true) // SessionStart hook: logs a context event
+			code = RunAudit([]string{"agent-compliance"}, true)
+		})
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d\n%s", code, out)
+	}
+	if !strings.Contains(out, "context=pass validate=n/a") || !strings.Contains(out, "compliance sessions=1 score=100") {
+		t.Errorf("expected one compliant session, got:\n%s", out)
+	}
+}
@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// completionCommands are the top-level commands offered for the first word.
+var completionCommands = []string{
+	"adopt", "all", "audit", "auto-track", "bdd", "completion", "config",
+	"context", "daemon", "deinit", "doctor", "events", "feature", "gate",
+	"gate-check", "help", "history", "hooks", "init", "issues", "lint",
+	"migrate", "prd", "remote", "report", "restore", "review", "seed",
+	"skills", "snapshot", "stage", "status", "task", "templates", "test",
+	"trace", "undo", "validate", "verify-hooks", "verify-log", "version",
+}
+
+var completionSubcommands = map[string][]string{
+	"all":        {"status", "validate", "test"},
+	"audit":      {"agent-compliance"},
+	"bdd":        {"add", "ids", "list", "diff"},
+	"completion": {"bash", "zsh", "fish"},
+	"config":     {"show", "get", "set", "unset", "keys", "scopes", "types", "ignore", "detect-runner"},
+	"daemon":     {"stop", "status"},
+	"events":     {"tail"},
+	"feature":    {"add", "list", "status", "show", "remove", "defer", "undefer", "pipeline", "parent", "depends", "split"},
+	"gate":       {"exempt"},
+	"hooks":      {"install", "log"},
+	"prd":        {"check", "show", "index", "import"},
+	"remote":     {"push", "pull"},
+	"report":     {"weekly", "durations", "trace"},
+	"review":     {"gate", "notes", "history", "serve"},
+	"seed":       {"init", "add", "snapshot", "emit", "validate", "scaffold"},
+	"snapshot":   {"list"},
+	"stage":      {"set"},
+	"task":       {"add", "list", "next", "show", "update", "comment", "claim", "release", "graph", "import"},
+	"templates":  {"list", "check"},
+	"test":       {"map", "coverage", "match", "unmapped", "run"},
+}
+
+// completionArgs gives the kind of each positional argument after "cmd" or
+// "cmd sub"; a kind ending in "..." repeats for every later argument.
+var completionArgs = map[string][]string{
+	"bdd add":          {core.CompleteFeature},
+	"bdd diff":         {core.CompleteFeature},
+	"feature status":   {core.CompleteFeature, core.CompleteStatus},
+	"feature show":     {core.CompleteFeature},
+	"feature remove":   {core.CompleteFeature},
+	"feature defer":    {core.CompleteFeature},
+	"feature undefer":  {core.CompleteFeature},
+	"feature pipeline": {core.CompleteFeature, core.CompletePipeline},
+	"feature parent":   {core.CompleteFeature, core.CompleteFeature},
+	"feature depends":  {core.CompleteFeature, core.CompleteFeature + "..."},
+	"feature split":    {core.CompleteFeature},
+	"prd show":         {core.CompleteFeature},
+	"report trace":     {core.CompleteFeature + "..."},
+	"review":           {core.CompleteFeature, core.CompleteStage},
+	"review gate":      {core.CompleteFeature, core.CompleteStage},
+	"review notes":     {core.CompleteFeature},
+	"review history":   {core.CompleteFeature},
+	"seed add":         {core.CompleteFeature},
+	"seed snapshot":    {core.CompleteFeature},
+	"seed emit":        {core.CompleteFeature},
+	"seed validate":    {core.CompleteFeature},
+	"seed scaffold":    {core.CompleteFeature},
+	"stage set":        {core.CompleteFeature, core.CompleteStage},
+	"task add":         {core.CompleteFeature},
+	"task show":        {core.CompleteTask},
+	"task update":      {core.CompleteTask, core.CompleteTaskStatus},
+	"task comment":     {core.CompleteTask},
+	"task claim":       {core.CompleteTask},
+	"task release":     {core.CompleteTask},
+	"test map":         {core.CompleteFeature},
+	"test coverage":    {core.CompleteFeature},
+	"test match":       {core.CompleteFeature},
+	"test run":         {core.CompleteFeature},
+	"trace":            {core.CompleteFeature},
+}
+
+// completionFlags are flags whose value is a project value.
+var completionFlags = map[string]string{
+	"--feature":  core.CompleteFeature,
+	"--parent":   core.CompleteFeature,
+	"--for-task": core.CompleteTask,
+	"--stage":    core.CompleteStage,
+	"--priority": core.CompletePriority,
+}
+
+// RunCompletion handles `ptsd completion bash|zsh|fish`: prints a script
+// that asks `ptsd __complete` for candidates on every <TAB>.
+func RunCompletion(args []string, agentMode bool) int {
+	if len(args) != 1 {
+		return usageError(agentMode, "completion", "usage: ptsd completion bash|zsh|fish")
+	}
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		return usageError(agentMode, "completion", fmt.Sprintf("unknown shell %q: use bash|zsh|fish", args[0]))
+	}
+	fmt.Print(script)
+	return 0
+}
+
+var completionScripts = map[string]string{
+	"bash": `# ptsd bash completion: source <(ptsd completion bash)
+_ptsd() {
+    local IFS=$'\n'
+    COMPREPLY=($(ptsd __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _ptsd ptsd
+`,
+	"zsh": `#compdef ptsd
+# ptsd zsh completion: source <(ptsd completion zsh)
+_ptsd() {
+    local -a candidates
+    candidates=(${(f)"$(ptsd __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+    compadd -a candidates
+}
+compdef _ptsd ptsd
+`,
+	"fish": `# ptsd fish completion: ptsd completion fish | source
+complete -c ptsd -f -a '(ptsd __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
+`,
+}
+
+// RunComplete handles the hidden `ptsd __complete <word>...` the completion
+// scripts call: the words after "ptsd", the last one being the word under
+// the cursor (possibly empty). It prints matching candidates one per line
+// and never fails, so a broken project only means no suggestions.
+func RunComplete(args []string, agentMode bool) int {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return 0
+	}
+	for _, c := range completeWords(cwd, args) {
+		fmt.Println(c)
+	}
+	return 0
+}
+
+func completeWords(projectDir string, words []string) []string {
+	words = stripGlobalFlags(words)
+	if len(words) == 0 {
+		return nil
+	}
+	cur, prev := words[len(words)-1], words[:len(words)-1]
+
+	var candidates []string
+	switch {
+	case len(prev) == 0 && strings.HasPrefix(cur, "-"):
+		candidates = []string{"--agent", "--json", "--wait-lock"}
+	case len(prev) == 0:
+		candidates = completionCommands
+	case completionFlags[prev[len(prev)-1]] != "":
+		candidates = completionValues(projectDir, completionFlags[prev[len(prev)-1]])
+	default:
+		candidates = completeArg(projectDir, prev[0], positionalArgs(prev[1:]))
+	}
+
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, cur) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// completeArg picks candidates for the next positional argument of cmd,
+// given the positionals already typed.
+func completeArg(projectDir, cmd string, positionals []string) []string {
+	if len(positionals) == 0 {
+		candidates := completionSubcommands[cmd]
+		if kinds := completionArgs[cmd]; len(kinds) > 0 {
+			candidates = append(append([]string{}, candidates...), completionValues(projectDir, kinds[0])...)
+		}
+		return candidates
+	}
+
+	kinds, idx := completionArgs[cmd+" "+positionals[0]], len(positionals)-1
+	if kinds == nil {
+		kinds, idx = completionArgs[cmd], len(positionals)
+	}
+	if len(kinds) == 0 {
+		return nil
+	}
+	if idx >= len(kinds) {
+		last := kinds[len(kinds)-1]
+		if !strings.HasSuffix(last, "...") {
+			return nil
+		}
+		idx = len(kinds) - 1
+	}
+	return completionValues(projectDir, strings.TrimSuffix(kinds[idx], "..."))
+}
+
+// positionalArgs drops flags, and the values of flags completion knows, from
+// the typed arguments.
+func positionalArgs(args []string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case completionFlags[args[i]] != "":
+			i++
+		case strings.HasPrefix(args[i], "-"):
+		default:
+			out = append(out, args[i])
+		}
+	}
+	return out
+}
+
+// stripGlobalFlags drops the global flags main parses before the command.
+func stripGlobalFlags(words []string) []string {
+	for len(words) > 1 {
+		switch {
+		case words[0] == "--agent" || words[0] == "-agent" || words[0] == "--json" || strings.HasPrefix(words[0], "--wait-lock="):
+			words = words[1:]
+		case words[0] == "--wait-lock" && len(words) > 2:
+			words = words[2:]
+		default:
+			return words
+		}
+	}
+	return words
+}
+
+func completionValues(projectDir, kind string) []string {
+	values, err := core.CompletionValues(projectDir, kind)
+	if err != nil {
+		return nil
+	}
+	return values
+}
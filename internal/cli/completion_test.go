@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompleteWords(t *testing.T) {
+	dir := setupTaskProjectWithTasks(t, []string{"auth", "billing"}, "tasks:\n  - id: T-1\n    feature: auth\n    title: Login\n    status: TODO\n")
+
+	cases := []struct {
+		words []string
+		want  string
+	}{
+		{[]string{"fea"}, "feature"},
+		{[]string{"feature", "sh"}, "show"},
+		{[]string{"feature", "show", ""}, "auth billing"},
+		{[]string{"feature", "status", "auth", "impl"}, "implemented"},
+		{[]string{"task", "show", ""}, "T-1"},
+		{[]string{"task", "update", "T-1", ""}, "DONE TODO WIP"},
+		{[]string{"task", "list", "--feature", "b"}, "billing"},
+		{[]string{"review", "a"}, "auth"},
+		{[]string{"review", "auth", "t"}, "tests"},
+		{[]string{"feature", "depends", "auth", "billing", ""}, "auth billing"},
+		{[]string{"--agent", "stage", "set", "billing", "b"}, "bdd"},
+		{[]string{"version", ""}, ""},
+	}
+	for _, c := range cases {
+		got := strings.Join(completeWords(dir, c.words), " ")
+		if got != c.want {
+			t.Errorf("complete %q: got %q, want %q", c.words, got, c.want)
+		}
+	}
+}
+
+func TestRunCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		out := captureStdout(t, func() {
+			if code := RunCompletion([]string{shell}, true); code != 0 {
+				t.Errorf("%s: exit %d", shell, code)
+			}
+		})
+		if !strings.Contains(out, "ptsd __complete") {
+			t.Errorf("%s script does not call __complete:\n%s", shell, out)
+		}
+	}
+	if code := RunCompletion([]string{"tcsh"}, true); code != 2 {
+		t.Errorf("unknown shell: exit %d, want 2", code)
+	}
+}
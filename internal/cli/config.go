@@ -1,8 +1,11 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/veschin/ptsd/internal/core"
@@ -10,7 +13,7 @@ import (
 
 func RunConfig(args []string, agentMode bool) int {
 	if len(args) == 0 {
-		return usageError(agentMode, "config", "subcommand required: show")
+		return usageError(agentMode, "config", "subcommand required: show|get|set|unset|keys|scopes|types|ignore|detect-runner")
 	}
 
 	cwd, err := os.Getwd()
@@ -29,35 +32,338 @@ func RunConfig(args []string, agentMode bool) int {
 		printConfig(agentMode, cfg)
 		return 0
 
+	case "get", "set", "unset":
+		return runConfigValue(cwd, sub, args[1:], agentMode)
+
+	case "keys":
+		for _, k := range core.ConfigKeys() {
+			fmt.Println(k)
+		}
+		return 0
+
+	case "scopes", "types":
+		return runConfigList(cwd, sub, args[1:], agentMode)
+
+	case "ignore":
+		return runConfigIgnore(cwd, args[1:], agentMode)
+
+	case "detect-runner":
+		return runConfigDetectRunner(cwd, args[1:], agentMode)
+
 	default:
-		return usageError(agentMode, "config", fmt.Sprintf("unknown subcommand %q: use show", sub))
+		return usageError(agentMode, "config", fmt.Sprintf("unknown subcommand %q: use show|get|set|unset|keys|scopes|types|ignore|detect-runner", sub))
 	}
 }
 
+// runConfigValue handles `ptsd config get <key>`, `config set <key> <value>`
+// and `config unset <key>`. set validates and coerces the value before
+// ptsd.yaml is rewritten; unset restores the key's default.
+func runConfigValue(cwd, op string, args []string, agentMode bool) int {
+	want := 1
+	usage := "usage: config " + op + " <key>"
+	if op == "set" {
+		want, usage = 2, "usage: config set <key> <value>"
+	}
+	if len(args) != want {
+		return usageError(agentMode, "config "+op, usage)
+	}
+	key := args[0]
+
+	switch op {
+	case "get":
+		value, err := core.GetConfigValue(cwd, key)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		fmt.Println(value)
+		return 0
+	case "set":
+		value, err := core.SetConfigValue(cwd, key, args[1])
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		if code := syncCommitConventions(cwd, key, agentMode); code != 0 {
+			return code
+		}
+		fmt.Printf("ok config set %s=%s\n", key, value)
+		return 0
+	default:
+		removed, err := core.UnsetConfigValue(cwd, key)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		if !removed {
+			fmt.Printf("ok config unset %s (not set)\n", key)
+			return 0
+		}
+		if code := syncCommitConventions(cwd, key, agentMode); code != 0 {
+			return code
+		}
+		fmt.Printf("ok config unset %s\n", key)
+		return 0
+	}
+}
+
+// syncCommitConventions keeps the agent instructions in CLAUDE.md in sync
+// after hooks.scopes or hooks.types change.
+func syncCommitConventions(cwd, key string, agentMode bool) int {
+	if key != "hooks.scopes" && key != "hooks.types" {
+		return 0
+	}
+	if err := core.SyncClaudeMD(cwd); err != nil {
+		return coreError(agentMode, err)
+	}
+	return 0
+}
+
+// runConfigList handles `ptsd config scopes|types [list|add <v>|remove <v>]`.
+func runConfigList(cwd, kind string, args []string, agentMode bool) int {
+	op := "list"
+	if len(args) > 0 {
+		op = args[0]
+	}
+
+	var err error
+	switch op {
+	case "list":
+		items := core.CommitScopes(cwd)
+		if kind == "types" {
+			items = core.CommitTypes(cwd)
+		}
+		if agentMode {
+			fmt.Println(strings.Join(items, ","))
+		} else {
+			for _, item := range items {
+				fmt.Println(item)
+			}
+		}
+		return 0
+	case "add", "remove":
+		if len(args) < 2 {
+			return usageError(agentMode, "config "+kind, op+" requires a value")
+		}
+		value := args[1]
+		switch {
+		case kind == "scopes" && op == "add":
+			err = core.AddCommitScope(cwd, value)
+		case kind == "scopes":
+			err = core.RemoveCommitScope(cwd, value)
+		case op == "add":
+			err = core.AddCommitType(cwd, value)
+		default:
+			err = core.RemoveCommitType(cwd, value)
+		}
+	default:
+		return usageError(agentMode, "config "+kind, fmt.Sprintf("unknown operation %q: use list|add|remove", op))
+	}
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	// Keep the agent instructions in CLAUDE.md in sync with the new list.
+	if err := core.SyncClaudeMD(cwd); err != nil {
+		return coreError(agentMode, err)
+	}
+	fmt.Printf("ok %s %s %s\n", kind, op, args[1])
+	return 0
+}
+
+// runConfigIgnore handles `ptsd config ignore [list|add [pattern...]|remove <pattern>]`.
+// add without patterns installs the default entries for ptsd's local artifacts.
+func runConfigIgnore(cwd string, args []string, agentMode bool) int {
+	op := "list"
+	if len(args) > 0 {
+		op = args[0]
+	}
+
+	switch op {
+	case "list":
+		entries, err := core.IgnoreEntries(cwd)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		for _, e := range entries {
+			fmt.Println(e)
+		}
+		return 0
+
+	case "add":
+		added, err := core.AddIgnoreEntries(cwd, args[1:]...)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		if len(added) == 0 {
+			fmt.Println("ok ignore add (already present)")
+			return 0
+		}
+		fmt.Printf("ok ignore add %s\n", strings.Join(added, " "))
+		return 0
+
+	case "remove":
+		if len(args) < 2 {
+			return usageError(agentMode, "config ignore", "remove requires a pattern")
+		}
+		if err := core.RemoveIgnoreEntry(cwd, args[1]); err != nil {
+			return coreError(agentMode, err)
+		}
+		fmt.Printf("ok ignore remove %s\n", args[1])
+		return 0
+
+	default:
+		return usageError(agentMode, "config ignore", fmt.Sprintf("unknown operation %q: use list|add|remove", op))
+	}
+}
+
+// runConfigDetectRunner handles `ptsd config detect-runner [--yes]`: it shows
+// how detection would change testing.runner and testing.patterns.files and
+// writes the change once confirmed. Agent mode only applies with --yes.
+func runConfigDetectRunner(cwd string, args []string, agentMode bool) int {
+	yes := false
+	for _, a := range args {
+		switch a {
+		case "--yes", "-y":
+			yes = true
+		default:
+			return usageError(agentMode, "config detect-runner", "unknown flag "+a)
+		}
+	}
+
+	p, err := core.DetectRunner(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if !p.Changed() {
+		fmt.Printf("ok runner unchanged: %s\n", p.Runner)
+		return 0
+	}
+
+	if agentMode {
+		fmt.Printf("testing.runner=%s -> %s\n", p.CurrentRunner, p.Runner)
+		fmt.Printf("testing.patterns.files=%s -> %s\n", strings.Join(p.CurrentPatterns, ","), strings.Join(p.Patterns, ","))
+		if !yes {
+			fmt.Println("pending: pass --yes to apply")
+			return 0
+		}
+	} else {
+		fmt.Printf("runner:   %s -> %s\n", dashIfEmpty(p.CurrentRunner), p.Runner)
+		fmt.Printf("patterns: %s -> %s\n", strings.Join(p.CurrentPatterns, ", "), strings.Join(p.Patterns, ", "))
+		if !yes {
+			fmt.Print("Update .ptsd/ptsd.yaml? [y/N] ")
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+				fmt.Println("aborted")
+				return 2
+			}
+		}
+	}
+
+	if err := core.ApplyRunner(cwd, p); err != nil {
+		return coreError(agentMode, err)
+	}
+	fmt.Printf("ok runner %s\n", p.Runner)
+	return 0
+}
+
 func printConfig(agentMode bool, cfg *core.Config) {
 	if agentMode {
 		fmt.Printf("project.name=%s\n", cfg.Project.Name)
+		fmt.Printf("project.profile=%s\n", cfg.Project.Profile)
 		fmt.Printf("testing.runner=%s\n", cfg.Testing.Runner)
 		fmt.Printf("testing.patterns.files=%s\n", strings.Join(cfg.Testing.Patterns.Files, ","))
 		fmt.Printf("testing.result_parser.format=%s\n", cfg.Testing.ResultParser.Format)
+		fmt.Printf("testing.max_failures=%d\n", cfg.Testing.MaxFailures)
 		fmt.Printf("review.min_score=%d\n", cfg.Review.MinScore)
 		fmt.Printf("review.auto_redo=%v\n", cfg.Review.AutoRedo)
+		fmt.Printf("review.git_notes=%v\n", cfg.Review.GitNotes)
+		fmt.Printf("review.token_env=%s\n", cfg.Review.TokenEnv)
 		fmt.Printf("hooks.pre_commit=%v\n", cfg.Hooks.PreCommit)
+		fmt.Printf("hooks.pre_push=%v\n", cfg.Hooks.PrePush)
 		fmt.Printf("hooks.scopes=%s\n", strings.Join(cfg.Hooks.Scopes, ","))
 		fmt.Printf("hooks.types=%s\n", strings.Join(cfg.Hooks.Types, ","))
+		fmt.Printf("hooks.autotrack_debounce=%d\n", cfg.Hooks.AutoTrackDebounce)
+		fmt.Printf("remote.url=%s\n", cfg.Remote.URL)
+		fmt.Printf("pipeline.stages=%s\n", strings.Join(cfg.Pipeline.Stages, ","))
+		fmt.Printf("tasks.scheduling=%s\n", cfg.Tasks.Scheduling)
+		fmt.Printf("gates.mode=%s\n", cfg.Gates.Mode)
+		fmt.Printf("discovery.max_depth=%d\n", cfg.Discovery.MaxDepth)
+		fmt.Printf("discovery.max_files=%d\n", cfg.Discovery.MaxFiles)
+		fmt.Printf("discovery.max_file_kb=%d\n", cfg.Discovery.MaxFileKB)
+		fmt.Printf("discovery.timeout=%d\n", cfg.Discovery.Timeout)
+		fmt.Printf("prd.max_kb=%d\n", cfg.PRD.MaxKB)
+		if cfg.Extends != "" {
+			fmt.Printf("extends=%s\n", cfg.Extends)
+		}
+		fmt.Printf("network.air_gapped=%v\n", cfg.Network.AirGapped)
+		fmt.Printf("audit.sign=%v\n", cfg.Audit.Sign)
+		for _, id := range slices.Sorted(maps.Keys(cfg.Features)) {
+			o := cfg.Features[id]
+			if o.Runner != "" {
+				fmt.Printf("features_config.%s.runner=%s\n", id, o.Runner)
+			}
+			if len(o.Patterns) > 0 {
+				fmt.Printf("features_config.%s.patterns=%s\n", id, strings.Join(o.Patterns, ","))
+			}
+			if o.MinScore > 0 {
+				fmt.Printf("features_config.%s.min_score=%d\n", id, o.MinScore)
+			}
+		}
 	} else {
 		fmt.Printf("project:\n")
 		fmt.Printf("  name: %s\n", cfg.Project.Name)
+		fmt.Printf("  profile: %s\n", cfg.Project.Profile)
 		fmt.Printf("testing:\n")
 		fmt.Printf("  runner: %s\n", cfg.Testing.Runner)
 		fmt.Printf("  patterns.files: %s\n", strings.Join(cfg.Testing.Patterns.Files, ", "))
 		fmt.Printf("  result_parser.format: %s\n", cfg.Testing.ResultParser.Format)
+		fmt.Printf("  max_failures: %d\n", cfg.Testing.MaxFailures)
 		fmt.Printf("review:\n")
 		fmt.Printf("  min_score: %d\n", cfg.Review.MinScore)
 		fmt.Printf("  auto_redo: %v\n", cfg.Review.AutoRedo)
+		fmt.Printf("  git_notes: %v\n", cfg.Review.GitNotes)
+		fmt.Printf("  token_env: %s\n", cfg.Review.TokenEnv)
 		fmt.Printf("hooks:\n")
 		fmt.Printf("  pre_commit: %v\n", cfg.Hooks.PreCommit)
+		fmt.Printf("  pre_push: %v\n", cfg.Hooks.PrePush)
 		fmt.Printf("  scopes: %s\n", strings.Join(cfg.Hooks.Scopes, ", "))
 		fmt.Printf("  types: %s\n", strings.Join(cfg.Hooks.Types, ", "))
+		fmt.Printf("  autotrack_debounce: %d\n", cfg.Hooks.AutoTrackDebounce)
+		fmt.Printf("remote:\n")
+		fmt.Printf("  url: %s\n", cfg.Remote.URL)
+		fmt.Printf("pipeline:\n")
+		fmt.Printf("  stages: %s\n", strings.Join(cfg.Pipeline.Stages, ", "))
+		fmt.Printf("tasks:\n")
+		fmt.Printf("  scheduling: %s\n", cfg.Tasks.Scheduling)
+		fmt.Printf("gates:\n")
+		fmt.Printf("  mode: %s\n", cfg.Gates.Mode)
+		fmt.Printf("discovery:\n")
+		fmt.Printf("  max_depth: %d\n", cfg.Discovery.MaxDepth)
+		fmt.Printf("  max_files: %d\n", cfg.Discovery.MaxFiles)
+		fmt.Printf("  max_file_kb: %d\n", cfg.Discovery.MaxFileKB)
+		fmt.Printf("  timeout: %d\n", cfg.Discovery.Timeout)
+		fmt.Printf("prd:\n")
+		fmt.Printf("  max_kb: %d\n", cfg.PRD.MaxKB)
+		if cfg.Extends != "" {
+			fmt.Printf("extends: %s\n", cfg.Extends)
+		}
+		fmt.Printf("network:\n")
+		fmt.Printf("  air_gapped: %v\n", cfg.Network.AirGapped)
+		fmt.Printf("audit:\n")
+		fmt.Printf("  sign: %v\n", cfg.Audit.Sign)
+		if len(cfg.Features) > 0 {
+			fmt.Printf("features_config:\n")
+			for _, id := range slices.Sorted(maps.Keys(cfg.Features)) {
+				o := cfg.Features[id]
+				fmt.Printf("  %s:\n", id)
+				if o.Runner != "" {
+					fmt.Printf("    runner: %s\n", o.Runner)
+				}
+				if len(o.Patterns) > 0 {
+					fmt.Printf("    patterns: %s\n", strings.Join(o.Patterns, ", "))
+				}
+				if o.MinScore > 0 {
+					fmt.Printf("    min_score: %d\n", o.MinScore)
+				}
+			}
+		}
 	}
 }
@@ -323,3 +323,99 @@ hooks:
 		t.Errorf("expected 'err:config' in output for invalid YAML, got: %q", out)
 	}
 }
+
+func TestRunConfig_ScopesRemoveAndList(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("project:\n  name: X\nhooks:\n  scopes: [PRD, IMPL, TASK]\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "CLAUDE.md"), []byte("<!-- ---ptsd--- -->\nold\n<!-- ---ptsd--- -->\n"), 0644)
+
+	if code := RunConfig([]string{"scopes", "remove", "TASK"}, true); code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+
+	out := captureStdout(t, func() {
+		RunConfig([]string{"scopes", "list"}, true)
+	})
+	if strings.TrimSpace(out) != "PRD,IMPL" {
+		t.Errorf("expected PRD,IMPL, got %q", out)
+	}
+
+	claudeMD, _ := os.ReadFile(filepath.Join(dir, "CLAUDE.md"))
+	if !strings.Contains(string(claudeMD), "Scopes: PRD, IMPL") {
+		t.Errorf("CLAUDE.md not synced with scopes:\n%s", claudeMD)
+	}
+}
+
+func TestRunConfig_TypesAddInvalid(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+
+	if code := RunConfig([]string{"types", "add", "Not Valid"}, true); code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+	if code := RunConfig([]string{"types", "add"}, true); code != 2 {
+		t.Errorf("expected exit 2 for missing value, got %d", code)
+	}
+}
+
+func TestRunConfig_DetectRunner(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("project:\n  name: X\ntesting:\n  runner: \"npx jest\"\n  patterns:\n    files: [\"**/*_test.go\"]\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"devDependencies":{"vitest":"^1.0.0"}}`), 0644)
+
+	out := captureStdout(t, func() {
+		if code := RunConfig([]string{"detect-runner"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "testing.runner=npx jest -> npx vitest run") || !strings.Contains(out, "pending:") {
+		t.Errorf("expected proposal without apply, got %q", out)
+	}
+	cfg, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"))
+	if strings.Contains(string(cfg), "vitest") {
+		t.Fatal("agent mode without --yes must not write ptsd.yaml")
+	}
+
+	if code := RunConfig([]string{"detect-runner", "--yes"}, true); code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	cfg, _ = os.ReadFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"))
+	if !strings.Contains(string(cfg), `runner: "npx vitest run"`) || !strings.Contains(string(cfg), `"**/*.test.ts"`) {
+		t.Errorf("runner/patterns not updated:\n%s", cfg)
+	}
+}
+
+func TestRunConfig_GetSetUnset(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+
+	var code int
+	out := captureStdout(t, func() { code = RunConfig([]string{"set", "review.min_score", "9"}, true) })
+	if code != 0 || !strings.Contains(out, "ok config set review.min_score=9") {
+		t.Fatalf("set: exit %d, output %q", code, out)
+	}
+	out = captureStdout(t, func() { code = RunConfig([]string{"get", "review.min_score"}, true) })
+	if code != 0 || strings.TrimSpace(out) != "9" {
+		t.Errorf("get: exit %d, output %q", code, out)
+	}
+	if code := RunConfig([]string{"set", "review.min_score", "high"}, true); code != 2 {
+		t.Errorf("invalid value: expected exit 2, got %d", code)
+	}
+	if code := RunConfig([]string{"set", "review.min_score"}, true); code != 2 {
+		t.Errorf("missing value: expected exit 2, got %d", code)
+	}
+	out = captureStdout(t, func() { code = RunConfig([]string{"unset", "review.min_score"}, true) })
+	if code != 0 || !strings.Contains(out, "ok config unset review.min_score") {
+		t.Errorf("unset: exit %d, output %q", code, out)
+	}
+	out = captureStdout(t, func() { RunConfig([]string{"get", "review.min_score"}, true) })
+	if strings.TrimSpace(out) != "7" {
+		t.Errorf("get after unset = %q, want default 7", out)
+	}
+}
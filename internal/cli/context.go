@@ -2,7 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/veschin/ptsd/internal/core"
 )
@@ -13,23 +16,192 @@ func RunContext(args []string, agentMode bool) int {
 		return coreError(agentMode, err)
 	}
 
+	budget := 0
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--for-task":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "context", "--for-task requires a task id")
+			}
+			return runTaskContext(dir, args[i+1], agentMode)
+		case "--budget":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "context", "--budget requires a number of tokens")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				return usageError(agentMode, "context", fmt.Sprintf("invalid --budget value %q: use a positive number of tokens", args[i+1]))
+			}
+			budget = n
+			i++
+		}
+	}
+
+	if agentMode {
+		// Session start marker for `ptsd audit agent-compliance`.
+		_ = core.AppendEvent(dir, core.Event{Type: core.EventContext})
+	}
+	if resp, ok := core.DaemonCall(dir, core.DaemonRequest{Cmd: "context", Agent: agentMode, Budget: budget}); ok {
+		return replayDaemonResponse(resp)
+	}
+
 	result, err := core.BuildContext(dir)
 	if err != nil {
 		return coreError(agentMode, err)
 	}
 
+	writeContext(os.Stdout, result, budget)
+	return 0
+}
+
+// writeContext prints the context lines; a positive budget keeps only what
+// fits in that many tokens, most important first (see core.FitContext).
+func writeContext(w io.Writer, result core.ContextResult, budget int) {
+	if budget > 0 {
+		for _, text := range core.FitContext(result, budget, contextLineText) {
+			fmt.Fprintln(w, text)
+		}
+		return
+	}
 	for _, line := range result.Lines {
-		switch line.Type {
-		case core.ContextNext:
-			fmt.Printf("next: %s stage=%s action=%s\n", line.Feature, line.Stage, line.Action)
-		case core.ContextBlocked:
-			fmt.Printf("blocked: %s stage=%s reason=%q\n", line.Feature, line.Stage, line.Reason)
-		case core.ContextDone:
-			fmt.Printf("done: %s stage=%s\n", line.Feature, line.Stage)
-		case core.ContextTask:
-			fmt.Printf("task: %s status=%s feature=%s title=%q\n", line.TaskID, line.TaskStatus, line.Feature, line.TaskTitle)
+		if text := contextLineText(line); text != "" {
+			fmt.Fprintln(w, text)
 		}
 	}
+}
+
+// contextLineText renders one context line without its newline.
+func contextLineText(line core.ContextLine) string {
+	switch line.Type {
+	case core.ContextNext:
+		return fmt.Sprintf("next: %s stage=%s action=%s", line.Feature, line.Stage, line.Action)
+	case core.ContextBlocked:
+		return fmt.Sprintf("blocked: %s stage=%s reason=%q", line.Feature, line.Stage, line.Reason)
+	case core.ContextIssue:
+		return fmt.Sprintf("issue: %s stage=%s severity=%s text=%q", line.Feature, line.Stage, line.Severity, line.Reason)
+	case core.ContextRegression:
+		return fmt.Sprintf("regression: %s file=%s severity=%s reason=%q", line.Feature, line.File, line.Severity, line.Reason)
+	case core.ContextDone:
+		return fmt.Sprintf("done: %s stage=%s", line.Feature, line.Stage)
+	case core.ContextRevisit:
+		return fmt.Sprintf("revisit: %s since=%s reason=%q", line.Feature, line.Revisit, line.Reason)
+	case core.ContextStale:
+		return fmt.Sprintf("stale: %s stage=%s reviewed=%s age=%dd", line.Feature, line.Stage, line.ReviewedAt.Format("2006-01-02"), int(line.Age.Hours()/24))
+	case core.ContextShadow:
+		if line.Feature == "" {
+			return fmt.Sprintf("shadow: gates.mode=shadow would-block=%d", line.Count)
+		}
+		return fmt.Sprintf("shadow: %s would-block=%d last=%q", line.Feature, line.Count, line.Reason)
+	case core.ContextTask:
+		lease := ""
+		switch {
+		case line.LeaseExpired:
+			lease = fmt.Sprintf(" lease=expired owner=%s", line.LeaseOwner)
+		case line.LeaseOwner != "":
+			lease = fmt.Sprintf(" claimed=%s", line.LeaseOwner)
+		}
+		return fmt.Sprintf("task: %s status=%s feature=%s%s title=%q", line.TaskID, line.TaskStatus, line.Feature, lease, line.TaskTitle)
+	}
+	return ""
+}
+
+// runTaskContext prints `ptsd context --for-task <id>`: the task and its
+// comments, its feature's unmet gates, scenarios, uncovered criteria, last
+// test failures, likely files and finally the PRD section.
+func runTaskContext(dir, taskID string, agentMode bool) int {
+	tc, err := core.BuildTaskContext(dir, taskID)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if jsonOutput {
+		return printJSON(agentMode, "context.task", newTaskContextJSON(tc))
+	}
 
+	t := tc.Task
+	fmt.Printf("task: %s status=%s feature=%s title=%q\n", t.ID, t.Status, t.Feature, t.Title)
+	writeTaskComments(os.Stdout, t.Comments)
+	fmt.Printf("stage: %s\n", tc.Stage)
+	for _, g := range tc.Gates {
+		if g.Reason != "" {
+			fmt.Printf("gate: %s state=%s reason=%q\n", g.Stage, g.State, g.Reason)
+		} else {
+			fmt.Printf("gate: %s state=%s\n", g.Stage, g.State)
+		}
+	}
+	for _, s := range tc.Scenarios {
+		fmt.Printf("scenario: %s title=%q\n", s.ID, s.Title)
+	}
+	for _, c := range tc.Uncovered {
+		fmt.Printf("uncovered: %s %q\n", c.ID, c.Text)
+	}
+	if tc.TestStatus != "" {
+		fmt.Printf("tests: %s", tc.TestStatus)
+		if len(tc.TestFailures) > 0 {
+			fmt.Printf(" failures=%s", strings.Join(tc.TestFailures, ","))
+		}
+		fmt.Println()
+	}
+	for _, f := range tc.Files {
+		fmt.Printf("file: %s\n", f)
+	}
+	if tc.PRD != "" {
+		fmt.Printf("prd: %s:%s\n%s\n", tc.PRDFile, tc.PRDLines, tc.PRD)
+	}
 	return 0
 }
+
+type taskContextJSON struct {
+	Task         taskJSON           `json:"task"`
+	Comments     []taskCommentJSON  `json:"comments"`
+	Stage        string             `json:"stage"`
+	Gates        []gateJSON         `json:"gates"`
+	Scenarios    []scenarioJSON     `json:"scenarios"`
+	Uncovered    []criterionJSON    `json:"uncovered"`
+	TestStatus   string             `json:"test_status"`
+	TestFailures []string           `json:"test_failures"`
+	Files        []string           `json:"files"`
+	PRD          taskContextPRDJSON `json:"prd"`
+}
+
+type gateJSON struct {
+	Stage  string `json:"stage"`
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type scenarioJSON struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type taskContextPRDJSON struct {
+	File    string `json:"file"`
+	Lines   string `json:"lines"`
+	Content string `json:"content"`
+}
+
+func newTaskContextJSON(tc core.TaskContext) taskContextJSON {
+	t := tc.Task
+	out := taskContextJSON{
+		Task:         taskJSON{ID: t.ID, Feature: t.Feature, Title: t.Title, Status: t.Status, Priority: t.Priority},
+		Comments:     newTaskCommentsJSON(t.Comments),
+		Stage:        tc.Stage,
+		Gates:        []gateJSON{},
+		Scenarios:    []scenarioJSON{},
+		Uncovered:    []criterionJSON{},
+		TestStatus:   tc.TestStatus,
+		TestFailures: nonNil(tc.TestFailures),
+		Files:        nonNil(tc.Files),
+		PRD:          taskContextPRDJSON{File: tc.PRDFile, Lines: tc.PRDLines, Content: tc.PRD},
+	}
+	for _, g := range tc.Gates {
+		out.Gates = append(out.Gates, gateJSON{Stage: g.Stage, State: g.State, Reason: g.Reason})
+	}
+	for _, s := range tc.Scenarios {
+		out.Scenarios = append(out.Scenarios, scenarioJSON{ID: s.ID, Title: s.Title})
+	}
+	for _, c := range tc.Uncovered {
+		out.Uncovered = append(out.Uncovered, criterionJSON{ID: c.ID, Text: c.Text, Scenarios: nonNil(c.Scenarios)})
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+func TestRunContext_ForTask(t *testing.T) {
+	dir := setupTaskProjectWithTasks(t, []string{"auth"}, "tasks:\n  - id: T-1\n    feature: auth\n    title: Login\n    status: TODO\n    priority: A\n")
+
+	var code int
+	var out string
+	withDir(t, dir, func() {
+		out = captureStdout(t, func() {
+			code = RunContext([]string{"--for-task", "T-1"}, true)
+		})
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	for _, want := range []string{`task: T-1 status=TODO feature=auth title="Login"`, "stage: ", "gate: "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	withDir(t, dir, func() {
+		code = RunContext([]string{"--for-task", "T-404"}, true)
+	})
+	if code != 2 {
+		t.Errorf("expected exit 2 for unknown task, got %d", code)
+	}
+}
+
+func TestWriteContextStaleReview(t *testing.T) {
+	reviewed := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	result := core.ContextResult{Lines: []core.ContextLine{{
+		Type: core.ContextStale, Feature: "auth", Stage: "prd", ReviewedAt: reviewed, Age: 45*24*time.Hour + 3*time.Hour,
+	}}}
+	var buf strings.Builder
+	writeContext(&buf, result, 0)
+	if got, want := buf.String(), "stale: auth stage=prd reviewed=2026-03-02 age=45d\n"; got != want {
+		t.Errorf("context = %q, want %q", got, want)
+	}
+}
+
+func TestRunContext_Budget(t *testing.T) {
+	dir := setupTaskProjectWithTasks(t, []string{"auth"}, "tasks:\n  - id: T-1\n    feature: auth\n    title: Login\n    status: WIP\n  - id: T-2\n    feature: auth\n    title: Logout\n    status: TODO\n")
+
+	var code int
+	var out string
+	withDir(t, dir, func() {
+		out = captureStdout(t, func() {
+			code = RunContext([]string{"--budget", "22"}, true)
+		})
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if !strings.HasPrefix(lines[0], "task: T-1 status=WIP") || !strings.HasPrefix(lines[len(lines)-1], "elided: ") {
+		t.Errorf("expected the WIP task first and an elision marker last:\n%s", out)
+	}
+
+	withDir(t, dir, func() {
+		code = RunContext([]string{"--budget", "lots"}, true)
+	})
+	if code != 2 {
+		t.Errorf("expected exit 2 for invalid --budget, got %d", code)
+	}
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunDaemon handles `ptsd daemon [stop|status]`.
+// Without a subcommand it serves hook and context requests on
+// .ptsd/daemon.sock in the foreground until stopped.
+func RunDaemon(args []string, agentMode bool) int {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "status":
+			if resp, ok := core.DaemonCall(cwd, core.DaemonRequest{Cmd: "ping"}); ok {
+				line := "running " + core.DaemonSocketPath(cwd)
+				if stats := strings.TrimSpace(resp.Stdout); stats != "" {
+					line += " " + stats
+				}
+				fmt.Println(line)
+				return 0
+			}
+			fmt.Println("stopped")
+			return 1
+		case "stop":
+			if _, ok := core.DaemonCall(cwd, core.DaemonRequest{Cmd: "stop"}); !ok {
+				return renderError(agentMode, "user", "no daemon running")
+			}
+			fmt.Println("stopped")
+			return 0
+		default:
+			return usageError(agentMode, "daemon", fmt.Sprintf("unknown subcommand %q: use stop|status", args[0]))
+		}
+	}
+
+	stop := make(chan struct{})
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		close(stop)
+	}()
+
+	fmt.Println("listening " + core.DaemonSocketPath(cwd))
+	if err := core.ServeDaemon(cwd, daemonHandler(cwd), stop); err != nil {
+		return coreError(agentMode, err)
+	}
+	return 0
+}
+
+// daemonHandler runs hook and context requests in-process and captures the
+// output the equivalent CLI invocation would print.
+func daemonHandler(projectDir string) core.DaemonHandler {
+	return func(req core.DaemonRequest) core.DaemonResponse {
+		var out, errOut bytes.Buffer
+		code := 0
+		switch req.Cmd {
+		case "gate-check":
+			code = gateCheckHook(projectDir, req.File, &errOut)
+		case "auto-track":
+			code = autoTrackHook(projectDir, req.File, &out)
+		case "context":
+			result, err := core.BuildContext(projectDir)
+			if err != nil {
+				fmt.Fprintln(&errOut, err.Error())
+				code = 1
+				break
+			}
+			writeContext(&out, result, req.Budget)
+		default:
+			fmt.Fprintf(&errOut, "err:user unknown daemon command: %s\n", req.Cmd)
+			code = 2
+		}
+		return core.DaemonResponse{Code: code, Stdout: out.String(), Stderr: errOut.String()}
+	}
+}
+
+// replayDaemonResponse prints a daemon response as if the command ran locally.
+func replayDaemonResponse(resp core.DaemonResponse) int {
+	fmt.Fprint(os.Stdout, resp.Stdout)
+	fmt.Fprint(os.Stderr, resp.Stderr)
+	return resp.Code
+}
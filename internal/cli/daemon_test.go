@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+func TestRunDaemon_StatusStopped(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	withDir(t, dir, func() {
+		if code := RunDaemon([]string{"status"}, true); code != 1 {
+			t.Errorf("expected exit 1 when stopped, got %d", code)
+		}
+		if code := RunDaemon([]string{"stop"}, true); code != 2 {
+			t.Errorf("expected exit 2 stopping absent daemon, got %d", code)
+		}
+	})
+}
+
+func TestRunContext_DelegatesToDaemon(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte("features:\n  - id: my-feat\n    status: in-progress\n"), 0644)
+	stop := make(chan struct{})
+	defer close(stop)
+	go core.ServeDaemon(dir, daemonHandler(dir), stop)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !core.DaemonRunning(dir) {
+		if time.Now().After(deadline) {
+			t.Fatal("daemon did not start")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var local string
+	withDir(t, dir, func() {
+		result, err := core.BuildContext(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		writeContext(&b, result, 0)
+		local = b.String()
+	})
+
+	resp, ok := core.DaemonCall(dir, core.DaemonRequest{Cmd: "context"})
+	if !ok {
+		t.Fatal("expected daemon response")
+	}
+	if resp.Code != 0 || resp.Stdout != local {
+		t.Errorf("daemon output differs from in-process:\ndaemon: %q\nlocal:  %q", resp.Stdout, local)
+	}
+	if !strings.Contains(resp.Stdout, "my-feat") {
+		t.Errorf("expected feature in context, got %q", resp.Stdout)
+	}
+
+	withDir(t, dir, func() {
+		out := captureStdout(t, func() {
+			if code := RunDaemon([]string{"status"}, true); code != 0 {
+				t.Errorf("status: expected exit 0, got %d", code)
+			}
+		})
+		if !strings.HasPrefix(out, "running ") || !strings.Contains(out, "parse_cache_hits=") {
+			t.Errorf("unexpected status output %q", out)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
+	"github.com/veschin/ptsd/internal/render"
+)
+
+// printDashboard prints the features × stages matrix once (status --format
+// dashboard). With --json it prints the matrix as a ptsd.dashboard/v1 payload.
+func printDashboard(cwd string, agentMode bool) int {
+	d, err := core.BuildDashboard(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if jsonOutput {
+		return printJSON(agentMode, "dashboard", newDashboardJSON(d))
+	}
+	fmt.Println(render.RenderDashboard(newDashboardView(d), !agentMode && useColor(os.Stdout)))
+	return 0
+}
+
+// runStatusTUI redraws the dashboard full-screen until Ctrl-C, rebuilding it
+// whenever one of the .ptsd files it reads changes. Plain ANSI escapes on the
+// alternate screen; no terminal library.
+func runStatusTUI(cwd string, interval time.Duration, agentMode bool) int {
+	if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return usageError(agentMode, "status", "--tui needs a terminal; use --format dashboard to print the matrix once")
+	}
+	if _, err := core.BuildDashboard(cwd); err != nil {
+		return coreError(agentMode, err)
+	}
+
+	stop := make(chan struct{})
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		close(stop)
+	}()
+
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	color := useColor(os.Stdout)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last := ""
+	for {
+		if stamp := core.DashboardStamp(cwd); stamp != last {
+			last = stamp
+			body := ""
+			if d, err := core.BuildDashboard(cwd); err != nil {
+				body = err.Error()
+			} else {
+				body = render.RenderDashboard(newDashboardView(d), color)
+			}
+			fmt.Printf("\x1b[H\x1b[2Jptsd status — %s — updated %s — Ctrl-C to quit\n\n%s\n",
+				cwd, time.Now().Format("15:04:05"), body)
+		}
+		select {
+		case <-stop:
+			return 0
+		case <-ticker.C:
+		}
+	}
+}
+
+func newDashboardView(d core.Dashboard) render.DashboardView {
+	v := render.DashboardView{Stages: d.Stages}
+	for _, r := range d.Rows {
+		row := render.DashboardRowView{ID: r.ID, Status: r.Status, Passed: r.Passed, Total: r.Total, Review: r.Review, Pending: r.Pending}
+		for _, c := range r.Cells {
+			row.Cells = append(row.Cells, render.DashboardCellView{State: c.State, Score: c.Score})
+		}
+		v.Rows = append(v.Rows, row)
+	}
+	return v
+}
+
+type dashboardJSON struct {
+	Stages   []string               `json:"stages"`
+	Features []dashboardFeatureJSON `json:"features"`
+}
+
+type dashboardFeatureJSON struct {
+	ID      string               `json:"id"`
+	Status  string               `json:"status"`
+	Stages  []dashboardStageJSON `json:"stages"`
+	Tests   testCountsJSON       `json:"tests"`
+	Review  string               `json:"review,omitempty"`
+	Pending int                  `json:"pending_tasks"`
+}
+
+type dashboardStageJSON struct {
+	Stage string `json:"stage"`
+	State string `json:"state,omitempty"`
+	Score *int   `json:"score,omitempty"`
+}
+
+type testCountsJSON struct {
+	Passed int `json:"passed"`
+	Total  int `json:"total"`
+}
+
+func newDashboardJSON(d core.Dashboard) dashboardJSON {
+	out := dashboardJSON{Stages: nonNil(d.Stages), Features: []dashboardFeatureJSON{}}
+	for _, r := range d.Rows {
+		f := dashboardFeatureJSON{ID: r.ID, Status: r.Status, Stages: []dashboardStageJSON{},
+			Tests: testCountsJSON{Passed: r.Passed, Total: r.Total}, Review: r.Review, Pending: r.Pending}
+		for i, c := range r.Cells {
+			s := dashboardStageJSON{Stage: d.Stages[i], State: c.State}
+			if c.Score >= 0 {
+				score := c.Score
+				s.Score = &score
+			}
+			f.Stages = append(f.Stages, s)
+		}
+		out.Features = append(out.Features, f)
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunDeinit handles `ptsd deinit [--yes] [--no-backup] [--force]`.
+// Without --yes it asks for confirmation; agent mode requires --yes.
+func RunDeinit(args []string, agentMode bool) int {
+	yes, backup, force := false, true, false
+	for _, a := range args {
+		switch a {
+		case "--yes", "-y":
+			yes = true
+		case "--no-backup":
+			backup = false
+		case "--force":
+			force = true
+		default:
+			return usageError(agentMode, "deinit", "unknown flag "+a)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	if !yes {
+		if agentMode {
+			return renderError(agentMode, "user", "deinit removes .ptsd/ and all ptsd hooks; pass --yes to confirm")
+		}
+		fmt.Printf("Remove ptsd from %s (.ptsd/, CLAUDE.md section, .claude/ hooks, git hooks)? [y/N] ", cwd)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Println("aborted")
+			return 2
+		}
+	}
+
+	res, err := core.DeinitProject(cwd, core.DeinitOptions{Backup: backup, Force: force})
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	if agentMode {
+		if res.Backup != "" {
+			fmt.Printf("backup:%s\n", res.Backup)
+		}
+		for _, p := range res.Removed {
+			fmt.Printf("removed:%s\n", p)
+		}
+		for _, p := range res.Restored {
+			fmt.Printf("restored:%s\n", p)
+		}
+		for _, p := range res.Kept {
+			fmt.Printf("kept:%s\n", p)
+		}
+		fmt.Println("deinit:ok")
+		return 0
+	}
+
+	for _, p := range res.Removed {
+		fmt.Printf("removed  %s\n", p)
+	}
+	for _, p := range res.Restored {
+		fmt.Printf("restored %s\n", p)
+	}
+	for _, p := range res.Kept {
+		fmt.Fprintf(os.Stderr, "warn: kept modified %s (--force to remove)\n", p)
+	}
+	if res.Backup != "" {
+		fmt.Printf("Backup of .ptsd/ saved to %s\n", res.Backup)
+	}
+	fmt.Printf("Removed ptsd from %s\n", cwd)
+	return 0
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunDoctor executes `ptsd doctor`: environment and project health checks,
+// each failing one with a fix. Exit 0 = no failures (warnings allowed),
+// 1 = at least one check failed.
+func RunDoctor(args []string, agentMode bool) int {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	checks, err := core.Doctor(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	counts := map[string]int{}
+	for _, c := range checks {
+		counts[c.Status]++
+	}
+
+	if jsonOutput {
+		out := doctorJSON{OK: counts["fail"] == 0, Checks: []doctorCheckJSON{}}
+		for _, c := range checks {
+			out.Checks = append(out.Checks, doctorCheckJSON(c))
+		}
+		printJSON(true, "doctor", out)
+	} else {
+		for _, c := range checks {
+			if agentMode {
+				line := fmt.Sprintf("%s:%s %s", c.Status, c.Check, c.Message)
+				if c.Fix != "" {
+					line += " fix: " + c.Fix
+				}
+				fmt.Println(line)
+				continue
+			}
+			fmt.Printf("%-6s %-9s %s\n", "["+strings.ToUpper(c.Status)+"]", c.Check, c.Message)
+			if c.Fix != "" {
+				fmt.Printf("%-16s fix: %s\n", "", c.Fix)
+			}
+		}
+		if agentMode {
+			fmt.Printf("doctor ok=%d warn=%d fail=%d\n", counts["ok"], counts["warn"], counts["fail"])
+		} else {
+			fmt.Printf("\n%d ok, %d warning(s), %d failed\n", counts["ok"], counts["warn"], counts["fail"])
+		}
+	}
+
+	if counts["fail"] > 0 {
+		return 1
+	}
+	return 0
+}
+
+type doctorJSON struct {
+	OK     bool              `json:"ok"`
+	Checks []doctorCheckJSON `json:"checks"`
+}
+
+type doctorCheckJSON struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunDoctor_FailingChecksExit1WithFixes(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+
+	var code int
+	out := captureStdout(t, func() {
+		code = RunDoctor(nil, true)
+	})
+	if code != 1 {
+		t.Errorf("expected exit 1 without .claude/settings.json, got %d", code)
+	}
+	for _, want := range []string{
+		"ok:config ptsd.yaml parses\n",
+		"fail:claude .claude/settings.json not found fix: ptsd init --force\n",
+		"ok:runner runner go test ./...\n",
+		"doctor ok=",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDoctor_NoProject(t *testing.T) {
+	chdir(t, t.TempDir())
+	if code := RunDoctor(nil, true); code != 3 {
+		t.Errorf("expected exit 3 without .ptsd, got %d", code)
+	}
+}
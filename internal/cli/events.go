@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunEvents handles `ptsd events tail [-n N] [--feature F] [--type T,...]
+// [--follow [--interval 500ms]]`: the last N pipeline events from
+// .ptsd/events.yaml, and with --follow each new one as it is appended.
+func RunEvents(args []string, agentMode bool) int {
+	const usage = "usage: events tail [-n N] [--feature <id>] [--type <t,...>] [--follow [--interval 500ms]]"
+	if len(args) == 0 || args[0] != "tail" {
+		return usageError(agentMode, "events", usage)
+	}
+
+	n := 10
+	follow := false
+	interval := 500 * time.Millisecond
+	var filter core.EventFilter
+	rest := args[1:]
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case "--follow", "-f":
+			follow = true
+		case "-n", "--feature", "--type", "--interval":
+			if i+1 >= len(rest) {
+				return usageError(agentMode, "events", rest[i]+" requires a value")
+			}
+			val := rest[i+1]
+			i++
+			switch rest[i-1] {
+			case "-n":
+				v, err := strconv.Atoi(val)
+				if err != nil || v < 0 {
+					return usageError(agentMode, "events", fmt.Sprintf("invalid -n value %q", val))
+				}
+				n = v
+			case "--feature":
+				filter.Feature = val
+			case "--type":
+				for _, t := range strings.Split(val, ",") {
+					if !slices.Contains(core.EventTypes, t) {
+						return usageError(agentMode, "events", fmt.Sprintf("unknown event type %q: use %s", t, strings.Join(core.EventTypes, "|")))
+					}
+					filter.Types = append(filter.Types, t)
+				}
+			case "--interval":
+				d, err := time.ParseDuration(val)
+				if err != nil || d <= 0 {
+					return usageError(agentMode, "events", fmt.Sprintf("invalid --interval %q: use a duration like 500ms or 2s", val))
+				}
+				interval = d
+			}
+		default:
+			return usageError(agentMode, "events", fmt.Sprintf("unknown flag %q; %s", rest[i], usage))
+		}
+	}
+	if follow && jsonOutput {
+		return usageError(agentMode, "events", "--follow streams lines and cannot be combined with --json")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+	if _, err := os.Stat(filepath.Join(cwd, ".ptsd")); err != nil {
+		return renderError(agentMode, "config", ".ptsd not found: run ptsd init")
+	}
+
+	events, offset, err := core.TailEvents(cwd, filter, n)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if jsonOutput {
+		out := make([]eventJSON, 0, len(events))
+		for _, e := range events {
+			out = append(out, newEventJSON(e))
+		}
+		return printJSON(agentMode, "events", out)
+	}
+	for _, e := range events {
+		fmt.Println(formatEvent(e, agentMode))
+	}
+	if !follow {
+		return 0
+	}
+
+	stop := make(chan struct{})
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		close(stop)
+	}()
+	err = core.FollowEvents(cwd, offset, filter, interval, func(e core.Event) {
+		fmt.Println(formatEvent(e, agentMode))
+	}, stop)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	return 0
+}
+
+// formatEvent renders one event as a line. Agent mode prints key:value
+// tokens with the free-text detail last; human mode a local-time log line.
+func formatEvent(e core.Event, agentMode bool) string {
+	var parts []string
+	if agentMode {
+		parts = append(parts, "event:"+e.Type, "at:"+e.At.UTC().Format(time.RFC3339))
+		if e.Feature != "" {
+			parts = append(parts, "feature:"+e.Feature)
+		}
+		if e.Stage != "" {
+			parts = append(parts, "stage:"+e.Stage)
+		}
+		if e.Task != "" {
+			parts = append(parts, "task:"+e.Task)
+		}
+		if e.Type == core.EventReview {
+			parts = append(parts, "score:"+strconv.Itoa(e.Score))
+		}
+		if e.Detail != "" {
+			parts = append(parts, "detail:"+e.Detail)
+		}
+		return strings.Join(parts, " ")
+	}
+
+	feature := e.Feature
+	if feature == "" {
+		feature = "-"
+	}
+	parts = append(parts, e.At.Local().Format("2006-01-02 15:04:05"), fmt.Sprintf("%-10s %-16s", e.Type, feature))
+	if e.Stage != "" {
+		parts = append(parts, e.Stage)
+	}
+	if e.Task != "" {
+		parts = append(parts, e.Task)
+	}
+	if e.Type == core.EventReview {
+		parts = append(parts, "score="+strconv.Itoa(e.Score))
+	}
+	if e.Detail != "" {
+		parts = append(parts, e.Detail)
+	}
+	return strings.Join(parts, " ")
+}
+
+type eventJSON struct {
+	At      string `json:"at"`
+	Type    string `json:"type"`
+	Feature string `json:"feature,omitempty"`
+	Stage   string `json:"stage,omitempty"`
+	Task    string `json:"task,omitempty"`
+	Score   *int   `json:"score,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+func newEventJSON(e core.Event) eventJSON {
+	out := eventJSON{At: e.At.UTC().Format(time.RFC3339Nano), Type: e.Type, Feature: e.Feature, Stage: e.Stage, Task: e.Task, Detail: e.Detail}
+	if e.Type == core.EventReview {
+		score := e.Score
+		out.Score = &score
+	}
+	return out
+}
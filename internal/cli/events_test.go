@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+func TestRunEvents_Tail(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdirTo(t, dir)
+
+	core.AppendEvent(dir, core.Event{Type: core.EventReview, Feature: "auth", Stage: "prd", Score: 8, Detail: "reviewer=alice"})
+	core.AppendEvent(dir, core.Event{Type: core.EventTask, Feature: "cart", Task: "T-2", Detail: "DONE"})
+	// A blocked write is logged as a gate event.
+	if code := gateCheckHook(dir, ".ptsd/review-status.yaml", io.Discard); code != 2 {
+		t.Fatalf("expected review-status write to be blocked, got exit %d", code)
+	}
+
+	out := captureStdout(t, func() {
+		if code := RunEvents([]string{"tail", "--type", "review,gate"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 events, got:\n%s", out)
+	}
+	if !strings.HasPrefix(lines[0], "event:review at:") || !strings.HasSuffix(lines[0], "feature:auth stage:prd score:8 detail:reviewer=alice") {
+		t.Errorf("review line = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "event:gate ") || !strings.Contains(lines[1], "detail:.ptsd/review-status.yaml: ") {
+		t.Errorf("gate line = %q", lines[1])
+	}
+
+	out = captureStdout(t, func() {
+		RunEvents([]string{"tail", "--feature", "cart", "-n", "5"}, true)
+	})
+	if !strings.Contains(out, "event:task") || strings.Contains(out, "feature:auth") {
+		t.Errorf("feature filter output:\n%s", out)
+	}
+
+	if code := RunEvents([]string{"tail", "--type", "bogus"}, true); code != 2 {
+		t.Errorf("expected exit 2 for an unknown type, got %d", code)
+	}
+	if code := RunEvents(nil, true); code != 2 {
+		t.Errorf("expected exit 2 without a subcommand, got %d", code)
+	}
+}
@@ -3,7 +3,10 @@ package cli
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/veschin/ptsd/internal/core"
 	"github.com/veschin/ptsd/internal/render"
@@ -24,12 +27,25 @@ func RunFeature(args []string, agentMode bool) int {
 
 	switch sub {
 	case "add":
-		if len(rest) < 2 {
-			return usageError(agentMode, "feature add", "usage: feature add <id> <title>")
+		mode, parent := "", ""
+		var words []string
+		for i := 0; i < len(rest); i++ {
+			switch {
+			case rest[i] == "--lite":
+				mode = core.PipelineLite
+			case rest[i] == "--parent" && i+1 < len(rest):
+				parent = rest[i+1]
+				i++
+			default:
+				words = append(words, rest[i])
+			}
 		}
-		id := rest[0]
-		title := strings.Join(rest[1:], " ")
-		if err := core.AddFeature(cwd, id, title); err != nil {
+		if len(words) < 2 {
+			return usageError(agentMode, "feature add", "usage: feature add <id> <title> [--lite] [--parent <id>]")
+		}
+		id := words[0]
+		title := strings.Join(words[1:], " ")
+		if err := core.AddFeatureWithParent(cwd, id, title, mode, parent); err != nil {
 			return coreError(agentMode, err)
 		}
 		if agentMode {
@@ -37,24 +53,98 @@ func RunFeature(args []string, agentMode bool) int {
 		} else {
 			fmt.Printf("Added feature: %s\n", id)
 		}
+		similar, _ := core.SimilarFeatures(cwd, id, title)
+		for _, s := range similar {
+			if agentMode {
+				fmt.Fprintf(os.Stderr, "warn: similar-feature id=%s score=%.2f match=%s title=%q\n", s.ID, s.Score, s.Match, s.Title)
+			} else {
+				fmt.Fprintf(os.Stderr, "warn: %s looks like existing feature %s %q (%s %d%% similar); remove it with ptsd feature remove %s if it is a duplicate\n",
+					id, s.ID, s.Title, s.Match, int(s.Score*100), id)
+			}
+		}
 		return 0
 
 	case "list":
-		filter := ""
-		if len(rest) > 0 {
-			filter = rest[0]
+		filter, sortKey, columns := "", "", ""
+		jsonOut, withState, reverse := jsonOutput, false, false
+		for i := 0; i < len(rest); i++ {
+			switch a := rest[i]; a {
+			case "--json":
+				jsonOut = true
+			case "--with-state":
+				withState = true
+			case "--reverse":
+				reverse = true
+			case "--sort", "--columns":
+				if i+1 >= len(rest) {
+					return usageError(agentMode, "feature list", a+" requires a value")
+				}
+				if a == "--sort" {
+					sortKey = rest[i+1]
+				} else {
+					columns = rest[i+1]
+				}
+				i++
+			default:
+				if filter == "" {
+					filter = a
+				}
+			}
+		}
+		if withState || sortKey != "" || columns != "" {
+			var cols []string
+			if columns != "" {
+				cols = strings.Split(columns, ",")
+				for _, c := range cols {
+					if _, ok := featureColumns[c]; !ok {
+						return usageError(agentMode, "feature list", fmt.Sprintf("unknown column %q: use %s", c, strings.Join(featureColumnNames, ",")))
+					}
+				}
+			}
+			overviews, err := core.FeatureOverviews(cwd, filter)
+			if err != nil {
+				return coreError(agentMode, err)
+			}
+			if sortKey != "" {
+				if err := core.SortFeatureOverviews(cwd, overviews, sortKey); err != nil {
+					return coreError(agentMode, err)
+				}
+			}
+			if reverse {
+				slices.Reverse(overviews)
+			}
+			if cols != nil && !agentMode && !jsonOut {
+				printFeatureColumns(overviews, cols)
+				return 0
+			}
+			return printFeatureOverviews(agentMode, jsonOut, overviews)
 		}
 		features, err := core.ListFeatures(cwd, filter)
 		if err != nil {
 			return coreError(agentMode, err)
 		}
+		if jsonOut {
+			out := make([]featureJSON, 0, len(features))
+			for _, f := range features {
+				out = append(out, newFeatureJSON(f))
+			}
+			return printJSON(agentMode, "feature.list", out)
+		}
 		if agentMode {
 			for _, f := range features {
-				fmt.Printf("%s [%s] %s\n", f.ID, f.Status, f.Title)
+				if f.Pipeline != "" {
+					fmt.Printf("%s [%s] %s pipeline=%s\n", f.ID, f.Status, f.Title, f.Pipeline)
+				} else {
+					fmt.Printf("%s [%s] %s\n", f.ID, f.Status, f.Title)
+				}
 			}
 		} else {
 			for _, f := range features {
-				fmt.Printf("%-30s %-15s %s\n", f.ID, f.Status, f.Title)
+				title := f.Title
+				if f.Pipeline != "" {
+					title += " (" + f.Pipeline + ")"
+				}
+				fmt.Printf("%-30s %-15s %s\n", f.ID, f.Status, title)
 			}
 		}
 		return 0
@@ -90,15 +180,132 @@ func RunFeature(args []string, agentMode bool) int {
 		}
 		return 0
 
+	case "pipeline":
+		if len(rest) < 2 {
+			return usageError(agentMode, "feature pipeline", "usage: feature pipeline <id> <full|lite>")
+		}
+		id, mode := rest[0], rest[1]
+		if err := core.SetFeaturePipeline(cwd, id, mode); err != nil {
+			return coreError(agentMode, err)
+		}
+		if agentMode {
+			fmt.Printf("feature.pipeline id=%s pipeline=%s\n", id, mode)
+		} else {
+			fmt.Printf("Feature %s uses the %s pipeline\n", id, mode)
+		}
+		return 0
+
+	case "parent":
+		if len(rest) < 2 {
+			return usageError(agentMode, "feature parent", "usage: feature parent <id> <parent-id|none>")
+		}
+		id, parent := rest[0], rest[1]
+		if err := core.SetFeatureParent(cwd, id, parent); err != nil {
+			return coreError(agentMode, err)
+		}
+		if agentMode {
+			fmt.Printf("feature.parent id=%s parent=%s\n", id, parent)
+		} else if parent == "none" {
+			fmt.Printf("Feature %s has no parent\n", id)
+		} else {
+			fmt.Printf("Feature %s is now a child of %s\n", id, parent)
+		}
+		return 0
+
+	case "depends":
+		if len(rest) < 2 {
+			return usageError(agentMode, "feature depends", "usage: feature depends <id> <dep-id>... | none")
+		}
+		id, deps := rest[0], rest[1:]
+		if err := core.SetFeatureDependencies(cwd, id, deps); err != nil {
+			return coreError(agentMode, err)
+		}
+		list := strings.Join(deps, ",")
+		if agentMode {
+			fmt.Printf("feature.depends id=%s depends_on=%s\n", id, list)
+		} else if list == "none" {
+			fmt.Printf("Feature %s has no dependencies\n", id)
+		} else {
+			fmt.Printf("Feature %s depends on %s\n", id, strings.Join(deps, ", "))
+		}
+		return 0
+
+	case "defer":
+		revisit := ""
+		var words []string
+		for i := 0; i < len(rest); i++ {
+			if rest[i] == "--until" && i+1 < len(rest) {
+				revisit = rest[i+1]
+				i++
+				continue
+			}
+			words = append(words, rest[i])
+		}
+		if len(words) < 2 {
+			return usageError(agentMode, "feature defer", "usage: feature defer <id> <reason> [--until YYYY-MM-DD]")
+		}
+		id := words[0]
+		reason := strings.Join(words[1:], " ")
+		if err := core.DeferFeature(cwd, id, reason, revisit); err != nil {
+			return coreError(agentMode, err)
+		}
+		if agentMode {
+			fmt.Printf("feature.defer id=%s revisit=%s\n", id, revisit)
+		} else if revisit != "" {
+			fmt.Printf("Deferred feature %s until %s\n", id, revisit)
+		} else {
+			fmt.Printf("Deferred feature %s\n", id)
+		}
+		return 0
+
+	case "undefer":
+		if len(rest) < 1 {
+			return usageError(agentMode, "feature undefer", "usage: feature undefer <id>")
+		}
+		id := rest[0]
+		if err := core.UndeferFeature(cwd, id); err != nil {
+			return coreError(agentMode, err)
+		}
+		if agentMode {
+			fmt.Printf("feature.undefer id=%s status=planned\n", id)
+		} else {
+			fmt.Printf("Feature %s is planned again\n", id)
+		}
+		return 0
+
 	case "show":
 		if len(rest) < 1 {
-			return usageError(agentMode, "feature show", "usage: feature show <id>")
+			return usageError(agentMode, "feature show", "usage: feature show <id> [--graph|--json]")
 		}
 		id := rest[0]
+		jsonOut := jsonOutput
+		for _, a := range rest[1:] {
+			switch a {
+			case "--graph":
+				return runFeatureGraph(cwd, id, agentMode)
+			case "--json":
+				jsonOut = true
+			}
+		}
 		detail, err := core.ShowFeature(cwd, id)
 		if err != nil {
 			return coreError(agentMode, err)
 		}
+		rollup, isEpic, err := core.FeatureRollup(cwd, id)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		if jsonOut {
+			out, err := newFeatureShowJSON(cwd, detail)
+			if err != nil {
+				return coreError(agentMode, err)
+			}
+			if isEpic {
+				r := newRollupJSON(rollup)
+				out.Rollup = &r
+			}
+			return printJSON(agentMode, "feature.show", out)
+		}
 		fv := render.FeatureView{
 			ID:         detail.ID,
 			Status:     detail.Status,
@@ -109,9 +316,431 @@ func RunFeature(args []string, agentMode bool) int {
 		}
 		r := newRenderer(agentMode)
 		fmt.Println(r.RenderFeatureShow(fv))
+		if detail.Parent != "" {
+			fmt.Printf("parent: %s\n", detail.Parent)
+		}
+		if len(detail.DependsOn) > 0 {
+			fmt.Printf("depends_on: %s\n", strings.Join(detail.DependsOn, ", "))
+		}
+		if isEpic {
+			for _, c := range rollup.Children {
+				fmt.Printf("child: %s [%s]\n", c.ID, c.Status)
+			}
+			fmt.Printf("rollup: %s\n", formatRollup(rollup))
+		}
 		return 0
 
+	case "split":
+		return runFeatureSplit(cwd, rest, agentMode)
+
 	default:
-		return usageError(agentMode, "feature", fmt.Sprintf("unknown subcommand %q: use add|list|remove|status|show", sub))
+		return usageError(agentMode, "feature", fmt.Sprintf("unknown subcommand %q: use add|list|remove|status|show|pipeline|parent|depends|defer|undefer|split", sub))
+	}
+}
+
+// runFeatureSplit moves scenarios, seed files and tasks into a child feature.
+func runFeatureSplit(cwd string, args []string, agentMode bool) int {
+	const usage = "usage: feature split <id> <child-id> [--title <title>] [--scenario <id|title>]... [--seed <file>]... [--task <T-n>]..."
+	var opts core.SplitOptions
+	var ids []string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--title", "--scenario", "--seed", "--task":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "feature split", args[i]+" requires a value")
+			}
+			v := args[i+1]
+			switch args[i] {
+			case "--title":
+				opts.Title = v
+			case "--scenario":
+				opts.Scenarios = append(opts.Scenarios, v)
+			case "--seed":
+				opts.Seeds = append(opts.Seeds, v)
+			case "--task":
+				opts.Tasks = append(opts.Tasks, v)
+			}
+			i++
+		default:
+			ids = append(ids, args[i])
+		}
+	}
+	if len(ids) != 2 {
+		return usageError(agentMode, "feature split", usage)
+	}
+	parent, child := ids[0], ids[1]
+	res, err := core.SplitFeature(cwd, parent, child, opts)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if agentMode {
+		fmt.Printf("feature.split parent=%s child=%s scenarios=%d seeds=%d tasks=%d mappings=%d prd=%t\n",
+			parent, child, len(res.Scenarios), len(res.Seeds), len(res.Tasks), res.Mappings, res.PRD)
+		return 0
+	}
+	fmt.Printf("Split %s from %s\n", child, parent)
+	if len(res.Scenarios) > 0 {
+		fmt.Printf("  scenarios: %s\n", strings.Join(res.Scenarios, ", "))
+	}
+	if len(res.Seeds) > 0 {
+		fmt.Printf("  seeds: %s\n", strings.Join(res.Seeds, ", "))
+	}
+	if len(res.Tasks) > 0 {
+		fmt.Printf("  tasks: %s\n", strings.Join(res.Tasks, ", "))
+	}
+	if res.Mappings > 0 {
+		fmt.Printf("  test mappings moved: %d\n", res.Mappings)
+	}
+	if !res.PRD {
+		fmt.Printf("  %s has no PRD anchor; add <!-- feature:%s --> to the PRD\n", parent, child)
+	}
+	return 0
+}
+
+// runFeatureGraph prints the feature's pipeline as a Mermaid state diagram.
+func runFeatureGraph(cwd string, id string, agentMode bool) int {
+	stages, err := core.FeaturePipeline(cwd, id)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	views := make([]render.PipelineStageView, len(stages))
+	for i, s := range stages {
+		views[i] = render.PipelineStageView{Stage: s.Stage, State: s.State, Reason: s.Reason}
+	}
+	fmt.Println(render.RenderStateDiagram(id, views))
+	return 0
+}
+
+type featureJSON struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Status      string   `json:"status"`
+	Pipeline    string   `json:"pipeline"`
+	DeferReason string   `json:"defer_reason,omitempty"`
+	Revisit     string   `json:"revisit,omitempty"`
+	Parent      string   `json:"parent,omitempty"`
+	DependsOn   []string `json:"depends_on,omitempty"`
+}
+
+func newFeatureJSON(f core.Feature) featureJSON {
+	pipeline := f.Pipeline
+	if pipeline == "" {
+		pipeline = "full"
+	}
+	return featureJSON{ID: f.ID, Title: f.Title, Status: f.Status, Pipeline: pipeline, DeferReason: f.DeferReason, Revisit: f.Revisit, Parent: f.Parent, DependsOn: f.DependsOn}
+}
+
+type scoreJSON struct {
+	Score int    `json:"score"`
+	At    string `json:"at"`
+}
+
+type reviewStatusJSON struct {
+	Stage  string            `json:"stage"`
+	Tests  string            `json:"tests"`
+	Review string            `json:"review"`
+	Issues []reviewIssueJSON `json:"issues"`
+}
+
+type coverageJSON struct {
+	Criteria  int `json:"criteria"`
+	Covered   int `json:"covered"`
+	Scenarios int `json:"scenarios"`
+	TestFiles int `json:"test_files"`
+}
+
+type featureStateJSON struct {
+	featureJSON
+	Stage    string               `json:"stage"`
+	Hashes   map[string]string    `json:"hashes"`
+	Scores   map[string]scoreJSON `json:"scores"`
+	Review   reviewStatusJSON     `json:"review"`
+	Tests    []string             `json:"tests"`
+	Coverage coverageJSON         `json:"coverage"`
+	Tasks    map[string]int       `json:"tasks"`
+	Score    int                  `json:"score"`
+	Progress int                  `json:"progress"`
+	// LastActivity is RFC 3339, empty when the feature has no activity.
+	LastActivity string `json:"last_activity"`
+}
+
+// featureShowSchema versions the feature show document for orchestrators:
+// fields may be added under v1, never renamed or removed.
+const featureShowSchema = "ptsd.feature.show/v1"
+
+type featureShowJSON struct {
+	Schema    string      `json:"schema"`
+	ID        string      `json:"id"`
+	Title     string      `json:"title"`
+	Status    string      `json:"status"`
+	Pipeline  string      `json:"pipeline"`
+	PRD       string      `json:"prd"`
+	Seed      string      `json:"seed"`
+	Scenarios int         `json:"scenarios"`
+	Tests     int         `json:"tests"`
+	Parent    string      `json:"parent,omitempty"`
+	Children  []string    `json:"children"`
+	DependsOn []string    `json:"depends_on,omitempty"`
+	Rollup    *rollupJSON `json:"rollup,omitempty"`
+	// DeferReason and Revisit are set for deferred features.
+	DeferReason string               `json:"defer_reason,omitempty"`
+	Revisit     string               `json:"revisit,omitempty"`
+	Stage       string               `json:"stage"`
+	Scores      map[string]scoreJSON `json:"scores"`
+	Review      reviewStatusJSON     `json:"review"`
+	Coverage    coverageJSON         `json:"coverage"`
+	TestFiles   []string             `json:"test_files"`
+	Tasks       []taskJSON           `json:"tasks"`
+	TaskCounts  map[string]int       `json:"task_counts"`
+	Regressions []regressionJSON     `json:"regressions"`
+	// Next is what context would tell the agent to do; null when context
+	// has nothing for the feature (e.g. planned or implemented).
+	Next     *nextActionJSON `json:"next"`
+	Score    int             `json:"score"`
+	Progress int             `json:"progress"`
+	// LastActivity is RFC 3339, empty when the feature has no activity.
+	LastActivity string `json:"last_activity"`
+}
+
+type nextActionJSON struct {
+	Type   string `json:"type"` // next | blocked | done | revisit
+	Stage  string `json:"stage,omitempty"`
+	Action string `json:"action,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// newFeatureShowJSON assembles the full feature show document: registry
+// meta, state, review, coverage, tasks, regressions and the next action.
+func newFeatureShowJSON(cwd string, detail core.FeatureDetail) (featureShowJSON, error) {
+	out := featureShowJSON{
+		Schema: featureShowSchema,
+		ID:     detail.ID, Status: detail.Status, PRD: detail.PRDAnchor, Seed: detail.SeedStatus,
+		Scenarios: detail.ScenarioCount, Tests: detail.TestCount,
+		Parent: detail.Parent, Children: nonNil(detail.Children),
+		DependsOn:   detail.DependsOn,
+		Scores:      map[string]scoreJSON{},
+		TestFiles:   []string{},
+		TaskCounts:  map[string]int{},
+		Regressions: []regressionJSON{},
+	}
+
+	overviews, err := core.FeatureOverviews(cwd, "")
+	if err != nil {
+		return out, err
+	}
+	for _, o := range overviews {
+		if o.ID != detail.ID {
+			continue
+		}
+		f := newFeatureJSON(o.Feature)
+		out.Title, out.Pipeline, out.DeferReason, out.Revisit = f.Title, f.Pipeline, f.DeferReason, f.Revisit
+		out.Stage = o.Stage
+		for stage, sc := range o.Scores {
+			at := ""
+			if !sc.Timestamp.IsZero() {
+				at = sc.Timestamp.UTC().Format(time.RFC3339)
+			}
+			out.Scores[stage] = scoreJSON{Score: sc.Value, At: at}
+		}
+		out.Review = reviewStatusJSON{
+			Stage: o.Review.Stage, Tests: o.Review.Tests, Review: o.Review.Review,
+			Issues: newReviewIssuesJSON(o.Review.IssuesList),
+		}
+		out.Coverage = coverageJSON{Criteria: o.Criteria, Covered: o.Covered, Scenarios: o.Scenarios, TestFiles: len(o.TestFiles)}
+		out.TestFiles = nonNil(o.TestFiles)
+		out.TaskCounts = o.Tasks
+		out.Score, out.Progress = o.Score, o.Progress
+		if !o.LastActivity.IsZero() {
+			out.LastActivity = o.LastActivity.UTC().Format(time.RFC3339)
+		}
+	}
+
+	tasks, err := core.ListTasks(cwd, detail.ID, "")
+	if err != nil {
+		return out, err
+	}
+	out.Tasks = newTasksJSON(tasks)
+
+	regressions, _ := core.CheckRegressions(cwd)
+	for _, w := range regressions {
+		if w.Feature == detail.ID {
+			out.Regressions = append(out.Regressions, regressionJSON{Feature: w.Feature, Severity: w.Severity, Message: w.Message})
+		}
+	}
+
+	next, ok, err := core.FeatureNext(cwd, detail.ID)
+	if err != nil {
+		return out, err
+	}
+	if ok {
+		out.Next = &nextActionJSON{Type: string(next.Type), Stage: next.Stage, Action: next.Action, Reason: next.Reason}
+	}
+	return out, nil
+}
+
+// rollupJSON is an epic's status, coverage and progress over its descendants.
+type rollupJSON struct {
+	ID          string         `json:"id"`
+	Status      string         `json:"status"`
+	Children    []string       `json:"children"`
+	Descendants int            `json:"descendants"`
+	Implemented int            `json:"implemented"`
+	TestsPass   int            `json:"tests_passing"`
+	Criteria    int            `json:"criteria"`
+	Covered     int            `json:"covered"`
+	Scenarios   int            `json:"scenarios"`
+	Tasks       map[string]int `json:"tasks"`
+}
+
+func newRollupJSON(r core.EpicRollup) rollupJSON {
+	out := rollupJSON{
+		ID: r.ID, Status: r.Status, Children: []string{}, Descendants: r.Descendants, Implemented: r.Implemented,
+		TestsPass: r.TestsPassing, Criteria: r.Criteria, Covered: r.Covered, Scenarios: r.Scenarios, Tasks: r.Tasks,
+	}
+	for _, c := range r.Children {
+		out.Children = append(out.Children, c.ID)
+	}
+	return out
+}
+
+// formatRollup renders an epic rollup as space-separated key=value pairs.
+func formatRollup(r core.EpicRollup) string {
+	taskTotal := 0
+	for _, n := range r.Tasks {
+		taskTotal += n
+	}
+	return fmt.Sprintf("status=%s implemented=%d/%d tests=%d/%d ac=%d/%d scn=%d tasks=%d/%d",
+		r.Status, r.Implemented, r.Descendants, r.TestsPassing, r.Descendants,
+		r.Covered, r.Criteria, r.Scenarios, r.Tasks["DONE"], taskTotal)
+}
+
+// printFeatureOverviews renders `feature list --with-state`: one JSON
+// document with --json, otherwise one line per feature.
+func printFeatureOverviews(agentMode, jsonOut bool, overviews []core.FeatureOverview) int {
+	if jsonOut {
+		out := make([]featureStateJSON, 0, len(overviews))
+		for _, o := range overviews {
+			fs := featureStateJSON{
+				featureJSON: newFeatureJSON(o.Feature),
+				Stage:       o.Stage,
+				Hashes:      o.Hashes,
+				Scores:      make(map[string]scoreJSON),
+				Review: reviewStatusJSON{
+					Stage: o.Review.Stage, Tests: o.Review.Tests, Review: o.Review.Review,
+					Issues: newReviewIssuesJSON(o.Review.IssuesList),
+				},
+				Tests:    nonNil(o.TestFiles),
+				Coverage: coverageJSON{Criteria: o.Criteria, Covered: o.Covered, Scenarios: o.Scenarios, TestFiles: len(o.TestFiles)},
+				Tasks:    o.Tasks,
+				Score:    o.Score,
+				Progress: o.Progress,
+			}
+			if !o.LastActivity.IsZero() {
+				fs.LastActivity = o.LastActivity.UTC().Format(time.RFC3339)
+			}
+			if fs.Hashes == nil {
+				fs.Hashes = map[string]string{}
+			}
+			for stage, sc := range o.Scores {
+				at := ""
+				if !sc.Timestamp.IsZero() {
+					at = sc.Timestamp.UTC().Format(time.RFC3339)
+				}
+				fs.Scores[stage] = scoreJSON{Score: sc.Value, At: at}
+			}
+			out = append(out, fs)
+		}
+		return printJSON(agentMode, "feature.list.state", out)
+	}
+
+	for _, o := range overviews {
+		tasks := fmt.Sprintf("%d/%d/%d", o.Tasks["TODO"], o.Tasks["WIP"], o.Tasks["DONE"])
+		ac := fmt.Sprintf("%d/%d", o.Covered, o.Criteria)
+		if agentMode {
+			activity := "-"
+			if !o.LastActivity.IsZero() {
+				activity = o.LastActivity.UTC().Format(time.RFC3339)
+			}
+			fmt.Printf("%s [%s] stage=%s review=%s tests=%d ac=%s scenarios=%d tasks=%s score=%d progress=%d activity=%s\n",
+				o.ID, o.Status, dashIfEmpty(o.Stage), dashIfEmpty(o.Review.Review), len(o.TestFiles), ac, o.Scenarios, tasks,
+				o.Score, o.Progress, activity)
+		} else {
+			fmt.Printf("%-30s %-12s %-8s review:%-6s tests:%-3d ac:%-6s tasks(todo/wip/done):%s\n",
+				o.ID, o.Status, dashIfEmpty(o.Stage), dashIfEmpty(o.Review.Review), len(o.TestFiles), ac, tasks)
+		}
+	}
+	return 0
+}
+
+// featureColumnNames lists the `feature list --columns` choices in help order.
+var featureColumnNames = []string{"id", "status", "title", "pipeline", "stage", "review", "score",
+	"tests", "coverage", "scenarios", "tasks", "progress", "last-activity"}
+
+// featureColumns renders one cell of `feature list --columns`.
+var featureColumns = map[string]func(o core.FeatureOverview) string{
+	"id":        func(o core.FeatureOverview) string { return o.ID },
+	"status":    func(o core.FeatureOverview) string { return o.Status },
+	"title":     func(o core.FeatureOverview) string { return o.Title },
+	"pipeline":  func(o core.FeatureOverview) string { return dashIfEmpty(o.Pipeline) },
+	"stage":     func(o core.FeatureOverview) string { return dashIfEmpty(o.Stage) },
+	"review":    func(o core.FeatureOverview) string { return dashIfEmpty(o.Review.Review) },
+	"tests":     func(o core.FeatureOverview) string { return strconv.Itoa(len(o.TestFiles)) },
+	"coverage":  func(o core.FeatureOverview) string { return fmt.Sprintf("%d/%d", o.Covered, o.Criteria) },
+	"scenarios": func(o core.FeatureOverview) string { return strconv.Itoa(o.Scenarios) },
+	"progress":  func(o core.FeatureOverview) string { return strconv.Itoa(o.Progress) + "%" },
+	"score": func(o core.FeatureOverview) string {
+		if o.Score == 0 {
+			return "-"
+		}
+		return strconv.Itoa(o.Score)
+	},
+	"tasks": func(o core.FeatureOverview) string {
+		return fmt.Sprintf("%d/%d/%d", o.Tasks["TODO"], o.Tasks["WIP"], o.Tasks["DONE"])
+	},
+	"last-activity": func(o core.FeatureOverview) string {
+		if o.LastActivity.IsZero() {
+			return "-"
+		}
+		return o.LastActivity.Local().Format("2006-01-02 15:04")
+	},
+}
+
+// printFeatureColumns renders `feature list --columns` as an aligned table
+// with a header row.
+func printFeatureColumns(overviews []core.FeatureOverview, cols []string) {
+	rows := [][]string{make([]string, len(cols))}
+	for i, c := range cols {
+		rows[0][i] = strings.ToUpper(c)
+	}
+	for _, o := range overviews {
+		row := make([]string, len(cols))
+		for i, c := range cols {
+			row[i] = featureColumns[c](o)
+		}
+		rows = append(rows, row)
+	}
+	widths := make([]int, len(cols))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			if i == len(row)-1 {
+				b.WriteString(cell)
+				break
+			}
+			b.WriteString(cell + strings.Repeat(" ", widths[i]-len([]rune(cell))+2))
+		}
+		fmt.Println(b.String())
+	}
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
 	}
+	return s
 }
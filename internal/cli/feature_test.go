@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -706,3 +707,298 @@ func TestRunFeature_AddAndList_RoundTrip(t *testing.T) {
 		}
 	}
 }
+
+func TestRunFeature_Show_Graph(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+
+	RunFeature([]string{"add", "my-feat", "My Feature"}, true)
+
+	var code int
+	out := captureStdout(t, func() {
+		code = RunFeature([]string{"show", "my-feat", "--graph"}, true)
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	if !strings.Contains(out, "stateDiagram-v2") || !strings.Contains(out, "class prd current") {
+		t.Errorf("expected mermaid state diagram with prd current, got:\n%s", out)
+	}
+}
+
+func TestRunFeature_AddLite_ListShowsPipeline(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+
+	if code := RunFeature([]string{"add", "bump-deps", "Bump", "deps", "--lite"}, true); code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	out := captureStdout(t, func() {
+		RunFeature([]string{"list"}, true)
+	})
+	if !strings.Contains(out, "bump-deps [planned] Bump deps pipeline=lite") {
+		t.Errorf("expected lite pipeline in list, got: %q", out)
+	}
+
+	if code := RunFeature([]string{"pipeline", "bump-deps", "bogus"}, true); code == 0 {
+		t.Error("expected non-zero exit for invalid pipeline")
+	}
+}
+
+func TestRunFeature_DeferUntil(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+	RunFeature([]string{"add", "billing", "Billing"}, true)
+
+	out := captureStdout(t, func() {
+		if code := RunFeature([]string{"defer", "billing", "waiting", "on", "vendor", "--until", "2026-03-01"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "feature.defer id=billing revisit=2026-03-01") {
+		t.Errorf("unexpected defer output: %q", out)
+	}
+
+	if code := RunFeature([]string{"defer", "billing", "x", "--until", "soon"}, true); code == 0 {
+		t.Error("expected non-zero exit for invalid date")
+	}
+	if code := RunFeature([]string{"undefer", "billing"}, true); code != 0 {
+		t.Errorf("expected exit 0 for undefer, got %d", code)
+	}
+}
+
+func TestRunFeatureListJSONWithState(t *testing.T) {
+	dir := setupTaskProject(t, "auth", "billing")
+	withDir(t, dir, func() {
+		RunTask([]string{"add", "auth", "Write", "login"}, true)
+		out := captureStdout(t, func() {
+			if code := RunFeature([]string{"list", "--json", "--with-state"}, true); code != 0 {
+				t.Fatalf("expected exit 0, got %d", code)
+			}
+		})
+		var got []map[string]any
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		if len(got) != 2 || got[0]["id"] != "auth" {
+			t.Fatalf("unexpected features: %v", got)
+		}
+		tasks, _ := got[0]["tasks"].(map[string]any)
+		if tasks["TODO"] != float64(1) {
+			t.Errorf("expected 1 TODO task for auth, got %v", got[0]["tasks"])
+		}
+		for _, key := range []string{"stage", "hashes", "scores", "review", "coverage", "tests"} {
+			if _, ok := got[0][key]; !ok {
+				t.Errorf("missing key %q in %v", key, got[0])
+			}
+		}
+	})
+}
+
+func TestRunFeature_Split(t *testing.T) {
+	dir := setupTaskProjectWithTasks(t, []string{"checkout"},
+		"tasks:\n  - id: T-1\n    feature: checkout\n    title: card\n    status: TODO\n    priority: A\n  - id: T-2\n    feature: checkout\n    title: refunds\n    status: TODO\n    priority: B\n")
+	bdd := "@feature:checkout\nFeature: Checkout\n\n  Scenario: Pay by card\n    Then charged\n\n  Scenario: Refund order\n    Then refunded\n"
+	if err := os.MkdirAll(filepath.Join(dir, ".ptsd", "bdd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "checkout.feature"), []byte(bdd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, dir)
+
+	var code int
+	out := captureStdout(t, func() {
+		code = RunFeature([]string{"split", "checkout", "refunds", "--title", "Refunds", "--scenario", "Refund order", "--task", "T-2"}, true)
+	})
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, out)
+	}
+	want := "feature.split parent=checkout child=refunds scenarios=1 seeds=0 tasks=1 mappings=0 prd=false"
+	if strings.TrimSpace(out) != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+
+	out = captureStdout(t, func() {
+		RunFeature([]string{"list", "--json"}, true)
+	})
+	if !strings.Contains(out, `"parent": "checkout"`) {
+		t.Errorf("feature list --json missing parent: %s", out)
+	}
+}
+
+func TestRunFeature_Split_NothingToMove_Exit2(t *testing.T) {
+	dir := setupTaskProject(t, "checkout")
+	chdir(t, dir)
+
+	var code int
+	captureStdout(t, func() {
+		code = RunFeature([]string{"split", "checkout", "refunds"}, true)
+	})
+	if code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+}
+
+func TestRunFeature_ParentRollup(t *testing.T) {
+	dir := setupTaskProject(t, "checkout", "payments")
+	chdir(t, dir)
+
+	captureStdout(t, func() {
+		RunFeature([]string{"add", "refunds", "Refunds", "--parent", "checkout"}, true)
+		RunFeature([]string{"parent", "payments", "checkout"}, true)
+	})
+
+	out := captureStdout(t, func() {
+		RunFeature([]string{"show", "checkout"}, true)
+	})
+	for _, want := range []string{"child: payments [planned]", "child: refunds [planned]", "rollup: status=planned implemented=0/2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("feature show missing %q:\n%s", want, out)
+		}
+	}
+
+	out = captureStdout(t, func() {
+		RunFeature([]string{"show", "refunds"}, true)
+	})
+	if !strings.Contains(out, "parent: checkout") {
+		t.Errorf("feature show missing parent:\n%s", out)
+	}
+
+	var code int
+	captureStdout(t, func() {
+		code = RunFeature([]string{"status", "checkout", "implemented"}, true)
+	})
+	if code != 1 {
+		t.Errorf("marking an epic implemented with open children: exit %d, want 1", code)
+	}
+}
+
+func TestRunFeature_Depends(t *testing.T) {
+	dir := setupTaskProject(t, "auth", "orders")
+	chdir(t, dir)
+
+	out := captureStdout(t, func() {
+		if code := RunFeature([]string{"depends", "orders", "auth"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if strings.TrimSpace(out) != "feature.depends id=orders depends_on=auth" {
+		t.Errorf("unexpected output: %q", out)
+	}
+	out = captureStdout(t, func() {
+		RunFeature([]string{"show", "orders"}, true)
+	})
+	if !strings.Contains(out, "depends_on: auth") {
+		t.Errorf("feature show missing depends_on:\n%s", out)
+	}
+
+	var code int
+	captureStdout(t, func() {
+		code = RunFeature([]string{"depends", "auth", "orders"}, true)
+	})
+	if code != 1 {
+		t.Errorf("dependency cycle: exit %d, want 1", code)
+	}
+}
+
+func TestRunFeatureListSortAndColumns(t *testing.T) {
+	dir := setupTaskProject(t, "auth", "billing")
+	withDir(t, dir, func() {
+		os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"),
+			[]byte("features:\n  - id: auth\n    title: auth\n    status: implemented\n  - id: billing\n    title: billing\n    status: planned\n"), 0644)
+
+		out := captureStdout(t, func() {
+			if code := RunFeature([]string{"list", "--sort", "progress", "--reverse", "--columns", "id,progress,tasks"}, false); code != 0 {
+				t.Fatalf("expected exit 0, got %d", code)
+			}
+		})
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID       PROGRESS  TASKS") || !strings.HasPrefix(lines[1], "auth     100%") {
+			t.Errorf("unexpected table:\n%s", out)
+		}
+
+		// Agent mode ignores --columns and keeps its field order.
+		out = captureStdout(t, func() {
+			RunFeature([]string{"list", "--sort", "progress", "--columns", "id"}, true)
+		})
+		if !strings.HasPrefix(out, "billing [planned] stage=- review=- tests=0 ac=0/0 scenarios=0 tasks=0/0/0 score=0 progress=0 activity=-\n") {
+			t.Errorf("unexpected agent output: %q", out)
+		}
+
+		if code := RunFeature([]string{"list", "--columns", "id,size"}, true); code != 2 {
+			t.Errorf("expected exit 2 for an unknown column, got %d", code)
+		}
+		if code := RunFeature([]string{"list", "--sort", "size"}, true); code != 2 {
+			t.Errorf("expected exit 2 for an unknown sort key, got %d", code)
+		}
+	})
+}
+
+func TestRunFeatureAddWarnsOnSimilarFeature(t *testing.T) {
+	dir := setupTaskProject(t)
+	withDir(t, dir, func() {
+		RunFeature([]string{"add", "auth", "User", "authentication"}, true)
+
+		var code int
+		stderr := captureStderr(t, func() {
+			captureStdout(t, func() {
+				code = RunFeature([]string{"add", "user-auth", "Authentication", "for", "users"}, true)
+			})
+		})
+		if code != 0 {
+			t.Fatalf("expected the add to succeed, got %d", code)
+		}
+		if !strings.Contains(stderr, "warn: similar-feature id=auth score=1.00 match=title") {
+			t.Errorf("expected a similar-feature warning, got %q", stderr)
+		}
+
+		stderr = captureStderr(t, func() {
+			captureStdout(t, func() {
+				RunFeature([]string{"add", "reports", "Sales", "reports"}, true)
+			})
+		})
+		if stderr != "" {
+			t.Errorf("expected no warning for a distinct feature, got %q", stderr)
+		}
+	})
+}
+
+func TestRunFeature_ShowJSON(t *testing.T) {
+	dir := setupTaskProject(t, "checkout")
+	chdir(t, dir)
+	captureStdout(t, func() {
+		RunFeature([]string{"status", "checkout", "in-progress"}, true)
+		RunTask([]string{"add", "checkout", "Write PRD"}, true)
+	})
+
+	var code int
+	out := captureStdout(t, func() {
+		code = RunFeature([]string{"show", "checkout", "--json"}, true)
+	})
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, out)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, out)
+	}
+	for _, key := range []string{"schema", "id", "title", "status", "stage", "scores", "review", "coverage", "test_files", "tasks", "task_counts", "regressions", "next", "progress"} {
+		if _, ok := doc[key]; !ok {
+			t.Errorf("feature show --json missing %q", key)
+		}
+	}
+	if doc["schema"] != "ptsd.feature.show/v1" || doc["status"] != "in-progress" {
+		t.Errorf("schema/status = %v/%v", doc["schema"], doc["status"])
+	}
+	if tasks, _ := doc["tasks"].([]any); len(tasks) != 1 {
+		t.Errorf("tasks = %v, want one", doc["tasks"])
+	}
+	next, _ := doc["next"].(map[string]any)
+	if next["type"] != "next" || next["action"] == "" {
+		t.Errorf("next = %v", doc["next"])
+	}
+}
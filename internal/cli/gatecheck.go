@@ -2,20 +2,32 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/veschin/ptsd/internal/core"
 )
 
+// RunGateCheck handles `ptsd gate-check --file <path>` and `ptsd gate-check
+// --stdin`. With --stdin it reads newline-delimited paths, or Claude Code hook
+// JSON with one or more file_path keys, and prints a verdict per path from a
+// single process; exit 2 when any path is denied.
 func RunGateCheck(args []string, agentMode bool) int {
 	filePath := ""
+	fromStdin := false
 	for i, arg := range args {
 		if arg == "--file" && i+1 < len(args) {
 			filePath = args[i+1]
 		}
+		if arg == "--stdin" {
+			fromStdin = true
+		}
 	}
-	if filePath == "" {
-		fmt.Fprintln(os.Stderr, "err:user usage: ptsd gate-check --file <path>")
+	if filePath == "" && !fromStdin {
+		fmt.Fprintln(os.Stderr, "err:user usage: ptsd gate-check --file <path> | --stdin")
 		return 2
 	}
 
@@ -24,6 +36,14 @@ func RunGateCheck(args []string, agentMode bool) int {
 		return coreError(agentMode, err)
 	}
 
+	if fromStdin {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return renderError(agentMode, "io", err.Error())
+		}
+		return gateCheckBatch(dir, gateCheckPaths(string(data)), agentMode)
+	}
+
 	result := core.GateCheck(dir, filePath)
 	if result.Allowed {
 		if agentMode {
@@ -37,3 +57,163 @@ func RunGateCheck(args []string, agentMode bool) int {
 	fmt.Fprintln(os.Stderr, result.Reason)
 	return 2
 }
+
+// gateCheckPaths reads the paths to check from gate-check --stdin input:
+// hook JSON when it starts with '{', else one path per line.
+func gateCheckPaths(input string) []string {
+	if strings.HasPrefix(strings.TrimSpace(input), "{") {
+		return extractFilePaths(input)
+	}
+	var paths []string
+	for _, line := range strings.Split(input, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths
+}
+
+type gateCheckFileJSON struct {
+	File    string `json:"file"`
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+	Feature string `json:"feature,omitempty"`
+}
+
+type gateCheckBatchJSON struct {
+	Files  []gateCheckFileJSON `json:"files"`
+	Denied int                 `json:"denied"`
+}
+
+// gateCheckBatch prints one allow/deny line per path.
+func gateCheckBatch(dir string, paths []string, agentMode bool) int {
+	out := gateCheckBatchJSON{Files: []gateCheckFileJSON{}}
+	for _, p := range paths {
+		r := core.GateCheck(dir, p)
+		out.Files = append(out.Files, gateCheckFileJSON{File: p, Allowed: r.Allowed, Reason: r.Reason, Feature: r.Feature})
+		if !r.Allowed {
+			out.Denied++
+		}
+	}
+	code := 0
+	if out.Denied > 0 {
+		code = 2
+	}
+	if jsonOutput {
+		printJSON(agentMode, "gate-check.batch", out)
+		return code
+	}
+
+	for _, f := range out.Files {
+		verdict := "allow"
+		if !f.Allowed {
+			verdict = "deny"
+		}
+		switch {
+		case agentMode && f.Reason != "":
+			fmt.Printf("%s %s reason=%q\n", verdict, f.File, f.Reason)
+		case agentMode:
+			fmt.Printf("%s %s\n", verdict, f.File)
+		case f.Reason != "":
+			fmt.Printf("%-5s  %s  (%s)\n", verdict, f.File, f.Reason)
+		default:
+			fmt.Printf("%-5s  %s\n", verdict, f.File)
+		}
+	}
+	if agentMode {
+		fmt.Printf("gate-check files=%d denied=%d\n", len(out.Files), out.Denied)
+	} else {
+		fmt.Printf("%d file(s) checked, %d denied\n", len(out.Files), out.Denied)
+	}
+	return code
+}
+
+// RunGate handles `ptsd gate exempt add <pattern> --until YYYY-MM-DD --reason
+// <text>`, `gate exempt list` and `gate exempt remove <pattern>`.
+func RunGate(args []string, agentMode bool) int {
+	const usage = "usage: gate exempt add <pattern> --until YYYY-MM-DD --reason <text> | gate exempt list | gate exempt remove <pattern>"
+	if len(args) < 1 || args[0] != "exempt" {
+		return usageError(agentMode, "gate", usage)
+	}
+	op := "list"
+	if len(args) > 1 {
+		op = args[1]
+	}
+	rest := args[min(len(args), 2):]
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ptsd")); err != nil {
+		return renderError(agentMode, "config", ".ptsd not found: run ptsd init")
+	}
+
+	switch op {
+	case "list":
+		exemptions, err := core.LoadGateExemptions(dir)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		now := time.Now()
+		for _, e := range exemptions {
+			state := "active"
+			if e.Expired(now) {
+				state = "expired"
+			}
+			if agentMode {
+				fmt.Printf("exempt: %s until=%s state=%s reason=%q\n", e.Pattern, e.Until, state, e.Reason)
+			} else {
+				fmt.Printf("%-24s until %s  %-7s  %s\n", e.Pattern, e.Until, state, e.Reason)
+			}
+		}
+		if len(exemptions) == 0 && !agentMode {
+			fmt.Println("No gate exemptions")
+		}
+		return 0
+
+	case "add":
+		pattern, until, reason := "", "", ""
+		for i := 0; i < len(rest); i++ {
+			switch rest[i] {
+			case "--until", "--reason":
+				if i+1 >= len(rest) {
+					return usageError(agentMode, "gate exempt", rest[i]+" requires a value")
+				}
+				if rest[i] == "--until" {
+					until = rest[i+1]
+				} else {
+					reason = rest[i+1]
+				}
+				i++
+			default:
+				if pattern != "" || strings.HasPrefix(rest[i], "--") {
+					return usageError(agentMode, "gate exempt", fmt.Sprintf("unexpected argument %q; %s", rest[i], usage))
+				}
+				pattern = rest[i]
+			}
+		}
+		if pattern == "" || until == "" {
+			return usageError(agentMode, "gate exempt", usage)
+		}
+		e, err := core.AddGateExemption(dir, pattern, until, reason, time.Now())
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		fmt.Printf("ok gate exempt add %s until=%s\n", e.Pattern, e.Until)
+		return 0
+
+	case "remove":
+		if len(rest) != 1 {
+			return usageError(agentMode, "gate exempt", "remove requires a pattern")
+		}
+		if err := core.RemoveGateExemption(dir, rest[0]); err != nil {
+			return coreError(agentMode, err)
+		}
+		fmt.Printf("ok gate exempt remove %s\n", rest[0])
+		return 0
+
+	default:
+		return usageError(agentMode, "gate exempt", fmt.Sprintf("unknown operation %q: use add|list|remove", op))
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunGate_ExemptAddListRemove(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+
+	until := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	var code int
+	out := captureStdout(t, func() {
+		code = RunGate([]string{"exempt", "add", "scripts/**", "--until", until, "--reason", "one-off migration"}, true)
+	})
+	if code != 0 || !strings.Contains(out, "ok gate exempt add scripts/** until="+until) {
+		t.Fatalf("add: exit %d, output %q", code, out)
+	}
+
+	out = captureStdout(t, func() { code = RunGate([]string{"exempt", "list"}, true) })
+	if code != 0 || !strings.Contains(out, `exempt: scripts/** until=`+until+` state=active reason="one-off migration"`) {
+		t.Errorf("list: exit %d, output %q", code, out)
+	}
+
+	if code := RunGate([]string{"exempt", "add", "tools/**", "--until", until}, true); code != 2 {
+		t.Errorf("missing --reason: expected exit 2, got %d", code)
+	}
+	if code := RunGate([]string{"exempt", "add", "tools/**", "--reason", "x"}, true); code != 2 {
+		t.Errorf("missing --until: expected exit 2, got %d", code)
+	}
+
+	out = captureStdout(t, func() { code = RunGate([]string{"exempt", "remove", "scripts/**"}, true) })
+	if code != 0 || !strings.Contains(out, "ok gate exempt remove scripts/**") {
+		t.Errorf("remove: exit %d, output %q", code, out)
+	}
+	if code := RunGate([]string{"exempt", "remove", "scripts/**"}, true); code != 2 {
+		t.Errorf("remove missing: expected exit 2, got %d", code)
+	}
+}
+
+// withStdin runs fn with os.Stdin reading input.
+func withStdin(t *testing.T, input string, fn func()) {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(input)
+	f.Seek(0, 0)
+	old := os.Stdin
+	os.Stdin = f
+	defer func() { os.Stdin = old; f.Close() }()
+	fn()
+}
+
+func TestRunGateCheck_Stdin(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte("features:\n  - id: auth\n    title: Auth\n    status: in-progress\n"), 0644)
+
+	var code int
+	out := captureStdout(t, func() {
+		withStdin(t, "README.md\n\ninternal/auth_test.go\n", func() { code = RunGateCheck([]string{"--stdin"}, true) })
+	})
+	if code != 2 {
+		t.Errorf("expected exit 2 with a denied path, got %d", code)
+	}
+	if !strings.Contains(out, "allow README.md\n") || !strings.Contains(out, "deny internal/auth_test.go reason=") || !strings.Contains(out, "gate-check files=2 denied=1") {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	hookJSON := `{"tool_name":"MultiEdit","tool_input":{"edits":[{"file_path":"README.md"},{"file_path":"docs/notes.md"},{"file_path":"README.md"}]}}`
+	out = captureStdout(t, func() {
+		withStdin(t, hookJSON, func() { code = RunGateCheck([]string{"--stdin"}, true) })
+	})
+	if code != 0 || !strings.Contains(out, "gate-check files=2 denied=0") {
+		t.Errorf("hook JSON: exit %d, output %q", code, out)
+	}
+}
@@ -7,39 +7,173 @@ func RunHelp(args []string, agentMode bool) int {
 
 Project setup:
   init [--name <name>]     Initialize .ptsd/, .claude/, git hooks
+                           (re-run: regenerates unmodified files; --force: all)
+                           (--profile minimal|standard|full, --minimal: .ptsd/ only)
   adopt                    Bootstrap ptsd onto existing project
+                           (re-run: imports only what is new; --map FILE: explicit
+                           feature IDs for .feature and test files; --dry-run)
+                           (--map-tests: map tests by file name, infer stages,
+                           match scenarios to tests)
+  deinit [--yes]           Remove ptsd (backs up .ptsd/ to .git/ unless --no-backup;
+                           restores git hooks ptsd replaced)
 
 Features:
-  feature add <id> <title> Register a new feature
+  feature add <id> <title> Register a new feature (--lite: skip seed/BDD)
+                           (warns when an existing feature looks like a duplicate)
   feature list             All features and their status
+                           (--with-state: stage, review, coverage, tasks; --json)
+                           (--sort status|stage|coverage|last-activity|progress,
+                           --reverse, --columns id,stage,score,tests,...)
   feature status <id> <s>  Set status (planned/in-progress/done)
-  feature show <id>        Show feature details
+  feature show <id>        Show feature details (--graph: Mermaid pipeline;
+                           --json: full ptsd.feature.show/v1 document)
+                           Epics also list children and the rollup
   feature remove <id>      Remove a feature
+  feature defer <id> <reason> [--until YYYY-MM-DD]
+                           Park a feature; context/status flag it once due
+  feature undefer <id>     Return a deferred feature to planned
+  feature pipeline <id> <full|lite>
+                           Switch pipeline mode (lite still needs tests+review)
+  feature parent <id> <parent-id|none>
+                           Nest a feature under an epic (feature add --parent too)
+  feature depends <id> <dep-id>...|none
+                           Hold <id> at BDD until its dependencies are implemented
+  feature split <id> <child-id> [--title T] [--scenario S]... [--seed F]... [--task T-n]...
+                           Move scenarios, seeds and tasks into a child feature
 
 Pipeline:
   seed add <feature>       Initialize seed data
-  bdd add <feature>        Initialize BDD scenarios
-  prd check                Validate PRD anchors
-  test map <f> <file>      Map test file to feature
-  test run <feature>       Run feature's tests
+  seed snapshot <f> <file> -- <cmd>
+                           Capture command output (or --request <name>) as a checksummed seed
+  seed emit <f> --lang go|ts|py
+                           Generate a seed accessor module for tests (--out <path>)
+  seed validate [feature]  Check seed files exist, parse, and match their schema
+  seed scaffold <f> --from <go-file|ts-file>
+                           Write skeleton seed JSON from Go structs or TS interfaces
+  bdd add <feature>        Initialize BDD scenarios (Given tables from seed data)
+  bdd ids                  Tag untagged scenarios with stable @id:<hash>
+  bdd diff <feature>       Scenarios added/removed/modified since the last bdd review (--json)
+  prd check                Validate PRD anchors across the PRD files (orphans: --register | --remove-anchor [id...])
+  prd show <feature>       One feature's PRD section (--raw: markdown only)
+  prd index                Which PRD file owns which feature anchor (--json)
+  trace <feature>          PRD acceptance criteria vs BDD scenarios (@ac:<id>)
+  trace <feature> --commits  Commits attributed by [scope] or Ptsd-Feature trailer (--json)
+  prd import <file>        Propose features from a spec's headings (--accept to register)
+  test map <f> <file>      Map test file to feature (--scenario <id> [--func <test>])
+  test coverage [feature]  Scenarios without a scenario-level test mapping (--json)
+  test match <feature>     Map uncovered scenarios to similarly named tests (--min 0.5, --dry-run, --json)
+  test unmapped            Test files found by adopt that no feature maps (--json)
+  test run <feature> [--fail-fast]
+                           Run feature's tests (--fail-fast: stop at first failure)
+  test run --parallel <n>  One runner per feature, n at a time; per-feature results
   review <f> <stage> <n>   Record review (score 0-10)
-  validate                 Check all pipeline gates
+  review ... --issue "[blocker|major|minor:] text" [--issues-file f.json]
+                           Record the issues found; ptsd context lists them
+  review ... --reviewer <name>
+                           Score as a named reviewer (review.require, review.strategy)
+  review <f> --scores prd=8,seed=9
+                           Record several stage scores at once, combined verdict
+  review notes [feature]   List review records stored as git notes
+  review history <f> [--stage s]
+                           Every recorded review and each stage's score trajectory (--json)
+  stage set <f> <stage> --why <reason> [--force]
+                           Set a feature's stage by hand once its guards hold
+  review serve [--port 8787] [--host 127.0.0.1]
+                           Accept POSTed reviews over HTTP ($PTSD_REVIEW_TOKEN)
+  validate                 Check all pipeline gates (--no-cache: skip cached pass)
+  validate --watch [--interval 1s]
+                           Re-validate on .ptsd/, BDD and test file changes
+  validate --baseline      Fail only on errors not in .ptsd/validate-baseline.yaml
+  validate --update-baseline
+                           Accept current errors into the baseline
+  gate-check --file <path> | --stdin
+                           Would the gate allow this write? --stdin checks
+                           newline-separated paths or hook JSON in one run
+  gate exempt add <glob> --until YYYY-MM-DD --reason <r>
+                           Let gate-check allow matching paths until a date
+  gate exempt list|remove <glob>
+                           Show or drop exemptions (validate reports expired ones)
+  validate --baseline-report
+                           Baseline size over time vs. now (burn-down)
+  lint                     Static checks: config, PRD, BDD, seeds, fsck
+  doctor                   Environment and wiring checks (git, hooks, runner, .claude) with fixes
 
 Context & tracking:
   context                  Show pipeline state (next/blocked/done)
-  status                   Project overview
-  task next                Next task to work on
+  context --budget <n>     Fit context in ~n tokens: current task, regressions, blocked
+                           gates, then the summary; the rest becomes one elided: line
+  context --for-task <id>  Task-scoped: comments, PRD section, unmet gates, scenarios,
+                           last test failures, likely files
+  status                   Project overview, ETAs and milestones
+  status --format dashboard  Features × stages matrix: tests, review scores, tasks
+  status --tui [--interval 1s]  Live full-screen dashboard, redrawn on state changes
+  task next [--limit N]    Next task(s) to work on (tasks.scheduling: priority|fair)
   task add <f> <title>     Add a task
+  task claim <id> [--ttl 30m] [--owner NAME]
+                           Lease a task so parallel agents skip it ($PTSD_AGENT)
+  task release <id>        Drop a task's lease
+  task comment <id> <text> [--author NAME]
+                           Append a timestamped note to the task's discussion log
+  task show <id>           Task with its comments (--json)
   task done <id>           Mark task done
+  task graph [--format f]  Task/feature graph (dot|mermaid)
+  task import --from markdown <file>  Import "- [ ]" checklist items as tasks
+  events tail [-n N] [--feature F] [--type T,...] [--follow]
+                           Recent pipeline events; --follow streams new ones
+  report weekly [--days N] Markdown digest of recent activity
+  report durations         Time spent per stage, stuck features flagged
+  report trace [f...]      Criteria → scenarios → tests → commits (Markdown, --json)
+
+Workspaces:
+  all <status|validate|test>  Run across every .ptsd workspace below cwd
+  remote push|pull         Sync state/tasks/events with remote.url (ETag-checked)
+
+Safety net:
+  snapshot [name]          Archive .ptsd/ to .ptsd/snapshots/<name>.tar.gz
+  snapshot list            List snapshots
+  restore <name>           Replace .ptsd/ contents with a snapshot
+  history                  States recorded before each command that changed .ptsd data
+  undo [n]                 Restore the data files from before the last n commands
+                           (undo again to redo)
+  migrate [--dry-run]      Rewrite ptsd.yaml/state.yaml/tasks.yaml to the current schema (snapshots first)
 
 Other:
   config show              Show config
+  config get|set|unset <key> [value]
+                           Read or change one key of ptsd.yaml (validated)
+  config keys              Keys accepted by get/set/unset
+  config scopes|types [list|add <v>|remove <v>]
+                           Manage commit scopes/types (hooks.scopes/types)
+  config ignore [list|add [p...]|remove <p>]
+                           Manage ptsd's block in .gitignore (add: defaults)
+  config detect-runner [--yes]
+                           Re-detect the test runner, update testing.runner/patterns
+  verify-log               Check signed event chain and state (audit.sign)
+  audit agent-compliance [--days N] [--gap 30m]
+                           Score agent sessions: context, validate, tasks
+  hooks log [--tail N]     Recent hook invocations and verdicts
+  verify-hooks [--schema N]
+                           Check this binary can serve hooks generated for
+                           schema N (run by every generated hook first)
+  daemon [stop|status]     Serve hooks/context over .ptsd/daemon.sock
   skills                   List pipeline skills
+  templates list           Scaffolding templates, their variables and overrides
+  templates check          Validate template overrides (parse errors,
+                           undefined variables) before init uses them
   issues                   Common issues registry
+  <name> [args]            Run ptsd-<name> from PATH (plugin; context JSON on stdin)
+  completion bash|zsh|fish Shell completion script; completes feature IDs, task IDs,
+                           stages and statuses from the project's files
   help                     This message
   version                  Show version
 
 Flags:
-  --agent                  Machine-readable output (all commands)`)
+  --agent                  Machine-readable output (all commands)
+  --json                   Before the command: one JSON envelope on stdout,
+                           {"schema":"ptsd.<kind>/v1","exit_code","data","error"}
+  --wait-lock D            How long commands that change .ptsd wait for
+                           .ptsd/.lock held by another ptsd (default 10s; 0: fail)
+  --progress jsonl         Stream progress events to stderr (adopt, test run)
+  --max-depth N            Limit discovery walks to N directory levels (adopt, validate)`)
 	return 0
 }
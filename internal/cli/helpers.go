@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/veschin/ptsd/internal/core"
 	"github.com/veschin/ptsd/internal/render"
 )
 
@@ -14,6 +17,10 @@ func newRenderer(agentMode bool) render.Renderer {
 }
 
 func renderError(agentMode bool, category string, message string) int {
+	if jsonOutput {
+		recordJSONError(category, message)
+		return errCategoryCode(category)
+	}
 	r := newRenderer(agentMode)
 	fmt.Fprintln(os.Stderr, r.RenderError(category, message))
 	return errCategoryCode(category)
@@ -35,6 +42,21 @@ func coreError(agentMode bool, err error) int {
 	return renderError(agentMode, category, msg)
 }
 
+// printJSON writes v as indented JSON to stdout. Under global --json it
+// becomes the data of the command's envelope, with schema ptsd.<kind>/v1.
+func printJSON(agentMode bool, kind string, v any) int {
+	if jsonOutput {
+		jsonKind, jsonPayload = kind, v
+		return 0
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+	fmt.Println(string(data))
+	return 0
+}
+
 func usageError(agentMode bool, cmd string, message string) int {
 	return renderError(agentMode, "user", fmt.Sprintf("%s: %s", cmd, message))
 }
@@ -55,3 +77,51 @@ func errCategoryCode(category string) int {
 		return 1
 	}
 }
+
+// parseProgressFlag strips `--progress <format>` from args. The only format is
+// jsonl, which streams core.ProgressEvent lines to stderr.
+func parseProgressFlag(args []string) (rest []string, progress core.ProgressFunc, err error) {
+	for i := 0; i < len(args); i++ {
+		if args[i] != "--progress" {
+			rest = append(rest, args[i])
+			continue
+		}
+		if i+1 >= len(args) || args[i+1] != "jsonl" {
+			return nil, nil, fmt.Errorf("err:user --progress supports only jsonl")
+		}
+		progress = core.JSONLProgress(os.Stderr)
+		i++
+	}
+	return rest, progress, nil
+}
+
+// parseMaxDepthFlag strips `--max-depth <n>` from args. A zero depth means the
+// flag was not given.
+func parseMaxDepthFlag(args []string) (rest []string, depth int, err error) {
+	for i := 0; i < len(args); i++ {
+		if args[i] != "--max-depth" {
+			rest = append(rest, args[i])
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, 0, fmt.Errorf("err:user --max-depth requires a value")
+		}
+		n, convErr := strconv.Atoi(args[i+1])
+		if convErr != nil || n < 1 {
+			return nil, 0, fmt.Errorf("err:user --max-depth must be a positive integer")
+		}
+		depth = n
+		i++
+	}
+	return rest, depth, nil
+}
+
+// useColor reports whether to emit ANSI colors on f: only for a terminal,
+// and never when NO_COLOR is set.
+func useColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
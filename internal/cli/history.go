@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunHistory handles `ptsd history`: the recorded states `ptsd undo` can
+// return to, newest first.
+func RunHistory(args []string, agentMode bool) int {
+	if len(args) > 0 {
+		return usageError(agentMode, "history", "unexpected argument: "+args[0])
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	entries, err := core.ListHistory(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("no history")
+		return 0
+	}
+	for i, e := range entries {
+		if agentMode {
+			fmt.Printf("%d at=%s command=%q\n", i+1, e.Created.Format("2006-01-02T15:04:05Z"), e.Command)
+		} else {
+			fmt.Printf("%3d  %s  before: ptsd %s\n", i+1, e.Created.Local().Format("2006-01-02 15:04:05"), e.Command)
+		}
+	}
+	return 0
+}
+
+// RunUndo handles `ptsd undo [n]`: restores the .ptsd data files as they
+// were before the last n recorded commands.
+func RunUndo(args []string, agentMode bool) int {
+	n := 1
+	switch len(args) {
+	case 0:
+	case 1:
+		v, err := strconv.Atoi(args[0])
+		if err != nil || v < 1 {
+			return usageError(agentMode, "undo", fmt.Sprintf("invalid count %q: use a positive number", args[0]))
+		}
+		n = v
+	default:
+		return usageError(agentMode, "undo", "usage: ptsd undo [n]")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	undone, err := core.Undo(cwd, n)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	for _, e := range undone {
+		if agentMode {
+			fmt.Printf("undone command=%q\n", e.Command)
+		} else {
+			fmt.Printf("undone: ptsd %s\n", e.Command)
+		}
+	}
+	fmt.Println("run ptsd undo again to redo")
+	return 0
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunUndoAfterLockedCommand(t *testing.T) {
+	dir := setupTaskProjectWithTasks(t, []string{"auth"}, "tasks:\n  - id: T-1\n    feature: auth\n    title: Login\n    status: TODO\n")
+
+	var out string
+	withDir(t, dir, func() {
+		args := []string{"update", "T-1", "DONE"}
+		captureStdout(t, func() {
+			if code := RunLocked("task", args, true, func() int { return RunTask(args, true) }); code != 0 {
+				t.Fatalf("task update: exit %d", code)
+			}
+		})
+
+		out = captureStdout(t, func() { RunHistory(nil, true) })
+		if !strings.Contains(out, `1 at=`) || !strings.Contains(out, `command="task update T-1 DONE"`) {
+			t.Errorf("unexpected history:\n%s", out)
+		}
+
+		out = captureStdout(t, func() {
+			if code := RunUndo(nil, true); code != 0 {
+				t.Errorf("undo: exit %d", code)
+			}
+		})
+		if !strings.Contains(out, `undone command="task update T-1 DONE"`) {
+			t.Errorf("unexpected undo output:\n%s", out)
+		}
+
+		out = captureStdout(t, func() { RunTask([]string{"list"}, true) })
+		if !strings.Contains(out, "[TODO]") {
+			t.Errorf("expected T-1 back to TODO, got:\n%s", out)
+		}
+	})
+
+	if code := RunUndo([]string{"x"}, true); code != 2 {
+		t.Errorf("bad count: exit %d, want 2", code)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/veschin/ptsd/internal/core"
 )
@@ -17,12 +19,13 @@ import (
 //	validate-commit — validate commit message format from file
 //	pre-tool-use    — gate-check for Claude Code PreToolUse hook
 //	post-tool-use   — auto-track for Claude Code PostToolUse hook
+//	log [--tail N]  — show recent hook invocations from .ptsd/hooks.log
 func RunHooks(args []string, agentMode bool) int {
 	if len(args) == 0 {
 		if agentMode {
-			fmt.Fprintf(os.Stderr, "err:user hooks requires a subcommand: install|validate-commit|pre-tool-use|post-tool-use\n")
+			fmt.Fprintf(os.Stderr, "err:user hooks requires a subcommand: install|validate-commit|prepare-commit-msg|pre-push|pre-tool-use|post-tool-use|log\n")
 		} else {
-			fmt.Fprintln(os.Stderr, "usage: ptsd hooks <install|validate-commit|pre-tool-use|post-tool-use>")
+			fmt.Fprintln(os.Stderr, "usage: ptsd hooks <install|validate-commit|prepare-commit-msg|pre-push|pre-tool-use|post-tool-use|log>")
 		}
 		return 2
 	}
@@ -35,10 +38,16 @@ func RunHooks(args []string, agentMode bool) int {
 		return runHooksInstall(agentMode)
 	case "validate-commit":
 		return runValidateCommit(subargs, agentMode)
+	case "prepare-commit-msg":
+		return runPrepareCommitMsg(subargs, agentMode)
+	case "pre-push":
+		return runPrePush(agentMode)
 	case "pre-tool-use":
 		return runPreToolUse(agentMode)
 	case "post-tool-use":
 		return runPostToolUse(agentMode)
+	case "log":
+		return runHooksLog(subargs, agentMode)
 	default:
 		if agentMode {
 			fmt.Fprintf(os.Stderr, "err:user unknown hooks subcommand: %s\n", subcmd)
@@ -64,6 +73,14 @@ func runHooksInstall(agentMode bool) int {
 		return coreError(agentMode, err)
 	}
 
+	if err := core.GeneratePrepareCommitMsgHook(cwd); err != nil {
+		return coreError(agentMode, err)
+	}
+
+	if err := core.GeneratePrePushHook(cwd); err != nil {
+		return coreError(agentMode, err)
+	}
+
 	if agentMode {
 		fmt.Println("ok hooks installed")
 	} else {
@@ -73,6 +90,63 @@ func runHooksInstall(agentMode bool) int {
 	return 0
 }
 
+// runPrePush is the git pre-push hook: it reads ref updates from stdin and
+// refuses the push if any commit's tree never passed `ptsd validate`.
+func runPrePush(agentMode bool) int {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	bad, err := core.CheckPushValidated(cwd, string(input))
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if len(bad) == 0 {
+		return 0
+	}
+	for _, c := range bad {
+		fmt.Fprintf(os.Stderr, "err:git %.12s never passed ptsd validate: %s\n", c.Hash, c.Subject)
+	}
+	fmt.Fprintln(os.Stderr, "err:git push refused — commits were made without validation (--no-verify?). Fix, run ptsd validate, and recommit.")
+	return 1
+}
+
+// runPrepareCommitMsg is the git prepare-commit-msg hook: it appends
+// Ptsd-Feature and Ptsd-Task trailers for the staged files to the message.
+// Git calls it as `prepare-commit-msg <msg-file> [<source> [<sha>]]`.
+func runPrepareCommitMsg(args []string, agentMode bool) int {
+	if len(args) == 0 {
+		return usageError(agentMode, "hooks", "usage: ptsd hooks prepare-commit-msg <msg-file> [<source>]")
+	}
+	source := ""
+	if len(args) > 1 {
+		source = args[1]
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	start := time.Now()
+	features, tasks, err := core.AddCommitTrailers(cwd, args[0], source)
+	if err != nil {
+		core.AppendHookLog(cwd, core.HookLogEntry{Hook: "prepare-commit-msg", Verdict: "error", Duration: time.Since(start), Reason: err.Error()})
+		return coreError(agentMode, err)
+	}
+	core.AppendHookLog(cwd, core.HookLogEntry{Hook: "prepare-commit-msg", Verdict: "ok", Duration: time.Since(start)})
+
+	if agentMode && (len(features) > 0 || len(tasks) > 0) {
+		fmt.Printf("trailers: features=%s tasks=%s\n", strings.Join(features, ","), strings.Join(tasks, ","))
+	}
+	return 0
+}
+
 func runValidateCommit(args []string, agentMode bool) int {
 	msgFile := ""
 	for i, arg := range args {
@@ -90,9 +164,12 @@ func runValidateCommit(args []string, agentMode bool) int {
 		return coreError(agentMode, err)
 	}
 
+	start := time.Now()
 	if err := core.ValidateCommitFromFile(cwd, msgFile); err != nil {
+		core.AppendHookLog(cwd, core.HookLogEntry{Hook: "validate-commit", Verdict: "block", Duration: time.Since(start), Reason: err.Error()})
 		return coreError(agentMode, err)
 	}
+	core.AppendHookLog(cwd, core.HookLogEntry{Hook: "validate-commit", Verdict: "ok", Duration: time.Since(start)})
 
 	if agentMode {
 		fmt.Println("ok")
@@ -100,11 +177,48 @@ func runValidateCommit(args []string, agentMode bool) int {
 	return 0
 }
 
-// runPreToolUse reads Claude Code hook JSON from stdin, extracts file_path, runs gate-check.
-// Exit 0 = allow, exit 2 = block.
+// runHooksLog prints the last N hooks.log lines (default 20).
+func runHooksLog(args []string, agentMode bool) int {
+	n := 20
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--tail" {
+			if i+1 >= len(args) {
+				return usageError(agentMode, "hooks log", "--tail requires a number")
+			}
+			v, err := strconv.Atoi(args[i+1])
+			if err != nil || v < 1 {
+				return usageError(agentMode, "hooks log", fmt.Sprintf("invalid --tail value %q", args[i+1]))
+			}
+			n = v
+			i++
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	lines, err := core.TailHookLog(cwd, n)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	return 0
+}
+
+// runPreToolUse reads Claude Code hook JSON from stdin, extracts every file_path, runs
+// gate-check on each. Exit 0 = allow, exit 2 = block (any path blocked). Delegates to
+// `ptsd daemon` when one is running.
 func runPreToolUse(agentMode bool) int {
-	filePath := extractFilePathFromStdin()
-	if filePath == "" {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return 0
+	}
+	paths := extractFilePaths(string(data))
+	if len(paths) == 0 {
 		return 0 // No file_path → not a file write → allow
 	}
 
@@ -113,12 +227,43 @@ func runPreToolUse(agentMode bool) int {
 		return 0
 	}
 
+	code := 0
+	for _, filePath := range paths {
+		c := 0
+		if resp, ok := core.DaemonCall(cwd, core.DaemonRequest{Cmd: "gate-check", File: filePath, Agent: agentMode}); ok {
+			c = replayDaemonResponse(resp)
+		} else {
+			c = gateCheckHook(cwd, filePath, os.Stderr)
+		}
+		code = max(code, c)
+	}
+	return code
+}
+
+// gateCheckHook runs gate-check for filePath, logs the verdict, and writes the
+// block reason to w.
+func gateCheckHook(cwd, filePath string, w io.Writer) int {
+	start := time.Now()
 	result := core.GateCheck(cwd, filePath)
+	entry := core.HookLogEntry{Hook: "pre-tool-use", File: filePath, Verdict: "allow", Duration: time.Since(start)}
 	if result.Allowed {
+		entry.Reason = result.Reason // set when a gate exemption applied
+		core.AppendHookLog(cwd, entry)
 		return 0
 	}
 
-	fmt.Fprintln(os.Stderr, result.Reason)
+	entry.Verdict = "block"
+	entry.Reason = result.Reason
+	if core.GatesShadowMode(cwd) {
+		// Shadow mode: record the would-be block, let the write through.
+		entry.Verdict = "shadow"
+		core.AppendHookLog(cwd, entry)
+		_ = core.AppendEvent(cwd, core.Event{Type: core.EventGate, Feature: result.Feature, Detail: core.ShadowPrefix + filePath + ": " + result.Reason})
+		return 0
+	}
+	core.AppendHookLog(cwd, entry)
+	_ = core.AppendEvent(cwd, core.Event{Type: core.EventGate, Feature: result.Feature, Detail: filePath + ": " + result.Reason})
+	fmt.Fprintln(w, result.Reason)
 	return 2
 }
 
@@ -134,14 +279,47 @@ func runPostToolUse(agentMode bool) int {
 		return 0
 	}
 
-	result, err := core.AutoTrack(cwd, filePath)
+	if resp, ok := core.DaemonCall(cwd, core.DaemonRequest{Cmd: "auto-track", File: filePath, Agent: agentMode}); ok {
+		return replayDaemonResponse(resp)
+	}
+	return autoTrackHook(cwd, filePath, os.Stdout)
+}
+
+// autoTrackHook runs (debounced) auto-track for filePath, logs the result, and
+// writes tracked lines to w. Never blocks: always returns 0.
+func autoTrackHook(cwd, filePath string, w io.Writer) int {
+	var window time.Duration
+	if cfg, err := core.LoadConfig(cwd); err == nil {
+		window = time.Duration(cfg.Hooks.AutoTrackDebounce) * time.Second
+	}
+
+	start := time.Now()
+	// Hooks are not run under the command lock (the daemon serves them too),
+	// so the state write takes it here.
+	unlock, err := core.LockProject(cwd)
+	var results []*core.AutoTrackResult
+	if err == nil {
+		results, err = core.AutoTrackDebounced(cwd, filePath, window)
+		unlock()
+	}
+	entry := core.HookLogEntry{Hook: "post-tool-use", File: filePath, Verdict: "skip", Duration: time.Since(start)}
 	if err != nil {
+		entry.Verdict = "error"
+		entry.Reason = err.Error()
+		core.AppendHookLog(cwd, entry)
 		return 0 // Don't block on tracking errors
 	}
 
-	if result != nil && result.Updated {
-		fmt.Printf("tracked: %s stage=%s tests=%s\n", result.Feature, result.Stage, result.Tests)
+	var tracked []string
+	for _, result := range results {
+		tracked = append(tracked, fmt.Sprintf("%s stage=%s tests=%s", result.Feature, result.Stage, result.Tests))
+		fmt.Fprintf(w, "tracked: %s stage=%s tests=%s\n", result.Feature, result.Stage, result.Tests)
 	}
+	if len(tracked) > 0 {
+		entry.Verdict = "tracked"
+		entry.Reason = strings.Join(tracked, "; ")
+	}
+	core.AppendHookLog(cwd, entry)
 
 	return 0
 }
@@ -159,16 +337,24 @@ func extractFilePathFromReader(r io.Reader) string {
 	if err != nil {
 		return ""
 	}
+	if paths := extractFilePaths(string(data)); len(paths) > 0 {
+		return paths[0]
+	}
+	return ""
+}
 
-	input := string(data)
+// extractFilePaths returns every "file_path" key's value in input, in order
+// and without duplicates, so hook JSON carrying several edits is checked
+// path by path.
+func extractFilePaths(input string) []string {
 	key := `"file_path"`
-
-	// Search for all occurrences and pick the one that looks like a JSON key
+	var paths []string
+	seen := make(map[string]bool)
 	offset := 0
 	for {
 		idx := strings.Index(input[offset:], key)
 		if idx == -1 {
-			return ""
+			return paths
 		}
 		pos := offset + idx
 
@@ -186,7 +372,10 @@ func extractFilePathFromReader(r io.Reader) string {
 		}
 
 		if isKey {
-			return extractJSONStringValue(input[pos+len(key):])
+			if p := extractJSONStringValue(input[pos+len(key):]); p != "" && !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
 		}
 
 		offset = pos + len(key)
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -421,3 +422,120 @@ func TestHooks_TaskScopeSkipsPipelineValidation(t *testing.T) {
 		t.Errorf("expected [TASK] scope to pass without pipeline validation, got: %v", err)
 	}
 }
+
+func TestRunHooks_Log_Tail(t *testing.T) {
+	dir := setupHooksProject(t)
+	chdirTo(t, dir)
+
+	for _, v := range []string{"allow", "block", "tracked"} {
+		core.AppendHookLog(dir, core.HookLogEntry{Hook: "pre-tool-use", Verdict: v})
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		code = RunHooks([]string{"log", "--tail", "1"}, true)
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	if strings.Count(out, "\n") != 1 || !strings.Contains(out, "verdict=tracked") {
+		t.Errorf("expected only last entry, got %q", out)
+	}
+}
+
+func TestRunHooks_Log_InvalidTail(t *testing.T) {
+	dir := setupHooksProject(t)
+	chdirTo(t, dir)
+
+	if code := RunHooks([]string{"log", "--tail", "x"}, true); code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+}
+
+func TestGateCheckHook_ShadowMode(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdirTo(t, dir)
+	cfg, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"))
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), append(cfg, "\ngates:\n  mode: shadow\n"...), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte("features:\n  - id: auth\n    title: Auth\n    status: in-progress\n"), 0644)
+
+	var stderr strings.Builder
+	if code := gateCheckHook(dir, "internal/auth_test.go", &stderr); code != 0 {
+		t.Fatalf("shadow mode must not block, got exit %d", code)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("shadow mode printed a block message: %q", stderr.String())
+	}
+	gateCheckHook(dir, "internal/auth_test.go", &stderr)
+
+	lines, _ := core.TailHookLog(dir, 1)
+	if len(lines) != 1 || !strings.Contains(lines[0], "verdict=shadow") {
+		t.Errorf("hooks.log = %v, want a shadow verdict", lines)
+	}
+
+	var buf strings.Builder
+	result, err := core.BuildContext(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeContext(&buf, result, 0)
+	for _, want := range []string{
+		"shadow: gates.mode=shadow would-block=2\n",
+		"shadow: auth would-block=2 last=\"no BDD scenarios for auth",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("context missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRunHooks_PrepareCommitMsgAndTraceCommits(t *testing.T) {
+	dir := t.TempDir()
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	os.MkdirAll(ptsdDir, 0755)
+	os.WriteFile(filepath.Join(ptsdDir, "features.yaml"), []byte("features:\n  - id: auth\n    status: in-progress\n"), 0644)
+	os.WriteFile(filepath.Join(ptsdDir, "tasks.yaml"), []byte("tasks:\n  - id: T-1\n    feature: auth\n    title: login\n    status: WIP\n    priority: A\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "auth.go"), []byte("package x\n"), 0644)
+	for k, v := range map[string]string{
+		"GIT_AUTHOR_NAME": "test", "GIT_AUTHOR_EMAIL": "test@test.com",
+		"GIT_COMMITTER_NAME": "test", "GIT_COMMITTER_EMAIL": "test@test.com",
+	} {
+		t.Setenv(k, v)
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("add", "auth.go")
+	chdirTo(t, dir)
+
+	msgFile := filepath.Join(dir, ".git", "COMMIT_EDITMSG")
+	os.WriteFile(msgFile, []byte("[IMPL] feat: login\n"), 0644)
+	out := captureStdout(t, func() {
+		if code := RunHooks([]string{"prepare-commit-msg", msgFile, "message"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if out != "trailers: features=auth tasks=T-1\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+	git("commit", "-q", "-F", msgFile)
+
+	out = captureStdout(t, func() {
+		if code := RunTrace([]string{"auth", "--commits"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, " source=trailer tasks=T-1 subject=\"[IMPL] feat: login\"\n") || !strings.HasSuffix(out, "trace auth commits=1\n") {
+		t.Errorf("unexpected trace output:\n%s", out)
+	}
+	if code := RunTrace([]string{"auth", "--files"}, true); code != 2 {
+		t.Errorf("expected exit 2 for an unknown flag, got %d", code)
+	}
+}
@@ -3,12 +3,14 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/veschin/ptsd/internal/core"
 )
 
-// RunInit handles `ptsd init [name]`.
+// RunInit handles `ptsd init [name] [--force] [--profile minimal|standard|full]`.
+// --minimal is shorthand for --profile minimal.
 func RunInit(args []string, agentMode bool) int {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -16,43 +18,90 @@ func RunInit(args []string, agentMode bool) int {
 	}
 
 	name := ""
-	for i, arg := range args {
-		if arg == "--name" && i+1 < len(args) {
+	profile := ""
+	force := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--force":
+			force = true
+		case arg == "--minimal":
+			profile = core.ProfileMinimal
+		case arg == "--profile":
+			if i+1 >= len(args) {
+				return renderError(agentMode, "user", "usage: ptsd init [name] [--profile "+strings.Join(core.InitProfiles, "|")+"]")
+			}
+			profile = args[i+1]
+			i++
+		case arg == "--name" && i+1 < len(args):
 			name = args[i+1]
-			break
-		}
-		if !strings.HasPrefix(arg, "-") && name == "" {
+			i++
+		case !strings.HasPrefix(arg, "-") && name == "":
 			name = arg
 		}
 	}
 
-	result, err := core.InitProject(cwd, name)
-	if err != nil {
-		return coreError(agentMode, err)
+	var result *core.InitResult
+	if _, statErr := os.Stat(filepath.Join(cwd, ".ptsd")); force && statErr == nil {
+		// --force: regenerate every generated file, discarding local edits.
+		skipped, err := core.ReInitProjectWithProfile(cwd, true, profile)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		result = &core.InitResult{Reinit: true, Skipped: skipped}
+	} else {
+		result, err = core.InitProjectWithProfile(cwd, name, profile)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+	}
+
+	for _, path := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "warn: kept customized %s (delete it to regenerate)\n", path)
 	}
 
 	if result.Reinit {
 		if agentMode {
-			fmt.Printf("reinit:ok hooks:5 skills:12\n")
+			fmt.Printf("reinit:ok hooks:5 skills:12 profile:%s\n", core.ProjectProfile(cwd))
 		} else {
 			fmt.Printf("Re-initialized ptsd project in %s\n", cwd)
 		}
 	} else {
 		if agentMode {
-			fmt.Printf("init:ok dir:%s\n", cwd)
+			fmt.Printf("init:ok dir:%s profile:%s\n", cwd, core.ProjectProfile(cwd))
 		} else {
-			fmt.Printf("Initialized ptsd project in %s\n", cwd)
+			fmt.Printf("Initialized ptsd project in %s (profile: %s)\n", cwd, core.ProjectProfile(cwd))
 		}
 	}
 	return 0
 }
 
-// RunAdopt handles `ptsd adopt [--dry-run]`.
+// RunAdopt handles `ptsd adopt [--map FILE] [--dry-run] [--progress jsonl]`.
+// On an already adopted project it imports only what is new.
 func RunAdopt(args []string, agentMode bool) int {
+	args, progress, err := parseProgressFlag(args)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	args, maxDepth, err := parseMaxDepthFlag(args)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	opts := core.AdoptOptions{Progress: progress, Limits: core.DefaultWalkLimits}
+	opts.Limits.MaxDepth = maxDepth
 	dryRun := false
-	for _, a := range args {
-		if a == "--dry-run" {
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--dry-run":
 			dryRun = true
+		case "--map":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "adopt", "--map requires a file")
+			}
+			opts.MapFile = args[i+1]
+			i++
+		case "--map-tests":
+			opts.MapTests = true
 		}
 	}
 
@@ -62,29 +111,105 @@ func RunAdopt(args []string, agentMode bool) int {
 	}
 
 	if dryRun {
-		result, err := core.AdoptDryRun(cwd)
+		opts.Progress = nil
+		result, err := core.AdoptDryRunWithOptions(cwd, opts)
 		if err != nil {
 			return coreError(agentMode, err)
 		}
+		writeAdoptPlan(result, agentMode)
 		if agentMode {
-			fmt.Printf("dry-run:ok bdd:%d tests:%d features:%s\n",
-				len(result.BDDFiles), len(result.TestFiles), result.FeaturesFile)
+			fmt.Printf("dry-run:ok bdd:%d tests:%d features:%s new:%d import:%d skip:%d map:%d incremental:%t unmapped:%d stages:%d\n",
+				len(result.BDDFiles), len(result.TestFiles), result.FeaturesFile,
+				len(result.NewFeatures), len(result.Imports), len(result.Skipped), len(result.TestMaps), result.Incremental, len(result.Unmapped), len(result.Stages))
 		} else {
-			fmt.Printf("Dry run — would create: %s\n", result.FeaturesFile)
+			verb := "create"
+			if result.Incremental {
+				verb = "update"
+			}
+			fmt.Printf("Dry run — would %s: %s\n", verb, result.FeaturesFile)
 			fmt.Printf("BDD features found: %d\n", len(result.BDDFiles))
 			fmt.Printf("Test files found: %d\n", len(result.TestFiles))
+			fmt.Printf("New features: %d, .feature imports: %d, skipped: %d, test mappings: %d, unmapped tests: %d, stages: %d\n",
+				len(result.NewFeatures), len(result.Imports), len(result.Skipped), len(result.TestMaps), len(result.Unmapped), len(result.Stages))
 		}
 		return 0
 	}
 
-	if err := core.AdoptProject(cwd); err != nil {
+	result, err := core.AdoptProjectWithOptions(cwd, opts)
+	if err != nil {
 		return coreError(agentMode, err)
 	}
+	writeAdoptPlan(result, agentMode)
 
 	if agentMode {
-		fmt.Printf("adopt:ok dir:%s\n", cwd)
+		fmt.Printf("adopt:ok dir:%s new:%d import:%d skip:%d map:%d incremental:%t unmapped:%d stages:%d scenarios:%d\n", cwd,
+			len(result.NewFeatures), len(result.Imports), len(result.Skipped), len(result.TestMaps), result.Incremental, len(result.Unmapped),
+			len(result.Stages), len(result.ScenarioMaps))
+	} else if result.Incremental {
+		fmt.Printf("Adopted project in %s (incremental: %d new feature(s), %d .feature import(s), %d test mapping(s))\n",
+			cwd, len(result.NewFeatures), len(result.Imports), len(result.TestMaps))
 	} else {
 		fmt.Printf("Adopted project in %s\n", cwd)
 	}
+	if !agentMode && len(result.Unmapped) > 0 {
+		fmt.Printf("%d test file(s) are not mapped to a feature yet: see ptsd test unmapped\n", len(result.Unmapped))
+	}
 	return 0
 }
+
+// writeAdoptPlan lists the per-file actions of an adopt run (planned for
+// --dry-run, done otherwise).
+func writeAdoptPlan(result *core.AdoptResult, agentMode bool) {
+	for _, id := range result.NewFeatures {
+		if agentMode {
+			fmt.Printf("feature %s\n", id)
+		} else {
+			fmt.Printf("  add feature %s\n", id)
+		}
+	}
+	for _, imp := range result.Imports {
+		action := "import"
+		if imp.Append {
+			action = "append"
+		}
+		if agentMode {
+			fmt.Printf("%s %s -> %s\n", action, imp.Src, imp.Dst)
+		} else {
+			fmt.Printf("  %s %s -> %s\n", action, imp.Src, imp.Dst)
+		}
+	}
+	for _, src := range result.Skipped {
+		if agentMode {
+			fmt.Printf("skip %s reason=exists\n", src)
+		} else {
+			fmt.Printf("  skip %s (already in the BDD directory; add it to --map to merge)\n", src)
+		}
+	}
+	for _, m := range result.TestMaps {
+		by := ""
+		if m.Convention {
+			by = " by=name"
+		}
+		if agentMode {
+			fmt.Printf("map %s %s%s\n", m.Feature, m.File, by)
+		} else if m.Convention {
+			fmt.Printf("  map %s -> %s (by file name)\n", m.File, m.Feature)
+		} else {
+			fmt.Printf("  map %s -> %s\n", m.File, m.Feature)
+		}
+	}
+	for _, st := range result.Stages {
+		if agentMode {
+			fmt.Printf("stage %s %s\n", st.Feature, st.Stage)
+		} else {
+			fmt.Printf("  stage %s -> %s\n", st.Feature, st.Stage)
+		}
+	}
+	for _, m := range result.ScenarioMaps {
+		if agentMode {
+			fmt.Printf("match: %s %q -> %s::%s confidence=%.2f mapped\n", m.Scenario, m.Title, m.File, m.Func, m.Confidence)
+		} else {
+			fmt.Printf("  map scenario %q -> %s::%s (%.0f%% confidence)\n", m.Title, m.File, m.Func, m.Confidence*100)
+		}
+	}
+}
@@ -250,13 +250,13 @@ func TestRunInitCreatesExpectedFiles(t *testing.T) {
 		t.Errorf("PRD.md expected to start with '# ' heading, got:\n%s", prdData)
 	}
 
-	// state.yaml must have a proper header.
+	// state.yaml must have a proper header: the schema version, then features.
 	stateData, err := os.ReadFile(filepath.Join(dir, ".ptsd", "state.yaml"))
 	if err != nil {
 		t.Fatalf("state.yaml not readable: %v", err)
 	}
-	if !strings.HasPrefix(strings.TrimSpace(string(stateData)), "features:") {
-		t.Errorf("state.yaml expected to start with 'features:', got:\n%s", stateData)
+	if !strings.HasPrefix(strings.TrimSpace(string(stateData)), "schema: 3\nfeatures:") {
+		t.Errorf("state.yaml expected to start with 'schema: 3' and 'features:', got:\n%s", stateData)
 	}
 }
 
@@ -359,6 +359,40 @@ func TestRunAdoptDryRun(t *testing.T) {
 	}
 }
 
+// TestRunAdoptMaxDepth verifies --max-depth bounds the discovery walk and
+// rejects non-numeric values as a user error.
+func TestRunAdoptMaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	deep := filepath.Join(dir, "a", "b")
+	if err := os.MkdirAll(deep, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(deep, "login.feature"), []byte("@feature:login\nFeature: Login\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	chdirTemp(t, dir)
+
+	var output string
+	code := -1
+	output = captureOutput(func() {
+		code = RunAdopt([]string{"--dry-run", "--max-depth", "1"}, true)
+	})
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d. output: %q", code, output)
+	}
+	if !strings.Contains(output, "bdd:0") {
+		t.Errorf("expected deep .feature to be skipped, got: %q", output)
+	}
+
+	output = captureOutput(func() {
+		code = RunAdopt([]string{"--dry-run", "--max-depth", "x"}, true)
+	})
+	if code != 2 {
+		t.Errorf("expected exit code 2 for invalid --max-depth, got %d. output: %q", code, output)
+	}
+}
+
 // TestRunAdoptDryRunHumanMode verifies human-mode output for dry run with actual artifacts.
 // The dir contains a .feature file and a _test.go file so that discovery is exercised.
 func TestRunAdoptDryRunHumanMode(t *testing.T) {
@@ -487,71 +521,83 @@ func TestRunAdoptSuccessHumanMode(t *testing.T) {
 	}
 }
 
-// TestRunAdoptRefusesIfAlreadyInitialized covers BDD: "Adopt refuses if .ptsd already initialized".
-// Returns exit code 1 (validation error).
-func TestRunAdoptRefusesIfAlreadyInitialized(t *testing.T) {
+// TestRunAdoptIncremental verifies adopt on an initialized project imports
+// only new artifacts and reports the incremental run.
+func TestRunAdoptIncremental(t *testing.T) {
 	dir := t.TempDir()
-
-	// Pre-create .ptsd/ to simulate already-initialized state.
 	if err := os.MkdirAll(filepath.Join(dir, ".ptsd"), 0755); err != nil {
 		t.Fatalf("failed to pre-create .ptsd: %v", err)
 	}
-	// Write a minimal features.yaml so it's a valid .ptsd dir.
 	if err := os.WriteFile(
 		filepath.Join(dir, ".ptsd", "features.yaml"),
-		[]byte("features: []\n"),
+		[]byte("features:\n  - id: existing\n    title: existing\n    status: planned\n"),
 		0644,
 	); err != nil {
 		t.Fatalf("failed to write features.yaml: %v", err)
 	}
+	if err := os.WriteFile(filepath.Join(dir, "fresh.feature"), []byte("@feature:fresh\nFeature: Fresh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
 
 	chdirTemp(t, dir)
 
-	var output string
 	code := -1
-	output = captureStderr(t, func() {
+	output := captureOutput(func() {
 		code = RunAdopt([]string{}, true)
 	})
 
-	if code != 1 {
-		t.Errorf("expected exit code 1 (validation), got %d. output: %q", code, output)
+	if code != 0 {
+		t.Fatalf("expected exit code 0 for incremental adopt, got %d. output: %q", code, output)
+	}
+	for _, want := range []string{"feature fresh\n", "import fresh.feature -> .ptsd/bdd/fresh.feature\n", "new:1 import:1 skip:0 map:0 incremental:true"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %q", want, output)
+		}
 	}
-	if !strings.Contains(output, "err:validation") {
-		t.Errorf("expected 'err:validation' in output, got: %q", output)
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "features.yaml"))
+	if !strings.Contains(string(data), "id: existing") || !strings.Contains(string(data), "id: fresh") {
+		t.Errorf("expected existing and fresh features, got:\n%s", data)
 	}
-	if !strings.Contains(output, "already initialized") {
-		t.Errorf("expected 'already initialized' in output, got: %q", output)
+	if _, err := os.Stat(filepath.Join(dir, ".ptsd", "ptsd.yaml")); err == nil {
+		t.Error("incremental adopt must not write ptsd.yaml")
 	}
 }
 
-// TestRunAdoptDryRunRefusesIfAlreadyInitialized verifies dry-run also checks .ptsd existence.
-func TestRunAdoptDryRunRefusesIfAlreadyInitialized(t *testing.T) {
+// TestRunAdoptDryRunWithMap verifies dry-run lists the plan for a map file.
+func TestRunAdoptDryRunWithMap(t *testing.T) {
 	dir := t.TempDir()
-
 	if err := os.MkdirAll(filepath.Join(dir, ".ptsd"), 0755); err != nil {
 		t.Fatalf("failed to pre-create .ptsd: %v", err)
 	}
 	if err := os.WriteFile(
 		filepath.Join(dir, ".ptsd", "features.yaml"),
-		[]byte("features: []\n"),
+		[]byte("features:\n  - id: existing\n    title: existing\n    status: planned\n"),
 		0644,
 	); err != nil {
 		t.Fatalf("failed to write features.yaml: %v", err)
 	}
+	os.MkdirAll(filepath.Join(dir, "specs"), 0755)
+	os.WriteFile(filepath.Join(dir, "specs", "login.feature"), []byte("Feature: Login\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "login_test.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "features.map.yaml"), []byte("features:\n  auth:\n    bdd: [specs/*.feature]\n    tests: [login_test.go]\n"), 0644)
 
 	chdirTemp(t, dir)
 
-	var output string
 	code := -1
-	output = captureStderr(t, func() {
-		code = RunAdopt([]string{"--dry-run"}, true)
+	output := captureOutput(func() {
+		code = RunAdopt([]string{"--dry-run", "--map", "features.map.yaml"}, true)
 	})
 
-	if code != 1 {
-		t.Errorf("expected exit code 1 (validation) for dry-run on initialized project, got %d. output: %q", code, output)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d. output: %q", code, output)
+	}
+	for _, want := range []string{"feature auth\n", "import specs/login.feature -> .ptsd/bdd/auth.feature\n", "map auth login_test.go\n", "new:1 import:1 skip:0 map:1 incremental:true"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %q", want, output)
+		}
 	}
-	if !strings.Contains(output, "err:validation") {
-		t.Errorf("expected 'err:validation' in output, got: %q", output)
+	if _, err := os.Stat(filepath.Join(dir, "specs", "login.feature")); err != nil {
+		t.Error("dry run moved a .feature file")
 	}
 }
 
@@ -708,37 +754,21 @@ func TestRunInitHumanModeReInit(t *testing.T) {
 	}
 }
 
-// TestRunAdoptHumanModeErrorAlreadyInitialized verifies that RunAdopt in human mode
-// returns exit code 1 when .ptsd already exists and prints a human-readable error.
-func TestRunAdoptHumanModeErrorAlreadyInitialized(t *testing.T) {
+// TestRunAdoptMapMissingFile verifies a missing --map file is an io error.
+func TestRunAdoptMapMissingFile(t *testing.T) {
 	dir := t.TempDir()
-
-	// Pre-create .ptsd/ to simulate already-initialized state.
-	if err := os.MkdirAll(filepath.Join(dir, ".ptsd"), 0755); err != nil {
-		t.Fatalf("failed to pre-create .ptsd: %v", err)
-	}
-	if err := os.WriteFile(
-		filepath.Join(dir, ".ptsd", "features.yaml"),
-		[]byte("features: []\n"),
-		0644,
-	); err != nil {
-		t.Fatalf("failed to write features.yaml: %v", err)
-	}
-
 	chdirTemp(t, dir)
 
-	var output string
 	code := -1
-	output = captureStderr(t, func() {
-		code = RunAdopt([]string{}, false)
+	output := captureStderr(t, func() {
+		code = RunAdopt([]string{"--map", "nope.yaml"}, true)
 	})
 
-	if code != 1 {
-		t.Errorf("expected exit code 1 (validation), got %d. output: %q", code, output)
+	if code != 4 {
+		t.Errorf("expected exit code 4 (io), got %d. output: %q", code, output)
 	}
-	// Human mode must communicate that adoption failed.
-	if !strings.Contains(output, "already initialized") {
-		t.Errorf("expected 'already initialized' in human-mode error output, got: %q", output)
+	if _, err := os.Stat(filepath.Join(dir, ".ptsd")); err == nil {
+		t.Error("adopt with a missing map file must not create .ptsd/")
 	}
 }
 
@@ -803,3 +833,50 @@ func TestRunInitDetectsTestRunner(t *testing.T) {
 		})
 	}
 }
+
+// TestRunInitMinimalFlag verifies --minimal is not mistaken for a name and is reported.
+func TestRunInitMinimalFlag(t *testing.T) {
+	dir := t.TempDir()
+	setupGitRepo(t, dir)
+	chdirTemp(t, dir)
+
+	output := captureOutput(func() {
+		if code := RunInit([]string{"--profile", "minimal", "MyLib"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(output, "profile:minimal") {
+		t.Errorf("expected profile:minimal in output, got %q", output)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"))
+	if !strings.Contains(string(data), `name: "MyLib"`) {
+		t.Errorf("expected project name MyLib, got:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "CLAUDE.md")); err == nil {
+		t.Error("minimal profile must not write CLAUDE.md")
+	}
+}
+
+// TestRunDeinitAgentRequiresYes verifies agent mode never deletes without --yes.
+func TestRunDeinitAgentRequiresYes(t *testing.T) {
+	dir := t.TempDir()
+	setupGitRepo(t, dir)
+	chdirTemp(t, dir)
+	captureOutput(func() { RunInit(nil, true) })
+
+	if code := RunDeinit(nil, true); code != 2 {
+		t.Errorf("expected exit 2 without --yes, got %d", code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ptsd")); err != nil {
+		t.Fatal(".ptsd must survive an unconfirmed deinit")
+	}
+
+	output := captureOutput(func() {
+		if code := RunDeinit([]string{"--yes", "--no-backup"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(output, "removed:.ptsd/") || !strings.Contains(output, "deinit:ok") {
+		t.Errorf("unexpected output: %q", output)
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Global --json output. A command run with --json prints exactly one JSON
+// document to stdout:
+//
+//	{"schema": "ptsd.<kind>/v1", "command": "status", "exit_code": 0, "data": ...}
+//
+// Commands with a structured form hand their payload to printJSON, which
+// names the schema after the payload kind (ptsd.status/v1, ptsd.task.list/v1).
+// Everything else is captured and wrapped as {"lines": [...]} under
+// ptsd.output/v1. Failures add "error": {"category", "message"}. The version
+// suffix changes only when a payload changes incompatibly.
+
+// JSONSchemaVersion is the version suffix of every --json schema.
+const JSONSchemaVersion = 1
+
+var (
+	jsonOutput  bool
+	jsonKind    string
+	jsonPayload any
+	jsonErr     *jsonError
+)
+
+type jsonError struct {
+	Category string `json:"category"`
+	Message  string `json:"message"`
+}
+
+type jsonEnvelope struct {
+	Schema   string     `json:"schema"`
+	Command  string     `json:"command"`
+	ExitCode int        `json:"exit_code"`
+	Data     any        `json:"data"`
+	Error    *jsonError `json:"error,omitempty"`
+}
+
+type outputLinesJSON struct {
+	Lines []string `json:"lines"`
+}
+
+// SetJSONOutput switches the cli package to --json output.
+func SetJSONOutput(on bool) {
+	jsonOutput = on
+}
+
+// JSONPassthrough reports whether command keeps its own output under --json:
+// hook protocols and the long-running daemon are not wrapped.
+func JSONPassthrough(command string) bool {
+	switch command {
+	case "daemon", "gate-check", "auto-track":
+		return true
+	}
+	return false
+}
+
+// RunWithJSON runs a command with stdout captured and prints its result as a
+// single JSON envelope. It returns the command's exit code.
+func RunWithJSON(command string, run func() int) int {
+	jsonKind, jsonPayload, jsonErr = "", nil, nil
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		return renderError(true, "io", err.Error())
+	}
+	var captured bytes.Buffer
+	copied := make(chan struct{})
+	go func() {
+		io.Copy(&captured, r)
+		close(copied)
+	}()
+
+	os.Stdout = w
+	code := func() int {
+		defer func() { os.Stdout = stdout }()
+		return run()
+	}()
+	w.Close()
+	<-copied
+	r.Close()
+
+	env := jsonEnvelope{Command: command, ExitCode: code, Error: jsonErr}
+	if jsonKind != "" {
+		env.Schema = jsonSchema(jsonKind)
+		env.Data = jsonPayload
+	} else {
+		env.Schema = jsonSchema("output")
+		lines := []string{}
+		if text := strings.TrimRight(captured.String(), "\n"); text != "" {
+			lines = strings.Split(text, "\n")
+		}
+		env.Data = outputLinesJSON{Lines: lines}
+	}
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return renderError(true, "io", err.Error())
+	}
+	fmt.Fprintln(stdout, string(data))
+	return code
+}
+
+func jsonSchema(kind string) string {
+	return fmt.Sprintf("ptsd.%s/v%d", kind, JSONSchemaVersion)
+}
+
+// recordJSONError keeps the first error of a --json run for its envelope.
+func recordJSONError(category, message string) {
+	if jsonErr == nil {
+		jsonErr = &jsonError{Category: category, Message: message}
+	}
+}
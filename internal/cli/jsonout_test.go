@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testEnvelope struct {
+	Schema   string          `json:"schema"`
+	Command  string          `json:"command"`
+	ExitCode int             `json:"exit_code"`
+	Data     json.RawMessage `json:"data"`
+	Error    *jsonError      `json:"error"`
+}
+
+// runJSON runs fn under global --json and decodes the envelope it prints.
+func runJSON(t *testing.T, command string, fn func() int) (testEnvelope, int) {
+	t.Helper()
+	SetJSONOutput(true)
+	defer SetJSONOutput(false)
+
+	var code int
+	out := captureStdout(t, func() {
+		code = RunWithJSON(command, fn)
+	})
+	var env testEnvelope
+	if err := json.Unmarshal([]byte(out), &env); err != nil {
+		t.Fatalf("output is not one JSON document: %v\n%s", err, out)
+	}
+	return env, code
+}
+
+func TestRunWithJSON_NativePayload(t *testing.T) {
+	dir := setupTaskProjectWithTasks(t, []string{"auth"}, "tasks:\n  - id: T-1\n    feature: auth\n    title: Login\n    status: TODO\n    priority: A\n")
+
+	var env testEnvelope
+	withDir(t, dir, func() {
+		env, _ = runJSON(t, "task", func() int { return RunTask([]string{"list"}, true) })
+	})
+	if env.Schema != "ptsd.task.list/v1" || env.Command != "task" || env.ExitCode != 0 {
+		t.Fatalf("unexpected envelope: %+v", env)
+	}
+	var tasks []taskJSON
+	if err := json.Unmarshal(env.Data, &tasks); err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "T-1" || tasks[0].Priority != "A" {
+		t.Errorf("unexpected tasks: %+v", tasks)
+	}
+
+	withDir(t, dir, func() {
+		env, _ = runJSON(t, "status", func() int { return RunStatus(nil, true) })
+	})
+	var status statusJSON
+	if err := json.Unmarshal(env.Data, &status); err != nil {
+		t.Fatal(err)
+	}
+	if env.Schema != "ptsd.status/v1" || status.Tasks.Total != 1 || status.Tasks.TODO != 1 {
+		t.Errorf("unexpected status: %s %+v", env.Schema, status)
+	}
+}
+
+func TestRunWithJSON_WrapsTextAndErrors(t *testing.T) {
+	dir := setupTaskProject(t, "auth")
+
+	var env testEnvelope
+	var code int
+	withDir(t, dir, func() {
+		env, code = runJSON(t, "feature", func() int { return RunFeature([]string{"status", "auth", "in-progress"}, true) })
+	})
+	if code != 0 || env.Schema != "ptsd.output/v1" {
+		t.Fatalf("unexpected envelope: %+v", env)
+	}
+	var out outputLinesJSON
+	if err := json.Unmarshal(env.Data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Lines) != 1 || out.Lines[0] != "feature.status id=auth status=in-progress" {
+		t.Errorf("unexpected lines: %q", out.Lines)
+	}
+
+	withDir(t, dir, func() {
+		env, code = runJSON(t, "feature", func() int { return RunFeature([]string{"show", "missing"}, true) })
+	})
+	if code == 0 || env.ExitCode != code || env.Error == nil || env.Error.Category == "" {
+		t.Errorf("expected error in envelope, got code %d %+v", code, env)
+	}
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunLint executes `ptsd lint`: config, PRD, BDD, seed and fsck checks in one
+// report. Exit 0 = no errors (warnings allowed), 1 = at least one error.
+func RunLint(args []string, agentMode bool) int {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	findings, err := core.Lint(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	errCount, warnCount := 0, 0
+	for _, f := range findings {
+		if f.Severity == "error" {
+			errCount++
+		} else {
+			warnCount++
+		}
+		feature := f.Feature
+		if agentMode {
+			if feature == "" {
+				feature = "-"
+			}
+			fmt.Printf("%s:%s %s: %s\n", f.Severity, f.Check, feature, f.Message)
+		} else {
+			if feature == "" {
+				feature = "(global)"
+			}
+			fmt.Printf("%-5s [%s] %s: %s\n", f.Severity, f.Check, feature, f.Message)
+		}
+	}
+
+	if agentMode {
+		fmt.Printf("lint errors=%d warnings=%d\n", errCount, warnCount)
+	} else if len(findings) == 0 {
+		fmt.Println("ok")
+	} else {
+		fmt.Printf("\n%d error(s), %d warning(s)\n", errCount, warnCount)
+	}
+
+	if errCount > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// lockedCommands change .ptsd and run under the project lock (see
+// core.LockProject). Everything else only reads, appends to a log, or runs
+// long and locks per write: hooks and gate-check on every tool call, the
+// daemon, review serve, validate --watch, events --follow, plugins, and
+// `all`, whose children lock their own workspaces. status and context lock
+// in core only when regressions or queued auto-track paths need a write.
+var lockedCommands = map[string]bool{
+	"init": true, "adopt": true, "deinit": true, "feature": true, "features": true,
+	"config": true, "task": true, "prd": true, "seed": true, "bdd": true,
+	"test": true, "stage": true, "validate": true, "review": true, "skills": true,
+	"templates": true, "issues": true, "gate": true, "auto-track": true,
+	"migrate": true, "snapshot": true, "restore": true, "remote": true,
+	"undo": true,
+}
+
+// SetLockWait sets the global --wait-lock timeout.
+func SetLockWait(d time.Duration) {
+	core.SetLockWait(d)
+}
+
+func commandLocks(cmd string, args []string) bool {
+	switch cmd {
+	case "hooks":
+		return len(args) > 0 && args[0] == "install"
+	case "review":
+		return len(args) == 0 || args[0] != "serve"
+	case "validate":
+		return !slices.Contains(args, "--watch")
+	}
+	return lockedCommands[cmd]
+}
+
+// RunLocked runs a command, holding the project lock of the current
+// directory when the command changes .ptsd, and records the data files in
+// .ptsd/.history first so `ptsd undo` can return to them (undo records its
+// own entry). Outside a project it just runs.
+func RunLocked(cmd string, args []string, agentMode bool, run func() int) int {
+	if !commandLocks(cmd, args) {
+		return run()
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return run()
+	}
+	if _, err := os.Stat(filepath.Join(cwd, ".ptsd")); err != nil {
+		return run()
+	}
+	unlock, err := core.LockProject(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	defer unlock()
+	if cmd != "undo" {
+		// Without history the command still runs; it just cannot be undone.
+		if finish, err := core.BeginHistory(cwd, strings.Join(append([]string{cmd}, args...), " ")); err == nil {
+			defer finish()
+		}
+	}
+	return run()
+}
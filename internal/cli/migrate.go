@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunMigrate handles `ptsd migrate [--dry-run]`: rewrites ptsd.yaml,
+// state.yaml and tasks.yaml to the current schema after snapshotting .ptsd/.
+func RunMigrate(args []string, agentMode bool) int {
+	dryRun := false
+	for _, a := range args {
+		switch a {
+		case "--dry-run":
+			dryRun = true
+		default:
+			return usageError(agentMode, "migrate", "unknown flag: "+a)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	result, err := core.Migrate(cwd, dryRun)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	pending := 0
+	for _, f := range result.Files {
+		if f.Outdated {
+			pending++
+		}
+		switch {
+		case agentMode && f.Outdated:
+			fmt.Printf("migrate %s from=%d to=%d\n", f.File, f.Version, core.SchemaVersion)
+		case agentMode:
+			fmt.Printf("current %s schema=%d\n", f.File, f.Version)
+		case f.Outdated:
+			fmt.Printf("%-12s schema %d → %d\n", f.File, f.Version, core.SchemaVersion)
+		default:
+			fmt.Printf("%-12s schema %d (current)\n", f.File, f.Version)
+		}
+	}
+
+	switch {
+	case pending == 0:
+		fmt.Println("nothing to migrate")
+	case dryRun:
+		fmt.Printf("dry run: %d file(s) would be rewritten\n", pending)
+	default:
+		fmt.Printf("migrated %d file(s); backup snapshot %s (undo: ptsd restore %s)\n", pending, result.Snapshot, result.Snapshot)
+	}
+	return 0
+}
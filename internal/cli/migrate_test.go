@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunMigrate(t *testing.T) {
+	dir := setupTaskProjectWithTasks(t, []string{"my-feat"}, "tasks:\n  - id: T-001\n    feature: my-feat\n    title: Some task\n    status: done\n")
+
+	var code int
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			code = RunMigrate([]string{"--dry-run"}, true)
+		})
+	})
+	if code != 0 || !strings.Contains(out, "migrate tasks.yaml from=1 to=3") || !strings.Contains(out, "dry run:") {
+		t.Fatalf("unexpected dry run (exit %d):\n%s", code, out)
+	}
+
+	out = captureStdout(t, func() {
+		withDir(t, dir, func() {
+			code = RunMigrate(nil, true)
+		})
+	})
+	if code != 0 || !strings.Contains(out, "backup snapshot pre-migrate-") {
+		t.Fatalf("unexpected migrate (exit %d):\n%s", code, out)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "tasks.yaml"))
+	if !strings.HasPrefix(string(data), "schema: 3\n") || !strings.Contains(string(data), "status: DONE") {
+		t.Errorf("tasks.yaml not migrated:\n%s", data)
+	}
+
+	withDir(t, dir, func() {
+		if code := RunMigrate([]string{"--force"}, true); code != 2 {
+			t.Errorf("expected exit 2 for unknown flag, got %d", code)
+		}
+	})
+}
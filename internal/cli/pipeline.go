@@ -3,13 +3,16 @@ package cli
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/veschin/ptsd/internal/core"
 	"github.com/veschin/ptsd/internal/render"
 )
 
-// RunPrd handles: ptsd prd check | ptsd prd show <feature>
+// RunPrd handles: ptsd prd check | ptsd prd show <feature> | ptsd prd index | ptsd prd import <file>
 func RunPrd(args []string, agentMode bool) int {
 	if len(args) == 0 {
 		fmt.Fprintln(os.Stderr, "err:user usage: ptsd prd check")
@@ -21,6 +24,50 @@ func RunPrd(args []string, agentMode bool) int {
 		if err != nil {
 			return coreError(agentMode, err)
 		}
+		register, removeAnchor := false, false
+		var ids []string
+		for _, a := range args[1:] {
+			switch a {
+			case "--register":
+				register = true
+			case "--remove-anchor":
+				removeAnchor = true
+			default:
+				ids = append(ids, a)
+			}
+		}
+		if register && removeAnchor {
+			return usageError(agentMode, "prd check", "--register and --remove-anchor are mutually exclusive")
+		}
+		if len(ids) > 0 && !register && !removeAnchor {
+			return usageError(agentMode, "prd check", "anchor ids need --register or --remove-anchor")
+		}
+		if register {
+			registered, err := core.RegisterOrphanedAnchors(dir, ids)
+			if err != nil {
+				return coreError(agentMode, err)
+			}
+			for _, id := range registered {
+				if agentMode {
+					fmt.Printf("feature.add id=%s\n", id)
+				} else {
+					fmt.Printf("Registered feature %s from its PRD anchor\n", id)
+				}
+			}
+		}
+		if removeAnchor {
+			removed, err := core.RemoveOrphanedAnchors(dir, ids)
+			if err != nil {
+				return coreError(agentMode, err)
+			}
+			for _, id := range removed {
+				if agentMode {
+					fmt.Printf("prd.anchor.remove id=%s\n", id)
+				} else {
+					fmt.Printf("Removed orphaned anchor %s\n", id)
+				}
+			}
+		}
 		errs, err := core.CheckPRDAnchors(dir)
 		if err != nil {
 			return coreError(agentMode, err)
@@ -33,38 +80,240 @@ func RunPrd(args []string, agentMode bool) int {
 			}
 			return 0
 		}
+		orphaned := false
 		for _, e := range errs {
-			fmt.Fprintf(os.Stderr, "err:pipeline %s %s\n", e.Type, e.FeatureID)
+			if len(e.Files) > 0 {
+				fmt.Fprintf(os.Stderr, "err:pipeline %s %s files=%s\n", e.Type, e.FeatureID, strings.Join(e.Files, ","))
+			} else {
+				fmt.Fprintf(os.Stderr, "err:pipeline %s %s\n", e.Type, e.FeatureID)
+			}
+			orphaned = orphaned || e.Type == "orphaned-anchor"
+		}
+		if orphaned && !agentMode {
+			fmt.Fprintln(os.Stderr, "fix orphaned anchors with: ptsd prd check --register | --remove-anchor [id...]")
 		}
 		return 1
 	case "show":
-		if len(args) < 2 {
-			fmt.Fprintln(os.Stderr, "err:user usage: ptsd prd show <feature>")
+		featureID, raw := "", false
+		for _, a := range args[1:] {
+			if a == "--raw" {
+				raw = true
+			} else if featureID == "" && !strings.HasPrefix(a, "-") {
+				featureID = a
+			} else {
+				return usageError(agentMode, "prd show", "usage: ptsd prd show <feature> [--raw]")
+			}
+		}
+		if featureID == "" {
+			fmt.Fprintln(os.Stderr, "err:user usage: ptsd prd show <feature> [--raw]")
 			return 2
 		}
-		featureID := args[1]
 		dir, err := os.Getwd()
 		if err != nil {
 			return coreError(agentMode, err)
 		}
+		if raw {
+			content, err := core.GetPRDSection(dir, featureID)
+			if err != nil {
+				return coreError(agentMode, err)
+			}
+			fmt.Println(content)
+			return 0
+		}
 		section, err := core.ExtractPRDSection(dir, featureID)
 		if err != nil {
 			return coreError(agentMode, err)
 		}
 		if agentMode {
-			fmt.Printf("feature:%s lines:%d-%d\n", section.FeatureID, section.StartLine, section.EndLine)
+			fmt.Printf("feature:%s lines:%d-%d file:%s\n", section.FeatureID, section.StartLine, section.EndLine, section.File)
 			fmt.Println(section.Content)
 		} else {
-			fmt.Printf("Feature: %s (lines %d-%d)\n\n%s\n", section.FeatureID, section.StartLine, section.EndLine, section.Content)
+			fmt.Printf("Feature: %s (%s lines %d-%d)\n\n%s\n", section.FeatureID, section.File, section.StartLine, section.EndLine, section.Content)
 		}
 		return 0
+	case "index":
+		return runPrdIndex(args[1:], agentMode)
+	case "import":
+		return runPrdImport(args[1:], agentMode)
 	default:
 		fmt.Fprintf(os.Stderr, "err:user unknown prd subcommand: %s\n", args[0])
 		return 2
 	}
 }
 
+type prdAnchorJSON struct {
+	Feature string `json:"feature"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+}
+
+// runPrdIndex handles: prd index [--json]. It lists which PRD file owns
+// which feature anchor.
+func runPrdIndex(args []string, agentMode bool) int {
+	jsonOut := jsonOutput
+	for _, a := range args {
+		if a != "--json" {
+			return usageError(agentMode, "prd index", "unknown flag "+a)
+		}
+		jsonOut = true
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	index, err := core.PRDIndex(dir)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if jsonOut {
+		out := []prdAnchorJSON{}
+		for _, a := range index {
+			out = append(out, prdAnchorJSON{Feature: a.FeatureID, File: a.File, Line: a.Line})
+		}
+		return printJSON(agentMode, "prd.index", out)
+	}
+	if agentMode {
+		for _, a := range index {
+			fmt.Printf("anchor: %s %s:%d\n", a.FeatureID, a.File, a.Line)
+		}
+		fmt.Printf("anchors count=%d\n", len(index))
+		return 0
+	}
+	if len(index) == 0 {
+		fmt.Println("No feature anchors in the PRD files.")
+		return 0
+	}
+	width := 0
+	for _, a := range index {
+		width = max(width, len(a.FeatureID))
+	}
+	for _, a := range index {
+		fmt.Printf("  %-*s  %s:%d\n", width, a.FeatureID, a.File, a.Line)
+	}
+	return 0
+}
+
+// runPrdImport handles: prd import <file> [--level N] [--rename old=new]... [--accept all|<id,...>]
+// Without --accept it only prints the proposed features.
+func runPrdImport(args []string, agentMode bool) int {
+	usage := "usage: prd import <file> [--level N] [--rename old=new] [--accept all|<id,...>]"
+	path := ""
+	level := 0
+	accept := ""
+	renames := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--level", "--accept", "--rename":
+			if i+1 >= len(args) {
+				return renderError(agentMode, "user", usage)
+			}
+			val := args[i+1]
+			i++
+			switch args[i-1] {
+			case "--level":
+				n, err := strconv.Atoi(val)
+				if err != nil {
+					return usageError(agentMode, "prd import", "--level must be 1-6")
+				}
+				level = n
+			case "--accept":
+				accept = val
+			case "--rename":
+				from, to, ok := strings.Cut(val, "=")
+				if !ok || from == "" || to == "" {
+					return usageError(agentMode, "prd import", "--rename expects old=new")
+				}
+				renames[from] = to
+			}
+		default:
+			path = args[i]
+		}
+	}
+	if path == "" {
+		return renderError(agentMode, "user", usage)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	proposals, err := core.ProposePRDImport(dir, string(data), level)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	for i := range proposals {
+		if to, ok := renames[proposals[i].ID]; ok {
+			proposals[i].ID = to
+			proposals[i].Conflict = ""
+		}
+	}
+
+	if accept == "" {
+		for _, p := range proposals {
+			if agentMode {
+				fmt.Printf("proposed: %s lines:%d-%d title:%q", p.ID, p.StartLine, p.EndLine, p.Title)
+				if p.Conflict != "" {
+					fmt.Printf(" conflict:%q", p.Conflict)
+				}
+				fmt.Println()
+			} else {
+				fmt.Printf("%-30s %s (lines %d-%d)", p.ID, p.Title, p.StartLine, p.EndLine)
+				if p.Conflict != "" {
+					fmt.Printf(" — %s", p.Conflict)
+				}
+				fmt.Println()
+			}
+		}
+		if !agentMode {
+			fmt.Println("\nRe-run with --accept all or --accept <id,...> to import (--rename old=new to change IDs).")
+		}
+		return 0
+	}
+
+	var chosen []core.PRDProposal
+	if accept == "all" {
+		for _, p := range proposals {
+			if p.Conflict == "" {
+				chosen = append(chosen, p)
+			}
+		}
+	} else {
+		byID := make(map[string]core.PRDProposal)
+		for _, p := range proposals {
+			byID[p.ID] = p
+		}
+		for _, id := range strings.Split(accept, ",") {
+			p, ok := byID[strings.TrimSpace(id)]
+			if !ok {
+				return renderError(agentMode, "validation", fmt.Sprintf("no proposed feature %s", id))
+			}
+			chosen = append(chosen, p)
+		}
+	}
+
+	if err := core.ApplyPRDImport(dir, chosen); err != nil {
+		return coreError(agentMode, err)
+	}
+	for _, p := range chosen {
+		if agentMode {
+			fmt.Printf("imported: %s\n", p.ID)
+		} else {
+			fmt.Printf("Imported %s: %s\n", p.ID, p.Title)
+		}
+	}
+	if agentMode {
+		fmt.Printf("prd import added=%d\n", len(chosen))
+	}
+	return 0
+}
+
 // RunSeed handles: ptsd seed add <feature> <file> [type] [description]
+// and ptsd seed snapshot (see runSeedSnapshot).
 func RunSeed(args []string, agentMode bool) int {
 	if len(args) == 0 {
 		fmt.Fprintln(os.Stderr, "err:user usage: ptsd seed add <feature> <file> [type] [description]")
@@ -120,16 +369,209 @@ func RunSeed(args []string, agentMode bool) int {
 			fmt.Printf("Added seed file %s to feature %s\n", filePath, featureID)
 		}
 		return 0
+	case "snapshot":
+		return runSeedSnapshot(args[1:], agentMode)
+	case "emit":
+		return runSeedEmit(args[1:], agentMode)
+	case "validate":
+		return runSeedValidate(args[1:], agentMode)
+	case "scaffold":
+		return runSeedScaffold(args[1:], agentMode)
 	default:
 		fmt.Fprintf(os.Stderr, "err:user unknown seed subcommand: %s\n", args[0])
 		return 2
 	}
 }
 
-// RunBdd handles: ptsd bdd add <feature> | ptsd bdd list [feature]
+// runSeedScaffold handles: ptsd seed scaffold <feature> --from <go-file|ts-file>
+func runSeedScaffold(args []string, agentMode bool) int {
+	const usage = "usage: ptsd seed scaffold <feature> --from <go-file|ts-file>"
+	featureID, from := "", ""
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--from" && i+1 < len(args):
+			from = args[i+1]
+			i++
+		case strings.HasPrefix(a, "--") || featureID != "":
+			return renderError(agentMode, "user", usage)
+		default:
+			featureID = a
+		}
+	}
+	if featureID == "" || from == "" {
+		return renderError(agentMode, "user", usage)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	result, err := core.ScaffoldSeeds(dir, featureID, from)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	written := 0
+	for _, f := range result.Files {
+		switch {
+		case agentMode && f.Skipped:
+			fmt.Printf("skipped: %s type=%s exists\n", f.File, f.Type)
+		case agentMode:
+			fmt.Printf("scaffold: %s type=%s fields=%d\n", f.File, f.Type, f.Fields)
+		case f.Skipped:
+			fmt.Printf("Skipped %s (%s): file exists\n", f.File, f.Type)
+		default:
+			fmt.Printf("Wrote %s from %s (%d fields)\n", f.File, f.Type, f.Fields)
+		}
+		if !f.Skipped {
+			written++
+		}
+	}
+	if agentMode {
+		fmt.Printf("seed scaffold: %s from=%s files=%d skipped=%d\n", featureID, from, written, len(result.Files)-written)
+	} else if written > 0 {
+		fmt.Printf("Replace the example values, add edge cases, then run: ptsd seed validate %s\n", featureID)
+	}
+	return 0
+}
+
+// runSeedValidate handles: ptsd seed validate [feature]. Every seed file must
+// exist, parse as its format and match its schema; problems go to stderr and
+// exit 1.
+func runSeedValidate(args []string, agentMode bool) int {
+	if len(args) > 1 || (len(args) == 1 && strings.HasPrefix(args[0], "-")) {
+		return renderError(agentMode, "user", "usage: ptsd seed validate [feature]")
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	var results []core.SeedValidation
+	if len(args) == 1 {
+		v, err := core.ValidateSeed(dir, args[0])
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		results = append(results, v)
+	} else if results, err = core.ValidateSeeds(dir); err != nil {
+		return coreError(agentMode, err)
+	}
+
+	files, problems := 0, 0
+	for _, v := range results {
+		files += v.Files
+		for _, p := range v.Problems {
+			problems++
+			fmt.Fprintf(os.Stderr, "err:validation seed %s/%s: %s\n", p.Feature, p.File, p.Message)
+		}
+	}
+	if agentMode {
+		fmt.Printf("seed validate: features=%d files=%d problems=%d\n", len(results), files, problems)
+	} else if problems == 0 {
+		fmt.Printf("%d seed file(s) in %d feature(s) OK\n", files, len(results))
+	} else {
+		fmt.Printf("%d problem(s) in %d seed file(s)\n", problems, files)
+	}
+	if problems > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runSeedEmit handles: ptsd seed emit <feature> --lang go|ts|py [--out <path>]
+func runSeedEmit(args []string, agentMode bool) int {
+	const usage = "usage: ptsd seed emit <feature> --lang go|ts|py [--out <path>]"
+	featureID, lang, out := "", "", ""
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case (a == "--lang" || a == "--out") && i+1 < len(args):
+			if a == "--lang" {
+				lang = args[i+1]
+			} else {
+				out = args[i+1]
+			}
+			i++
+		case strings.HasPrefix(a, "--") || featureID != "":
+			return renderError(agentMode, "user", usage)
+		default:
+			featureID = a
+		}
+	}
+	if featureID == "" || lang == "" {
+		return renderError(agentMode, "user", usage)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	emit, err := core.EmitSeedAccessors(dir, featureID, lang, out)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if agentMode {
+		fmt.Printf("seed emit: %s -> %s lang=%s files=%d\n", featureID, emit.Path, emit.Lang, len(emit.Files))
+		for _, f := range emit.Files {
+			fmt.Printf("const: %s = %s\n", f.Const, f.File)
+		}
+	} else {
+		fmt.Printf("Wrote %s with %d seed accessor(s) for feature %s\n", emit.Path, len(emit.Files), featureID)
+		for _, f := range emit.Files {
+			fmt.Printf("  %-20s %s\n", f.Const, f.File)
+		}
+		fmt.Println("Re-run after changing the seed manifest.")
+	}
+	return 0
+}
+
+// runSeedSnapshot handles:
+//
+//	ptsd seed snapshot <feature> <file> [--type data|fixture] -- <command...>
+//	ptsd seed snapshot <feature> <file> [--type data|fixture] --request <name>
+func runSeedSnapshot(args []string, agentMode bool) int {
+	const usage = "usage: ptsd seed snapshot <feature> <file> [--type data|fixture] (-- <command...> | --request <name>)"
+	var positional []string
+	opts := core.SeedSnapshotOptions{}
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--":
+			opts.Command = strings.Join(args[i+1:], " ")
+			i = len(args)
+		case (a == "--type" || a == "--request") && i+1 < len(args):
+			if a == "--type" {
+				opts.Type = args[i+1]
+			} else {
+				opts.Request = args[i+1]
+			}
+			i++
+		case strings.HasPrefix(a, "--"):
+			return renderError(agentMode, "user", usage)
+		default:
+			positional = append(positional, a)
+		}
+	}
+	if len(positional) != 2 {
+		return renderError(agentMode, "user", usage)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	snap, err := core.SnapshotSeed(dir, positional[0], positional[1], opts)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if agentMode {
+		fmt.Printf("seed snapshot: %s -> %s bytes=%d sha256=%s\n", snap.Path, positional[0], snap.Bytes, snap.Hash)
+	} else {
+		fmt.Printf("Captured %s (%d bytes, sha256 %s) for feature %s\n", snap.Path, snap.Bytes, snap.Hash[:12], positional[0])
+	}
+	return 0
+}
+
+// RunBdd handles: ptsd bdd add <feature> | ptsd bdd list [feature] | ptsd bdd ids | ptsd bdd diff <feature>
 func RunBdd(args []string, agentMode bool) int {
 	if len(args) == 0 {
-		fmt.Fprintln(os.Stderr, "err:user usage: ptsd bdd <add|list> ...")
+		fmt.Fprintln(os.Stderr, "err:user usage: ptsd bdd <add|list|ids|diff> ...")
 		return 2
 	}
 	switch args[0] {
@@ -153,6 +595,21 @@ func RunBdd(args []string, agentMode bool) int {
 			fmt.Printf("BDD scaffold created for feature %s\n", featureID)
 		}
 		return 0
+	case "ids":
+		dir, err := os.Getwd()
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		n, err := core.AnnotateScenarioIDs(dir)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		if agentMode {
+			fmt.Printf("bdd ids annotated=%d\n", n)
+		} else {
+			fmt.Printf("Annotated %d scenario(s) with @id tags\n", n)
+		}
+		return 0
 	case "list":
 		featureID := ""
 		if len(args) >= 2 {
@@ -170,37 +627,224 @@ func RunBdd(args []string, agentMode bool) int {
 			fmt.Println(l)
 		}
 		return 0
+	case "diff":
+		return runBddDiff(args[1:], agentMode)
 	default:
 		fmt.Fprintf(os.Stderr, "err:user unknown bdd subcommand: %s\n", args[0])
 		return 2
 	}
 }
 
-// RunTest handles: ptsd test run [feature] | ptsd test map <bdd-file> <test-file>
+type bddDiffJSON struct {
+	Feature    string              `json:"feature"`
+	Base       string              `json:"base"`
+	ReviewedAt string              `json:"reviewed_at"`
+	Changes    []bddScenarioChange `json:"changes"`
+	Unchanged  int                 `json:"unchanged"`
+}
+
+type bddScenarioChange struct {
+	Kind     string   `json:"kind"`
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	OldTitle string   `json:"old_title,omitempty"`
+	Fields   []string `json:"fields"`
+}
+
+// runBddDiff handles: bdd diff <feature> [--json]. It lists the scenarios
+// added, removed or modified since the feature's last bdd review.
+func runBddDiff(args []string, agentMode bool) int {
+	jsonOut := jsonOutput
+	featureID := ""
+	for _, a := range args {
+		switch {
+		case a == "--json":
+			jsonOut = true
+		case strings.HasPrefix(a, "-"):
+			return usageError(agentMode, "bdd diff", "unknown flag "+a)
+		case featureID == "":
+			featureID = a
+		default:
+			return usageError(agentMode, "bdd diff", "expected one feature")
+		}
+	}
+	if featureID == "" {
+		return usageError(agentMode, "bdd diff", "usage: ptsd bdd diff <feature> [--json]")
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	diff, err := core.DiffBDD(dir, featureID)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	counts := map[string]int{}
+	for _, c := range diff.Changes {
+		counts[c.Kind]++
+	}
+
+	if jsonOut {
+		out := bddDiffJSON{
+			Feature:    diff.Feature,
+			Base:       diff.Base,
+			ReviewedAt: diff.ReviewedAt.UTC().Format(time.RFC3339),
+			Changes:    []bddScenarioChange{},
+			Unchanged:  diff.Unchanged,
+		}
+		for _, c := range diff.Changes {
+			out.Changes = append(out.Changes, bddScenarioChange{Kind: c.Kind, ID: c.ID, Title: c.Title, OldTitle: c.OldTitle, Fields: nonNil(c.Fields)})
+		}
+		return printJSON(agentMode, "bdd.diff", out)
+	}
+	if agentMode {
+		for _, c := range diff.Changes {
+			switch {
+			case c.OldTitle != "":
+				fmt.Printf("%s: %s fields=%s title=%q was=%q\n", c.Kind, c.ID, strings.Join(c.Fields, ","), c.Title, c.OldTitle)
+			case len(c.Fields) > 0:
+				fmt.Printf("%s: %s fields=%s title=%q\n", c.Kind, c.ID, strings.Join(c.Fields, ","), c.Title)
+			default:
+				fmt.Printf("%s: %s title=%q\n", c.Kind, c.ID, c.Title)
+			}
+		}
+		fmt.Printf("bdd diff: %s base=%s added=%d removed=%d modified=%d unchanged=%d\n",
+			diff.Feature, diff.Base, counts["added"], counts["removed"], counts["modified"], diff.Unchanged)
+		return 0
+	}
+	base := "the stored copy"
+	if strings.HasPrefix(diff.Base, "git:") {
+		base = "commit " + strings.TrimPrefix(diff.Base, "git:")
+	}
+	fmt.Printf("BDD changes for %s since its review on %s (compared with %s):\n",
+		diff.Feature, diff.ReviewedAt.Local().Format("2006-01-02 15:04"), base)
+	if len(diff.Changes) == 0 {
+		fmt.Printf("  none (%d scenario(s) unchanged)\n", diff.Unchanged)
+		return 0
+	}
+	for _, c := range diff.Changes {
+		line := fmt.Sprintf("  %-8s %s", c.Kind, c.Title)
+		if c.OldTitle != "" {
+			line += fmt.Sprintf(" (was %q)", c.OldTitle)
+		}
+		if len(c.Fields) > 0 {
+			line += " [" + strings.Join(c.Fields, ", ") + "]"
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("%d added, %d removed, %d modified, %d unchanged\n", counts["added"], counts["removed"], counts["modified"], diff.Unchanged)
+	return 0
+}
+
+type testRunJSON struct {
+	Total        int               `json:"total"`
+	Passed       int               `json:"passed"`
+	Failed       int               `json:"failed"`
+	Failures     []string          `json:"failures"`
+	StoppedAfter int               `json:"stopped_after"`
+	Features     []featureTestJSON `json:"features"`
+	DurationMS   int64             `json:"duration_ms"`
+	Cases        []testCaseJSON    `json:"cases"`
+}
+
+type testCaseJSON struct {
+	Name       string `json:"name"`
+	File       string `json:"file"`
+	Status     string `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+	Message    string `json:"message,omitempty"`
+}
+
+type featureTestJSON struct {
+	Feature  string   `json:"feature"`
+	Total    int      `json:"total"`
+	Passed   int      `json:"passed"`
+	Failed   int      `json:"failed"`
+	Failures []string `json:"failures"`
+}
+
+// RunTest handles: ptsd test run [feature] [--fail-fast] [--parallel N] [--progress jsonl] | ptsd test map <bdd-file> <test-file> [--scenario <id> [--func <name>]] | ptsd test coverage [feature] [--json]
 func RunTest(args []string, agentMode bool) int {
 	if len(args) == 0 {
-		fmt.Fprintln(os.Stderr, "err:user usage: ptsd test <run|map> ...")
+		fmt.Fprintln(os.Stderr, "err:user usage: ptsd test <run|map|coverage|unmapped> ...")
 		return 2
 	}
 	switch args[0] {
 	case "run":
+		runArgs, progress, err := parseProgressFlag(args[1:])
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		opts := core.TestRunOptions{Progress: progress}
 		featureFilter := ""
-		if len(args) >= 2 {
-			featureFilter = args[1]
+		for i := 0; i < len(runArgs); i++ {
+			a := runArgs[i]
+			switch {
+			case a == "--fail-fast":
+				opts.MaxFailures = 1
+			case a == "--parallel":
+				if i+1 >= len(runArgs) {
+					return usageError(agentMode, "test run", "--parallel requires a process count")
+				}
+				n, err := strconv.Atoi(runArgs[i+1])
+				if err != nil || n < 1 {
+					return usageError(agentMode, "test run", fmt.Sprintf("invalid --parallel %q: must be a positive integer", runArgs[i+1]))
+				}
+				opts.Parallel = n
+				i++
+			case strings.HasPrefix(a, "-"):
+				return usageError(agentMode, "test run", "unknown flag "+a)
+			case featureFilter == "":
+				featureFilter = a
+			}
 		}
 		dir, err := os.Getwd()
 		if err != nil {
 			return coreError(agentMode, err)
 		}
-		results, err := core.RunTests(dir, featureFilter)
+		results, err := core.RunTestsWithOptions(dir, featureFilter, opts)
 		if err != nil {
 			return coreError(agentMode, err)
 		}
+		if jsonOutput {
+			out := testRunJSON{
+				Total: results.Total, Passed: results.Passed, Failed: results.Failed,
+				Failures: nonNil(results.Failures), StoppedAfter: results.StoppedAfter,
+				Features: []featureTestJSON{}, DurationMS: results.Duration.Milliseconds(), Cases: []testCaseJSON{},
+			}
+			for _, c := range results.Cases {
+				out.Cases = append(out.Cases, testCaseJSON{
+					Name: c.Name, File: c.File, Status: c.Status, DurationMS: c.Duration.Milliseconds(), Message: c.Message,
+				})
+			}
+			for _, f := range results.Features {
+				out.Features = append(out.Features, featureTestJSON{
+					Feature: f.Feature, Total: f.Total, Passed: f.Passed, Failed: f.Failed, Failures: nonNil(f.Failures),
+				})
+			}
+			printJSON(agentMode, "test.run", out)
+			if results.Failed > 0 {
+				return 5
+			}
+			return 0
+		}
 		view := render.TestResultsView{
-			Total:    results.Total,
-			Passed:   results.Passed,
-			Failed:   results.Failed,
-			Failures: results.Failures,
+			Total:        results.Total,
+			Passed:       results.Passed,
+			Failed:       results.Failed,
+			Failures:     results.Failures,
+			StoppedAfter: results.StoppedAfter,
+		}
+		for _, f := range results.Features {
+			view.Features = append(view.Features, render.FeatureTestView{Feature: f.Feature, Passed: f.Passed, Failed: f.Failed})
+		}
+		if results.Duration > 0 {
+			view.Duration = results.Duration.Round(time.Millisecond).String()
+		}
+		for _, c := range results.Cases {
+			if c.Status == "failed" {
+				view.FailureDetails = append(view.FailureDetails, render.TestFailureView{Name: c.Name, File: c.File, Message: c.Message})
+			}
 		}
 		r := newRenderer(agentMode)
 		fmt.Println(r.RenderTestResults(view))
@@ -209,28 +853,239 @@ func RunTest(args []string, agentMode bool) int {
 		}
 		return 0
 	case "map":
-		if len(args) < 3 {
-			fmt.Fprintln(os.Stderr, "err:user usage: ptsd test map <bdd-file> <test-file>")
+		scenario, testFunc := "", ""
+		var positional []string
+		for i := 1; i < len(args); i++ {
+			if (args[i] == "--scenario" || args[i] == "--func") && i+1 < len(args) {
+				if args[i] == "--scenario" {
+					scenario = args[i+1]
+				} else {
+					testFunc = args[i+1]
+				}
+				i++
+				continue
+			}
+			positional = append(positional, args[i])
+		}
+		if len(positional) < 2 {
+			fmt.Fprintln(os.Stderr, "err:user usage: ptsd test map <bdd-file> <test-file> [--scenario <id|title> [--func <test-function>]]")
 			return 2
 		}
-		bddFile := args[1]
-		testFile := args[2]
+		bddFile := positional[0]
+		testFile := positional[1]
 		dir, err := os.Getwd()
 		if err != nil {
 			return coreError(agentMode, err)
 		}
-		err = core.MapTest(dir, bddFile, testFile)
+		mapping, err := core.MapScenarioTestFunc(dir, bddFile, scenario, testFile, testFunc)
 		if err != nil {
 			return coreError(agentMode, err)
 		}
+		from, to, _ := strings.Cut(mapping, "::")
 		if agentMode {
-			fmt.Printf("mapped: %s -> %s\n", bddFile, testFile)
+			fmt.Printf("mapped: %s -> %s\n", from, to)
 		} else {
-			fmt.Printf("Mapped %s to %s\n", bddFile, testFile)
+			fmt.Printf("Mapped %s to %s\n", from, to)
+		}
+		return 0
+	case "coverage":
+		return runTestCoverage(args[1:], agentMode)
+	case "match":
+		return runTestMatch(args[1:], agentMode)
+	case "unmapped":
+		jsonOut := jsonOutput
+		for _, a := range args[1:] {
+			if a != "--json" {
+				return usageError(agentMode, "test unmapped", "unknown flag "+a)
+			}
+			jsonOut = true
+		}
+		dir, err := os.Getwd()
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		files, err := core.UnmappedTests(dir)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		if jsonOut {
+			return printJSON(agentMode, "test.unmapped", nonNil(files))
+		}
+		if agentMode {
+			for _, f := range files {
+				fmt.Printf("unmapped: %s\n", f)
+			}
+			fmt.Printf("unmapped count=%d\n", len(files))
+			return 0
+		}
+		if len(files) == 0 {
+			fmt.Println("No unmapped test files.")
+			return 0
 		}
+		fmt.Printf("%d test file(s) found by adopt are not mapped to a feature:\n", len(files))
+		for _, f := range files {
+			fmt.Printf("  %s\n", f)
+		}
+		fmt.Println("Map one with: ptsd test map <bdd-file> <test-file>")
 		return 0
 	default:
 		fmt.Fprintf(os.Stderr, "err:user unknown test subcommand: %s\n", args[0])
 		return 2
 	}
 }
+
+// runTestCoverage handles: ptsd test coverage [feature] [--json]. It lists,
+// per feature file, the scenarios no scenario- or function-level mapping
+// covers.
+func runTestCoverage(args []string, agentMode bool) int {
+	jsonOut := jsonOutput
+	featureID := ""
+	for _, a := range args {
+		switch {
+		case a == "--json":
+			jsonOut = true
+		case strings.HasPrefix(a, "-") || featureID != "":
+			return usageError(agentMode, "test coverage", "usage: ptsd test coverage [feature] [--json]")
+		default:
+			featureID = a
+		}
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	coverage, err := core.CheckTestCoverage(dir)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if featureID != "" {
+		coverage = slices.DeleteFunc(coverage, func(c core.CoverageEntry) bool { return c.Feature != featureID })
+		if len(coverage) == 0 {
+			return renderError(agentMode, "validation", "no BDD feature file for "+featureID)
+		}
+	}
+
+	if jsonOut {
+		type entryJSON struct {
+			Feature   string   `json:"feature"`
+			Status    string   `json:"status"`
+			Scenarios int      `json:"scenarios"`
+			Covered   int      `json:"covered"`
+			Uncovered []string `json:"uncovered"`
+		}
+		out := make([]entryJSON, 0, len(coverage))
+		for _, c := range coverage {
+			out = append(out, entryJSON{c.Feature, c.Status, c.Scenarios, c.Covered, nonNil(c.Uncovered)})
+		}
+		return printJSON(agentMode, "test.coverage", out)
+	}
+	for _, c := range coverage {
+		if agentMode {
+			fmt.Printf("coverage: %s status=%s scenarios=%d covered=%d\n", c.Feature, c.Status, c.Scenarios, c.Covered)
+			for _, title := range c.Uncovered {
+				fmt.Printf("uncovered: %s %q\n", c.Feature, title)
+			}
+			continue
+		}
+		fmt.Printf("%s: %d/%d scenarios covered (%s)\n", c.Feature, c.Covered, c.Scenarios, c.Status)
+		for _, title := range c.Uncovered {
+			fmt.Printf("  - %s\n", title)
+		}
+	}
+	if !agentMode && len(coverage) == 0 {
+		fmt.Println("No BDD feature files.")
+	}
+	return 0
+}
+
+// runTestMatch handles: ptsd test match <feature> [--min <0-1>] [--dry-run]
+// [--json]. It maps uncovered scenarios to the test functions whose names
+// resemble their titles and lists the scenarios nothing matched.
+func runTestMatch(args []string, agentMode bool) int {
+	const usage = "usage: ptsd test match <feature> [--min <0-1>] [--dry-run] [--json]"
+	jsonOut := jsonOutput
+	dryRun := false
+	minConfidence := core.TestMatchThreshold
+	featureID := ""
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--json":
+			jsonOut = true
+		case a == "--dry-run":
+			dryRun = true
+		case a == "--min" && i+1 < len(args):
+			v, err := strconv.ParseFloat(args[i+1], 64)
+			if err != nil || v < 0 || v > 1 {
+				return usageError(agentMode, "test match", "--min must be between 0 and 1, got "+args[i+1])
+			}
+			minConfidence = v
+			i++
+		case strings.HasPrefix(a, "-") || featureID != "":
+			return usageError(agentMode, "test match", usage)
+		default:
+			featureID = a
+		}
+	}
+	if featureID == "" {
+		return usageError(agentMode, "test match", usage)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	result, err := core.MatchTests(dir, featureID, minConfidence, dryRun)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	if jsonOut {
+		type matchJSON struct {
+			Scenario   string  `json:"scenario"`
+			Title      string  `json:"title"`
+			File       string  `json:"file"`
+			Func       string  `json:"func"`
+			Confidence float64 `json:"confidence"`
+			Mapped     bool    `json:"mapped"`
+		}
+		out := struct {
+			Feature   string      `json:"feature"`
+			Matches   []matchJSON `json:"matches"`
+			Unmatched []string    `json:"unmatched"`
+		}{Feature: result.Feature, Matches: []matchJSON{}, Unmatched: nonNil(result.Unmatched)}
+		for _, m := range result.Matches {
+			out.Matches = append(out.Matches, matchJSON{m.Scenario, m.Title, m.File, m.Func, m.Confidence, m.Mapped})
+		}
+		return printJSON(agentMode, "test.match", out)
+	}
+	if agentMode {
+		for _, m := range result.Matches {
+			verdict := "candidate"
+			if m.Mapped {
+				verdict = "mapped"
+			}
+			fmt.Printf("match: %s %q -> %s::%s confidence=%.2f %s\n", featureID, m.Title, m.File, m.Func, m.Confidence, verdict)
+		}
+		for _, title := range result.Unmatched {
+			fmt.Printf("unmatched: %s %q\n", featureID, title)
+		}
+		fmt.Printf("test match: %s matched=%d unmatched=%d\n", featureID, len(result.Matches), len(result.Unmatched))
+		return 0
+	}
+	verb := "Mapped"
+	if dryRun {
+		verb = "Would map"
+	}
+	for _, m := range result.Matches {
+		fmt.Printf("%s %q to %s::%s (%.0f%% confidence)\n", verb, m.Title, m.File, m.Func, m.Confidence*100)
+	}
+	if len(result.Matches) == 0 {
+		fmt.Println("No test matches an uncovered scenario.")
+	}
+	if len(result.Unmatched) > 0 {
+		fmt.Printf("%d scenario(s) still need a test:\n", len(result.Unmatched))
+		for _, title := range result.Unmatched {
+			fmt.Printf("  - %s\n", title)
+		}
+	}
+	return 0
+}
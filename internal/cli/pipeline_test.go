@@ -109,6 +109,45 @@ func TestRunPrdCheckMissingAnchor(t *testing.T) {
 	}
 }
 
+func TestRunPrdImportProposeThenAccept(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+
+	spec := filepath.Join(dir, "spec.md")
+	if err := os.WriteFile(spec, []byte("## Search Index\nFast.\n\n## My Feat\nTaken.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		code = RunPrd([]string{"import", spec}, true)
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	if !strings.Contains(out, `proposed: search-index lines:1-2 title:"Search Index"`) ||
+		!strings.Contains(out, `proposed: my-feat lines:4-5 title:"My Feat" conflict:"feature already registered"`) {
+		t.Errorf("unexpected proposals:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ptsd", "docs", "PRD.md")); err == nil {
+		t.Fatal("proposing must not write PRD.md")
+	}
+
+	out = captureStdout(t, func() {
+		code = RunPrd([]string{"import", spec, "--rename", "search-index=search", "--accept", "all"}, true)
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, out)
+	}
+	if !strings.Contains(out, "imported: search") || !strings.Contains(out, "prd import added=1") {
+		t.Errorf("unexpected import output:\n%s", out)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "docs", "PRD.md"))
+	if !strings.Contains(string(data), "<!-- feature:search -->\n### Search Index") {
+		t.Errorf("PRD.md missing imported section:\n%s", data)
+	}
+}
+
 func TestRunPrdCheckNoPRDFile(t *testing.T) {
 	dir := setupPipelineProject(t)
 	chdirTo(t, dir)
@@ -397,6 +436,30 @@ func TestRunTestMapBDDNotFound(t *testing.T) {
 	}
 }
 
+func TestRunTestUnmapped(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+
+	os.WriteFile(filepath.Join(dir, "test_login.py"), []byte("def test_x(): pass\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte("features: {}\nunmapped:\n  - test_login.py\n  - gone.spec.js\n"), 0644)
+
+	out := captureStdout(t, func() {
+		if code := RunTest([]string{"unmapped"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if out != "unmapped: test_login.py\nunmapped count=1\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	out = captureStdout(t, func() {
+		RunTest([]string{"unmapped", "--json"}, true)
+	})
+	if !strings.Contains(out, `"test_login.py"`) {
+		t.Errorf("expected JSON list, got %q", out)
+	}
+}
+
 func TestRunTestRunAllTests(t *testing.T) {
 	dir := setupPipelineProject(t)
 	chdirTo(t, dir)
@@ -510,6 +573,14 @@ func TestRunTestRunWithFailingTests(t *testing.T) {
 	}
 }
 
+func TestRunTestRunParallelInvalidCount(t *testing.T) {
+	for _, args := range [][]string{{"run", "--parallel"}, {"run", "--parallel", "0"}, {"run", "--parallel", "x"}} {
+		if code := RunTest(args, true); code != 2 {
+			t.Errorf("%v: expected exit 2, got %d", args, code)
+		}
+	}
+}
+
 // --- Issue 1: Orphaned anchor test ---
 
 func TestRunPrdCheckOrphanedAnchor(t *testing.T) {
@@ -827,3 +898,315 @@ Catalog section
 		t.Errorf("expected error in output, got: %s", out)
 	}
 }
+
+func TestRunTraceReportsGaps(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+
+	prd := "# PRD\n<!-- feature:my-feat -->\nAcceptance criteria:\n- Saves the draft\n- Rejects empty input\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "docs", "PRD.md"), []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bdd := "@feature:my-feat\nFeature: My\n  @id:save @ac:AC-1\n  Scenario: Save\n    Given a draft\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "my-feat.feature"), []byte(bdd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		code = RunTrace([]string{"my-feat"}, true)
+	})
+	if code != 1 {
+		t.Errorf("expected exit 1 with an uncovered criterion, got %d", code)
+	}
+	for _, want := range []string{
+		"trace my-feat criteria=2 uncovered=1 scenarios=1 untraced=0",
+		`ac:AC-1 save "Saves the draft"`,
+		`ac:AC-2 uncovered "Rejects empty input"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunPrdIndexSplitFiles(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+	docs := filepath.Join(dir, ".ptsd", "docs")
+	if err := os.WriteFile(filepath.Join(docs, "PRD.md"), []byte("# PRD\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(docs, "core.md"), []byte("# Core\n\n<!-- feature:my-feat -->\nSection\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if code := RunPrd([]string{"index"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "anchor: my-feat .ptsd/docs/core.md:3") || !strings.Contains(out, "anchors count=1") {
+		t.Errorf("unexpected index output: %s", out)
+	}
+
+	if code := RunPrd([]string{"check"}, true); code != 0 {
+		t.Errorf("expected prd check to find the anchor in core.md, got exit %d", code)
+	}
+
+	out = captureStdout(t, func() {
+		if code := RunPrd([]string{"show", "my-feat"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "file:.ptsd/docs/core.md") {
+		t.Errorf("expected owning file in show output, got: %s", out)
+	}
+}
+
+func TestRunBddDiff(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+	bddPath := filepath.Join(dir, ".ptsd", "bdd", "my-feat.feature")
+	reviewed := "Feature: Mine\n\n  Scenario: Create\n    Given nothing\n    Then a thing\n\n  Scenario: Delete\n    Given a thing\n    Then nothing\n"
+	if err := os.WriteFile(bddPath, []byte(reviewed), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := RunBdd([]string{"diff", "my-feat"}, true); code != 1 {
+		t.Errorf("expected exit 1 (pipeline) before any bdd review, got %d", code)
+	}
+	captureStdout(t, func() {
+		if code := RunReview([]string{"my-feat", "bdd", "8"}, true); code != 0 {
+			t.Fatalf("review failed with exit %d", code)
+		}
+	})
+
+	changed := "Feature: Mine\n\n  Scenario: Create\n    Given nothing\n    Then two things\n\n  Scenario: Update\n    Given a thing\n    Then a new thing\n"
+	if err := os.WriteFile(bddPath, []byte(changed), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := captureStdout(t, func() {
+		if code := RunBdd([]string{"diff", "my-feat"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	for _, want := range []string{
+		`modified: sc-`, `fields=steps title="Create"`,
+		`added: sc-`, `title="Update"`,
+		`removed: sc-`, `title="Delete"`,
+		"bdd diff: my-feat base=stored added=1 removed=1 modified=1 unchanged=0",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+
+	if code := RunBdd([]string{"diff"}, true); code != 2 {
+		t.Errorf("expected exit 2 without a feature, got %d", code)
+	}
+}
+
+func TestRunPrdShowRaw(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+	prd := "# PRD\n<!-- feature:my-feat -->\n### Mine\n\nDoes things.\n\n## Appendix\nOther.\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "docs", "PRD.md"), []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if code := RunPrd([]string{"show", "my-feat", "--raw"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if out != "### Mine\n\nDoes things.\n" {
+		t.Errorf("unexpected raw section: %q", out)
+	}
+
+	if code := RunPrd([]string{"show", "unknown", "--raw"}, true); code != 1 {
+		t.Errorf("expected exit 1 for a feature without an anchor, got %d", code)
+	}
+}
+
+func TestRunSeedEmit(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+	seedDir := filepath.Join(dir, ".ptsd", "seeds", "my-feat")
+	if err := os.MkdirAll(seedDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "feature: my-feat\nfiles:\n  - path: orders.yaml\n    type: data\n"
+	if err := os.WriteFile(filepath.Join(seedDir, "seed.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if code := RunSeed([]string{"emit", "my-feat", "--lang", "py"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "seed emit: my-feat -> tests/seeds/my_feat.py lang=py files=1") || !strings.Contains(out, "const: ORDERS_YAML = orders.yaml") {
+		t.Errorf("unexpected output: %s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tests", "seeds", "my_feat.py")); err != nil {
+		t.Errorf("expected generated module: %v", err)
+	}
+
+	if code := RunSeed([]string{"emit", "my-feat"}, true); code != 2 {
+		t.Errorf("expected exit 2 without --lang, got %d", code)
+	}
+}
+
+func TestRunSeedValidate(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+	seedDir := filepath.Join(dir, ".ptsd", "seeds", "my-feat")
+	if err := os.MkdirAll(seedDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "feature: my-feat\nfiles:\n  - path: orders.json\n    type: data\n"
+	if err := os.WriteFile(filepath.Join(seedDir, "seed.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(seedDir, "orders.json"), []byte(`[{"id": 1}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if code := RunSeed([]string{"validate", "my-feat"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "seed validate: features=1 files=1 problems=0") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	if err := os.WriteFile(filepath.Join(seedDir, "orders.json"), []byte(`[{"id": 1},]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stderr := captureStderr(t, func() {
+		captureStdout(t, func() {
+			if code := RunSeed([]string{"validate"}, true); code != 1 {
+				t.Errorf("expected exit 1, got %d", code)
+			}
+		})
+	})
+	if !strings.Contains(stderr, "err:validation seed my-feat/orders.json: invalid JSON") {
+		t.Errorf("unexpected stderr: %s", stderr)
+	}
+}
+
+func TestRunSeedScaffold(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+	seedDir := filepath.Join(dir, ".ptsd", "seeds", "my-feat")
+	if err := os.MkdirAll(seedDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(seedDir, "seed.yaml"), []byte("feature: my-feat\nfiles:\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src := "export interface Order {\n  id: number;\n  email: string;\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "order.ts"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if code := RunSeed([]string{"scaffold", "my-feat", "--from", "order.ts"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "scaffold: order.json type=Order fields=2") || !strings.Contains(out, "seed scaffold: my-feat from=order.ts files=1 skipped=0") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	out = captureStdout(t, func() {
+		RunSeed([]string{"scaffold", "my-feat", "--from", "order.ts"}, true)
+	})
+	if !strings.Contains(out, "skipped: order.json type=Order exists") {
+		t.Errorf("expected skip on re-run, got: %s", out)
+	}
+
+	if code := RunSeed([]string{"scaffold", "my-feat"}, true); code != 2 {
+		t.Errorf("expected exit 2 without --from, got %d", code)
+	}
+}
+
+func TestRunTestMapFuncAndCoverage(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+	bdd := "@feature:my-feat\nFeature: My feat\n  @id:save\n  Scenario: Save draft\n  @id:publish\n  Scenario: Publish post\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "my-feat.feature"), []byte(bdd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "post_test.go"), []byte("package post\n\nfunc TestSaveDraft(t *testing.T) {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if code := RunTest([]string{"map", ".ptsd/bdd/my-feat.feature", "post_test.go", "--scenario", "Save draft", "--func", "TestSaveDraft"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if out != "mapped: .ptsd/bdd/my-feat.feature#save -> post_test.go::TestSaveDraft\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	out = captureStdout(t, func() {
+		if code := RunTest([]string{"coverage", "my-feat"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if out != "coverage: my-feat status=partial scenarios=2 covered=1\nuncovered: my-feat \"Publish post\"\n" {
+		t.Errorf("unexpected coverage output: %q", out)
+	}
+
+	out = captureStdout(t, func() {
+		RunTest([]string{"coverage", "--json"}, true)
+	})
+	if !strings.Contains(out, `"uncovered": [`) || !strings.Contains(out, `"Publish post"`) {
+		t.Errorf("unexpected JSON: %s", out)
+	}
+}
+
+func TestRunTestMatch(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+	bdd := "@feature:my-feat\nFeature: My feat\n  @id:save\n  Scenario: Save draft\n  @id:publish\n  Scenario: Publish post\n  @id:archive\n  Scenario: Archive old posts\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "my-feat.feature"), []byte(bdd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "post_test.go"), []byte("package post\n\nfunc TestSaveDraft(t *testing.T) {}\n\nfunc TestPublishPost(t *testing.T) {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	captureStdout(t, func() { RunTest([]string{"map", ".ptsd/bdd/my-feat.feature", "post_test.go"}, true) })
+
+	out := captureStdout(t, func() {
+		if code := RunTest([]string{"match", "my-feat", "--dry-run"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	want := "match: my-feat \"Save draft\" -> post_test.go::TestSaveDraft confidence=1.00 candidate\n" +
+		"match: my-feat \"Publish post\" -> post_test.go::TestPublishPost confidence=1.00 candidate\n" +
+		"unmatched: my-feat \"Archive old posts\"\n" +
+		"test match: my-feat matched=2 unmatched=1\n"
+	if out != want {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	captureStdout(t, func() { RunTest([]string{"match", "my-feat"}, true) })
+	out = captureStdout(t, func() { RunTest([]string{"coverage", "my-feat"}, true) })
+	if !strings.HasPrefix(out, "coverage: my-feat status=partial scenarios=3 covered=2\n") {
+		t.Errorf("expected matches to be mapped, got %q", out)
+	}
+
+	errOut := captureStderr(t, func() {
+		if code := RunTest([]string{"match", "my-feat", "--min", "2"}, true); code != 2 {
+			t.Errorf("expected exit 2, got %d", code)
+		}
+	})
+	if !strings.Contains(errOut, "--min must be between 0 and 1") {
+		t.Errorf("unexpected stderr: %q", errOut)
+	}
+}
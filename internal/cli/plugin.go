@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunPlugin dispatches an unknown command to the ptsd-<cmd> executable on
+// PATH (see core.RunPlugin). found is false when there is none.
+func RunPlugin(cmd string, args []string, agentMode bool) (code int, found bool) {
+	path, ok := core.FindPlugin(cmd)
+	if !ok {
+		return 0, false
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err), true
+	}
+	ctx := core.NewPluginContext(dir, buildVersion(), cmd, args, agentMode)
+	code, err = core.RunPlugin(path, dir, ctx, os.Stdout, os.Stderr)
+	if err != nil {
+		return coreError(agentMode, err), true
+	}
+	return code, true
+}
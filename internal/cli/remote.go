@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunRemote handles `ptsd remote <push|pull>` — syncing state and the event log
+// with the object store configured under remote.url.
+func RunRemote(args []string, agentMode bool) int {
+	if len(args) == 0 {
+		return usageError(agentMode, "remote", "subcommand required: push|pull")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	var result core.RemoteResult
+	switch args[0] {
+	case "push":
+		result, err = core.RemotePush(cwd)
+	case "pull":
+		result, err = core.RemotePull(cwd)
+	default:
+		return usageError(agentMode, "remote", fmt.Sprintf("unknown subcommand %q: use push|pull", args[0]))
+	}
+
+	printRemoteResult(result)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	return 0
+}
+
+func printRemoteResult(r core.RemoteResult) {
+	if len(r.Pushed) > 0 {
+		fmt.Printf("pushed: %s\n", strings.Join(r.Pushed, ","))
+	}
+	if len(r.Pulled) > 0 {
+		fmt.Printf("pulled: %s\n", strings.Join(r.Pulled, ","))
+	}
+	if len(r.Pushed) == 0 && len(r.Pulled) == 0 {
+		fmt.Println("up to date")
+	}
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunRemote_NoSubcommand(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	withDir(t, dir, func() {
+		if code := RunRemote([]string{}, true); code != 2 {
+			t.Errorf("expected exit 2, got %d", code)
+		}
+	})
+}
+
+func TestRunRemote_NotConfigured(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("project:\n  name: X\n"), 0644)
+	withDir(t, dir, func() {
+		if code := RunRemote([]string{"push"}, true); code != 3 {
+			t.Errorf("expected exit 3 (config), got %d", code)
+		}
+	})
+}
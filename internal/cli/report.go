@@ -0,0 +1,357 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunReport handles the `ptsd report` command.
+// Subcommands:
+//
+//	ptsd report weekly [--days N]
+//	ptsd report durations [--stuck-days N] [--json]
+//	ptsd report trace [feature...] [--json]
+func RunReport(args []string, agentMode bool) int {
+	if len(args) == 0 {
+		return renderError(agentMode, "user", "usage: ptsd report weekly [--days N] | ptsd report durations [--stuck-days N] [--json] | ptsd report trace [feature...] [--json]")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	switch args[0] {
+	case "weekly":
+		return runReportWeekly(args[1:], cwd, agentMode)
+	case "durations":
+		return runReportDurations(args[1:], cwd, agentMode)
+	case "trace":
+		return runReportTrace(args[1:], cwd, agentMode)
+	default:
+		return renderError(agentMode, "user", "unknown subcommand: "+args[0])
+	}
+}
+
+func runReportWeekly(args []string, cwd string, agentMode bool) int {
+	days := 7
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--days" {
+			if i+1 >= len(args) {
+				return renderError(agentMode, "user", "--days requires a numeric value")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				return renderError(agentMode, "user", fmt.Sprintf("invalid --days value %q: must be a positive integer", args[i+1]))
+			}
+			days = n
+			i++
+		}
+	}
+
+	until := time.Now()
+	since := until.AddDate(0, 0, -days)
+	d, err := core.BuildDigest(cwd, since, until)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	fmt.Print(renderDigestMarkdown(d))
+	return 0
+}
+
+type stageDurationJSON struct {
+	Feature string  `json:"feature"`
+	Stage   string  `json:"stage"`
+	Entered string  `json:"entered"`
+	Days    float64 `json:"days"`
+	Current bool    `json:"current"`
+	Stuck   bool    `json:"stuck"`
+	Done    bool    `json:"done"`
+}
+
+func runReportDurations(args []string, cwd string, agentMode bool) int {
+	stuckDays := 7
+	jsonOut := jsonOutput
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--json":
+			jsonOut = true
+		case "--stuck-days":
+			if i+1 >= len(args) {
+				return renderError(agentMode, "user", "--stuck-days requires a numeric value")
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				return renderError(agentMode, "user", fmt.Sprintf("invalid --stuck-days value %q: must be a positive integer", args[i+1]))
+			}
+			stuckDays = n
+			i++
+		}
+	}
+
+	durations, err := core.StageDurations(cwd, time.Now(), time.Duration(stuckDays)*24*time.Hour)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	if jsonOut {
+		out := make([]stageDurationJSON, 0, len(durations))
+		for _, d := range durations {
+			out = append(out, stageDurationJSON{
+				Feature: d.Feature,
+				Stage:   d.Stage,
+				Entered: d.Entered.UTC().Format(time.RFC3339),
+				Days:    durationDays(d.Duration),
+				Current: d.Current,
+				Stuck:   d.Stuck,
+				Done:    d.Done,
+			})
+		}
+		return printJSON(agentMode, "report.durations", out)
+	}
+
+	for _, d := range durations {
+		flag := ""
+		if d.Stuck {
+			flag = "stuck"
+		} else if d.Current {
+			flag = "current"
+		} else if d.Done {
+			flag = "done"
+		}
+		if agentMode {
+			line := fmt.Sprintf("%s stage=%s days=%.1f", d.Feature, d.Stage, durationDays(d.Duration))
+			if flag != "" {
+				line += " " + flag
+			}
+			fmt.Println(line)
+		} else {
+			fmt.Printf("%-30s %-6s %8.1fd  %s\n", d.Feature, d.Stage, durationDays(d.Duration), flag)
+		}
+	}
+	return 0
+}
+
+type featureTraceJSON struct {
+	Feature   string              `json:"feature"`
+	Title     string              `json:"title"`
+	Status    string              `json:"status"`
+	PRD       bool                `json:"prd"`
+	Criteria  []criterionJSON     `json:"criteria"`
+	Scenarios []scenarioTraceJSON `json:"scenarios"`
+	Tests     []testTraceJSON     `json:"tests"`
+	Commits   []commitTraceJSON   `json:"commits"`
+}
+
+type criterionJSON struct {
+	ID        string   `json:"id"`
+	Text      string   `json:"text"`
+	Scenarios []string `json:"scenarios"`
+}
+
+type scenarioTraceJSON struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Criteria []string `json:"criteria"`
+	Tests    []string `json:"tests"`
+}
+
+type testTraceJSON struct {
+	File      string   `json:"file"`
+	Functions []string `json:"functions"`
+}
+
+type commitTraceJSON struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+	Source  string `json:"source"`
+}
+
+func runReportTrace(args []string, cwd string, agentMode bool) int {
+	jsonOut := jsonOutput
+	var ids []string
+	for _, a := range args {
+		if a == "--json" {
+			jsonOut = true
+		} else {
+			ids = append(ids, a)
+		}
+	}
+
+	traces, err := core.BuildTraceability(cwd, ids)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	if !jsonOut {
+		fmt.Print(renderTraceMarkdown(traces))
+		return 0
+	}
+
+	out := make([]featureTraceJSON, 0, len(traces))
+	for _, tr := range traces {
+		ft := featureTraceJSON{
+			Feature:   tr.Feature,
+			Title:     tr.Title,
+			Status:    tr.Status,
+			PRD:       tr.HasPRD,
+			Criteria:  []criterionJSON{},
+			Scenarios: []scenarioTraceJSON{},
+			Tests:     []testTraceJSON{},
+			Commits:   []commitTraceJSON{},
+		}
+		for _, c := range tr.Criteria {
+			ft.Criteria = append(ft.Criteria, criterionJSON{ID: c.ID, Text: c.Text, Scenarios: nonNil(c.Scenarios)})
+		}
+		for _, s := range tr.Scenarios {
+			ft.Scenarios = append(ft.Scenarios, scenarioTraceJSON{ID: s.ID, Title: s.Title, Criteria: nonNil(s.Criteria), Tests: nonNil(s.Tests)})
+		}
+		for _, t := range tr.Tests {
+			ft.Tests = append(ft.Tests, testTraceJSON{File: t.File, Functions: nonNil(t.Functions)})
+		}
+		for _, c := range tr.Commits {
+			ft.Commits = append(ft.Commits, commitTraceJSON{Hash: c.Hash, Subject: c.Subject, Source: c.Source})
+		}
+		out = append(out, ft)
+	}
+	return printJSON(agentMode, "report.trace", out)
+}
+
+// nonNil keeps empty lists as [] rather than null in JSON output.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// renderTraceMarkdown formats traceability for audits: criteria → scenarios
+// → tests → commits, one section per feature.
+func renderTraceMarkdown(traces []core.FeatureTrace) string {
+	var b strings.Builder
+	b.WriteString("# Traceability report\n")
+	for _, tr := range traces {
+		b.WriteString(fmt.Sprintf("\n## `%s` — %s (%s)\n", tr.Feature, tr.Title, tr.Status))
+
+		b.WriteString("\n### Acceptance criteria\n\n")
+		switch {
+		case !tr.HasPRD:
+			b.WriteString("_No PRD section._\n")
+		case len(tr.Criteria) == 0:
+			b.WriteString("_No acceptance criteria listed._\n")
+		default:
+			b.WriteString("| ID | Criterion | Scenarios |\n|---|---|---|\n")
+			for _, c := range tr.Criteria {
+				scenarios := "**uncovered**"
+				if len(c.Scenarios) > 0 {
+					scenarios = "`" + strings.Join(c.Scenarios, "`, `") + "`"
+				}
+				b.WriteString(fmt.Sprintf("| %s | %s | %s |\n", c.ID, c.Text, scenarios))
+			}
+		}
+
+		b.WriteString("\n### Scenarios\n\n")
+		if len(tr.Scenarios) == 0 {
+			b.WriteString("_No BDD scenarios._\n")
+		} else {
+			b.WriteString("| ID | Scenario | Criteria | Tests |\n|---|---|---|---|\n")
+			for _, s := range tr.Scenarios {
+				b.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", s.ID, s.Title, orDash(s.Criteria), orDash(s.Tests)))
+			}
+		}
+
+		b.WriteString("\n### Tests\n\n")
+		if len(tr.Tests) == 0 {
+			b.WriteString("_No mapped tests._\n")
+		}
+		for _, t := range tr.Tests {
+			b.WriteString(fmt.Sprintf("- `%s`", t.File))
+			if len(t.Functions) > 0 {
+				b.WriteString(": " + strings.Join(t.Functions, ", "))
+			}
+			b.WriteString("\n")
+		}
+
+		b.WriteString("\n### Commits\n\n")
+		if len(tr.Commits) == 0 {
+			b.WriteString("_No commits reference this feature._\n")
+		}
+		for _, c := range tr.Commits {
+			hash := c.Hash
+			if len(hash) > 12 {
+				hash = hash[:12]
+			}
+			b.WriteString(fmt.Sprintf("- `%s` %s (%s)\n", hash, c.Subject, c.Source))
+		}
+	}
+	return b.String()
+}
+
+func orDash(items []string) string {
+	if len(items) == 0 {
+		return "—"
+	}
+	return strings.Join(items, ", ")
+}
+
+func durationDays(d time.Duration) float64 {
+	return float64(int(d.Hours()/24*10)) / 10
+}
+
+// renderDigestMarkdown formats a digest as Markdown for posting to team channels.
+func renderDigestMarkdown(d core.Digest) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("## PTSD digest %s — %s\n\n", d.Since.Format("2006-01-02"), d.Until.Format("2006-01-02")))
+
+	b.WriteString(fmt.Sprintf("- Stages advanced: %d\n", len(d.Advanced)))
+	if d.Reviews > 0 {
+		b.WriteString(fmt.Sprintf("- Reviews recorded: %d (avg %.1f)\n", d.Reviews, d.AvgScore))
+	} else {
+		b.WriteString("- Reviews recorded: 0\n")
+	}
+	b.WriteString(fmt.Sprintf("- Tests added: %d\n", d.TestsAdded))
+	b.WriteString(fmt.Sprintf("- Regressions caught: %d\n", len(d.Regressions)))
+	b.WriteString(fmt.Sprintf("- Tasks completed: %d\n", len(d.TasksCompleted)))
+
+	if len(d.Advanced) > 0 {
+		b.WriteString("\n### Features advanced\n\n")
+		for _, e := range d.Advanced {
+			b.WriteString(fmt.Sprintf("- `%s` → %s\n", e.Feature, e.Stage))
+		}
+	}
+
+	if len(d.StageAvgScores) > 0 {
+		b.WriteString("\n### Review scores\n\n| Stage | Avg |\n|---|---|\n")
+		stages := make([]string, 0, len(d.StageAvgScores))
+		for s := range d.StageAvgScores {
+			stages = append(stages, s)
+		}
+		sort.Strings(stages)
+		for _, s := range stages {
+			b.WriteString(fmt.Sprintf("| %s | %.1f |\n", s, d.StageAvgScores[s]))
+		}
+	}
+
+	if len(d.Regressions) > 0 {
+		b.WriteString("\n### Regressions\n\n")
+		for _, e := range d.Regressions {
+			b.WriteString(fmt.Sprintf("- `%s`: %s\n", e.Feature, e.Detail))
+		}
+	}
+
+	if len(d.TasksCompleted) > 0 {
+		b.WriteString("\n### Tasks completed\n\n")
+		for _, e := range d.TasksCompleted {
+			b.WriteString(fmt.Sprintf("- %s (`%s`)\n", e.Task, e.Feature))
+		}
+	}
+
+	return b.String()
+}
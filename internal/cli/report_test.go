@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+func TestRunReport_NoSubcommand(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	withDir(t, dir, func() {
+		if code := RunReport([]string{}, true); code != 2 {
+			t.Errorf("expected exit 2, got %d", code)
+		}
+	})
+}
+
+func TestRunReport_InvalidDays(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	withDir(t, dir, func() {
+		if code := RunReport([]string{"weekly", "--days", "zero"}, true); code != 2 {
+			t.Errorf("expected exit 2, got %d", code)
+		}
+	})
+}
+
+func TestRunReport_Weekly(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	if err := core.AppendEvent(dir, core.Event{Type: core.EventReview, Feature: "my-feat", Stage: "prd", Score: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.AppendEvent(dir, core.Event{Type: core.EventStage, Feature: "my-feat", Stage: "prd"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			code = RunReport([]string{"weekly"}, true)
+		})
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	for _, want := range []string{"## PTSD digest", "Reviews recorded: 1 (avg 9.0)", "`my-feat` → prd", "| prd | 9.0 |"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderDigestMarkdown_Empty(t *testing.T) {
+	now := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	out := renderDigestMarkdown(core.Digest{Since: now.AddDate(0, 0, -7), Until: now})
+	if !strings.Contains(out, "2026-03-02 — 2026-03-09") {
+		t.Errorf("expected date range in header, got:\n%s", out)
+	}
+	if strings.Contains(out, "###") {
+		t.Errorf("expected no detail sections for empty digest, got:\n%s", out)
+	}
+}
+
+func TestRunReport_DurationsJSON(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	entered := time.Now().Add(-10 * 24 * time.Hour)
+	if err := core.AppendEvent(dir, core.Event{At: entered, Type: core.EventStage, Feature: "my-feat", Stage: "seed"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			code = RunReport([]string{"durations", "--json", "--stuck-days", "5"}, true)
+		})
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	for _, want := range []string{`"feature": "my-feat"`, `"stage": "seed"`, `"stuck": true`, `"days": 10`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunReport_DurationsAgent(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	if err := core.AppendEvent(dir, core.Event{At: time.Now().Add(-36 * time.Hour), Type: core.EventStage, Feature: "my-feat", Stage: "bdd"}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			RunReport([]string{"durations"}, true)
+		})
+	})
+	if strings.TrimSpace(out) != "my-feat stage=bdd days=1.5 current" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestRunReport_TraceJSONAndMarkdown(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	if err := os.MkdirAll(filepath.Join(dir, ".ptsd", "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	prd := "<!-- feature:my-feat -->\nAcceptance criteria:\n- Saves drafts\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "docs", "PRD.md"), []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			code = RunReport([]string{"trace", "my-feat", "--json"}, true)
+		})
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	for _, want := range []string{`"feature": "my-feat"`, `"prd": true`, `"id": "AC-1"`, `"scenarios": []`, `"commits": []`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in output, got:\n%s", want, out)
+		}
+	}
+
+	out = captureStdout(t, func() {
+		withDir(t, dir, func() {
+			code = RunReport([]string{"trace"}, false)
+		})
+	})
+	if code != 0 || !strings.Contains(out, "| AC-1 | Saves drafts | **uncovered** |") {
+		t.Errorf("unexpected markdown (exit %d):\n%s", code, out)
+	}
+}
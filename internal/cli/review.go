@@ -2,8 +2,13 @@ package cli
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/veschin/ptsd/internal/core"
 )
@@ -11,8 +16,12 @@ import (
 // RunReview handles the `ptsd review` command.
 // Subcommands:
 //
-//	ptsd review <feature> <stage> <score>
+//	ptsd review <feature> <stage> <score> [--reviewer <name>] [--issue "[severity:] text"]... [--issues-file <json>]
+//	ptsd review <feature> --scores <stage>=<score>,... [--reviewer <name>] [--issue ...]
 //	ptsd review gate <feature> <stage>
+//	ptsd review notes [feature]
+//	ptsd review history <feature> [--stage <stage>] [--json]
+//	ptsd review serve [--port N] [--host H]
 func RunReview(args []string, agentMode bool) int {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -26,13 +35,29 @@ func RunReview(args []string, agentMode bool) int {
 	if args[0] == "gate" {
 		return runReviewGate(args[1:], cwd, agentMode)
 	}
+	if args[0] == "notes" {
+		return runReviewNotes(args[1:], cwd, agentMode)
+	}
+	if args[0] == "history" {
+		return runReviewHistory(args[1:], cwd, agentMode)
+	}
+	if args[0] == "serve" {
+		return runReviewServe(args[1:], cwd, agentMode)
+	}
 
 	return runReviewRecord(args, cwd, agentMode)
 }
 
 func runReviewRecord(args []string, cwd string, agentMode bool) int {
+	args, meta, code := reviewMetaFlags(args, agentMode)
+	if code != 0 {
+		return code
+	}
+	if len(args) >= 2 && (args[1] == "--scores" || strings.HasPrefix(args[1], "--scores=")) {
+		return runReviewBulk(args, meta, cwd, agentMode)
+	}
 	if len(args) < 3 {
-		return renderError(agentMode, "user", "usage: ptsd review <feature> <stage> <score>")
+		return renderError(agentMode, "user", "usage: ptsd review <feature> <stage> <score> [--reviewer <name>] [--issue \"[severity:] text\"]... [--issues-file <json>]")
 	}
 
 	feature := args[0]
@@ -44,30 +69,195 @@ func runReviewRecord(args []string, cwd string, agentMode bool) int {
 		return renderError(agentMode, "user", "score must be an integer, got: "+scoreStr)
 	}
 
-	if err := core.RecordReview(cwd, feature, stage, score); err != nil {
+	if err := core.RecordReviewsWithMeta(cwd, feature, []core.StageScore{{Stage: stage, Score: score}}, meta); err != nil {
+		return coreError(agentMode, err)
+	}
+	gate, err := core.ReviewGate(cwd, feature, stage)
+	if err != nil {
 		return coreError(agentMode, err)
 	}
+	verdict := gate.Verdict()
+
+	if jsonOutput {
+		return printJSON(agentMode, "review", reviewJSON{Feature: feature, Stage: stage, Score: score, Verdict: verdict,
+			Consensus: gate.Score, Reviewers: gate.Reviewers, Require: gate.Require})
+	}
+	if agentMode {
+		fmt.Printf("score:%d verdict:%s%s%s\n", score, verdict, consensusNote(gate, " consensus:%d reviewers:%d/%d"), issueCount(meta.Issues, " issues:%d"))
+	} else {
+		fmt.Printf("review recorded: feature=%s stage=%s score=%d verdict=%s%s%s\n", feature, stage, score, verdict,
+			consensusNote(gate, " consensus=%d reviewers=%d/%d"), issueCount(meta.Issues, " issues=%d"))
+	}
+
+	return 0
+}
+
+// reviewMetaFlags takes --reviewer <name>, --issue "[severity:] text"
+// (repeatable) and --issues-file <json> out of args, returning the remaining
+// arguments.
+func reviewMetaFlags(args []string, agentMode bool) ([]string, core.ReviewMeta, int) {
+	var rest []string
+	var meta core.ReviewMeta
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--reviewer":
+			if i+1 >= len(args) || strings.TrimSpace(args[i+1]) == "" {
+				return nil, meta, usageError(agentMode, "review", "--reviewer requires a name")
+			}
+			meta.Reviewer = args[i+1]
+			i++
+		case "--issue":
+			if i+1 >= len(args) {
+				return nil, meta, usageError(agentMode, "review", "--issue requires the issue text")
+			}
+			meta.Issues = append(meta.Issues, core.ParseReviewIssue(args[i+1]))
+			i++
+		case "--issues-file":
+			if i+1 >= len(args) {
+				return nil, meta, usageError(agentMode, "review", "--issues-file requires a JSON file")
+			}
+			loaded, err := core.LoadReviewIssues(args[i+1])
+			if err != nil {
+				return nil, meta, coreError(agentMode, err)
+			}
+			meta.Issues = append(meta.Issues, loaded...)
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest, meta, 0
+}
+
+// consensusNote formats a stage's consensus score and reviewer count, ""
+// unless review.require asks for more than one reviewer.
+func consensusNote(gate core.ReviewGateResult, format string) string {
+	if gate.Require <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(format, gate.Score, gate.Reviewers, gate.Require)
+}
+
+// issueCount formats the number of reviewer issues, "" when there are none.
+func issueCount(issues []core.ReviewIssue, format string) string {
+	if len(issues) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(format, len(issues))
+}
+
+// runReviewBulk handles `ptsd review <feature> --scores prd=8,seed=9`: all
+// scores are recorded with one state write and reported with a combined
+// verdict, which fails if any stage is below review.min_score.
+func runReviewBulk(args []string, meta core.ReviewMeta, cwd string, agentMode bool) int {
+	feature := args[0]
+	list := strings.TrimPrefix(args[1], "--scores=")
+	if args[1] == "--scores" {
+		if len(args) < 3 {
+			return usageError(agentMode, "review", "--scores requires <stage>=<score>,...")
+		}
+		list = args[2]
+	}
+
+	var scores []core.StageScore
+	for _, pair := range strings.Split(list, ",") {
+		stage, scoreStr, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || stage == "" {
+			return usageError(agentMode, "review", fmt.Sprintf("invalid score %q: use <stage>=<score>", pair))
+		}
+		score, err := strconv.Atoi(scoreStr)
+		if err != nil {
+			return renderError(agentMode, "user", "score must be an integer, got: "+scoreStr)
+		}
+		scores = append(scores, core.StageScore{Stage: stage, Score: score})
+	}
 
-	cfg, _ := core.LoadConfig(cwd)
-	minScore := cfg.Review.MinScore
-	if minScore == 0 {
-		minScore = 7
+	if err := core.RecordReviewsWithMeta(cwd, feature, scores, meta); err != nil {
+		return coreError(agentMode, err)
 	}
 
+	minScore := core.MinScore(cwd, feature)
+
 	verdict := "pass"
-	if score < minScore {
-		verdict = "fail"
+	var parts, failed []string
+	out := reviewBulkJSON{Feature: feature, Scores: []reviewJSON{}}
+	for _, sc := range scores {
+		gate, err := core.ReviewGate(cwd, feature, sc.Stage)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		v := gate.Verdict()
+		switch {
+		case v == "fail":
+			verdict = "fail"
+			failed = append(failed, sc.Stage)
+		case v == "pending" && verdict == "pass":
+			verdict = "pending"
+		}
+		parts = append(parts, fmt.Sprintf("%s=%d", sc.Stage, sc.Score))
+		out.Scores = append(out.Scores, reviewJSON{Feature: feature, Stage: sc.Stage, Score: sc.Score, Verdict: v,
+			Consensus: gate.Score, Reviewers: gate.Reviewers, Require: gate.Require})
 	}
+	out.Verdict = verdict
 
+	if jsonOutput {
+		return printJSON(agentMode, "review.bulk", out)
+	}
 	if agentMode {
-		fmt.Printf("score:%d verdict:%s\n", score, verdict)
+		line := fmt.Sprintf("scores:%s verdict:%s", strings.Join(parts, ","), verdict)
+		if len(failed) > 0 {
+			line += " failed:" + strings.Join(failed, ",")
+		}
+		fmt.Println(line + issueCount(meta.Issues, " issues:%d"))
 	} else {
-		fmt.Printf("review recorded: feature=%s stage=%s score=%d verdict=%s\n", feature, stage, score, verdict)
+		fmt.Printf("reviews recorded: feature=%s %s verdict=%s%s\n", feature, strings.Join(parts, " "), verdict, issueCount(meta.Issues, " issues=%d"))
+		if len(failed) > 0 {
+			fmt.Printf("below min score %d: %s\n", minScore, strings.Join(failed, ", "))
+		}
 	}
-
 	return 0
 }
 
+type reviewBulkJSON struct {
+	Feature string       `json:"feature"`
+	Scores  []reviewJSON `json:"scores"`
+	Verdict string       `json:"verdict"`
+}
+
+type reviewJSON struct {
+	Feature string `json:"feature"`
+	Stage   string `json:"stage"`
+	Score   int    `json:"score"`
+	Verdict string `json:"verdict"`
+	// Consensus combines every reviewer's score under review.strategy.
+	Consensus int `json:"consensus"`
+	Reviewers int `json:"reviewers"`
+	Require   int `json:"require"`
+}
+
+type reviewIssueJSON struct {
+	Severity string `json:"severity"`
+	Text     string `json:"text"`
+}
+
+func newReviewIssuesJSON(issues []core.ReviewIssue) []reviewIssueJSON {
+	out := make([]reviewIssueJSON, 0, len(issues))
+	for _, issue := range issues {
+		out = append(out, reviewIssueJSON{issue.Severity, issue.Text})
+	}
+	return out
+}
+
+type reviewGateJSON struct {
+	Feature   string `json:"feature"`
+	Stage     string `json:"stage"`
+	Verdict   string `json:"verdict"`
+	Score     int    `json:"score"`
+	Reviewers int    `json:"reviewers"`
+	Require   int    `json:"require"`
+	Strategy  string `json:"strategy"`
+}
+
 func runReviewGate(args []string, cwd string, agentMode bool) int {
 	if len(args) < 2 {
 		return renderError(agentMode, "user", "usage: ptsd review gate <feature> <stage>")
@@ -76,25 +266,202 @@ func runReviewGate(args []string, cwd string, agentMode bool) int {
 	feature := args[0]
 	stage := args[1]
 
-	passed, err := core.CheckReviewGate(cwd, feature, stage)
+	gate, err := core.ReviewGate(cwd, feature, stage)
 	if err != nil {
 		return coreError(agentMode, err)
 	}
 
 	verdict := "fail"
-	if passed {
+	if gate.Passed {
 		verdict = "pass"
 	}
 
-	if agentMode {
-		fmt.Printf("gate:%s feature:%s stage:%s\n", verdict, feature, stage)
+	if jsonOutput {
+		printJSON(agentMode, "review.gate", reviewGateJSON{Feature: feature, Stage: stage, Verdict: verdict,
+			Score: gate.Score, Reviewers: gate.Reviewers, Require: gate.Require, Strategy: gate.Strategy})
+	} else if agentMode {
+		fmt.Printf("gate:%s feature:%s stage:%s%s\n", verdict, feature, stage, consensusNote(gate, " score:%d reviewers:%d/%d"))
 	} else {
-		fmt.Printf("review gate %s: feature=%s stage=%s\n", verdict, feature, stage)
+		fmt.Printf("review gate %s: feature=%s stage=%s%s\n", verdict, feature, stage, consensusNote(gate, " score=%d reviewers=%d/%d"))
 	}
 
-	if !passed {
+	if !gate.Passed {
 		return 1
 	}
 
 	return 0
 }
+
+func runReviewNotes(args []string, cwd string, agentMode bool) int {
+	feature := ""
+	if len(args) > 0 {
+		feature = args[0]
+	}
+
+	notes, err := core.LoadReviewNotes(cwd, feature)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	for _, n := range notes {
+		commit := n.Commit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		if agentMode {
+			fmt.Printf("%s feature=%s stage=%s score=%d verdict=%s\n", commit, n.Feature, n.Stage, n.Score, n.Verdict)
+		} else {
+			fmt.Printf("%s %s  %-20s %-6s %2d  %s\n", commit, n.At.Format("2006-01-02"), n.Feature, n.Stage, n.Score, n.Verdict)
+		}
+	}
+	return 0
+}
+
+// runReviewHistory handles `ptsd review history <feature> [--stage <stage>]
+// [--json]`: every recorded review of the feature, oldest first, and the
+// score trajectory of each stage.
+func runReviewHistory(args []string, cwd string, agentMode bool) int {
+	jsonOut := jsonOutput
+	feature, stage := "", ""
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--json":
+			jsonOut = true
+		case args[i] == "--stage" && i+1 < len(args):
+			stage = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "-") || feature != "":
+			return usageError(agentMode, "review", "usage: ptsd review history <feature> [--stage <stage>] [--json]")
+		default:
+			feature = args[i]
+		}
+	}
+	if feature == "" {
+		return usageError(agentMode, "review", "usage: ptsd review history <feature> [--stage <stage>] [--json]")
+	}
+
+	records, err := core.ReviewHistory(cwd, feature, stage)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	if jsonOut {
+		type recordJSON struct {
+			At       string            `json:"at"`
+			Stage    string            `json:"stage"`
+			Score    int               `json:"score"`
+			MinScore int               `json:"min_score"`
+			Verdict  string            `json:"verdict"`
+			Reviewer string            `json:"reviewer"`
+			Issues   []reviewIssueJSON `json:"issues"`
+		}
+		out := make([]recordJSON, 0, len(records))
+		for _, r := range records {
+			out = append(out, recordJSON{r.At.UTC().Format(time.RFC3339), r.Stage, r.Score, r.MinScore, r.Verdict, r.Reviewer, newReviewIssuesJSON(r.Issues)})
+		}
+		return printJSON(agentMode, "review.history", out)
+	}
+
+	// Trajectory per stage, in the order stages were first reviewed.
+	var stages []string
+	scores := make(map[string][]string)
+	for _, r := range records {
+		if _, ok := scores[r.Stage]; !ok {
+			stages = append(stages, r.Stage)
+		}
+		scores[r.Stage] = append(scores[r.Stage], strconv.Itoa(r.Score))
+	}
+
+	if agentMode {
+		for _, r := range records {
+			fmt.Printf("review: %s stage=%s score=%d min=%d verdict=%s reviewer=%s issues=%d\n",
+				r.At.UTC().Format(time.RFC3339), r.Stage, r.Score, r.MinScore, r.Verdict, r.Reviewer, len(r.Issues))
+			for _, issue := range r.Issues {
+				fmt.Printf("issue: %s %s %q\n", r.Stage, issue.Severity, issue.Text)
+			}
+		}
+		for _, s := range stages {
+			fmt.Printf("trajectory: %s %s\n", s, strings.Join(scores[s], ","))
+		}
+		fmt.Printf("history: %s reviews=%d\n", feature, len(records))
+		return 0
+	}
+	if len(records) == 0 {
+		fmt.Printf("No reviews recorded for %s.\n", feature)
+		return 0
+	}
+	for _, r := range records {
+		fmt.Printf("%s  %-8s %2d/10  %-6s  by %s\n", r.At.Local().Format("2006-01-02 15:04"), r.Stage, r.Score, r.Verdict, r.Reviewer)
+		for _, issue := range r.Issues {
+			fmt.Printf("    - [%s] %s\n", issue.Severity, issue.Text)
+		}
+	}
+	fmt.Println()
+	for _, s := range stages {
+		fmt.Printf("%s: %s\n", s, strings.Join(scores[s], " -> "))
+	}
+	return 0
+}
+
+// runReviewServe handles `ptsd review serve [--port N] [--host H]`: an HTTP
+// receiver that records reviews POSTed by external reviewers until
+// interrupted. The bearer token is read from the env var named by
+// review.token_env.
+func runReviewServe(args []string, cwd string, agentMode bool) int {
+	host, port := "127.0.0.1", 8787
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--port", "--host":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "review", args[i]+" requires a value")
+			}
+			if args[i] == "--host" {
+				host = args[i+1]
+			} else {
+				p, err := strconv.Atoi(args[i+1])
+				if err != nil || p < 1 || p > 65535 {
+					return usageError(agentMode, "review", "--port must be 1-65535, got: "+args[i+1])
+				}
+				port = p
+			}
+			i++
+		default:
+			return usageError(agentMode, "review", fmt.Sprintf("unknown flag %q for review serve", args[i]))
+		}
+	}
+
+	cfg, err := core.LoadConfig(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	token := os.Getenv(cfg.Review.TokenEnv)
+	if token == "" {
+		return renderError(agentMode, "config", "$"+cfg.Review.TokenEnv+" is not set: review serve requires a shared token")
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	if err := core.CheckListenAddr(cwd, "review serve", addr); err != nil {
+		return coreError(agentMode, err)
+	}
+
+	stop := make(chan struct{})
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		close(stop)
+	}()
+
+	fmt.Printf("listening http://%s/review\n", addr)
+	onRecord := func(sub core.ReviewSubmission, r core.ReviewReceipt) {
+		line := fmt.Sprintf("review recorded: feature=%s stage=%s score=%d verdict=%s", r.Feature, r.Stage, r.Score, r.Verdict)
+		if sub.Reviewer != "" {
+			line += " reviewer=" + sub.Reviewer
+		}
+		fmt.Println(line)
+	}
+	if err := core.ServeReviews(cwd, addr, token, onRecord, stop); err != nil {
+		return coreError(agentMode, err)
+	}
+	return 0
+}
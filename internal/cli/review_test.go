@@ -339,3 +339,161 @@ func TestRunReview_AutoRedoCreatesTask(t *testing.T) {
 		t.Errorf("expected redo task for my-feat in tasks.yaml, got:\n%s", content)
 	}
 }
+
+func TestRunReview_NotesOutsideRepo(t *testing.T) {
+	dir, cleanup := setupReviewProject(t)
+	defer cleanup()
+
+	var code int
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			code = RunReview([]string{"notes"}, true)
+		})
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	if out != "" {
+		t.Errorf("expected no notes, got %q", out)
+	}
+}
+
+func TestRunReview_BulkScores(t *testing.T) {
+	dir, cleanup := setupReviewProject(t)
+	defer cleanup()
+
+	out := captureStdout(t, func() {
+		if code := RunReview([]string{"my-feat", "--scores", "prd=8,seed=9,bdd=6"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if strings.TrimSpace(out) != "scores:prd=8,seed=9,bdd=6 verdict:fail failed:bdd" {
+		t.Errorf("unexpected output: %q", out)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "state.yaml"))
+	for _, stage := range []string{"prd:", "seed:", "bdd:"} {
+		if !strings.Contains(string(data), stage) {
+			t.Errorf("state.yaml missing %s score:\n%s", stage, data)
+		}
+	}
+
+	if code := RunReview([]string{"my-feat", "--scores", "prd8"}, true); code != 2 {
+		t.Errorf("expected exit 2 for a malformed pair, got %d", code)
+	}
+}
+
+func TestRunReview_Issues(t *testing.T) {
+	dir, cleanup := setupReviewProject(t)
+	defer cleanup()
+	os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte("features:\n  - id: my-feat\n    status: in-progress\n"), 0644)
+	issuesFile := filepath.Join(dir, "issues.json")
+	os.WriteFile(issuesFile, []byte(`[{"severity": "minor", "text": "rename helper"}]`), 0644)
+
+	out := captureStdout(t, func() {
+		code := RunReview([]string{"my-feat", "impl", "5", "--issue", "blocker: no error handling", "--issues-file", issuesFile}, true)
+		if code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if strings.TrimSpace(out) != "score:5 verdict:fail issues:2" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	out = captureStdout(t, func() { RunContext(nil, true) })
+	for _, want := range []string{
+		`issue: my-feat stage=impl severity=blocker text="score 5 below min 7 at impl stage"`,
+		`issue: my-feat stage=impl severity=blocker text="no error handling"`,
+		`issue: my-feat stage=impl severity=minor text="rename helper"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in context:\n%s", want, out)
+		}
+	}
+
+	if code := RunReview([]string{"my-feat", "impl", "8", "--issue"}, true); code != 2 {
+		t.Errorf("expected exit 2 without issue text, got %d", code)
+	}
+	if code := RunReview([]string{"my-feat", "impl", "8", "--issues-file", filepath.Join(dir, "missing.json")}, true); code != 2 {
+		t.Errorf("expected exit 2 for a missing issues file, got %d", code)
+	}
+}
+
+func TestRunReview_Reviewers(t *testing.T) {
+	dir, cleanup := setupReviewProject(t)
+	defer cleanup()
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("review:\n  min_score: 7\n  require: 2\n  strategy: min\n"), 0644)
+
+	out := captureStdout(t, func() { RunReview([]string{"my-feat", "impl", "8", "--reviewer", "claude-opus"}, true) })
+	if out != "score:8 verdict:pending consensus:8 reviewers:1/2\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+	out = captureStdout(t, func() {
+		if code := RunReview([]string{"gate", "my-feat", "impl"}, true); code != 1 {
+			t.Errorf("expected gate exit 1 with one reviewer, got %d", code)
+		}
+	})
+	if out != "gate:fail feature:my-feat stage:impl score:8 reviewers:1/2\n" {
+		t.Errorf("unexpected gate output: %q", out)
+	}
+
+	out = captureStdout(t, func() { RunReview([]string{"my-feat", "impl", "9", "--reviewer", "human"}, true) })
+	if out != "score:9 verdict:pass consensus:8 reviewers:2/2\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+	if code := RunReview([]string{"gate", "my-feat", "impl"}, true); code != 0 {
+		t.Errorf("expected gate exit 0 with two passing reviewers, got %d", code)
+	}
+
+	if code := RunReview([]string{"my-feat", "impl", "9", "--reviewer"}, true); code != 2 {
+		t.Errorf("expected exit 2 without a reviewer name, got %d", code)
+	}
+}
+
+func TestRunReview_ServeRequiresToken(t *testing.T) {
+	_, cleanup := setupReviewProject(t)
+	defer cleanup()
+	t.Setenv("PTSD_REVIEW_TOKEN", "")
+
+	if code := RunReview([]string{"serve"}, true); code != 3 {
+		t.Errorf("expected exit 3 without a token, got %d", code)
+	}
+	if code := RunReview([]string{"serve", "--port", "http"}, true); code != 2 {
+		t.Errorf("expected exit 2 for a bad port, got %d", code)
+	}
+}
+
+// TestRunReview_History verifies that review history lists every recorded
+// score and each stage's trajectory.
+func TestRunReview_History(t *testing.T) {
+	_, cleanup := setupReviewProject(t)
+	defer cleanup()
+	t.Setenv("PTSD_AGENT", "agent-1")
+
+	captureStdout(t, func() {
+		RunReview([]string{"my-feat", "impl", "5"}, true)
+		RunReview([]string{"my-feat", "impl", "8"}, true)
+	})
+	out := captureStdout(t, func() {
+		if code := RunReview([]string{"history", "my-feat"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got:\n%s", out)
+	}
+	if !strings.HasPrefix(lines[0], "review: ") || !strings.HasSuffix(lines[0], " stage=impl score=5 min=7 verdict=failed reviewer=agent-1 issues=0") {
+		t.Errorf("unexpected first review line: %q", lines[0])
+	}
+	if lines[2] != "trajectory: impl 5,8" || lines[3] != "history: my-feat reviews=2" {
+		t.Errorf("unexpected summary:\n%s", out)
+	}
+
+	out = captureStdout(t, func() { RunReview([]string{"history", "my-feat", "--stage", "prd", "--json"}, true) })
+	if !strings.Contains(out, "[]") {
+		t.Errorf("expected an empty JSON list for prd, got %s", out)
+	}
+	if code := RunReview([]string{"history"}, true); code != 2 {
+		t.Errorf("expected exit 2 without a feature, got %d", code)
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunSnapshot handles `ptsd snapshot [name]` and `ptsd snapshot list`.
+func RunSnapshot(args []string, agentMode bool) int {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	if len(args) > 0 && args[0] == "list" {
+		snaps, err := core.ListSnapshots(cwd)
+		if err != nil {
+			return coreError(agentMode, err)
+		}
+		for _, s := range snaps {
+			if agentMode {
+				fmt.Printf("%s created=%s size=%d\n", s.Name, s.Created.UTC().Format("2006-01-02T15:04:05Z"), s.Size)
+			} else {
+				fmt.Printf("%-30s %s  %d bytes\n", s.Name, s.Created.Format("2006-01-02 15:04"), s.Size)
+			}
+		}
+		return 0
+	}
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	name, err = core.CreateSnapshot(cwd, name)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	fmt.Printf("snapshot %s\n", name)
+	return 0
+}
+
+// RunRestore handles `ptsd restore <name>`.
+func RunRestore(args []string, agentMode bool) int {
+	if len(args) == 0 {
+		return usageError(agentMode, "restore", "snapshot name required (see ptsd snapshot list)")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	if err := core.RestoreSnapshot(cwd, args[0]); err != nil {
+		return coreError(agentMode, err)
+	}
+	fmt.Printf("restored %s\n", args[0])
+	return 0
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunSnapshot_CreateListRestore(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	tasksPath := filepath.Join(dir, ".ptsd", "tasks.yaml")
+	os.WriteFile(tasksPath, []byte("tasks:\n"), 0644)
+
+	withDir(t, dir, func() {
+		if code := RunSnapshot([]string{"safe"}, true); code != 0 {
+			t.Fatalf("snapshot: expected exit 0, got %d", code)
+		}
+	})
+
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			RunSnapshot([]string{"list"}, true)
+		})
+	})
+	if !strings.HasPrefix(out, "safe created=") {
+		t.Errorf("expected snapshot in list, got %q", out)
+	}
+
+	os.Remove(tasksPath)
+	withDir(t, dir, func() {
+		if code := RunRestore([]string{"safe"}, true); code != 0 {
+			t.Fatalf("restore: expected exit 0, got %d", code)
+		}
+	})
+	if _, err := os.Stat(tasksPath); err != nil {
+		t.Errorf("tasks.yaml not restored: %v", err)
+	}
+}
+
+func TestRunRestore_NoName(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	withDir(t, dir, func() {
+		if code := RunRestore([]string{}, true); code != 2 {
+			t.Errorf("expected exit 2, got %d", code)
+		}
+	})
+}
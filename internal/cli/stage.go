@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunStage handles: ptsd stage set <feature> <stage> --why <reason> [--force].
+// --force overrides failed guards and is refused in agent mode.
+func RunStage(args []string, agentMode bool) int {
+	const usage = "usage: ptsd stage set <feature> <stage> --why <reason> [--force]"
+	if len(args) == 0 || args[0] != "set" {
+		return renderError(agentMode, "user", usage)
+	}
+	var positional []string
+	why, force := "", false
+	for i := 1; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--why" && i+1 < len(args):
+			why = args[i+1]
+			i++
+		case a == "--force":
+			force = true
+		case strings.HasPrefix(a, "--"):
+			return renderError(agentMode, "user", usage)
+		default:
+			positional = append(positional, a)
+		}
+	}
+	if len(positional) != 2 {
+		return renderError(agentMode, "user", usage)
+	}
+	if force && agentMode {
+		// Overriding the guards is a human decision, never an agent's.
+		return renderError(agentMode, "user", "stage set --force is not accepted in agent mode")
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	change, err := core.SetStage(dir, positional[0], positional[1], why, force)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	from := change.From
+	if from == "" {
+		from = "none"
+	}
+	if agentMode {
+		fmt.Printf("stage: %s %s -> %s\n", change.Feature, from, change.To)
+		for _, g := range change.Forced {
+			fmt.Printf("forced: %s\n", g)
+		}
+	} else {
+		fmt.Printf("Stage of %s set to %s (was %s)\n", change.Feature, change.To, from)
+		if len(change.Forced) > 0 {
+			fmt.Printf("Overrode: %s\n", strings.Join(change.Forced, "; "))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunStageSet(t *testing.T) {
+	dir := setupPipelineProject(t)
+	chdirTo(t, dir)
+	prd := "# PRD\n\n<!-- feature:my-feat -->\n## My feat\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "docs", "PRD.md"), []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if code := RunStage([]string{"set", "my-feat", "prd", "--why", "state.yaml was reset"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "stage: my-feat none -> prd") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	if code := RunStage([]string{"set", "my-feat", "seed", "--why", "x"}, true); code != 1 {
+		t.Errorf("expected exit 1 when guards fail, got %d", code)
+	}
+	if code := RunStage([]string{"set", "my-feat", "seed", "--why", "x", "--force"}, true); code != 2 {
+		t.Errorf("expected --force to be refused in agent mode, got %d", code)
+	}
+	out = captureStdout(t, func() {
+		if code := RunStage([]string{"set", "my-feat", "seed", "--why", "seed kept elsewhere", "--force"}, false); code != 0 {
+			t.Errorf("expected exit 0 with --force, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "Stage of my-feat set to seed (was prd)") || !strings.Contains(out, "Overrode: prd review not passed; no seed.yaml") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	if code := RunStage([]string{"set", "my-feat"}, true); code != 2 {
+		t.Errorf("expected usage error, got %d", code)
+	}
+}
@@ -3,19 +3,62 @@ package cli
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/veschin/ptsd/internal/core"
 	"github.com/veschin/ptsd/internal/render"
 )
 
-// RunStatus executes `ptsd status`. Returns an exit code.
+// RunStatus executes `ptsd status [--format dashboard] [--tui [--interval 1s]]`.
+// Returns an exit code.
 func RunStatus(args []string, agentMode bool) int {
+	const usage = "usage: status [--format summary|dashboard] [--tui [--interval 1s]]"
+	format := "summary"
+	tui := false
+	interval := time.Second
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--tui":
+			tui = true
+		case "--format", "--interval":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "status", args[i]+" requires a value")
+			}
+			val := args[i+1]
+			i++
+			if args[i-1] == "--format" {
+				if val != "summary" && val != "dashboard" {
+					return usageError(agentMode, "status", fmt.Sprintf("invalid --format %q: must be summary|dashboard", val))
+				}
+				format = val
+				continue
+			}
+			d, err := time.ParseDuration(val)
+			if err != nil || d <= 0 {
+				return usageError(agentMode, "status", fmt.Sprintf("invalid --interval %q: use a duration like 500ms or 2s", val))
+			}
+			interval = d
+		default:
+			return usageError(agentMode, "status", fmt.Sprintf("unknown flag %q; %s", args[i], usage))
+		}
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "err:io %s\n", err)
 		return 4
 	}
 
+	if tui {
+		if jsonOutput {
+			return usageError(agentMode, "status", "--tui redraws the terminal and cannot be combined with --json")
+		}
+		return runStatusTUI(cwd, interval, agentMode)
+	}
+	if format == "dashboard" {
+		return printDashboard(cwd, agentMode)
+	}
+
 	result, err := core.ProjectStatus(cwd)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "err:io %s\n", err)
@@ -25,6 +68,10 @@ func RunStatus(args []string, agentMode bool) int {
 	// Build StatusData from ProjectStatusResult.
 	data := buildStatusData(cwd, result)
 
+	if jsonOutput {
+		return printJSON(agentMode, "status", newStatusJSON(data, result))
+	}
+
 	if agentMode {
 		r := &render.AgentRenderer{}
 
@@ -34,9 +81,59 @@ func RunStatus(args []string, agentMode bool) int {
 		}
 
 		fmt.Println(r.RenderStatus(data))
+		for _, f := range result.Revisit {
+			fmt.Printf("revisit: %s since=%s reason=%q\n", f.ID, f.Revisit, f.DeferReason)
+		}
+		for _, e := range result.Epics {
+			fmt.Printf("epic: %s %s\n", e.ID, formatRollup(e))
+		}
+		for _, e := range result.ETAs {
+			line := fmt.Sprintf("eta: %s stage=%s eta=%s remaining=%s", e.Feature, e.Stage, etaDate(e.ETA), etaRemaining(e))
+			if e.Milestone != "" {
+				line += fmt.Sprintf(" milestone=%s target=%s", e.Milestone, e.Target)
+			}
+			if e.Late {
+				line += " late"
+			}
+			fmt.Println(line)
+		}
+		for _, m := range result.Milestones {
+			fmt.Printf("milestone: %s target=%s eta=%s features=%d done=%d %s\n", m.Name, m.Target, etaDate(m.ETA), m.Features, m.Done, milestoneVerdict(m))
+		}
 	} else {
 		// Human mode: simple table output (no Bubbletea dependency in cli layer).
 		printStatusHuman(data, result.Regressions)
+		if len(result.Revisit) > 0 {
+			fmt.Println("\nDue for revisit:")
+			for _, f := range result.Revisit {
+				fmt.Printf("  %s (since %s): %s\n", f.ID, f.Revisit, f.DeferReason)
+			}
+		}
+		if len(result.Epics) > 0 {
+			fmt.Println("\nEpics:")
+			for _, e := range result.Epics {
+				fmt.Printf("  %-20s %s\n", e.ID, formatRollup(e))
+			}
+		}
+		if len(result.ETAs) > 0 {
+			fmt.Println("\nEstimates:")
+			for _, e := range result.ETAs {
+				fmt.Printf("  %-20s %-6s %s (%s left)", e.Feature, e.Stage, etaDate(e.ETA), etaRemaining(e))
+				if e.Milestone != "" {
+					fmt.Printf("  %s by %s", e.Milestone, e.Target)
+				}
+				if e.Late {
+					fmt.Print("  LATE")
+				}
+				fmt.Println()
+			}
+		}
+		if len(result.Milestones) > 0 {
+			fmt.Println("\nMilestones:")
+			for _, m := range result.Milestones {
+				fmt.Printf("  %-20s target %s  eta %s  %d/%d done  %s\n", m.Name, m.Target, etaDate(m.ETA), m.Done, m.Features, milestoneVerdict(m))
+			}
+		}
 	}
 
 	return 0
@@ -110,3 +207,124 @@ func printStatusHuman(data render.StatusData, regressions []core.RegressionWarni
 		}
 	}
 }
+
+type statusJSON struct {
+	Features    countJSON        `json:"features"`
+	BDD         countJSON        `json:"bdd"`
+	Tests       countJSON        `json:"tests"`
+	Tasks       taskCountsJSON   `json:"tasks"`
+	Regressions []regressionJSON `json:"regressions"`
+	Revisit     []revisitJSON    `json:"revisit"`
+	Epics       []rollupJSON     `json:"epics"`
+	ETAs        []etaJSON        `json:"etas"`
+	Milestones  []milestoneJSON  `json:"milestones"`
+}
+
+// etaJSON dates are YYYY-MM-DD, "" when there is no estimate.
+type etaJSON struct {
+	Feature          string `json:"feature"`
+	Stage            string `json:"stage"`
+	ETA              string `json:"eta"`
+	RemainingSeconds int64  `json:"remaining_seconds"`
+	Milestone        string `json:"milestone"`
+	Target           string `json:"target"`
+	Late             bool   `json:"late"`
+}
+
+type milestoneJSON struct {
+	Name     string `json:"name"`
+	Target   string `json:"target"`
+	ETA      string `json:"eta"`
+	Features int    `json:"features"`
+	Done     int    `json:"done"`
+	Late     bool   `json:"late"`
+}
+
+// etaDate formats an estimate as YYYY-MM-DD, "unknown" without history.
+func etaDate(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Format("2006-01-02")
+}
+
+func etaRemaining(e core.FeatureETA) string {
+	if e.ETA.IsZero() {
+		return "unknown"
+	}
+	return durationShort(e.Remaining)
+}
+
+func milestoneVerdict(m core.MilestoneETA) string {
+	switch {
+	case m.Late:
+		return "late"
+	case m.ETA.IsZero():
+		return "unknown"
+	}
+	return "on-track"
+}
+
+// countJSON is a total and how many of it are missing a stage, BDD or tests.
+type countJSON struct {
+	Total   int `json:"total"`
+	Missing int `json:"missing"`
+}
+
+type taskCountsJSON struct {
+	Total int `json:"total"`
+	TODO  int `json:"todo"`
+	WIP   int `json:"wip"`
+	DONE  int `json:"done"`
+}
+
+type regressionJSON struct {
+	Feature  string `json:"feature"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type revisitJSON struct {
+	ID     string `json:"id"`
+	Since  string `json:"since"`
+	Reason string `json:"reason"`
+}
+
+func newStatusJSON(data render.StatusData, result core.ProjectStatusResult) statusJSON {
+	out := statusJSON{
+		Features:    countJSON{Total: data.FeatTotal, Missing: data.FeatFail},
+		BDD:         countJSON{Total: data.BDDTotal, Missing: data.BDDFail},
+		Tests:       countJSON{Total: data.TestTotal, Missing: data.TestFail},
+		Tasks:       taskCountsJSON{Total: data.TaskTotal, TODO: data.TaskTodo, WIP: data.TaskWIP, DONE: data.TaskDone},
+		Regressions: []regressionJSON{},
+		Revisit:     []revisitJSON{},
+		Epics:       []rollupJSON{},
+		ETAs:        []etaJSON{},
+		Milestones:  []milestoneJSON{},
+	}
+	for _, w := range result.Regressions {
+		out.Regressions = append(out.Regressions, regressionJSON{Feature: w.Feature, Severity: w.Severity, Message: w.Message})
+	}
+	for _, f := range result.Revisit {
+		out.Revisit = append(out.Revisit, revisitJSON{ID: f.ID, Since: f.Revisit, Reason: f.DeferReason})
+	}
+	for _, e := range result.Epics {
+		out.Epics = append(out.Epics, newRollupJSON(e))
+	}
+	for _, e := range result.ETAs {
+		eta := ""
+		if !e.ETA.IsZero() {
+			eta = e.ETA.Format("2006-01-02")
+		}
+		out.ETAs = append(out.ETAs, etaJSON{Feature: e.Feature, Stage: e.Stage, ETA: eta, RemainingSeconds: int64(e.Remaining.Seconds()),
+			Milestone: e.Milestone, Target: e.Target, Late: e.Late})
+	}
+	for _, m := range result.Milestones {
+		eta := ""
+		if !m.ETA.IsZero() {
+			eta = m.ETA.Format("2006-01-02")
+		}
+		out.Milestones = append(out.Milestones, milestoneJSON{Name: m.Name, Target: m.Target, ETA: eta, Features: m.Features, Done: m.Done, Late: m.Late})
+	}
+	return out
+}
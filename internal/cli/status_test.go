@@ -6,6 +6,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
 )
 
 // setupStatusProject creates a minimal .ptsd project in dir and returns dir.
@@ -242,3 +245,61 @@ func TestRunStatus_OutputFormat_ExactValues(t *testing.T) {
 		t.Errorf("agent output does not match expected format\nwant (substring): %q\ngot:              %q", expected, output)
 	}
 }
+
+func TestRunStatus_DashboardFormat(t *testing.T) {
+	dir := setupStatusProject(t)
+	chdirTo(t, dir)
+
+	var code int
+	out := captureStdout(t, func() { code = RunStatus([]string{"--format", "dashboard"}, true) })
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	if !strings.Contains(out, "FEATURE") || !strings.Contains(out, "alpha") || !strings.Contains(out, "1 features") {
+		t.Errorf("unexpected dashboard:\n%s", out)
+	}
+
+	if code := RunStatus([]string{"--format", "grid"}, true); code != 2 {
+		t.Errorf("invalid --format: expected exit 2, got %d", code)
+	}
+	// captureStdout swaps stdout for a pipe, which is not a terminal.
+	captureStdout(t, func() { code = RunStatus([]string{"--tui"}, true) })
+	if code != 2 {
+		t.Errorf("--tui without a terminal: expected exit 2, got %d", code)
+	}
+}
+
+func TestRunStatus_Estimates(t *testing.T) {
+	dir := setupStatusProject(t)
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	featuresContent := "features:\n  - id: alpha\n    title: Alpha Feature\n    status: in-progress\n  - id: beta\n    title: Beta\n    status: implemented\n"
+	if err := os.WriteFile(filepath.Join(ptsdDir, "features.yaml"), []byte(featuresContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ptsdDir, "ptsd.yaml"), []byte("milestones:\n  v1:\n    target: 2020-01-01\n    features: [alpha, beta]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, e := range []core.Event{
+		{At: now.Add(-96 * time.Hour), Type: core.EventStage, Feature: "beta", Stage: "prd"},
+		{At: now.Add(-72 * time.Hour), Type: core.EventStage, Feature: "beta", Stage: "seed"},
+		{At: now.Add(-time.Hour), Type: core.EventStage, Feature: "alpha", Stage: "prd"},
+	} {
+		if err := core.AppendEvent(dir, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	chdirTo(t, dir)
+
+	out := captureStdout(t, func() {
+		if code := RunStatus([]string{}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "eta: alpha stage=prd eta=") || !strings.Contains(out, " milestone=v1 target=2020-01-01 late\n") {
+		t.Errorf("expected a late eta line for alpha, got:\n%s", out)
+	}
+	if !strings.Contains(out, "milestone: v1 target=2020-01-01 eta=") || !strings.Contains(out, "features=2 done=1 late\n") {
+		t.Errorf("expected a late milestone line, got:\n%s", out)
+	}
+}
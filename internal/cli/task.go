@@ -2,9 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/veschin/ptsd/internal/core"
 	"github.com/veschin/ptsd/internal/render"
@@ -14,7 +16,7 @@ func RunTask(args []string, agentMode bool) int {
 	r := newRenderer(agentMode)
 
 	if len(args) == 0 {
-		fmt.Fprintln(os.Stderr, r.RenderError("user", "subcommand required: add|list|next|update"))
+		fmt.Fprintln(os.Stderr, r.RenderError("user", "subcommand required: add|list|next|show|update|comment|claim|release|graph|import"))
 		return 2
 	}
 
@@ -34,10 +36,22 @@ func RunTask(args []string, agentMode bool) int {
 		return runTaskList(cwd, rest, agentMode)
 	case "next":
 		return runTaskNext(cwd, rest, agentMode)
+	case "show":
+		return runTaskShow(cwd, rest, agentMode)
 	case "update":
 		return runTaskUpdate(cwd, rest, agentMode)
+	case "comment":
+		return runTaskComment(cwd, rest, agentMode)
+	case "claim":
+		return runTaskClaim(cwd, rest, agentMode)
+	case "release":
+		return runTaskRelease(cwd, rest, agentMode)
+	case "graph":
+		return runTaskGraph(cwd, rest, agentMode)
+	case "import":
+		return runTaskImport(cwd, rest, agentMode)
 	default:
-		fmt.Fprintln(os.Stderr, r.RenderError("user", fmt.Sprintf("unknown subcommand %q: use add|list|next|update", sub)))
+		fmt.Fprintln(os.Stderr, r.RenderError("user", fmt.Sprintf("unknown subcommand %q: use add|list|next|show|update|comment|claim|release|graph|import", sub)))
 		return 2
 	}
 }
@@ -99,12 +113,31 @@ func runTaskList(cwd string, args []string, agentMode bool) int {
 		return coreError(agentMode, err)
 	}
 
+	if jsonOutput {
+		return printJSON(agentMode, "task.list", newTasksJSON(tasks))
+	}
 	for _, t := range tasks {
 		fmt.Printf("%s %s [%s] [%s]: %s\n", t.ID, t.Feature, t.Status, t.Priority, t.Title)
 	}
 	return 0
 }
 
+type taskJSON struct {
+	ID       string `json:"id"`
+	Feature  string `json:"feature"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Priority string `json:"priority"`
+}
+
+func newTasksJSON(tasks []core.Task) []taskJSON {
+	out := make([]taskJSON, 0, len(tasks))
+	for _, t := range tasks {
+		out = append(out, taskJSON{ID: t.ID, Feature: t.Feature, Title: t.Title, Status: t.Status, Priority: t.Priority})
+	}
+	return out
+}
+
 // runTaskNext handles: task next [--limit N]
 func runTaskNext(cwd string, args []string, agentMode bool) int {
 	r := newRenderer(agentMode)
@@ -130,6 +163,9 @@ func runTaskNext(cwd string, args []string, agentMode bool) int {
 	if err != nil {
 		return coreError(agentMode, err)
 	}
+	if jsonOutput {
+		return printJSON(agentMode, "task.next", newTasksJSON(tasks))
+	}
 
 	views := make([]render.TaskView, len(tasks))
 	for i, t := range tasks {
@@ -167,3 +203,276 @@ func runTaskUpdate(cwd string, args []string, agentMode bool) int {
 	fmt.Printf("%s updated to %s\n", id, status)
 	return 0
 }
+
+// runTaskShow handles: task show <id> [--json]
+func runTaskShow(cwd string, args []string, agentMode bool) int {
+	id, jsonOut := "", jsonOutput
+	for _, a := range args {
+		switch {
+		case a == "--json":
+			jsonOut = true
+		case strings.HasPrefix(a, "-") || id != "":
+			return usageError(agentMode, "task", "usage: task show <id> [--json]")
+		default:
+			id = a
+		}
+	}
+	if id == "" {
+		return usageError(agentMode, "task", "usage: task show <id> [--json]")
+	}
+
+	t, err := core.GetTask(cwd, id)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if jsonOut {
+		return printJSON(agentMode, "task.show", taskShowJSON{
+			taskJSON: taskJSON{ID: t.ID, Feature: t.Feature, Title: t.Title, Status: t.Status, Priority: t.Priority},
+			Comments: newTaskCommentsJSON(t.Comments),
+		})
+	}
+	if agentMode {
+		fmt.Printf("task: %s status=%s feature=%s priority=%s title=%q\n", t.ID, t.Status, t.Feature, t.Priority, t.Title)
+		writeTaskComments(os.Stdout, t.Comments)
+		return 0
+	}
+	fmt.Printf("%s %s [%s] [%s]: %s\n", t.ID, t.Feature, t.Status, t.Priority, t.Title)
+	for _, c := range t.Comments {
+		fmt.Printf("\n  %s  %s\n  %s\n", c.At.Local().Format("2006-01-02 15:04"), c.Author, c.Text)
+	}
+	return 0
+}
+
+type taskShowJSON struct {
+	taskJSON
+	Comments []taskCommentJSON `json:"comments"`
+}
+
+type taskCommentJSON struct {
+	At     string `json:"at"`
+	Author string `json:"author"`
+	Text   string `json:"text"`
+}
+
+func newTaskCommentsJSON(comments []core.TaskComment) []taskCommentJSON {
+	out := make([]taskCommentJSON, 0, len(comments))
+	for _, c := range comments {
+		out = append(out, taskCommentJSON{At: c.At.UTC().Format(time.RFC3339), Author: c.Author, Text: c.Text})
+	}
+	return out
+}
+
+// writeTaskComments prints one `comment:` line per entry for agents.
+func writeTaskComments(w io.Writer, comments []core.TaskComment) {
+	for _, c := range comments {
+		fmt.Fprintf(w, "comment: %s author=%s text=%q\n", c.At.UTC().Format(time.RFC3339), c.Author, c.Text)
+	}
+}
+
+// runTaskComment handles: task comment <id> <text> [--author NAME]
+func runTaskComment(cwd string, args []string, agentMode bool) int {
+	const usage = "usage: task comment <id> <text> [--author NAME]"
+	if len(args) < 2 || strings.HasPrefix(args[0], "--") {
+		return usageError(agentMode, "task", usage)
+	}
+	id, author := args[0], ""
+	var words []string
+	for i := 1; i < len(args); i++ {
+		if args[i] == "--author" {
+			if i+1 >= len(args) {
+				return usageError(agentMode, "task", "--author requires a name")
+			}
+			author = args[i+1]
+			i++
+			continue
+		}
+		words = append(words, args[i])
+	}
+
+	c, err := core.CommentTask(cwd, id, author, strings.Join(words, " "))
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if agentMode {
+		fmt.Printf("commented: %s author=%s\n", id, c.Author)
+	} else {
+		fmt.Printf("comment added to %s by %s\n", id, c.Author)
+	}
+	return 0
+}
+
+// runTaskClaim handles: task claim <id> [--ttl 30m] [--owner NAME]
+func runTaskClaim(cwd string, args []string, agentMode bool) int {
+	if len(args) < 1 || strings.HasPrefix(args[0], "--") {
+		return usageError(agentMode, "task", "usage: task claim <id> [--ttl 30m] [--owner NAME]")
+	}
+	id := args[0]
+	ttl, owner := core.DefaultLeaseTTL, core.LeaseOwner()
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--ttl":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "task", "--ttl requires a duration such as 30m")
+			}
+			d, err := time.ParseDuration(args[i+1])
+			if err != nil || d <= 0 {
+				return usageError(agentMode, "task", fmt.Sprintf("invalid --ttl value %q: use a duration such as 30m or 2h", args[i+1]))
+			}
+			ttl = d
+			i++
+		case "--owner":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "task", "--owner requires a name")
+			}
+			owner = args[i+1]
+			i++
+		default:
+			return usageError(agentMode, "task", "unknown flag: "+args[i])
+		}
+	}
+
+	lease, err := core.ClaimTask(cwd, id, owner, ttl, time.Now())
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	fmt.Printf("%s claimed by %s until %s\n", lease.Task, lease.Owner, lease.Expires.Local().Format("2006-01-02 15:04"))
+	return 0
+}
+
+// runTaskRelease handles: task release <id>
+func runTaskRelease(cwd string, args []string, agentMode bool) int {
+	if len(args) < 1 {
+		return usageError(agentMode, "task", "usage: task release <id>")
+	}
+	released, err := core.ReleaseTask(cwd, args[0])
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if !released {
+		fmt.Printf("%s was not claimed\n", args[0])
+		return 0
+	}
+	fmt.Printf("%s released\n", args[0])
+	return 0
+}
+
+// runTaskGraph handles: task graph [--format dot|mermaid]
+func runTaskGraph(cwd string, args []string, agentMode bool) int {
+	r := newRenderer(agentMode)
+	format := "dot"
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--format" {
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, r.RenderError("user", "--format requires a value: dot|mermaid"))
+				return 2
+			}
+			format = args[i+1]
+			i++
+		}
+	}
+	if format != "dot" && format != "mermaid" {
+		fmt.Fprintln(os.Stderr, r.RenderError("user", fmt.Sprintf("invalid --format %q: must be dot|mermaid", format)))
+		return 2
+	}
+
+	graph, err := core.BuildTaskGraph(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	fmt.Println(renderGraph(graph, format))
+	return 0
+}
+
+// runTaskImport handles: task import --from markdown <file|-> [--feature <id>] [--priority A|B|C] [--dry-run]
+func runTaskImport(cwd string, args []string, agentMode bool) int {
+	r := newRenderer(agentMode)
+	usage := "usage: task import --from markdown <file|-> [--feature <id>] [--priority A|B|C] [--dry-run]"
+
+	from := ""
+	path := ""
+	var opts core.TaskImportOptions
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--from", "--feature", "--priority":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, r.RenderError("user", args[i]+" requires a value"))
+				return 2
+			}
+			switch args[i] {
+			case "--from":
+				from = args[i+1]
+			case "--feature":
+				opts.Feature = args[i+1]
+			case "--priority":
+				opts.Priority = strings.ToUpper(args[i+1])
+			}
+			i++
+		case "--dry-run":
+			opts.DryRun = true
+		default:
+			path = args[i]
+		}
+	}
+	if from != "markdown" {
+		fmt.Fprintln(os.Stderr, r.RenderError("user", usage))
+		return 2
+	}
+	if path == "" {
+		fmt.Fprintln(os.Stderr, r.RenderError("user", usage))
+		return 2
+	}
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	result, err := core.ImportTasksMarkdown(cwd, string(data), opts)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	for _, t := range result.Added {
+		fmt.Printf("%s %s [%s] [%s]: %s\n", t.ID, t.Feature, t.Status, t.Priority, t.Title)
+	}
+	for _, s := range result.Skipped {
+		if agentMode {
+			fmt.Printf("skip:%d %s: %s\n", s.Line, s.Reason, s.Text)
+		} else {
+			fmt.Printf("skipped line %d (%s): %s\n", s.Line, s.Reason, s.Text)
+		}
+	}
+	verb := "imported"
+	if opts.DryRun {
+		verb = "would import"
+	}
+	if agentMode {
+		fmt.Printf("import added=%d skipped=%d dry_run=%v\n", len(result.Added), len(result.Skipped), opts.DryRun)
+	} else {
+		fmt.Printf("%s %d task(s), skipped %d line(s)\n", verb, len(result.Added), len(result.Skipped))
+	}
+	return 0
+}
+
+// renderGraph converts a core graph to its render view and formats it.
+func renderGraph(g core.TaskGraph, format string) string {
+	var view render.GraphView
+	for _, n := range g.Nodes {
+		view.Nodes = append(view.Nodes, render.GraphNodeView{ID: n.ID, Kind: n.Kind, Label: n.Label})
+	}
+	for _, e := range g.Edges {
+		view.Edges = append(view.Edges, render.GraphEdgeView{From: e.From, To: e.To, Label: e.Label, Blocked: e.Blocked})
+	}
+	if format == "mermaid" {
+		return render.RenderMermaid(view)
+	}
+	return render.RenderDOT(view)
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -329,6 +330,69 @@ func TestRunTask_Update(t *testing.T) {
 	})
 }
 
+func TestRunTask_ClaimAndRelease(t *testing.T) {
+	preloadedTasks := `tasks:
+  - id: T-001
+    feature: my-feat
+    title: Some task
+    status: TODO
+    priority: B
+`
+	dir := setupTaskProjectWithTasks(t, []string{"my-feat"}, preloadedTasks)
+	var codes []int
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			codes = append(codes,
+				RunTask([]string{"claim", "T-001", "--ttl", "10m", "--owner", "agent-1"}, true),
+				RunTask([]string{"claim", "T-001", "--owner", "agent-2"}, true),
+				RunTask([]string{"claim", "T-001", "--ttl", "later"}, true),
+				RunTask([]string{"release", "T-001"}, true),
+			)
+		})
+	})
+	// claimed, conflict (err:validation), bad --ttl (err:user), released
+	if want := []int{0, 1, 2, 0}; !slices.Equal(codes, want) {
+		t.Errorf("expected exit codes %v, got %v", want, codes)
+	}
+	if !strings.Contains(out, "T-001 claimed by agent-1 until") || !strings.Contains(out, "T-001 released") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestRunTask_CommentAndShow(t *testing.T) {
+	preloadedTasks := `tasks:
+  - id: T-001
+    feature: my-feat
+    title: Some task
+    status: TODO
+    priority: B
+`
+	dir := setupTaskProjectWithTasks(t, []string{"my-feat"}, preloadedTasks)
+	var codes []int
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			codes = append(codes,
+				RunTask([]string{"comment", "T-001", "chose", "JWT:", "sessions", "don't", "scale", "--author", "agent-1"}, true),
+				RunTask([]string{"show", "T-001"}, true),
+				RunTask([]string{"comment", "T-404", "hello"}, true),
+				RunTask([]string{"comment", "T-001"}, true),
+			)
+		})
+	})
+	if want := []int{0, 0, 1, 2}; !slices.Equal(codes, want) {
+		t.Errorf("expected exit codes %v, got %v", want, codes)
+	}
+	for _, want := range []string{
+		"commented: T-001 author=agent-1\n",
+		`task: T-001 status=TODO feature=my-feat priority=B title="Some task"`,
+		`author=agent-1 text="chose JWT: sessions don't scale"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
 func TestRunTask_Update_InvalidStatus(t *testing.T) {
 	preloadedTasks := `tasks:
   - id: T-001
@@ -701,3 +765,63 @@ func TestRunTask_List_FeatureFilter(t *testing.T) {
 		}
 	})
 }
+
+func TestRunTask_Graph(t *testing.T) {
+	dir := setupTaskProjectWithTasks(t, []string{"my-feat"},
+		"tasks:\n  - id: T-1\n    feature: my-feat\n    title: Build it\n    status: TODO\n    priority: A\n")
+
+	var code int
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			code = RunTask([]string{"graph", "--format", "mermaid"}, true)
+		})
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+	if !strings.Contains(out, "flowchart LR") || !strings.Contains(out, "n_my_feat --> n_T_1") {
+		t.Errorf("unexpected mermaid output:\n%s", out)
+	}
+}
+
+func TestRunTask_Graph_InvalidFormat(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	withDir(t, dir, func() {
+		if code := RunTask([]string{"graph", "--format", "svg"}, true); code != 2 {
+			t.Errorf("expected exit 2, got %d", code)
+		}
+	})
+}
+
+func TestRunTask_ImportMarkdown(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	plan := filepath.Join(dir, "plan.md")
+	if err := os.WriteFile(plan, []byte("## my-feat\n- [ ] Build it (A)\n- [ ] Other @ghost\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		withDir(t, dir, func() {
+			code = RunTask([]string{"import", "--from", "markdown", plan}, true)
+		})
+	})
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, out)
+	}
+	if !strings.Contains(out, "T-1 my-feat [TODO] [A]: Build it") {
+		t.Errorf("expected imported task in output:\n%s", out)
+	}
+	if !strings.Contains(out, "skip:3 unknown feature ghost") || !strings.Contains(out, "import added=1 skipped=1") {
+		t.Errorf("expected skip report in output:\n%s", out)
+	}
+}
+
+func TestRunTask_ImportRequiresMarkdown(t *testing.T) {
+	dir := setupTaskProject(t, "my-feat")
+	withDir(t, dir, func() {
+		if code := RunTask([]string{"import", "--from", "csv", "plan.csv"}, true); code != 2 {
+			t.Errorf("expected exit 2, got %d", code)
+		}
+	})
+}
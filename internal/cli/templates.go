@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunTemplates handles the `ptsd templates` command.
+// Subcommands:
+//
+//	ptsd templates list [--json]
+//	ptsd templates check [--json]
+func RunTemplates(args []string, agentMode bool) int {
+	const usage = "usage: ptsd templates list|check [--json]"
+	if len(args) == 0 {
+		return usageError(agentMode, "templates", usage)
+	}
+	jsonOut := jsonOutput
+	for _, a := range args[1:] {
+		if a != "--json" {
+			return usageError(agentMode, "templates", "unknown flag: "+a)
+		}
+		jsonOut = true
+	}
+	switch args[0] {
+	case "list":
+		return runTemplatesList(jsonOut, agentMode)
+	case "check":
+		return runTemplatesCheck(jsonOut, agentMode)
+	default:
+		return usageError(agentMode, "templates", usage)
+	}
+}
+
+func runTemplatesList(jsonOut, agentMode bool) int {
+	dir, err := core.TemplatesDir()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	templates := core.ListTemplates()
+
+	if jsonOut {
+		type varJSON struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		}
+		type templateJSON struct {
+			Name      string    `json:"name"`
+			Variables []varJSON `json:"variables"`
+			Override  string    `json:"override"`
+		}
+		out := struct {
+			Dir       string         `json:"dir"`
+			Templates []templateJSON `json:"templates"`
+		}{Dir: dir, Templates: []templateJSON{}}
+		for _, t := range templates {
+			tj := templateJSON{Name: t.Name, Variables: []varJSON{}, Override: t.Override}
+			for _, v := range t.Variables {
+				tj.Variables = append(tj.Variables, varJSON{v.Name, v.Description})
+			}
+			out.Templates = append(out.Templates, tj)
+		}
+		return printJSON(agentMode, "templates.list", out)
+	}
+
+	if agentMode {
+		for _, t := range templates {
+			var names []string
+			for _, v := range t.Variables {
+				names = append(names, v.Name)
+			}
+			source := "builtin"
+			if t.Override != "" {
+				source = t.Override
+			}
+			fmt.Printf("template: %s vars=%s source=%s\n", t.Name, strings.Join(names, ","), source)
+		}
+		fmt.Printf("templates: dir=%s\n", dir)
+		return 0
+	}
+	fmt.Printf("Overrides are read from %s\n", dir)
+	for _, t := range templates {
+		fmt.Printf("\n%s", t.Name)
+		if t.Override != "" {
+			fmt.Printf("  (overridden: %s)", t.Override)
+		}
+		fmt.Println()
+		for _, v := range t.Variables {
+			fmt.Printf("  {{.%s}}  %s\n", v.Name, v.Description)
+		}
+	}
+	return 0
+}
+
+func runTemplatesCheck(jsonOut, agentMode bool) int {
+	problems, checked, err := core.CheckTemplates()
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	if jsonOut {
+		type problemJSON struct {
+			Template string `json:"template"`
+			Path     string `json:"path"`
+			Message  string `json:"message"`
+		}
+		out := struct {
+			Checked  int           `json:"checked"`
+			Problems []problemJSON `json:"problems"`
+		}{Checked: checked, Problems: []problemJSON{}}
+		for _, p := range problems {
+			out.Problems = append(out.Problems, problemJSON{p.Name, p.Path, p.Message})
+		}
+		if code := printJSON(agentMode, "templates.check", out); code != 0 {
+			return code
+		}
+	} else if agentMode {
+		for _, p := range problems {
+			fmt.Printf("problem: %s %q\n", p.Name, p.Message)
+		}
+		fmt.Printf("templates: checked=%d problems=%d\n", checked, len(problems))
+	} else {
+		for _, p := range problems {
+			fmt.Printf("%s: %s\n", p.Path, p.Message)
+		}
+		if len(problems) == 0 {
+			fmt.Printf("%d override(s) checked, ok\n", checked)
+		} else {
+			fmt.Printf("\n%d override(s) checked, %d problem(s)\n", checked, len(problems))
+		}
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+	return 0
+}
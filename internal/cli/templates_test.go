@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunTemplates(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PTSD_TEMPLATES", dir)
+
+	out := captureStdout(t, func() {
+		if code := RunTemplates([]string{"list"}, true); code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+	})
+	if !strings.Contains(out, "template: prd.md.tmpl vars=Name source=builtin\n") {
+		t.Errorf("expected prd.md.tmpl in list, got:\n%s", out)
+	}
+	out = captureStdout(t, func() { RunTemplates([]string{"check"}, true) })
+	if out != "templates: checked=0 problems=0\n" {
+		t.Errorf("unexpected check output for no overrides: %q", out)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "prd.md.tmpl"), []byte("# {{.Title}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out = captureStdout(t, func() {
+		if code := RunTemplates([]string{"check"}, true); code != 1 {
+			t.Errorf("expected exit 1 for a broken override, got %d", code)
+		}
+	})
+	if !strings.HasPrefix(out, `problem: prd.md.tmpl "undefined variable .Title`) || !strings.HasSuffix(out, "templates: checked=1 problems=1\n") {
+		t.Errorf("unexpected check output:\n%s", out)
+	}
+	out = captureStdout(t, func() { RunTemplates([]string{"list"}, true) })
+	if !strings.Contains(out, "template: prd.md.tmpl vars=Name source="+filepath.Join(dir, "prd.md.tmpl")) {
+		t.Errorf("expected the override in list, got:\n%s", out)
+	}
+
+	if code := RunTemplates([]string{"render"}, true); code != 2 {
+		t.Errorf("expected exit 2 for an unknown subcommand, got %d", code)
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunTrace executes `ptsd trace <feature>`: the matrix of PRD acceptance
+// criteria against BDD scenarios. Exit 1 when a criterion has no scenario or
+// a scenario traces to no criterion. With --commits it lists the commits
+// attributed to the feature instead.
+func RunTrace(args []string, agentMode bool) int {
+	if len(args) == 0 {
+		return usageError(agentMode, "trace", "feature required: trace <feature> [--commits]")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	if len(args) > 1 {
+		if args[1] != "--commits" || len(args) > 2 {
+			return usageError(agentMode, "trace", "usage: trace <feature> [--commits]")
+		}
+		return runTraceCommits(cwd, args[0], agentMode)
+	}
+
+	m, err := core.TraceFeature(cwd, args[0])
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	uncovered, untraced := len(m.Uncovered()), len(m.Untraced())
+	if agentMode {
+		fmt.Printf("trace %s criteria=%d uncovered=%d scenarios=%d untraced=%d\n",
+			m.Feature, len(m.Criteria), uncovered, len(m.Scenarios), untraced)
+		for _, c := range m.Criteria {
+			if len(c.Scenarios) == 0 {
+				fmt.Printf("ac:%s uncovered %q\n", c.ID, c.Text)
+			} else {
+				fmt.Printf("ac:%s %s %q\n", c.ID, strings.Join(c.Scenarios, ","), c.Text)
+			}
+		}
+		for _, s := range m.Scenarios {
+			if len(s.Criteria) == 0 {
+				fmt.Printf("scenario:%s untraced %q\n", s.ID, s.Title)
+			}
+			for _, id := range s.Unknown {
+				fmt.Printf("scenario:%s unknown-ac %s\n", s.ID, id)
+			}
+		}
+	} else {
+		fmt.Printf("Acceptance criteria for %s:\n", m.Feature)
+		if len(m.Criteria) == 0 {
+			fmt.Println("  (none — add an \"Acceptance criteria\" list to the PRD section)")
+		}
+		for _, c := range m.Criteria {
+			status := "UNCOVERED"
+			if len(c.Scenarios) > 0 {
+				status = strings.Join(c.Scenarios, ", ")
+			}
+			fmt.Printf("  %-6s %s\n         -> %s\n", c.ID, c.Text, status)
+		}
+		if untraced > 0 {
+			fmt.Println("\nScenarios without a criterion:")
+			for _, s := range m.Untraced() {
+				fmt.Printf("  [%s] %s\n", s.ID, s.Title)
+			}
+		}
+		for _, s := range m.Scenarios {
+			for _, id := range s.Unknown {
+				fmt.Printf("  [%s] tags unknown criterion %s\n", s.ID, id)
+			}
+		}
+		fmt.Printf("\n%d/%d criteria covered, %d untraced scenario(s)\n", len(m.Criteria)-uncovered, len(m.Criteria), untraced)
+	}
+
+	if uncovered > 0 || untraced > 0 {
+		return 1
+	}
+	return 0
+}
+
+type traceCommitJSON struct {
+	Hash    string   `json:"hash"`
+	At      string   `json:"at"`
+	Subject string   `json:"subject"`
+	Source  string   `json:"source"`
+	Tasks   []string `json:"tasks"`
+}
+
+// runTraceCommits lists the commits whose [scope] or Ptsd-Feature trailer
+// names the feature, newest first.
+func runTraceCommits(cwd, feature string, agentMode bool) int {
+	commits, err := core.FeatureCommits(cwd, feature)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	if jsonOutput {
+		out := make([]traceCommitJSON, 0, len(commits))
+		for _, c := range commits {
+			out = append(out, traceCommitJSON{Hash: c.Hash, At: c.At.Format(time.RFC3339), Subject: c.Subject, Source: c.Source, Tasks: nonNil(c.Tasks)})
+		}
+		return printJSON(agentMode, "trace.commits", out)
+	}
+	if agentMode {
+		for _, c := range commits {
+			line := fmt.Sprintf("commit: %.12s at=%s source=%s", c.Hash, c.At.Format("2006-01-02"), c.Source)
+			if len(c.Tasks) > 0 {
+				line += " tasks=" + strings.Join(c.Tasks, ",")
+			}
+			fmt.Printf("%s subject=%q\n", line, c.Subject)
+		}
+		fmt.Printf("trace %s commits=%d\n", feature, len(commits))
+		return 0
+	}
+	if len(commits) == 0 {
+		fmt.Printf("No commits attributed to %s.\n", feature)
+		return 0
+	}
+	for _, c := range commits {
+		tasks := ""
+		if len(c.Tasks) > 0 {
+			tasks = "  (" + strings.Join(c.Tasks, ", ") + ")"
+		}
+		fmt.Printf("%.7s  %s  %s%s\n", c.Hash, c.At.Format("2006-01-02"), c.Subject, tasks)
+	}
+	return 0
+}
@@ -3,6 +3,11 @@ package cli
 import (
 	"fmt"
 	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/veschin/ptsd/internal/core"
 )
@@ -10,38 +15,403 @@ import (
 // RunValidate executes `ptsd validate`. Returns an exit code.
 // Exit 0 = clean, 1 = validation errors present.
 func RunValidate(args []string, agentMode bool) int {
+	rest, maxDepth, err := parseMaxDepthFlag(args)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	noCache, watch := false, false
+	useBaseline, updateBaseline, baselineReport := false, false, false
+	interval := core.DefaultWatchInterval
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case "--no-cache":
+			noCache = true
+		case "--watch":
+			watch = true
+		case "--baseline":
+			useBaseline = true
+		case "--update-baseline":
+			updateBaseline = true
+		case "--baseline-report":
+			baselineReport = true
+		case "--interval":
+			if i+1 >= len(rest) {
+				return usageError(agentMode, "validate", "--interval requires a duration (e.g. 500ms, 2s)")
+			}
+			d, err := time.ParseDuration(rest[i+1])
+			if err != nil || d <= 0 {
+				return usageError(agentMode, "validate", fmt.Sprintf("invalid --interval %q: use a duration like 500ms or 2s", rest[i+1]))
+			}
+			interval = d
+			i++
+		}
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return renderError(agentMode, "io", err.Error())
 	}
 
-	errs, err := core.Validate(cwd)
+	limits := core.ProjectWalkLimits(cwd)
+	if maxDepth > 0 {
+		limits.MaxDepth = maxDepth
+	}
+	if watch {
+		return runValidateWatch(cwd, limits, interval, agentMode)
+	}
+	if updateBaseline {
+		return runUpdateBaseline(cwd, limits, agentMode)
+	}
+	if baselineReport {
+		return runBaselineReport(cwd, limits, agentMode)
+	}
+	var baseline *core.Baseline
+	if useBaseline {
+		if baseline, err = core.LoadBaseline(cwd); err != nil {
+			return coreError(agentMode, err)
+		}
+	}
+	// The index counts as validated only if it is what validation reads.
+	tree := core.ValidationTree(cwd)
+	var errs []core.ValidationError
+	cached := false
+	if noCache || baseline != nil {
+		// A cached pass says nothing about which baseline errors remain.
+		errs, err = core.ValidateWithLimits(cwd, limits)
+	} else {
+		errs, cached, err = core.ValidateWithCache(cwd, limits)
+	}
 	if err != nil {
 		return coreError(agentMode, err)
 	}
 
+	var applied *core.BaselineResult
+	if baseline != nil {
+		r := baseline.Apply(errs)
+		applied = &r
+		errs = r.New
+	}
+	core.RecordValidation(cwd, errs)
+
+	lite, _ := core.LiteFeatures(cwd)
+	if jsonOutput {
+		return printValidateJSON(cwd, tree, errs, cached, lite, applied)
+	}
+	if len(lite) > 0 {
+		if agentMode {
+			fmt.Printf("lite: %s\n", strings.Join(lite, ","))
+		} else {
+			fmt.Printf("lite pipeline (seed/bdd skipped): %s\n", strings.Join(lite, ", "))
+		}
+	}
+	if applied != nil {
+		fmt.Printf("baseline: known=%d fixed=%d new=%d\n", len(applied.Known), len(applied.Fixed), len(applied.New))
+		if len(applied.Fixed) > 0 && !agentMode {
+			fmt.Println("run ptsd validate --update-baseline to drop fixed errors from the baseline")
+		}
+	}
+
 	if len(errs) == 0 {
+		// Remember the validated tree for the pre-push policy; best effort.
+		_ = core.RecordValidatedTree(cwd, tree)
 		if !agentMode {
-			fmt.Println("ok")
+			if cached {
+				fmt.Println("ok (cached)")
+			} else {
+				fmt.Println("ok")
+			}
 		}
 		return 0
 	}
 
+	printValidationReport(os.Stderr, errs, agentMode)
+	return 1
+}
+
+// printValidationReport writes validation findings to w. Agent mode keeps one
+// line per finding and ends with a summary token; human mode groups findings
+// by feature and rule, colored when w is a terminal, followed by per-category
+// counts and a summary line.
+func printValidationReport(w *os.File, errs []core.ValidationError, agentMode bool) {
+	errCount, warnCount, features := validationCounts(errs)
+	if agentMode {
+		for _, ve := range errs {
+			fmt.Fprintln(w, formatValidationError(ve, true))
+		}
+		fmt.Fprintf(w, "summary: errors=%d warnings=%d features=%d\n", errCount, warnCount, features)
+		return
+	}
+
+	color := useColor(w)
+	paint := func(code, s string) string {
+		if !color {
+			return s
+		}
+		return "\033[" + code + "m" + s + "\033[0m"
+	}
+
+	var order []string
+	groups := make(map[string][]core.ValidationError)
 	for _, ve := range errs {
-		if agentMode {
-			feature := ve.Feature
-			if feature == "" {
-				feature = "-"
+		if _, ok := groups[ve.Feature]; !ok && ve.Feature != "" {
+			order = append(order, ve.Feature)
+		}
+		groups[ve.Feature] = append(groups[ve.Feature], ve)
+	}
+	if _, ok := groups[""]; ok {
+		order = append(order, "")
+	}
+	for _, feature := range order {
+		findings := groups[feature]
+		name := feature
+		if name == "" {
+			name = "(global)"
+		}
+		fmt.Fprintf(w, "%s (%d)\n", paint("1", name), len(findings))
+		// Keep each rule's findings together, rules in order of first appearance.
+		firstSeen := make(map[string]int)
+		for i, ve := range findings {
+			if _, ok := firstSeen[ve.Rule]; !ok {
+				firstSeen[ve.Rule] = i
 			}
-			fmt.Fprintf(os.Stderr, "err:%s %s: %s\n", ve.Category, feature, ve.Message)
-		} else {
-			feature := ve.Feature
-			if feature == "" {
-				feature = "(global)"
+		}
+		sort.SliceStable(findings, func(i, j int) bool {
+			return firstSeen[findings[i].Rule] < firstSeen[findings[j].Rule]
+		})
+		for _, ve := range findings {
+			label := paint("31", "error")
+			if ve.IsWarning() {
+				label = paint("33", "warn ")
+			}
+			rule := ve.Rule
+			if rule == "" {
+				rule = ve.Category
 			}
-			fmt.Fprintf(os.Stderr, "[%s] %s: %s\n", ve.Category, feature, ve.Message)
+			fmt.Fprintf(w, "  %s %-12s %s\n", label, rule, ve.Message)
 		}
 	}
 
-	return 1
+	categories := make(map[string]int)
+	var names []string
+	for _, ve := range errs {
+		if categories[ve.Category] == 0 {
+			names = append(names, ve.Category)
+		}
+		categories[ve.Category]++
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, c := range names {
+		parts[i] = fmt.Sprintf("%s %d", c, categories[c])
+	}
+	fmt.Fprintf(w, "\nby category: %s\n", strings.Join(parts, ", "))
+	fmt.Fprintf(w, "%s, %s across %s\n",
+		paint("31", plural(errCount, "error")), paint("33", plural(warnCount, "warning")), plural(features, "feature"))
+}
+
+// validationCounts returns the number of errors and warnings and of distinct
+// features they concern; global findings belong to no feature.
+func validationCounts(errs []core.ValidationError) (errCount, warnCount, features int) {
+	seen := make(map[string]bool)
+	for _, ve := range errs {
+		if ve.IsWarning() {
+			warnCount++
+		} else {
+			errCount++
+		}
+		if ve.Feature != "" && !seen[ve.Feature] {
+			seen[ve.Feature] = true
+			features++
+		}
+	}
+	return errCount, warnCount, features
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// runValidateWatch re-runs validation whenever .ptsd/, BDD or test files
+// change, printing the files that changed, the errors that appeared (+) or
+// were fixed (-), and a summary line. It runs until interrupted.
+func runValidateWatch(cwd string, limits core.WalkLimits, interval time.Duration, agentMode bool) int {
+	stop := make(chan struct{})
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	go func() {
+		<-sig
+		close(stop)
+	}()
+
+	if !agentMode {
+		fmt.Printf("watching .ptsd/, BDD and test files every %s (Ctrl-C to stop)\n", interval)
+	}
+	core.WatchValidate(cwd, limits, interval, stop, func(ev core.WatchEvent) {
+		printWatchEvent(ev, agentMode)
+	})
+	return 0
+}
+
+func printWatchEvent(ev core.WatchEvent, agentMode bool) {
+	if len(ev.Changed) > 0 {
+		fmt.Printf("changed: %s\n", strings.Join(ev.Changed, ","))
+	}
+	if ev.Err != nil {
+		fmt.Println(ev.Err.Error())
+		return
+	}
+	for _, ve := range ev.Added {
+		fmt.Println("+" + formatValidationError(ve, agentMode))
+	}
+	for _, ve := range ev.Resolved {
+		fmt.Println("-" + formatValidationError(ve, agentMode))
+	}
+	if len(ev.Errors) == 0 {
+		fmt.Println("validate: ok")
+	} else {
+		fmt.Printf("validate: %d error(s)\n", len(ev.Errors))
+	}
+}
+
+func formatValidationError(ve core.ValidationError, agentMode bool) string {
+	if agentMode {
+		feature := ve.Feature
+		if feature == "" {
+			feature = "-"
+		}
+		prefix := "err"
+		if ve.IsWarning() {
+			prefix = "warn"
+		}
+		return fmt.Sprintf("%s:%s %s: %s", prefix, ve.Category, feature, ve.Message)
+	}
+	feature := ve.Feature
+	if feature == "" {
+		feature = "(global)"
+	}
+	return fmt.Sprintf("[%s] %s: %s", ve.Category, feature, ve.Message)
+}
+
+type validateJSON struct {
+	OK       bool                  `json:"ok"`
+	Cached   bool                  `json:"cached"`
+	Lite     []string              `json:"lite"`
+	Errors   []validationErrorJSON `json:"errors"`
+	Summary  validateSummaryJSON   `json:"summary"`
+	Baseline *baselineCountsJSON   `json:"baseline,omitempty"`
+}
+
+type validateSummaryJSON struct {
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Features int `json:"features"`
+}
+
+type baselineCountsJSON struct {
+	Known int `json:"known"`
+	Fixed int `json:"fixed"`
+	New   int `json:"new"`
+}
+
+type validationErrorJSON struct {
+	Category string `json:"category"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Feature  string `json:"feature"`
+	Message  string `json:"message"`
+}
+
+func printValidateJSON(cwd, tree string, errs []core.ValidationError, cached bool, lite []string, applied *core.BaselineResult) int {
+	out := validateJSON{OK: len(errs) == 0, Cached: cached, Lite: nonNil(lite), Errors: []validationErrorJSON{}}
+	out.Summary.Errors, out.Summary.Warnings, out.Summary.Features = validationCounts(errs)
+	if applied != nil {
+		out.Baseline = &baselineCountsJSON{Known: len(applied.Known), Fixed: len(applied.Fixed), New: len(applied.New)}
+	}
+	for _, ve := range errs {
+		severity := "error"
+		if ve.IsWarning() {
+			severity = "warn"
+		}
+		out.Errors = append(out.Errors, validationErrorJSON{Category: ve.Category, Rule: ve.Rule, Severity: severity, Feature: ve.Feature, Message: ve.Message})
+	}
+	printJSON(true, "validate", out)
+	if len(errs) > 0 {
+		return 1
+	}
+	_ = core.RecordValidatedTree(cwd, tree)
+	return 0
+}
+
+// runUpdateBaseline validates without the cache and accepts every current
+// error into .ptsd/validate-baseline.yaml.
+func runUpdateBaseline(cwd string, limits core.WalkLimits, agentMode bool) int {
+	errs, err := core.ValidateWithLimits(cwd, limits)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	b, err := core.UpdateBaseline(cwd, errs)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	if jsonOutput {
+		printJSON(true, "validate.baseline", newBaselineReportJSON(b, core.BaselineResult{Known: errs}))
+		return 0
+	}
+	fmt.Printf("baseline: updated total=%d\n", len(b.Errors))
+	return 0
+}
+
+// runBaselineReport prints the baseline's history followed by where the
+// project stands now: the burn-down of accepted errors over time.
+func runBaselineReport(cwd string, limits core.WalkLimits, agentMode bool) int {
+	b, err := core.LoadBaseline(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	errs, err := core.ValidateWithLimits(cwd, limits)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+	r := b.Apply(errs)
+	if jsonOutput {
+		printJSON(true, "validate.baseline", newBaselineReportJSON(b, r))
+		return 0
+	}
+	for _, h := range b.History {
+		if agentMode {
+			fmt.Printf("baseline: %s total=%d\n", h.At.Format(time.RFC3339), h.Total)
+		} else {
+			fmt.Printf("%s  %d\n", h.At.Format("2006-01-02 15:04"), h.Total)
+		}
+	}
+	if agentMode {
+		fmt.Printf("baseline: now total=%d fixed=%d new=%d\n", len(r.Known), len(r.Fixed), len(r.New))
+	} else {
+		fmt.Printf("now               %d (%d fixed, %d new)\n", len(r.Known), len(r.Fixed), len(r.New))
+	}
+	return 0
+}
+
+type baselineReportJSON struct {
+	Total   int                   `json:"total"`
+	Fixed   int                   `json:"fixed"`
+	New     int                   `json:"new"`
+	History []baselineHistoryJSON `json:"history"`
+}
+
+type baselineHistoryJSON struct {
+	At    string `json:"at"`
+	Total int    `json:"total"`
+}
+
+func newBaselineReportJSON(b *core.Baseline, r core.BaselineResult) baselineReportJSON {
+	out := baselineReportJSON{Total: len(r.Known), Fixed: len(r.Fixed), New: len(r.New), History: []baselineHistoryJSON{}}
+	for _, h := range b.History {
+		out.History = append(out.History, baselineHistoryJSON{At: h.At.Format(time.RFC3339), Total: h.Total})
+	}
+	return out
 }
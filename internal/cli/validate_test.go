@@ -408,3 +408,104 @@ func TestRunValidate_PlannedFeatureSkipped(t *testing.T) {
 		t.Errorf("expected exit 0 when only planned/deferred features exist, got %d", code)
 	}
 }
+
+func TestRunLint_ReportsErrorsWithSingleExitCode(t *testing.T) {
+	dir := t.TempDir()
+	setupPTSDDir(t, dir)
+	chdir(t, dir)
+	os.MkdirAll(filepath.Join(dir, ".ptsd", "bdd"), 0755)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "ghost.feature"), []byte("@feature:ghost\nFeature: Ghost\n"), 0644)
+
+	var code int
+	out := captureStdout(t, func() {
+		code = RunLint(nil, true)
+	})
+	if code != 1 {
+		t.Errorf("expected exit 1, got %d", code)
+	}
+	if !strings.Contains(out, "error:bdd ghost: unknown feature tag") || !strings.Contains(out, "lint errors=") {
+		t.Errorf("unexpected lint output: %q", out)
+	}
+}
+
+func TestRunValidate_Baseline(t *testing.T) {
+	dir := setupValidateViolationProject(t)
+	chdirTo(t, dir)
+
+	if code := RunValidate([]string{"--baseline"}, true); code != 2 {
+		t.Fatalf("expected exit 2 without a baseline file, got %d", code)
+	}
+
+	out := captureStdout(t, func() {
+		if code := RunValidate([]string{"--update-baseline"}, true); code != 0 {
+			t.Errorf("update-baseline exit %d", code)
+		}
+	})
+	if !strings.HasPrefix(out, "baseline: updated total=") || strings.Contains(out, "total=0") {
+		t.Fatalf("unexpected update output: %q", out)
+	}
+
+	out = captureStdout(t, func() {
+		if code := RunValidate([]string{"--baseline"}, true); code != 0 {
+			t.Errorf("known errors should pass with --baseline, got exit %d", code)
+		}
+	})
+	if !strings.Contains(out, "fixed=0 new=0") {
+		t.Errorf("unexpected baseline output: %q", out)
+	}
+
+	// A second active feature with no PRD anchor is a new violation.
+	features := "features:\n  - id: gamma\n    title: Gamma Feature\n    status: in-progress\n  - id: delta\n    title: Delta\n    status: in-progress\n"
+	os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte(features), 0644)
+	var code int
+	stderr := captureStderr(t, func() {
+		code = RunValidate([]string{"--baseline"}, true)
+	})
+	if code != 1 || !strings.Contains(stderr, "delta") || strings.Contains(stderr, "gamma") {
+		t.Errorf("expected only the new delta errors, exit 1; got %d %q", code, stderr)
+	}
+
+	out = captureStdout(t, func() {
+		RunValidate([]string{"--baseline-report"}, true)
+	})
+	if strings.Count(out, "baseline: ") != 2 || !strings.Contains(out, "baseline: now total=") {
+		t.Errorf("unexpected report: %q", out)
+	}
+}
+
+func TestRunValidate_AgentSummaryToken(t *testing.T) {
+	dir := setupValidateViolationProject(t)
+	chdirTo(t, dir)
+
+	stderr := captureStderr(t, func() {
+		RunValidate([]string{}, true)
+	})
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	if last := lines[len(lines)-1]; last != "summary: errors=2 warnings=0 features=1" {
+		t.Errorf("expected trailing summary token, got %q", stderr)
+	}
+}
+
+func TestRunValidate_HumanGroupedByFeature(t *testing.T) {
+	dir := setupValidateViolationProject(t)
+	features := "features:\n  - id: gamma\n    title: Gamma Feature\n    status: in-progress\n  - id: delta\n    title: Delta\n    status: in-progress\n"
+	os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte(features), 0644)
+	chdirTo(t, dir)
+
+	stderr := captureStderr(t, func() {
+		RunValidate([]string{}, false)
+	})
+	if strings.Contains(stderr, "\033[") {
+		t.Errorf("no colors expected when stderr is not a terminal: %q", stderr)
+	}
+	for _, want := range []string{
+		"delta (1)\n  error prd-anchor   has no prd anchor\n",
+		"gamma (2)\n  error bdd-seed     has bdd but no seed\n  error bdd-tests    has bdd but no tests\n",
+		"by category: pipeline 3\n",
+		"3 errors, 0 warnings across 2 features\n",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("missing %q in:\n%s", want, stderr)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunVerifyHooks handles `ptsd verify-hooks [--schema N]`. Generated git and
+// Claude Code hooks run it before anything else: a non-zero exit (or a
+// binary too old to know the command) makes the hook print a re-install
+// instruction instead of failing cryptically.
+func RunVerifyHooks(args []string, agentMode bool) int {
+	required := core.SchemaVersion
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--schema":
+			if i+1 >= len(args) {
+				return usageError(agentMode, "verify-hooks", "--schema requires a number")
+			}
+			v, err := strconv.Atoi(args[i+1])
+			if err != nil || v < 1 {
+				return usageError(agentMode, "verify-hooks", fmt.Sprintf("invalid --schema value %q", args[i+1]))
+			}
+			required = v
+			i++
+		default:
+			return usageError(agentMode, "verify-hooks", "unknown flag: "+args[i])
+		}
+	}
+
+	if err := core.CheckHookSchema(required); err != nil {
+		return coreError(agentMode, err)
+	}
+	if agentMode {
+		fmt.Printf("verify-hooks schema=%d required=%d ok\n", core.SchemaVersion, required)
+	} else {
+		fmt.Printf("ptsd %s supports schema %d (hooks need %d)\n", buildVersion(), core.SchemaVersion, required)
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+func TestRunVerifyHooks(t *testing.T) {
+	out := captureStdout(t, func() {
+		if code := RunVerifyHooks([]string{"--schema", fmt.Sprint(core.SchemaVersion)}, true); code != 0 {
+			t.Errorf("current schema: exit %d", code)
+		}
+	})
+	if !strings.Contains(out, "verify-hooks schema=") || !strings.HasSuffix(strings.TrimSpace(out), "ok") {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	out = captureStderr(t, func() {
+		if code := RunVerifyHooks([]string{"--schema", fmt.Sprint(core.SchemaVersion + 1)}, true); code != 3 {
+			t.Errorf("newer schema: exit %d, want 3", code)
+		}
+	})
+	if !strings.Contains(out, "err:config") || !strings.Contains(out, "ptsd hooks install") {
+		t.Errorf("newer schema output %q", out)
+	}
+
+	if code := RunVerifyHooks([]string{"--schema", "x"}, true); code != 2 {
+		t.Errorf("bad --schema: exit %d, want 2", code)
+	}
+}
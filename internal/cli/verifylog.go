@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/veschin/ptsd/internal/core"
+)
+
+// RunVerifyLog executes `ptsd verify-log`: checks the signed event chain and
+// signed state files (audit.sign: true). Exit 1 when anything fails to verify.
+func RunVerifyLog(args []string, agentMode bool) int {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return renderError(agentMode, "io", err.Error())
+	}
+
+	result, err := core.VerifyLog(cwd)
+	if err != nil {
+		return coreError(agentMode, err)
+	}
+
+	for _, f := range result.Findings {
+		if agentMode {
+			fmt.Printf("err:validation %s: %s\n", f.Subject, f.Message)
+		} else {
+			fmt.Printf("FAIL %s: %s\n", f.Subject, f.Message)
+		}
+	}
+	if agentMode {
+		fmt.Printf("verify-log events=%d signed=%d problems=%d\n", result.Events, result.Signed, len(result.Findings))
+	} else if len(result.Findings) == 0 {
+		fmt.Printf("ok — %d event(s), %d signed\n", result.Events, result.Signed)
+	} else {
+		fmt.Printf("\n%d problem(s) in %d event(s)\n", len(result.Findings), result.Events)
+	}
+
+	if len(result.Findings) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -6,10 +6,15 @@ import (
 )
 
 func RunVersion(args []string, agentMode bool) int {
-	version := "dev"
+	fmt.Printf("ptsd %s\n", buildVersion())
+	return 0
+}
+
+// buildVersion is the module version ptsd was built at, "dev" for local
+// builds.
+func buildVersion() string {
 	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
-		version = info.Main.Version
+		return info.Main.Version
 	}
-	fmt.Printf("ptsd %s\n", version)
-	return 0
+	return "dev"
 }
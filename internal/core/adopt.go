@@ -3,7 +3,9 @@ package core
 import (
 	"fmt"
 	"os"
+	pathpkg "path"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -12,137 +14,565 @@ type AdoptResult struct {
 	BDDFiles     []string // feature IDs discovered from .feature files
 	TestFiles    []string // test file paths discovered
 	FeaturesFile string   // path to features.yaml that would be created
+
+	// Incremental is true when .ptsd/ already existed: ptsd.yaml and the
+	// registered features are kept and only new artifacts are added.
+	Incremental bool
+	NewFeatures []string       // feature IDs not yet in features.yaml
+	Imports     []BDDImport    // .feature files moved into the BDD directory
+	Skipped     []string       // .feature files left in place: destination exists
+	TestMaps    []AdoptTestMap // map-file test mappings not yet in state.yaml
+	// Unmapped are discovered test files no feature maps, recorded in
+	// state.yaml for `ptsd test unmapped`; already recorded ones are left out.
+	Unmapped []string
+	// Stages are the stages --map-tests infers for features state.yaml has
+	// no stage for yet.
+	Stages []AdoptStage
+	// ScenarioMaps are the scenario-level mappings --map-tests made with the
+	// `ptsd test match` matcher once the test files were mapped (not on
+	// --dry-run).
+	ScenarioMaps []TestMatch
+
+	titles   map[string]string
+	mapTests bool
+}
+
+// AdoptStage is a stage adopt infers from the artifacts a feature has.
+type AdoptStage struct {
+	Feature string
+	Stage   string
+}
+
+// BDDImport is one .feature file adopt moves into the BDD directory
+// (.ptsd/bdd/ unless paths.bdd says otherwise).
+type BDDImport struct {
+	Src string // project-relative source
+	Dst string // project-relative destination in the BDD directory
+	// Feature is the ID assigned by the map file, "" when the file's own
+	// @feature: tag stands. Mapped files are retagged to Feature.
+	Feature string
+	// Append adds the file's scenarios to an existing Dst.
+	Append bool
+}
+
+// AdoptTestMap is a test file the map file, or with --map-tests the file
+// name convention (see conventionFeature), assigns to a feature.
+type AdoptTestMap struct {
+	Feature string
+	File    string
+	// Convention is true when the file name, not the map file, chose Feature.
+	Convention bool
+}
+
+// AdoptMapEntry is one feature in a map file. BDD and Tests are
+// project-relative paths or globs (** allowed, as in testing.patterns).
+type AdoptMapEntry struct {
+	ID    string
+	Title string
+	BDD   []string
+	Tests []string
+}
+
+// LoadAdoptMap reads a `ptsd adopt --map` file:
+//
+//	features:
+//	  auth:
+//	    title: Authentication
+//	    bdd: [specs/login.feature, specs/signup.feature]
+//	    tests:
+//	      - internal/auth/**/*_test.go
+//
+// Entries keep file order.
+func LoadAdoptMap(path string) ([]AdoptMapEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	var entries []AdoptMapEntry
+	var list *[]string
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		bad := func() error {
+			return fmt.Errorf("err:config %s:%d: unexpected %q", filepath.Base(path), n+1, trimmed)
+		}
+		switch {
+		case indent == 0:
+			if trimmed != "features:" {
+				return nil, bad()
+			}
+		case indent == 2:
+			id, rest, ok := strings.Cut(trimmed, ":")
+			if !ok || strings.TrimSpace(rest) != "" {
+				return nil, bad()
+			}
+			if !validFeatureID.MatchString(id) {
+				return nil, fmt.Errorf("err:config %s:%d: invalid feature ID %q: must be ASCII slug (a-z0-9 with hyphens)", filepath.Base(path), n+1, id)
+			}
+			entries = append(entries, AdoptMapEntry{ID: id})
+			list = nil
+		case indent == 4 && len(entries) > 0:
+			e := &entries[len(entries)-1]
+			key, val, _ := strings.Cut(trimmed, ":")
+			val = strings.TrimSpace(val)
+			switch key {
+			case "title":
+				e.Title = stripQuotes(val)
+				continue
+			case "bdd":
+				list = &e.BDD
+			case "tests":
+				list = &e.Tests
+			default:
+				return nil, bad()
+			}
+			if val != "" {
+				for _, item := range parseFeatureList(val) {
+					*list = append(*list, stripQuotes(item))
+				}
+				list = nil
+			}
+		case list != nil && strings.HasPrefix(trimmed, "- "):
+			*list = append(*list, stripQuotes(strings.TrimSpace(strings.TrimPrefix(trimmed, "- "))))
+		default:
+			return nil, bad()
+		}
+	}
+	return entries, nil
+}
+
+// mapFeature returns the ID of the first map entry whose patterns match rel.
+func mapFeature(entries []AdoptMapEntry, rel string, tests bool) string {
+	for _, e := range entries {
+		patterns := e.BDD
+		if tests {
+			patterns = e.Tests
+		}
+		for _, p := range patterns {
+			if p == rel || matchesTestPattern(rel, p) {
+				return e.ID
+			}
+		}
+	}
+	return ""
 }
 
 // AdoptProject bootstraps .ptsd/ structure for an existing project.
 // It scans for BDD .feature files and test files, extracts feature IDs,
-// and creates the .ptsd/ directory structure. Fails if .ptsd/ already exists.
+// and creates the .ptsd/ directory structure. Run again on an adopted
+// project it imports only what is new (see AdoptResult.Incremental).
 func AdoptProject(dir string) error {
-	ptsdDir := filepath.Join(dir, ".ptsd")
-	if _, err := os.Stat(ptsdDir); err == nil {
-		return fmt.Errorf("err:validation already initialized")
-	}
+	return AdoptProjectWithProgress(dir, nil)
+}
+
+// AdoptProjectWithProgress is AdoptProject reporting scan and import progress.
+func AdoptProjectWithProgress(dir string, progress ProgressFunc) error {
+	_, err := AdoptProjectWithOptions(dir, AdoptOptions{Progress: progress, Limits: DefaultWalkLimits})
+	return err
+}
 
-	result, err := scanProject(dir)
+// AdoptOptions tunes an adopt run. Limits bound the discovery walks.
+// MapFile, when set, assigns feature IDs to .feature and test files
+// explicitly (see LoadAdoptMap); relative paths are resolved against the
+// project directory. MapTests also maps test files to features by name,
+// infers stages from the artifacts found, and matches scenarios to tests.
+type AdoptOptions struct {
+	Progress ProgressFunc
+	Limits   WalkLimits
+	MapFile  string
+	MapTests bool
+}
+
+// AdoptProjectWithOptions is AdoptProject with progress reporting, walk
+// limits and a map file. It returns what was done.
+func AdoptProjectWithOptions(dir string, opts AdoptOptions) (*AdoptResult, error) {
+	progress := opts.Progress
+	result, err := scanProject(dir, opts, progress)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return applyAdopt(dir, result)
+	progress.emit(ProgressEvent{Phase: "import", Percent: 66})
+	if err := applyAdopt(dir, result); err != nil {
+		return nil, err
+	}
+	progress.emit(ProgressEvent{Phase: "done", Percent: 100})
+	return result, nil
 }
 
 // AdoptDryRun scans the project and returns what would be done without making changes.
 func AdoptDryRun(dir string) (*AdoptResult, error) {
-	ptsdDir := filepath.Join(dir, ".ptsd")
-	if _, err := os.Stat(ptsdDir); err == nil {
-		return nil, fmt.Errorf("err:validation already initialized")
-	}
+	return AdoptDryRunWithLimits(dir, DefaultWalkLimits)
+}
+
+// AdoptDryRunWithLimits is AdoptDryRun with explicit walk limits.
+func AdoptDryRunWithLimits(dir string, limits WalkLimits) (*AdoptResult, error) {
+	return AdoptDryRunWithOptions(dir, AdoptOptions{Limits: limits})
+}
 
-	return scanProject(dir)
+// AdoptDryRunWithOptions is AdoptDryRun with walk limits and a map file.
+func AdoptDryRunWithOptions(dir string, opts AdoptOptions) (*AdoptResult, error) {
+	return scanProject(dir, opts, nil)
 }
 
-// scanProject discovers BDD files and test files in the project directory.
-func scanProject(dir string) (*AdoptResult, error) {
+// scanProject discovers BDD files and test files in the project directory
+// and plans the import against whatever .ptsd/ already holds.
+func scanProject(dir string, opts AdoptOptions, progress ProgressFunc) (*AdoptResult, error) {
+	ptsdDir := filepath.Join(dir, ".ptsd")
 	result := &AdoptResult{
-		FeaturesFile: filepath.Join(dir, ".ptsd", "features.yaml"),
+		FeaturesFile: filepath.Join(ptsdDir, "features.yaml"),
+		titles:       make(map[string]string),
+		mapTests:     opts.MapTests,
+	}
+
+	var entries []AdoptMapEntry
+	if opts.MapFile != "" {
+		path := opts.MapFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		var err error
+		if entries, err = LoadAdoptMap(path); err != nil {
+			return nil, err
+		}
+	}
+
+	known := make(map[string]bool)
+	state := &State{Features: make(map[string]FeatureState)}
+	if _, err := os.Stat(ptsdDir); err == nil {
+		result.Incremental = true
+		if _, err := os.Stat(result.FeaturesFile); err == nil {
+			features, err := loadFeatures(dir)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range features {
+				known[f.ID] = true
+			}
+		}
+		if state, err = LoadState(dir); err != nil {
+			return nil, err
+		}
 	}
 
 	// Discover BDD .feature files with @feature: tags
-	bddFiles, err := discoverBDDFiles(dir)
+	progress.emit(ProgressEvent{Phase: "scan-bdd", Percent: 0})
+	sources, err := discoverBDDFiles(dir, opts.Limits, progress)
 	if err != nil {
 		return nil, err
 	}
-	result.BDDFiles = bddFiles
+	seen := make(map[string]bool)
+	addFeature := func(id string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		if !known[id] {
+			result.NewFeatures = append(result.NewFeatures, id)
+		}
+	}
+	planned := make(map[string]bool)
+	bddDir := bddDirRel(dir)
+	for _, src := range sources {
+		imp := BDDImport{Src: src.Path, Feature: mapFeature(entries, src.Path, false)}
+		ids := src.Tags
+		if imp.Feature == "" && bddFileFeature(dir, src.Path) != "" {
+			// Already in the BDD directory (paths.bdd): register in place.
+			for _, id := range ids {
+				if !seen[id] {
+					result.BDDFiles = append(result.BDDFiles, id)
+				}
+				addFeature(id)
+			}
+			continue
+		}
+		if imp.Feature != "" {
+			ids = []string{imp.Feature}
+			imp.Dst = filepath.Join(filepath.FromSlash(bddDir), imp.Feature+".feature")
+		} else {
+			imp.Dst = filepath.Join(filepath.FromSlash(bddDir), filepath.Base(src.Path))
+		}
+
+		_, statErr := os.Stat(filepath.Join(dir, imp.Dst))
+		exists := (statErr == nil && imp.Dst != imp.Src) || planned[imp.Dst]
+		if exists && imp.Feature == "" {
+			result.Skipped = append(result.Skipped, src.Path)
+			continue
+		}
+		imp.Append = exists
+		planned[imp.Dst] = true
+		result.Imports = append(result.Imports, imp)
+		for _, id := range ids {
+			if !seen[id] {
+				result.BDDFiles = append(result.BDDFiles, id)
+			}
+			addFeature(id)
+		}
+	}
+	for _, e := range entries {
+		addFeature(e.ID)
+		if e.Title != "" {
+			result.titles[e.ID] = e.Title
+		}
+	}
 
 	// Discover test files using default pattern
-	testFiles, err := discoverTestFiles(dir)
+	progress.emit(ProgressEvent{Phase: "scan-tests", Percent: 33})
+	testFiles, mapped, err := discoverTestFiles(dir, opts.Limits, progress, entries)
 	if err != nil {
 		return nil, err
 	}
 	result.TestFiles = testFiles
+	isMapped := make(map[string]bool)
+	for _, m := range mapped {
+		isMapped[m.File] = true
+		if !hasTestMapping(state.Features[m.Feature], m.File) {
+			result.TestMaps = append(result.TestMaps, m)
+		}
+	}
+	var ids []string
+	for id := range known {
+		ids = append(ids, id)
+	}
+	ids = append(ids, result.NewFeatures...)
+	slices.Sort(ids)
+	if opts.MapTests {
+		features := make([]Feature, len(ids))
+		for i, id := range ids {
+			features[i] = Feature{ID: id}
+		}
+		for _, f := range testFiles {
+			if isMapped[f] || mappedByAnyFeature(state, f) {
+				continue
+			}
+			if id := conventionFeature(f, features); id != "" {
+				result.TestMaps = append(result.TestMaps, AdoptTestMap{Feature: id, File: f, Convention: true})
+				isMapped[f] = true
+			}
+		}
+	}
+	recorded := make(map[string]bool)
+	for _, f := range state.Unmapped {
+		recorded[f] = true
+	}
+	for _, f := range testFiles {
+		if !isMapped[f] && !recorded[f] && !mappedByAnyFeature(state, f) {
+			result.Unmapped = append(result.Unmapped, f)
+		}
+	}
+
+	if opts.MapTests {
+		hasBDD := make(map[string]bool)
+		for _, id := range result.BDDFiles {
+			hasBDD[id] = true
+		}
+		hasTests := make(map[string]bool)
+		for _, m := range result.TestMaps {
+			hasTests[m.Feature] = true
+		}
+		for _, id := range ids {
+			fs := state.Features[id]
+			if fs.Stage != "" {
+				continue
+			}
+			if tests, _ := fs.Tests.([]string); len(tests) > 0 {
+				hasTests[id] = true
+			}
+			if stage := adoptStage(dir, id, hasBDD[id], hasTests[id]); stage != "" {
+				result.Stages = append(result.Stages, AdoptStage{Feature: id, Stage: stage})
+			}
+		}
+	}
 
 	return result, nil
 }
 
-// discoverBDDFiles finds .feature files and extracts feature IDs from @feature: tags.
-func discoverBDDFiles(dir string) ([]string, error) {
-	var featureIDs []string
-	seen := make(map[string]bool)
+// conventionFeature maps a test file to a feature by name: the file name
+// without its test affixes (auth_test.go, test_auth.py, AuthTest.java), as
+// gate-check does for writes, or else the nearest directory named like a
+// feature (internal/auth/handler_test.go).
+func conventionFeature(rel string, features []Feature) string {
+	rel = filepath.ToSlash(rel)
+	base := pathpkg.Base(rel)
+	for _, affix := range []string{"_test.go", ".test.tsx", ".test.ts", ".test.js", ".spec.ts", ".spec.js", "_spec.rb", "_test.py", "Tests.java", "Test.java"} {
+		if stem, ok := strings.CutSuffix(base, affix); ok {
+			base = stem
+			break
+		}
+	}
+	base = strings.TrimSuffix(strings.TrimPrefix(base, "test_"), ".py")
+	if id := matchFeatureID(strings.Join(nameWords(base), "-"), features); id != "" {
+		return id
+	}
+	dirs := strings.Split(pathpkg.Dir(rel), "/")
+	for i := len(dirs) - 1; i >= 0; i-- {
+		name := strings.Join(nameWords(dirs[i]), "-")
+		for _, f := range features {
+			if f.ID == name {
+				return f.ID
+			}
+		}
+	}
+	return ""
+}
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
+// adoptStage is the latest built-in stage up to tests whose artifact the
+// feature has: a PRD anchor, seed.yaml, a BDD file, mapped tests. impl is
+// left to a passing `ptsd test run`. Stages a lite feature skips do not
+// count; "" when it has none.
+func adoptStage(dir, id string, hasBDD, hasTests bool) string {
+	stage := ""
+	for _, s := range FeatureStages(dir, id) {
+		var ok bool
+		switch s {
+		case "prd":
+			ok = prdFileFor(dir, id) != ""
+		case "seed":
+			ok = fileExists(seedManifestPath(dir, id))
+		case "bdd":
+			ok = hasBDD || fileExists(bddFilePath(dir, id))
+		case "tests":
+			ok = hasTests
+		}
+		if ok {
+			stage = s
 		}
-		// Skip .ptsd directory (shouldn't exist yet, but be safe)
-		if info.IsDir() && filepath.Base(path) == ".ptsd" {
-			return filepath.SkipDir
+	}
+	return stage
+}
+
+// mappedByAnyFeature reports whether some feature in state maps file.
+func mappedByAnyFeature(state *State, file string) bool {
+	for _, fs := range state.Features {
+		if hasTestMapping(fs, file) {
+			return true
 		}
+	}
+	return false
+}
+
+// hasTestMapping reports whether any of fs's test mappings names file.
+func hasTestMapping(fs FeatureState, file string) bool {
+	existing, _ := fs.Tests.([]string)
+	for _, m := range existing {
+		if parseTestMapping(m).File == file {
+			return true
+		}
+	}
+	return false
+}
+
+// bddSource is a .feature file found outside .ptsd/.
+type bddSource struct {
+	Path string   // project-relative
+	Tags []string // @feature: IDs in the file
+}
+
+// discoverBDDFiles finds .feature files and extracts feature IDs from @feature: tags.
+func discoverBDDFiles(dir string, limits WalkLimits, progress ProgressFunc) ([]bddSource, error) {
+	var sources []bddSource
+
+	err := walkProject(dir, limits, func(path string, info os.FileInfo) error {
 		if !strings.HasSuffix(path, ".feature") {
 			return nil
 		}
-
-		data, err := os.ReadFile(path)
+		rel, err := filepath.Rel(dir, path)
 		if err != nil {
-			return nil
+			rel = path
 		}
+		progress.emit(ProgressEvent{Phase: "scan-bdd", Percent: -1, File: rel})
 
-		for _, line := range strings.Split(string(data), "\n") {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "@feature:") {
-				id := strings.TrimPrefix(line, "@feature:")
-				id = strings.TrimSpace(id)
-				if id != "" && !seen[id] {
-					seen[id] = true
-					featureIDs = append(featureIDs, id)
+		src := bddSource{Path: rel}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				line = strings.TrimSpace(line)
+				if strings.HasPrefix(line, "@feature:") {
+					id := strings.TrimPrefix(line, "@feature:")
+					id = strings.TrimSpace(id)
+					if id != "" {
+						src.Tags = append(src.Tags, id)
+					}
 				}
 			}
 		}
+		sources = append(sources, src)
 		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("err:io %w", err)
+		return nil, err
 	}
 
-	return featureIDs, nil
+	return sources, nil
+}
+
+// testFileGlobs are the test file name conventions adopt recognizes: Go,
+// JavaScript/TypeScript (Jest, Vitest, Mocha), RSpec and pytest. JUnit-style
+// tests are recognized by their src/test/java tree instead (isTestFile).
+var testFileGlobs = []string{"*_test.go", "*.test.ts", "*.test.tsx", "*.spec.js", "*_spec.rb", "test_*.py", "*_test.py"}
+
+// isTestFile reports whether a project-relative path looks like a test file
+// of a supported ecosystem.
+func isTestFile(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	if strings.HasSuffix(rel, ".java") && (strings.HasPrefix(rel, "src/test/java/") || strings.Contains(rel, "/src/test/java/")) {
+		return true
+	}
+	base := pathpkg.Base(rel)
+	for _, g := range testFileGlobs {
+		if ok, _ := pathpkg.Match(g, base); ok {
+			return true
+		}
+	}
+	return false
 }
 
-// discoverTestFiles finds test files matching the default Go test pattern.
-func discoverTestFiles(dir string) ([]string, error) {
+// discoverTestFiles finds test files of every supported ecosystem (see
+// isTestFile), plus any file the map entries' tests patterns assign to a
+// feature.
+func discoverTestFiles(dir string, limits WalkLimits, progress ProgressFunc, entries []AdoptMapEntry) ([]string, []AdoptTestMap, error) {
 	var testFiles []string
+	var mapped []AdoptTestMap
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	err := walkProject(dir, limits, func(path string, info os.FileInfo) error {
+		rel, err := filepath.Rel(dir, path)
 		if err != nil {
-			return nil
-		}
-		if info.IsDir() && filepath.Base(path) == ".ptsd" {
-			return filepath.SkipDir
+			rel = path
 		}
-		if strings.HasSuffix(path, "_test.go") {
-			rel, err := filepath.Rel(dir, path)
-			if err != nil {
-				rel = path
-			}
+		rel = filepath.ToSlash(rel)
+		id := mapFeature(entries, rel, true)
+		if isTestFile(rel) || id != "" {
 			testFiles = append(testFiles, rel)
+			progress.emit(ProgressEvent{Phase: "scan-tests", Percent: -1, File: rel})
+		}
+		if id != "" {
+			mapped = append(mapped, AdoptTestMap{Feature: id, File: rel})
 		}
 		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("err:io %w", err)
+		return nil, nil, err
 	}
 
-	return testFiles, nil
+	return testFiles, mapped, nil
 }
 
-// applyAdopt creates the .ptsd/ directory structure and imports discovered artifacts.
+// applyAdopt creates the .ptsd/ directory structure and imports discovered
+// artifacts. An incremental run keeps ptsd.yaml and existing features.
 func applyAdopt(dir string, result *AdoptResult) error {
 	ptsdDir := filepath.Join(dir, ".ptsd")
 
 	// Create directory structure
+	paths := artifactPaths(dir)
 	dirs := []string{
 		ptsdDir,
-		filepath.Join(ptsdDir, "seeds"),
-		filepath.Join(ptsdDir, "bdd"),
-		filepath.Join(ptsdDir, "docs"),
+		filepath.Join(dir, filepath.FromSlash(paths.Seeds)),
+		filepath.Join(dir, filepath.FromSlash(paths.BDD)),
+		filepath.Join(dir, filepath.FromSlash(prdDirRel(dir))),
 		filepath.Join(ptsdDir, "skills"),
 	}
 	for _, d := range dirs {
@@ -151,52 +581,197 @@ func applyAdopt(dir string, result *AdoptResult) error {
 		}
 	}
 
-	// Create ptsd.yaml with defaults
-	ptsdYAML := "project:\n  name: \"\"\ntesting:\n  patterns:\n    files: [\"**/*_test.go\"]\nreview:\n  min_score: 7\n"
-	if err := os.WriteFile(filepath.Join(ptsdDir, "ptsd.yaml"), []byte(ptsdYAML), 0644); err != nil {
-		return fmt.Errorf("err:io %w", err)
+	if !result.Incremental {
+		// Create ptsd.yaml with defaults
+		ptsdYAML := schemaHeader() + "project:\n  name: \"\"\ntesting:\n  patterns:\n    files: [\"**/*_test.go\"]\nreview:\n  min_score: 7\n"
+		if err := writeFileAtomic(filepath.Join(ptsdDir, "ptsd.yaml"), []byte(ptsdYAML), 0644); err != nil {
+			return fmt.Errorf("err:io %w", err)
+		}
 	}
 
-	// Create features.yaml from discovered BDD feature IDs
-	var b strings.Builder
-	b.WriteString("features:\n")
-	for _, id := range result.BDDFiles {
-		b.WriteString("  - id: " + id + "\n")
-		b.WriteString("    title: " + id + "\n")
-		b.WriteString("    status: planned\n")
+	// Register new feature IDs from BDD tags and the map file
+	var features []Feature
+	if _, err := os.Stat(result.FeaturesFile); err == nil {
+		if features, err = loadFeatures(dir); err != nil {
+			return err
+		}
+	}
+	for _, id := range result.NewFeatures {
+		title := result.titles[id]
+		if title == "" {
+			title = id
+		}
+		features = append(features, Feature{ID: id, Title: title, Status: "planned"})
 	}
-	if err := os.WriteFile(filepath.Join(ptsdDir, "features.yaml"), []byte(b.String()), 0644); err != nil {
+	if err := saveFeatures(dir, features); err != nil {
 		return fmt.Errorf("err:io %w", err)
 	}
 
-	// Move discovered .feature files to .ptsd/bdd/
-	bddDir := filepath.Join(ptsdDir, "bdd")
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	// Move discovered .feature files to the BDD directory
+	for _, imp := range result.Imports {
+		src := filepath.Join(dir, imp.Src)
+		dst := filepath.Join(dir, imp.Dst)
+		data, err := os.ReadFile(src)
 		if err != nil {
-			return nil
+			continue
 		}
-		if info.IsDir() && filepath.Base(path) == ".ptsd" {
-			return filepath.SkipDir
+		content := string(data)
+		if imp.Feature != "" {
+			content = retagFeature(content, imp.Feature)
 		}
-		if !strings.HasSuffix(path, ".feature") {
-			return nil
+		if imp.Append {
+			existing, err := os.ReadFile(dst)
+			if err != nil {
+				return fmt.Errorf("err:io %w", err)
+			}
+			content = strings.TrimRight(string(existing), "\n") + "\n\n" + featureBody(content)
 		}
-
-		basename := filepath.Base(path)
-		dst := filepath.Join(bddDir, basename)
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return nil
+		if err := writeFileAtomic(dst, []byte(content), 0644); err != nil {
+			return fmt.Errorf("err:io %w", err)
+		}
+		if imp.Src == imp.Dst {
+			continue // retagged in place
 		}
-		if err := os.WriteFile(dst, data, 0644); err != nil {
+		if err := os.Remove(src); err != nil {
 			return fmt.Errorf("err:io %w", err)
 		}
-		return os.Remove(path)
-	})
+	}
+
+	// Record map-file test mappings, against the feature's BDD file when it
+	// has one, and the remaining test files as unmapped candidates
+	if len(result.TestMaps) == 0 && len(result.Unmapped) == 0 && len(result.Stages) == 0 {
+		return nil
+	}
+	state, err := LoadState(dir)
 	if err != nil {
-		return fmt.Errorf("err:io %w", err)
+		return err
+	}
+	for _, m := range result.TestMaps {
+		fs, ok := state.Features[m.Feature]
+		if !ok {
+			fs = FeatureState{Hashes: make(map[string]string), Scores: make(map[string]ScoreEntry)}
+		}
+		mapping := m.File
+		bdd := bddFileRel(dir, m.Feature)
+		if _, err := os.Stat(filepath.Join(dir, bdd)); err == nil {
+			mapping = bdd + "::" + m.File
+		}
+		tests, _ := fs.Tests.([]string)
+		fs.Tests = append(tests, mapping)
+		state.Features[m.Feature] = fs
+		state.Unmapped = slices.DeleteFunc(state.Unmapped, func(f string) bool { return f == m.File })
+	}
+	state.Unmapped = append(state.Unmapped, result.Unmapped...)
+	for _, st := range result.Stages {
+		fs, ok := state.Features[st.Feature]
+		if !ok {
+			fs = FeatureState{Hashes: make(map[string]string), Scores: make(map[string]ScoreEntry)}
+		}
+		fs.Stage = st.Stage
+		state.Features[st.Feature] = fs
+	}
+	if err := writeState(dir, state); err != nil {
+		return err
+	}
+	if err := recordAdoptStages(dir, result.Stages); err != nil {
+		return err
+	}
+	if !result.mapTests {
+		return nil
 	}
 
+	// Scenario-level mappings for the features that now have test files.
+	matched := make(map[string]bool)
+	for _, m := range result.TestMaps {
+		if matched[m.Feature] {
+			continue
+		}
+		matched[m.Feature] = true
+		res, err := MatchTests(dir, m.Feature, TestMatchThreshold, false)
+		if err != nil {
+			continue // no BDD file, or nothing to match
+		}
+		result.ScenarioMaps = append(result.ScenarioMaps, res.Matches...)
+	}
+	return nil
+}
+
+// recordAdoptStages mirrors inferred stages into review-status.yaml and the
+// event log, as `ptsd stage set` does.
+func recordAdoptStages(dir string, stages []AdoptStage) error {
+	if len(stages) == 0 {
+		return nil
+	}
+	rs, err := loadReviewStatus(dir)
+	if err != nil {
+		return err
+	}
+	for _, st := range stages {
+		entry, ok := rs[st.Feature]
+		if !ok {
+			entry = ReviewStatusEntry{Tests: "absent", Review: "pending"}
+		}
+		entry.Stage = st.Stage
+		if st.Stage == "tests" && entry.Tests == "absent" {
+			entry.Tests = "written"
+		}
+		rs[st.Feature] = entry
+	}
+	if err := saveReviewStatus(dir, rs); err != nil {
+		return fmt.Errorf("err:io failed to save review-status: %w", err)
+	}
+	for _, st := range stages {
+		if err := AppendEvent(dir, Event{Type: EventStage, Feature: st.Feature, Stage: st.Stage, Detail: "adopt: inferred from artifacts"}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// retagFeature makes id the only @feature: tag of a .feature file.
+func retagFeature(content, id string) string {
+	lines := strings.Split(content, "\n")
+	var out []string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "Feature:") {
+			out = append(out, "@feature:"+id)
+			return strings.Join(append(out, lines[i:]...), "\n")
+		}
+		if strings.HasPrefix(trimmed, "@") {
+			var kept []string
+			for _, tag := range strings.Fields(trimmed) {
+				if !strings.HasPrefix(tag, "@feature:") {
+					kept = append(kept, tag)
+				}
+			}
+			if len(kept) == 0 {
+				continue
+			}
+			line = strings.Join(kept, " ")
+		}
+		out = append(out, line)
+	}
+	return "@feature:" + id + "\n" + content
+}
+
+// featureBody is a .feature file from its first Background, Rule or
+// scenario (or the tags above it) on: what appending it to another file of
+// the same feature needs.
+func featureBody(content string) string {
+	lines := strings.Split(content, "\n")
+	inFeature := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !inFeature {
+			inFeature = strings.HasPrefix(trimmed, "Feature:")
+			continue
+		}
+		for _, kw := range []string{"@", "Background:", "Rule:", "Scenario", "Example:"} {
+			if strings.HasPrefix(trimmed, kw) {
+				return strings.Join(lines[i:], "\n")
+			}
+		}
+	}
+	return ""
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -36,19 +37,168 @@ func TestAdoptCreatesStructure(t *testing.T) {
 	}
 }
 
-// TestAdoptRefusesIfAlreadyInitialized verifies adopt fails when .ptsd/ exists.
-func TestAdoptRefusesIfAlreadyInitialized(t *testing.T) {
-	dir := setupProjectWithFeatures(t, "existing:planned")
+// TestAdoptIncrementalKeepsExisting verifies adopt on an adopted project
+// adds only new features and leaves ptsd.yaml and existing BDD files alone.
+func TestAdoptIncrementalKeepsExisting(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "existing:in-progress")
+	config := filepath.Join(dir, ".ptsd", "ptsd.yaml")
+	os.WriteFile(config, []byte("project:\n  name: \"kept\"\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "existing.feature"), []byte("@feature:existing\nFeature: Existing\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "specs"), 0755)
+	os.WriteFile(filepath.Join(dir, "specs", "existing.feature"), []byte("@feature:existing\nFeature: Clash\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "specs", "billing.feature"), []byte("@feature:billing\nFeature: Billing\n"), 0644)
 
-	err := AdoptProject(dir)
-	if err == nil {
-		t.Fatal("expected error for already-initialized project")
+	result, err := AdoptProjectWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits})
+	if err != nil {
+		t.Fatalf("incremental adopt failed: %v", err)
+	}
+	if !result.Incremental {
+		t.Error("expected an incremental run")
+	}
+	if len(result.NewFeatures) != 1 || result.NewFeatures[0] != "billing" {
+		t.Errorf("expected only billing to be new, got %v", result.NewFeatures)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != filepath.Join("specs", "existing.feature") {
+		t.Errorf("expected the clashing file to be skipped, got %v", result.Skipped)
+	}
+
+	features, err := loadFeatures(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(features) != 2 || features[0].ID != "existing" || features[0].Status != "in-progress" || features[1].ID != "billing" {
+		t.Errorf("unexpected registry after incremental adopt: %+v", features)
+	}
+	if data, _ := os.ReadFile(config); !strings.Contains(string(data), "kept") {
+		t.Errorf("ptsd.yaml was overwritten:\n%s", data)
 	}
-	if !strings.Contains(err.Error(), "err:validation") {
-		t.Errorf("expected err:validation, got: %v", err)
+	if data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "bdd", "existing.feature")); strings.Contains(string(data), "Clash") {
+		t.Error("existing BDD file was overwritten")
 	}
-	if !strings.Contains(err.Error(), "already initialized") {
-		t.Errorf("expected 'already initialized' in error, got: %v", err)
+	if _, err := os.Stat(filepath.Join(dir, "specs", "existing.feature")); err != nil {
+		t.Error("skipped .feature file should stay in place")
+	}
+
+	// A second run finds nothing left to do.
+	result, err = AdoptProjectWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.NewFeatures) != 0 || len(result.Imports) != 0 {
+		t.Errorf("expected a no-op rerun, got %+v", result)
+	}
+}
+
+// TestAdoptRegistersFilesInConfiguredBDDDir verifies that with paths.bdd
+// pointing at an existing directory, adopt registers its feature files in
+// place instead of moving them.
+func TestAdoptRegistersFilesInConfiguredBDDDir(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".ptsd"), 0755)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("paths:\n  bdd: features\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "features"), 0755)
+	os.WriteFile(filepath.Join(dir, "features", "login.feature"), []byte("@feature:login\nFeature: Login\n"), 0644)
+
+	result, err := AdoptProjectWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits})
+	if err != nil {
+		t.Fatalf("adopt failed: %v", err)
+	}
+	if len(result.NewFeatures) != 1 || result.NewFeatures[0] != "login" {
+		t.Errorf("expected login to be registered, got %v", result.NewFeatures)
+	}
+	if len(result.Imports) != 0 || len(result.Skipped) != 0 {
+		t.Errorf("expected no moves or skips, got imports=%v skipped=%v", result.Imports, result.Skipped)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "features", "login.feature")); err != nil {
+		t.Error("feature file should stay in features/")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ptsd", "bdd")); err == nil {
+		t.Error(".ptsd/bdd should not be created when paths.bdd is set")
+	}
+}
+
+// TestAdoptMapFile verifies --map assigns untagged .feature files and test
+// files to features, merging several files into one feature's BDD file.
+func TestAdoptMapFile(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "specs"), 0755)
+	os.MkdirAll(filepath.Join(dir, "auth"), 0755)
+	os.MkdirAll(filepath.Join(dir, "web"), 0755)
+	os.WriteFile(filepath.Join(dir, "specs", "login.feature"), []byte("@smoke @feature:old\nFeature: Login\n  Scenario: Sign in\n    Given a user\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "specs", "signup.feature"), []byte("Feature: Signup\n  Describes signup.\n\n  Scenario: Register\n    Given a visitor\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "auth", "login_test.go"), []byte("package auth\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "web", "cart.test.ts"), []byte("test()\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "features.map.yaml"), []byte(`features:
+  auth:
+    title: User authentication
+    bdd: [specs/login.feature, "specs/signup.feature"]
+    tests:
+      - auth/**/*_test.go
+  cart:
+    tests: [web/cart.test.ts]
+`), 0644)
+
+	dry, err := AdoptDryRunWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits, MapFile: "features.map.yaml"})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(dry.Imports) != 2 || dry.Imports[0].Append || !dry.Imports[1].Append {
+		t.Errorf("expected import then append, got %+v", dry.Imports)
+	}
+	if len(dry.TestMaps) != 2 {
+		t.Errorf("expected 2 test mappings, got %+v", dry.TestMaps)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ptsd")); err == nil {
+		t.Fatal("dry run created .ptsd/")
+	}
+
+	if _, err := AdoptProjectWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits, MapFile: "features.map.yaml"}); err != nil {
+		t.Fatalf("adopt failed: %v", err)
+	}
+
+	features, _ := loadFeatures(dir)
+	if len(features) != 2 || features[0].ID != "auth" || features[0].Title != "User authentication" || features[1].ID != "cart" {
+		t.Errorf("unexpected registry: %+v", features)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".ptsd", "bdd", "auth.feature"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ff, err := parseFeatureContent(string(data))
+	if err != nil {
+		t.Fatalf("merged feature file does not parse: %v\n%s", err, data)
+	}
+	if ff.Tag != "auth" || len(ff.Scenarios) != 2 || !strings.HasPrefix(string(data), "@smoke\n@feature:auth\n") {
+		t.Errorf("unexpected merged feature file:\n%s", data)
+	}
+
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tests, _ := state.Features["auth"].Tests.([]string); len(tests) != 1 || tests[0] != ".ptsd/bdd/auth.feature::auth/login_test.go" {
+		t.Errorf("unexpected auth mappings: %v", tests)
+	}
+	if tests, _ := state.Features["cart"].Tests.([]string); len(tests) != 1 || tests[0] != "web/cart.test.ts" {
+		t.Errorf("unexpected cart mappings: %v", tests)
+	}
+
+	// Rerunning with the same map adds nothing.
+	result, err := AdoptProjectWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits, MapFile: "features.map.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.NewFeatures) != 0 || len(result.TestMaps) != 0 {
+		t.Errorf("expected a no-op rerun, got %+v", result)
+	}
+}
+
+// TestLoadAdoptMapRejectsInvalidIDs verifies map files are validated.
+func TestLoadAdoptMapRejectsInvalidIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "features.map.yaml")
+	os.WriteFile(path, []byte("features:\n  Bad_ID:\n    tests: [a_test.go]\n"), 0644)
+	if _, err := LoadAdoptMap(path); err == nil || !strings.Contains(err.Error(), "err:config") {
+		t.Errorf("expected err:config, got %v", err)
 	}
 }
 
@@ -175,16 +325,21 @@ func TestAdoptDryRunMakesNoChanges(t *testing.T) {
 	}
 }
 
-// TestAdoptDryRunRefusesIfAlreadyInitialized verifies dry-run also checks for existing .ptsd/.
-func TestAdoptDryRunRefusesIfAlreadyInitialized(t *testing.T) {
+// TestAdoptDryRunIncremental verifies dry-run on an adopted project plans an
+// incremental run without writing.
+func TestAdoptDryRunIncremental(t *testing.T) {
 	dir := setupProjectWithFeatures(t, "existing:planned")
+	os.WriteFile(filepath.Join(dir, "new.feature"), []byte("@feature:fresh\nFeature: Fresh\n"), 0644)
 
-	_, err := AdoptDryRun(dir)
-	if err == nil {
-		t.Fatal("expected error for already-initialized project")
+	result, err := AdoptDryRun(dir)
+	if err != nil {
+		t.Fatalf("AdoptDryRun failed: %v", err)
 	}
-	if !strings.Contains(err.Error(), "err:validation") {
-		t.Errorf("expected err:validation, got: %v", err)
+	if !result.Incremental || len(result.NewFeatures) != 1 || result.NewFeatures[0] != "fresh" {
+		t.Errorf("unexpected plan: %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.feature")); err != nil {
+		t.Error("dry run moved a .feature file")
 	}
 }
 
@@ -206,3 +361,133 @@ func TestAdoptEmptyProject(t *testing.T) {
 		t.Error("features.yaml missing 'features:' header")
 	}
 }
+
+// TestAdoptRecordsUnmappedTestsAcrossEcosystems verifies adopt recognizes
+// JS/TS, RSpec, pytest and JUnit test files and records unmapped ones.
+func TestAdoptRecordsUnmappedTestsAcrossEcosystems(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		"pkg/a_test.go", "web/cart.test.ts", "web/Cart.test.tsx", "web/api.spec.js",
+		"spec/user_spec.rb", "tests/test_login.py", "tests/login_test.py",
+		"app/src/test/java/com/acme/AppTest.java",
+		"web/cart.ts", "tests/conftest.py", "src/main/java/com/acme/App.java",
+	}
+	for _, f := range files {
+		os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0755)
+		os.WriteFile(filepath.Join(dir, f), []byte("x\n"), 0644)
+	}
+
+	result, err := AdoptProjectWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits})
+	if err != nil {
+		t.Fatalf("adopt failed: %v", err)
+	}
+	if len(result.TestFiles) != 8 || len(result.Unmapped) != 8 {
+		t.Fatalf("expected 8 test files, all unmapped, got %v / %v", result.TestFiles, result.Unmapped)
+	}
+	for _, f := range result.TestFiles {
+		if f == "web/cart.ts" || f == "tests/conftest.py" || strings.HasPrefix(f, "src/main/") {
+			t.Errorf("non-test file discovered: %s", f)
+		}
+	}
+
+	unmapped, err := UnmappedTests(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unmapped) != 8 {
+		t.Fatalf("expected 8 unmapped tests, got %v", unmapped)
+	}
+
+	// Mapping one removes it; a deleted file drops out of the report.
+	os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "cart.feature"), []byte("@feature:cart\nFeature: Cart\n"), 0644)
+	if err := MapTest(dir, ".ptsd/bdd/cart.feature", "web/cart.test.ts"); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filepath.Join(dir, "spec", "user_spec.rb"))
+	unmapped, _ = UnmappedTests(dir)
+	if len(unmapped) != 6 || slices.Contains(unmapped, "web/cart.test.ts") || slices.Contains(unmapped, "spec/user_spec.rb") {
+		t.Errorf("unexpected unmapped tests: %v", unmapped)
+	}
+	if state, _ := LoadState(dir); slices.Contains(state.Unmapped, "web/cart.test.ts") {
+		t.Error("test map should drop the file from state.yaml unmapped")
+	}
+
+	// A rerun records nothing twice.
+	result, err = AdoptProjectWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Unmapped) != 0 {
+		t.Errorf("expected no new unmapped tests on rerun, got %v", result.Unmapped)
+	}
+}
+
+// TestAdoptMapTests verifies --map-tests maps test files by name and
+// directory, infers stages from the artifacts, and matches scenarios.
+func TestAdoptMapTests(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"specs/auth.feature":              "@feature:auth\nFeature: Auth\n  Scenario: Login succeeds\n    Given a user\n",
+		"specs/billing.feature":           "@feature:billing\nFeature: Billing\n  Scenario: Charge card\n    Given a card\n",
+		"auth/auth_test.go":               "package auth\n\nfunc TestLoginSucceeds(t *testing.T) {}\n",
+		"internal/billing/charge_test.go": "package billing\n\nfunc TestSomething(t *testing.T) {}\n",
+		"misc/util_test.go":               "package misc\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan, err := AdoptDryRunWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits, MapTests: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMaps := []AdoptTestMap{
+		{Feature: "auth", File: "auth/auth_test.go", Convention: true},
+		{Feature: "billing", File: "internal/billing/charge_test.go", Convention: true},
+	}
+	if !slices.Equal(plan.TestMaps, wantMaps) {
+		t.Errorf("TestMaps = %v, want %v", plan.TestMaps, wantMaps)
+	}
+	if !slices.Equal(plan.Unmapped, []string{"misc/util_test.go"}) {
+		t.Errorf("Unmapped = %v", plan.Unmapped)
+	}
+	wantStages := []AdoptStage{{Feature: "auth", Stage: "tests"}, {Feature: "billing", Stage: "tests"}}
+	if !slices.Equal(plan.Stages, wantStages) {
+		t.Errorf("Stages = %v, want %v", plan.Stages, wantStages)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ptsd")); err == nil {
+		t.Fatal("dry run created .ptsd")
+	}
+
+	result, err := AdoptProjectWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits, MapTests: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ScenarioMaps) != 1 || result.ScenarioMaps[0].Func != "TestLoginSucceeds" {
+		t.Errorf("ScenarioMaps = %+v", result.ScenarioMaps)
+	}
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Features["auth"].Stage != "tests" || state.Features["billing"].Stage != "tests" {
+		t.Errorf("stages = auth:%q billing:%q", state.Features["auth"].Stage, state.Features["billing"].Stage)
+	}
+	tests, _ := state.Features["auth"].Tests.([]string)
+	if !slices.ContainsFunc(tests, func(m string) bool { return strings.HasSuffix(m, "::TestLoginSucceeds") }) {
+		t.Errorf("auth mappings = %v", tests)
+	}
+
+	// A second run leaves the stages it set alone.
+	again, err := AdoptDryRunWithOptions(dir, AdoptOptions{Limits: DefaultWalkLimits, MapTests: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Stages) != 0 || len(again.TestMaps) != 0 {
+		t.Errorf("second run: stages %v maps %v", again.Stages, again.TestMaps)
+	}
+}
@@ -1,8 +1,11 @@
 package core
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type AutoTrackResult struct {
@@ -13,16 +16,57 @@ type AutoTrackResult struct {
 	Previous string
 }
 
-var stageOrder = map[string]int{
-	"":      -1,
-	"prd":   0,
-	"seed":  1,
-	"bdd":   2,
-	"tests": 3,
-	"impl":  4,
+func AutoTrack(projectDir, filePath string) (*AutoTrackResult, error) {
+	results, err := autoTrackBatch(projectDir, []string{filePath})
+	if err != nil || len(results) == 0 {
+		return nil, err
+	}
+	return results[0], nil
 }
 
-func AutoTrack(projectDir, filePath string) (*AutoTrackResult, error) {
+// autoTrackBatch tracks filePaths against one load of review-status.yaml and
+// state.yaml, and saves each once if anything changed. It returns a result
+// per path that maps to a feature.
+func autoTrackBatch(projectDir string, filePaths []string) ([]*AutoTrackResult, error) {
+	rs, err := loadReviewStatus(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	// Syncing state.yaml is best-effort: a state that cannot be read is
+	// left alone.
+	st, stErr := LoadState(projectDir)
+
+	var results []*AutoTrackResult
+	updated := false
+	for _, filePath := range filePaths {
+		r := applyAutoTrack(projectDir, filePath, rs, st, stErr == nil)
+		if r == nil {
+			continue
+		}
+		updated = updated || r.Updated
+		results = append(results, r)
+	}
+	if !updated {
+		return results, nil
+	}
+
+	if err := saveReviewStatus(projectDir, rs); err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		if r.Updated && r.Stage != r.Previous {
+			recordEvent(projectDir, Event{Type: EventStage, Feature: r.Feature, Stage: r.Stage})
+		}
+	}
+	if stErr == nil {
+		_ = writeState(projectDir, st)
+	}
+	return results, nil
+}
+
+// applyAutoTrack classifies filePath and advances its feature in rs, and in
+// st when syncState is set, without writing either.
+func applyAutoTrack(projectDir, filePath string, rs map[string]ReviewStatusEntry, st *State, syncState bool) *AutoTrackResult {
 	rel := filePath
 	if filepath.IsAbs(filePath) {
 		r, err := filepath.Rel(projectDir, filePath)
@@ -33,12 +77,7 @@ func AutoTrack(projectDir, filePath string) (*AutoTrackResult, error) {
 
 	featureID, newStage, newTests := classifyForTracking(projectDir, rel)
 	if featureID == "" {
-		return nil, nil
-	}
-
-	rs, err := loadReviewStatus(projectDir)
-	if err != nil {
-		return nil, err
+		return nil
 	}
 
 	entry, ok := rs[featureID]
@@ -66,77 +105,76 @@ func AutoTrack(projectDir, filePath string) (*AutoTrackResult, error) {
 		result.Tests = "written"
 	}
 
-	// Only advance stage, never regress
-	if newStage != "" && stageOrder[newStage] > stageOrder[entry.Stage] {
+	// Only advance stage, never regress. Artifacts never skip a configured
+	// custom stage: those advance only through `ptsd review`. Nor do they
+	// move a feature past BDD ahead of its dependencies.
+	stages := FeatureStages(projectDir, featureID)
+	if blocked, _ := customStageGate(projectDir, featureID, newStage); blocked {
+		newStage = ""
+	}
+	if blocked, _ := dependencyGate(projectDir, featureID, newStage); blocked {
+		newStage = ""
+	}
+	if newStage != "" && stageRank(stages, newStage) > stageRank(stages, entry.Stage) {
 		entry.Stage = newStage
 		updated = true
 		result.Stage = newStage
 	}
 
-	if updated {
-		result.Updated = true
-		rs[featureID] = entry
-		if err := saveReviewStatus(projectDir, rs); err != nil {
-			return nil, err
-		}
+	if !updated {
+		return result
+	}
+	result.Updated = true
+	rs[featureID] = entry
 
-		// Sync state.yaml — errors are non-blocking
-		if st, err := LoadState(projectDir); err == nil {
-			sfs, ok := st.Features[featureID]
-			if !ok {
-				sfs = FeatureState{
-					Hashes: make(map[string]string),
-					Scores: make(map[string]ScoreEntry),
-				}
-			}
-			if sfs.Hashes == nil {
-				sfs.Hashes = make(map[string]string)
+	if syncState {
+		sfs, ok := st.Features[featureID]
+		if !ok {
+			sfs = FeatureState{
+				Hashes: make(map[string]string),
+				Scores: make(map[string]ScoreEntry),
 			}
+		}
+		if sfs.Hashes == nil {
+			sfs.Hashes = make(map[string]string)
+		}
 
-			if newStage != "" && stageOrder[newStage] > stageOrder[sfs.Stage] {
-				sfs.Stage = newStage
-			}
+		if newStage != "" && stageRank(stages, newStage) > stageRank(stages, sfs.Stage) {
+			sfs.Stage = newStage
+		}
 
-			var hashKey, hashPath string
-			switch newStage {
-			case "bdd":
-				hashKey = "bdd"
-				hashPath = filepath.Join(projectDir, rel)
-			case "seed":
-				hashKey = "seed"
-				hashPath = filepath.Join(projectDir, rel)
-			case "tests":
-				hashKey = "test"
-				hashPath = filepath.Join(projectDir, rel)
-			}
-			if hashKey != "" {
-				if h, err := computeFileHash(hashPath); err == nil {
-					sfs.Hashes[hashKey] = h
-				}
+		var hashKey, hashPath string
+		switch newStage {
+		case "bdd":
+			hashKey = "bdd"
+			hashPath = filepath.Join(projectDir, rel)
+		case "seed":
+			hashKey = "seed"
+			hashPath = filepath.Join(projectDir, rel)
+		case "tests":
+			hashKey = "test"
+			hashPath = filepath.Join(projectDir, rel)
+		}
+		if hashKey != "" {
+			if h, err := computeFileHash(hashPath); err == nil {
+				sfs.Hashes[hashKey] = h
 			}
-
-			st.Features[featureID] = sfs
-			_ = writeState(projectDir, st)
 		}
-	}
 
-	return result, nil
+		st.Features[featureID] = sfs
+	}
+	return result
 }
 
 func classifyForTracking(projectDir, rel string) (featureID, stage, tests string) {
 	// BDD file
-	if strings.HasPrefix(rel, ".ptsd/bdd/") && strings.HasSuffix(rel, ".feature") {
-		featureID = strings.TrimSuffix(filepath.Base(rel), ".feature")
+	if featureID = bddFileFeature(projectDir, rel); featureID != "" {
 		return featureID, "bdd", ""
 	}
 
 	// Seed file
-	if strings.HasPrefix(rel, ".ptsd/seeds/") {
-		parts := strings.Split(rel, "/")
-		if len(parts) >= 3 {
-			featureID = parts[2]
-			return featureID, "seed", ""
-		}
+	if featureID, _, ok := seedFileFeature(projectDir, rel); ok {
+		return featureID, "seed", ""
 	}
 
 	// Test file
@@ -158,3 +196,105 @@ func classifyForTracking(projectDir, rel string) (featureID, stage, tests string
 
 	return "", "", ""
 }
+
+func autoTrackPendingPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "autotrack-pending")
+}
+
+func autoTrackFlushedPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "autotrack-flushed")
+}
+
+// AutoTrackDebounced queues filePath and tracks all queued paths in one batch at
+// most once per window. Inside the window the path is only queued and nil is
+// returned; the queue is drained by the next call after the window or by
+// FlushAutoTrack (validate, context). A zero window tracks immediately.
+func AutoTrackDebounced(projectDir, filePath string, window time.Duration) ([]*AutoTrackResult, error) {
+	if window <= 0 {
+		r, err := AutoTrack(projectDir, filePath)
+		if err != nil || r == nil || !r.Updated {
+			return nil, err
+		}
+		return []*AutoTrackResult{r}, nil
+	}
+
+	if !containsString(readPendingPaths(autoTrackPendingPath(projectDir)), filePath) {
+		f, err := os.OpenFile(autoTrackPendingPath(projectDir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("err:io %w", err)
+		}
+		_, werr := f.WriteString(filePath + "\n")
+		f.Close()
+		if werr != nil {
+			return nil, fmt.Errorf("err:io %w", werr)
+		}
+	}
+
+	if info, err := os.Stat(autoTrackFlushedPath(projectDir)); err == nil && time.Since(info.ModTime()) < window {
+		return nil, nil
+	}
+
+	return FlushAutoTrack(projectDir)
+}
+
+// FlushAutoTrack tracks every queued path in one batch (see autoTrackBatch),
+// clears the queue once that is saved, and returns the results that changed
+// review-status. It holds the project lock so paths the hook queues meanwhile
+// are not dropped with the queue.
+func FlushAutoTrack(projectDir string) ([]*AutoTrackResult, error) {
+	pendingPath := autoTrackPendingPath(projectDir)
+	if len(readPendingPaths(pendingPath)) == 0 {
+		return nil, nil
+	}
+	unlock, err := LockProject(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	pending := readPendingPaths(pendingPath)
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	_ = writeFileAtomic(autoTrackFlushedPath(projectDir), nil, 0644)
+
+	tracked, err := autoTrackBatch(projectDir, pending)
+	if err != nil {
+		// The queue stays for the next flush.
+		return nil, err
+	}
+	if err := os.Remove(pendingPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+
+	var results []*AutoTrackResult
+	for _, r := range tracked {
+		if r.Updated {
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+func readPendingPaths(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !containsString(paths, line) {
+			paths = append(paths, line)
+		}
+	}
+	return paths
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
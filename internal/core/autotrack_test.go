@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestAutoTrack_BDDAdvancesStage(t *testing.T) {
@@ -295,3 +296,121 @@ func TestAutoTrack_ManagementFilesIgnored(t *testing.T) {
 		}
 	}
 }
+
+func TestAutoTrackDebounced_QueuesInsideWindow(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:in-progress")
+	ptsd := filepath.Join(dir, ".ptsd")
+	os.WriteFile(filepath.Join(ptsd, "bdd", "auth.feature"), []byte("Feature: auth\n"), 0644)
+	os.WriteFile(filepath.Join(ptsd, "bdd", "billing.feature"), []byte("Feature: billing\n"), 0644)
+
+	// First call opens the window and tracks immediately.
+	results, err := AutoTrackDebounced(dir, ".ptsd/bdd/auth.feature", time.Hour)
+	if err != nil {
+		t.Fatalf("AutoTrackDebounced: %v", err)
+	}
+	if len(results) != 1 || results[0].Feature != "auth" {
+		t.Fatalf("expected auth tracked immediately, got %+v", results)
+	}
+
+	// Second call inside the window is only queued.
+	results, err = AutoTrackDebounced(dir, ".ptsd/bdd/billing.feature", time.Hour)
+	if err != nil {
+		t.Fatalf("AutoTrackDebounced: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected billing queued, got %+v", results)
+	}
+	rs, _ := loadReviewStatus(dir)
+	if _, ok := rs["billing"]; ok {
+		t.Error("billing must not be tracked inside the debounce window")
+	}
+
+	// Flush drains the queue.
+	results, err = FlushAutoTrack(dir)
+	if err != nil {
+		t.Fatalf("FlushAutoTrack: %v", err)
+	}
+	if len(results) != 1 || results[0].Feature != "billing" || results[0].Stage != "bdd" {
+		t.Errorf("expected billing tracked on flush, got %+v", results)
+	}
+	if _, err := os.Stat(autoTrackPendingPath(dir)); !os.IsNotExist(err) {
+		t.Error("expected pending queue removed after flush")
+	}
+}
+
+func TestAutoTrackDebounced_ZeroWindowTracksImmediately(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "auth.feature"), []byte("Feature: auth\n"), 0644)
+
+	results, err := AutoTrackDebounced(dir, ".ptsd/bdd/auth.feature", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected immediate tracking, got %+v", results)
+	}
+	if _, err := os.Stat(autoTrackPendingPath(dir)); !os.IsNotExist(err) {
+		t.Error("zero window must not create a queue")
+	}
+}
+
+func TestFlushAutoTrackKeepsQueueWhileLocked(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "auth.feature"), []byte("Feature: auth\n"), 0644)
+	os.WriteFile(autoTrackPendingPath(dir), []byte(".ptsd/bdd/auth.feature\n"), 0644)
+	SetLockWait(0)
+	defer SetLockWait(DefaultLockWait)
+
+	// Another process (the hook) holding the project lock.
+	release, ok, err := tryLockFile(filepath.Join(dir, ".ptsd", lockFile))
+	if err != nil || !ok {
+		t.Fatalf("cannot take the lock: ok=%v err=%v", ok, err)
+	}
+	if _, err := FlushAutoTrack(dir); err == nil {
+		t.Error("expected the flush to need the project lock")
+	}
+	if paths := readPendingPaths(autoTrackPendingPath(dir)); len(paths) != 1 {
+		t.Errorf("queue changed without the lock: %v", paths)
+	}
+	release()
+
+	if results, err := FlushAutoTrack(dir); err != nil || len(results) != 1 {
+		t.Errorf("expected auth tracked after release, got %+v %v", results, err)
+	}
+}
+
+func TestFlushAutoTrackBatchesAndKeepsQueueOnError(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:in-progress")
+	ptsd := filepath.Join(dir, ".ptsd")
+	os.WriteFile(filepath.Join(ptsd, "bdd", "auth.feature"), []byte("Feature: auth\n"), 0644)
+	os.WriteFile(filepath.Join(ptsd, "bdd", "billing.feature"), []byte("Feature: billing\n"), 0644)
+	queue := ".ptsd/bdd/auth.feature\n.ptsd/bdd/billing.feature\n"
+
+	// review-status.yaml cannot be read: nothing is tracked and the queue stays.
+	rsPath := filepath.Join(ptsd, "review-status.yaml")
+	os.Remove(rsPath)
+	os.Mkdir(rsPath, 0755)
+	os.WriteFile(autoTrackPendingPath(dir), []byte(queue), 0644)
+	if _, err := FlushAutoTrack(dir); err == nil {
+		t.Fatal("expected the flush to fail")
+	}
+	if paths := readPendingPaths(autoTrackPendingPath(dir)); len(paths) != 2 {
+		t.Errorf("queue lost on a failed flush: %v", paths)
+	}
+
+	os.Remove(rsPath)
+	results, err := FlushAutoTrack(dir)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("expected both features tracked in one flush, got %+v %v", results, err)
+	}
+	rs, _ := loadReviewStatus(dir)
+	state, _ := LoadState(dir)
+	for _, id := range []string{"auth", "billing"} {
+		if rs[id].Stage != "bdd" || state.Features[id].Stage != "bdd" || state.Features[id].Hashes["bdd"] == "" {
+			t.Errorf("%s not tracked: review-status %+v, state %+v", id, rs[id], state.Features[id])
+		}
+	}
+	if _, err := os.Stat(autoTrackPendingPath(dir)); !os.IsNotExist(err) {
+		t.Error("expected pending queue removed after flush")
+	}
+}
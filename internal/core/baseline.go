@@ -0,0 +1,185 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validation baseline. Projects adopting ptsd late start with many
+// violations; .ptsd/validate-baseline.yaml records them so `validate
+// --baseline` fails only on new ones. Each `--update-baseline` rewrites the
+// accepted errors and appends a history entry, which is the burn-down record.
+
+// Baseline is the set of accepted validation errors plus their history.
+type Baseline struct {
+	Errors  []ValidationError
+	History []BaselineEntry
+}
+
+// BaselineEntry records the size of the baseline when it was updated.
+type BaselineEntry struct {
+	At    time.Time
+	Total int
+}
+
+// BaselineResult splits a validation run against a baseline.
+type BaselineResult struct {
+	New   []ValidationError // not in the baseline: these fail validation
+	Known []ValidationError // accepted by the baseline
+	Fixed []ValidationError // in the baseline but no longer reported
+}
+
+func baselinePath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "validate-baseline.yaml")
+}
+
+// LoadBaseline reads .ptsd/validate-baseline.yaml. A missing file is an
+// err:user pointing at --update-baseline.
+func LoadBaseline(projectDir string) (*Baseline, error) {
+	data, err := os.ReadFile(baselinePath(projectDir))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("err:user no validation baseline: run ptsd validate --update-baseline")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	return parseBaseline(string(data))
+}
+
+func parseBaseline(content string) (*Baseline, error) {
+	b := &Baseline{}
+	section := ""
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			section = strings.TrimSuffix(trimmed, ":")
+			continue
+		}
+		item := strings.HasPrefix(trimmed, "- ")
+		key, val, ok := strings.Cut(strings.TrimPrefix(trimmed, "- "), ": ")
+		if !ok {
+			return nil, fmt.Errorf("err:config validate-baseline.yaml line %d: expected key: value", i+1)
+		}
+		if strings.HasPrefix(val, "\"") {
+			unq, err := strconv.Unquote(val)
+			if err != nil {
+				return nil, fmt.Errorf("err:config validate-baseline.yaml line %d: bad string %s", i+1, val)
+			}
+			val = unq
+		}
+		switch section {
+		case "errors":
+			if item {
+				b.Errors = append(b.Errors, ValidationError{})
+			}
+			if len(b.Errors) == 0 {
+				continue
+			}
+			e := &b.Errors[len(b.Errors)-1]
+			switch key {
+			case "category":
+				e.Category = val
+			case "feature":
+				e.Feature = val
+			case "rule":
+				e.Rule = val
+			case "severity":
+				e.Severity = val
+			case "message":
+				e.Message = val
+			}
+		case "history":
+			if item {
+				b.History = append(b.History, BaselineEntry{})
+			}
+			if len(b.History) == 0 {
+				continue
+			}
+			h := &b.History[len(b.History)-1]
+			switch key {
+			case "at":
+				h.At, _ = time.Parse(time.RFC3339, val)
+			case "total":
+				h.Total, _ = strconv.Atoi(val)
+			}
+		}
+	}
+	return b, nil
+}
+
+// UpdateBaseline replaces the accepted errors with errs and appends a
+// history entry. An existing history is kept.
+func UpdateBaseline(projectDir string, errs []ValidationError) (*Baseline, error) {
+	b, err := LoadBaseline(projectDir)
+	if err != nil {
+		if !strings.HasPrefix(err.Error(), "err:user") {
+			return nil, err
+		}
+		b = &Baseline{}
+	}
+	b.Errors = errs
+	b.History = append(b.History, BaselineEntry{At: time.Now().UTC().Truncate(time.Second), Total: len(errs)})
+	if err := saveBaseline(projectDir, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func saveBaseline(projectDir string, b *Baseline) error {
+	var sb strings.Builder
+	sb.WriteString("# Accepted validation errors; ptsd validate --baseline fails only on others.\n")
+	sb.WriteString("# Regenerate with ptsd validate --update-baseline.\n")
+	sb.WriteString("errors:\n")
+	for _, e := range b.Errors {
+		sb.WriteString("  - category: " + e.Category + "\n")
+		sb.WriteString("    feature: " + strconv.Quote(e.Feature) + "\n")
+		if e.Rule != "" {
+			sb.WriteString("    rule: " + e.Rule + "\n")
+		}
+		if e.Severity != "" {
+			sb.WriteString("    severity: " + e.Severity + "\n")
+		}
+		sb.WriteString("    message: " + strconv.Quote(e.Message) + "\n")
+	}
+	sb.WriteString("history:\n")
+	for _, h := range b.History {
+		sb.WriteString("  - at: \"" + h.At.UTC().Format(time.RFC3339) + "\"\n")
+		sb.WriteString("    total: " + strconv.Itoa(h.Total) + "\n")
+	}
+	if err := writeFileAtomic(baselinePath(projectDir), []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// Apply classifies errs against the baseline. Each baseline entry accepts
+// one matching error, so a second identical error counts as new.
+func (b *Baseline) Apply(errs []ValidationError) BaselineResult {
+	accepted := make(map[ValidationError]int, len(b.Errors))
+	for _, e := range b.Errors {
+		accepted[e]++
+	}
+	var r BaselineResult
+	for _, e := range errs {
+		if accepted[e] > 0 {
+			accepted[e]--
+			r.Known = append(r.Known, e)
+		} else {
+			r.New = append(r.New, e)
+		}
+	}
+	for _, e := range b.Errors {
+		if accepted[e] > 0 {
+			accepted[e]--
+			r.Fixed = append(r.Fixed, e)
+		}
+	}
+	return r
+}
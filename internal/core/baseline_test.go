@@ -0,0 +1,42 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBaselineRoundTripAndApply(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+
+	if _, err := LoadBaseline(dir); err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Fatalf("expected err:user without a baseline, got %v", err)
+	}
+
+	accepted := []ValidationError{
+		{Feature: "auth", Category: "pipeline", Rule: "bdd-seed", Message: `has bdd but no "seed"`},
+		{Feature: "", Category: "pipeline", Rule: "regression", Severity: "warn", Message: "bdd changed: stale"},
+		{Feature: "auth", Category: "pipeline", Message: "dup"},
+	}
+	if _, err := UpdateBaseline(dir, accepted); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UpdateBaseline(dir, accepted[:2]); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := LoadBaseline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Errors) != 2 || b.Errors[0] != accepted[0] || b.Errors[1] != accepted[1] {
+		t.Fatalf("errors did not round-trip: %+v", b.Errors)
+	}
+	if len(b.History) != 2 || b.History[0].Total != 3 || b.History[1].Total != 2 || b.History[0].At.IsZero() {
+		t.Errorf("history = %+v", b.History)
+	}
+
+	r := b.Apply([]ValidationError{accepted[0], accepted[0], {Feature: "pay", Category: "pipeline", Message: "new"}})
+	if len(r.Known) != 1 || len(r.New) != 2 || len(r.Fixed) != 1 || r.Fixed[0] != accepted[1] {
+		t.Errorf("apply = %+v", r)
+	}
+}
@@ -1,10 +1,14 @@
 package core
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/veschin/ptsd/internal/gherkin"
 )
 
 type FeatureFileData struct {
@@ -14,25 +18,48 @@ type FeatureFileData struct {
 }
 
 type ScenarioData struct {
+	// ID is the explicit @id:<id> tag, or a hash of the title when untagged.
+	// Run AnnotateScenarioIDs to pin hash IDs so later renames keep them.
+	ID    string
 	Name  string
 	Title string
 	Steps []string
+	// Tags are the scenario's tags other than @id, e.g. "@ac:AC-2".
+	Tags []string
+	Line int
+	// Outline is the outline's ID for a Scenario Outline expansion.
+	Outline string
+}
+
+// scenarioIDTag prefixes the explicit scenario ID tag.
+const scenarioIDTag = "@id:"
+
+// scenarioHashID derives the default ID for an untagged scenario.
+func scenarioHashID(title string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(title)))
+	return "sc-" + hex.EncodeToString(sum[:4])
 }
 
+// AddBDD creates the feature's BDD file with one scenario per data/fixture
+// seed file, its Given step pre-filled from the seed records.
 func AddBDD(projectDir string, featureID string) error {
-	seedPath := filepath.Join(projectDir, ".ptsd", "seeds", featureID, "seed.yaml")
-	if _, err := os.Stat(seedPath); os.IsNotExist(err) {
+	seedPath := seedManifestPath(projectDir, featureID)
+	manifest, err := os.ReadFile(seedPath)
+	if os.IsNotExist(err) {
 		return fmt.Errorf("err:pipeline %s has no seed", featureID)
 	}
+	if err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
 
-	bddDir := filepath.Join(projectDir, ".ptsd", "bdd")
+	bddDir := bddDirPath(projectDir)
 	if err := os.MkdirAll(bddDir, 0755); err != nil {
 		return fmt.Errorf("err:io %w", err)
 	}
 
 	bddPath := filepath.Join(bddDir, featureID+".feature")
-	content := "@feature:" + featureID + "\nFeature: " + featureID + "\n"
-	if err := os.WriteFile(bddPath, []byte(content), 0644); err != nil {
+	content := scaffoldBDD(projectDir, featureID, string(manifest))
+	if err := writeFileAtomic(bddPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("err:io %w", err)
 	}
 
@@ -50,7 +77,7 @@ func CheckBDD(projectDir string) ([]string, error) {
 		featureSet[f.ID] = true
 	}
 
-	bddDir := filepath.Join(projectDir, ".ptsd", "bdd")
+	bddDir := bddDirPath(projectDir)
 	entries, _ := os.ReadDir(bddDir)
 	for _, e := range entries {
 		if !strings.HasSuffix(e.Name(), ".feature") {
@@ -70,6 +97,14 @@ func CheckBDD(projectDir string) ([]string, error) {
 				}
 			}
 		}
+		ff, _ := parseFeatureContent(string(data))
+		seenIDs := make(map[string]bool)
+		for _, sc := range ff.Scenarios {
+			if seenIDs[sc.ID] {
+				return nil, fmt.Errorf("err:validation duplicate scenario id %s in %s", sc.ID, e.Name())
+			}
+			seenIDs[sc.ID] = true
+		}
 	}
 
 	var missing []string
@@ -91,7 +126,7 @@ func CheckBDD(projectDir string) ([]string, error) {
 }
 
 func ShowBDD(projectDir string, featureID string) ([]string, error) {
-	bddPath := filepath.Join(projectDir, ".ptsd", "bdd", featureID+".feature")
+	bddPath := bddFilePath(projectDir, featureID)
 	data, err := os.ReadFile(bddPath)
 	if err != nil {
 		return nil, fmt.Errorf("err:validation feature %s not found", featureID)
@@ -108,7 +143,7 @@ func ShowBDD(projectDir string, featureID string) ([]string, error) {
 		for _, step := range s.Steps {
 			parts = append(parts, step)
 		}
-		line := s.Name + ": " + strings.Join(parts, " / ")
+		line := "[" + s.ID + "] " + s.Name + ": " + strings.Join(parts, " / ")
 		lines = append(lines, line)
 	}
 
@@ -116,7 +151,7 @@ func ShowBDD(projectDir string, featureID string) ([]string, error) {
 }
 
 func ParseFeatureFile(path string) (FeatureFileData, error) {
-	data, err := os.ReadFile(path)
+	data, err := readFileCached(path)
 	if err != nil {
 		return FeatureFileData{}, fmt.Errorf("err:validation file not found: %s", path)
 	}
@@ -124,47 +159,159 @@ func ParseFeatureFile(path string) (FeatureFileData, error) {
 	return parseFeatureContent(string(data))
 }
 
+// parseFeatureContent parses a feature file with the Gherkin parser. Scenario
+// outlines expand into one scenario per Examples row, with ID
+// <outline-id>-<n>. On a syntax error the scenarios parsed so far are
+// returned along with an err:validation error.
 func parseFeatureContent(content string) (FeatureFileData, error) {
+	return bddMemo.get(content, parseGherkinFeature)
+}
+
+func parseGherkinFeature(content string) (FeatureFileData, error) {
 	ff := FeatureFileData{}
-	lines := strings.Split(content, "\n")
+	doc, parseErr := gherkin.Parse(content)
+	if parseErr != nil {
+		parseErr = fmt.Errorf("err:validation %w", parseErr)
+	}
+	f := doc.Feature
+	if f == nil {
+		return ff, parseErr
+	}
 
-	var currentScenario *ScenarioData
+	ff.Title = f.Name
+	for _, tag := range f.Tags {
+		if strings.HasPrefix(tag.Name, "@feature:") {
+			ff.Tag = strings.TrimPrefix(tag.Name, "@feature:")
+		}
+	}
 
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
+	for _, sc := range f.AllScenarios() {
+		id := ""
+		var tags []string
+		for _, tag := range sc.Tags {
+			if strings.HasPrefix(tag.Name, scenarioIDTag) {
+				id = strings.TrimPrefix(tag.Name, scenarioIDTag)
+			} else {
+				tags = append(tags, tag.Name)
+			}
+		}
+		if id == "" {
+			id = scenarioHashID(sc.Name)
+		}
 
-		if strings.HasPrefix(trimmed, "@feature:") {
-			ff.Tag = strings.TrimPrefix(trimmed, "@feature:")
+		if !sc.IsOutline() {
+			ff.Scenarios = append(ff.Scenarios, ScenarioData{ID: id, Name: sc.Name, Title: sc.Name, Steps: stepLines(sc.Steps), Tags: tags, Line: sc.Line})
 			continue
 		}
+		for _, ex := range sc.Expand() {
+			exTags := append([]string{}, tags...)
+			for _, tag := range ex.Tags {
+				exTags = append(exTags, tag.Name)
+			}
+			ff.Scenarios = append(ff.Scenarios, ScenarioData{
+				ID: fmt.Sprintf("%s-%d", id, ex.Index), Name: ex.Name, Title: ex.Name,
+				Steps: stepLines(ex.Steps), Tags: exTags, Line: ex.Line, Outline: id,
+			})
+		}
+	}
+
+	return ff, parseErr
+}
+
+// stepLines renders steps as "<Keyword> <text>".
+func stepLines(steps []*gherkin.Step) []string {
+	lines := make([]string, len(steps))
+	for i, st := range steps {
+		lines[i] = st.Keyword + " " + st.Text
+	}
+	return lines
+}
+
+// scenarioLine reports whether a trimmed line starts a scenario or outline
+// and returns its name.
+func scenarioLine(trimmed string) (string, bool) {
+	for _, kw := range []string{"Scenario Outline:", "Scenario Template:", "Scenario:", "Example:"} {
+		if strings.HasPrefix(trimmed, kw) {
+			return strings.TrimSpace(strings.TrimPrefix(trimmed, kw)), true
+		}
+	}
+	return "", false
+}
 
-		if strings.HasPrefix(trimmed, "Feature:") {
-			ff.Title = strings.TrimSpace(strings.TrimPrefix(trimmed, "Feature:"))
+// AnnotateScenarioIDs tags every untagged scenario in .ptsd/bdd/*.feature with
+// @id:<hash>, so mappings survive later title changes. Returns the number of
+// scenarios annotated.
+func AnnotateScenarioIDs(projectDir string) (int, error) {
+	bddDir := bddDirPath(projectDir)
+	entries, err := os.ReadDir(bddDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("err:io %w", err)
+	}
+
+	total := 0
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".feature") {
 			continue
 		}
+		path := filepath.Join(bddDir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return total, fmt.Errorf("err:io %w", err)
+		}
 
-		if strings.HasPrefix(trimmed, "Scenario:") {
-			if currentScenario != nil {
-				ff.Scenarios = append(ff.Scenarios, *currentScenario)
+		lines := strings.Split(string(data), "\n")
+		var out []string
+		tagged := false
+		added := 0
+		for _, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "@") && !strings.HasPrefix(trimmed, "@feature:") {
+				if strings.Contains(trimmed, scenarioIDTag) {
+					tagged = true
+				}
+			} else if name, ok := scenarioLine(trimmed); ok {
+				if !tagged {
+					indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+					out = append(out, indent+scenarioIDTag+scenarioHashID(name))
+					added++
+				}
+				tagged = false
+			} else if trimmed != "" {
+				tagged = false
 			}
-			name := strings.TrimSpace(strings.TrimPrefix(trimmed, "Scenario:"))
-			currentScenario = &ScenarioData{Name: name, Title: name}
-			continue
+			out = append(out, line)
 		}
 
-		if currentScenario != nil {
-			if strings.HasPrefix(trimmed, "Given ") ||
-				strings.HasPrefix(trimmed, "When ") ||
-				strings.HasPrefix(trimmed, "Then ") ||
-				strings.HasPrefix(trimmed, "And ") {
-				currentScenario.Steps = append(currentScenario.Steps, trimmed)
+		if added > 0 {
+			if err := writeFileAtomic(path, []byte(strings.Join(out, "\n")), 0644); err != nil {
+				return total, fmt.Errorf("err:io %w", err)
 			}
+			total += added
 		}
 	}
+	return total, nil
+}
 
-	if currentScenario != nil {
-		ff.Scenarios = append(ff.Scenarios, *currentScenario)
+// findScenario resolves a scenario reference by ID, falling back to title.
+// An outline's own ID resolves to the outline, covering all its expansions.
+func findScenario(ff FeatureFileData, ref string) (ScenarioData, bool) {
+	for _, sc := range ff.Scenarios {
+		if sc.ID == ref {
+			return sc, true
+		}
 	}
-
-	return ff, nil
+	for _, sc := range ff.Scenarios {
+		if sc.Outline == ref {
+			return ScenarioData{ID: sc.Outline, Name: sc.Name, Title: sc.Title}, true
+		}
+	}
+	for _, sc := range ff.Scenarios {
+		if sc.Title == ref {
+			return sc, true
+		}
+	}
+	return ScenarioData{}, false
 }
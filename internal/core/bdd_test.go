@@ -199,3 +199,163 @@ func TestParseFeatureFileNotFound(t *testing.T) {
 		t.Errorf("expected err:validation, got: %v", err)
 	}
 }
+
+func TestScenarioIDsExplicitAndHashed(t *testing.T) {
+	ff, err := parseFeatureContent(`@feature:user-auth
+Feature: User Auth
+  @id:login-ok @smoke
+  Scenario: Login success
+    Given user exists
+
+  Scenario: Logout
+    Given logged in
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ff.Scenarios[0].ID != "login-ok" {
+		t.Errorf("expected explicit id login-ok, got %q", ff.Scenarios[0].ID)
+	}
+	if ff.Scenarios[1].ID != scenarioHashID("Logout") || !strings.HasPrefix(ff.Scenarios[1].ID, "sc-") {
+		t.Errorf("expected hashed id for untagged scenario, got %q", ff.Scenarios[1].ID)
+	}
+}
+
+func TestAnnotateScenarioIDsSurvivesRename(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	bddDir := filepath.Join(dir, ".ptsd", "bdd")
+	os.MkdirAll(bddDir, 0755)
+	bddPath := filepath.Join(bddDir, "user-auth.feature")
+	os.WriteFile(bddPath, []byte("@feature:user-auth\nFeature: User Auth\n  @id:kept\n  Scenario: Login\n    Given a\n\n  Scenario: Logout\n    Given b\n"), 0644)
+
+	n, err := AnnotateScenarioIDs(dir)
+	if err != nil {
+		t.Fatalf("AnnotateScenarioIDs: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 scenario annotated, got %d", n)
+	}
+	data, _ := os.ReadFile(bddPath)
+	want := "  @id:" + scenarioHashID("Logout") + "\n  Scenario: Logout"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("expected indented id tag above Logout, got:\n%s", data)
+	}
+
+	// Rename after annotation: the ID stays.
+	renamed := strings.Replace(string(data), "Scenario: Logout", "Scenario: Sign out", 1)
+	os.WriteFile(bddPath, []byte(renamed), 0644)
+	ff, _ := ParseFeatureFile(bddPath)
+	if ff.Scenarios[1].ID != scenarioHashID("Logout") {
+		t.Errorf("renamed scenario lost its id: %q", ff.Scenarios[1].ID)
+	}
+
+	if n, _ := AnnotateScenarioIDs(dir); n != 0 {
+		t.Errorf("second run must be a no-op, annotated %d", n)
+	}
+}
+
+func TestAddBDDInjectsSeedValues(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	if err := InitSeed(dir, "user-auth"); err != nil {
+		t.Fatal(err)
+	}
+	src := t.TempDir()
+	files := map[string]string{
+		"users.json":  `{"users": [{"email": "alice@example.com", "id": 1, "active": true}, {"email": "bob@example.com", "id": 2, "active": false}]}`,
+		"config.yaml": "# limits\nmax_attempts: 3\nlockout: \"15m\"\n",
+		"logins.csv":  "user,ip\nalice,10.0.0.1\n",
+		"schema.json": `{"type": "object"}`,
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(src, name), []byte(content), 0644)
+	}
+	for _, name := range []string{"users.json", "config.yaml", "logins.csv"} {
+		if err := AddSeedFile(dir, "user-auth", filepath.Join(src, name), "data", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := AddSeedFile(dir, "user-auth", filepath.Join(src, "schema.json"), "schema", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AddBDD(dir, "user-auth"); err != nil {
+		t.Fatalf("AddBDD failed: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "bdd", "user-auth.feature"))
+	content := string(data)
+
+	for _, want := range []string{
+		`Given the seed data "users.json":`,
+		"| active | email             | id |",
+		"| true   | alice@example.com | 1  |",
+		"| false  | bob@example.com   | 2  |",
+		"| max_attempts | lockout |",
+		"| 3            | 15m     |",
+		"| alice | 10.0.0.1 |",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("feature file missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "schema.json") {
+		t.Error("schema seeds should not get a scenario")
+	}
+
+	ff, err := parseFeatureContent(content)
+	if err != nil {
+		t.Fatalf("generated file does not parse: %v", err)
+	}
+	if len(ff.Scenarios) != 3 {
+		t.Errorf("expected 3 scenarios, got %d", len(ff.Scenarios))
+	}
+}
+
+const outlineBDD = `@feature:refunds
+Feature: Refunds
+  Background:
+    Given a paid order
+
+  @id:refund
+  Scenario Outline: Refund <amount>
+    When a refund of <amount> is requested
+    Then the status is <status>
+
+    Examples:
+      | amount | status   |
+      | 1      | refunded |
+      | 500    | pending  |
+
+  Scenario: Cancel refund
+    Given a pending refund
+    Then it is cancelled
+`
+
+func TestParseFeatureContentExpandsOutlines(t *testing.T) {
+	ff, err := parseFeatureContent(outlineBDD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ff.Scenarios) != 3 {
+		t.Fatalf("expected 2 expansions + 1 scenario, got %+v", ff.Scenarios)
+	}
+	first := ff.Scenarios[0]
+	if first.ID != "refund-1" || first.Outline != "refund" || first.Title != "Refund 1" {
+		t.Errorf("first expansion = %+v", first)
+	}
+	if got := ff.Scenarios[1].Steps[1]; got != "Then the status is pending" {
+		t.Errorf("expansion steps not substituted: %q", got)
+	}
+	if sc, ok := findScenario(ff, "refund"); !ok || sc.ID != "refund" {
+		t.Errorf("outline ID should resolve to the outline, got %+v %v", sc, ok)
+	}
+}
+
+func TestParseFeatureContentSyntaxError(t *testing.T) {
+	ff, err := parseFeatureContent("@feature:x\nFeature: X\n  Scenario: One\n    Given a\n      | a | b |\n      | 1 |\n")
+	if err == nil || !strings.HasPrefix(err.Error(), "err:validation line 6:") {
+		t.Fatalf("expected err:validation with line, got %v", err)
+	}
+	if ff.Tag != "x" || len(ff.Scenarios) != 1 {
+		t.Errorf("partial parse lost: %+v", ff)
+	}
+}
@@ -0,0 +1,193 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scenario-level BDD diffs. Recording a bdd review keeps a copy of the
+// feature file in .ptsd/reviewed/<id>.feature; `ptsd bdd diff <id>` compares
+// the current file against it, so a re-review covers the scenarios that
+// changed rather than the whole file. Without a stored copy (reviews from
+// before it existed) the file is taken from git as of the last bdd review.
+
+// reviewedDir holds feature files as they were at their last bdd review.
+func reviewedDir(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "reviewed")
+}
+
+// BDDScenarioChange is one scenario added, removed or modified since the
+// last bdd review.
+type BDDScenarioChange struct {
+	// Kind is "added", "removed" or "modified".
+	Kind  string
+	ID    string
+	Title string
+	// OldTitle is set when a modified scenario was renamed.
+	OldTitle string
+	// Fields lists what changed in a modified scenario: "title", "steps",
+	// "tags".
+	Fields []string
+}
+
+// BDDDiffResult compares a feature file against its last reviewed version.
+type BDDDiffResult struct {
+	Feature string
+	// Base says where the reviewed version came from: "stored" or
+	// "git:<short-hash>".
+	Base       string
+	ReviewedAt time.Time
+	Changes    []BDDScenarioChange
+	Unchanged  int
+}
+
+// storeReviewedBDD keeps a copy of the feature file as reviewed. A missing
+// feature file is not an error: there is nothing to compare against later.
+func storeReviewedBDD(projectDir, featureID string) error {
+	data, err := os.ReadFile(bddFilePath(projectDir, featureID))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	if err := os.MkdirAll(reviewedDir(projectDir), 0755); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(reviewedDir(projectDir), featureID+".feature"), data, 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// DiffBDD reports the scenarios of featureID added, removed or modified
+// since its last bdd review. Scenarios are matched by ID, then by title.
+func DiffBDD(projectDir, featureID string) (BDDDiffResult, error) {
+	result := BDDDiffResult{Feature: featureID}
+	state, err := LoadState(projectDir)
+	if err != nil {
+		return result, err
+	}
+	score, ok := state.Features[featureID].Scores["bdd"]
+	if !ok {
+		return result, fmt.Errorf("err:pipeline no bdd review recorded for %s", featureID)
+	}
+	result.ReviewedAt = score.Timestamp
+
+	currentPath := bddFilePath(projectDir, featureID)
+	data, err := os.ReadFile(currentPath)
+	if err != nil {
+		return result, fmt.Errorf("err:validation file not found: %s", currentPath)
+	}
+	current, _ := parseFeatureContent(string(data))
+
+	base, err := os.ReadFile(filepath.Join(reviewedDir(projectDir), featureID+".feature"))
+	switch {
+	case err == nil:
+		result.Base = "stored"
+	case os.IsNotExist(err):
+		var commit string
+		base, commit, err = reviewedBDDFromGit(projectDir, featureID, score.Timestamp)
+		if err != nil {
+			return result, err
+		}
+		result.Base = "git:" + commit
+	default:
+		return result, fmt.Errorf("err:io %w", err)
+	}
+	reviewed, _ := parseFeatureContent(string(base))
+
+	result.Changes, result.Unchanged = diffScenarios(reviewed.Scenarios, current.Scenarios)
+	return result, nil
+}
+
+// reviewedBDDFromGit returns the feature file as last committed before the
+// review, with the commit's short hash.
+func reviewedBDDFromGit(projectDir, featureID string, at time.Time) ([]byte, string, error) {
+	rel := bddFileRel(projectDir, featureID)
+	notFound := fmt.Errorf("err:pipeline no reviewed copy of %s: not stored and not in git before %s (re-review to store one)", rel, at.Local().Format("2006-01-02 15:04"))
+	if at.IsZero() {
+		return nil, "", notFound
+	}
+	log := exec.Command("git", "log", "-1", "--format=%h", "--before="+at.UTC().Format(time.RFC3339), "--", rel)
+	log.Dir = projectDir
+	out, err := log.Output()
+	commit := strings.TrimSpace(string(out))
+	if err != nil || commit == "" {
+		return nil, "", notFound
+	}
+	show := exec.Command("git", "show", commit+":"+rel)
+	show.Dir = projectDir
+	content, err := show.Output()
+	if err != nil {
+		return nil, "", notFound
+	}
+	return content, commit, nil
+}
+
+// diffScenarios matches scenarios by ID first, then by title among those
+// left, and returns changes in current-file order followed by removals.
+func diffScenarios(old, cur []ScenarioData) ([]BDDScenarioChange, int) {
+	matched := make(map[int]int) // cur index -> old index
+	used := make(map[int]bool)
+	for i, c := range cur {
+		for j, o := range old {
+			if !used[j] && o.ID == c.ID {
+				matched[i], used[j] = j, true
+				break
+			}
+		}
+	}
+	for i, c := range cur {
+		if _, ok := matched[i]; ok {
+			continue
+		}
+		for j, o := range old {
+			if !used[j] && o.Title == c.Title {
+				matched[i], used[j] = j, true
+				break
+			}
+		}
+	}
+
+	var changes []BDDScenarioChange
+	unchanged := 0
+	for i, c := range cur {
+		j, ok := matched[i]
+		if !ok {
+			changes = append(changes, BDDScenarioChange{Kind: "added", ID: c.ID, Title: c.Title})
+			continue
+		}
+		o := old[j]
+		var fields []string
+		if o.Title != c.Title {
+			fields = append(fields, "title")
+		}
+		if !slices.Equal(o.Steps, c.Steps) {
+			fields = append(fields, "steps")
+		}
+		if !slices.Equal(o.Tags, c.Tags) {
+			fields = append(fields, "tags")
+		}
+		if len(fields) == 0 {
+			unchanged++
+			continue
+		}
+		ch := BDDScenarioChange{Kind: "modified", ID: c.ID, Title: c.Title, Fields: fields}
+		if o.Title != c.Title {
+			ch.OldTitle = o.Title
+		}
+		changes = append(changes, ch)
+	}
+	for j, o := range old {
+		if !used[j] {
+			changes = append(changes, BDDScenarioChange{Kind: "removed", ID: o.ID, Title: o.Title})
+		}
+	}
+	return changes, unchanged
+}
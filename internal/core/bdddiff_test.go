@@ -0,0 +1,159 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const reviewedFeature = `@feature:auth
+Feature: Auth
+
+  @id:login
+  Scenario: Login
+    Given a user
+    When they log in
+    Then they see the dashboard
+
+  Scenario: Logout
+    Given a logged in user
+    When they log out
+    Then they see the login page
+
+  Scenario: Lockout
+    Given a user
+    When they fail to log in 5 times
+    Then the account is locked
+`
+
+const changedFeature = `@feature:auth
+Feature: Auth
+
+  @id:login
+  Scenario: Sign in
+    Given a user
+    When they log in
+    Then they see the dashboard
+
+  @ac:AC-2
+  Scenario: Logout
+    Given a logged in user
+    When they log out
+    Then they see the home page
+
+  Scenario: Password reset
+    Given a user
+    When they request a reset
+    Then they get an email
+`
+
+func writeAuthFeature(t *testing.T, dir, content string) {
+	t.Helper()
+	bddDir := filepath.Join(dir, ".ptsd", "bdd")
+	if err := os.MkdirAll(bddDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bddDir, "auth.feature"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func changeSummary(changes []BDDScenarioChange) string {
+	var parts []string
+	for _, c := range changes {
+		s := c.Kind + ":" + c.Title
+		if len(c.Fields) > 0 {
+			s += "[" + strings.Join(c.Fields, ",") + "]"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestDiffBDDAgainstStoredReview(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	writeAuthFeature(t, dir, reviewedFeature)
+	if err := RecordReview(dir, "auth", "bdd", 8); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ptsd", "reviewed", "auth.feature")); err != nil {
+		t.Fatalf("expected review to store the feature file: %v", err)
+	}
+
+	diff, err := DiffBDD(dir, "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Changes) != 0 || diff.Unchanged != 3 || diff.Base != "stored" {
+		t.Fatalf("expected no changes right after review, got %+v", diff)
+	}
+
+	writeAuthFeature(t, dir, changedFeature)
+	diff, err = DiffBDD(dir, "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "modified:Sign in[title] modified:Logout[steps,tags] added:Password reset removed:Lockout"
+	if got := changeSummary(diff.Changes); got != want {
+		t.Errorf("changes = %q, want %q", got, want)
+	}
+	if diff.Changes[0].OldTitle != "Login" {
+		t.Errorf("expected rename from Login, got %+v", diff.Changes[0])
+	}
+	if diff.Unchanged != 0 {
+		t.Errorf("unchanged = %d, want 0", diff.Unchanged)
+	}
+}
+
+func TestDiffBDDNoReview(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	writeAuthFeature(t, dir, reviewedFeature)
+	if _, err := DiffBDD(dir, "auth"); err == nil || !strings.HasPrefix(err.Error(), "err:pipeline") {
+		t.Fatalf("expected err:pipeline without a bdd review, got %v", err)
+	}
+}
+
+func TestDiffBDDFallsBackToGit(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	for k, v := range map[string]string{
+		"GIT_AUTHOR_NAME": "test", "GIT_AUTHOR_EMAIL": "test@test.com",
+		"GIT_COMMITTER_NAME": "test", "GIT_COMMITTER_EMAIL": "test@test.com",
+		"GIT_AUTHOR_DATE": past, "GIT_COMMITTER_DATE": past,
+	} {
+		t.Setenv(k, v)
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	writeAuthFeature(t, dir, reviewedFeature)
+	git("init")
+	git("add", ".ptsd/bdd/auth.feature")
+	git("commit", "-m", "bdd")
+
+	// A review recorded before reviewed copies were stored.
+	if err := RecordReview(dir, "auth", "bdd", 8); err != nil {
+		t.Fatal(err)
+	}
+	os.RemoveAll(filepath.Join(dir, ".ptsd", "reviewed"))
+	writeAuthFeature(t, dir, changedFeature)
+
+	diff, err := DiffBDD(dir, "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(diff.Base, "git:") {
+		t.Errorf("expected a git base, got %q", diff.Base)
+	}
+	if len(diff.Changes) != 4 {
+		t.Errorf("expected 4 changes, got %+v", diff.Changes)
+	}
+}
@@ -0,0 +1,262 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// BDD scaffolding from seed data. `ptsd bdd add` turns each data/fixture seed
+// file into a scenario whose Given step carries a table of real field names
+// and values, so scenarios reference the seed data from the start.
+
+// seedScaffoldRows caps how many seed records are copied into a Given table.
+const seedScaffoldRows = 5
+
+// seedRecord is one seed record: field names in column order and their values.
+type seedRecord struct {
+	fields []string
+	values map[string]string
+}
+
+func (r *seedRecord) set(field, value string) {
+	if r.values == nil {
+		r.values = make(map[string]string)
+	}
+	if _, ok := r.values[field]; !ok {
+		r.fields = append(r.fields, field)
+	}
+	r.values[field] = value
+}
+
+// seedManifestEntries returns the path and type of each file in seed.yaml.
+func seedManifestEntries(content string) [][2]string {
+	var entries [][2]string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "- path: "):
+			entries = append(entries, [2]string{strings.TrimSpace(strings.TrimPrefix(trimmed, "- path: ")), ""})
+		case strings.HasPrefix(trimmed, "type: ") && len(entries) > 0:
+			entries[len(entries)-1][1] = strings.TrimSpace(strings.TrimPrefix(trimmed, "type: "))
+		}
+	}
+	return entries
+}
+
+// scaffoldBDD renders the initial feature file for featureID from its seeds.
+func scaffoldBDD(projectDir, featureID string, manifest string) string {
+	var b strings.Builder
+	b.WriteString("@feature:" + featureID + "\nFeature: " + featureID + "\n")
+
+	first := true
+	for _, entry := range seedManifestEntries(manifest) {
+		path, typ := entry[0], entry[1]
+		if typ == "schema" {
+			continue
+		}
+		records := readSeedRecords(filepath.Join(seedDirPath(projectDir, featureID), path))
+		if first {
+			b.WriteString("\n  # Generated from seed data: rename the scenarios and replace the\n")
+			b.WriteString("  # When/Then placeholders. Keep Given steps in sync with the seeds.\n")
+			first = false
+		}
+		b.WriteString("\n  Scenario: " + featureID + " with " + path + "\n")
+		b.WriteString("    Given the seed data \"" + path + "\"")
+		if table := seedTable(records); table != "" {
+			b.WriteString(":\n" + table)
+		} else {
+			b.WriteString("\n")
+		}
+		b.WriteString("    When <action>\n    Then <expected outcome>\n")
+	}
+	return b.String()
+}
+
+// seedTable renders up to seedScaffoldRows records as an aligned Gherkin
+// data table, one column per field in order of first appearance.
+func seedTable(records []seedRecord) string {
+	if len(records) > seedScaffoldRows {
+		records = records[:seedScaffoldRows]
+	}
+	var columns []string
+	seen := make(map[string]bool)
+	for _, r := range records {
+		for _, f := range r.fields {
+			if !seen[f] {
+				seen[f] = true
+				columns = append(columns, f)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return ""
+	}
+
+	rows := [][]string{columns}
+	for _, r := range records {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = r.values[c]
+		}
+		rows = append(rows, row)
+	}
+	widths := make([]int, len(columns))
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = strings.ReplaceAll(cell, "|", "\\|")
+			if n := len([]rune(row[i])); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString("      |")
+		for i, cell := range row {
+			b.WriteString(" " + cell + strings.Repeat(" ", widths[i]-len([]rune(cell))) + " |")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// readSeedRecords parses a JSON, YAML or CSV seed file into records. Files
+// it cannot read or parse yield no records.
+func readSeedRecords(path string) []seedRecord {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return jsonSeedRecords(data)
+	case ".yaml", ".yml":
+		return yamlSeedRecords(string(data))
+	case ".csv":
+		return csvSeedRecords(data)
+	}
+	return nil
+}
+
+func jsonSeedRecords(data []byte) []seedRecord {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil
+	}
+	// {"users": [...]} is treated like the bare list.
+	if obj, ok := v.(map[string]any); ok && len(obj) == 1 {
+		for _, inner := range obj {
+			if list, ok := inner.([]any); ok {
+				v = list
+			}
+		}
+	}
+
+	var objects []map[string]any
+	switch t := v.(type) {
+	case map[string]any:
+		objects = append(objects, t)
+	case []any:
+		for _, item := range t {
+			if obj, ok := item.(map[string]any); ok {
+				objects = append(objects, obj)
+			}
+		}
+	}
+
+	var records []seedRecord
+	for _, obj := range objects {
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var r seedRecord
+		for _, k := range keys {
+			r.set(k, jsonSeedValue(obj[k]))
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func jsonSeedValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return t
+	case json.Number, bool:
+		return fmt.Sprint(t)
+	}
+	out, _ := json.Marshal(v)
+	return string(out)
+}
+
+// yamlSeedRecords reads the YAML shapes seeds use: a flat mapping (one
+// record) or a list of flat mappings, optionally under a top-level key.
+// Nested structures are skipped.
+func yamlSeedRecords(content string) []seedRecord {
+	var records []seedRecord
+	var top seedRecord
+	listIndent := -1
+	inList := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if strings.HasPrefix(trimmed, "- ") {
+			if !inList || indent == listIndent {
+				inList, listIndent = true, indent
+				records = append(records, seedRecord{})
+			}
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "- "))
+		} else if inList && indent <= listIndent {
+			break
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		key, value = strings.TrimSpace(key), stripQuotes(strings.TrimSpace(value))
+		if inList {
+			records[len(records)-1].set(key, value)
+		} else if indent == 0 {
+			top.set(key, value)
+		}
+	}
+	if len(records) == 0 && len(top.fields) > 0 {
+		records = append(records, top)
+	}
+	return records
+}
+
+func csvSeedRecords(data []byte) []seedRecord {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil || len(rows) < 2 {
+		return nil
+	}
+	var records []seedRecord
+	for _, row := range rows[1:] {
+		var r seedRecord
+		for i, field := range rows[0] {
+			if i < len(row) {
+				r.set(strings.TrimSpace(field), row[i])
+			}
+		}
+		records = append(records, r)
+	}
+	return records
+}
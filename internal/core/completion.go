@@ -0,0 +1,54 @@
+package core
+
+import "fmt"
+
+// Completion kinds: the project values `ptsd __complete` can offer for an
+// argument.
+const (
+	CompleteFeature    = "feature"
+	CompleteTask       = "task"
+	CompleteStage      = "stage"
+	CompleteStatus     = "status"
+	CompleteTaskStatus = "task-status"
+	CompletePriority   = "priority"
+	CompletePipeline   = "pipeline"
+)
+
+// CompletionValues lists the current values of one kind, read from the
+// project's files each call so completion follows features and tasks as
+// they change. Feature IDs and task IDs come in file order.
+func CompletionValues(projectDir, kind string) ([]string, error) {
+	switch kind {
+	case CompleteFeature:
+		features, err := loadFeatures(projectDir)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, 0, len(features))
+		for _, f := range features {
+			ids = append(ids, f.ID)
+		}
+		return ids, nil
+	case CompleteTask:
+		tasks, err := ListTasks(projectDir, "", "")
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]string, 0, len(tasks))
+		for _, t := range tasks {
+			ids = append(ids, t.ID)
+		}
+		return ids, nil
+	case CompleteStage:
+		return PipelineStages(projectDir), nil
+	case CompleteStatus:
+		return sortedKeys(validStatuses), nil
+	case CompleteTaskStatus:
+		return sortedKeys(validTaskStatuses), nil
+	case CompletePriority:
+		return sortedKeys(validTaskPriorities), nil
+	case CompletePipeline:
+		return []string{"full", PipelineLite}, nil
+	}
+	return nil, fmt.Errorf("err:user unknown completion kind: %s", kind)
+}
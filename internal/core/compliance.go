@@ -0,0 +1,256 @@
+package core
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Agent compliance audit. An agent session is a run of activity — agent hook
+// calls in hooks.log, events, commits — with no idle gap longer than gap,
+// containing at least one tool-use hook or context event. Each session is
+// checked against the protocol in CLAUDE.md:
+//
+//	context  `ptsd context` ran (SessionStart hook) before the first tool use
+//	validate every commit passed `ptsd validate` first: its tree is in
+//	         .git/ptsd-validated, or a passing validate ran since the
+//	         previous commit
+//	tasks    a session that committed or advanced a stage also updated a task
+//	         (only when tasks.yaml has tasks)
+//
+// Score is the percentage of applicable checks passed.
+
+// ComplianceCheck is the outcome of one protocol check in a session.
+type ComplianceCheck struct {
+	Name       string // context | validate | tasks
+	Applicable bool
+	Passed     bool
+	Detail     string
+}
+
+// ComplianceSession is one agent session and how it followed the protocol.
+type ComplianceSession struct {
+	Start    time.Time
+	End      time.Time
+	ToolUses int
+	Commits  int
+	Checks   []ComplianceCheck
+	Score    int // 0-100
+}
+
+// ComplianceReport is the result of AgentCompliance.
+type ComplianceReport struct {
+	Since    time.Time
+	Until    time.Time
+	Sessions []ComplianceSession
+	Score    int // mean session score; 0 without sessions
+}
+
+type complianceCommit struct {
+	At        time.Time
+	Hash      string
+	Validated bool // tree recorded by RecordValidatedTree
+}
+
+// complianceActivity is one timestamped item from any source.
+type complianceActivity struct {
+	At     time.Time
+	Kind   string // tool | context | validate | task | stage | commit | event
+	Pass   bool   // validate: the run passed
+	Commit complianceCommit
+}
+
+// AgentCompliance reports protocol compliance for agent sessions in
+// [since, until), splitting sessions at idle gaps longer than gap.
+func AgentCompliance(projectDir string, since, until time.Time, gap time.Duration) (ComplianceReport, error) {
+	events, err := LoadEvents(projectDir)
+	if err != nil {
+		return ComplianceReport{}, err
+	}
+	hooks, err := LoadHookLog(projectDir)
+	if err != nil {
+		return ComplianceReport{}, err
+	}
+	tasks, err := loadTasks(projectDir)
+	if err != nil {
+		return ComplianceReport{}, err
+	}
+
+	var acts []complianceActivity
+	for _, e := range EventsSince(events, since, until) {
+		a := complianceActivity{At: e.At, Kind: "event"}
+		switch e.Type {
+		case EventContext:
+			a.Kind = "context"
+		case EventValidate:
+			if e.Feature != "" {
+				continue // per-feature detail of a run already counted
+			}
+			a.Kind = "validate"
+			a.Pass = validatePassed(e.Detail)
+		case EventTask:
+			a.Kind = "task"
+		case EventStage:
+			a.Kind = "stage"
+		}
+		acts = append(acts, a)
+	}
+	for _, h := range hooks {
+		if (h.Hook == "pre-tool-use" || h.Hook == "post-tool-use") && !h.At.Before(since) && h.At.Before(until) {
+			acts = append(acts, complianceActivity{At: h.At, Kind: "tool"})
+		}
+	}
+	for _, c := range complianceCommits(projectDir, since, until) {
+		acts = append(acts, complianceActivity{At: c.At, Kind: "commit", Commit: c})
+	}
+	// Commit times have second precision: a commit sorts after everything
+	// else in its second, such as the validate run that preceded it.
+	slices.SortStableFunc(acts, func(a, b complianceActivity) int {
+		if c := a.At.Truncate(time.Second).Compare(b.At.Truncate(time.Second)); c != 0 {
+			return c
+		}
+		if (a.Kind == "commit") != (b.Kind == "commit") {
+			if a.Kind == "commit" {
+				return 1
+			}
+			return -1
+		}
+		return a.At.Compare(b.At)
+	})
+
+	r := ComplianceReport{Since: since, Until: until}
+	total := 0
+	for _, group := range splitSessions(acts, gap) {
+		s, ok := scoreSession(group, len(tasks) > 0)
+		if !ok {
+			continue
+		}
+		r.Sessions = append(r.Sessions, s)
+		total += s.Score
+	}
+	if len(r.Sessions) > 0 {
+		r.Score = total / len(r.Sessions)
+	}
+	return r, nil
+}
+
+// validatePassed reads a validate summary event: "pass", or a failure with
+// warnings only.
+func validatePassed(detail string) bool {
+	return detail == "pass" || strings.HasPrefix(detail, "fail errors=0 ")
+}
+
+// complianceCommits lists commits in [since, until) with whether their tree
+// passed validation. Outside a git repository there are none.
+func complianceCommits(projectDir string, since, until time.Time) []complianceCommit {
+	out, err := gitOutput(projectDir, "log", "--format=%H %T %ct", "--since="+since.Format(time.RFC3339))
+	if err != nil || out == "" {
+		return nil
+	}
+	validated := make(map[string]bool)
+	if path, err := validatedLogPath(projectDir); err == nil {
+		for _, t := range loadValidatedTrees(path) {
+			validated[t] = true
+		}
+	}
+	var commits []complianceCommit
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		sec, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		at := time.Unix(sec, 0)
+		if at.Before(since) || !at.Before(until) {
+			continue
+		}
+		commits = append(commits, complianceCommit{At: at, Hash: fields[0], Validated: validated[fields[1]]})
+	}
+	return commits
+}
+
+// splitSessions groups time-ordered activity at gaps longer than gap.
+func splitSessions(acts []complianceActivity, gap time.Duration) [][]complianceActivity {
+	var groups [][]complianceActivity
+	for i, a := range acts {
+		if i == 0 || a.At.Sub(acts[i-1].At) > gap {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], a)
+	}
+	return groups
+}
+
+// scoreSession checks one group of activity; ok is false when the group
+// shows no agent at work (no tool use and no context call).
+func scoreSession(acts []complianceActivity, haveTasks bool) (ComplianceSession, bool) {
+	s := ComplianceSession{Start: acts[0].At, End: acts[len(acts)-1].At}
+	var firstTool, firstContext time.Time
+	var taskUpdates, stageChanges, unvalidated int
+	validatedSince := false
+	for _, a := range acts {
+		switch a.Kind {
+		case "tool":
+			s.ToolUses++
+			if firstTool.IsZero() {
+				firstTool = a.At
+			}
+		case "context":
+			if firstContext.IsZero() {
+				firstContext = a.At
+			}
+		case "validate":
+			validatedSince = validatedSince || a.Pass
+		case "task":
+			taskUpdates++
+		case "stage":
+			stageChanges++
+		case "commit":
+			s.Commits++
+			if !a.Commit.Validated && !validatedSince {
+				unvalidated++
+			}
+			validatedSince = false
+		}
+	}
+	if s.ToolUses == 0 && firstContext.IsZero() {
+		return s, false
+	}
+
+	ctx := ComplianceCheck{Name: "context", Applicable: true}
+	switch {
+	case firstContext.IsZero():
+		ctx.Detail = "ptsd context never ran"
+	case !firstTool.IsZero() && firstTool.Before(firstContext.Truncate(time.Second)): // hooks.log has second precision
+		ctx.Detail = "ptsd context ran after the first tool use"
+	default:
+		ctx.Passed = true
+	}
+
+	val := ComplianceCheck{Name: "validate", Applicable: s.Commits > 0, Passed: unvalidated == 0}
+	if s.Commits > 0 {
+		val.Detail = strconv.Itoa(s.Commits-unvalidated) + "/" + strconv.Itoa(s.Commits) + " commits validated"
+	}
+
+	tsk := ComplianceCheck{Name: "tasks", Applicable: haveTasks && (s.Commits > 0 || stageChanges > 0), Passed: taskUpdates > 0}
+	if tsk.Applicable {
+		tsk.Detail = strconv.Itoa(taskUpdates) + " task updates"
+	}
+
+	s.Checks = []ComplianceCheck{ctx, val, tsk}
+	applicable, passed := 0, 0
+	for _, c := range s.Checks {
+		if c.Applicable {
+			applicable++
+			if c.Passed {
+				passed++
+			}
+		}
+	}
+	s.Score = passed * 100 / applicable
+	return s, true
+}
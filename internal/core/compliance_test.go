@@ -0,0 +1,137 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAgentComplianceSessions(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	setupTasks(t, dir, Task{ID: "T-1", Feature: "auth", Title: "login", Status: "TODO", Priority: "A"})
+	base := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return base.Add(d) }
+
+	// Session 1 follows the protocol.
+	AppendEvent(dir, Event{At: at(0), Type: EventContext})
+	AppendHookLog(dir, HookLogEntry{At: at(time.Minute), Hook: "pre-tool-use", File: "src/a.go", Verdict: "allow"})
+	AppendEvent(dir, Event{At: at(2 * time.Minute), Type: EventStage, Feature: "auth", Stage: "impl"})
+	AppendEvent(dir, Event{At: at(3 * time.Minute), Type: EventTask, Feature: "auth", Task: "T-1", Detail: "WIP"})
+
+	// Session 2, hours later: tools before context, stage change without a task update.
+	AppendHookLog(dir, HookLogEntry{At: at(3 * time.Hour), Hook: "post-tool-use", File: "src/a.go", Verdict: "skip"})
+	AppendEvent(dir, Event{At: at(3*time.Hour + 5*time.Minute), Type: EventContext})
+	AppendEvent(dir, Event{At: at(3*time.Hour + 6*time.Minute), Type: EventStage, Feature: "auth", Stage: "impl"})
+
+	// Human-only activity is not a session.
+	AppendEvent(dir, Event{At: at(6 * time.Hour), Type: EventReview, Feature: "auth", Stage: "impl", Score: 8})
+
+	r, err := AgentCompliance(dir, base.Add(-time.Hour), base.Add(24*time.Hour), 30*time.Minute)
+	if err != nil {
+		t.Fatalf("AgentCompliance failed: %v", err)
+	}
+	if len(r.Sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %+v", r.Sessions)
+	}
+	if s := r.Sessions[0]; s.Score != 100 || s.ToolUses != 1 {
+		t.Errorf("session 1: expected score 100 with 1 tool use, got %+v", s)
+	}
+	s := r.Sessions[1]
+	if s.Score != 0 {
+		t.Errorf("session 2: expected score 0, got %+v", s)
+	}
+	if c := s.Checks[0]; c.Name != "context" || c.Passed || !strings.Contains(c.Detail, "after the first tool use") {
+		t.Errorf("session 2: expected late context failure, got %+v", c)
+	}
+	if c := s.Checks[1]; c.Applicable {
+		t.Errorf("session 2: validate should not apply without commits, got %+v", c)
+	}
+	if r.Score != 50 {
+		t.Errorf("expected overall score 50, got %d", r.Score)
+	}
+}
+
+func TestAgentComplianceValidateBeforeCommit(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	for k, v := range map[string]string{
+		"GIT_AUTHOR_NAME": "test", "GIT_AUTHOR_EMAIL": "test@test.com",
+		"GIT_COMMITTER_NAME": "test", "GIT_COMMITTER_EMAIL": "test@test.com",
+	} {
+		t.Setenv(k, v)
+	}
+	base := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	git := func(date time.Time, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE="+date.Format(time.RFC3339), "GIT_AUTHOR_DATE="+date.Format(time.RFC3339))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git(base, "init")
+
+	AppendEvent(dir, Event{At: base, Type: EventContext})
+	AppendHookLog(dir, HookLogEntry{At: base.Add(time.Minute), Hook: "pre-tool-use", File: "a.txt", Verdict: "allow"})
+
+	// Validated: tree recorded, as the pre-commit hook's validate does.
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	git(base, "add", "a.txt")
+	tree, _ := gitOutput(dir, "write-tree")
+	if err := RecordValidatedTree(dir, tree); err != nil {
+		t.Fatal(err)
+	}
+	git(base.Add(2*time.Minute), "commit", "--no-verify", "-m", "validated")
+
+	// Bypassed: no validation at all.
+	os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644)
+	git(base, "add", "b.txt")
+	git(base.Add(4*time.Minute), "commit", "--no-verify", "-m", "sneaky")
+
+	r, err := AgentCompliance(dir, base.Add(-time.Hour), base.Add(time.Hour), 30*time.Minute)
+	if err != nil {
+		t.Fatalf("AgentCompliance failed: %v", err)
+	}
+	if len(r.Sessions) != 1 {
+		t.Fatalf("expected 1 session, got %+v", r.Sessions)
+	}
+	s := r.Sessions[0]
+	if s.Commits != 2 {
+		t.Fatalf("expected 2 commits, got %d", s.Commits)
+	}
+	if c := s.Checks[1]; c.Passed || c.Detail != "1/2 commits validated" {
+		t.Errorf("expected validate failure for the bypassed commit, got %+v", c)
+	}
+	if c := s.Checks[2]; c.Applicable {
+		t.Errorf("tasks check should not apply without tasks, got %+v", c)
+	}
+	if s.Score != 50 {
+		t.Errorf("expected score 50, got %d", s.Score)
+	}
+}
+
+func TestLoadHookLogParsesEntries(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	at := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	AppendHookLog(dir, HookLogEntry{At: at, Hook: "pre-tool-use", File: "src/a.go", Verdict: "block", Duration: 3 * time.Millisecond, Reason: "no tests yet"})
+	os.WriteFile(hookLogPath(dir)+".1", []byte(at.Add(-time.Hour).Format(time.RFC3339)+" hook=validate-commit verdict=ok duration=0ms\ngarbage\n"), 0644)
+
+	entries, err := LoadHookLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].Hook != "validate-commit" {
+		t.Errorf("expected rotated log first, got %+v", entries[0])
+	}
+	e := entries[1]
+	if !e.At.Equal(at) || e.Hook != "pre-tool-use" || e.File != "src/a.go" || e.Verdict != "block" ||
+		e.Duration != 3*time.Millisecond || e.Reason != "no tests yet" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+}
@@ -2,27 +2,211 @@ package core
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	Project ProjectConfig
-	Testing TestingConfig
-	Review  ReviewConfig
-	Hooks   HooksConfig
+	Project   ProjectConfig
+	Testing   TestingConfig
+	Review    ReviewConfig
+	Hooks     HooksConfig
+	Remote    RemoteConfig
+	Pipeline  PipelineConfig
+	Discovery DiscoveryConfig
+	Audit     AuditConfig
+	Tasks     TasksConfig
+	Gates     GatesConfig
+	Paths     PathsConfig
+	Network   NetworkConfig
+	PRD       PRDConfig
+	// Extends is the policy file this config is layered over; see
+	// resolveExtends.
+	Extends string
+	// SeedRequests are named HTTP requests `ptsd seed snapshot --request`
+	// captures as golden seed data.
+	SeedRequests map[string]SeedRequest
+	// Features holds per-feature overrides from features_config.<id>; see
+	// ForFeature.
+	Features map[string]FeatureOverride
+	// Milestones are named target dates for groups of features
+	// (milestones.<name>.target, .features); see EstimateCompletion.
+	Milestones map[string]Milestone
+}
+
+// FeatureOverride replaces testing.runner, testing.patterns.files and
+// review.min_score for one feature (features_config.<id>.runner, .patterns,
+// .min_score), e.g. a frontend feature tested with vitest in a Go monorepo.
+// Zero values keep the project setting.
+type FeatureOverride struct {
+	Runner   string
+	Patterns []string
+	MinScore int
+}
+
+// ForFeature returns the config with the feature's overrides applied. The
+// receiver is not modified.
+func (c *Config) ForFeature(featureID string) *Config {
+	o, ok := c.Features[featureID]
+	if !ok {
+		return c
+	}
+	out := *c
+	if o.Runner != "" {
+		out.Testing.Runner = o.Runner
+	}
+	if len(o.Patterns) > 0 {
+		out.Testing.Patterns.Files = o.Patterns
+	}
+	if o.MinScore > 0 {
+		out.Review.MinScore = o.MinScore
+	}
+	return &out
+}
+
+// TestPatterns returns the project's test file patterns plus every
+// per-feature override, without duplicates.
+func (c *Config) TestPatterns() []string {
+	out := append([]string{}, c.Testing.Patterns.Files...)
+	for _, id := range slices.Sorted(maps.Keys(c.Features)) {
+		for _, p := range c.Features[id].Patterns {
+			if !containsString(out, p) {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// checkFeatureOverrides reports features_config entries for features that
+// are not registered.
+func checkFeatureOverrides(projectDir string, features []Feature) []ValidationError {
+	cfg, err := LoadConfig(projectDir)
+	if err != nil {
+		return nil
+	}
+	known := make(map[string]bool, len(features))
+	for _, f := range features {
+		known[f.ID] = true
+	}
+	var errs []ValidationError
+	for _, id := range slices.Sorted(maps.Keys(cfg.Features)) {
+		if !known[id] {
+			errs = append(errs, ValidationError{Feature: id, Category: "pipeline", Rule: "features-config",
+				Message: "features_config." + id + " overrides an unknown feature"})
+		}
+	}
+	return errs
+}
+
+// MinScore returns review.min_score for a feature, honoring its override;
+// 7 when there is no config.
+func MinScore(projectDir, featureID string) int {
+	cfg, err := LoadConfig(projectDir)
+	if err != nil {
+		return 7
+	}
+	return cfg.ForFeature(featureID).Review.MinScore
+}
+
+// Milestone is a target date (YYYY-MM-DD) for a set of features.
+type Milestone struct {
+	Target   string
+	Features []string
+}
+
+// SeedRequest describes an HTTP request under seed_requests.<name>.
+// TokenEnv names an env var holding a bearer token.
+type SeedRequest struct {
+	URL      string
+	Method   string
+	Body     string
+	TokenEnv string
+}
+
+// AuditConfig enables tamper evidence: a signed event chain and signed
+// state files, checked by `ptsd verify-log`.
+type AuditConfig struct {
+	Sign bool
+}
+
+// TasksConfig controls `ptsd task next`. Scheduling is "priority" (strict
+// priority order, the default) or "fair" (round-robin across features).
+type TasksConfig struct {
+	Scheduling string
+}
+
+// PRDConfig.MaxKB (prd.max_kb) is the size above which a PRD file draws a
+// validate and doctor warning suggesting a split; 0 disables the warning.
+type PRDConfig struct {
+	MaxKB int
+}
+
+// DefaultPRDMaxKB is prd.max_kb when unset.
+const DefaultPRDMaxKB = 1024
+
+// NetworkConfig.AirGapped (network.air_gapped) switches off every subsystem
+// that can reach the network, for regulated environments; see CheckNetwork.
+type NetworkConfig struct {
+	AirGapped bool
+}
+
+// GatesConfig controls the PreToolUse gate. Mode is "enforce" (the default:
+// blocked writes fail the hook) or "shadow" (would-be blocks are logged to
+// hooks.log and events.yaml and summarized by context, but never block).
+type GatesConfig struct {
+	Mode string
+}
+
+// Gate modes for gates.mode.
+const (
+	GatesEnforce = "enforce"
+	GatesShadow  = "shadow"
+)
+
+// PathsConfig relocates the pipeline artifacts so a project can keep its
+// existing docs and features directories. Paths are project-relative and
+// slash-separated; the PRD's directory also holds the other PRD parts.
+type PathsConfig struct {
+	PRD   string // main PRD file (default .ptsd/docs/PRD.md)
+	BDD   string // directory of <feature>.feature files (default .ptsd/bdd)
+	Seeds string // directory of <feature>/seed.yaml (default .ptsd/seeds)
+}
+
+// DiscoveryConfig bounds the repository walks done by adopt, validate and the
+// gates. Zero values fall back to DefaultWalkLimits.
+type DiscoveryConfig struct {
+	MaxDepth  int
+	MaxFiles  int
+	MaxFileKB int
+	Timeout   int // seconds
+}
+
+// PipelineConfig customizes the stage list. Stages must contain the built-in
+// prd, seed, bdd, tests, impl in that order; extra review-only stages (e.g.
+// design, security-review) may be inserted between them.
+type PipelineConfig struct {
+	Stages []string
 }
 
 type ProjectConfig struct {
 	Name string
+	// Profile is the init scaffolding profile: minimal, standard or full.
+	Profile string
 }
 
 type TestingConfig struct {
 	Runner       string
 	Patterns     PatternsConfig
 	ResultParser ResultParserConfig
+	// MaxFailures stops a test run once this many tests have failed
+	// (0 = run to completion). `ptsd test run --fail-fast` means 1.
+	MaxFailures int
 }
 
 type PatternsConfig struct {
@@ -40,12 +224,48 @@ type ResultParserConfig struct {
 type ReviewConfig struct {
 	MinScore int
 	AutoRedo bool
+	GitNotes bool // also attach review records to HEAD as git notes
+	// TokenEnv names the env var holding the shared token `ptsd review
+	// serve` requires as a bearer token (default PTSD_REVIEW_TOKEN).
+	TokenEnv string
+	// StaleAfter flags passing reviews older than this for re-review, even
+	// when nothing changed (0 = never).
+	StaleAfter time.Duration
+	// Require is how many distinct reviewers must score a stage before its
+	// gate can pass (default 1); Strategy combines their scores.
+	Require  int
+	Strategy string
 }
 
+// Review strategies: how several reviewers' scores for one stage combine.
+// latest (the default) gates on the most recent score, min on the lowest,
+// avg on the average rounded down.
+const (
+	ReviewStrategyLatest = "latest"
+	ReviewStrategyMin    = "min"
+	ReviewStrategyAvg    = "avg"
+)
+
+// ReviewStrategies lists the valid review.strategy values.
+var ReviewStrategies = []string{ReviewStrategyLatest, ReviewStrategyMin, ReviewStrategyAvg}
+
 type HooksConfig struct {
 	PreCommit bool
-	Scopes    []string
-	Types     []string
+	// PrePush installs a pre-push hook refusing commits that never passed
+	// `ptsd validate` (e.g. made with --no-verify).
+	PrePush bool
+	Scopes  []string
+	Types   []string
+	// AutoTrackDebounce batches post-tool-use tracking: state is written at
+	// most once per this many seconds (0 = track every edit).
+	AutoTrackDebounce int
+}
+
+// RemoteConfig points at an HTTP object store (S3/GCS-compatible) used to share
+// pipeline state between clones. TokenEnv names the env var holding a bearer token.
+type RemoteConfig struct {
+	URL      string
+	TokenEnv string
 }
 
 func LoadConfig(dir string) (*Config, error) {
@@ -54,19 +274,31 @@ func LoadConfig(dir string) (*Config, error) {
 		return nil, err
 	}
 
-	content, err := os.ReadFile(cfgPath)
+	content, err := readFileCached(cfgPath)
 	if err != nil {
 		return nil, fmt.Errorf("err:config %w", err)
 	}
-
-	cfg, err := parseConfig(string(content))
-	if err != nil {
-		return nil, err
+	text := string(content)
+	if extendsSource(text) != "" {
+		if text, err = resolveExtends(filepath.Dir(filepath.Dir(cfgPath)), text); err != nil {
+			return nil, err
+		}
 	}
 
-	applyDefaults(cfg)
+	return configMemo.get(text, func(content string) (*Config, error) {
+		upgraded, err := upgradeSchema("ptsd.yaml", content)
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := parseConfig(upgraded)
+		if err != nil {
+			return nil, err
+		}
+
+		applyDefaults(cfg)
 
-	return cfg, nil
+		return cfg, nil
+	})
 }
 
 func findConfigPath(dir string) (string, error) {
@@ -112,6 +344,7 @@ func parseInlineArray(value string) []string {
 func parseConfig(content string) (*Config, error) {
 	cfg := &Config{
 		Hooks: HooksConfig{PreCommit: true},
+		PRD:   PRDConfig{MaxKB: DefaultPRDMaxKB},
 	}
 
 	lines := strings.Split(content, "\n")
@@ -137,6 +370,54 @@ func parseConfig(content string) (*Config, error) {
 			continue
 		}
 
+		// features_config.<id>.patterns as a block list.
+		if currentSection == "features_config" && currentSubSection != "" && strings.TrimSpace(line) == "patterns:" {
+			var patterns []string
+			for _, next := range lines[i+1:] {
+				item, ok := strings.CutPrefix(strings.TrimSpace(next), "- ")
+				if !ok || !strings.HasPrefix(next, "      ") {
+					break
+				}
+				patterns = append(patterns, stripQuotes(strings.TrimSpace(item)))
+			}
+			if cfg.Features == nil {
+				cfg.Features = make(map[string]FeatureOverride)
+			}
+			o := cfg.Features[currentSubSection]
+			o.Patterns = patterns
+			cfg.Features[currentSubSection] = o
+			continue
+		}
+
+		// milestones.<name>.features as a block list.
+		if currentSection == "milestones" && currentSubSection != "" && strings.TrimSpace(line) == "features:" {
+			var ids []string
+			for _, next := range lines[i+1:] {
+				item, ok := strings.CutPrefix(strings.TrimSpace(next), "- ")
+				if !ok || !strings.HasPrefix(next, "      ") {
+					break
+				}
+				ids = append(ids, stripQuotes(strings.TrimSpace(item)))
+			}
+			if cfg.Milestones == nil {
+				cfg.Milestones = make(map[string]Milestone)
+			}
+			m := cfg.Milestones[currentSubSection]
+			m.Features = ids
+			cfg.Milestones[currentSubSection] = m
+			continue
+		}
+
+		// Top-level scalars end the current section.
+		if key, value, ok := strings.Cut(line, ": "); ok && !strings.HasPrefix(line, " ") {
+			currentSection, currentSubSection = "", ""
+			switch key {
+			case "extends":
+				cfg.Extends = stripQuotes(strings.TrimSpace(value))
+			}
+			continue
+		}
+
 		if strings.Contains(line, ": ") {
 			parts := strings.SplitN(line, ": ", 2)
 			if len(parts) != 2 {
@@ -147,8 +428,14 @@ func parseConfig(content string) (*Config, error) {
 			value = stripQuotes(value)
 
 			if currentSection == "project" {
-				if key == "name" {
+				switch key {
+				case "name":
 					cfg.Project.Name = value
+				case "profile":
+					if err := validProfile(value); err != nil {
+						return nil, fmt.Errorf("err:config invalid project.profile: %s", value)
+					}
+					cfg.Project.Profile = value
 				}
 			} else if currentSection == "testing" {
 				if currentSubSection == "patterns" && key == "files" {
@@ -172,6 +459,12 @@ func parseConfig(content string) (*Config, error) {
 					}
 				} else if key == "runner" {
 					cfg.Testing.Runner = value
+				} else if key == "max_failures" {
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 0 {
+						return nil, fmt.Errorf("err:config invalid max_failures: %s", value)
+					}
+					cfg.Testing.MaxFailures = n
 				}
 			} else if currentSection == "review" {
 				switch key {
@@ -183,12 +476,166 @@ func parseConfig(content string) (*Config, error) {
 					cfg.Review.MinScore = n
 				case "auto_redo":
 					cfg.Review.AutoRedo = value == "true"
+				case "git_notes":
+					cfg.Review.GitNotes = value == "true"
+				case "token_env":
+					cfg.Review.TokenEnv = value
+				case "stale_after":
+					d, err := parseAge(value)
+					if err != nil {
+						return nil, fmt.Errorf("err:config invalid stale_after: %s (use e.g. 30d, 2w or 720h)", value)
+					}
+					cfg.Review.StaleAfter = d
+				case "require":
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 1 {
+						return nil, fmt.Errorf("err:config invalid review.require: %s (must be 1 or more)", value)
+					}
+					cfg.Review.Require = n
+				case "strategy":
+					if !containsString(ReviewStrategies, value) {
+						return nil, fmt.Errorf("err:config invalid review.strategy: %s (must be %s)", value, strings.Join(ReviewStrategies, "|"))
+					}
+					cfg.Review.Strategy = value
+				}
+			} else if currentSection == "pipeline" {
+				if key == "stages" {
+					if inline := parseInlineArray(parts[1]); inline != nil {
+						cfg.Pipeline.Stages = inline
+					} else {
+						cfg.Pipeline.Stages = parseArray(lines, i)
+					}
+					if err := validateStages(cfg.Pipeline.Stages); err != nil {
+						return nil, err
+					}
+				}
+			} else if currentSection == "seed_requests" && currentSubSection != "" {
+				if cfg.SeedRequests == nil {
+					cfg.SeedRequests = make(map[string]SeedRequest)
+				}
+				req := cfg.SeedRequests[currentSubSection]
+				switch key {
+				case "url":
+					req.URL = value
+				case "method":
+					req.Method = strings.ToUpper(value)
+				case "body":
+					req.Body = value
+				case "token_env":
+					req.TokenEnv = value
+				}
+				cfg.SeedRequests[currentSubSection] = req
+			} else if currentSection == "features_config" && currentSubSection != "" {
+				if cfg.Features == nil {
+					cfg.Features = make(map[string]FeatureOverride)
+				}
+				o := cfg.Features[currentSubSection]
+				switch key {
+				case "runner":
+					o.Runner = value
+				case "patterns":
+					o.Patterns = parseInlineArray(parts[1])
+				case "min_score":
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 1 {
+						return nil, fmt.Errorf("err:config invalid features_config.%s.min_score: %s", currentSubSection, value)
+					}
+					o.MinScore = n
+				}
+				cfg.Features[currentSubSection] = o
+			} else if currentSection == "milestones" && currentSubSection != "" {
+				if cfg.Milestones == nil {
+					cfg.Milestones = make(map[string]Milestone)
+				}
+				m := cfg.Milestones[currentSubSection]
+				switch key {
+				case "target":
+					if _, err := time.Parse(revisitLayout, value); err != nil {
+						return nil, fmt.Errorf("err:config invalid milestones.%s.target: %s (must be YYYY-MM-DD)", currentSubSection, value)
+					}
+					m.Target = value
+				case "features":
+					m.Features = parseInlineArray(parts[1])
+				}
+				cfg.Milestones[currentSubSection] = m
+			} else if currentSection == "tasks" {
+				if key == "scheduling" {
+					if value != SchedulingPriority && value != SchedulingFair {
+						return nil, fmt.Errorf("err:config invalid tasks.scheduling: %s (must be priority|fair)", value)
+					}
+					cfg.Tasks.Scheduling = value
+				}
+			} else if currentSection == "gates" {
+				if key == "mode" {
+					if value != GatesEnforce && value != GatesShadow {
+						return nil, fmt.Errorf("err:config invalid gates.mode: %s (must be enforce|shadow)", value)
+					}
+					cfg.Gates.Mode = value
+				}
+			} else if currentSection == "paths" {
+				clean, err := cleanArtifactPath(key, value)
+				if err != nil {
+					return nil, err
+				}
+				switch key {
+				case "prd":
+					cfg.Paths.PRD = clean
+				case "bdd":
+					cfg.Paths.BDD = clean
+				case "seeds":
+					cfg.Paths.Seeds = clean
+				}
+			} else if currentSection == "prd" {
+				if key == "max_kb" {
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 0 {
+						return nil, fmt.Errorf("err:config invalid prd.max_kb: %s", value)
+					}
+					cfg.PRD.MaxKB = n
+				}
+			} else if currentSection == "network" {
+				if key == "air_gapped" {
+					cfg.Network.AirGapped = value == "true"
+				}
+			} else if currentSection == "audit" {
+				if key == "sign" {
+					cfg.Audit.Sign = value == "true"
+				}
+			} else if currentSection == "discovery" {
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("err:config invalid discovery.%s: %s", key, value)
+				}
+				switch key {
+				case "max_depth":
+					cfg.Discovery.MaxDepth = n
+				case "max_files":
+					cfg.Discovery.MaxFiles = n
+				case "max_file_kb":
+					cfg.Discovery.MaxFileKB = n
+				case "timeout":
+					cfg.Discovery.Timeout = n
+				}
+			} else if currentSection == "remote" {
+				switch key {
+				case "url":
+					cfg.Remote.URL = strings.TrimSuffix(value, "/")
+				case "token_env":
+					cfg.Remote.TokenEnv = value
 				}
 			} else if currentSection == "hooks" {
 				switch key {
 				case "pre_commit":
 					cfg.Hooks.PreCommit = value == "true"
 					preCommitExplicit = true
+				case "pre_push":
+					cfg.Hooks.PrePush = value == "true"
+				case "autotrack_debounce":
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 0 {
+						return nil, fmt.Errorf("err:config invalid autotrack_debounce: %s", value)
+					}
+					cfg.Hooks.AutoTrackDebounce = n
 				case "scopes":
 					if inline := parseInlineArray(parts[1]); inline != nil {
 						cfg.Hooks.Scopes = inline
@@ -238,4 +685,31 @@ func applyDefaults(cfg *Config) {
 	if cfg.Review.MinScore == 0 {
 		cfg.Review.MinScore = 7
 	}
+	if cfg.Review.Require == 0 {
+		cfg.Review.Require = 1
+	}
+	if cfg.Review.Strategy == "" {
+		cfg.Review.Strategy = ReviewStrategyLatest
+	}
+	if cfg.Review.TokenEnv == "" {
+		cfg.Review.TokenEnv = "PTSD_REVIEW_TOKEN"
+	}
+	if cfg.Project.Profile == "" {
+		cfg.Project.Profile = ProfileFull
+	}
+	if cfg.Gates.Mode == "" {
+		cfg.Gates.Mode = GatesEnforce
+	}
+	if cfg.Tasks.Scheduling == "" {
+		cfg.Tasks.Scheduling = SchedulingPriority
+	}
+	if cfg.Paths.PRD == "" {
+		cfg.Paths.PRD = DefaultPRDPath
+	}
+	if cfg.Paths.BDD == "" {
+		cfg.Paths.BDD = DefaultBDDDir
+	}
+	if cfg.Paths.Seeds == "" {
+		cfg.Paths.Seeds = DefaultSeedsDir
+	}
 }
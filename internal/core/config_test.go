@@ -315,3 +315,79 @@ hooks:
 		}
 	}
 }
+
+func TestFeaturesConfigOverrides(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "api:in-progress", "web-ui:in-progress")
+	cfgYAML := "testing:\n  runner: go test ./...\n  patterns:\n    files: [\"**/*_test.go\"]\n" +
+		"review:\n  min_score: 7\n" +
+		"features_config:\n" +
+		"  web-ui:\n    runner: \"npx vitest run\"\n    patterns:\n      - \"web/**/*.test.ts\"\n    min_score: 9\n" +
+		"  ghost:\n    patterns: [\"ghost/**\"]\n"
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte(cfgYAML), 0644)
+
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	web := cfg.ForFeature("web-ui")
+	if web.Testing.Runner != "npx vitest run" || strings.Join(web.Testing.Patterns.Files, ",") != "web/**/*.test.ts" || web.Review.MinScore != 9 {
+		t.Errorf("web-ui config = %+v %+v", web.Testing, web.Review)
+	}
+	if api := cfg.ForFeature("api"); api.Testing.Runner != "go test ./..." || api.Review.MinScore != 7 {
+		t.Errorf("api must keep project settings: %+v", api.Testing)
+	}
+	if cfg.Testing.Runner != "go test ./..." {
+		t.Error("ForFeature must not modify the project config")
+	}
+	if got := strings.Join(cfg.TestPatterns(), ","); got != "**/*_test.go,ghost/**,web/**/*.test.ts" {
+		t.Errorf("TestPatterns = %s", got)
+	}
+	if MinScore(dir, "web-ui") != 9 || MinScore(dir, "api") != 7 {
+		t.Errorf("MinScore = %d/%d", MinScore(dir, "web-ui"), MinScore(dir, "api"))
+	}
+	if kind, _ := ClassifyFile(dir, "web/app/login.test.ts"); kind != "TEST" {
+		t.Errorf("feature pattern file classified as %s", kind)
+	}
+
+	errs := checkFeatureOverrides(dir, []Feature{{ID: "api"}, {ID: "web-ui"}})
+	if len(errs) != 1 || errs[0].Feature != "ghost" || errs[0].Rule != "features-config" {
+		t.Errorf("unexpected findings: %+v", errs)
+	}
+
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("features_config:\n  web-ui:\n    min_score: high\n"), 0644)
+	if _, err := LoadConfig(dir); err == nil || !strings.Contains(err.Error(), "features_config.web-ui.min_score") {
+		t.Errorf("expected invalid min_score error, got %v", err)
+	}
+}
+
+func TestFeaturesConfigRunnerOverride(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "api:in-progress", "web-ui:in-progress")
+	os.WriteFile(filepath.Join(dir, "web.sh"), []byte("#!/bin/sh\necho \"ok 1 - web $1\"\n"), 0755)
+	os.WriteFile(filepath.Join(dir, "api.sh"), []byte("#!/bin/sh\necho 'ok 1 - api'\necho 'ok 2 - api'\n"), 0755)
+	os.WriteFile(filepath.Join(dir, "login.test.ts"), []byte("test('x')\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("testing:\n  runner: ./api.sh\n"+
+		"  result_parser:\n    format: tap\nfeatures_config:\n  web-ui:\n    runner: ./web.sh\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte("features:\n  web-ui:\n    stage: tests\n    hashes:\n    scores:\n"+
+		"    tests:\n      - .ptsd/bdd/web-ui.feature::login.test.ts\n"), 0644)
+
+	results, err := RunTests(dir, "web-ui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.Passed != 1 || results.Total != 1 {
+		t.Errorf("feature run used the wrong runner: %+v", results)
+	}
+
+	// A full run adds the overridden feature's runner to the project runner.
+	results, err = RunTests(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.Passed != 3 || len(results.Features) != 1 || results.Features[0].Feature != "web-ui" {
+		t.Errorf("full run = %+v", results)
+	}
+	state, _ := LoadState(dir)
+	if state.Features["web-ui"].Hashes["test_status"] != "passing" {
+		t.Errorf("web-ui results not recorded: %+v", state.Features["web-ui"].Hashes)
+	}
+}
@@ -0,0 +1,265 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	commitTypeRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+)
+
+// AddCommitScope adds a scope to hooks.scopes. Scopes must be one of
+// DefaultCommitScopes, because staged files can only be classified into those.
+func AddCommitScope(projectDir, scope string) error {
+	if !validScopes[scope] {
+		return fmt.Errorf("err:user unknown scope %q: must be one of %s", scope, strings.Join(DefaultCommitScopes, "|"))
+	}
+	scopes := CommitScopes(projectDir)
+	if containsString(scopes, scope) {
+		return fmt.Errorf("err:user scope %s already configured", scope)
+	}
+	return setHooksList(projectDir, "scopes", append(append([]string{}, scopes...), scope))
+}
+
+// RemoveCommitScope removes a scope from hooks.scopes. The last scope cannot be removed.
+func RemoveCommitScope(projectDir, scope string) error {
+	scopes, err := removeListItem(CommitScopes(projectDir), scope, "scope")
+	if err != nil {
+		return err
+	}
+	return setHooksList(projectDir, "scopes", scopes)
+}
+
+// AddCommitType adds a commit type to hooks.types.
+func AddCommitType(projectDir, commitType string) error {
+	if !commitTypeRe.MatchString(commitType) {
+		return fmt.Errorf("err:user invalid commit type %q: use lowercase letters, digits, '-'", commitType)
+	}
+	types := CommitTypes(projectDir)
+	if containsString(types, commitType) {
+		return fmt.Errorf("err:user type %s already configured", commitType)
+	}
+	return setHooksList(projectDir, "types", append(append([]string{}, types...), commitType))
+}
+
+// RemoveCommitType removes a commit type from hooks.types. The last type cannot be removed.
+func RemoveCommitType(projectDir, commitType string) error {
+	types, err := removeListItem(CommitTypes(projectDir), commitType, "type")
+	if err != nil {
+		return err
+	}
+	return setHooksList(projectDir, "types", types)
+}
+
+func removeListItem(list []string, item, kind string) ([]string, error) {
+	if !containsString(list, item) {
+		return nil, fmt.Errorf("err:user %s %s not configured", kind, item)
+	}
+	if len(list) == 1 {
+		return nil, fmt.Errorf("err:user cannot remove the last %s", kind)
+	}
+	var out []string
+	for _, v := range list {
+		if v != item {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// setHooksList rewrites `hooks.<key>` in ptsd.yaml as an inline array, replacing
+// either the inline or the block form and leaving the rest of the file untouched.
+func setHooksList(projectDir, key string, items []string) error {
+	cfgPath, err := findConfigPath(projectDir)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("err:config %w", err)
+	}
+
+	newLine := "  " + key + ": [" + strings.Join(items, ", ") + "]"
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	var out []string
+	inHooks, replaced, sectionEnd := false, false, -1
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		topLevel := line != "" && !strings.HasPrefix(line, " ")
+		if topLevel {
+			if inHooks && sectionEnd == -1 {
+				sectionEnd = len(out)
+			}
+			inHooks = strings.TrimRight(line, " ") == "hooks:"
+		}
+		if inHooks && strings.HasPrefix(line, "  "+key+":") {
+			out = append(out, newLine)
+			replaced = true
+			for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "    - ") {
+				i++
+			}
+			continue
+		}
+		out = append(out, line)
+	}
+	if inHooks && sectionEnd == -1 {
+		sectionEnd = len(out)
+	}
+
+	if !replaced {
+		if sectionEnd >= 0 {
+			// Insert after the last non-blank line of the hooks section.
+			for sectionEnd > 0 && strings.TrimSpace(out[sectionEnd-1]) == "" {
+				sectionEnd--
+			}
+			out = append(out[:sectionEnd], append([]string{newLine}, out[sectionEnd:]...)...)
+		} else {
+			out = append(out, "", "hooks:", newLine)
+		}
+	}
+
+	if err := writeFileAtomic(cfgPath, []byte(strings.Join(out, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// setConfigValue sets the scalar at a dotted path such as `project.profile`
+// or `testing.patterns.files` in ptsd.yaml to value (written verbatim). The
+// key's previous value, including a block list under it, is replaced; missing
+// keys and sections are added. The rest of the file is left untouched.
+func setConfigValue(projectDir, path, value string) error {
+	cfgPath, lines, err := readConfigLines(projectDir)
+	if err != nil {
+		return err
+	}
+	return writeConfigLines(cfgPath, setConfigLines(lines, path, value))
+}
+
+func readConfigLines(projectDir string) (string, []string, error) {
+	cfgPath, err := findConfigPath(projectDir)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("err:config %w", err)
+	}
+	return cfgPath, strings.Split(strings.TrimRight(string(data), "\n"), "\n"), nil
+}
+
+// configSpan narrows lines to the body of each existing ancestor of path in
+// turn. It returns the body [start, end) of the deepest one found and its
+// depth; depth == len(parts)-1 means every ancestor exists.
+func configSpan(lines, parts []string) (start, end, depth int) {
+	start, end = 0, len(lines)
+	for ; depth < len(parts)-1; depth++ {
+		header := -1
+		for i := start; i < end; i++ {
+			if configIndent(lines[i]) == depth*2 && strings.TrimSpace(lines[i]) == parts[depth]+":" {
+				header = i
+				break
+			}
+		}
+		if header < 0 {
+			break
+		}
+		bodyEnd := header + 1
+		for bodyEnd < end && (configBlank(lines[bodyEnd]) || configIndent(lines[bodyEnd]) > depth*2) {
+			bodyEnd++
+		}
+		for bodyEnd > header+1 && configBlank(lines[bodyEnd-1]) {
+			bodyEnd--
+		}
+		start, end = header+1, bodyEnd
+	}
+	return start, end, depth
+}
+
+// configKeyLines finds key at depth within [start, end) and returns its line
+// and the index just past its value (block lists and nested lines included).
+func configKeyLines(lines []string, start, end, depth int, key string) (at, next int, ok bool) {
+	for i := start; i < end; i++ {
+		if configIndent(lines[i]) != depth*2 || !strings.HasPrefix(strings.TrimSpace(lines[i]), key+":") {
+			continue
+		}
+		next = i + 1
+		for next < end && (configIndent(lines[next]) > depth*2 || strings.HasPrefix(strings.TrimSpace(lines[next]), "- ")) {
+			next++
+		}
+		return i, next, true
+	}
+	return 0, 0, false
+}
+
+func configIndent(line string) int { return len(line) - len(strings.TrimLeft(line, " ")) }
+
+func configBlank(line string) bool { return strings.TrimSpace(line) == "" }
+
+// setConfigLines is setConfigValue on the lines of ptsd.yaml.
+func setConfigLines(lines []string, path, value string) []string {
+	parts := strings.Split(path, ".")
+	start, end, depth := configSpan(lines, parts)
+
+	key := parts[len(parts)-1]
+	var out []string
+	if depth == len(parts)-1 {
+		if i, next, ok := configKeyLines(lines, start, end, depth, key); ok {
+			out = append(out, lines[:i]...)
+			out = append(out, strings.Repeat("  ", depth)+key+": "+value)
+			return append(out, lines[next:]...)
+		}
+	}
+
+	var insert []string
+	if depth == 0 && len(lines) > 0 && !configBlank(lines[len(lines)-1]) {
+		insert = append(insert, "")
+	}
+	for d := depth; d < len(parts)-1; d++ {
+		insert = append(insert, strings.Repeat("  ", d)+parts[d]+":")
+	}
+	insert = append(insert, strings.Repeat("  ", len(parts)-1)+key+": "+value)
+	out = append(out, lines[:end]...)
+	out = append(out, insert...)
+	return append(out, lines[end:]...)
+}
+
+// unsetConfigLines removes the key at path, with its value, from the lines of
+// ptsd.yaml. Sections left empty are removed too. ok is false when the key
+// is not set.
+func unsetConfigLines(lines []string, path string) (out []string, ok bool) {
+	parts := strings.Split(path, ".")
+	start, end, depth := configSpan(lines, parts)
+	if depth != len(parts)-1 {
+		return lines, false
+	}
+	i, next, found := configKeyLines(lines, start, end, depth, parts[len(parts)-1])
+	if !found {
+		return lines, false
+	}
+	out = append(append(out, lines[:i]...), lines[next:]...)
+	if i > 0 && configBlank(out[i-1]) && (i == len(out) || configBlank(out[i])) {
+		out = append(out[:i-1], out[i:]...)
+	}
+	if len(parts) > 1 {
+		// Drop the section header once the section has no keys left.
+		if start, end, _ := configSpan(out, parts); start == end {
+			out, _ = unsetConfigLines(out, strings.Join(parts[:len(parts)-1], "."))
+		}
+	}
+	for len(out) > 0 && configBlank(out[len(out)-1]) {
+		out = out[:len(out)-1]
+	}
+	return out, true
+}
+
+func writeConfigLines(cfgPath string, lines []string) error {
+	if err := writeFileAtomic(cfgPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
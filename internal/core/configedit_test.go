@@ -0,0 +1,223 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t)
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestCommitScopesDefaultsWhenUnset(t *testing.T) {
+	dir := writeTestConfig(t, "project:\n  name: X\n")
+	if got := strings.Join(CommitScopes(dir), ","); got != strings.Join(DefaultCommitScopes, ",") {
+		t.Errorf("expected default scopes, got %s", got)
+	}
+}
+
+func TestRemoveAndAddCommitScope(t *testing.T) {
+	dir := writeTestConfig(t, "project:\n  name: X\nhooks:\n  pre_commit: true\n  scopes: [PRD, IMPL, TASK]\nreview:\n  min_score: 8\n")
+
+	if err := RemoveCommitScope(dir, "TASK"); err != nil {
+		t.Fatalf("RemoveCommitScope: %v", err)
+	}
+	if err := AddCommitScope(dir, "BDD"); err != nil {
+		t.Fatalf("AddCommitScope: %v", err)
+	}
+
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(cfg.Hooks.Scopes, ","); got != "PRD,IMPL,BDD" {
+		t.Errorf("expected PRD,IMPL,BDD, got %s", got)
+	}
+	if cfg.Review.MinScore != 8 || !cfg.Hooks.PreCommit {
+		t.Errorf("other settings must be preserved: %+v", cfg)
+	}
+}
+
+func TestAddCommitScopeValidation(t *testing.T) {
+	dir := writeTestConfig(t, "hooks:\n  scopes: [PRD]\n")
+
+	if err := AddCommitScope(dir, "DOCS"); err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Errorf("expected err:user for unclassifiable scope, got %v", err)
+	}
+	if err := AddCommitScope(dir, "PRD"); err == nil {
+		t.Error("expected error for duplicate scope")
+	}
+	if err := RemoveCommitScope(dir, "PRD"); err == nil {
+		t.Error("expected error removing last scope")
+	}
+}
+
+func TestAddCommitTypeAppendsToHooksSection(t *testing.T) {
+	dir := writeTestConfig(t, "project:\n  name: X\n")
+
+	if err := AddCommitType(dir, "docs"); err != nil {
+		t.Fatalf("AddCommitType: %v", err)
+	}
+	if err := AddCommitType(dir, "Bad Type"); err == nil {
+		t.Error("expected invalid type error")
+	}
+
+	types := CommitTypes(dir)
+	if !containsString(types, "docs") || !containsString(types, "feat") {
+		t.Errorf("expected defaults plus docs, got %v", types)
+	}
+
+	if err := ValidateCommit(dir, "[TASK] docs: update notes", nil); err != nil {
+		t.Errorf("configured type must be accepted by ValidateCommit: %v", err)
+	}
+}
+
+func TestValidateCommitHonorsConfiguredScopes(t *testing.T) {
+	dir := writeTestConfig(t, "hooks:\n  scopes: [PRD, TASK]\n")
+	if err := ValidateCommit(dir, "[STATUS] update: sync", nil); err == nil {
+		t.Error("expected scope removed from config to be rejected")
+	}
+}
+
+func TestSetConfigValueNestedPath(t *testing.T) {
+	dir := writeTestConfig(t, "project:\n  name: X\n\ntesting:\n  runner: \"npx jest\"\n  patterns:\n    files:\n    - \"**/*.test.js\"\n\nreview:\n  min_score: 7\n")
+
+	if err := setConfigValue(dir, "testing.patterns.files", `["**/*.test.ts"]`); err != nil {
+		t.Fatal(err)
+	}
+	if err := setConfigValue(dir, "testing.result_parser.format", "json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := setConfigValue(dir, "remote.url", `"https://x"`); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"))
+	want := "project:\n  name: X\n\ntesting:\n  runner: \"npx jest\"\n  patterns:\n    files: [\"**/*.test.ts\"]\n  result_parser:\n    format: json\n\nreview:\n  min_score: 7\n\nremote:\n  url: \"https://x\"\n"
+	if string(data) != want {
+		t.Errorf("unexpected config:\n%s", data)
+	}
+}
+
+func TestDetectRunnerProposesSwitch(t *testing.T) {
+	dir := writeTestConfig(t, "project:\n  name: X\ntesting:\n  runner: \"npx jest\"\n  patterns:\n    files: [\"**/*.test.ts\", \"**/*.test.tsx\", \"**/*.test.js\", \"**/*.spec.ts\", \"**/*.spec.js\"]\n")
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"devDependencies":{"vitest":"^1.0.0"}}`), 0644)
+
+	p, err := DetectRunner(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.CurrentRunner != "npx jest" || p.Runner != "npx vitest run" || !p.Changed() {
+		t.Fatalf("unexpected proposal: %+v", p)
+	}
+	if err := ApplyRunner(dir, p); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Testing.Runner != "npx vitest run" || strings.Join(cfg.Testing.Patterns.Files, ",") != strings.Join(p.Patterns, ",") {
+		t.Errorf("config not updated: %+v", cfg.Testing)
+	}
+
+	p, _ = DetectRunner(dir)
+	if p.Changed() {
+		t.Errorf("expected no change after apply, got %+v", p)
+	}
+}
+
+func TestDetectRunnerKeepsCustomPatterns(t *testing.T) {
+	dir := writeTestConfig(t, "project:\n  name: X\ntesting:\n  runner: \"npx jest\"\n  patterns:\n    files: [\"src/**/*.unit.ts\"]\n")
+	os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"devDependencies":{"vitest":"^1.0.0"}}`), 0644)
+
+	p, err := DetectRunner(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(p.Patterns, ",") != "src/**/*.unit.ts" {
+		t.Errorf("custom patterns must be kept, got %v", p.Patterns)
+	}
+
+	empty := writeTestConfig(t, "project:\n  name: X\n")
+	if _, err := DetectRunner(empty); err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Errorf("expected err:config when nothing is detected, got %v", err)
+	}
+}
+
+func TestSetConfigValueCoercesAndValidates(t *testing.T) {
+	dir := writeTestConfig(t, "project:\n  name: X\n\nreview:\n  min_score: 7\n")
+
+	for _, tc := range []struct{ key, raw, want string }{
+		{"review.min_score", " 8 ", "8"},
+		{"review.auto_redo", "yes", "true"},
+		{"hooks.scopes", "PRD, TASK", `["PRD", "TASK"]`},
+		{"gates.mode", "shadow", "shadow"},
+		{"remote.url", "https://store.example/ptsd", `"https://store.example/ptsd"`},
+	} {
+		got, err := SetConfigValue(dir, tc.key, tc.raw)
+		if err != nil || got != tc.want {
+			t.Errorf("set %s %q = %q, %v; want %q", tc.key, tc.raw, got, err, tc.want)
+		}
+	}
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Review.MinScore != 8 || !cfg.Review.AutoRedo || cfg.Gates.Mode != GatesShadow || strings.Join(cfg.Hooks.Scopes, ",") != "PRD,TASK" {
+		t.Errorf("config not updated: %+v", cfg)
+	}
+
+	before, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"))
+	for _, tc := range []struct{ key, raw, want string }{
+		{"review.min_score", "eleven", "err:user"},
+		{"review.min_score", "11", "err:user"},
+		{"hooks.pre_push", "maybe", "err:user"},
+		{"tasks.scheduling", "random", "err:user"},
+		{"hooks.scopes", "PRD,DOCS", "err:user"},
+		{"pipeline.stages", "prd,bdd", "err:config"},
+		{"testing.runner", `go "test"`, "err:user"},
+		{"no.such.key", "1", "err:user"},
+	} {
+		if _, err := SetConfigValue(dir, tc.key, tc.raw); err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+			t.Errorf("set %s %q: expected %s error, got %v", tc.key, tc.raw, tc.want, err)
+		}
+	}
+	after, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"))
+	if string(after) != string(before) {
+		t.Errorf("rejected values must not touch ptsd.yaml:\n%s", after)
+	}
+
+	if v, err := GetConfigValue(dir, "review.token_env"); err != nil || v != "PTSD_REVIEW_TOKEN" {
+		t.Errorf("get review.token_env = %q, %v; want the default", v, err)
+	}
+}
+
+func TestUnsetConfigValueRemovesKeyAndEmptySection(t *testing.T) {
+	dir := writeTestConfig(t, "project:\n  name: X\n\ngates:\n  mode: shadow\n\nreview:\n  min_score: 9\n  auto_redo: true\n")
+
+	if removed, err := UnsetConfigValue(dir, "review.min_score"); err != nil || !removed {
+		t.Fatalf("unset review.min_score = %v, %v", removed, err)
+	}
+	if removed, err := UnsetConfigValue(dir, "gates.mode"); err != nil || !removed {
+		t.Fatalf("unset gates.mode = %v, %v", removed, err)
+	}
+	if removed, err := UnsetConfigValue(dir, "gates.mode"); err != nil || removed {
+		t.Errorf("second unset = %v, %v; want not removed", removed, err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"))
+	if want := "project:\n  name: X\n\nreview:\n  auto_redo: true\n"; string(data) != want {
+		t.Errorf("unexpected config:\n%s", data)
+	}
+	if v, _ := GetConfigValue(dir, "review.min_score"); v != "7" {
+		t.Errorf("min_score after unset = %s, want default 7", v)
+	}
+}
@@ -0,0 +1,252 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// configKey describes one settable key of ptsd.yaml for `ptsd config
+// get|set|unset`. Kind is string, int, bool, enum or list; values are
+// coerced to the kind and checked before ptsd.yaml is rewritten.
+type configKey struct {
+	Path   string
+	Kind   string
+	Values []string           // enum values
+	Min    int                // int lower bound
+	Max    int                // int upper bound; 0 means none
+	check  func(string) error // extra check for a string or each list item
+	get    func(projectDir string, cfg *Config) string
+}
+
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func checkEnvName(v string) error {
+	if !envNameRe.MatchString(v) {
+		return fmt.Errorf("err:user invalid env var name %q", v)
+	}
+	return nil
+}
+
+func checkScope(v string) error {
+	if !validScopes[v] {
+		return fmt.Errorf("err:user unknown scope %q: must be one of %s", v, strings.Join(DefaultCommitScopes, "|"))
+	}
+	return nil
+}
+
+func checkCommitType(v string) error {
+	if !commitTypeRe.MatchString(v) {
+		return fmt.Errorf("err:user invalid commit type %q: use lowercase letters, digits, '-'", v)
+	}
+	return nil
+}
+
+func checkAge(v string) error {
+	if _, err := parseAge(v); err != nil {
+		return fmt.Errorf("err:user invalid duration %q: use e.g. 30d, 2w or 720h", v)
+	}
+	return nil
+}
+
+func checkURL(v string) error {
+	if !strings.HasPrefix(v, "http://") && !strings.HasPrefix(v, "https://") {
+		return fmt.Errorf("err:user invalid url %q: must start with http:// or https://", v)
+	}
+	return nil
+}
+
+// checkArtifactPath checks a paths.<key> value the way ptsd.yaml loading does.
+func checkArtifactPath(key string) func(string) error {
+	return func(v string) error {
+		if _, err := cleanArtifactPath(key, v); err != nil {
+			return fmt.Errorf("err:user %s", strings.TrimPrefix(err.Error(), "err:config "))
+		}
+		return nil
+	}
+}
+
+var configKeys = []configKey{
+	{Path: "project.name", Kind: "string", get: func(_ string, c *Config) string { return c.Project.Name }},
+	{Path: "project.profile", Kind: "enum", Values: InitProfiles, get: func(_ string, c *Config) string { return c.Project.Profile }},
+	{Path: "testing.runner", Kind: "string", get: func(_ string, c *Config) string { return c.Testing.Runner }},
+	{Path: "testing.patterns.files", Kind: "list", get: func(_ string, c *Config) string { return strings.Join(c.Testing.Patterns.Files, ",") }},
+	{Path: "testing.result_parser.format", Kind: "string", get: func(_ string, c *Config) string { return c.Testing.ResultParser.Format }},
+	{Path: "testing.max_failures", Kind: "int", get: func(_ string, c *Config) string { return strconv.Itoa(c.Testing.MaxFailures) }},
+	{Path: "review.min_score", Kind: "int", Min: 1, Max: 10, get: func(_ string, c *Config) string { return strconv.Itoa(c.Review.MinScore) }},
+	{Path: "review.auto_redo", Kind: "bool", get: func(_ string, c *Config) string { return strconv.FormatBool(c.Review.AutoRedo) }},
+	{Path: "review.git_notes", Kind: "bool", get: func(_ string, c *Config) string { return strconv.FormatBool(c.Review.GitNotes) }},
+	{Path: "review.token_env", Kind: "string", check: checkEnvName, get: func(_ string, c *Config) string { return c.Review.TokenEnv }},
+	{Path: "review.stale_after", Kind: "string", check: checkAge, get: func(_ string, c *Config) string {
+		if c.Review.StaleAfter == 0 {
+			return ""
+		}
+		return formatAge(c.Review.StaleAfter)
+	}},
+	{Path: "review.require", Kind: "int", Min: 1, Max: 10, get: func(_ string, c *Config) string { return strconv.Itoa(c.Review.Require) }},
+	{Path: "review.strategy", Kind: "enum", Values: ReviewStrategies, get: func(_ string, c *Config) string { return c.Review.Strategy }},
+	{Path: "hooks.pre_commit", Kind: "bool", get: func(_ string, c *Config) string { return strconv.FormatBool(c.Hooks.PreCommit) }},
+	{Path: "hooks.pre_push", Kind: "bool", get: func(_ string, c *Config) string { return strconv.FormatBool(c.Hooks.PrePush) }},
+	{Path: "hooks.scopes", Kind: "list", check: checkScope, get: func(dir string, _ *Config) string { return strings.Join(CommitScopes(dir), ",") }},
+	{Path: "hooks.types", Kind: "list", check: checkCommitType, get: func(dir string, _ *Config) string { return strings.Join(CommitTypes(dir), ",") }},
+	{Path: "hooks.autotrack_debounce", Kind: "int", get: func(_ string, c *Config) string { return strconv.Itoa(c.Hooks.AutoTrackDebounce) }},
+	{Path: "remote.url", Kind: "string", check: checkURL, get: func(_ string, c *Config) string { return c.Remote.URL }},
+	{Path: "remote.token_env", Kind: "string", check: checkEnvName, get: func(_ string, c *Config) string { return c.Remote.TokenEnv }},
+	{Path: "pipeline.stages", Kind: "list", get: func(dir string, _ *Config) string { return strings.Join(PipelineStages(dir), ",") }},
+	{Path: "tasks.scheduling", Kind: "enum", Values: []string{SchedulingPriority, SchedulingFair}, get: func(_ string, c *Config) string { return c.Tasks.Scheduling }},
+	{Path: "gates.mode", Kind: "enum", Values: []string{GatesEnforce, GatesShadow}, get: func(_ string, c *Config) string { return c.Gates.Mode }},
+	{Path: "discovery.max_depth", Kind: "int", get: func(_ string, c *Config) string { return strconv.Itoa(c.Discovery.MaxDepth) }},
+	{Path: "discovery.max_files", Kind: "int", get: func(_ string, c *Config) string { return strconv.Itoa(c.Discovery.MaxFiles) }},
+	{Path: "discovery.max_file_kb", Kind: "int", get: func(_ string, c *Config) string { return strconv.Itoa(c.Discovery.MaxFileKB) }},
+	{Path: "discovery.timeout", Kind: "int", get: func(_ string, c *Config) string { return strconv.Itoa(c.Discovery.Timeout) }},
+	{Path: "prd.max_kb", Kind: "int", get: func(_ string, c *Config) string { return strconv.Itoa(c.PRD.MaxKB) }},
+	{Path: "extends", Kind: "string", get: func(_ string, c *Config) string { return c.Extends }},
+	{Path: "network.air_gapped", Kind: "bool", get: func(_ string, c *Config) string { return strconv.FormatBool(c.Network.AirGapped) }},
+	{Path: "audit.sign", Kind: "bool", get: func(_ string, c *Config) string { return strconv.FormatBool(c.Audit.Sign) }},
+	{Path: "paths.prd", Kind: "string", check: checkArtifactPath("prd"), get: func(_ string, c *Config) string { return c.Paths.PRD }},
+	{Path: "paths.bdd", Kind: "string", check: checkArtifactPath("bdd"), get: func(_ string, c *Config) string { return c.Paths.BDD }},
+	{Path: "paths.seeds", Kind: "string", check: checkArtifactPath("seeds"), get: func(_ string, c *Config) string { return c.Paths.Seeds }},
+}
+
+// ConfigKeys lists the keys `ptsd config get|set|unset` accept.
+func ConfigKeys() []string {
+	out := make([]string, len(configKeys))
+	for i, k := range configKeys {
+		out[i] = k.Path
+	}
+	return out
+}
+
+func lookupConfigKey(path string) (configKey, error) {
+	for _, k := range configKeys {
+		if k.Path == path {
+			return k, nil
+		}
+	}
+	return configKey{}, fmt.Errorf("err:user unknown config key %q: see ptsd config keys", path)
+}
+
+// GetConfigValue returns the effective value of a key, defaults applied.
+// Lists are comma-separated.
+func GetConfigValue(projectDir, path string) (string, error) {
+	k, err := lookupConfigKey(path)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := LoadConfig(projectDir)
+	if err != nil {
+		return "", err
+	}
+	return k.get(projectDir, cfg), nil
+}
+
+// SetConfigValue coerces raw to the key's type, checks that ptsd.yaml still
+// loads with the new value, and only then writes it. It returns the value as
+// written. Lists take comma-separated items; booleans accept
+// true/false/yes/no/on/off/1/0.
+func SetConfigValue(projectDir, path, raw string) (string, error) {
+	k, err := lookupConfigKey(path)
+	if err != nil {
+		return "", err
+	}
+	value, err := k.coerce(raw)
+	if err != nil {
+		return "", err
+	}
+	cfgPath, lines, err := readConfigLines(projectDir)
+	if err != nil {
+		return "", err
+	}
+	lines = setConfigLines(lines, path, value)
+	if _, err := parseConfig(strings.Join(lines, "\n")); err != nil {
+		return "", err
+	}
+	if err := writeConfigLines(cfgPath, lines); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// UnsetConfigValue removes a key from ptsd.yaml so its default applies again.
+// removed is false when the key was not set.
+func UnsetConfigValue(projectDir, path string) (removed bool, err error) {
+	if _, err := lookupConfigKey(path); err != nil {
+		return false, err
+	}
+	cfgPath, lines, err := readConfigLines(projectDir)
+	if err != nil {
+		return false, err
+	}
+	lines, removed = unsetConfigLines(lines, path)
+	if !removed {
+		return false, nil
+	}
+	return true, writeConfigLines(cfgPath, lines)
+}
+
+// coerce converts raw to the YAML written for the key.
+func (k configKey) coerce(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.ContainsAny(raw, "\"\n") {
+		return "", fmt.Errorf("err:user %s: value must not contain quotes or newlines", k.Path)
+	}
+	switch k.Kind {
+	case "int":
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return "", fmt.Errorf("err:user %s must be an integer, got %q", k.Path, raw)
+		}
+		if n < k.Min || (k.Max > 0 && n > k.Max) {
+			if k.Max > 0 {
+				return "", fmt.Errorf("err:user %s must be between %d and %d, got %d", k.Path, k.Min, k.Max, n)
+			}
+			return "", fmt.Errorf("err:user %s must be at least %d, got %d", k.Path, k.Min, n)
+		}
+		return strconv.Itoa(n), nil
+	case "bool":
+		switch strings.ToLower(raw) {
+		case "true", "yes", "on", "1":
+			return "true", nil
+		case "false", "no", "off", "0":
+			return "false", nil
+		}
+		return "", fmt.Errorf("err:user %s must be true or false, got %q", k.Path, raw)
+	case "enum":
+		if !containsString(k.Values, raw) {
+			return "", fmt.Errorf("err:user %s must be %s, got %q", k.Path, strings.Join(k.Values, "|"), raw)
+		}
+		return raw, nil
+	case "list":
+		var items []string
+		for _, item := range strings.Split(strings.Trim(raw, "[]"), ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			if strings.ContainsAny(item, "[]") {
+				return "", fmt.Errorf("err:user %s: invalid list item %q", k.Path, item)
+			}
+			if k.check != nil {
+				if err := k.check(item); err != nil {
+					return "", err
+				}
+			}
+			items = append(items, `"`+item+`"`)
+		}
+		if len(items) == 0 {
+			return "", fmt.Errorf("err:user %s needs at least one item; use config unset for the default", k.Path)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	default:
+		if raw == "" {
+			return "", fmt.Errorf("err:user %s must not be empty; use config unset to clear it", k.Path)
+		}
+		if k.check != nil {
+			if err := k.check(raw); err != nil {
+				return "", err
+			}
+		}
+		return `"` + raw + `"`, nil
+	}
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 )
 
 type ContextLineType string
@@ -12,6 +13,20 @@ const (
 	ContextBlocked ContextLineType = "blocked"
 	ContextDone    ContextLineType = "done"
 	ContextTask    ContextLineType = "task"
+	// ContextRevisit marks a deferred feature whose revisit date has passed.
+	ContextRevisit ContextLineType = "revisit"
+	// ContextShadow reports writes gate-check would have blocked under
+	// gates.mode: shadow — one total line (Feature "") then one per feature.
+	ContextShadow ContextLineType = "shadow"
+	// ContextStale marks a stage whose last passing review is older than
+	// review.stale_after.
+	ContextStale ContextLineType = "stale"
+	// ContextIssue is an open issue from the feature's last review; Reason
+	// holds its text.
+	ContextIssue ContextLineType = "issue"
+	// ContextRegression is an artifact changed since its stage was reached
+	// (see CheckRegressions); Reason holds the message.
+	ContextRegression ContextLineType = "regression"
 )
 
 type ContextLine struct {
@@ -20,24 +35,52 @@ type ContextLine struct {
 	Stage   string
 	Action  string
 	Reason  string
+	// Revisit is the passed revisit date (only when Type == ContextRevisit);
+	// Reason then holds the defer reason.
+	Revisit string
 	// Task fields (only when Type == ContextTask)
 	TaskID     string
 	TaskStatus string
 	TaskTitle  string
+	// LeaseOwner is set when the task is claimed (ptsd task claim);
+	// LeaseExpired marks a lease that ran out, so the task is up for grabs.
+	LeaseOwner   string
+	LeaseExpired bool
+	// Count is the number of would-be blocks (only when Type == ContextShadow);
+	// Reason then holds the latest one.
+	Count int
+	// ReviewedAt and Age describe a stale review (only when Type ==
+	// ContextStale); Stage is the reviewed stage.
+	ReviewedAt time.Time
+	Age        time.Duration
+	// Severity of a review issue or regression (only when Type ==
+	// ContextIssue or ContextRegression).
+	Severity string
+	// File is the changed artifact (only when Type == ContextRegression).
+	File string
 }
 
 type ContextResult struct {
 	Lines []ContextLine
 }
 
-var stageActions = map[string]string{
-	"prd":   "write-seed",
-	"seed":  "write-bdd",
-	"bdd":   "write-tests",
-	"tests": "write-impl",
+// stageAction returns the next action for a feature at stage: write the next
+// stage's artifact, or review a custom (review-only) stage.
+func stageAction(stages []string, stage string) string {
+	next := nextStage(stages, stage)
+	switch {
+	case next == "":
+		return "write-seed"
+	case IsCustomStage(next):
+		return "review-" + next
+	default:
+		return "write-" + next
+	}
 }
 
 func BuildContext(projectDir string) (ContextResult, error) {
+	_, _ = FlushAutoTrack(projectDir)
+
 	features, err := loadFeatures(projectDir)
 	if err != nil {
 		return ContextResult{}, err
@@ -54,6 +97,7 @@ func BuildContext(projectDir string) (ContextResult, error) {
 	}
 
 	var result ContextResult
+	stages := PipelineStages(projectDir)
 
 	for _, f := range features {
 		if f.Status == "planned" || f.Status == "deferred" {
@@ -117,10 +161,11 @@ func BuildContext(projectDir string) (ContextResult, error) {
 			continue
 		}
 
-		action, ok := stageActions[stage]
-		if !ok {
-			action = "write-seed"
+		fstages := stages
+		if f.Pipeline == PipelineLite {
+			fstages = liteStages(stages)
 		}
+		action := stageAction(fstages, stage)
 
 		result.Lines = append(result.Lines, ContextLine{
 			Type:    ContextNext,
@@ -130,32 +175,142 @@ func BuildContext(projectDir string) (ContextResult, error) {
 		})
 	}
 
-	// Emit TODO tasks
+	// Artifacts changed under a reached stage; reported only, validate
+	// applies the downgrades
+	if state, err := LoadState(projectDir); err == nil {
+		warnings, _ := detectRegressions(projectDir, state)
+		for _, w := range warnings {
+			file := w.File
+			if rel, err := filepath.Rel(projectDir, w.File); err == nil {
+				file = filepath.ToSlash(rel)
+			}
+			result.Lines = append(result.Lines, ContextLine{
+				Type:     ContextRegression,
+				Feature:  w.Feature,
+				Stage:    w.FileType,
+				Severity: w.Severity,
+				File:     file,
+				Reason:   w.Message,
+			})
+		}
+	}
+
+	// Open issues from each active feature's last review, so the agent
+	// knows what to fix before asking for another one
+	for _, f := range features {
+		if f.Status == "planned" || f.Status == "deferred" {
+			continue
+		}
+		entry := rs[f.ID]
+		for _, issue := range entry.IssuesList {
+			result.Lines = append(result.Lines, ContextLine{
+				Type:     ContextIssue,
+				Feature:  f.ID,
+				Stage:    entry.Stage,
+				Severity: issue.Severity,
+				Reason:   issue.Text,
+			})
+		}
+	}
+
+	// Surface deferred features that are due for another look
+	due, _ := DueForRevisit(projectDir, time.Now())
+	for _, f := range due {
+		result.Lines = append(result.Lines, ContextLine{
+			Type:    ContextRevisit,
+			Feature: f.ID,
+			Revisit: f.Revisit,
+			Reason:  f.DeferReason,
+		})
+	}
+
+	// Passing reviews older than review.stale_after are due for another look
+	stale, _ := StaleReviews(projectDir, time.Now())
+	for _, st := range stale {
+		result.Lines = append(result.Lines, ContextLine{
+			Type:       ContextStale,
+			Feature:    st.Feature,
+			Stage:      st.Stage,
+			ReviewedAt: st.ReviewedAt,
+			Age:        st.Age,
+		})
+	}
+
+	// In shadow mode, show what enforcement would have blocked so far
+	if GatesShadowMode(projectDir) {
+		blocks, _ := ShadowBlocks(projectDir)
+		total := 0
+		for _, b := range blocks {
+			total += b.Count
+		}
+		result.Lines = append(result.Lines, ContextLine{Type: ContextShadow, Count: total})
+		for _, b := range blocks {
+			result.Lines = append(result.Lines, ContextLine{Type: ContextShadow, Feature: b.Feature, Count: b.Count, Reason: b.Last})
+		}
+	}
+
+	// Emit TODO tasks, with who has claimed them
+	leases := taskLeases(projectDir)
+	now := time.Now()
 	for _, t := range tasks {
 		if t.Status != "TODO" && t.Status != "WIP" {
 			continue
 		}
-		result.Lines = append(result.Lines, ContextLine{
+		line := ContextLine{
 			Type:       ContextTask,
 			Feature:    t.Feature,
 			TaskID:     t.ID,
 			TaskStatus: t.Status,
 			TaskTitle:  t.Title,
-		})
+		}
+		if l, ok := leases[t.ID]; ok {
+			line.LeaseOwner = l.Owner
+			line.LeaseExpired = l.Expired(now)
+		}
+		result.Lines = append(result.Lines, line)
 	}
 
 	return result, nil
 }
 
+// FeatureNext returns the context line saying what to do next for one
+// feature: next, blocked, done or revisit. ok is false when context has no
+// such line, e.g. for an implemented feature.
+func FeatureNext(projectDir, featureID string) (line ContextLine, ok bool, err error) {
+	result, err := BuildContext(projectDir)
+	if err != nil {
+		return ContextLine{}, false, err
+	}
+	for _, l := range result.Lines {
+		if l.Feature != featureID {
+			continue
+		}
+		switch l.Type {
+		case ContextNext, ContextBlocked, ContextDone, ContextRevisit:
+			return l, true, nil
+		}
+	}
+	return ContextLine{}, false, nil
+}
+
 func checkPrerequisite(projectDir, featureID, stage string) (blocked bool, reason string) {
+	if blocked, reason := customStageGate(projectDir, featureID, stage); blocked && !IsCustomStage(stage) {
+		return true, reason
+	}
+	if blocked, reason := dependencyGate(projectDir, featureID, stage); blocked {
+		return true, reason
+	}
+	if isLiteFeature(projectDir, featureID) {
+		return false, ""
+	}
 	switch stage {
 	case "bdd":
-		seedPath := filepath.Join(projectDir, ".ptsd", "seeds", featureID, "seed.yaml")
+		seedPath := seedManifestPath(projectDir, featureID)
 		if !fileExists(seedPath) {
 			return true, "missing seed"
 		}
 	case "tests":
-		bddPath := filepath.Join(projectDir, ".ptsd", "bdd", featureID+".feature")
+		bddPath := bddFilePath(projectDir, featureID)
 		if !fileExists(bddPath) {
 			return true, "missing bdd"
 		}
@@ -66,6 +66,16 @@ func TestBuildContext_Blocked(t *testing.T) {
 	if !found {
 		t.Errorf("expected auth to be blocked with failed review, got: %+v", result.Lines)
 	}
+
+	var issues []ContextLine
+	for _, line := range result.Lines {
+		if line.Type == ContextIssue {
+			issues = append(issues, line)
+		}
+	}
+	if len(issues) != 1 || issues[0].Feature != "auth" || issues[0].Stage != "seed" || issues[0].Severity != SeverityMajor || issues[0].Reason != "low quality" {
+		t.Errorf("expected the open review issue in context, got: %+v", issues)
+	}
 }
 
 func TestBuildContext_Done(t *testing.T) {
@@ -308,3 +318,24 @@ func TestBuildContext_DoneTasksExcluded(t *testing.T) {
 		}
 	}
 }
+
+func TestContextSurfacesFeaturesDueForRevisit(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "billing:planned")
+	if err := DeferFeature(dir, "billing", "vendor contract", "2020-01-01"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := BuildContext(dir)
+	if err != nil {
+		t.Fatalf("BuildContext: %v", err)
+	}
+	found := false
+	for _, l := range result.Lines {
+		if l.Type == ContextRevisit && l.Feature == "billing" && l.Revisit == "2020-01-01" && l.Reason == "vendor contract" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected revisit line for billing, got %+v", result.Lines)
+	}
+}
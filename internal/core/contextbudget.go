@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// EstimateTokens approximates how many LLM tokens s takes: one per four
+// bytes, rounded up, which errs on the generous side for ASCII output.
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// contextPriority ranks a line for `context --budget`, lowest first: the
+// current (WIP) task, regressions, blocked gates and review issues, what
+// to do next, open tasks, then the rest of the feature summary.
+func contextPriority(l ContextLine) int {
+	switch l.Type {
+	case ContextTask:
+		if l.TaskStatus == "WIP" {
+			return 0
+		}
+		return 4
+	case ContextRegression:
+		return 1
+	case ContextBlocked, ContextIssue:
+		return 2
+	case ContextNext:
+		return 3
+	case ContextDone:
+		return 6
+	}
+	return 5 // revisit, stale, shadow
+}
+
+// FitContext renders the lines in priority order and keeps those that fit
+// in budget tokens (see EstimateTokens, newline included). From the first
+// line that does not fit on, everything is dropped and summarized in one
+// final marker, counted against the budget too:
+//
+//	elided: lines=7 tokens=183 task=4 done=3
+//
+// with the count per line type in priority order, so the same state and
+// budget always give the same output. render returns "" for lines it skips.
+func FitContext(result ContextResult, budget int, render func(ContextLine) string) []string {
+	lines := slices.Clone(result.Lines)
+	sort.SliceStable(lines, func(i, j int) bool { return contextPriority(lines[i]) < contextPriority(lines[j]) })
+
+	var types []ContextLineType
+	var rendered []string
+	for _, l := range lines {
+		if text := render(l); text != "" {
+			types = append(types, l.Type)
+			rendered = append(rendered, text)
+		}
+	}
+
+	keep, used := 0, 0
+	for keep < len(rendered) && used+EstimateTokens(rendered[keep]+"\n") <= budget {
+		used += EstimateTokens(rendered[keep] + "\n")
+		keep++
+	}
+	for keep < len(rendered) {
+		marker := elisionMarker(types[keep:], rendered[keep:])
+		if keep == 0 || used+EstimateTokens(marker+"\n") <= budget {
+			return append(rendered[:keep:keep], marker)
+		}
+		keep--
+		used -= EstimateTokens(rendered[keep] + "\n")
+	}
+	return rendered
+}
+
+func elisionMarker(types []ContextLineType, rendered []string) string {
+	tokens := 0
+	for _, text := range rendered {
+		tokens += EstimateTokens(text + "\n")
+	}
+	var order []ContextLineType
+	counts := map[ContextLineType]int{}
+	for _, t := range types {
+		if counts[t] == 0 {
+			order = append(order, t)
+		}
+		counts[t]++
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "elided: lines=%d tokens=%d", len(rendered), tokens)
+	for _, t := range order {
+		fmt.Fprintf(&b, " %s=%d", t, counts[t])
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func renderTestLine(l ContextLine) string {
+	if l.Type == ContextTask {
+		return fmt.Sprintf("task: %s status=%s", l.TaskID, l.TaskStatus)
+	}
+	return fmt.Sprintf("%s: %s", l.Type, l.Feature)
+}
+
+func TestFitContextPriorityAndElision(t *testing.T) {
+	result := ContextResult{Lines: []ContextLine{
+		{Type: ContextDone, Feature: "old"},
+		{Type: ContextNext, Feature: "api"},
+		{Type: ContextTask, TaskID: "T-2", TaskStatus: "TODO"},
+		{Type: ContextBlocked, Feature: "auth"},
+		{Type: ContextRegression, Feature: "auth"},
+		{Type: ContextTask, TaskID: "T-1", TaskStatus: "WIP"},
+	}}
+
+	all := FitContext(result, 1000, renderTestLine)
+	want := []string{"task: T-1 status=WIP", "regression: auth", "blocked: auth", "next: api", "task: T-2 status=TODO", "done: old"}
+	if strings.Join(all, "|") != strings.Join(want, "|") {
+		t.Errorf("expected priority order %q, got %q", want, all)
+	}
+
+	// 6 + 5 tokens for the first two lines, 15 for the marker.
+	fit := FitContext(result, 26, renderTestLine)
+	wantFit := []string{"task: T-1 status=WIP", "regression: auth", "elided: lines=4 tokens=16 blocked=1 next=1 task=1 done=1"}
+	if strings.Join(fit, "|") != strings.Join(wantFit, "|") {
+		t.Errorf("expected %q, got %q", wantFit, fit)
+	}
+	if again := FitContext(result, 26, renderTestLine); strings.Join(again, "|") != strings.Join(fit, "|") {
+		t.Errorf("expected deterministic output, got %q then %q", fit, again)
+	}
+
+	if tiny := FitContext(result, 1, renderTestLine); len(tiny) != 1 || !strings.HasPrefix(tiny[0], "elided: lines=6 ") {
+		t.Errorf("expected only the marker under a tiny budget, got %q", tiny)
+	}
+}
+
+func TestBuildContextReportsRegressionsWithoutWriting(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	ptsd := filepath.Join(dir, ".ptsd")
+	os.MkdirAll(filepath.Join(ptsd, "docs"), 0755)
+	os.WriteFile(filepath.Join(ptsd, "docs", "PRD.md"), []byte("<!-- feature:auth -->\n## Auth\nchanged\n"), 0644)
+	stateYAML := "features:\n  auth:\n    stage: bdd\n    hashes:\n      prd: 0000\n"
+	os.WriteFile(filepath.Join(ptsd, "state.yaml"), []byte(stateYAML), 0644)
+
+	result, err := BuildContext(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, l := range result.Lines {
+		if l.Type == ContextRegression && l.Feature == "auth" && l.File == ".ptsd/docs/PRD.md" && l.Severity == "error" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a prd regression line for auth, got %+v", result.Lines)
+	}
+	if data, _ := os.ReadFile(filepath.Join(ptsd, "state.yaml")); string(data) != stateYAML {
+		t.Errorf("context must not rewrite state.yaml, got:\n%s", data)
+	}
+}
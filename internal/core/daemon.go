@@ -0,0 +1,136 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DaemonRequest is one newline-delimited JSON request sent over the daemon socket.
+type DaemonRequest struct {
+	Cmd    string `json:"cmd"`            // gate-check | auto-track | context | ping | stop
+	File   string `json:"file,omitempty"` // target file for gate-check / auto-track
+	Agent  bool   `json:"agent,omitempty"`
+	Budget int    `json:"budget,omitempty"` // context --budget in tokens, 0 for no limit
+}
+
+// DaemonResponse carries the exact output and exit code the in-process command
+// would have produced, so the client can replay it.
+type DaemonResponse struct {
+	Code   int    `json:"code"`
+	Stdout string `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"`
+}
+
+// DaemonHandler serves a single request. Calls are serialized by ServeDaemon.
+type DaemonHandler func(req DaemonRequest) DaemonResponse
+
+// daemonDialTimeout bounds how long a client waits before falling back to
+// in-process execution.
+const daemonDialTimeout = 50 * time.Millisecond
+
+// DaemonSocketPath returns the unix socket path for a project.
+func DaemonSocketPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "daemon.sock")
+}
+
+// ServeDaemon listens on the project socket and serves requests until a stop
+// request arrives or stop is closed. Parsed files are memoized while it runs
+// (EnableParseCache). A stale socket left by a crashed daemon is
+// replaced; a live one is an error.
+func ServeDaemon(projectDir string, handler DaemonHandler, stop <-chan struct{}) error {
+	sock := DaemonSocketPath(projectDir)
+	if _, err := os.Stat(filepath.Dir(sock)); err != nil {
+		return fmt.Errorf("err:config .ptsd not found")
+	}
+	if DaemonRunning(projectDir) {
+		return fmt.Errorf("err:user daemon already running on %s", sock)
+	}
+	os.Remove(sock)
+
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	defer os.Remove(sock)
+	EnableParseCache(true)
+	defer EnableParseCache(false)
+
+	done := make(chan struct{})
+	var closeOnce sync.Once
+	shutdown := func() { closeOnce.Do(func() { close(done); ln.Close() }) }
+	go func() {
+		select {
+		case <-stop:
+			shutdown()
+		case <-done:
+		}
+	}()
+
+	var mu sync.Mutex
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			select {
+			case <-done:
+				return nil
+			default:
+				return fmt.Errorf("err:io %w", err)
+			}
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			var req DaemonRequest
+			if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+				json.NewEncoder(conn).Encode(DaemonResponse{Code: 2, Stderr: "err:user bad daemon request\n"})
+				return
+			}
+			var resp DaemonResponse
+			switch req.Cmd {
+			case "ping":
+				resp.Stdout = fmt.Sprintf("parse_cache_hits=%d\n", ParseCacheHits())
+			case "stop":
+				defer shutdown()
+			default:
+				mu.Lock()
+				resp = handler(req)
+				mu.Unlock()
+			}
+			json.NewEncoder(conn).Encode(resp)
+		}(conn)
+	}
+}
+
+// DaemonCall sends a request to the project daemon. ok is false when no daemon
+// is reachable, in which case the caller runs the command in-process.
+func DaemonCall(projectDir string, req DaemonRequest) (resp DaemonResponse, ok bool) {
+	sock := DaemonSocketPath(projectDir)
+	if _, err := os.Stat(sock); err != nil {
+		return resp, false
+	}
+	conn, err := net.DialTimeout("unix", sock, daemonDialTimeout)
+	if err != nil {
+		return resp, false
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return resp, false
+	}
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return resp, false
+	}
+	return resp, true
+}
+
+// DaemonRunning reports whether a daemon answers on the project socket.
+func DaemonRunning(projectDir string) bool {
+	_, ok := DaemonCall(projectDir, DaemonRequest{Cmd: "ping"})
+	return ok
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func startTestDaemon(t *testing.T, dir string, handler DaemonHandler) chan error {
+	t.Helper()
+	errc := make(chan error, 1)
+	go func() { errc <- ServeDaemon(dir, handler, nil) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !DaemonRunning(dir) {
+		if time.Now().After(deadline) {
+			t.Fatal("daemon did not start")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return errc
+}
+
+func TestDaemonServesRequestsAndStops(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	errc := startTestDaemon(t, dir, func(req DaemonRequest) DaemonResponse {
+		return DaemonResponse{Code: 2, Stderr: "blocked " + req.File}
+	})
+
+	resp, ok := DaemonCall(dir, DaemonRequest{Cmd: "gate-check", File: "src/a.go"})
+	if !ok {
+		t.Fatal("expected daemon to answer")
+	}
+	if resp.Code != 2 || resp.Stderr != "blocked src/a.go" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	if _, ok := DaemonCall(dir, DaemonRequest{Cmd: "stop"}); !ok {
+		t.Fatal("stop request failed")
+	}
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Errorf("ServeDaemon returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("daemon did not stop")
+	}
+
+	if _, err := os.Stat(DaemonSocketPath(dir)); !os.IsNotExist(err) {
+		t.Error("socket must be removed on shutdown")
+	}
+}
+
+func TestDaemonCallWithoutDaemon(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	if _, ok := DaemonCall(dir, DaemonRequest{Cmd: "ping"}); ok {
+		t.Error("expected no daemon")
+	}
+
+	// A stale socket file must not be mistaken for a live daemon.
+	os.WriteFile(DaemonSocketPath(dir), nil, 0644)
+	if DaemonRunning(dir) {
+		t.Error("stale socket reported as running")
+	}
+}
+
+func TestServeDaemonRejectsSecondInstance(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	startTestDaemon(t, dir, func(DaemonRequest) DaemonResponse { return DaemonResponse{} })
+	defer DaemonCall(dir, DaemonRequest{Cmd: "stop"})
+
+	if err := ServeDaemon(dir, nil, nil); err == nil {
+		t.Error("expected error for second daemon")
+	}
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DashboardCell is one feature × stage cell of the status dashboard.
+// State is done, current, blocked or pending, or "" when the stage is not in
+// the feature's pipeline (lite features skip seed and bdd). Score is the
+// latest review score for the stage, -1 when none was recorded.
+type DashboardCell struct {
+	State string
+	Score int
+}
+
+// DashboardRow is one feature of the status dashboard.
+type DashboardRow struct {
+	ID     string
+	Status string
+	Cells  []DashboardCell // parallel to Dashboard.Stages
+	// Passed and Total come from the last recorded test run; Total is 0
+	// when the feature's tests never ran.
+	Passed int
+	Total  int
+	// Review is the review-status verdict for the current stage.
+	Review string
+	// Pending counts TODO and WIP tasks.
+	Pending int
+}
+
+// Dashboard is the features × pipeline stages matrix behind
+// `ptsd status --tui` and `--format dashboard`.
+type Dashboard struct {
+	Stages []string
+	Rows   []DashboardRow
+}
+
+// BuildDashboard assembles the dashboard from features.yaml, state.yaml,
+// review-status.yaml and tasks.yaml, in registry order.
+func BuildDashboard(projectDir string) (Dashboard, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return Dashboard{}, err
+	}
+	state, err := LoadState(projectDir)
+	if err != nil {
+		return Dashboard{}, err
+	}
+	rs, err := loadReviewStatus(projectDir)
+	if err != nil {
+		return Dashboard{}, err
+	}
+	tasks, err := loadTasks(projectDir)
+	if err != nil {
+		return Dashboard{}, err
+	}
+	pending := make(map[string]int)
+	for _, t := range tasks {
+		if t.Status != "DONE" {
+			pending[t.Feature]++
+		}
+	}
+
+	d := Dashboard{Stages: PipelineStages(projectDir)}
+	for _, f := range features {
+		pipeline, err := FeaturePipeline(projectDir, f.ID)
+		if err != nil {
+			return Dashboard{}, err
+		}
+		states := make(map[string]string, len(pipeline))
+		for _, ps := range pipeline {
+			states[ps.Stage] = ps.State
+		}
+		fs := state.Features[f.ID]
+		row := DashboardRow{ID: f.ID, Status: f.Status, Review: rs[f.ID].Review, Pending: pending[f.ID]}
+		for _, s := range d.Stages {
+			cell := DashboardCell{State: states[s], Score: -1}
+			if sc, ok := fs.Scores[s]; ok {
+				cell.Score = sc.Value
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		row.Passed, row.Total = parseTestCounts(fs.Hashes["test_results"])
+		d.Rows = append(d.Rows, row)
+	}
+	return d, nil
+}
+
+// parseTestCounts reads a "passed:N failed:M" test_results hash.
+func parseTestCounts(s string) (passed, total int) {
+	var failed int
+	if _, err := fmt.Sscanf(s, "passed:%d failed:%d", &passed, &failed); err != nil {
+		return 0, 0
+	}
+	return passed, passed + failed
+}
+
+// DashboardStamp identifies the current contents of the files the dashboard
+// reads; the dashboard is rebuilt only when it changes.
+func DashboardStamp(projectDir string) string {
+	var b strings.Builder
+	for _, name := range []string{"features.yaml", "state.yaml", "review-status.yaml", "tasks.yaml", "ptsd.yaml"} {
+		info, err := os.Stat(filepath.Join(projectDir, ".ptsd", name))
+		if err != nil {
+			b.WriteString(name + ":-;")
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", name, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildDashboard(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:planned")
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte("features:\n"+
+		"  auth:\n    stage: tests\n    hashes:\n      test_results: passed:3 failed:1\n"+
+		"    scores:\n      bdd:\n        score: 8\n        at: \"2026-01-02T10:00:00Z\"\n"), 0644)
+	os.WriteFile(filepath.Join(ptsdDir, "review-status.yaml"), []byte("features:\n"+
+		"  auth:\n    stage: tests\n    tests: written\n    review: pending\n    issues: 0\n"), 0644)
+	setupTasks(t, dir,
+		Task{ID: "T-1", Feature: "auth", Title: "Login", Status: "WIP", Priority: "A"},
+		Task{ID: "T-2", Feature: "auth", Title: "Logout", Status: "DONE", Priority: "B"},
+		Task{ID: "T-3", Feature: "billing", Title: "Invoice", Status: "TODO", Priority: "B"})
+
+	d, err := BuildDashboard(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Rows) != 2 || d.Rows[0].ID != "auth" || d.Rows[1].ID != "billing" {
+		t.Fatalf("rows = %+v", d.Rows)
+	}
+	auth := d.Rows[0]
+	cells := map[string]DashboardCell{}
+	for i, s := range d.Stages {
+		cells[s] = auth.Cells[i]
+	}
+	if cells["bdd"].State != "done" || cells["bdd"].Score != 8 {
+		t.Errorf("bdd cell = %+v", cells["bdd"])
+	}
+	// No BDD file on disk, so the current stage is blocked.
+	if cells["tests"].State != "blocked" || cells["tests"].Score != -1 {
+		t.Errorf("tests cell = %+v", cells["tests"])
+	}
+	if cells["impl"].State != "pending" {
+		t.Errorf("impl cell = %+v", cells["impl"])
+	}
+	if auth.Passed != 3 || auth.Total != 4 || auth.Review != "pending" || auth.Pending != 1 {
+		t.Errorf("auth row = %+v", auth)
+	}
+	if billing := d.Rows[1]; billing.Total != 0 || billing.Pending != 1 {
+		t.Errorf("billing row = %+v", billing)
+	}
+}
+
+func TestDashboardStampChangesWithState(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:planned")
+	before := DashboardStamp(dir)
+	if DashboardStamp(dir) != before {
+		t.Fatal("stamp changed without a write")
+	}
+	os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte("features:\n  auth:\n    stage: bdd\n"), 0644)
+	if DashboardStamp(dir) == before {
+		t.Error("stamp did not change after state.yaml was written")
+	}
+}
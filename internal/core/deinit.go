@@ -0,0 +1,261 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DeinitOptions controls DeinitProject.
+type DeinitOptions struct {
+	// Backup archives .ptsd/ to .git/ptsd-deinit-<timestamp>.tar.gz first.
+	Backup bool
+	// Force also removes generated files the user has modified.
+	Force bool
+}
+
+// DeinitResult reports what DeinitProject did. Paths are project-relative.
+type DeinitResult struct {
+	Backup   string
+	Removed  []string
+	Restored []string // git hooks moved back from .ptsd-backup
+	Kept     []string // modified generated files left in place
+}
+
+// DeinitProject removes ptsd from a project: the managed CLAUDE.md section and
+// .gitignore block, generated .claude/ files and settings entries, ptsd git
+// hooks (restoring any hooks they replaced), ptsd's files under .git/, and
+// finally .ptsd/.
+func DeinitProject(dir string, opts DeinitOptions) (*DeinitResult, error) {
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	if _, err := os.Stat(ptsdDir); err != nil {
+		return nil, fmt.Errorf("err:config .ptsd not found")
+	}
+
+	res := &DeinitResult{}
+	gitDir := filepath.Join(dir, ".git")
+
+	if opts.Backup {
+		if err := os.MkdirAll(gitDir, 0755); err != nil {
+			return nil, fmt.Errorf("err:io %w", err)
+		}
+		dest := filepath.Join(gitDir, "ptsd-deinit-"+time.Now().UTC().Format("20060102-150405")+".tar.gz")
+		if err := archiveDir(ptsdDir, dest, nil); err != nil {
+			return nil, err
+		}
+		res.Backup, _ = filepath.Rel(dir, dest)
+	}
+
+	removed, err := stripClaudeMDSection(dir)
+	if err != nil {
+		return nil, err
+	}
+	if removed {
+		res.Removed = append(res.Removed, "CLAUDE.md")
+	}
+
+	if before, _, after, found, err := readGitignore(dir); err != nil {
+		return nil, err
+	} else if found {
+		if err := writeGitignore(dir, before, nil, after); err != nil {
+			return nil, err
+		}
+		res.Removed = append(res.Removed, ".gitignore#ptsd")
+	}
+
+	if err := removeClaudeFiles(dir, opts.Force, res); err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{"pre-commit", "commit-msg", "prepare-commit-msg", "pre-push"} {
+		if err := removeGitHook(dir, name, res); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range []string{"ptsd-validated", "ptsd-validate-cache"} {
+		if err := os.Remove(filepath.Join(gitDir, name)); err == nil {
+			res.Removed = append(res.Removed, ".git/"+name)
+		}
+	}
+
+	if err := os.RemoveAll(ptsdDir); err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	res.Removed = append(res.Removed, ".ptsd/")
+
+	return res, nil
+}
+
+// stripClaudeMDSection removes the marker-delimited ptsd section from
+// CLAUDE.md, deleting the file if nothing else is left. It reports whether
+// the file was deleted.
+func stripClaudeMDSection(dir string) (bool, error) {
+	path := filepath.Join(dir, "CLAUDE.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, nil
+	}
+	content := string(data)
+	first := strings.Index(content, ptsdMarker)
+	if first < 0 {
+		return false, nil
+	}
+	second := strings.Index(content[first+len(ptsdMarker):], ptsdMarker)
+	if second < 0 {
+		return false, nil
+	}
+	end := first + len(ptsdMarker) + second + len(ptsdMarker)
+	rest := strings.TrimRight(content[:first], "\n")
+	if tail := strings.TrimLeft(content[end:], "\n"); tail != "" {
+		if rest != "" {
+			rest += "\n\n"
+		}
+		rest += tail
+	}
+
+	if strings.TrimSpace(rest) == "" {
+		if err := os.Remove(path); err != nil {
+			return false, fmt.Errorf("err:io %w", err)
+		}
+		return true, nil
+	}
+	if !strings.HasSuffix(rest, "\n") {
+		rest += "\n"
+	}
+	return false, writeFile(path, rest)
+}
+
+// removeClaudeFiles deletes the generated .claude/ files recorded in the
+// manifest, strips ptsd hooks from a customized settings.json, and prunes
+// directories left empty.
+func removeClaudeFiles(dir string, force bool, res *DeinitResult) error {
+	m := loadGenManifest(dir)
+	var paths []string
+	for rel := range m.Hashes {
+		if strings.HasPrefix(rel, ".claude/") {
+			paths = append(paths, rel)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, rel := range paths {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if !force && contentHash(data) != m.Hashes[rel] {
+			if rel == ".claude/settings.json" {
+				if err := stripPtsdSettings(path, data); err != nil {
+					return err
+				}
+				continue
+			}
+			res.Kept = append(res.Kept, rel)
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("err:io %w", err)
+		}
+		res.Removed = append(res.Removed, rel)
+	}
+
+	// Prune empty directories, deepest first.
+	claudeDir := filepath.Join(dir, ".claude")
+	var dirs []string
+	filepath.WalkDir(claudeDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Remove(dirs[i]) // fails, harmlessly, when not empty
+	}
+	return nil
+}
+
+// stripPtsdSettings removes the hook entries pointing at .claude/hooks/ptsd-*
+// from a settings.json the user has edited, keeping everything else.
+func stripPtsdSettings(path string, data []byte) error {
+	var settings map[string]any
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("err:io cannot parse %s: %w", path, err)
+	}
+	events, _ := settings["hooks"].(map[string]any)
+	for event, v := range events {
+		groups, _ := v.([]any)
+		var keptGroups []any
+		for _, g := range groups {
+			group, ok := g.(map[string]any)
+			if !ok {
+				keptGroups = append(keptGroups, g)
+				continue
+			}
+			hooks, _ := group["hooks"].([]any)
+			var keptHooks []any
+			for _, h := range hooks {
+				hook, _ := h.(map[string]any)
+				cmd, _ := hook["command"].(string)
+				if strings.HasPrefix(filepath.Base(cmd), "ptsd-") && strings.Contains(filepath.ToSlash(cmd), ".claude/hooks/") {
+					continue
+				}
+				keptHooks = append(keptHooks, h)
+			}
+			if len(keptHooks) > 0 {
+				group["hooks"] = keptHooks
+				keptGroups = append(keptGroups, group)
+			}
+		}
+		if len(keptGroups) > 0 {
+			events[event] = keptGroups
+		} else {
+			delete(events, event)
+		}
+	}
+	if events != nil && len(events) == 0 {
+		delete(settings, "hooks")
+	}
+
+	if len(settings) == 0 {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("err:io %w", err)
+		}
+		return nil
+	}
+	out, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return writeFile(path, string(out)+"\n")
+}
+
+// removeGitHook deletes a ptsd git hook and restores the user's hook it replaced.
+func removeGitHook(dir, name string, res *DeinitResult) error {
+	hookPath := filepath.Join(dir, ".git", "hooks", name)
+	if data, err := os.ReadFile(hookPath); err == nil && isPtsdGitHook(string(data)) {
+		if err := os.Remove(hookPath); err != nil {
+			return fmt.Errorf("err:io %w", err)
+		}
+		res.Removed = append(res.Removed, ".git/hooks/"+name)
+	}
+
+	backup := hookPath + gitHookBackupSuffix
+	if _, err := os.Stat(backup); err != nil {
+		return nil
+	}
+	if _, err := os.Stat(hookPath); err == nil {
+		// Something else now lives at the hook path; leave both alone.
+		return nil
+	}
+	if err := os.Rename(backup, hookPath); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	res.Restored = append(res.Restored, ".git/hooks/"+name)
+	return nil
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeinitRemovesEverythingAndRestoresHooks(t *testing.T) {
+	dir := t.TempDir()
+	setupGitDir(t, dir)
+
+	// A user hook that init replaces and deinit must bring back.
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	os.MkdirAll(hooksDir, 0755)
+	userHook := "#!/bin/sh\nmake lint\n"
+	os.WriteFile(filepath.Join(hooksDir, "pre-commit"), []byte(userHook), 0755)
+	os.WriteFile(filepath.Join(dir, "CLAUDE.md"), []byte("# My notes\n\nKeep me.\n"), 0644)
+
+	initProject(t, dir, "MyApp")
+	if _, err := os.Stat(filepath.Join(hooksDir, "pre-commit"+gitHookBackupSuffix)); err != nil {
+		t.Fatalf("init should back up the user's hook: %v", err)
+	}
+	// Re-init must not clobber the backup with ptsd's own hook.
+	initProject(t, dir, "MyApp")
+
+	res, err := DeinitProject(dir, DeinitOptions{Backup: true})
+	if err != nil {
+		t.Fatalf("DeinitProject failed: %v", err)
+	}
+
+	for _, rel := range []string{".ptsd", ".claude", ".git/hooks/commit-msg", ".git/hooks/pre-commit" + gitHookBackupSuffix} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err == nil {
+			t.Errorf("%s should be gone", rel)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(hooksDir, "pre-commit"))
+	if string(data) != userHook {
+		t.Errorf("user hook not restored, got %q", data)
+	}
+	if len(res.Restored) != 1 || res.Restored[0] != ".git/hooks/pre-commit" {
+		t.Errorf("Restored = %v", res.Restored)
+	}
+
+	md, _ := os.ReadFile(filepath.Join(dir, "CLAUDE.md"))
+	if string(md) != "# My notes\n\nKeep me.\n" || strings.Contains(string(md), ptsdMarker) {
+		t.Errorf("CLAUDE.md should keep only user content, got %q", md)
+	}
+
+	if gi, _ := os.ReadFile(filepath.Join(dir, ".gitignore")); strings.Contains(string(gi), ignoreBlockStart) {
+		t.Errorf(".gitignore block should be stripped:\n%s", gi)
+	}
+
+	if res.Backup == "" {
+		t.Fatal("expected a backup path")
+	}
+	if _, err := os.Stat(filepath.Join(dir, res.Backup)); err != nil {
+		t.Errorf("backup missing: %v", err)
+	}
+}
+
+func TestDeinitKeepsUserSettingsAndModifiedFiles(t *testing.T) {
+	dir := t.TempDir()
+	setupGitDir(t, dir)
+	initProject(t, dir, "MyApp")
+
+	// User adds their own hook entry to the generated settings.json.
+	settingsPath := filepath.Join(dir, ".claude", "settings.json")
+	data, _ := os.ReadFile(settingsPath)
+	custom := strings.Replace(string(data), `"hooks": {`, `"hooks": {
+    "Stop": [{"hooks": [{"type": "command", "command": "notify-send done"}]}],`, 1)
+	os.WriteFile(settingsPath, []byte(custom), 0644)
+
+	// And edits a generated skill.
+	skill := filepath.Join(dir, ".claude", "skills", "write-prd", "SKILL.md")
+	os.WriteFile(skill, []byte("my prd skill\n"), 0644)
+
+	res, err := DeinitProject(dir, DeinitOptions{})
+	if err != nil {
+		t.Fatalf("DeinitProject failed: %v", err)
+	}
+
+	if len(res.Kept) != 1 || res.Kept[0] != ".claude/skills/write-prd/SKILL.md" {
+		t.Errorf("Kept = %v", res.Kept)
+	}
+	if _, err := os.Stat(skill); err != nil {
+		t.Error("modified skill should be kept without --force")
+	}
+
+	out, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatalf("customized settings.json should be kept: %v", err)
+	}
+	if strings.Contains(string(out), "ptsd-") {
+		t.Errorf("ptsd hooks should be stripped from settings.json:\n%s", out)
+	}
+	if !strings.Contains(string(out), "notify-send done") {
+		t.Errorf("user hook should survive:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "CLAUDE.md")); err == nil {
+		t.Error("CLAUDE.md holding only the ptsd section should be deleted")
+	}
+	if res.Backup != "" {
+		t.Error("no backup was requested")
+	}
+}
+
+func TestDeinitRequiresProject(t *testing.T) {
+	_, err := DeinitProject(t.TempDir(), DeinitOptions{})
+	if err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Fatalf("expected err:config, got %v", err)
+	}
+}
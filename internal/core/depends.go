@@ -0,0 +1,195 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Features declare layering through `depends_on: [a, b]` in features.yaml.
+// Up to BDD a feature proceeds on its own; it may not move past BDD (tests,
+// impl and any custom stage after bdd) until every dependency is
+// implemented. Validate, TaskNext, gate-check, context and auto-track all
+// apply the same rule through dependencyGate.
+
+// unmetDependencies returns the dependencies of id that are not implemented,
+// each as "dep (status)"; an unregistered dependency is "dep (unknown)".
+func unmetDependencies(features []Feature, id string) []string {
+	status := make(map[string]string, len(features))
+	var deps []string
+	for _, f := range features {
+		status[f.ID] = f.Status
+		if f.ID == id {
+			deps = f.DependsOn
+		}
+	}
+	var unmet []string
+	for _, d := range deps {
+		s, ok := status[d]
+		if !ok {
+			s = "unknown"
+		}
+		if s != "implemented" {
+			unmet = append(unmet, d+" ("+s+")")
+		}
+	}
+	return unmet
+}
+
+// pastBDD reports whether stage comes after bdd in the project's pipeline.
+func pastBDD(projectDir, stage string) bool {
+	stages := PipelineStages(projectDir)
+	return stageRank(stages, stage) > stageRank(stages, "bdd")
+}
+
+// dependencyGate blocks work on a stage past BDD while any of the feature's
+// dependencies is not implemented.
+func dependencyGate(projectDir, featureID, stage string) (blocked bool, reason string) {
+	if !pastBDD(projectDir, stage) {
+		return false, ""
+	}
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return false, ""
+	}
+	if unmet := unmetDependencies(features, featureID); len(unmet) > 0 {
+		return true, featureID + " depends on " + strings.Join(unmet, ", ") + " — implement dependencies before " + stage
+	}
+	return false, ""
+}
+
+// waitingOnDependencies returns the features with at least one dependency
+// that is not implemented. Their tasks are held back by TaskNext.
+func waitingOnDependencies(projectDir string) map[string]bool {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil
+	}
+	waiting := make(map[string]bool)
+	for _, f := range features {
+		if len(unmetDependencies(features, f.ID)) > 0 {
+			waiting[f.ID] = true
+		}
+	}
+	return waiting
+}
+
+// SetFeatureDependencies replaces the dependencies of id; an empty list (or
+// "none") clears them.
+func SetFeatureDependencies(projectDir, id string, deps []string) error {
+	if len(deps) == 1 && deps[0] == "none" {
+		deps = nil
+	}
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return err
+	}
+	idx := -1
+	known := make(map[string]bool, len(features))
+	for i, f := range features {
+		known[f.ID] = true
+		if f.ID == id {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("err:validation feature %s not found", id)
+	}
+	seen := make(map[string]bool)
+	var clean []string
+	for _, d := range deps {
+		switch {
+		case d == id:
+			return fmt.Errorf("err:validation feature %s cannot depend on itself", id)
+		case !known[d]:
+			return fmt.Errorf("err:validation dependency %s not found", d)
+		case !seen[d]:
+			seen[d] = true
+			clean = append(clean, d)
+		}
+	}
+	features[idx].DependsOn = clean
+	if cycle := dependencyCycle(features, id); cycle != nil {
+		return fmt.Errorf("err:validation dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return saveFeatures(projectDir, features)
+}
+
+// dependencyCycle returns a depends_on cycle through start, as the path from
+// start back to itself, or nil.
+func dependencyCycle(features []Feature, start string) []string {
+	deps := make(map[string][]string, len(features))
+	for _, f := range features {
+		deps[f.ID] = f.DependsOn
+	}
+	visited := make(map[string]bool)
+	var path []string
+	var walk func(id string) bool
+	walk = func(id string) bool {
+		path = append(path, id)
+		for _, d := range deps[id] {
+			if d == start {
+				path = append(path, d)
+				return true
+			}
+			if !visited[d] {
+				visited[d] = true
+				if walk(d) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	if walk(start) {
+		return path
+	}
+	return nil
+}
+
+// checkDependencies reports unknown dependencies, cycles, and active
+// features past BDD (by review-status stage) whose dependencies are not
+// implemented.
+func checkDependencies(projectDir string, features []Feature, reviewStatus map[string]ReviewStatusEntry) []ValidationError {
+	known := make(map[string]bool, len(features))
+	for _, f := range features {
+		known[f.ID] = true
+	}
+	var errs []ValidationError
+	inCycle := make(map[string]bool)
+	for _, f := range features {
+		if f.Status == "planned" || f.Status == "deferred" {
+			continue
+		}
+		for _, d := range f.DependsOn {
+			if !known[d] {
+				errs = append(errs, ValidationError{Feature: f.ID, Category: "pipeline", Rule: "depends-on",
+					Message: "depends on unknown feature " + d})
+			}
+		}
+		if !inCycle[f.ID] {
+			if cycle := dependencyCycle(features, f.ID); cycle != nil {
+				for _, id := range cycle {
+					inCycle[id] = true
+				}
+				errs = append(errs, ValidationError{Feature: f.ID, Category: "pipeline", Rule: "depends-on",
+					Message: "dependency cycle: " + strings.Join(cycle, " -> ")})
+			}
+		}
+		stage := reviewStatus[f.ID].Stage
+		if !pastBDD(projectDir, stage) {
+			continue
+		}
+		var unmet []string
+		for _, u := range unmetDependencies(features, f.ID) {
+			if !strings.HasSuffix(u, " (unknown)") {
+				unmet = append(unmet, u)
+			}
+		}
+		if len(unmet) > 0 {
+			errs = append(errs, ValidationError{Feature: f.ID, Category: "pipeline", Rule: "depends-on",
+				Message: "at stage " + stage + " but depends on " + strings.Join(unmet, ", ") + ", not implemented"})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupDependsProject registers orders depending on auth; auth is still in
+// progress. orders has BDD and sits at the tests stage.
+func setupDependsProject(t *testing.T) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t)
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	features := "features:\n" +
+		"  - id: auth\n    title: Auth\n    status: in-progress\n" +
+		"  - id: orders\n    title: Orders\n    status: in-progress\n    depends_on: [auth]\n"
+	if err := os.WriteFile(filepath.Join(ptsdDir, "features.yaml"), []byte(features), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(ptsdDir, "bdd"), 0755)
+	os.WriteFile(filepath.Join(ptsdDir, "bdd", "orders.feature"), []byte("@feature:orders\nFeature: Orders\n"), 0644)
+	os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte("features:\n  orders:\n    stage: impl\n    hashes:\n    scores:\n"), 0644)
+	os.WriteFile(filepath.Join(ptsdDir, "review-status.yaml"), []byte("features:\n  orders:\n    stage: tests\n    tests: absent\n    review: pending\n    issues: 0\n"), 0644)
+	return dir
+}
+
+func TestLoadFeaturesDependsOn(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	features := "features:\n" +
+		"  - id: a\n    title: A\n    status: planned\n    depends_on: [b, \"c\"]\n" +
+		"  - id: b\n    title: B\n    status: planned\n    depends_on:\n      - c\n" +
+		"  - id: c\n    title: C\n    status: planned\n"
+	os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte(features), 0644)
+
+	got, err := loadFeatures(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got[0].DependsOn, ",") != "b,c" || strings.Join(got[1].DependsOn, ",") != "c" || got[1].Status != "planned" {
+		t.Errorf("features = %+v", got)
+	}
+
+	// Round trip writes the flow form.
+	if err := saveFeatures(dir, got); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "features.yaml"))
+	if !strings.Contains(string(data), "    depends_on: [b, c]\n") {
+		t.Errorf("features.yaml:\n%s", data)
+	}
+}
+
+func TestDependencyGateHoldsFeatureAtBDD(t *testing.T) {
+	dir := setupDependsProject(t)
+
+	if blocked, _ := dependencyGate(dir, "orders", "bdd"); blocked {
+		t.Error("BDD work must not wait on dependencies")
+	}
+	blocked, reason := dependencyGate(dir, "orders", "tests")
+	if !blocked || !strings.Contains(reason, "auth (in-progress)") {
+		t.Errorf("blocked=%v reason=%q, want blocked on auth", blocked, reason)
+	}
+
+	if r := GateCheck(dir, "internal/orders_test.go"); r.Allowed || !strings.Contains(r.Reason, "depends on auth") {
+		t.Errorf("gate-check = %+v, want test write blocked", r)
+	}
+
+	features, _ := loadFeatures(dir)
+	features[0].Status = "implemented"
+	if err := saveFeatures(dir, features); err != nil {
+		t.Fatal(err)
+	}
+	if r := GateCheck(dir, "internal/orders_test.go"); !r.Allowed {
+		t.Errorf("gate-check blocked after auth was implemented: %s", r.Reason)
+	}
+}
+
+func TestTaskNextSkipsFeaturesWaitingOnDependencies(t *testing.T) {
+	dir := setupDependsProject(t)
+	setupTasks(t, dir,
+		Task{ID: "T-1", Feature: "orders", Title: "checkout", Status: "TODO", Priority: "A"},
+		Task{ID: "T-2", Feature: "", Title: "chore", Status: "TODO", Priority: "B"},
+	)
+
+	tasks, err := TaskNext(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "T-2" {
+		t.Errorf("tasks = %+v, want only T-2", tasks)
+	}
+}
+
+func TestValidateReportsDependencies(t *testing.T) {
+	dir := setupDependsProject(t)
+	features := "features:\n" +
+		"  - id: auth\n    title: Auth\n    status: in-progress\n    depends_on: [orders]\n" +
+		"  - id: orders\n    title: Orders\n    status: in-progress\n    depends_on: [auth, ghost]\n"
+	os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte(features), 0644)
+
+	errs, err := Validate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []string
+	for _, e := range errs {
+		if e.Rule == "depends-on" {
+			msgs = append(msgs, e.Feature+": "+e.Message)
+		}
+	}
+	want := []string{
+		"auth: dependency cycle: auth -> orders -> auth",
+		"orders: depends on unknown feature ghost",
+		"orders: at stage tests but depends on auth (in-progress), not implemented",
+	}
+	if strings.Join(msgs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("depends-on errors:\n%s\nwant:\n%s", strings.Join(msgs, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSetFeatureDependencies(t *testing.T) {
+	dir := setupDependsProject(t)
+
+	if err := SetFeatureDependencies(dir, "auth", []string{"orders"}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("err = %v, want a cycle error", err)
+	}
+	if err := SetFeatureDependencies(dir, "auth", []string{"ghost"}); err == nil {
+		t.Error("expected error for an unknown dependency")
+	}
+	if err := SetFeatureDependencies(dir, "orders", []string{"none"}); err != nil {
+		t.Fatal(err)
+	}
+	features, _ := loadFeatures(dir)
+	if len(features[1].DependsOn) != 0 {
+		t.Errorf("depends_on not cleared: %+v", features[1])
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RunnerProposal compares the configured test runner and file patterns with
+// what detection finds in the project today.
+type RunnerProposal struct {
+	CurrentRunner   string
+	CurrentPatterns []string
+	Runner          string
+	Patterns        []string
+}
+
+// Changed reports whether applying the proposal would modify ptsd.yaml.
+func (p RunnerProposal) Changed() bool {
+	return p.Runner != p.CurrentRunner || !slices.Equal(p.Patterns, p.CurrentPatterns)
+}
+
+// defaultTestPatterns returns the testing.patterns.files ptsd proposes for a
+// detected runner, or nil when it has no opinion.
+func defaultTestPatterns(runner string) []string {
+	switch runner {
+	case "npx vitest run", "npx jest":
+		return []string{"**/*.test.ts", "**/*.test.tsx", "**/*.test.js", "**/*.spec.ts", "**/*.spec.js"}
+	case "go test ./...", "go test -json ./...":
+		return []string{"**/*_test.go"}
+	case "pytest":
+		return []string{"tests/**/*.py", "**/*_test.py"}
+	}
+	return nil
+}
+
+// DetectRunner re-runs test runner detection against the current config.
+// Patterns are only proposed for replacement when they are still the defaults
+// for the configured runner (or the init template's); hand-written patterns
+// are kept.
+func DetectRunner(projectDir string) (RunnerProposal, error) {
+	cfg, err := LoadConfig(projectDir)
+	if err != nil {
+		return RunnerProposal{}, err
+	}
+	runner := detectTestRunner(projectDir)
+	if runner == "" {
+		return RunnerProposal{}, fmt.Errorf("err:config no test runner detected (looked for package.json, go.mod, pytest.ini, pyproject.toml)")
+	}
+
+	p := RunnerProposal{
+		CurrentRunner:   cfg.Testing.Runner,
+		CurrentPatterns: cfg.Testing.Patterns.Files,
+		Runner:          runner,
+		Patterns:        cfg.Testing.Patterns.Files,
+	}
+	stock := slices.Equal(p.CurrentPatterns, []string{"**/*_test.go"}) ||
+		slices.Equal(p.CurrentPatterns, defaultTestPatterns(p.CurrentRunner))
+	if defaults := defaultTestPatterns(runner); stock && defaults != nil {
+		p.Patterns = defaults
+	}
+	return p, nil
+}
+
+// ApplyRunner writes the proposal's runner and patterns to ptsd.yaml.
+func ApplyRunner(projectDir string, p RunnerProposal) error {
+	if err := setConfigValue(projectDir, "testing.runner", strconv.Quote(p.Runner)); err != nil {
+		return err
+	}
+	quoted := make([]string, len(p.Patterns))
+	for i, pattern := range p.Patterns {
+		quoted[i] = strconv.Quote(pattern)
+	}
+	return setConfigValue(projectDir, "testing.patterns.files", "["+strings.Join(quoted, ", ")+"]")
+}
@@ -0,0 +1,309 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DoctorCheck is one result of Doctor. Fix is a command or edit that resolves
+// a warn or fail; it is empty for ok.
+type DoctorCheck struct {
+	Check   string // git | hooks | config | schema | gates | runner | registry | prd | claude
+	Status  string // ok | warn | fail
+	Message string
+	Fix     string
+}
+
+// Doctor checks the environment and the project's wiring: git, the git hooks
+// and the binary they run, ptsd.yaml and the gates mode, the schema version
+// of the .ptsd files, the test runner,
+// registry consistency and the .claude/settings.json hooks. Checks that do
+// not apply to the project's init profile are skipped.
+func Doctor(projectDir string) ([]DoctorCheck, error) {
+	if _, err := os.Stat(filepath.Join(projectDir, ".ptsd")); err != nil {
+		return nil, fmt.Errorf("err:config .ptsd not found: run ptsd init")
+	}
+
+	var checks []DoctorCheck
+	checks = append(checks, doctorGit(projectDir)...)
+
+	cfg, cfgErr := LoadConfig(projectDir)
+	if cfgErr != nil {
+		checks = append(checks, DoctorCheck{Check: "config", Status: "fail",
+			Message: strings.TrimPrefix(cfgErr.Error(), "err:config "),
+			Fix:     "fix .ptsd/ptsd.yaml, or regenerate it with ptsd init --force"})
+	} else {
+		checks = append(checks, DoctorCheck{Check: "config", Status: "ok", Message: "ptsd.yaml parses"})
+		if cfg.Gates.Mode == GatesShadow {
+			checks = append(checks, DoctorCheck{Check: "gates", Status: "warn",
+				Message: "gates.mode is shadow: PreToolUse logs would-be blocks but never blocks",
+				Fix:     "set gates.mode: enforce in .ptsd/ptsd.yaml once the shadow: lines in ptsd context look right"})
+		}
+	}
+
+	checks = append(checks, doctorSchema(projectDir))
+
+	profile := ProfileFull
+	if cfg != nil && cfg.Project.Profile != "" {
+		profile = cfg.Project.Profile
+	}
+	if profile != ProfileMinimal {
+		checks = append(checks, doctorGitHooks(projectDir, cfg)...)
+	}
+	if cfg != nil {
+		checks = append(checks, doctorRunner(projectDir, cfg.Testing.Runner))
+	}
+	checks = append(checks, doctorRegistry(projectDir)...)
+	for _, big := range OversizedPRDFiles(projectDir) {
+		checks = append(checks, DoctorCheck{Check: "prd", Status: "warn",
+			Message: fmt.Sprintf("%s is %dKB, over prd.max_kb (%dKB)", big.File, big.KB, big.MaxKB),
+			Fix:     big.Suggestion()})
+	}
+	if profile == ProfileFull {
+		checks = append(checks, doctorClaude(projectDir)...)
+	}
+	return checks, nil
+}
+
+// doctorSchema checks ptsd.yaml, state.yaml and tasks.yaml are at
+// SchemaVersion: older files still load but should be migrated, newer ones
+// need a newer ptsd.
+func doctorSchema(projectDir string) DoctorCheck {
+	files, err := CheckSchema(projectDir)
+	if err != nil {
+		return DoctorCheck{Check: "schema", Status: "fail", Message: strings.TrimPrefix(err.Error(), "err:io "), Fix: "check permissions on .ptsd/"}
+	}
+	var older, newer []string
+	for _, f := range files {
+		switch {
+		case f.Version > SchemaVersion:
+			newer = append(newer, fmt.Sprintf("%s (schema %d)", f.File, f.Version))
+		case f.Outdated:
+			older = append(older, fmt.Sprintf("%s (schema %d)", f.File, f.Version))
+		}
+	}
+	switch {
+	case len(newer) > 0:
+		return DoctorCheck{Check: "schema", Status: "fail",
+			Message: fmt.Sprintf("written by a newer ptsd (this one reads schema %d): %s", SchemaVersion, strings.Join(newer, ", ")),
+			Fix:     "upgrade ptsd"}
+	case len(older) > 0:
+		return DoctorCheck{Check: "schema", Status: "warn",
+			Message: fmt.Sprintf("older layout, upgraded in memory on every load: %s", strings.Join(older, ", ")),
+			Fix:     "ptsd migrate"}
+	}
+	return DoctorCheck{Check: "schema", Status: "ok", Message: fmt.Sprintf("schema %d", SchemaVersion)}
+}
+
+func doctorGit(projectDir string) []DoctorCheck {
+	if _, err := exec.LookPath("git"); err != nil {
+		return []DoctorCheck{{Check: "git", Status: "fail", Message: "git not found on PATH", Fix: "install git"}}
+	}
+	if _, err := gitOutput(projectDir, "rev-parse", "--absolute-git-dir"); err != nil {
+		return []DoctorCheck{{Check: "git", Status: "fail", Message: "not a git repository", Fix: "git init && ptsd hooks install"}}
+	}
+	return []DoctorCheck{{Check: "git", Status: "ok", Message: "git repository"}}
+}
+
+// doctorGitHooks checks that each ptsd git hook exists, is managed by ptsd
+// and runs an executable ptsd binary, preferably the one running now.
+func doctorGitHooks(projectDir string, cfg *Config) []DoctorCheck {
+	gitDir, err := gitOutput(projectDir, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil
+	}
+	names := []string{"pre-commit", "commit-msg", "prepare-commit-msg"}
+	if cfg != nil && cfg.Hooks.PrePush {
+		names = append(names, "pre-push")
+	}
+
+	var checks []DoctorCheck
+	for _, name := range names {
+		path := filepath.Join(gitDir, "hooks", name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			checks = append(checks, DoctorCheck{Check: "hooks", Status: "fail", Message: name + " hook not installed", Fix: "ptsd hooks install"})
+			continue
+		}
+		if !isPtsdGitHook(string(data)) {
+			checks = append(checks, DoctorCheck{Check: "hooks", Status: "fail", Message: name + " hook is not managed by ptsd", Fix: "ptsd hooks install (the current hook is kept as " + name + gitHookBackupSuffix + ")"})
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.Mode()&0111 == 0 {
+			checks = append(checks, DoctorCheck{Check: "hooks", Status: "fail", Message: name + " hook is not executable", Fix: "chmod +x " + path})
+			continue
+		}
+		checks = append(checks, doctorHookBinary("hooks", name+" hook", hookBinary(string(data)), "ptsd hooks install"))
+	}
+	return checks
+}
+
+// hookBinary returns the program a generated hook script runs: its PTSD_BIN
+// assignment, or for hooks written before that existed, the first word of
+// its first command line.
+func hookBinary(script string) string {
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if bin, ok := strings.CutPrefix(line, "PTSD_BIN="); ok {
+			return strings.Trim(bin, `'"`)
+		}
+		if fields := strings.Fields(line); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// doctorHookBinary checks that bin, the ptsd binary a hook runs, exists and
+// is the binary running now.
+func doctorHookBinary(check, what, bin, fix string) DoctorCheck {
+	if bin == "" {
+		return DoctorCheck{Check: check, Status: "fail", Message: what + " runs no command", Fix: fix}
+	}
+	resolved := bin
+	if !filepath.IsAbs(bin) {
+		p, err := exec.LookPath(bin)
+		if err != nil {
+			return DoctorCheck{Check: check, Status: "fail", Message: what + " runs " + bin + ", which is not on PATH", Fix: fix}
+		}
+		resolved = p
+	} else if _, err := os.Stat(bin); err != nil {
+		return DoctorCheck{Check: check, Status: "fail", Message: what + " runs missing binary " + bin, Fix: fix}
+	}
+	if self := ptsdBinaryPath(); filepath.IsAbs(self) && !sameFile(resolved, self) {
+		return DoctorCheck{Check: check, Status: "warn", Message: what + " runs " + resolved + ", not this binary (" + self + ")", Fix: fix}
+	}
+	return DoctorCheck{Check: check, Status: "ok", Message: what + " runs " + resolved}
+}
+
+func sameFile(a, b string) bool {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	ib, err := os.Stat(b)
+	return err == nil && os.SameFile(ia, ib)
+}
+
+// doctorRunner checks that the configured test runner's program can be found.
+func doctorRunner(projectDir, runner string) DoctorCheck {
+	fields := strings.Fields(runner)
+	if len(fields) == 0 {
+		return DoctorCheck{Check: "runner", Status: "warn", Message: "testing.runner is not set", Fix: "set testing.runner in .ptsd/ptsd.yaml (ptsd adopt detects it)"}
+	}
+	prog := fields[0]
+	if strings.Contains(prog, "/") && !filepath.IsAbs(prog) {
+		prog = filepath.Join(projectDir, prog)
+	}
+	if _, err := exec.LookPath(prog); err != nil {
+		return DoctorCheck{Check: "runner", Status: "fail", Message: fields[0] + " (testing.runner) is not executable or not on PATH", Fix: "install " + fields[0] + " or change testing.runner in .ptsd/ptsd.yaml"}
+	}
+	return DoctorCheck{Check: "runner", Status: "ok", Message: "runner " + runner}
+}
+
+// doctorRegistry reports the fsck findings: features.yaml against state,
+// review-status and tasks.
+func doctorRegistry(projectDir string) []DoctorCheck {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return []DoctorCheck{{Check: "registry", Status: "fail", Message: "cannot read features.yaml", Fix: "fix the YAML in .ptsd/features.yaml"}}
+	}
+	var checks []DoctorCheck
+	for _, f := range fsck(projectDir, features) {
+		c := DoctorCheck{Check: "registry", Status: "warn", Message: f.Message}
+		if f.Feature != "" {
+			c.Message = f.Feature + ": " + f.Message
+		}
+		if f.Severity == "error" {
+			c.Status = "fail"
+		}
+		switch {
+		case strings.HasPrefix(f.Message, "duplicate"):
+			c.Fix = "remove the duplicate entry from .ptsd/features.yaml"
+		case strings.HasPrefix(f.Message, "unknown status"):
+			c.Fix = "ptsd feature status " + f.Feature + " <planned|in-progress|implemented|deferred>"
+		case strings.HasPrefix(f.Message, "task "):
+			c.Fix = "register the feature with ptsd feature add " + f.Feature + ", or move the task"
+		default:
+			c.Fix = "ptsd feature add " + f.Feature + ", or remove the stale entry"
+		}
+		checks = append(checks, c)
+	}
+	if len(checks) == 0 {
+		checks = append(checks, DoctorCheck{Check: "registry", Status: "ok", Message: fmt.Sprintf("%d feature(s), state consistent", len(features))})
+	}
+	return checks
+}
+
+// claudeHookEvents are the .claude/settings.json events ptsd wires, with the
+// hook script each one runs.
+var claudeHookEvents = []struct{ event, script string }{
+	{"SessionStart", "ptsd-context.sh"},
+	{"UserPromptSubmit", "ptsd-context.sh"},
+	{"PreToolUse", "ptsd-gate.sh"},
+	{"PostToolUse", "ptsd-track.sh"},
+}
+
+// doctorClaude checks .claude/settings.json wires every ptsd hook event to an
+// existing, executable script that runs a ptsd binary.
+func doctorClaude(projectDir string) []DoctorCheck {
+	const fix = "ptsd init --force"
+	settingsPath := filepath.Join(projectDir, ".claude", "settings.json")
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		return []DoctorCheck{{Check: "claude", Status: "fail", Message: ".claude/settings.json not found", Fix: fix}}
+	}
+	var settings struct {
+		Hooks map[string][]struct {
+			Hooks []struct {
+				Command string `json:"command"`
+			} `json:"hooks"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return []DoctorCheck{{Check: "claude", Status: "fail", Message: ".claude/settings.json is not valid JSON: " + err.Error(), Fix: "fix the JSON, or regenerate with " + fix}}
+	}
+
+	var checks []DoctorCheck
+	scripts := make(map[string]bool)
+	for _, ev := range claudeHookEvents {
+		command := ""
+		for _, group := range settings.Hooks[ev.event] {
+			for _, h := range group.Hooks {
+				if strings.HasSuffix(strings.TrimSpace(h.Command), ev.script) {
+					command = strings.TrimSpace(h.Command)
+				}
+			}
+		}
+		if command == "" {
+			checks = append(checks, DoctorCheck{Check: "claude", Status: "fail", Message: ev.event + " does not run " + ev.script, Fix: fix})
+			continue
+		}
+		if scripts[command] {
+			continue
+		}
+		scripts[command] = true
+		path := command
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectDir, path)
+		}
+		script, err := os.ReadFile(path)
+		if err != nil {
+			checks = append(checks, DoctorCheck{Check: "claude", Status: "fail", Message: ev.event + " runs missing script " + command, Fix: fix})
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.Mode()&0111 == 0 {
+			checks = append(checks, DoctorCheck{Check: "claude", Status: "fail", Message: ev.script + " is not executable", Fix: "chmod +x " + path})
+			continue
+		}
+		checks = append(checks, doctorHookBinary("claude", ev.script, hookBinary(string(script)), fix))
+	}
+	return checks
+}
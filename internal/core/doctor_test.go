@@ -0,0 +1,96 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func doctorStatus(checks []DoctorCheck, check, contains string) string {
+	for _, c := range checks {
+		if c.Check == check && strings.Contains(c.Message, contains) {
+			return c.Status
+		}
+	}
+	return ""
+}
+
+func TestDoctor(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	if _, err := Doctor(t.TempDir()); err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Fatalf("expected err:config without .ptsd, got %v", err)
+	}
+
+	checks, err := Doctor(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := doctorStatus(checks, "git", "not a git repository"); s != "fail" {
+		t.Errorf("git outside a repository = %q, want fail", s)
+	}
+	if s := doctorStatus(checks, "config", ""); s != "fail" {
+		t.Errorf("missing ptsd.yaml = %q, want fail", s)
+	}
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("project:\n  name: demo\n"), 0644)
+	checks, _ = Doctor(dir)
+	if s := doctorStatus(checks, "runner", "not set"); s != "warn" {
+		t.Errorf("unset runner = %q, want warn", s)
+	}
+	if s := doctorStatus(checks, "claude", "settings.json not found"); s != "fail" {
+		t.Errorf("missing settings.json = %q, want fail", s)
+	}
+
+	if out, err := exec.Command("git", "-C", dir, "init").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	if err := GeneratePreCommitHook(dir); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, ".git", "hooks", "commit-msg"), []byte("#!/bin/sh\nexit 0\n"), 0755)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("testing:\n  runner: no-such-runner-xyz --run\ngates:\n  mode: shadow\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte("features:\n  ghost:\n    stage: prd\n"), 0644)
+
+	checks, err = Doctor(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := doctorStatus(checks, "hooks", "pre-commit hook runs"); s != "ok" {
+		t.Errorf("pre-commit hook = %q, want ok: %+v", s, checks)
+	}
+	if s := doctorStatus(checks, "hooks", "commit-msg hook is not managed"); s != "fail" {
+		t.Errorf("foreign commit-msg hook = %q, want fail", s)
+	}
+	if s := doctorStatus(checks, "runner", "no-such-runner-xyz"); s != "fail" {
+		t.Errorf("missing runner = %q, want fail", s)
+	}
+	if s := doctorStatus(checks, "gates", "shadow"); s != "warn" {
+		t.Errorf("shadow gates = %q, want warn", s)
+	}
+	if s := doctorStatus(checks, "registry", "ghost: state.yaml entry"); s != "warn" {
+		t.Errorf("stale state entry = %q, want warn", s)
+	}
+	for _, c := range checks {
+		if c.Status != "ok" && c.Fix == "" {
+			t.Errorf("%s check %q has no fix", c.Check, c.Message)
+		}
+	}
+}
+
+func TestDoctorClaudeSettings(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	m := loadGenManifest(dir)
+	if err := generateClaudeHooks(dir, m); err != nil {
+		t.Fatal(err)
+	}
+	if checks := doctorClaude(dir); len(checks) != 3 || checks[0].Status != "ok" {
+		t.Fatalf("expected 3 ok script checks, got %+v", checks)
+	}
+
+	os.WriteFile(filepath.Join(dir, ".claude", "settings.json"), []byte(`{"hooks": {}}`), 0644)
+	checks := doctorClaude(dir)
+	if len(checks) != 4 || checks[2].Message != "PreToolUse does not run ptsd-gate.sh" {
+		t.Errorf("expected every event unwired, got %+v", checks)
+	}
+}
@@ -0,0 +1,202 @@
+package core
+
+import "fmt"
+
+// Features form a hierarchy through `parent:` in features.yaml. A feature
+// with children is an epic: its status, coverage and progress are rolled up
+// from its descendants, and it cannot be marked implemented before they are.
+
+// EpicRollup aggregates an epic and all of its descendants.
+type EpicRollup struct {
+	ID string
+	// Status is derived from the descendants: implemented when all of them
+	// are, in-progress once any has started, planned otherwise.
+	Status      string
+	Children    []Feature // direct children, in registry order
+	Descendants int
+	Implemented int
+	// TestsPassing counts descendants whose last test run passed.
+	TestsPassing int
+	// Criteria, Covered and Scenarios sum PRD acceptance criteria, covered
+	// criteria and BDD scenarios over the epic and its descendants.
+	Criteria  int
+	Covered   int
+	Scenarios int
+	// Tasks counts tasks by status over the epic and its descendants.
+	Tasks map[string]int
+}
+
+// EpicRollups returns a rollup for every feature that has children, in
+// registry order.
+func EpicRollups(projectDir string) ([]EpicRollup, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var parents []string
+	for _, f := range features {
+		if len(featureChildren(features, f.ID)) > 0 {
+			parents = append(parents, f.ID)
+		}
+	}
+	if len(parents) == 0 {
+		return nil, nil
+	}
+
+	overviews, err := FeatureOverviews(projectDir, "")
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]FeatureOverview, len(overviews))
+	for _, o := range overviews {
+		byID[o.ID] = o
+	}
+
+	var out []EpicRollup
+	for _, id := range parents {
+		out = append(out, buildRollup(features, byID, id))
+	}
+	return out, nil
+}
+
+// FeatureRollup returns the rollup for id; ok is false when it has no children.
+func FeatureRollup(projectDir, id string) (EpicRollup, bool, error) {
+	rollups, err := EpicRollups(projectDir)
+	if err != nil {
+		return EpicRollup{}, false, err
+	}
+	for _, r := range rollups {
+		if r.ID == id {
+			return r, true, nil
+		}
+	}
+	return EpicRollup{}, false, nil
+}
+
+func buildRollup(features []Feature, byID map[string]FeatureOverview, id string) EpicRollup {
+	r := EpicRollup{ID: id, Children: featureChildren(features, id), Tasks: make(map[string]int)}
+	add := func(o FeatureOverview) {
+		r.Criteria += o.Criteria
+		r.Covered += o.Covered
+		r.Scenarios += o.Scenarios
+		for status, n := range o.Tasks {
+			r.Tasks[status] += n
+		}
+	}
+	add(byID[id])
+
+	started := false
+	for _, d := range featureDescendants(features, id) {
+		o := byID[d.ID]
+		add(o)
+		r.Descendants++
+		switch d.Status {
+		case "implemented":
+			r.Implemented++
+			started = true
+		case "in-progress":
+			started = true
+		}
+		if o.Hashes["test_status"] == "passing" {
+			r.TestsPassing++
+		}
+	}
+	switch {
+	case r.Implemented == r.Descendants:
+		r.Status = "implemented"
+	case started:
+		r.Status = "in-progress"
+	default:
+		r.Status = "planned"
+	}
+	return r
+}
+
+// featureChildren returns the features whose parent is id.
+func featureChildren(features []Feature, id string) []Feature {
+	var children []Feature
+	for _, f := range features {
+		if f.Parent == id && f.ID != id {
+			children = append(children, f)
+		}
+	}
+	return children
+}
+
+// featureDescendants returns children, grandchildren and so on, depth first.
+// A parent cycle in a hand-edited features.yaml is walked only once.
+func featureDescendants(features []Feature, id string) []Feature {
+	seen := map[string]bool{id: true}
+	var out []Feature
+	var walk func(string)
+	walk = func(parent string) {
+		for _, c := range featureChildren(features, parent) {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			out = append(out, c)
+			walk(c.ID)
+		}
+	}
+	walk(id)
+	return out
+}
+
+// incompleteDescendants lists the IDs of id's descendants not yet implemented.
+func incompleteDescendants(features []Feature, id string) []string {
+	var ids []string
+	for _, d := range featureDescendants(features, id) {
+		if d.Status != "implemented" {
+			ids = append(ids, d.ID)
+		}
+	}
+	return ids
+}
+
+// SetFeatureParent makes parent the parent of id; an empty parent (or "none")
+// detaches it.
+func SetFeatureParent(projectDir, id, parent string) error {
+	if parent == "none" {
+		parent = ""
+	}
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return err
+	}
+	if err := checkFeatureParent(features, id, parent); err != nil {
+		return err
+	}
+	for i := range features {
+		if features[i].ID == id {
+			features[i].Parent = parent
+			return saveFeatures(projectDir, features)
+		}
+	}
+	return fmt.Errorf("err:validation feature %s not found", id)
+}
+
+// checkFeatureParent rejects unknown parents and links that would form a cycle.
+func checkFeatureParent(features []Feature, id, parent string) error {
+	if parent == "" {
+		return nil
+	}
+	if parent == id {
+		return fmt.Errorf("err:validation feature %s cannot be its own parent", id)
+	}
+	found := false
+	for _, f := range features {
+		if f.ID == parent {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("err:validation parent feature %s not found", parent)
+	}
+	for _, d := range featureDescendants(features, id) {
+		if d.ID == parent {
+			return fmt.Errorf("err:validation %s is a descendant of %s: parent links cannot form a cycle", parent, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupEpicProject(t *testing.T) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t)
+	features := "features:\n" +
+		"  - id: checkout\n    title: Checkout\n    status: in-progress\n" +
+		"  - id: payments\n    title: Payments\n    status: implemented\n    parent: checkout\n" +
+		"  - id: refunds\n    title: Refunds\n    status: in-progress\n    parent: checkout\n" +
+		"  - id: chargebacks\n    title: Chargebacks\n    status: planned\n    parent: refunds\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte(features), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := "features:\n  payments:\n    stage: impl\n    hashes:\n      test_status: passing\n    scores:\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setupTasks(t, dir,
+		Task{ID: "T-1", Feature: "checkout", Title: "wire up", Status: "DONE", Priority: "A"},
+		Task{ID: "T-2", Feature: "payments", Title: "cards", Status: "DONE", Priority: "A"},
+		Task{ID: "T-3", Feature: "chargebacks", Title: "disputes", Status: "TODO", Priority: "B"},
+	)
+	return dir
+}
+
+func TestEpicRollups(t *testing.T) {
+	dir := setupEpicProject(t)
+
+	rollups, err := EpicRollups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rollups) != 2 || rollups[0].ID != "checkout" || rollups[1].ID != "refunds" {
+		t.Fatalf("expected rollups for checkout and refunds, got %+v", rollups)
+	}
+
+	r := rollups[0]
+	if len(r.Children) != 2 || r.Descendants != 3 {
+		t.Errorf("children=%d descendants=%d, want 2 and 3", len(r.Children), r.Descendants)
+	}
+	if r.Status != "in-progress" || r.Implemented != 1 || r.TestsPassing != 1 {
+		t.Errorf("status=%s implemented=%d passing=%d", r.Status, r.Implemented, r.TestsPassing)
+	}
+	if r.Tasks["DONE"] != 2 || r.Tasks["TODO"] != 1 {
+		t.Errorf("tasks = %v, want DONE:2 TODO:1", r.Tasks)
+	}
+
+	if sub := rollups[1]; sub.Status != "planned" || sub.Descendants != 1 {
+		t.Errorf("refunds rollup = %+v", sub)
+	}
+}
+
+func TestUpdateFeatureStatusImplementedRequiresChildren(t *testing.T) {
+	dir := setupEpicProject(t)
+
+	err := UpdateFeatureStatus(dir, "checkout", "implemented")
+	if err == nil || !strings.HasPrefix(err.Error(), "err:pipeline") {
+		t.Fatalf("expected err:pipeline, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "refunds, chargebacks") {
+		t.Errorf("error should list incomplete descendants: %v", err)
+	}
+}
+
+func TestSetFeatureParent(t *testing.T) {
+	dir := setupEpicProject(t)
+
+	if err := SetFeatureParent(dir, "checkout", "chargebacks"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+	if err := SetFeatureParent(dir, "checkout", "checkout"); err == nil {
+		t.Error("expected error for self parent")
+	}
+	if err := SetFeatureParent(dir, "checkout", "nope"); err == nil {
+		t.Error("expected error for unknown parent")
+	}
+
+	if err := SetFeatureParent(dir, "chargebacks", "checkout"); err != nil {
+		t.Fatal(err)
+	}
+	detail, err := ShowFeature(dir, "checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(detail.Children, ",") != "payments,refunds,chargebacks" {
+		t.Errorf("children = %v", detail.Children)
+	}
+
+	if err := SetFeatureParent(dir, "chargebacks", "none"); err != nil {
+		t.Fatal(err)
+	}
+	if detail, _ := ShowFeature(dir, "chargebacks"); detail.Parent != "" {
+		t.Errorf("parent not cleared: %q", detail.Parent)
+	}
+}
+
+func TestAddFeatureWithParent(t *testing.T) {
+	dir := setupEpicProject(t)
+
+	if err := AddFeatureWithParent(dir, "receipts", "Receipts", "", "missing"); err == nil {
+		t.Error("expected error for unknown parent")
+	}
+	if err := AddFeatureWithParent(dir, "receipts", "Receipts", "", "checkout"); err != nil {
+		t.Fatal(err)
+	}
+	if detail, _ := ShowFeature(dir, "receipts"); detail.Parent != "checkout" {
+		t.Errorf("parent = %q, want checkout", detail.Parent)
+	}
+}
@@ -0,0 +1,193 @@
+package core
+
+import (
+	"sort"
+	"time"
+)
+
+// Completion estimates. Each stage a feature has left is assumed to take as
+// long as that stage took historically (the median of finished stage
+// durations, or of all finished stages when a stage has no history yet);
+// time already spent in the current stage is subtracted. Milestones from
+// ptsd.yaml finish when their last feature does.
+
+// FeatureETA is the estimated completion of one unfinished feature.
+type FeatureETA struct {
+	Feature   string
+	Stage     string
+	Remaining time.Duration
+	// ETA is zero when there is no stage history to estimate from.
+	ETA time.Time
+	// Milestone and Target are set when the feature belongs to a milestone.
+	Milestone string
+	Target    string
+	// Late reports an ETA after the milestone target.
+	Late bool
+}
+
+// MilestoneETA rolls a milestone's features up against its target date.
+type MilestoneETA struct {
+	Name     string
+	Target   string
+	ETA      time.Time // zero when any open feature has no estimate
+	Features int
+	Done     int
+	Late     bool
+}
+
+// EstimateCompletion estimates in-progress features and milestone members
+// from stage history. Features are sorted by ID, milestones by name.
+func EstimateCompletion(projectDir string, now time.Time) ([]FeatureETA, []MilestoneETA, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var milestoneCfg map[string]Milestone
+	if cfg, err := LoadConfig(projectDir); err == nil {
+		milestoneCfg = cfg.Milestones
+	}
+	durations, err := StageDurations(projectDir, now, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	state, _ := LoadState(projectDir)
+
+	medians, overall := stageMedians(durations)
+	current := make(map[string]StageDuration)
+	for _, d := range durations {
+		if d.Current {
+			current[d.Feature] = d
+		}
+	}
+
+	// A feature in several milestones is checked against the earliest target.
+	milestoneOf := make(map[string]string)
+	var names []string
+	for name, m := range milestoneCfg {
+		names = append(names, name)
+		for _, id := range m.Features {
+			if prev, ok := milestoneOf[id]; !ok || m.Target < milestoneCfg[prev].Target {
+				milestoneOf[id] = name
+			}
+		}
+	}
+	sort.Strings(names)
+
+	status := make(map[string]string)
+	var etas []FeatureETA
+	byID := make(map[string]FeatureETA)
+	for _, f := range features {
+		status[f.ID] = f.Status
+		_, inMilestone := milestoneOf[f.ID]
+		if f.Status == "implemented" || f.Status == "deferred" || (f.Status != "in-progress" && !inMilestone) {
+			continue
+		}
+
+		e := FeatureETA{Feature: f.ID}
+		var elapsed time.Duration
+		if d, ok := current[f.ID]; ok {
+			e.Stage, elapsed = d.Stage, d.Duration
+		} else if state != nil {
+			e.Stage = state.Features[f.ID].Stage
+		}
+		if overall > 0 {
+			e.Remaining = remainingTime(FeatureStages(projectDir, f.ID), e.Stage, elapsed, medians, overall)
+			e.ETA = now.Add(e.Remaining)
+		}
+		if name, ok := milestoneOf[f.ID]; ok {
+			e.Milestone, e.Target = name, milestoneCfg[name].Target
+			e.Late = !e.ETA.IsZero() && e.Target != "" && e.ETA.Format(revisitLayout) > e.Target
+		}
+		etas = append(etas, e)
+		byID[f.ID] = e
+	}
+	sort.Slice(etas, func(i, j int) bool { return etas[i].Feature < etas[j].Feature })
+
+	var milestones []MilestoneETA
+	for _, name := range names {
+		m := milestoneCfg[name]
+		me := MilestoneETA{Name: name, Target: m.Target, Features: len(m.Features)}
+		unknown := false
+		for _, id := range m.Features {
+			if status[id] == "implemented" {
+				me.Done++
+				continue
+			}
+			e, ok := byID[id]
+			if !ok || e.ETA.IsZero() {
+				unknown = true
+				continue
+			}
+			if e.ETA.After(me.ETA) {
+				me.ETA = e.ETA
+			}
+		}
+		if unknown {
+			me.ETA = time.Time{}
+		} else if me.Done == me.Features && me.ETA.IsZero() {
+			me.ETA = now
+		}
+		me.Late = !me.ETA.IsZero() && m.Target != "" && me.ETA.Format(revisitLayout) > m.Target
+		milestones = append(milestones, me)
+	}
+	return etas, milestones, nil
+}
+
+// remainingTime is what is left of the current stage plus every later one.
+func remainingTime(stages []string, stage string, elapsed time.Duration, medians map[string]time.Duration, overall time.Duration) time.Duration {
+	estimate := func(s string) time.Duration {
+		if m, ok := medians[s]; ok {
+			return m
+		}
+		return overall
+	}
+	start := 0
+	for i, s := range stages {
+		if s == stage {
+			start = i
+			break
+		}
+	}
+	var total time.Duration
+	for i := start; i < len(stages); i++ {
+		d := estimate(stages[i])
+		if i == start && stage != "" {
+			d = max(0, d-elapsed)
+		}
+		total += d
+	}
+	return total
+}
+
+// stageMedians returns the median duration of each stage features have
+// left, and the median across all of them. Stages still in progress, and the
+// last stage of finished features, are not history.
+func stageMedians(durations []StageDuration) (map[string]time.Duration, time.Duration) {
+	byStage := make(map[string][]time.Duration)
+	var all []time.Duration
+	for _, d := range durations {
+		if d.Current || d.Done {
+			continue
+		}
+		byStage[d.Stage] = append(byStage[d.Stage], d.Duration)
+		all = append(all, d.Duration)
+	}
+	medians := make(map[string]time.Duration)
+	for s, ds := range byStage {
+		medians[s] = medianDuration(ds)
+	}
+	return medians, medianDuration(all)
+}
+
+func medianDuration(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimateCompletion(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:in-progress", "done:implemented", "later:planned", "idle:planned")
+	cfg := "milestones:\n  v1:\n    target: 2026-03-30\n    features: [auth, done]\n  v2:\n    target: \"2026-03-25\"\n    features:\n      - billing\n      - later\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	// done took prd 2d, seed 2d, bdd 4d, tests 2d: impl has no history and
+	// falls back to the 2d median of all finished stages.
+	for _, e := range []Event{
+		{At: now.Add(-30 * day), Type: EventStage, Feature: "done", Stage: "prd"},
+		{At: now.Add(-28 * day), Type: EventStage, Feature: "done", Stage: "seed"},
+		{At: now.Add(-26 * day), Type: EventStage, Feature: "done", Stage: "bdd"},
+		{At: now.Add(-22 * day), Type: EventStage, Feature: "done", Stage: "tests"},
+		{At: now.Add(-20 * day), Type: EventStage, Feature: "done", Stage: "impl"},
+		{At: now.Add(-1 * day), Type: EventStage, Feature: "auth", Stage: "bdd"},
+	} {
+		if err := AppendEvent(dir, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	etas, milestones, err := EstimateCompletion(dir, now)
+	if err != nil {
+		t.Fatalf("EstimateCompletion failed: %v", err)
+	}
+	if len(etas) != 3 {
+		t.Fatalf("expected estimates for auth, billing, later; got %+v", etas)
+	}
+	auth, billing, later := etas[0], etas[1], etas[2]
+	if auth.Feature != "auth" || auth.Stage != "bdd" || auth.Remaining != 7*day || auth.Milestone != "v1" || auth.Late {
+		t.Errorf("unexpected auth estimate: %+v", auth)
+	}
+	if billing.Remaining != 12*day || !billing.ETA.Equal(now.Add(12*day)) || billing.Milestone != "v2" || !billing.Late {
+		t.Errorf("unexpected billing estimate: %+v", billing)
+	}
+	if later.Feature != "later" || !later.Late {
+		t.Errorf("expected planned milestone member later to be estimated late, got %+v", later)
+	}
+
+	if len(milestones) != 2 {
+		t.Fatalf("expected 2 milestones, got %+v", milestones)
+	}
+	v1, v2 := milestones[0], milestones[1]
+	if v1.Name != "v1" || v1.Done != 1 || v1.Features != 2 || !v1.ETA.Equal(now.Add(7*day)) || v1.Late {
+		t.Errorf("unexpected v1: %+v", v1)
+	}
+	if v2.Target != "2026-03-25" || !v2.Late || v2.Done != 0 {
+		t.Errorf("unexpected v2: %+v", v2)
+	}
+}
+
+func TestEstimateCompletionWithoutHistory(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	etas, milestones, err := EstimateCompletion(dir, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(etas) != 1 || !etas[0].ETA.IsZero() || etas[0].Late {
+		t.Errorf("expected an unknown estimate, got %+v", etas)
+	}
+	if len(milestones) != 0 {
+		t.Errorf("expected no milestones, got %+v", milestones)
+	}
+}
+
+func TestLoadConfig_InvalidMilestoneTarget(t *testing.T) {
+	dir := writeTestConfig(t, "milestones:\n  v1:\n    target: next week\n")
+	_, err := LoadConfig(dir)
+	if err == nil || !strings.HasPrefix(err.Error(), "err:config invalid milestones.v1.target") {
+		t.Errorf("expected err:config, got %v", err)
+	}
+}
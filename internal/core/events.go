@@ -0,0 +1,172 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a single entry in the append-only pipeline event log (.ptsd/events.yaml).
+type Event struct {
+	At      time.Time
+	Type    string // stage | review | test | regression | task | validate | gate | tamper | context
+	Feature string
+	Stage   string
+	Task    string
+	Score   int
+	Detail  string
+	Sig     string // HMAC chain signature when audit.sign is on
+}
+
+const (
+	EventStage      = "stage"
+	EventReview     = "review"
+	EventTest       = "test"
+	EventRegression = "regression"
+	EventTask       = "task"
+	EventValidate   = "validate" // one per `ptsd validate` run, plus one per failing feature
+	EventGate       = "gate"     // a write blocked by gate-check
+	EventContext    = "context"  // an agent-mode `ptsd context` run (session start hook)
+)
+
+func eventsPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "events.yaml")
+}
+
+// AppendEvent appends an event to .ptsd/events.yaml, creating the file if needed.
+// A zero At is replaced with the current time.
+func AppendEvent(projectDir string, e Event) error {
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+	e.Detail = strings.ReplaceAll(e.Detail, "\"", "'")
+	if signingEnabled(projectDir) {
+		if err := signEvent(projectDir, &e); err != nil {
+			return err
+		}
+	}
+
+	path := eventsPath(projectDir)
+	var b strings.Builder
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		b.WriteString("events:\n")
+	}
+	b.WriteString("  - at: \"" + e.At.UTC().Format(time.RFC3339Nano) + "\"\n")
+	b.WriteString("    type: " + e.Type + "\n")
+	if e.Feature != "" {
+		b.WriteString("    feature: " + e.Feature + "\n")
+	}
+	if e.Stage != "" {
+		b.WriteString("    stage: " + e.Stage + "\n")
+	}
+	if e.Task != "" {
+		b.WriteString("    task: " + e.Task + "\n")
+	}
+	if e.Type == EventReview {
+		b.WriteString("    score: " + strconv.Itoa(e.Score) + "\n")
+	}
+	if e.Detail != "" {
+		b.WriteString("    detail: \"" + e.Detail + "\"\n")
+	}
+	if e.Sig != "" {
+		b.WriteString("    sig: " + e.Sig + "\n")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		f.Close()
+		return fmt.Errorf("err:io %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// recordEvent appends an event and ignores failures — the log never blocks a command.
+func recordEvent(projectDir string, e Event) {
+	_ = AppendEvent(projectDir, e)
+}
+
+// LoadEvents reads all events from .ptsd/events.yaml. Returns empty list if the log does not exist.
+func LoadEvents(projectDir string) ([]Event, error) {
+	data, err := os.ReadFile(eventsPath(projectDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	return parseEvents(string(data)), nil
+}
+
+// EventsSince returns events with At in [since, until).
+func EventsSince(events []Event, since, until time.Time) []Event {
+	var out []Event
+	for _, e := range events {
+		if !e.At.Before(since) && e.At.Before(until) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func parseEvents(content string) []Event {
+	var events []Event
+	var cur *Event
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "events:" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") {
+			if cur != nil {
+				events = append(events, *cur)
+			}
+			cur = &Event{}
+			trimmed = strings.TrimPrefix(trimmed, "- ")
+		}
+		if cur == nil {
+			continue
+		}
+
+		parts := strings.SplitN(trimmed, ": ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		val := stripQuotes(parts[1])
+		switch parts[0] {
+		case "at":
+			t, err := time.Parse(time.RFC3339Nano, val)
+			if err == nil {
+				cur.At = t
+			}
+		case "type":
+			cur.Type = val
+		case "feature":
+			cur.Feature = val
+		case "stage":
+			cur.Stage = val
+		case "task":
+			cur.Task = val
+		case "score":
+			cur.Score, _ = strconv.Atoi(val)
+		case "detail":
+			cur.Detail = val
+		case "sig":
+			cur.Sig = val
+		}
+	}
+	if cur != nil {
+		events = append(events, *cur)
+	}
+
+	return events
+}
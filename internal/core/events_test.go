@@ -0,0 +1,94 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendEventCreatesLog(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+
+	at := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	if err := AppendEvent(dir, Event{At: at, Type: EventReview, Feature: "auth", Stage: "prd", Score: 8}); err != nil {
+		t.Fatalf("AppendEvent failed: %v", err)
+	}
+	if err := AppendEvent(dir, Event{At: at.Add(time.Hour), Type: EventTask, Feature: "auth", Task: "T-1", Detail: "DONE"}); err != nil {
+		t.Fatalf("AppendEvent failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".ptsd", "events.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "events:\n") {
+		t.Errorf("expected events: header, got:\n%s", data)
+	}
+	if strings.Count(string(data), "events:") != 1 {
+		t.Errorf("header must be written once, got:\n%s", data)
+	}
+
+	events, err := LoadEvents(dir)
+	if err != nil {
+		t.Fatalf("LoadEvents failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Type != EventReview || events[0].Score != 8 || events[0].Stage != "prd" || !events[0].At.Equal(at) {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[1].Task != "T-1" || events[1].Detail != "DONE" {
+		t.Errorf("unexpected second event: %+v", events[1])
+	}
+}
+
+func TestLoadEventsMissingLog(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	events, err := LoadEvents(dir)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected no events, got %d", len(events))
+	}
+}
+
+func TestRecordReviewAppendsEvents(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+
+	if err := RecordReview(dir, "auth", "seed", 9); err != nil {
+		t.Fatalf("RecordReview failed: %v", err)
+	}
+
+	events, _ := LoadEvents(dir)
+	var review, stage bool
+	for _, e := range events {
+		if e.Type == EventReview && e.Feature == "auth" && e.Score == 9 {
+			review = true
+		}
+		if e.Type == EventStage && e.Feature == "auth" && e.Stage == "seed" {
+			stage = true
+		}
+	}
+	if !review || !stage {
+		t.Errorf("expected review and stage events, got %+v", events)
+	}
+}
+
+func TestUpdateTaskAppendsEvent(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "auth")
+	setupTasks(t, dir, Task{ID: "T-1", Feature: "auth", Title: "Login", Status: "TODO", Priority: "A"})
+
+	if err := UpdateTask(dir, "T-1", "DONE"); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+
+	events, _ := LoadEvents(dir)
+	if len(events) != 1 || events[0].Type != EventTask || events[0].Detail != "DONE" || events[0].Feature != "auth" {
+		t.Errorf("expected one task DONE event, got %+v", events)
+	}
+}
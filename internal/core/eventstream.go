@@ -0,0 +1,147 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EventTypes lists every event type ptsd writes, for filter validation.
+var EventTypes = []string{EventStage, EventReview, EventTest, EventRegression, EventTask, EventValidate, EventGate, EventTamper, EventContext}
+
+// EventFilter selects events by feature and type; empty fields match all.
+type EventFilter struct {
+	Feature string
+	Types   []string
+}
+
+// Match reports whether e passes the filter.
+func (f EventFilter) Match(e Event) bool {
+	if f.Feature != "" && e.Feature != f.Feature {
+		return false
+	}
+	return len(f.Types) == 0 || slices.Contains(f.Types, e.Type)
+}
+
+// TailEvents returns the last n events matching filter (none when n is 0)
+// and the size of the log, the offset FollowEvents continues from.
+func TailEvents(projectDir string, filter EventFilter, n int) ([]Event, int64, error) {
+	data, err := os.ReadFile(eventsPath(projectDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("err:io %w", err)
+	}
+	var matched []Event
+	for _, e := range parseEvents(string(data)) {
+		if filter.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+	if len(matched) > n {
+		matched = matched[len(matched)-n:]
+	}
+	return matched, int64(len(data)), nil
+}
+
+// FollowEvents polls the event log every interval and calls onEvent for each
+// matching event appended after offset, until stop is closed. Entries are
+// appended with a single write, so only complete lines are parsed. A log that
+// shrank (replaced by restore, say) is read again from the start.
+func FollowEvents(projectDir string, offset int64, filter EventFilter, interval time.Duration, onEvent func(Event), stop <-chan struct{}) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	path := eventsPath(projectDir)
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			offset = 0
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("err:io %w", err)
+		}
+		if info.Size() < offset {
+			offset = 0
+		}
+		if info.Size() == offset {
+			continue
+		}
+		data, err := readFrom(path, offset)
+		if err != nil {
+			return err
+		}
+		end := bytes.LastIndexByte(data, '\n')
+		if end < 0 {
+			continue
+		}
+		offset += int64(end + 1)
+		for _, e := range parseEvents(string(data[:end+1])) {
+			if filter.Match(e) {
+				onEvent(e)
+			}
+		}
+	}
+}
+
+func readFrom(path string, offset int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	return data, nil
+}
+
+// RecordValidation logs a validate run: a summary event, and one event per
+// failing feature naming the rules it broke.
+func RecordValidation(projectDir string, errs []ValidationError) {
+	if len(errs) == 0 {
+		recordEvent(projectDir, Event{Type: EventValidate, Detail: "pass"})
+		return
+	}
+	var order []string
+	counts := make(map[string]int)
+	rules := make(map[string][]string)
+	warnings := 0
+	for _, e := range errs {
+		if e.IsWarning() {
+			warnings++
+		}
+		if _, seen := counts[e.Feature]; !seen {
+			order = append(order, e.Feature)
+		}
+		counts[e.Feature]++
+		if e.Rule != "" && !slices.Contains(rules[e.Feature], e.Rule) {
+			rules[e.Feature] = append(rules[e.Feature], e.Rule)
+		}
+	}
+	recordEvent(projectDir, Event{Type: EventValidate,
+		Detail: fmt.Sprintf("fail errors=%d warnings=%d features=%d", len(errs)-warnings, warnings, len(order))})
+	for _, f := range order {
+		if f == "" {
+			continue
+		}
+		sort.Strings(rules[f])
+		recordEvent(projectDir, Event{Type: EventValidate, Feature: f,
+			Detail: fmt.Sprintf("fail findings=%d rules=%s", counts[f], strings.Join(rules[f], ","))})
+	}
+}
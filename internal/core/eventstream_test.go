@@ -0,0 +1,96 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTailEventsFilters(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "cart:in-progress")
+	AppendEvent(dir, Event{Type: EventStage, Feature: "auth", Stage: "seed"})
+	AppendEvent(dir, Event{Type: EventReview, Feature: "auth", Stage: "seed", Score: 8})
+	AppendEvent(dir, Event{Type: EventReview, Feature: "cart", Stage: "prd", Score: 5})
+	AppendEvent(dir, Event{Type: EventTask, Feature: "auth", Task: "T-1", Detail: "DONE"})
+
+	events, offset, err := TailEvents(dir, EventFilter{Feature: "auth"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Type != EventReview || events[1].Task != "T-1" {
+		t.Errorf("events = %+v, want the last two auth events", events)
+	}
+	if info, _ := os.Stat(filepath.Join(dir, ".ptsd", "events.yaml")); offset != info.Size() {
+		t.Errorf("offset = %d, want log size %d", offset, info.Size())
+	}
+
+	events, _, _ = TailEvents(dir, EventFilter{Types: []string{EventReview}}, 10)
+	if len(events) != 2 || events[1].Feature != "cart" {
+		t.Errorf("review events = %+v", events)
+	}
+	if events, _, _ := TailEvents(dir, EventFilter{}, 0); len(events) != 0 {
+		t.Errorf("-n 0 should return no backlog, got %d", len(events))
+	}
+}
+
+func TestFollowEventsStreamsAppends(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	AppendEvent(dir, Event{Type: EventStage, Feature: "auth", Stage: "seed"})
+	_, offset, err := TailEvents(dir, EventFilter{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(chan Event, 4)
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- FollowEvents(dir, offset, EventFilter{Feature: "auth"}, 10*time.Millisecond, func(e Event) { got <- e }, stop)
+	}()
+
+	AppendEvent(dir, Event{Type: EventGate, Feature: "other", Detail: "x_test.go: no BDD"})
+	AppendEvent(dir, Event{Type: EventGate, Feature: "auth", Detail: "auth_test.go: no BDD"})
+	select {
+	case e := <-got:
+		if e.Type != EventGate || e.Feature != "auth" {
+			t.Errorf("followed event = %+v", e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("appended event not streamed")
+	}
+
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("unexpected extra events: %d (the backlog must not be replayed)", len(got))
+	}
+}
+
+func TestRecordValidation(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	RecordValidation(dir, []ValidationError{
+		{Feature: "auth", Category: "pipeline", Rule: "bdd-tests", Message: "has bdd but no tests"},
+		{Feature: "auth", Category: "pipeline", Rule: "bdd-seed", Message: "has bdd but no seed"},
+		{Feature: "cart", Category: "pipeline", Rule: "regression", Severity: "warn", Message: "stale"},
+	})
+	RecordValidation(dir, nil)
+
+	events, _ := LoadEvents(dir)
+	want := []string{
+		"|fail errors=2 warnings=1 features=2",
+		"auth|fail findings=2 rules=bdd-seed,bdd-tests",
+		"cart|fail findings=1 rules=regression",
+		"|pass",
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v", events)
+	}
+	for i, e := range events {
+		if e.Type != EventValidate || e.Feature+"|"+e.Detail != want[i] {
+			t.Errorf("event %d = %s %q, want %q", i, e.Type, e.Feature+"|"+e.Detail, want[i])
+		}
+	}
+}
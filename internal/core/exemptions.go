@@ -0,0 +1,192 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Gate exemptions. `ptsd gate exempt add <pattern> --until <date> --reason
+// <text>` lets gate-check allow writes to matching paths until the date
+// (inclusive), instead of weakening the gates for good. Exemptions live in
+// .ptsd/gate-exemptions.yaml, which only ptsd writes; once expired they stop
+// applying and validate reports them until removed.
+
+// GateExemption allows gated writes to paths matching Pattern (a glob; "**"
+// spans directories) until Until, a YYYY-MM-DD date.
+type GateExemption struct {
+	Pattern string
+	Until   string
+	Reason  string
+	Added   string
+}
+
+// Expired reports whether the exemption no longer applies on now.
+func (e GateExemption) Expired(now time.Time) bool {
+	return e.Until < now.Format(revisitLayout)
+}
+
+const exemptionsFile = "gate-exemptions.yaml"
+
+func exemptionsPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", exemptionsFile)
+}
+
+// LoadGateExemptions reads .ptsd/gate-exemptions.yaml; a missing file means
+// no exemptions.
+func LoadGateExemptions(projectDir string) ([]GateExemption, error) {
+	data, err := os.ReadFile(exemptionsPath(projectDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	var out []GateExemption
+	for i, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || !strings.HasPrefix(line, " ") {
+			continue
+		}
+		item := strings.HasPrefix(trimmed, "- ")
+		key, val, ok := strings.Cut(strings.TrimPrefix(trimmed, "- "), ": ")
+		if !ok {
+			return nil, fmt.Errorf("err:config %s line %d: expected key: value", exemptionsFile, i+1)
+		}
+		if strings.HasPrefix(val, "\"") {
+			unq, err := strconv.Unquote(val)
+			if err != nil {
+				return nil, fmt.Errorf("err:config %s line %d: bad string %s", exemptionsFile, i+1, val)
+			}
+			val = unq
+		}
+		if item {
+			out = append(out, GateExemption{})
+		}
+		if len(out) == 0 {
+			continue
+		}
+		e := &out[len(out)-1]
+		switch key {
+		case "pattern":
+			e.Pattern = val
+		case "until":
+			e.Until = val
+		case "reason":
+			e.Reason = val
+		case "added":
+			e.Added = val
+		}
+	}
+	return out, nil
+}
+
+func saveGateExemptions(projectDir string, exemptions []GateExemption) error {
+	var sb strings.Builder
+	sb.WriteString("# Temporary gate-check exemptions; manage with ptsd gate exempt.\n")
+	sb.WriteString("exemptions:\n")
+	for _, e := range exemptions {
+		sb.WriteString("  - pattern: " + strconv.Quote(e.Pattern) + "\n")
+		sb.WriteString("    until: " + e.Until + "\n")
+		sb.WriteString("    reason: " + strconv.Quote(e.Reason) + "\n")
+		if e.Added != "" {
+			sb.WriteString("    added: " + e.Added + "\n")
+		}
+	}
+	if err := writeFileAtomic(exemptionsPath(projectDir), []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// AddGateExemption records an exemption for pattern, replacing an existing
+// one for the same pattern. until must be a YYYY-MM-DD date not before now,
+// and a reason is required.
+func AddGateExemption(projectDir, pattern, until, reason string, now time.Time) (GateExemption, error) {
+	pattern = filepath.ToSlash(strings.TrimPrefix(strings.TrimSpace(pattern), "./"))
+	switch {
+	case pattern == "" || filepath.IsAbs(pattern) || strings.HasPrefix(pattern, "../"):
+		return GateExemption{}, fmt.Errorf("err:user invalid pattern %q: use a path relative to the project root", pattern)
+	case strings.HasPrefix(pattern, ".ptsd/"):
+		return GateExemption{}, fmt.Errorf("err:user .ptsd files cannot be exempted")
+	case strings.TrimSpace(reason) == "":
+		return GateExemption{}, fmt.Errorf("err:user --reason is required")
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return GateExemption{}, fmt.Errorf("err:user invalid pattern %q: %v", pattern, err)
+	}
+	if _, err := time.Parse(revisitLayout, until); err != nil {
+		return GateExemption{}, fmt.Errorf("err:user invalid --until %q: must be YYYY-MM-DD", until)
+	}
+	e := GateExemption{Pattern: pattern, Until: until, Reason: strings.TrimSpace(reason), Added: now.Format(revisitLayout)}
+	if e.Expired(now) {
+		return GateExemption{}, fmt.Errorf("err:user --until %s is in the past", until)
+	}
+
+	exemptions, err := LoadGateExemptions(projectDir)
+	if err != nil {
+		return GateExemption{}, err
+	}
+	replaced := false
+	for i := range exemptions {
+		if exemptions[i].Pattern == pattern {
+			exemptions[i] = e
+			replaced = true
+		}
+	}
+	if !replaced {
+		exemptions = append(exemptions, e)
+	}
+	return e, saveGateExemptions(projectDir, exemptions)
+}
+
+// RemoveGateExemption deletes the exemption for pattern.
+func RemoveGateExemption(projectDir, pattern string) error {
+	exemptions, err := LoadGateExemptions(projectDir)
+	if err != nil {
+		return err
+	}
+	var kept []GateExemption
+	for _, e := range exemptions {
+		if e.Pattern != pattern {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(exemptions) {
+		return fmt.Errorf("err:user no gate exemption for %q", pattern)
+	}
+	return saveGateExemptions(projectDir, kept)
+}
+
+// activeExemption returns the first unexpired exemption matching rel.
+func activeExemption(projectDir, rel string, now time.Time) (GateExemption, bool) {
+	exemptions, err := LoadGateExemptions(projectDir)
+	if err != nil {
+		return GateExemption{}, false
+	}
+	for _, e := range exemptions {
+		if !e.Expired(now) && matchesTestPattern(rel, e.Pattern) {
+			return e, true
+		}
+	}
+	return GateExemption{}, false
+}
+
+// checkExemptions reports expired gate exemptions that are still recorded.
+func checkExemptions(projectDir string, now time.Time) []ValidationError {
+	exemptions, err := LoadGateExemptions(projectDir)
+	if err != nil {
+		return []ValidationError{{Category: "pipeline", Rule: "gate-exemption", Message: err.Error()}}
+	}
+	var errs []ValidationError
+	for _, e := range exemptions {
+		if e.Expired(now) {
+			errs = append(errs, ValidationError{Category: "pipeline", Rule: "gate-exemption",
+				Message: fmt.Sprintf("gate exemption for %s expired %s (%s) — remove it: ptsd gate exempt remove %s", e.Pattern, e.Until, e.Reason, e.Pattern)})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,293 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config inheritance. A ptsd.yaml with a top-level `extends: <source>` is
+// layered over that file, so a platform team can keep gates, scopes and
+// review thresholds in one policy file shared by many repositories:
+//
+//	extends: ../platform/ptsd-policy.yaml                      local path, relative to the project root
+//	extends: https://policy.example.com/ptsd.yaml              fetched over HTTP(S)
+//	extends: git@github.com:org/policy.git//ptsd.yaml?ref=v2   a file in a git repository
+//
+// Keys the project sets win: a top-level scalar, and each key of a section
+// (a sub-block such as features_config.<id>, testing.patterns or a block
+// list counts as one key), replaces the base's; the rest is inherited. A
+// base may extend another, up to maxExtendsDepth. Fetched sources are cached
+// in .ptsd/.extends/ for ExtendsTTL, and a stale copy is used when a refresh
+// fails or the project is air-gapped, by its own ptsd.yaml or by a policy.
+
+// ExtendsTTL is how long a fetched extends source is used before refetching.
+const ExtendsTTL = time.Hour
+
+const maxExtendsDepth = 5
+
+// extendsSource returns the top-level extends: value, or "".
+func extendsSource(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimRight(line, "\r "), "extends: "); ok {
+			return stripQuotes(strings.TrimSpace(value))
+		}
+	}
+	return ""
+}
+
+func extendsCacheDir(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", ".extends")
+}
+
+type extendsResolver struct {
+	root string
+	// airGapped is set once any file of the chain read so far sets
+	// network.air_gapped, so a policy can air-gap the project too.
+	airGapped bool
+}
+
+func setsAirGapped(content string) bool {
+	cfg, err := parseConfig(content)
+	return err == nil && cfg.Network.AirGapped
+}
+
+// resolveExtends returns the project's ptsd.yaml content merged over its
+// extends chain, every file upgraded to the current schema first.
+func resolveExtends(projectDir, content string) (string, error) {
+	upgraded, err := upgradeSchema("ptsd.yaml", content)
+	if err != nil {
+		return "", err
+	}
+	r := &extendsResolver{root: projectDir, airGapped: setsAirGapped(upgraded)}
+	return r.resolve(projectDir, upgraded, 0)
+}
+
+func (r *extendsResolver) resolve(dir, content string, depth int) (string, error) {
+	source := extendsSource(content)
+	if source == "" {
+		return content, nil
+	}
+	if depth >= maxExtendsDepth {
+		return "", fmt.Errorf("err:config extends chain is deeper than %d files (is %s extending itself?)", maxExtendsDepth, source)
+	}
+	data, baseDir, err := r.read(dir, source)
+	if err != nil {
+		return "", err
+	}
+	upgraded, err := upgradeSchema("ptsd.yaml", string(data))
+	if err != nil {
+		return "", fmt.Errorf("err:config extends %s: %s", source, strings.TrimPrefix(err.Error(), "err:config "))
+	}
+	cfg, err := parseConfig(upgraded)
+	if err != nil {
+		return "", fmt.Errorf("err:config extends %s: %s", source, strings.TrimPrefix(err.Error(), "err:config "))
+	}
+	r.airGapped = r.airGapped || cfg.Network.AirGapped
+	base, err := r.resolve(baseDir, upgraded, depth+1)
+	if err != nil {
+		return "", err
+	}
+	return mergeConfigText(base, content), nil
+}
+
+// read returns an extends source and the directory its own relative
+// extends resolve against ("" for fetched sources).
+func (r *extendsResolver) read(dir, source string) ([]byte, string, error) {
+	if extendsIsGit(source) || strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err := r.fetch(source)
+		return data, "", err
+	}
+
+	path := source
+	if !filepath.IsAbs(path) {
+		if dir == "" {
+			return nil, "", fmt.Errorf("err:config extends %s: a fetched policy can only extend absolute paths or URLs", source)
+		}
+		path = filepath.Join(dir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("err:config extends %s: %v", source, err)
+	}
+	return data, filepath.Dir(path), nil
+}
+
+// fetch returns a remote source from the cache while it is fresh, otherwise
+// fetches and caches it, falling back to a stale copy on failure. A cached
+// policy that itself air-gaps the project is never refreshed.
+func (r *extendsResolver) fetch(source string) ([]byte, error) {
+	cachePath := filepath.Join(extendsCacheDir(r.root), contentHash([]byte(source))[:16]+".yaml")
+	cached, cacheErr := os.ReadFile(cachePath)
+	if cacheErr == nil {
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < ExtendsTTL {
+			return cached, nil
+		}
+	}
+	if r.airGapped || (cacheErr == nil && setsAirGapped(string(cached))) {
+		if cacheErr == nil {
+			return cached, nil
+		}
+		return nil, fmt.Errorf("err:config extends %s needs the network, which network.air_gapped disables", source)
+	}
+
+	var data []byte
+	var err error
+	if extendsIsGit(source) {
+		data, err = fetchGitFile(source)
+	} else {
+		data, err = fetchHTTPFile(source)
+	}
+	if err != nil {
+		if cacheErr == nil {
+			return cached, nil
+		}
+		return nil, fmt.Errorf("err:config cannot fetch extends %s: %v", source, err)
+	}
+	if err := os.MkdirAll(extendsCacheDir(r.root), 0755); err == nil {
+		writeFileAtomic(cachePath, data, 0644)
+	}
+	return data, nil
+}
+
+func extendsIsGit(source string) bool {
+	return strings.HasPrefix(source, "git::") || strings.HasPrefix(source, "git@") ||
+		strings.HasPrefix(source, "ssh://") || strings.Contains(source, ".git//")
+}
+
+// fetchGitFile reads <repo>//<file>[?ref=<ref>] from a shallow clone.
+func fetchGitFile(source string) ([]byte, error) {
+	source = strings.TrimPrefix(source, "git::")
+	scheme := ""
+	if i := strings.Index(source, "://"); i >= 0 {
+		scheme, source = source[:i+3], source[i+3:]
+	}
+	repo, file, ok := strings.Cut(source, "//")
+	if !ok {
+		return nil, fmt.Errorf("name the file inside the repository: <repo>//<file>")
+	}
+	file, ref, _ := strings.Cut(file, "?ref=")
+	if !filepath.IsLocal(file) {
+		return nil, fmt.Errorf("file %q is outside the repository", file)
+	}
+	// A shared ptsd.yaml must not smuggle git options (--upload-pack=...).
+	if strings.HasPrefix(scheme+repo, "-") || strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("repository and ref must not start with '-'")
+	}
+
+	tmp, err := os.MkdirTemp("", "ptsd-extends-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	cmd := exec.Command("git", append(args, "--", scheme+repo, tmp)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("git clone failed: %s", strings.TrimSpace(string(out)))
+	}
+	return os.ReadFile(filepath.Join(tmp, file))
+}
+
+func fetchHTTPFile(url string) ([]byte, error) {
+	resp, err := remoteClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET returned %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// configBlock is a top-level key of ptsd.yaml with its lines; a section's
+// keys at indent 2 are children, each with its deeper lines.
+type configBlock struct {
+	key      string
+	lines    []string
+	children []configBlock
+}
+
+func parseConfigBlocks(content string) []configBlock {
+	var blocks []configBlock
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r ")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		key, _, _ := strings.Cut(trimmed, ":")
+		switch {
+		case indent == 0:
+			blocks = append(blocks, configBlock{key: key, lines: []string{line}})
+		case len(blocks) == 0:
+		case indent == 2 && !strings.HasPrefix(trimmed, "- "):
+			b := &blocks[len(blocks)-1]
+			b.children = append(b.children, configBlock{key: key, lines: []string{line}})
+		default:
+			b := &blocks[len(blocks)-1]
+			if n := len(b.children); n > 0 {
+				b.children[n-1].lines = append(b.children[n-1].lines, line)
+			} else {
+				b.lines = append(b.lines, line)
+			}
+		}
+	}
+	return blocks
+}
+
+// mergeConfigText layers local over base key by key (see extendsSource).
+func mergeConfigText(base, local string) string {
+	merged := parseConfigBlocks(base)
+	for _, lb := range parseConfigBlocks(local) {
+		i := indexConfigBlock(merged, lb.key)
+		switch {
+		case i < 0:
+			merged = append(merged, lb)
+		case len(lb.children) == 0 && len(lb.lines) == 1 && strings.HasSuffix(lb.lines[0], ":"):
+			// An empty section inherits everything.
+		case len(lb.children) == 0 || len(merged[i].children) == 0:
+			merged[i] = lb
+		default:
+			for _, lc := range lb.children {
+				if j := indexConfigBlock(merged[i].children, lc.key); j >= 0 {
+					merged[i].children[j] = lc
+				} else {
+					merged[i].children = append(merged[i].children, lc)
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, block := range merged {
+		for _, line := range block.lines {
+			b.WriteString(line + "\n")
+		}
+		for _, child := range block.children {
+			for _, line := range child.lines {
+				b.WriteString(line + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+func indexConfigBlock(blocks []configBlock, key string) int {
+	for i, b := range blocks {
+		if b.key == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const policyConfig = `review:
+  min_score: 8
+  require: 2
+hooks:
+  scopes: [API, UI]
+gates:
+  mode: enforce
+`
+
+func TestLoadConfigExtendsLocalPolicy(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte(policyConfig), 0644)
+	writePtsdFile(t, dir, "ptsd.yaml", "extends: policy.yaml\nproject:\n  name: app\nreview:\n  require: 1\ngates:\n")
+
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Extends != "policy.yaml" || cfg.Project.Name != "app" {
+		t.Errorf("expected local keys, got extends=%q name=%q", cfg.Extends, cfg.Project.Name)
+	}
+	if cfg.Review.MinScore != 8 || cfg.Review.Require != 1 {
+		t.Errorf("expected inherited min_score 8 and local require 1, got %d/%d", cfg.Review.MinScore, cfg.Review.Require)
+	}
+	if strings.Join(cfg.Hooks.Scopes, ",") != "API,UI" || cfg.Gates.Mode != GatesEnforce {
+		t.Errorf("expected inherited scopes and gates, got %v %s", cfg.Hooks.Scopes, cfg.Gates.Mode)
+	}
+}
+
+func TestLoadConfigExtendsErrors(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	writePtsdFile(t, dir, "ptsd.yaml", "extends: missing.yaml\n")
+	if _, err := LoadConfig(dir); err == nil || !strings.HasPrefix(err.Error(), "err:config extends missing.yaml") {
+		t.Errorf("expected missing policy error, got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "loop.yaml"), []byte("extends: loop.yaml\n"), 0644)
+	writePtsdFile(t, dir, "ptsd.yaml", "extends: loop.yaml\n")
+	if _, err := LoadConfig(dir); err == nil || !strings.Contains(err.Error(), "extends chain is deeper") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte("gates:\n  mode: lax\n"), 0644)
+	writePtsdFile(t, dir, "ptsd.yaml", "extends: bad.yaml\n")
+	if _, err := LoadConfig(dir); err == nil || !strings.Contains(err.Error(), "extends bad.yaml: invalid gates.mode") {
+		t.Errorf("expected invalid policy error, got %v", err)
+	}
+}
+
+func TestLoadConfigExtendsHTTPCache(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(policyConfig))
+	}))
+	dir := setupProjectWithFeatures(t)
+	writePtsdFile(t, dir, "ptsd.yaml", "extends: "+srv.URL+"/ptsd.yaml\n")
+
+	for range 2 {
+		if cfg, err := LoadConfig(dir); err != nil || cfg.Review.MinScore != 8 {
+			t.Fatalf("expected policy min_score 8, got %+v %v", cfg, err)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("expected one fetch within the TTL, got %d", hits)
+	}
+
+	// An expired cache is refetched; when that fails the stale copy is used.
+	srv.Close()
+	entries, _ := os.ReadDir(extendsCacheDir(dir))
+	if len(entries) != 1 {
+		t.Fatalf("expected one cached source, got %d", len(entries))
+	}
+	old := time.Now().Add(-2 * ExtendsTTL)
+	os.Chtimes(filepath.Join(extendsCacheDir(dir), entries[0].Name()), old, old)
+	if cfg, err := LoadConfig(dir); err != nil || cfg.Review.MinScore != 8 {
+		t.Errorf("expected stale policy when the server is gone, got %+v %v", cfg, err)
+	}
+}
+
+func TestLoadConfigExtendsGitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := filepath.Join(t.TempDir(), "policy.git")
+	os.MkdirAll(filepath.Join(repo, "ptsd"), 0755)
+	os.WriteFile(filepath.Join(repo, "ptsd", "policy.yaml"), []byte(policyConfig), 0644)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "policy"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	dir := setupProjectWithFeatures(t)
+	writePtsdFile(t, dir, "ptsd.yaml", "extends: file://"+repo+"//ptsd/policy.yaml\n")
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Review.Require != 2 {
+		t.Errorf("expected require 2 from the git policy, got %d", cfg.Review.Require)
+	}
+}
+
+func TestLoadConfigExtendsGitRejectsOptions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	marker := filepath.Join(t.TempDir(), "pwned")
+	dir := setupProjectWithFeatures(t)
+	for _, source := range []string{
+		"git::--upload-pack=touch " + marker + ";false//ptsd/policy.yaml",
+		"git::file:///nonexistent.git//ptsd/policy.yaml?ref=--upload-pack=touch " + marker,
+	} {
+		writePtsdFile(t, dir, "ptsd.yaml", "extends: \""+source+"\"\n")
+		if _, err := LoadConfig(dir); err == nil || !strings.Contains(err.Error(), "must not start with '-'") {
+			t.Errorf("expected %q rejected, got %v", source, err)
+		}
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("extends source ran a command through git options")
+	}
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// writeFileAtomic is os.WriteFile for files ptsd rewrites: the content goes
+// to a temp file beside path, is fsynced, and is renamed over path, so a
+// hook killed mid-write leaves the old file or the new one, never half of
+// either. An existing file keeps its mode, and a symlink is written through
+// rather than replaced.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+base+".*.tmp")
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	committed = true
+	syncDir(dir)
+	return nil
+}
+
+// syncDir fsyncs a directory so a rename in it survives a crash. It is
+// best effort: some platforms cannot open a directory for syncing.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+// slashPath is a project-relative path as state stores it: with forward
+// slashes whichever OS wrote it, so checkouts on Windows and unix share
+// mappings. Backslashes are accepted on read and converted.
+func slashPath(p string) string {
+	return strings.ReplaceAll(p, `\`, "/")
+}
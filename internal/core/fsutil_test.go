@@ -0,0 +1,57 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.yaml")
+
+	if err := writeFileAtomic(path, []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "two\n" {
+		t.Errorf("content = %q", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want the existing 0600", info.Mode().Perm())
+	}
+
+	// A symlinked file is written through, not replaced.
+	link := filepath.Join(dir, "link.yaml")
+	if err := os.Symlink(path, link); err != nil {
+		t.Skip("symlinks unsupported:", err)
+	}
+	if err := writeFileAtomic(link, []byte("three\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Lstat(link); info.Mode()&os.ModeSymlink == 0 {
+		t.Error("symlink replaced by a regular file")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "three\n" {
+		t.Errorf("target content = %q", data)
+	}
+
+	// A failed write leaves no temp file behind.
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "x.yaml"), []byte("x"), 0644); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("leftover files: %v", names)
+	}
+}
@@ -3,7 +3,9 @@ package core
 import (
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 type GateCheckResult struct {
@@ -17,7 +19,6 @@ type GateCheckResult struct {
 // AutoTrack (PostToolUse) updates it via Go code, bypassing the gate.
 // Use `ptsd review` to set review verdicts.
 var alwaysAllowed = map[string]bool{
-	".ptsd/docs/PRD.md":     true,
 	".ptsd/tasks.yaml":      true,
 	".ptsd/state.yaml":      true,
 	".ptsd/features.yaml":   true,
@@ -41,6 +42,10 @@ func GateCheck(projectDir, filePath string) GateCheckResult {
 	if alwaysAllowed[rel] {
 		return GateCheckResult{Allowed: true}
 	}
+	// The PRD may be split across several files; every part is editable.
+	if isPRDFile(projectDir, rel) {
+		return GateCheckResult{Allowed: true}
+	}
 
 	// review-status.yaml: blocked for direct AI edits.
 	// AutoTrack (PostToolUse) writes via Go code, bypassing the gate.
@@ -52,6 +57,45 @@ func GateCheck(projectDir, filePath string) GateCheckResult {
 		}
 	}
 
+	// review-log.yaml: the append-only review history is written by
+	// `ptsd review` only, so the audit trail cannot be rewritten.
+	if rel == ".ptsd/"+reviewLogFile {
+		return GateCheckResult{
+			Allowed: false,
+			Reason:  "direct edits to " + reviewLogFile + " are blocked — ptsd review appends to it",
+		}
+	}
+
+	// reviewed/: feature files as of their last bdd review, kept by
+	// `ptsd review` as the baseline for `ptsd bdd diff`.
+	if strings.HasPrefix(filepath.ToSlash(rel), ".ptsd/reviewed/") {
+		return GateCheckResult{
+			Allowed: false,
+			Reason:  "direct edits to .ptsd/reviewed/ are blocked — ptsd review stores them",
+		}
+	}
+
+	// signatures.yaml: only ptsd writes it, alongside the files it signs.
+	if rel == ".ptsd/signatures.yaml" {
+		return GateCheckResult{
+			Allowed: false,
+			Reason:  "direct edits to signatures.yaml are blocked — ptsd maintains it",
+		}
+	}
+
+	// gate-exemptions.yaml: exemptions are granted with ptsd gate exempt.
+	if rel == ".ptsd/"+exemptionsFile {
+		return GateCheckResult{
+			Allowed: false,
+			Reason:  "direct edits to " + exemptionsFile + " are blocked — use ptsd gate exempt",
+		}
+	}
+
+	// Paths under an unexpired exemption skip the pipeline gates.
+	if e, ok := activeExemption(projectDir, filepath.ToSlash(rel), time.Now()); ok {
+		return GateCheckResult{Allowed: true, Reason: "exempt: " + e.Pattern + " until " + e.Until}
+	}
+
 	// Skills are always allowed
 	if strings.HasPrefix(rel, ".ptsd/skills/") {
 		return GateCheckResult{Allowed: true}
@@ -63,9 +107,8 @@ func GateCheck(projectDir, filePath string) GateCheckResult {
 	}
 
 	// BDD file → requires seed
-	if strings.HasPrefix(rel, ".ptsd/bdd/") && strings.HasSuffix(rel, ".feature") {
-		featureID := strings.TrimSuffix(filepath.Base(rel), ".feature")
-		seedPath := filepath.Join(projectDir, ".ptsd", "seeds", featureID, "seed.yaml")
+	if featureID := bddFileFeature(projectDir, rel); featureID != "" {
+		seedPath := seedManifestPath(projectDir, featureID)
 		if _, err := os.Stat(seedPath); os.IsNotExist(err) {
 			return GateCheckResult{
 				Allowed: false,
@@ -73,47 +116,44 @@ func GateCheck(projectDir, filePath string) GateCheckResult {
 				Feature: featureID,
 			}
 		}
-		return GateCheckResult{Allowed: true, Feature: featureID}
+		return stageGateResult(projectDir, featureID, "bdd")
 	}
 
 	// Seed file → requires PRD anchor
-	if strings.HasPrefix(rel, ".ptsd/seeds/") {
-		parts := strings.Split(rel, "/")
-		if len(parts) >= 3 {
-			featureID := parts[2] // .ptsd/seeds/<id>/...
-			anchors, err := extractAnchors(projectDir)
-			if err == nil {
-				found := false
-				for _, a := range anchors {
-					if a == featureID {
-						found = true
-						break
-					}
+	if featureID, _, ok := seedFileFeature(projectDir, rel); ok {
+		anchors, err := extractAnchors(projectDir)
+		if err == nil {
+			found := false
+			for _, a := range anchors {
+				if a == featureID {
+					found = true
+					break
 				}
-				if !found {
-					return GateCheckResult{
-						Allowed: false,
-						Reason:  "no PRD anchor for " + featureID,
-						Feature: featureID,
-					}
+			}
+			if !found {
+				return GateCheckResult{
+					Allowed: false,
+					Reason:  "no PRD anchor for " + featureID,
+					Feature: featureID,
 				}
 			}
-			return GateCheckResult{Allowed: true, Feature: featureID}
 		}
+		return stageGateResult(projectDir, featureID, "seed")
 	}
 
 	// Test file → requires BDD
 	if strings.HasSuffix(rel, "_test.go") || strings.HasSuffix(rel, ".test.ts") || strings.HasSuffix(rel, ".test.js") {
 		featureID := inferFeatureFromTestFile(projectDir, rel)
 		if featureID != "" {
-			bddPath := filepath.Join(projectDir, ".ptsd", "bdd", featureID+".feature")
-			if _, err := os.Stat(bddPath); os.IsNotExist(err) {
+			bddPath := bddFilePath(projectDir, featureID)
+			if _, err := os.Stat(bddPath); os.IsNotExist(err) && !isLiteFeature(projectDir, featureID) {
 				return GateCheckResult{
 					Allowed: false,
 					Reason:  "no BDD scenarios for " + featureID + " — run: ptsd bdd add " + featureID,
 					Feature: featureID,
 				}
 			}
+			return stageGateResult(projectDir, featureID, "tests")
 		}
 		return GateCheckResult{Allowed: true, Feature: featureID}
 	}
@@ -123,13 +163,14 @@ func GateCheck(projectDir, filePath string) GateCheckResult {
 		featureID := inferFeatureFromImplFile(projectDir, rel)
 		if featureID != "" {
 			state, _ := LoadState(projectDir)
-			if !hasTestsForFeature(projectDir, featureID, state) {
+			if !hasTestsForFeature(projectDir, featureID, state, ProjectWalkLimits(projectDir)) {
 				return GateCheckResult{
 					Allowed: false,
 					Reason:  "no tests for " + featureID,
 					Feature: featureID,
 				}
 			}
+			return stageGateResult(projectDir, featureID, "impl")
 		}
 		return GateCheckResult{Allowed: true, Feature: featureID}
 	}
@@ -137,6 +178,19 @@ func GateCheck(projectDir, filePath string) GateCheckResult {
 	return GateCheckResult{Allowed: true}
 }
 
+// stageGateResult allows a write for stage unless a custom stage configured
+// before it has not passed review, or, past BDD, a dependency of the feature
+// is not implemented.
+func stageGateResult(projectDir, featureID, stage string) GateCheckResult {
+	if blocked, reason := customStageGate(projectDir, featureID, stage); blocked {
+		return GateCheckResult{Allowed: false, Reason: reason, Feature: featureID}
+	}
+	if blocked, reason := dependencyGate(projectDir, featureID, stage); blocked {
+		return GateCheckResult{Allowed: false, Reason: reason, Feature: featureID}
+	}
+	return GateCheckResult{Allowed: true, Feature: featureID}
+}
+
 func inferFeatureFromTestFile(projectDir, rel string) string {
 	base := filepath.Base(rel)
 	// Strip test suffixes
@@ -217,3 +271,53 @@ func isImplFile(rel string) bool {
 	}
 	return implExts[ext]
 }
+
+// ShadowPrefix starts the detail of a gate event logged in shadow mode.
+const ShadowPrefix = "shadow: "
+
+// GatesShadowMode reports whether gates.mode is shadow. A missing or invalid
+// config enforces.
+func GatesShadowMode(projectDir string) bool {
+	cfg, err := LoadConfig(projectDir)
+	return err == nil && cfg.Gates.Mode == GatesShadow
+}
+
+// ShadowBlock counts the writes gate-check would have blocked for one
+// feature ("-" for files not tied to a feature) while gates.mode was shadow.
+type ShadowBlock struct {
+	Feature string
+	Count   int
+	Last    string // reason of the most recent would-be block
+}
+
+// ShadowBlocks summarizes the shadow-mode gate events in events.yaml, most
+// frequently blocked feature first.
+func ShadowBlocks(projectDir string) ([]ShadowBlock, error) {
+	events, err := LoadEvents(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var out []ShadowBlock
+	index := make(map[string]int)
+	for _, e := range events {
+		if e.Type != EventGate || !strings.HasPrefix(e.Detail, ShadowPrefix) {
+			continue
+		}
+		feature := e.Feature
+		if feature == "" {
+			feature = "-"
+		}
+		i, ok := index[feature]
+		if !ok {
+			i = len(out)
+			index[feature] = i
+			out = append(out, ShadowBlock{Feature: feature})
+		}
+		out[i].Count++
+		// Detail is "shadow: <file>: <reason>".
+		_, reason, _ := strings.Cut(strings.TrimPrefix(e.Detail, ShadowPrefix), ": ")
+		out[i].Last = reason
+	}
+	sort.SliceStable(out, func(a, b int) bool { return out[a].Count > out[b].Count })
+	return out, nil
+}
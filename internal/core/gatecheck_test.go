@@ -3,6 +3,7 @@ package core
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -234,3 +235,36 @@ func TestGateCheck_SettingsJSONAlwaysAllowed(t *testing.T) {
 		t.Errorf("expected .claude/settings.json to be always allowed, got blocked: %s", result.Reason)
 	}
 }
+
+func TestShadowBlocks(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "cart:in-progress")
+	AppendEvent(dir, Event{Type: EventGate, Feature: "cart", Detail: ShadowPrefix + "cart_test.go: no BDD scenarios for cart"})
+	AppendEvent(dir, Event{Type: EventGate, Feature: "auth", Detail: ShadowPrefix + "auth_test.go: no BDD scenarios for auth"})
+	AppendEvent(dir, Event{Type: EventGate, Feature: "auth", Detail: ShadowPrefix + "auth.go: no tests for auth"})
+	// Enforced blocks are not shadow blocks.
+	AppendEvent(dir, Event{Type: EventGate, Feature: "cart", Detail: "cart.go: no tests for cart"})
+
+	blocks, err := ShadowBlocks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 2 || blocks[0] != (ShadowBlock{Feature: "auth", Count: 2, Last: "no tests for auth"}) || blocks[1].Count != 1 {
+		t.Errorf("blocks = %+v", blocks)
+	}
+}
+
+func TestGatesModeConfig(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	cfgPath := filepath.Join(dir, ".ptsd", "ptsd.yaml")
+	if GatesShadowMode(dir) {
+		t.Error("gates must enforce by default")
+	}
+	os.WriteFile(cfgPath, []byte("gates:\n  mode: shadow\n"), 0644)
+	if !GatesShadowMode(dir) {
+		t.Error("gates.mode: shadow not read")
+	}
+	os.WriteFile(cfgPath, []byte("gates:\n  mode: loud\n"), 0644)
+	if _, err := LoadConfig(dir); err == nil || !strings.Contains(err.Error(), "gates.mode") {
+		t.Errorf("err = %v, want invalid gates.mode", err)
+	}
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// .gitignore management. ptsd owns one marker-delimited block in the project
+// .gitignore and never touches lines outside it.
+
+const (
+	ignoreBlockStart = "# ---ptsd--- managed by `ptsd config ignore`"
+	ignoreBlockEnd   = "# ---ptsd---"
+)
+
+// DefaultIgnoreEntries are the local-only artifacts ptsd writes under .ptsd/.
+var DefaultIgnoreEntries = []string{
+	".ptsd/hooks.log*",
+	".ptsd/snapshots/",
+	".ptsd/.history/",
+	".ptsd/.extends/",
+	".ptsd/daemon.sock",
+	".ptsd/.lock",
+	".ptsd/.*.tmp",
+	".ptsd/autotrack-*",
+	".ptsd/task-leases.*",
+}
+
+// readGitignore splits .gitignore into the lines before, inside and after
+// the managed block. found is false when there is no block.
+func readGitignore(dir string) (before, block, after []string, found bool, err error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if os.IsNotExist(err) {
+		return nil, nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, nil, false, fmt.Errorf("err:io %w", err)
+	}
+
+	content := strings.TrimRight(string(data), "\n")
+	if content == "" {
+		return nil, nil, nil, false, nil
+	}
+	state := 0 // 0 before, 1 inside, 2 after
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case state == 0 && line == ignoreBlockStart:
+			state, found = 1, true
+		case state == 1 && line == ignoreBlockEnd:
+			state = 2
+		case state == 0:
+			before = append(before, line)
+		case state == 1:
+			if strings.TrimSpace(line) != "" {
+				block = append(block, line)
+			}
+		default:
+			after = append(after, line)
+		}
+	}
+	return before, block, after, found, nil
+}
+
+func writeGitignore(dir string, before, block, after []string) error {
+	for len(before) > 0 && strings.TrimSpace(before[len(before)-1]) == "" {
+		before = before[:len(before)-1]
+	}
+	lines := append([]string{}, before...)
+	if len(block) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, ignoreBlockStart)
+		lines = append(lines, block...)
+		lines = append(lines, ignoreBlockEnd)
+	}
+	lines = append(lines, after...)
+	return writeFile(filepath.Join(dir, ".gitignore"), strings.Join(lines, "\n")+"\n")
+}
+
+// IgnoreEntries returns the patterns in the managed .gitignore block.
+func IgnoreEntries(dir string) ([]string, error) {
+	_, block, _, _, err := readGitignore(dir)
+	return block, err
+}
+
+// AddIgnoreEntries adds patterns to the managed .gitignore block, creating the
+// file or block as needed; no patterns means DefaultIgnoreEntries. Patterns
+// already present anywhere in .gitignore are skipped. Returns those added.
+func AddIgnoreEntries(dir string, patterns ...string) ([]string, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultIgnoreEntries
+	}
+	for _, p := range patterns {
+		if strings.TrimSpace(p) == "" || strings.HasPrefix(p, "#") || strings.ContainsAny(p, "\r\n") {
+			return nil, fmt.Errorf("err:user invalid ignore pattern %q", p)
+		}
+	}
+
+	before, block, after, _, err := readGitignore(dir)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool)
+	for _, lines := range [][]string{before, block, after} {
+		for _, line := range lines {
+			present[strings.TrimSpace(line)] = true
+		}
+	}
+
+	var added []string
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if present[p] {
+			continue
+		}
+		present[p] = true
+		block = append(block, p)
+		added = append(added, p)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+	return added, writeGitignore(dir, before, block, after)
+}
+
+// RemoveIgnoreEntry removes a pattern from the managed block; user lines
+// outside the block cannot be removed this way.
+func RemoveIgnoreEntry(dir, pattern string) error {
+	before, block, after, _, err := readGitignore(dir)
+	if err != nil {
+		return err
+	}
+	var kept []string
+	for _, line := range block {
+		if line != pattern {
+			kept = append(kept, line)
+		}
+	}
+	if len(kept) == len(block) {
+		return fmt.Errorf("err:user %q is not in the ptsd-managed .gitignore block", pattern)
+	}
+	return writeGitignore(dir, before, kept, after)
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddIgnoreEntriesKeepsUserLines(t *testing.T) {
+	dir := t.TempDir()
+	user := "node_modules/\n.ptsd/snapshots/\n*.log\n"
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte(user), 0644)
+
+	added, err := AddIgnoreEntries(dir)
+	if err != nil {
+		t.Fatalf("AddIgnoreEntries failed: %v", err)
+	}
+	// .ptsd/snapshots/ is already ignored by the user.
+	if len(added) != len(DefaultIgnoreEntries)-1 {
+		t.Errorf("added = %v", added)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	content := string(data)
+	if !strings.HasPrefix(content, user) {
+		t.Errorf("user lines must stay first and untouched:\n%s", content)
+	}
+	if strings.Count(content, ignoreBlockStart) != 1 || !strings.HasSuffix(content, ignoreBlockEnd+"\n") {
+		t.Errorf("expected one managed block at the end:\n%s", content)
+	}
+
+	// Second add is a no-op; a custom pattern goes into the existing block.
+	if added, _ := AddIgnoreEntries(dir); len(added) != 0 {
+		t.Errorf("re-adding defaults should add nothing, got %v", added)
+	}
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte(content+"dist/\n"), 0644)
+	if _, err := AddIgnoreEntries(dir, ".ptsd/runs/"); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := IgnoreEntries(dir)
+	if entries[len(entries)-1] != ".ptsd/runs/" {
+		t.Errorf("entries = %v", entries)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if !strings.HasSuffix(string(data), ignoreBlockEnd+"\ndist/\n") {
+		t.Errorf("lines after the block must be preserved:\n%s", data)
+	}
+}
+
+func TestRemoveIgnoreEntry(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.log\n"), 0644)
+	AddIgnoreEntries(dir, ".ptsd/daemon.sock")
+
+	if err := RemoveIgnoreEntry(dir, "*.log"); err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Errorf("user lines must not be removable, got %v", err)
+	}
+	if err := RemoveIgnoreEntry(dir, ".ptsd/daemon.sock"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if string(data) != "*.log\n" {
+		t.Errorf("empty block should be dropped, got %q", data)
+	}
+}
+
+func TestAddIgnoreEntriesRejectsComments(t *testing.T) {
+	if _, err := AddIgnoreEntries(t.TempDir(), "# nope"); err == nil {
+		t.Error("expected error for comment pattern")
+	}
+}
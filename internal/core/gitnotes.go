@@ -0,0 +1,109 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reviewNotesRef is the git notes ref holding review records (refs/notes/ptsd-reviews).
+const reviewNotesRef = "ptsd-reviews"
+
+// ReviewNote is one review record attached to a commit as a git note.
+// Each note line has the form:
+//
+//	ptsd-review feature=<id> stage=<stage> score=<n> verdict=<passed|failed> at=<RFC3339>
+type ReviewNote struct {
+	Commit  string
+	Feature string
+	Stage   string
+	Score   int
+	Verdict string
+	At      time.Time
+}
+
+func formatReviewNote(n ReviewNote) string {
+	return fmt.Sprintf("ptsd-review feature=%s stage=%s score=%d verdict=%s at=%s",
+		n.Feature, n.Stage, n.Score, n.Verdict, n.At.UTC().Format(time.RFC3339))
+}
+
+func parseReviewNote(line string) (ReviewNote, bool) {
+	if !strings.HasPrefix(line, "ptsd-review ") {
+		return ReviewNote{}, false
+	}
+	var n ReviewNote
+	for _, field := range strings.Fields(strings.TrimPrefix(line, "ptsd-review ")) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "feature":
+			n.Feature = value
+		case "stage":
+			n.Stage = value
+		case "score":
+			n.Score, _ = strconv.Atoi(value)
+		case "verdict":
+			n.Verdict = value
+		case "at":
+			n.At, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+	return n, n.Feature != ""
+}
+
+// appendReviewNote appends a review record to the note on HEAD.
+func appendReviewNote(projectDir string, n ReviewNote) error {
+	cmd := exec.Command("git", "notes", "--ref="+reviewNotesRef, "append", "-m", formatReviewNote(n), "HEAD")
+	cmd.Dir = projectDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("err:git notes append failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// LoadReviewNotes returns all review records stored in git notes, optionally
+// filtered by feature, sorted by time.
+func LoadReviewNotes(projectDir string, featureID string) ([]ReviewNote, error) {
+	list := exec.Command("git", "notes", "--ref="+reviewNotesRef, "list")
+	list.Dir = projectDir
+	out, err := list.Output()
+	if err != nil {
+		// No notes ref yet (or not a git repo) — nothing recorded.
+		if _, statErr := exec.LookPath("git"); statErr != nil {
+			return nil, fmt.Errorf("err:git git not found")
+		}
+		return nil, nil
+	}
+
+	var notes []ReviewNote
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) != 2 {
+			continue
+		}
+		commit := parts[1]
+
+		show := exec.Command("git", "notes", "--ref="+reviewNotesRef, "show", commit)
+		show.Dir = projectDir
+		body, err := show.Output()
+		if err != nil {
+			continue
+		}
+		for _, noteLine := range strings.Split(string(body), "\n") {
+			n, ok := parseReviewNote(strings.TrimSpace(noteLine))
+			if !ok || (featureID != "" && n.Feature != featureID) {
+				continue
+			}
+			n.Commit = commit
+			notes = append(notes, n)
+		}
+	}
+
+	sort.SliceStable(notes, func(i, j int) bool { return notes[i].At.Before(notes[j].At) })
+	return notes, nil
+}
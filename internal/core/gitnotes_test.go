@@ -0,0 +1,81 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseReviewNote(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	line := formatReviewNote(ReviewNote{Feature: "auth", Stage: "bdd", Score: 8, Verdict: "passed", At: at})
+
+	n, ok := parseReviewNote(line)
+	if !ok {
+		t.Fatalf("failed to parse %q", line)
+	}
+	if n.Feature != "auth" || n.Stage != "bdd" || n.Score != 8 || n.Verdict != "passed" || !n.At.Equal(at) {
+		t.Errorf("unexpected note: %+v", n)
+	}
+
+	if _, ok := parseReviewNote("unrelated note"); ok {
+		t.Error("expected non-ptsd line to be ignored")
+	}
+}
+
+func TestRecordReviewWritesGitNote(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	for k, v := range map[string]string{
+		"GIT_AUTHOR_NAME": "test", "GIT_AUTHOR_EMAIL": "test@test.com",
+		"GIT_COMMITTER_NAME": "test", "GIT_COMMITTER_EMAIL": "test@test.com",
+	} {
+		t.Setenv(k, v)
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init")
+	git("commit", "--allow-empty", "-m", "init")
+
+	cfg := "project:\n  name: Notes\nreview:\n  git_notes: true\n"
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte(cfg), 0644)
+
+	if err := RecordReview(dir, "auth", "prd", 9); err != nil {
+		t.Fatalf("RecordReview failed: %v", err)
+	}
+	if err := RecordReview(dir, "auth", "seed", 4); err != nil {
+		t.Fatalf("RecordReview failed: %v", err)
+	}
+
+	notes, err := LoadReviewNotes(dir, "auth")
+	if err != nil {
+		t.Fatalf("LoadReviewNotes failed: %v", err)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 notes, got %d: %+v", len(notes), notes)
+	}
+	if notes[0].Stage != "prd" || notes[0].Verdict != "passed" || notes[0].Commit == "" {
+		t.Errorf("unexpected first note: %+v", notes[0])
+	}
+	if notes[1].Score != 4 || notes[1].Verdict != "failed" {
+		t.Errorf("unexpected second note: %+v", notes[1])
+	}
+}
+
+func TestLoadReviewNotesNoRepo(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	notes, err := LoadReviewNotes(dir, "")
+	if err != nil {
+		t.Fatalf("expected no error outside a repo, got %v", err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("expected no notes, got %+v", notes)
+	}
+}
@@ -0,0 +1,157 @@
+package core
+
+import "fmt"
+
+// GraphNode is a node in the task/feature dependency graph.
+type GraphNode struct {
+	ID    string
+	Kind  string // feature | task
+	Label string
+}
+
+// GraphEdge links a feature to one of its tasks. Blocked edges mark tasks held
+// back by the pipeline gate (feature has not reached impl).
+type GraphEdge struct {
+	From    string
+	To      string
+	Label   string
+	Blocked bool
+}
+
+type TaskGraph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+}
+
+// BuildTaskGraph builds the dependency graph of open (TODO/WIP) tasks and their features.
+func BuildTaskGraph(projectDir string) (TaskGraph, error) {
+	tasks, err := loadTasks(projectDir)
+	if err != nil {
+		return TaskGraph{}, err
+	}
+
+	state, _ := LoadState(projectDir)
+	waiting := waitingOnDependencies(projectDir)
+
+	var g TaskGraph
+	seen := make(map[string]bool)
+
+	for _, t := range tasks {
+		if t.Status != "TODO" && t.Status != "WIP" {
+			continue
+		}
+
+		if t.Feature != "" && !seen[t.Feature] {
+			seen[t.Feature] = true
+			label := t.Feature
+			if stage := featureStage(state, t.Feature); stage != "" {
+				label += " (" + stage + ")"
+			}
+			if blocked, reason := checkPrerequisite(projectDir, t.Feature, featureStage(state, t.Feature)); blocked {
+				label += " — " + reason
+			}
+			g.Nodes = append(g.Nodes, GraphNode{ID: t.Feature, Kind: "feature", Label: label})
+		}
+
+		g.Nodes = append(g.Nodes, GraphNode{
+			ID:    t.ID,
+			Kind:  "task",
+			Label: t.ID + " [" + t.Status + "] " + t.Title,
+		})
+
+		if t.Feature == "" {
+			continue
+		}
+		edge := GraphEdge{From: t.Feature, To: t.ID}
+		if !taskUnblocked(t, state, waiting) {
+			edge.Blocked = true
+			edge.Label = "blocked at " + featureStage(state, t.Feature)
+			if waiting[t.Feature] {
+				edge.Label = "waiting on dependencies"
+			}
+		}
+		g.Edges = append(g.Edges, edge)
+	}
+
+	return g, nil
+}
+
+func featureStage(state *State, featureID string) string {
+	if state == nil {
+		return ""
+	}
+	return state.Features[featureID].Stage
+}
+
+// PipelineStage is one stage of a feature's pipeline with its state:
+// done, current, blocked, or pending.
+type PipelineStage struct {
+	Stage  string
+	State  string
+	Reason string
+}
+
+// FeaturePipeline reports the state of every pipeline stage for a feature.
+func FeaturePipeline(projectDir, featureID string) ([]PipelineStage, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, f := range features {
+		if f.ID == featureID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("err:validation feature %s not found", featureID)
+	}
+
+	rs, err := loadReviewStatus(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	current, review := "", "pending"
+	if entry, ok := rs[featureID]; ok {
+		current, review = entry.Stage, entry.Review
+	}
+	if current == "" {
+		state, _ := LoadState(projectDir)
+		current = featureStage(state, featureID)
+	}
+	if current == "" {
+		current = ComputeStageFromArtifacts(projectDir, featureID)
+	}
+	if current == "" {
+		current = "prd"
+	}
+
+	blocked, reason := checkPrerequisite(projectDir, featureID, current)
+	if review == "failed" {
+		blocked, reason = true, "review failed"
+	}
+
+	stages := FeatureStages(projectDir, featureID)
+	var out []PipelineStage
+	for _, s := range stages {
+		ps := PipelineStage{Stage: s}
+		switch {
+		case stageRank(stages, s) < stageRank(stages, current):
+			ps.State = "done"
+		case s == current && s == "impl" && review == "passed":
+			ps.State = "done"
+		case s == current && blocked:
+			ps.State = "blocked"
+			ps.Reason = reason
+		case s == current:
+			ps.State = "current"
+		default:
+			ps.State = "pending"
+		}
+		out = append(out, ps)
+	}
+
+	return out, nil
+}
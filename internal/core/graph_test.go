@@ -0,0 +1,102 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildTaskGraph(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "auth", "billing")
+	setupTasks(t, dir,
+		Task{ID: "T-1", Feature: "auth", Title: "Login", Status: "TODO", Priority: "A"},
+		Task{ID: "T-2", Feature: "billing", Title: "Invoices", Status: "WIP", Priority: "B"},
+		Task{ID: "T-3", Feature: "auth", Title: "Old", Status: "DONE", Priority: "B"},
+	)
+	stateContent := "features:\n  auth:\n    stage: impl\n  billing:\n    stage: bdd\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte(stateContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := BuildTaskGraph(dir)
+	if err != nil {
+		t.Fatalf("BuildTaskGraph failed: %v", err)
+	}
+
+	if len(g.Nodes) != 4 {
+		t.Fatalf("expected 4 nodes (2 features, 2 open tasks), got %d: %+v", len(g.Nodes), g.Nodes)
+	}
+	if len(g.Edges) != 2 {
+		t.Fatalf("expected 2 edges, got %d", len(g.Edges))
+	}
+
+	for _, e := range g.Edges {
+		switch e.To {
+		case "T-1":
+			if e.Blocked {
+				t.Errorf("T-1 should not be blocked (auth at impl)")
+			}
+		case "T-2":
+			if !e.Blocked || e.Label != "blocked at bdd" {
+				t.Errorf("T-2 should be blocked at bdd, got %+v", e)
+			}
+		default:
+			t.Errorf("unexpected edge %+v", e)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if n.ID == "billing" && n.Label != "billing (bdd) — missing seed" {
+			t.Errorf("unexpected billing label %q", n.Label)
+		}
+	}
+}
+
+func TestFeaturePipeline(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	rs := "features:\n  auth:\n    stage: bdd\n    tests: absent\n    review: pending\n    issues: 0\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "review-status.yaml"), []byte(rs), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stages, err := FeaturePipeline(dir, "auth")
+	if err != nil {
+		t.Fatalf("FeaturePipeline failed: %v", err)
+	}
+
+	want := []string{"done", "done", "blocked", "pending", "pending"}
+	for i, s := range stages {
+		if s.State != want[i] {
+			t.Errorf("stage %s: expected %s, got %s", s.Stage, want[i], s.State)
+		}
+	}
+	if stages[2].Reason != "missing seed" {
+		t.Errorf("expected missing seed reason, got %q", stages[2].Reason)
+	}
+}
+
+func TestFeaturePipelineImplPassed(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	rs := "features:\n  auth:\n    stage: impl\n    tests: written\n    review: passed\n    issues: 0\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "review-status.yaml"), []byte(rs), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stages, err := FeaturePipeline(dir, "auth")
+	if err != nil {
+		t.Fatalf("FeaturePipeline failed: %v", err)
+	}
+	for _, s := range stages {
+		if s.State != "done" {
+			t.Errorf("expected all stages done, %s is %s", s.Stage, s.State)
+		}
+	}
+}
+
+func TestFeaturePipelineUnknownFeature(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	if _, err := FeaturePipeline(dir, "ghost"); err == nil {
+		t.Fatal("expected error for unknown feature")
+	}
+}
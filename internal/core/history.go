@@ -0,0 +1,211 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// historyFiles are the .ptsd data files recorded before each mutating
+// command and put back by `ptsd undo`. Logs (events, hooks, review-log,
+// signatures) only grow and are left alone.
+var historyFiles = []string{
+	"ptsd.yaml", "features.yaml", "state.yaml", "review-status.yaml",
+	"tasks.yaml", "issues.yaml", "validate-baseline.yaml",
+	"gate-exemptions.yaml",
+}
+
+// HistoryLimit is how many history entries are kept; older ones are pruned.
+const HistoryLimit = 50
+
+const historyIDLayout = "20060102-150405.000000000"
+
+// HistoryEntry is one recorded state of the data files, taken before
+// Command ran.
+type HistoryEntry struct {
+	ID      string
+	Command string
+	Created time.Time
+	Files   []string
+}
+
+func historyDir(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", ".history")
+}
+
+// BeginHistory copies the data files into a new .ptsd/.history entry before
+// command runs. The returned finish drops the entry again if the command
+// changed none of the files, and prunes entries beyond HistoryLimit.
+func BeginHistory(projectDir, command string) (finish func(), err error) {
+	dir, err := recordHistory(projectDir, command)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		if unchangedSince(projectDir, dir) {
+			os.RemoveAll(dir)
+			return
+		}
+		pruneHistory(projectDir)
+	}, nil
+}
+
+func recordHistory(projectDir, command string) (string, error) {
+	ptsdDir := filepath.Join(projectDir, ".ptsd")
+	if _, err := os.Stat(ptsdDir); err != nil {
+		return "", fmt.Errorf("err:config .ptsd not found")
+	}
+	if err := os.MkdirAll(historyDir(projectDir), 0755); err != nil {
+		return "", fmt.Errorf("err:io %w", err)
+	}
+
+	var dir string
+	for {
+		dir = filepath.Join(historyDir(projectDir), time.Now().UTC().Format(historyIDLayout))
+		err := os.Mkdir(dir, 0755)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return "", fmt.Errorf("err:io %w", err)
+		}
+	}
+
+	for _, name := range historyFiles {
+		data, err := os.ReadFile(filepath.Join(ptsdDir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err == nil {
+			err = writeFileAtomic(filepath.Join(dir, name), data, 0644)
+		}
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("err:io %w", err)
+		}
+	}
+	if err := writeFileAtomic(filepath.Join(dir, "command"), []byte(command+"\n"), 0644); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("err:io %w", err)
+	}
+	return dir, nil
+}
+
+// unchangedSince reports whether every data file is as recorded in the
+// history entry dir, including files that did not exist then.
+func unchangedSince(projectDir, dir string) bool {
+	for _, name := range historyFiles {
+		then, errThen := os.ReadFile(filepath.Join(dir, name))
+		now, errNow := os.ReadFile(filepath.Join(projectDir, ".ptsd", name))
+		if os.IsNotExist(errThen) && os.IsNotExist(errNow) {
+			continue
+		}
+		if errThen != nil || errNow != nil || !bytes.Equal(then, now) {
+			return false
+		}
+	}
+	return true
+}
+
+func pruneHistory(projectDir string) {
+	entries, err := ListHistory(projectDir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries[min(len(entries), HistoryLimit):] {
+		os.RemoveAll(filepath.Join(historyDir(projectDir), e.ID))
+	}
+}
+
+// ListHistory returns the history entries, newest first: entry 1 is the
+// state before the last command that changed anything.
+func ListHistory(projectDir string) ([]HistoryEntry, error) {
+	dirs, err := os.ReadDir(historyDir(projectDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+
+	var entries []HistoryEntry
+	for _, d := range dirs {
+		created, err := time.Parse(historyIDLayout, d.Name())
+		if !d.IsDir() || err != nil {
+			continue
+		}
+		e := HistoryEntry{ID: d.Name(), Created: created}
+		files, err := os.ReadDir(filepath.Join(historyDir(projectDir), d.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("err:io %w", err)
+		}
+		for _, f := range files {
+			if f.Name() == "command" {
+				data, _ := os.ReadFile(filepath.Join(historyDir(projectDir), d.Name(), "command"))
+				e.Command = strings.TrimSpace(string(data))
+			} else {
+				e.Files = append(e.Files, f.Name())
+			}
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID > entries[j].ID })
+	return entries, nil
+}
+
+// Undo puts the data files back as they were before the n most recent
+// recorded commands, and returns those entries, newest first. The state it
+// replaces becomes a new entry, so undoing again redoes. Signed files are
+// re-signed as they are put back.
+func Undo(projectDir string, n int) ([]HistoryEntry, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("err:user undo count must be at least 1")
+	}
+	entries, err := ListHistory(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("err:user nothing to undo: .ptsd/.history is empty")
+	}
+	if n > len(entries) {
+		return nil, fmt.Errorf("err:user only %d history entries (see ptsd history)", len(entries))
+	}
+	target := filepath.Join(historyDir(projectDir), entries[n-1].ID)
+
+	if _, err := recordHistory(projectDir, fmt.Sprintf("undo %d", n)); err != nil {
+		return nil, err
+	}
+	for _, name := range historyFiles {
+		dest := filepath.Join(projectDir, ".ptsd", name)
+		data, err := os.ReadFile(filepath.Join(target, name))
+		switch {
+		case os.IsNotExist(err):
+			err = os.Remove(dest)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+			if err == nil && slices.Contains(signedFiles, name) {
+				err = dropSignature(projectDir, name)
+			}
+		case err == nil && slices.Contains(signedFiles, name):
+			// Restored through the signed write so verify-log still passes.
+			err = signFileWrite(projectDir, name, data)
+		case err == nil:
+			err = writeFileAtomic(dest, data, 0644)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("err:io %w", err)
+		}
+	}
+
+	for _, e := range entries[:n] {
+		os.RemoveAll(filepath.Join(historyDir(projectDir), e.ID))
+	}
+	return entries[:n], nil
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHistoryUndoRedo(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:planned")
+	ptsdDir := filepath.Join(dir, ".ptsd")
+
+	finish, err := BeginHistory(dir, "task add auth Login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(ptsdDir, "tasks.yaml"), []byte("tasks:\n  - id: T-1\n"), 0644)
+	finish()
+
+	// A command that changes nothing leaves no entry.
+	finish, err = BeginHistory(dir, "validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	finish()
+
+	entries, err := ListHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Command != "task add auth Login" {
+		t.Fatalf("expected one entry for task add, got %+v", entries)
+	}
+
+	undone, err := Undo(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(undone) != 1 || undone[0].Command != "task add auth Login" {
+		t.Errorf("unexpected undone entries %+v", undone)
+	}
+	if _, err := os.Stat(filepath.Join(ptsdDir, "tasks.yaml")); !os.IsNotExist(err) {
+		t.Error("undo must remove tasks.yaml, which did not exist before task add")
+	}
+
+	// Undoing the undo brings the task back.
+	if _, err := Undo(dir, 1); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(ptsdDir, "tasks.yaml"))
+	if err != nil || !strings.Contains(string(data), "T-1") {
+		t.Errorf("expected redo to restore tasks.yaml, got %q (%v)", data, err)
+	}
+}
+
+func TestUndoErrors(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	if _, err := Undo(dir, 1); err == nil || !strings.Contains(err.Error(), "nothing to undo") {
+		t.Errorf("expected nothing to undo, got %v", err)
+	}
+	if _, err := recordHistory(dir, "feature add x X"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Undo(dir, 2); err == nil || !strings.HasPrefix(err.Error(), "err:user only 1") {
+		t.Errorf("expected count error, got %v", err)
+	}
+}
+
+func TestUndoAfterSnapshotRestore(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:planned")
+	tasksPath := filepath.Join(dir, ".ptsd", "tasks.yaml")
+	os.WriteFile(tasksPath, []byte("tasks: []\n"), 0644)
+	if _, err := CreateSnapshot(dir, "empty"); err != nil {
+		t.Fatal(err)
+	}
+
+	finish, err := BeginHistory(dir, "task add auth Login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(tasksPath, []byte("tasks:\n  - id: T-1\n"), 0644)
+	finish()
+
+	finish, err = BeginHistory(dir, "snapshot restore empty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreSnapshot(dir, "empty"); err != nil {
+		t.Fatal(err)
+	}
+	finish()
+
+	undone, err := Undo(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if undone[0].Command != "snapshot restore empty" {
+		t.Errorf("undo after restore undid %q", undone[0].Command)
+	}
+	if data, _ := os.ReadFile(tasksPath); !strings.Contains(string(data), "T-1") {
+		t.Errorf("undo did not bring back the pre-restore tasks: %q", data)
+	}
+}
@@ -0,0 +1,156 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hookLogMaxSize is the size at which hooks.log is rotated to hooks.log.1.
+const hookLogMaxSize = 256 * 1024
+
+// HookLogEntry is one hook invocation recorded in .ptsd/hooks.log.
+type HookLogEntry struct {
+	At       time.Time
+	Hook     string // pre-tool-use | post-tool-use | validate-commit
+	File     string
+	Verdict  string // allow | block | shadow | tracked | skip | error | ok
+	Duration time.Duration
+	Reason   string
+}
+
+func hookLogPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "hooks.log")
+}
+
+// formatHookLogEntry writes an entry as tab-separated fields, so file paths
+// and reasons may contain spaces.
+func formatHookLogEntry(e HookLogEntry) string {
+	line := fmt.Sprintf("%s\thook=%s\tverdict=%s\tduration=%dms",
+		e.At.UTC().Format(time.RFC3339), e.Hook, e.Verdict, e.Duration.Milliseconds())
+	if e.File != "" {
+		line += "\tfile=" + hookLogField(e.File)
+	}
+	if e.Reason != "" {
+		line += "\treason=\"" + hookLogField(strings.TrimSpace(e.Reason)) + "\""
+	}
+	return line
+}
+
+// hookLogField keeps a value on one line and inside its field.
+func hookLogField(s string) string {
+	return strings.NewReplacer("\n", " ", "\t", " ").Replace(s)
+}
+
+// AppendHookLog appends an entry to .ptsd/hooks.log, rotating the file to
+// hooks.log.1 once it exceeds hookLogMaxSize. Logging never fails a hook:
+// errors are returned for tests but callers may ignore them.
+func AppendHookLog(projectDir string, e HookLogEntry) error {
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+
+	path := hookLogPath(projectDir)
+	if _, err := os.Stat(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("err:config .ptsd not found")
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() >= hookLogMaxSize {
+		os.Rename(path, path+".1")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(formatHookLogEntry(e) + "\n"); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// TailHookLog returns the last n lines of hooks.log (n <= 0 returns all).
+func TailHookLog(projectDir string, n int) ([]string, error) {
+	data, err := os.ReadFile(hookLogPath(projectDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+
+	content := strings.TrimRight(string(data), "\n")
+	if content == "" {
+		return nil, nil
+	}
+	lines := strings.Split(content, "\n")
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+// LoadHookLog parses hooks.log.1 and hooks.log, oldest first. Lines that do
+// not parse are skipped; a missing log means no entries.
+func LoadHookLog(projectDir string) ([]HookLogEntry, error) {
+	var out []HookLogEntry
+	for _, path := range []string{hookLogPath(projectDir) + ".1", hookLogPath(projectDir)} {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("err:io %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if e, ok := parseHookLogLine(line); ok {
+				out = append(out, e)
+			}
+		}
+	}
+	return out, nil
+}
+
+// parseHookLogLine reverses formatHookLogEntry. Lines written before fields
+// were tab-separated are split on spaces.
+func parseHookLogLine(line string) (HookLogEntry, bool) {
+	var fields []string
+	reason, hasReason := "", false
+	if strings.Contains(line, "\t") {
+		fields = strings.Split(line, "\t")
+	} else {
+		line, reason, hasReason = strings.Cut(line, ` reason="`)
+		fields = strings.Fields(line)
+	}
+	if len(fields) < 2 {
+		return HookLogEntry{}, false
+	}
+	at, err := time.Parse(time.RFC3339, fields[0])
+	if err != nil {
+		return HookLogEntry{}, false
+	}
+	e := HookLogEntry{At: at}
+	if hasReason {
+		e.Reason = strings.TrimSuffix(reason, `"`)
+	}
+	for _, f := range fields[1:] {
+		key, val, _ := strings.Cut(f, "=")
+		switch key {
+		case "hook":
+			e.Hook = val
+		case "verdict":
+			e.Verdict = val
+		case "file":
+			e.File = val
+		case "reason":
+			e.Reason = strings.TrimSuffix(strings.TrimPrefix(val, `"`), `"`)
+		case "duration":
+			ms, _ := strconv.Atoi(strings.TrimSuffix(val, "ms"))
+			e.Duration = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return e, e.Hook != ""
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendHookLogAndTail(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+
+	for i, verdict := range []string{"allow", "block", "tracked"} {
+		err := AppendHookLog(dir, HookLogEntry{
+			Hook:     "pre-tool-use",
+			File:     "src/a.go",
+			Verdict:  verdict,
+			Duration: time.Duration(i) * time.Millisecond,
+			Reason:   "reason\nsecond line",
+		})
+		if err != nil {
+			t.Fatalf("AppendHookLog failed: %v", err)
+		}
+	}
+
+	lines, err := TailHookLog(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], "verdict=block") || !strings.Contains(lines[0], "duration=1ms") {
+		t.Errorf("unexpected line: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `reason="reason second line"`) {
+		t.Errorf("expected single-line reason, got: %s", lines[1])
+	}
+}
+
+func TestAppendHookLogRotates(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	path := hookLogPath(dir)
+	os.WriteFile(path, []byte(strings.Repeat("x", hookLogMaxSize)), 0644)
+
+	if err := AppendHookLog(dir, HookLogEntry{Hook: "post-tool-use", Verdict: "skip"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("expected rotated hooks.log.1: %v", err)
+	}
+	lines, _ := TailHookLog(dir, 0)
+	if len(lines) != 1 {
+		t.Errorf("expected fresh log with 1 line, got %d", len(lines))
+	}
+}
+
+func TestTailHookLogMissing(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	lines, err := TailHookLog(dir, 10)
+	if err != nil || len(lines) != 0 {
+		t.Errorf("expected empty result, got %v %v", lines, err)
+	}
+}
+
+func TestLoadHookLogPathWithSpaces(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	// A line from before fields were tab-separated still loads.
+	os.WriteFile(hookLogPath(dir), []byte("2026-01-05T09:00:00Z hook=pre-tool-use verdict=allow duration=1ms file=src/a.go\n"), 0644)
+	entry := HookLogEntry{Hook: "pre-tool-use", File: "src/my docs/a b.go", Verdict: "block", Duration: 3 * time.Millisecond, Reason: "gate: seed\tmissing"}
+	if err := AppendHookLog(dir, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := LoadHookLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].File != "src/a.go" || entries[0].Verdict != "allow" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	got := entries[1]
+	if got.File != "src/my docs/a b.go" || got.Verdict != "block" || got.Duration != 3*time.Millisecond || got.Reason != "gate: seed missing" {
+		t.Errorf("path with spaces shifted the fields: %+v", got)
+	}
+}
@@ -8,6 +8,13 @@ import (
 	"strings"
 )
 
+// DefaultCommitScopes are the commit scopes staged files are classified into.
+// hooks.scopes in ptsd.yaml may narrow this list but not extend it.
+var DefaultCommitScopes = []string{"PRD", "SEED", "BDD", "TEST", "IMPL", "TASK", "STATUS"}
+
+// DefaultCommitTypes are the commit types accepted when hooks.types is unset.
+var DefaultCommitTypes = []string{"feat", "add", "fix", "refactor", "remove", "update"}
+
 var validScopes = map[string]bool{
 	"PRD":    true,
 	"SEED":   true,
@@ -18,13 +25,23 @@ var validScopes = map[string]bool{
 	"STATUS": true,
 }
 
-var validCommitTypes = map[string]bool{
-	"feat":     true,
-	"add":      true,
-	"fix":      true,
-	"refactor": true,
-	"remove":   true,
-	"update":   true,
+// CommitScopes returns the configured commit scopes (hooks.scopes), falling back
+// to DefaultCommitScopes. ValidateCommit, the commit-msg hook, and CLAUDE.md all
+// read this list.
+func CommitScopes(projectDir string) []string {
+	if cfg, err := LoadConfig(projectDir); err == nil && len(cfg.Hooks.Scopes) > 0 {
+		return cfg.Hooks.Scopes
+	}
+	return DefaultCommitScopes
+}
+
+// CommitTypes returns the configured commit types (hooks.types), falling back
+// to DefaultCommitTypes.
+func CommitTypes(projectDir string) []string {
+	if cfg, err := LoadConfig(projectDir); err == nil && len(cfg.Hooks.Types) > 0 {
+		return cfg.Hooks.Types
+	}
+	return DefaultCommitTypes
 }
 
 func ptsdBinaryPath() string {
@@ -34,38 +51,89 @@ func ptsdBinaryPath() string {
 	return "ptsd"
 }
 
-func GeneratePreCommitHook(projectDir string) error {
-	hookDir := filepath.Join(projectDir, ".git", "hooks")
-	if err := os.MkdirAll(hookDir, 0755); err != nil {
-		return fmt.Errorf("err:io %w", err)
-	}
+// gitHookMarker tags git hooks written by ptsd so deinit can tell them from
+// hooks the user installed.
+const gitHookMarker = "# managed by ptsd"
 
-	bin := ptsdBinaryPath()
-	hookContent := "#!/bin/sh\n" + bin + " validate\n"
-	hookPath := filepath.Join(hookDir, "pre-commit")
-	if err := os.WriteFile(hookPath, []byte(hookContent), 0755); err != nil {
-		return fmt.Errorf("err:io %w", err)
+// gitHookBackupSuffix names the copy of a user's hook that ptsd replaced;
+// deinit moves it back.
+const gitHookBackupSuffix = ".ptsd-backup"
+
+// isPtsdGitHook reports whether content is a hook ptsd generated, including
+// the unmarked two-line hooks written by older versions.
+func isPtsdGitHook(content string) bool {
+	if strings.Contains(content, gitHookMarker) {
+		return true
 	}
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	if len(lines) != 2 || lines[0] != "#!/bin/sh" {
+		return false
+	}
+	for _, suffix := range []string{" validate", " hooks validate-commit --msg-file \"$1\"", " hooks pre-push"} {
+		if strings.HasSuffix(lines[1], suffix) {
+			return true
+		}
+	}
+	return false
+}
 
+// gitHookScript runs a ptsd subcommand from a git hook. The script first
+// asks the binary to confirm it still exists and understands this schema, so
+// a moved or downgraded ptsd stops the commit with a re-install instruction
+// instead of "not found".
+func gitHookScript(bin, command string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s
+PTSD_BIN='%s'
+if [ ! -x "$PTSD_BIN" ] || ! "$PTSD_BIN" verify-hooks --schema %d >/dev/null 2>&1; then
+  echo "err:config ptsd git hooks run $PTSD_BIN, which is missing or older than schema %d; install ptsd, then run: ptsd hooks install" >&2
+  exit 1
+fi
+"$PTSD_BIN" %s
+`, gitHookMarker, bin, SchemaVersion, SchemaVersion, command)
+}
+
+// CheckHookSchema is what generated hooks run before anything else: it fails
+// when they were written for a newer schema than this binary supports.
+func CheckHookSchema(required int) error {
+	if required > SchemaVersion {
+		return fmt.Errorf("err:config hooks need schema %d but %s supports schema %d; install a newer ptsd, then run: ptsd hooks install && ptsd init --force", required, ptsdBinaryPath(), SchemaVersion)
+	}
 	return nil
 }
 
-func GenerateCommitMsgHook(projectDir string) error {
+// installGitHook writes .git/hooks/<name> running the given ptsd subcommand.
+// A hook ptsd did not write is first saved as <name>.ptsd-backup (once).
+func installGitHook(projectDir, name, command string) error {
 	hookDir := filepath.Join(projectDir, ".git", "hooks")
 	if err := os.MkdirAll(hookDir, 0755); err != nil {
 		return fmt.Errorf("err:io %w", err)
 	}
 
-	bin := ptsdBinaryPath()
-	hookContent := "#!/bin/sh\n" + bin + " hooks validate-commit --msg-file \"$1\"\n"
-	hookPath := filepath.Join(hookDir, "commit-msg")
-	if err := os.WriteFile(hookPath, []byte(hookContent), 0755); err != nil {
-		return fmt.Errorf("err:io %w", err)
+	hookPath := filepath.Join(hookDir, name)
+	backupPath := hookPath + gitHookBackupSuffix
+	if cur, err := os.ReadFile(hookPath); err == nil && !isPtsdGitHook(string(cur)) {
+		if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+			if err := os.Rename(hookPath, backupPath); err != nil {
+				return fmt.Errorf("err:io %w", err)
+			}
+		}
 	}
 
+	if err := writeFileAtomic(hookPath, []byte(gitHookScript(ptsdBinaryPath(), command)), 0755); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
 	return nil
 }
 
+func GeneratePreCommitHook(projectDir string) error {
+	return installGitHook(projectDir, "pre-commit", "validate")
+}
+
+func GenerateCommitMsgHook(projectDir string) error {
+	return installGitHook(projectDir, "commit-msg", "hooks validate-commit --msg-file \"$1\"")
+}
+
 func ValidateCommitFromFile(projectDir, msgFile string) error {
 	data, err := os.ReadFile(msgFile)
 	if err != nil {
@@ -105,12 +173,13 @@ func ValidateCommit(projectDir string, message string, stagedFiles []string) err
 		return err
 	}
 
-	if !validScopes[scope] {
+	if !containsString(CommitScopes(projectDir), scope) {
 		return fmt.Errorf("err:git unknown scope %s", scope)
 	}
 
-	if commitType != "" && !validCommitTypes[commitType] {
-		return fmt.Errorf("err:git invalid commit type %q: must be feat|add|fix|refactor|remove|update", commitType)
+	types := CommitTypes(projectDir)
+	if commitType != "" && !containsString(types, commitType) {
+		return fmt.Errorf("err:git invalid commit type %q: must be %s", commitType, strings.Join(types, "|"))
 	}
 
 	if scope == "TASK" || scope == "STATUS" {
@@ -127,7 +196,7 @@ func ValidateCommit(projectDir string, message string, stagedFiles []string) err
 
 	// IMPL is the final pipeline stage — trigger full validation
 	if scope == "IMPL" {
-		validationErrors, err := Validate(projectDir)
+		validationErrors, _, err := ValidateWithCache(projectDir, ProjectWalkLimits(projectDir))
 		if err != nil {
 			return fmt.Errorf("err:pipeline %w", err)
 		}
@@ -149,22 +218,26 @@ func ClassifyFile(projectDir string, path string) (string, error) {
 		return "STATUS", nil
 	}
 
+	// Pipeline artifacts, wherever paths: in ptsd.yaml puts them
+	switch {
+	case isPRDFile(projectDir, path) || strings.HasPrefix(path, prdDirRel(projectDir)+"/"):
+		return "PRD", nil
+	case strings.HasPrefix(path, seedsDirRel(projectDir)+"/"):
+		return "SEED", nil
+	case strings.HasPrefix(path, bddDirRel(projectDir)+"/"):
+		return "BDD", nil
+	}
+
 	// .ptsd/ internal files
 	if strings.HasPrefix(path, ".ptsd/") {
 		switch {
-		case strings.HasPrefix(path, ".ptsd/docs/"):
-			return "PRD", nil
-		case strings.HasPrefix(path, ".ptsd/seeds/"):
-			return "SEED", nil
-		case strings.HasPrefix(path, ".ptsd/bdd/"):
-			return "BDD", nil
 		case path == ".ptsd/tasks.yaml":
 			return "TASK", nil
-		case path == ".ptsd/state.yaml" || path == ".ptsd/review-status.yaml":
+		case path == ".ptsd/state.yaml" || path == ".ptsd/review-status.yaml" || path == ".ptsd/"+reviewLogFile || path == ".ptsd/events.yaml" || path == ".ptsd/remote-sync.yaml" || path == ".ptsd/signatures.yaml" || strings.HasPrefix(path, ".ptsd/hooks.log") || strings.HasPrefix(path, ".ptsd/autotrack-") || path == ".ptsd/daemon.sock":
 			return "STATUS", nil
-		case path == ".ptsd/features.yaml" || path == ".ptsd/ptsd.yaml" || path == ".ptsd/issues.yaml":
+		case path == ".ptsd/features.yaml" || path == ".ptsd/ptsd.yaml" || path == ".ptsd/issues.yaml" || path == ".ptsd/generated.yaml":
 			return "STATUS", nil
-		case strings.HasPrefix(path, ".ptsd/skills/"):
+		case strings.HasPrefix(path, ".ptsd/skills/") || strings.HasPrefix(path, ".ptsd/snapshots/"):
 			return "STATUS", nil
 		}
 	}
@@ -172,7 +245,7 @@ func ClassifyFile(projectDir string, path string) (string, error) {
 	// Try config-based test patterns
 	cfg, err := LoadConfig(projectDir)
 	if err == nil && len(cfg.Testing.Patterns.Files) > 0 {
-		for _, pattern := range cfg.Testing.Patterns.Files {
+		for _, pattern := range cfg.TestPatterns() {
 			if matchesTestPattern(path, pattern) {
 				return "TEST", nil
 			}
@@ -211,6 +284,8 @@ func matchesTestPattern(path, pattern string) bool {
 }
 
 func ParseCommitMessage(msg string) (scope string, commitType string, text string, err error) {
+	// Only the subject line; the body may hold trailers like "Ptsd-Feature: x".
+	msg, _, _ = strings.Cut(msg, "\n")
 	if !strings.HasPrefix(msg, "[") {
 		return "", "", "", fmt.Errorf("err:git missing [SCOPE] in commit message")
 	}
@@ -312,3 +312,50 @@ func setupPtsdConfig(t *testing.T, dir string, testPatterns []string) {
 func containsErr(err error, substr string) bool {
 	return err != nil && strings.Contains(err.Error(), substr)
 }
+
+func TestGitHookScriptChecksBinary(t *testing.T) {
+	dir := t.TempDir()
+	run := func(bin string) (string, int) {
+		t.Helper()
+		hook := filepath.Join(dir, "pre-commit")
+		os.WriteFile(hook, []byte(gitHookScript(bin, "validate")), 0755)
+		out, err := exec.Command("sh", hook).CombinedOutput()
+		if exit, ok := err.(*exec.ExitError); ok {
+			return string(out), exit.ExitCode()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		return string(out), 0
+	}
+
+	out, code := run(filepath.Join(dir, "moved", "ptsd"))
+	if code != 1 || !strings.Contains(out, "missing or older than schema") || !strings.Contains(out, "ptsd hooks install") {
+		t.Errorf("missing binary: exit %d, output %q", code, out)
+	}
+
+	// A binary too old to know verify-hooks fails it like a missing one.
+	old := filepath.Join(dir, "old-ptsd")
+	os.WriteFile(old, []byte("#!/bin/sh\n[ \"$1\" = verify-hooks ] && exit 2\necho ran \"$@\"\n"), 0755)
+	if out, code := run(old); code != 1 || strings.Contains(out, "ran") {
+		t.Errorf("old binary: exit %d, output %q", code, out)
+	}
+
+	current := filepath.Join(dir, "ptsd")
+	os.WriteFile(current, []byte("#!/bin/sh\necho ran \"$@\"\n"), 0755)
+	if out, code := run(current); code != 0 || strings.TrimSpace(out) != "ran validate" {
+		t.Errorf("current binary: exit %d, output %q", code, out)
+	}
+	if bin := hookBinary(gitHookScript(current, "validate")); bin != current {
+		t.Errorf("hookBinary = %q, want %q", bin, current)
+	}
+}
+
+func TestCheckHookSchema(t *testing.T) {
+	if err := CheckHookSchema(SchemaVersion); err != nil {
+		t.Errorf("current schema: %v", err)
+	}
+	err := CheckHookSchema(SchemaVersion + 1)
+	if err == nil || !strings.HasPrefix(err.Error(), "err:config") || !strings.Contains(err.Error(), "ptsd hooks install") {
+		t.Errorf("newer schema: %v", err)
+	}
+}
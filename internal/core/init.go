@@ -10,6 +10,29 @@ import (
 // InitResult reports what InitProject did.
 type InitResult struct {
 	Reinit bool
+	// Skipped lists generated files kept on re-init because the user modified them.
+	Skipped []string
+}
+
+// Scaffolding profiles, recorded as project.profile in ptsd.yaml so re-init
+// regenerates the same set of files.
+const (
+	// ProfileMinimal writes only .ptsd/: config, registries, PRD and skills.
+	ProfileMinimal = "minimal"
+	// ProfileStandard adds git hooks, .gitignore and the CLAUDE.md section.
+	ProfileStandard = "standard"
+	// ProfileFull adds the Claude Code integration under .claude/. Default.
+	ProfileFull = "full"
+)
+
+// InitProfiles lists the valid scaffolding profiles, smallest first.
+var InitProfiles = []string{ProfileMinimal, ProfileStandard, ProfileFull}
+
+func validProfile(profile string) error {
+	if !containsString(InitProfiles, profile) {
+		return fmt.Errorf("err:user unknown profile %q: must be %s", profile, strings.Join(InitProfiles, "|"))
+	}
+	return nil
 }
 
 // InitProject scaffolds .ptsd/ directory structure in the given directory.
@@ -17,6 +40,19 @@ type InitResult struct {
 // without touching project data files.
 // name is the project name written into ptsd.yaml; if empty, defaults to basename of dir.
 func InitProject(dir string, name string) (*InitResult, error) {
+	return InitProjectWithProfile(dir, name, "")
+}
+
+// InitProjectWithProfile is InitProject with a scaffolding profile. An empty
+// profile means full for a new project and the recorded profile on re-init;
+// an explicit profile on re-init is recorded in ptsd.yaml.
+func InitProjectWithProfile(dir, name, profile string) (*InitResult, error) {
+	if profile != "" {
+		if err := validProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+
 	// Require git repository.
 	gitDir := filepath.Join(dir, ".git")
 	if _, err := os.Stat(gitDir); err != nil {
@@ -26,10 +62,15 @@ func InitProject(dir string, name string) (*InitResult, error) {
 	// Auto-detect re-init.
 	ptsdDir := filepath.Join(dir, ".ptsd")
 	if _, err := os.Stat(ptsdDir); err == nil {
-		if err := ReInitProject(dir); err != nil {
+		skipped, err := ReInitProjectWithProfile(dir, false, profile)
+		if err != nil {
 			return nil, err
 		}
-		return &InitResult{Reinit: true}, nil
+		return &InitResult{Reinit: true, Skipped: skipped}, nil
+	}
+
+	if profile == "" {
+		profile = ProfileFull
 	}
 
 	if name == "" {
@@ -54,9 +95,11 @@ func InitProject(dir string, name string) (*InitResult, error) {
 	runner := detectTestRunner(dir)
 
 	// Write ptsd.yaml.
-	ptsdYAML, err := renderTemplate("templates/ptsd.yaml.tmpl", struct{ Name, Runner string }{name, runner})
+	ptsdYAML, err := renderTemplate("templates/ptsd.yaml.tmpl", ptsdYAMLData{
+		SchemaVersion, name, profile, runner, strings.Join(DefaultCommitScopes, ", "), strings.Join(DefaultCommitTypes, ", "),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("err:io %w", err)
+		return nil, err
 	}
 	if err := writeFile(filepath.Join(ptsdDir, "ptsd.yaml"), ptsdYAML); err != nil {
 		return nil, err
@@ -65,8 +108,8 @@ func InitProject(dir string, name string) (*InitResult, error) {
 	// Write empty registry files.
 	emptyFiles := map[string]string{
 		"features.yaml":      "features: []\n",
-		"state.yaml":         "features: {}\n",
-		"tasks.yaml":         "tasks: []\n",
+		"state.yaml":         schemaHeader() + "features: {}\n",
+		"tasks.yaml":         schemaHeader() + "tasks: []\n",
 		"review-status.yaml": "features: {}\n",
 	}
 	for filename, content := range emptyFiles {
@@ -76,63 +119,56 @@ func InitProject(dir string, name string) (*InitResult, error) {
 	}
 
 	// Write PRD template.
-	prdContent, err := renderTemplate("templates/prd.md.tmpl", struct{ Name string }{name})
+	prdContent, err := renderTemplate("templates/prd.md.tmpl", prdData{name})
 	if err != nil {
-		return nil, fmt.Errorf("err:io %w", err)
-	}
-	if err := writeFile(filepath.Join(ptsdDir, "docs", "PRD.md"), prdContent); err != nil {
 		return nil, err
 	}
-
-	// Write skills.
-	if err := GenerateAllSkills(dir); err != nil {
+	if err := writeFile(filepath.Join(ptsdDir, "docs", "PRD.md"), prdContent); err != nil {
 		return nil, err
 	}
 
-	// Generate Claude Code skill discovery files.
-	if err := generateClaudeSkills(dir); err != nil {
-		return nil, err
-	}
+	m := loadGenManifest(dir)
 
-	// Write CLAUDE.md at project root (with markers for future re-init).
-	if err := updateClaudeMDSection(dir); err != nil {
+	// Write skills.
+	if err := generateAllSkills(dir, m); err != nil {
 		return nil, err
 	}
 
-	// Write .gitignore if it doesn't exist.
-	gitignorePath := filepath.Join(dir, ".gitignore")
-	if _, err := os.Stat(gitignorePath); os.IsNotExist(err) {
-		gitignore := "# Build artifacts\n*.exe\n*.dll\n*.so\n*.dylib\n\n# Binary output (match project name)\n/" + name + "\n"
-		if err := writeFile(gitignorePath, gitignore); err != nil {
+	if profile != ProfileMinimal {
+		// Write .gitignore if it doesn't exist.
+		gitignorePath := filepath.Join(dir, ".gitignore")
+		if _, err := os.Stat(gitignorePath); os.IsNotExist(err) {
+			gitignore := "# Build artifacts\n*.exe\n*.dll\n*.so\n*.dylib\n\n# Binary output (match project name)\n/" + name + "\n"
+			if err := writeFile(gitignorePath, gitignore); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := AddIgnoreEntries(dir); err != nil {
 			return nil, err
 		}
 	}
 
-	// Install git hooks.
-	if err := GeneratePreCommitHook(dir); err != nil {
-		return nil, err
-	}
-	if err := GenerateCommitMsgHook(dir); err != nil {
+	if err := generateProfileFiles(dir, profile, m); err != nil {
 		return nil, err
 	}
-
-	// Generate Claude Code hooks.
-	if err := generateClaudeHooks(dir); err != nil {
+	if err := m.save(); err != nil {
 		return nil, err
 	}
 
 	return &InitResult{Reinit: false}, nil
 }
 
-const ptsdMarker = "<!-- ---ptsd--- -->"
-
-// ReInitProject regenerates hooks, skills, and CLAUDE.md section without touching project data.
-func ReInitProject(dir string) error {
-	if err := GenerateAllSkills(dir); err != nil {
-		return err
+// generateProfileFiles writes the files outside .ptsd/ that profile includes:
+// git hooks and the CLAUDE.md section (standard), plus .claude/ skills and
+// hooks (full).
+func generateProfileFiles(dir, profile string, m *genManifest) error {
+	if profile == ProfileMinimal {
+		return nil
 	}
-	if err := generateClaudeSkills(dir); err != nil {
-		return err
+	if profile == ProfileFull {
+		if err := generateClaudeSkills(dir, m); err != nil {
+			return err
+		}
 	}
 	if err := GeneratePreCommitHook(dir); err != nil {
 		return err
@@ -140,20 +176,113 @@ func ReInitProject(dir string) error {
 	if err := GenerateCommitMsgHook(dir); err != nil {
 		return err
 	}
-	if err := generateClaudeHooks(dir); err != nil {
+	if err := GeneratePrepareCommitMsgHook(dir); err != nil {
 		return err
 	}
-	if err := updateClaudeMDSection(dir); err != nil {
+	if err := GeneratePrePushHook(dir); err != nil {
 		return err
 	}
-	return nil
+	if profile == ProfileFull {
+		if err := generateClaudeHooks(dir, m); err != nil {
+			return err
+		}
+	}
+	// Write CLAUDE.md at project root (with markers for future re-init).
+	return updateClaudeMDSection(dir)
+}
+
+const ptsdMarker = "<!-- ---ptsd--- -->"
+
+// ReInitProject regenerates hooks, skills, and CLAUDE.md section without touching project data.
+// Generated files whose content no longer matches the recorded hash in
+// .ptsd/generated.yaml were customized by the user; they are kept and returned
+// unless force is set.
+func ReInitProject(dir string, force bool) ([]string, error) {
+	return ReInitProjectWithProfile(dir, force, "")
+}
+
+// ReInitProjectWithProfile re-inits with the given profile, recording it in
+// ptsd.yaml; an empty profile keeps the recorded one. Switching to a smaller
+// profile stops regenerating the extra files but does not delete them.
+func ReInitProjectWithProfile(dir string, force bool, profile string) ([]string, error) {
+	if profile == "" {
+		profile = ProjectProfile(dir)
+	} else {
+		if err := validProfile(profile); err != nil {
+			return nil, err
+		}
+		if err := setConfigValue(dir, "project.profile", `"`+profile+`"`); err != nil {
+			return nil, err
+		}
+	}
+
+	m := loadGenManifest(dir)
+	m.force = force
+	if err := generateAllSkills(dir, m); err != nil {
+		return nil, err
+	}
+	if err := generateProfileFiles(dir, profile, m); err != nil {
+		return nil, err
+	}
+	if err := m.save(); err != nil {
+		return nil, err
+	}
+	return m.Skipped, nil
+}
+
+// ProjectProfile returns the recorded scaffolding profile, full when unset.
+func ProjectProfile(dir string) string {
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		return ProfileFull
+	}
+	return cfg.Project.Profile
+}
+
+// claudeMDData holds the project variables rendered into the CLAUDE.md section.
+type claudeMDData struct {
+	Name     string
+	Runner   string
+	MinScore int
+	Scopes   string
+	Types    string
+}
+
+// claudeMDVariables reads ptsd.yaml so the agent instructions match the actual
+// configuration. Missing config falls back to the built-in defaults.
+func claudeMDVariables(dir string) claudeMDData {
+	data := claudeMDData{
+		Name:     filepath.Base(dir),
+		MinScore: 7,
+		Scopes:   strings.Join(CommitScopes(dir), ", "),
+		Types:    strings.Join(CommitTypes(dir), ", "),
+	}
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		return data
+	}
+	if cfg.Project.Name != "" {
+		data.Name = cfg.Project.Name
+	}
+	data.Runner = cfg.Testing.Runner
+	data.MinScore = cfg.Review.MinScore
+	return data
+}
+
+// SyncClaudeMD re-renders the managed CLAUDE.md section after a config change.
+// Projects without a CLAUDE.md are left alone.
+func SyncClaudeMD(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, "CLAUDE.md")); err != nil {
+		return nil
+	}
+	return updateClaudeMDSection(dir)
 }
 
 // updateClaudeMDSection writes or updates the ptsd-owned section in CLAUDE.md using markers.
 func updateClaudeMDSection(dir string) error {
-	claudeMD, err := readTemplate("templates/claude.md")
+	claudeMD, err := renderTemplate("templates/claude.md.tmpl", claudeMDVariables(dir))
 	if err != nil {
-		return fmt.Errorf("err:io %w", err)
+		return err
 	}
 
 	section := ptsdMarker + "\n" + claudeMD + "\n" + ptsdMarker
@@ -189,7 +318,7 @@ func updateClaudeMDSection(dir string) error {
 	return writeFile(path, content)
 }
 
-func generateClaudeHooks(dir string) error {
+func generateClaudeHooks(dir string, m *genManifest) error {
 	bin := ptsdBinaryPath()
 
 	// Create .claude/hooks/ directory
@@ -198,7 +327,7 @@ func generateClaudeHooks(dir string) error {
 		return fmt.Errorf("err:io %w", err)
 	}
 
-	binData := struct{ Bin string }{bin}
+	binData := hookData{Bin: bin, Schema: SchemaVersion}
 
 	// Generate hook scripts from templates
 	hookFiles := []struct {
@@ -213,35 +342,35 @@ func generateClaudeHooks(dir string) error {
 	for _, hf := range hookFiles {
 		content, err := renderTemplate(hf.tmpl, binData)
 		if err != nil {
-			return fmt.Errorf("err:io %w", err)
+			return err
 		}
-		if err := os.WriteFile(filepath.Join(hooksDir, hf.dest), []byte(content), 0755); err != nil {
-			return fmt.Errorf("err:io %w", err)
+		if err := m.write(filepath.Join(hooksDir, hf.dest), content, 0755); err != nil {
+			return err
 		}
 	}
 
 	// Generate .claude/settings.json from template
-	settingsData := struct{ ContextHook, GateHook, TrackHook string }{
+	hooks := settingsData{
 		ContextHook: filepath.Join(hooksDir, "ptsd-context.sh"),
 		GateHook:    filepath.Join(hooksDir, "ptsd-gate.sh"),
 		TrackHook:   filepath.Join(hooksDir, "ptsd-track.sh"),
 	}
 
-	settingsJSON, err := renderTemplate("templates/settings.json.tmpl", settingsData)
+	settingsJSON, err := renderTemplate("templates/settings.json.tmpl", hooks)
 	if err != nil {
-		return fmt.Errorf("err:io %w", err)
+		return err
 	}
 
 	settingsPath := filepath.Join(dir, ".claude", "settings.json")
-	if err := os.WriteFile(settingsPath, []byte(settingsJSON), 0644); err != nil {
-		return fmt.Errorf("err:io %w", err)
+	if err := m.write(settingsPath, settingsJSON, 0644); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 func writeFile(path, content string) error {
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	if err := writeFileAtomic(path, []byte(content), 0644); err != nil {
 		return fmt.Errorf("err:io %w", err)
 	}
 	return nil
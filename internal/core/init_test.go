@@ -458,8 +458,10 @@ func TestReInitRegeneratesSkills(t *testing.T) {
 		t.Fatalf("failed to corrupt skill: %v", err)
 	}
 
-	// Re-init.
-	initProject(t, dir, "MyApp")
+	// Forced re-init overwrites customized generated files.
+	if _, err := ReInitProject(dir, true); err != nil {
+		t.Fatalf("forced re-init failed: %v", err)
+	}
 
 	// Skill should be regenerated.
 	data, err := os.ReadFile(skillPath)
@@ -682,3 +684,159 @@ func TestReInitPreservesUserContentInClaudeMD(t *testing.T) {
 		t.Error("CLAUDE.md missing template content after re-init")
 	}
 }
+
+// TestReinitKeepsCustomizedGeneratedFiles verifies checksum-pinned regeneration:
+// untouched generated files are rewritten, user-edited ones are kept and reported.
+func TestReinitKeepsCustomizedGeneratedFiles(t *testing.T) {
+	dir := t.TempDir()
+	setupGitDir(t, dir)
+	initProject(t, dir, "MyApp")
+
+	manifest := loadGenManifest(dir)
+	if len(manifest.Hashes) == 0 {
+		t.Fatal("expected generated.yaml to record file hashes")
+	}
+	if _, ok := manifest.Hashes[".claude/settings.json"]; !ok {
+		t.Error("expected settings.json in manifest")
+	}
+
+	customized := filepath.Join(dir, ".ptsd", "skills", "write-prd.md")
+	os.WriteFile(customized, []byte("my own prd skill\n"), 0644)
+	untouched := filepath.Join(dir, ".ptsd", "skills", "write-bdd.md")
+	os.Remove(untouched)
+
+	result, err := InitProject(dir, "MyApp")
+	if err != nil {
+		t.Fatalf("re-init failed: %v", err)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != ".ptsd/skills/write-prd.md" {
+		t.Errorf("expected write-prd.md skipped, got %v", result.Skipped)
+	}
+
+	data, _ := os.ReadFile(customized)
+	if string(data) != "my own prd skill\n" {
+		t.Error("customized skill must not be overwritten")
+	}
+	if _, err := os.Stat(untouched); err != nil {
+		t.Errorf("deleted generated file must be regenerated: %v", err)
+	}
+}
+
+// TestReinitKeepsUntrackedFilesFromBeforeTheManifest verifies that a project
+// initialized before generated.yaml existed keeps its customized files.
+func TestReinitKeepsUntrackedFilesFromBeforeTheManifest(t *testing.T) {
+	dir := t.TempDir()
+	setupGitDir(t, dir)
+	initProject(t, dir, "MyApp")
+
+	os.Remove(generatedManifestPath(dir))
+	customized := filepath.Join(dir, ".ptsd", "skills", "write-prd.md")
+	os.WriteFile(customized, []byte("my own prd skill\n"), 0644)
+
+	result, err := InitProject(dir, "MyApp")
+	if err != nil {
+		t.Fatalf("re-init failed: %v", err)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != ".ptsd/skills/write-prd.md" {
+		t.Errorf("expected write-prd.md skipped, got %v", result.Skipped)
+	}
+	if data, _ := os.ReadFile(customized); string(data) != "my own prd skill\n" {
+		t.Error("customized skill with no recorded hash must not be overwritten")
+	}
+	if _, tracked := loadGenManifest(dir).Hashes[".ptsd/skills/write-bdd.md"]; !tracked {
+		t.Error("unchanged files must be recorded in the new manifest")
+	}
+}
+
+// TestClaudeMDRendersProjectVariables verifies the managed section reflects ptsd.yaml.
+func TestClaudeMDRendersProjectVariables(t *testing.T) {
+	dir := t.TempDir()
+	setupGitDir(t, dir)
+	initProject(t, dir, "MyApp")
+
+	cfg := "project:\n  name: \"Billing\"\ntesting:\n  runner: \"pytest -q\"\nreview:\n  min_score: 8\nhooks:\n  scopes: [PRD, IMPL]\n  types: [feat, fix]\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	initProject(t, dir, "MyApp")
+
+	data, err := os.ReadFile(filepath.Join(dir, "CLAUDE.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"Project: Billing",
+		"- pytest -q — project test runner",
+		"review score ≥ 8",
+		"Scopes: PRD, IMPL",
+		"Types: feat, fix",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("CLAUDE.md missing %q", want)
+		}
+	}
+}
+
+// TestInitMinimalProfile verifies --minimal writes only .ptsd/ and that
+// re-init keeps the recorded profile until another one is requested.
+func TestInitMinimalProfile(t *testing.T) {
+	dir := t.TempDir()
+	setupGitDir(t, dir)
+
+	if _, err := InitProjectWithProfile(dir, "Lib", ProfileMinimal); err != nil {
+		t.Fatalf("InitProjectWithProfile failed: %v", err)
+	}
+	for _, rel := range []string{".ptsd/ptsd.yaml", ".ptsd/features.yaml", ".ptsd/docs/PRD.md", ".ptsd/skills/write-prd.md"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("%s should exist: %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"CLAUDE.md", ".claude", ".gitignore", ".git/hooks/pre-commit"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err == nil {
+			t.Errorf("%s should not exist with the minimal profile", rel)
+		}
+	}
+	if got := ProjectProfile(dir); got != ProfileMinimal {
+		t.Errorf("recorded profile = %q, want minimal", got)
+	}
+
+	// Plain re-init honours the recorded profile.
+	initProject(t, dir, "")
+	if _, err := os.Stat(filepath.Join(dir, "CLAUDE.md")); err == nil {
+		t.Error("re-init must not add CLAUDE.md to a minimal project")
+	}
+
+	// Explicit profile on re-init upgrades and is recorded.
+	if _, err := InitProjectWithProfile(dir, "", ProfileStandard); err != nil {
+		t.Fatalf("re-init with standard failed: %v", err)
+	}
+	for _, rel := range []string{"CLAUDE.md", ".git/hooks/pre-commit", ".git/hooks/commit-msg"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("%s should exist with the standard profile: %v", rel, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".claude")); err == nil {
+		t.Error(".claude/ is only generated by the full profile")
+	}
+	if got := ProjectProfile(dir); got != ProfileStandard {
+		t.Errorf("recorded profile = %q, want standard", got)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"))
+	if strings.Count(string(data), "profile:") != 1 {
+		t.Errorf("profile should be replaced in place, got:\n%s", data)
+	}
+}
+
+func TestInitRejectsUnknownProfile(t *testing.T) {
+	dir := t.TempDir()
+	setupGitDir(t, dir)
+
+	_, err := InitProjectWithProfile(dir, "x", "huge")
+	if err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Fatalf("expected err:user, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, ".ptsd")); statErr == nil {
+		t.Error("nothing should be written for an unknown profile")
+	}
+}
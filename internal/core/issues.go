@@ -153,5 +153,5 @@ func saveIssues(projectDir string, issues []Issue) error {
 		b.WriteString("    fix: \"" + issue.Fix + "\"\n")
 	}
 
-	return os.WriteFile(issuesPath, []byte(b.String()), 0644)
+	return writeFileAtomic(issuesPath, []byte(b.String()), 0644)
 }
@@ -0,0 +1,226 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task leases. `ptsd task claim <id> --ttl 30m` records who is working on a
+// task and until when, so parallel agents asking `task next` are not handed
+// the same task. Leases live in .ptsd/task-leases.yaml (local, gitignored).
+// An expired lease needs no cleanup: its task is offered by `task next`
+// again, context flags it, and the next claim takes it over.
+
+// TaskLease is one agent's claim on a task.
+type TaskLease struct {
+	Task    string
+	Owner   string
+	Expires time.Time
+}
+
+// Expired reports whether the lease has run out at now.
+func (l TaskLease) Expired(now time.Time) bool {
+	return !now.Before(l.Expires)
+}
+
+// DefaultLeaseTTL is the lease length when --ttl is not given.
+const DefaultLeaseTTL = 30 * time.Minute
+
+const leasesFile = "task-leases.yaml"
+
+func leasesPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", leasesFile)
+}
+
+// LeaseOwner names the current agent for task claims: $PTSD_AGENT, else
+// user@host. Parallel agents on one machine should each set PTSD_AGENT.
+func LeaseOwner() string {
+	if v := strings.TrimSpace(os.Getenv("PTSD_AGENT")); v != "" {
+		return v
+	}
+	user := os.Getenv("USER")
+	if user == "" {
+		user = "agent"
+	}
+	host, _ := os.Hostname()
+	if host == "" {
+		return user
+	}
+	return user + "@" + host
+}
+
+// LoadTaskLeases reads .ptsd/task-leases.yaml, expired leases included; a
+// missing file means no leases.
+func LoadTaskLeases(projectDir string) ([]TaskLease, error) {
+	data, err := os.ReadFile(leasesPath(projectDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	var out []TaskLease
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(line, " ") || trimmed == "" {
+			continue
+		}
+		item := strings.HasPrefix(trimmed, "- ")
+		key, val, ok := strings.Cut(strings.TrimPrefix(trimmed, "- "), ": ")
+		if !ok {
+			continue
+		}
+		if unq, err := strconv.Unquote(val); err == nil {
+			val = unq
+		}
+		if item {
+			out = append(out, TaskLease{})
+		}
+		if len(out) == 0 {
+			continue
+		}
+		l := &out[len(out)-1]
+		switch key {
+		case "task":
+			l.Task = val
+		case "owner":
+			l.Owner = val
+		case "expires":
+			l.Expires, _ = time.Parse(time.RFC3339, val)
+		}
+	}
+	return out, nil
+}
+
+func saveTaskLeases(projectDir string, leases []TaskLease) error {
+	var sb strings.Builder
+	sb.WriteString("# Task claims; manage with ptsd task claim|release.\n")
+	sb.WriteString("leases:\n")
+	for _, l := range leases {
+		sb.WriteString("  - task: " + l.Task + "\n")
+		sb.WriteString("    owner: " + strconv.Quote(l.Owner) + "\n")
+		sb.WriteString("    expires: " + l.Expires.UTC().Format(time.RFC3339) + "\n")
+	}
+	if err := writeFileAtomic(leasesPath(projectDir), []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// withLeaseLock runs fn under the project lock (LockProject), so two agents
+// claiming at once cannot both win. Commands run by RunLocked already hold
+// it; the lock is reentrant.
+func withLeaseLock(projectDir string, fn func() error) error {
+	unlock, err := LockProject(projectDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+// ClaimTask leases task id to owner for ttl and marks it WIP. The owner of
+// an active lease may renew it; anyone may take over an expired one. DONE
+// tasks cannot be claimed.
+func ClaimTask(projectDir, id, owner string, ttl time.Duration, now time.Time) (TaskLease, error) {
+	if ttl <= 0 {
+		return TaskLease{}, fmt.Errorf("err:user --ttl must be positive")
+	}
+	if strings.TrimSpace(owner) == "" {
+		return TaskLease{}, fmt.Errorf("err:user --owner must not be empty")
+	}
+	lease := TaskLease{Task: id, Owner: owner, Expires: now.Add(ttl).Truncate(time.Second)}
+	err := withLeaseLock(projectDir, func() error {
+		tasks, err := loadTasks(projectDir)
+		if err != nil {
+			return err
+		}
+		var task *Task
+		for i := range tasks {
+			if tasks[i].ID == id {
+				task = &tasks[i]
+			}
+		}
+		if task == nil {
+			return fmt.Errorf("err:validation task %s not found", id)
+		}
+		if task.Status == "DONE" {
+			return fmt.Errorf("err:validation task %s is DONE", id)
+		}
+
+		leases, err := LoadTaskLeases(projectDir)
+		if err != nil {
+			return err
+		}
+		detail := "WIP claimed by " + owner
+		var kept []TaskLease
+		for _, l := range leases {
+			if l.Task != id {
+				kept = append(kept, l)
+				continue
+			}
+			if l.Owner != owner {
+				if !l.Expired(now) {
+					return fmt.Errorf("err:validation task %s is claimed by %s until %s", id, l.Owner, l.Expires.Local().Format("2006-01-02 15:04"))
+				}
+				detail += " (lease of " + l.Owner + " expired)"
+			}
+		}
+		if err := saveTaskLeases(projectDir, append(kept, lease)); err != nil {
+			return err
+		}
+		if task.Status != "WIP" {
+			task.Status = "WIP"
+			if err := saveTasks(projectDir, tasks); err != nil {
+				return err
+			}
+		}
+		recordEvent(projectDir, Event{Type: EventTask, Feature: task.Feature, Task: id, Detail: detail})
+		return nil
+	})
+	if err != nil {
+		return TaskLease{}, err
+	}
+	return lease, nil
+}
+
+// ReleaseTask drops the lease on task id. released is false when there was
+// none.
+func ReleaseTask(projectDir, id string) (released bool, err error) {
+	if _, err := os.Stat(leasesPath(projectDir)); os.IsNotExist(err) {
+		return false, nil
+	}
+	err = withLeaseLock(projectDir, func() error {
+		leases, err := LoadTaskLeases(projectDir)
+		if err != nil {
+			return err
+		}
+		var kept []TaskLease
+		for _, l := range leases {
+			if l.Task == id {
+				released = true
+			} else {
+				kept = append(kept, l)
+			}
+		}
+		if !released {
+			return nil
+		}
+		return saveTaskLeases(projectDir, kept)
+	})
+	return released, err
+}
+
+// taskLeases maps task IDs to their leases; read errors mean no leases.
+func taskLeases(projectDir string) map[string]TaskLease {
+	leases, _ := LoadTaskLeases(projectDir)
+	out := make(map[string]TaskLease, len(leases))
+	for _, l := range leases {
+		out[l.Task] = l
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClaimTaskLeases(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	setupTasks(t, dir,
+		Task{ID: "T-1", Feature: "auth", Title: "login", Status: "TODO", Priority: "A"},
+		Task{ID: "T-2", Feature: "auth", Title: "logout", Status: "TODO", Priority: "B"},
+	)
+	now := time.Now()
+
+	lease, err := ClaimTask(dir, "T-1", "agent-1", 30*time.Minute, now)
+	if err != nil {
+		t.Fatalf("ClaimTask failed: %v", err)
+	}
+	if lease.Owner != "agent-1" || lease.Expired(now.Add(29*time.Minute)) {
+		t.Errorf("unexpected lease: %+v", lease)
+	}
+	tasks, _ := ListTasks(dir, "", "")
+	if tasks[0].Status != "WIP" {
+		t.Errorf("expected claimed task to be WIP, got %s", tasks[0].Status)
+	}
+
+	// Another agent is refused; the owner may renew.
+	if _, err := ClaimTask(dir, "T-1", "agent-2", time.Hour, now); err == nil || !strings.Contains(err.Error(), "claimed by agent-1") {
+		t.Errorf("expected conflict, got %v", err)
+	}
+	if _, err := ClaimTask(dir, "T-1", "agent-1", time.Hour, now); err != nil {
+		t.Errorf("expected renewal to succeed, got %v", err)
+	}
+
+	next, _ := TaskNext(dir, 0)
+	if len(next) != 1 || next[0].ID != "T-2" {
+		t.Errorf("expected task next to skip the claimed task, got %+v", next)
+	}
+
+	// Expired: offered again and taken over by the next claim.
+	later := now.Add(2 * time.Hour)
+	leases, _ := LoadTaskLeases(dir)
+	if len(leases) != 1 || !leases[0].Expired(later) {
+		t.Fatalf("expected one lease expiring within 2h, got %+v", leases)
+	}
+	if _, err := ClaimTask(dir, "T-1", "agent-2", time.Hour, later); err != nil {
+		t.Errorf("expected takeover of expired lease, got %v", err)
+	}
+	leases, _ = LoadTaskLeases(dir)
+	if len(leases) != 1 || leases[0].Owner != "agent-2" {
+		t.Errorf("expected agent-2 to hold the lease, got %+v", leases)
+	}
+
+	// Finishing the task ends the lease.
+	if err := UpdateTask(dir, "T-1", "DONE"); err != nil {
+		t.Fatal(err)
+	}
+	if leases, _ := LoadTaskLeases(dir); len(leases) != 0 {
+		t.Errorf("expected DONE to release the lease, got %+v", leases)
+	}
+	if _, err := ClaimTask(dir, "T-1", "agent-1", time.Hour, later); err == nil {
+		t.Error("expected claiming a DONE task to fail")
+	}
+}
+
+func TestTaskNextReoffersExpiredLease(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	setupTasks(t, dir, Task{ID: "T-1", Feature: "auth", Title: "login", Status: "TODO", Priority: "A"})
+	if _, err := ClaimTask(dir, "T-1", "agent-1", time.Minute, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	next, _ := TaskNext(dir, 0)
+	if len(next) != 1 || next[0].ID != "T-1" || next[0].Status != "WIP" {
+		t.Errorf("expected the WIP task with an expired lease, got %+v", next)
+	}
+
+	result, err := BuildContext(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, l := range result.Lines {
+		if l.Type == ContextTask && l.TaskID == "T-1" {
+			found = l.LeaseOwner == "agent-1" && l.LeaseExpired
+		}
+	}
+	if !found {
+		t.Errorf("expected context to flag the expired lease, got %+v", result.Lines)
+	}
+}
+
+func TestClaimTaskWaitsForProjectLock(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	setupTasks(t, dir, Task{ID: "T-1", Feature: "auth", Title: "login", Status: "TODO", Priority: "A"})
+	SetLockWait(0)
+	defer SetLockWait(DefaultLockWait)
+
+	// Another process holding the project lock.
+	release, ok, err := tryLockFile(filepath.Join(dir, ".ptsd", lockFile))
+	if err != nil || !ok {
+		t.Fatalf("cannot take the lock: ok=%v err=%v", ok, err)
+	}
+	if _, err := ClaimTask(dir, "T-1", "agent-1", time.Hour, time.Now()); err == nil || !strings.Contains(err.Error(), "project is locked") {
+		t.Errorf("expected the claim to need the project lock, got %v", err)
+	}
+	release()
+
+	if _, err := ClaimTask(dir, "T-1", "agent-1", time.Hour, time.Now()); err != nil {
+		t.Errorf("expected claim after release, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LintFinding is one problem reported by Lint.
+type LintFinding struct {
+	Check    string // config | prd | bdd | seed | fsck
+	Severity string // error | warn
+	Feature  string
+	Message  string
+}
+
+// Lint runs the static checks (config, PRD anchors, BDD files, seed
+// manifests, .ptsd consistency) and returns every finding in check order.
+// Unlike Validate it never runs tests or inspects review gates.
+func Lint(projectDir string) ([]LintFinding, error) {
+	if _, err := os.Stat(filepath.Join(projectDir, ".ptsd")); err != nil {
+		return nil, fmt.Errorf("err:config .ptsd not found")
+	}
+
+	var findings []LintFinding
+	if _, err := LoadConfig(projectDir); err != nil {
+		findings = append(findings, LintFinding{Check: "config", Severity: "error", Message: strings.TrimPrefix(err.Error(), "err:config ")})
+	}
+
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		findings = append(findings, LintFinding{Check: "fsck", Severity: "error", Message: "cannot read features.yaml"})
+		return findings, nil
+	}
+
+	findings = append(findings, lintPRD(projectDir)...)
+	findings = append(findings, lintBDD(projectDir, features)...)
+	findings = append(findings, lintSeeds(projectDir, features)...)
+	findings = append(findings, fsck(projectDir, features)...)
+	return findings, nil
+}
+
+func lintPRD(projectDir string) []LintFinding {
+	var findings []LintFinding
+	errs, err := CheckPRDAnchors(projectDir)
+	if err != nil {
+		return []LintFinding{{Check: "prd", Severity: "error", Message: "cannot read PRD"}}
+	}
+	for _, e := range errs {
+		msg := "has no prd anchor"
+		switch e.Type {
+		case "orphaned-anchor":
+			msg = "prd anchor has no registered feature"
+		case "duplicate-anchor":
+			msg = "prd anchor appears in several files: " + strings.Join(e.Files, ", ")
+		}
+		findings = append(findings, LintFinding{Check: "prd", Severity: "error", Feature: e.FeatureID, Message: msg})
+	}
+	return findings
+}
+
+func lintBDD(projectDir string, features []Feature) []LintFinding {
+	known := make(map[string]bool)
+	for _, f := range features {
+		known[f.ID] = true
+	}
+
+	bddDir := bddDirPath(projectDir)
+	entries, _ := os.ReadDir(bddDir)
+	var findings []LintFinding
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".feature") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(bddDir, e.Name()))
+		if err != nil {
+			findings = append(findings, LintFinding{Check: "bdd", Severity: "error", Message: "cannot read " + e.Name()})
+			continue
+		}
+		ff, err := parseFeatureContent(string(data))
+		fileID := strings.TrimSuffix(e.Name(), ".feature")
+		if err != nil {
+			findings = append(findings, LintFinding{Check: "bdd", Severity: "error", Feature: fileID, Message: e.Name() + " " + strings.TrimPrefix(err.Error(), "err:validation ")})
+		}
+
+		switch {
+		case ff.Tag == "":
+			findings = append(findings, LintFinding{Check: "bdd", Severity: "error", Feature: fileID, Message: "missing @feature tag"})
+		case !known[ff.Tag]:
+			findings = append(findings, LintFinding{Check: "bdd", Severity: "error", Feature: ff.Tag, Message: "unknown feature tag in " + e.Name()})
+		case ff.Tag != fileID:
+			findings = append(findings, LintFinding{Check: "bdd", Severity: "warn", Feature: ff.Tag, Message: "tag does not match file name " + e.Name()})
+		}
+
+		seen := make(map[string]bool)
+		for _, sc := range ff.Scenarios {
+			if seen[sc.ID] {
+				findings = append(findings, LintFinding{Check: "bdd", Severity: "error", Feature: fileID, Message: "duplicate scenario id " + sc.ID})
+			}
+			seen[sc.ID] = true
+			if len(sc.Steps) == 0 {
+				findings = append(findings, LintFinding{Check: "bdd", Severity: "warn", Feature: fileID, Message: "scenario \"" + sc.Title + "\" has no steps"})
+			}
+		}
+	}
+	return findings
+}
+
+func lintSeeds(projectDir string, features []Feature) []LintFinding {
+	var findings []LintFinding
+	for _, f := range features {
+		seedDir := seedDirPath(projectDir, f.ID)
+		if info, err := os.Stat(seedDir); err != nil || !info.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(seedDir, "seed.yaml"))
+		if err != nil {
+			findings = append(findings, LintFinding{Check: "seed", Severity: "error", Feature: f.ID, Message: "seed directory has no seed.yaml"})
+			continue
+		}
+		sums := seedChecksums(string(data))
+		schemas := seedSchemas(string(data))
+		for _, file := range parseSeedManifestFiles(string(data)) {
+			content, err := os.ReadFile(filepath.Join(seedDir, file))
+			if os.IsNotExist(err) {
+				findings = append(findings, LintFinding{Check: "seed", Severity: "error", Feature: f.ID, Message: "seed manifest references missing file: " + file})
+				continue
+			}
+			if sum, ok := sums[file]; ok && err == nil && contentHash(content) != sum {
+				findings = append(findings, LintFinding{Check: "seed", Severity: "warn", Feature: f.ID, Message: "seed " + file + " does not match its recorded sha256 (re-run ptsd seed snapshot)"})
+			}
+			// Broken seed data fails here instead of in the tests that load it.
+			for _, msg := range seedFileProblems(seedDir, file, schemas[file]) {
+				findings = append(findings, LintFinding{Check: "seed", Severity: "error", Feature: f.ID, Message: "seed " + file + ": " + msg})
+			}
+		}
+	}
+	return findings
+}
+
+// fsck checks that the .ptsd files agree with each other: unique feature IDs,
+// known statuses, and no state, review or task entries for unknown features.
+func fsck(projectDir string, features []Feature) []LintFinding {
+	var findings []LintFinding
+	known := make(map[string]bool)
+	for _, f := range features {
+		if known[f.ID] {
+			findings = append(findings, LintFinding{Check: "fsck", Severity: "error", Feature: f.ID, Message: "duplicate feature id in features.yaml"})
+		}
+		known[f.ID] = true
+		if !validStatuses[f.Status] {
+			findings = append(findings, LintFinding{Check: "fsck", Severity: "warn", Feature: f.ID, Message: "unknown status " + f.Status})
+		}
+	}
+
+	if state, err := LoadState(projectDir); err == nil {
+		for _, id := range sortedKeys(state.Features) {
+			if !known[id] {
+				findings = append(findings, LintFinding{Check: "fsck", Severity: "warn", Feature: id, Message: "state.yaml entry for unknown feature"})
+			}
+		}
+	}
+	if rs, err := loadReviewStatus(projectDir); err == nil {
+		for _, id := range sortedKeys(rs) {
+			if !known[id] {
+				findings = append(findings, LintFinding{Check: "fsck", Severity: "warn", Feature: id, Message: "review-status.yaml entry for unknown feature"})
+			}
+		}
+	}
+	if tasks, err := loadTasks(projectDir); err == nil {
+		for _, t := range tasks {
+			if t.Feature != "" && !known[t.Feature] {
+				findings = append(findings, LintFinding{Check: "fsck", Severity: "error", Feature: t.Feature, Message: "task " + t.ID + " references unknown feature"})
+			}
+		}
+	}
+	return findings
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
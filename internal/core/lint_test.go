@@ -0,0 +1,107 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func hasFinding(findings []LintFinding, check, severity, feature string) bool {
+	for _, f := range findings {
+		if f.Check == check && f.Severity == severity && f.Feature == feature {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLintCollectsFindingsAcrossChecks(t *testing.T) {
+	dir := setupProjectWithFeature(t, "user-auth", func(base string) {
+		writeFeaturesYAML(t, base, "- id: user-auth\n  title: Auth\n  status: in-progress\n")
+		createPRDAnchor(t, base, "user-auth")
+		os.WriteFile(filepath.Join(base, "bdd", "user-auth.feature"), []byte("@feature:user-auth\nFeature: Auth\n  @id:a\n  Scenario: One\n    Given x\n  @id:a\n  Scenario: Two\n"), 0644)
+		os.MkdirAll(filepath.Join(base, "seeds", "user-auth"), 0755)
+		os.WriteFile(filepath.Join(base, "seeds", "user-auth", "seed.yaml"), []byte("feature: user-auth\nfiles:\n  - path: users.json\n"), 0644)
+		os.WriteFile(filepath.Join(base, "review-status.yaml"), []byte("features:\n  ghost:\n    stage: prd\n    tests: absent\n    review: pending\n"), 0644)
+	})
+
+	findings, err := Lint(dir)
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	if !hasFinding(findings, "bdd", "error", "user-auth") {
+		t.Errorf("expected duplicate scenario id error, got %+v", findings)
+	}
+	if !hasFinding(findings, "bdd", "warn", "user-auth") {
+		t.Errorf("expected empty scenario warning, got %+v", findings)
+	}
+	if !hasFinding(findings, "seed", "error", "user-auth") {
+		t.Errorf("expected missing seed file error, got %+v", findings)
+	}
+	if !hasFinding(findings, "fsck", "warn", "ghost") {
+		t.Errorf("expected unknown feature warning from fsck, got %+v", findings)
+	}
+}
+
+func TestLintCleanProject(t *testing.T) {
+	dir := setupCleanProject(t)
+	findings, err := Lint(dir)
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	for _, f := range findings {
+		if f.Severity == "error" {
+			t.Errorf("unexpected error finding: %+v", f)
+		}
+	}
+}
+
+func TestLintWithoutPtsdDir(t *testing.T) {
+	if _, err := Lint(t.TempDir()); err == nil {
+		t.Error("expected err:config without .ptsd")
+	}
+}
+
+func TestLintReportsGherkinSyntaxErrors(t *testing.T) {
+	dir := setupProjectWithFeature(t, "user-auth", func(base string) {
+		writeFeaturesYAML(t, base, "- id: user-auth\n  title: Auth\n  status: in-progress\n")
+		createPRDAnchor(t, base, "user-auth")
+		os.WriteFile(filepath.Join(base, "bdd", "user-auth.feature"), []byte("@feature:user-auth\nFeature: Auth\n  Scenario: One\n    Given x\n    Examples:\n"), 0644)
+	})
+
+	findings, err := Lint(dir)
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	found := false
+	for _, f := range findings {
+		if f.Check == "bdd" && f.Severity == "error" && f.Message == "user-auth.feature line 5: Examples outside a Scenario Outline" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected syntax error finding, got %+v", findings)
+	}
+}
+
+func TestLintReportsDuplicatePRDAnchor(t *testing.T) {
+	dir := setupProjectWithFeature(t, "user-auth", func(base string) {
+		writeFeaturesYAML(t, base, "- id: user-auth\n  title: Auth\n  status: in-progress\n")
+		createPRDAnchor(t, base, "user-auth")
+		os.WriteFile(filepath.Join(base, "docs", "auth.md"), []byte("<!-- feature:user-auth -->\nAgain\n"), 0644)
+	})
+
+	findings, err := Lint(dir)
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	for _, f := range findings {
+		if f.Check == "prd" && f.Feature == "user-auth" {
+			if f.Message != "prd anchor appears in several files: .ptsd/docs/PRD.md, .ptsd/docs/auth.md" {
+				t.Errorf("unexpected message: %s", f.Message)
+			}
+			return
+		}
+	}
+	t.Errorf("expected a prd finding for the duplicate anchor, got %+v", findings)
+}
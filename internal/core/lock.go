@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Project lock. Commands that change .ptsd hold an advisory lock on
+// .ptsd/.lock (flock on unix) for their whole run, so agents and hooks doing
+// read-modify-write on state.yaml, tasks.yaml and friends serialize instead
+// of losing each other's updates. The lock is reentrant within a process;
+// long-running servers (daemon, review serve) take it per request.
+
+// DefaultLockWait is how long a command waits for the project lock before
+// failing; --wait-lock overrides it.
+const DefaultLockWait = 10 * time.Second
+
+const lockFile = ".lock"
+
+var projectLocks = struct {
+	sync.Mutex
+	wait time.Duration
+	held map[string]*heldLock
+}{wait: DefaultLockWait, held: make(map[string]*heldLock)}
+
+type heldLock struct {
+	depth   int
+	release func()
+}
+
+// SetLockWait sets how long LockProject waits; 0 fails at once when the
+// lock is taken.
+func SetLockWait(d time.Duration) {
+	projectLocks.Lock()
+	projectLocks.wait = d
+	projectLocks.Unlock()
+}
+
+// LockWait returns the current SetLockWait timeout.
+func LockWait() time.Duration {
+	projectLocks.Lock()
+	defer projectLocks.Unlock()
+	return projectLocks.wait
+}
+
+// LockProject takes the project lock, waiting up to the SetLockWait timeout,
+// and returns the function that releases it. The holder's pid is written to
+// the lock file so a timeout can name it.
+func LockProject(projectDir string) (unlock func(), err error) {
+	path, err := filepath.Abs(filepath.Join(projectDir, ".ptsd", lockFile))
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	projectLocks.Lock()
+	defer projectLocks.Unlock()
+	if h, ok := projectLocks.held[path]; ok {
+		h.depth++
+		return func() { unlockProject(path) }, nil
+	}
+
+	deadline := time.Now().Add(projectLocks.wait)
+	for {
+		release, ok, err := tryLockFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("err:io %w", err)
+		}
+		if ok {
+			projectLocks.held[path] = &heldLock{depth: 1, release: release}
+			return func() { unlockProject(path) }, nil
+		}
+		if !time.Now().Before(deadline) {
+			holder := "another ptsd"
+			if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) != "" {
+				holder = strings.TrimSpace(string(data))
+			}
+			return nil, fmt.Errorf("err:io project is locked by %s (.ptsd/%s); retry, or wait longer with --wait-lock", holder, lockFile)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func unlockProject(path string) {
+	projectLocks.Lock()
+	defer projectLocks.Unlock()
+	h, ok := projectLocks.held[path]
+	if !ok {
+		return
+	}
+	if h.depth--; h.depth == 0 {
+		h.release()
+		delete(projectLocks.held, path)
+	}
+}
+
+// lockOwner is what the lock file records about its holder.
+func lockOwner() string {
+	return fmt.Sprintf("pid %d", os.Getpid())
+}
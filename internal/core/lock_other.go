@@ -0,0 +1,23 @@
+//go:build !unix
+
+package core
+
+import (
+	"errors"
+	"os"
+)
+
+// tryLockFile creates path exclusively and removes it on release. Without
+// flock a crashed ptsd leaves the file behind; delete it by hand.
+func tryLockFile(path string) (release func(), ok bool, err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	f.WriteString(lockOwner() + "\n")
+	f.Close()
+	return func() { os.Remove(path) }, true, nil
+}
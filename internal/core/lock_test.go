@@ -0,0 +1,52 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockProject(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	SetLockWait(50 * time.Millisecond)
+	defer SetLockWait(DefaultLockWait)
+
+	unlock, err := LockProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reentrant within the process.
+	inner, err := LockProject(dir)
+	if err != nil {
+		t.Fatalf("nested lock: %v", err)
+	}
+	inner()
+
+	// A second holder (another open file, as another process would have)
+	// cannot take it while the outer lock is held.
+	path := filepath.Join(dir, ".ptsd", lockFile)
+	if release, ok, err := tryLockFile(path); err != nil || ok {
+		if ok {
+			release()
+		}
+		t.Fatalf("lock taken twice: ok=%v err=%v", ok, err)
+	}
+	unlock()
+
+	release, ok, err := tryLockFile(path)
+	if err != nil || !ok {
+		t.Fatalf("lock not released: ok=%v err=%v", ok, err)
+	}
+	_, err = LockProject(dir)
+	if err == nil || !strings.Contains(err.Error(), "err:io project is locked by pid") {
+		t.Errorf("err = %v", err)
+	}
+	release()
+
+	unlock, err = LockProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+}
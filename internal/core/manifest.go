@@ -0,0 +1,115 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// genManifest records the generator version and the content hash of every file
+// ptsd generates (skills, Claude hooks, settings.json) in .ptsd/generated.yaml.
+// On re-init a file is only regenerated when its current content still matches
+// the recorded hash; files the user edited, and existing files with no
+// recorded hash that differ from the new output, are kept and reported as
+// Skipped.
+type genManifest struct {
+	dir     string
+	Version string
+	Hashes  map[string]string // project-relative slash path → sha256
+	force   bool
+	Skipped []string
+}
+
+func generatedManifestPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "generated.yaml")
+}
+
+// generatorVersion is the ptsd module version stamped into the manifest.
+func generatorVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+func loadGenManifest(projectDir string) *genManifest {
+	m := &genManifest{dir: projectDir, Hashes: make(map[string]string)}
+
+	data, err := os.ReadFile(generatedManifestPath(projectDir))
+	if err != nil {
+		return m
+	}
+	inFiles := false
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "generator:"):
+			m.Version = stripQuotes(strings.TrimSpace(strings.TrimPrefix(line, "generator:")))
+		case line == "files:":
+			inFiles = true
+		case inFiles && strings.HasPrefix(line, "  ") && strings.Contains(trimmed, ": "):
+			parts := strings.SplitN(trimmed, ": ", 2)
+			m.Hashes[stripQuotes(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return m
+}
+
+func (m *genManifest) save() error {
+	if m == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(m.Hashes))
+	for k := range m.Hashes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("generator: " + generatorVersion() + "\n")
+	b.WriteString("files:\n")
+	for _, k := range keys {
+		b.WriteString("  " + k + ": " + m.Hashes[k] + "\n")
+	}
+	return writeFile(generatedManifestPath(m.dir), b.String())
+}
+
+// write writes a generated file, skipping it when the user has modified the
+// previously generated content. A nil manifest writes unconditionally.
+func (m *genManifest) write(path, content string, perm os.FileMode) error {
+	if m == nil {
+		if err := writeFileAtomic(path, []byte(content), perm); err != nil {
+			return fmt.Errorf("err:io %w", err)
+		}
+		return nil
+	}
+
+	rel, err := filepath.Rel(m.dir, path)
+	if err != nil {
+		rel = path
+	}
+	rel = filepath.ToSlash(rel)
+	newHash := contentHash([]byte(content))
+
+	if !m.force {
+		// A file the manifest does not track yet (a project that predates
+		// it, or a user's own file) may be customized just the same: only
+		// the recorded hash proves it is ptsd's untouched output.
+		if cur, err := os.ReadFile(path); err == nil {
+			curHash := contentHash(cur)
+			if old, tracked := m.Hashes[rel]; curHash != newHash && (!tracked || curHash != old) {
+				m.Skipped = append(m.Skipped, rel)
+				return nil
+			}
+		}
+	}
+
+	if err := writeFileAtomic(path, []byte(content), perm); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	m.Hashes[rel] = newHash
+	return nil
+}
@@ -0,0 +1,287 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema versions. ptsd.yaml, state.yaml and tasks.yaml carry a top-level
+// `schema: N` line; a file without one predates versioning and is schema 1.
+// Loaders upgrade older content in memory, so an old layout is never
+// misread, and refuse files from a newer ptsd. `ptsd migrate` rewrites the
+// files on disk after snapshotting .ptsd/.
+
+// SchemaVersion is the layout this ptsd reads and writes.
+const SchemaVersion = 3
+
+// schemaFiles are the versioned files under .ptsd/.
+var schemaFiles = []string{"ptsd.yaml", "state.yaml", "tasks.yaml"}
+
+// schemaMigration upgrades file contents from To-1 to To. A file without an
+// entry in Apply only gets its schema line bumped.
+type schemaMigration struct {
+	To    int
+	Apply map[string]func(string) string
+}
+
+var schemaMigrations = []schemaMigration{
+	{To: 2, Apply: map[string]func(string) string{
+		"ptsd.yaml":  migrateConfigPatterns,
+		"state.yaml": migrateStateScores,
+		"tasks.yaml": migrateTaskFields,
+	}},
+	{To: 3, Apply: map[string]func(string) string{
+		"state.yaml": migrateStatePaths,
+	}},
+}
+
+var schemaLineRe = regexp.MustCompile(`^schema:\s*(\d+)\s*$`)
+
+// schemaVersion returns the top-level schema: value, 1 when absent.
+func schemaVersion(content string) int {
+	for _, line := range strings.Split(content, "\n") {
+		if m := schemaLineRe.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			return n
+		}
+	}
+	return 1
+}
+
+// schemaHeader is the line writers put at the top of versioned files.
+func schemaHeader() string {
+	return "schema: " + strconv.Itoa(SchemaVersion) + "\n"
+}
+
+// upgradeSchema brings the contents of a versioned file up to SchemaVersion.
+// Content already current is returned unchanged.
+func upgradeSchema(name, content string) (string, error) {
+	v := schemaVersion(content)
+	if v > SchemaVersion {
+		return "", fmt.Errorf("err:config %s has schema %d, newer than this ptsd supports (%d): upgrade ptsd", name, v, SchemaVersion)
+	}
+	if v == SchemaVersion {
+		return content, nil
+	}
+	for _, m := range schemaMigrations {
+		if m.To <= v {
+			continue
+		}
+		if apply := m.Apply[name]; apply != nil {
+			content = apply(content)
+		}
+	}
+	return setSchemaLine(content), nil
+}
+
+// setSchemaLine replaces the top-level schema line, or adds one at the top.
+func setSchemaLine(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if schemaLineRe.MatchString(strings.TrimRight(line, "\r")) {
+			lines[i] = strings.TrimSuffix(schemaHeader(), "\n")
+			return strings.Join(lines, "\n")
+		}
+	}
+	return schemaHeader() + content
+}
+
+// migrateConfigPatterns: schema 1 accepted `testing.patterns: [...]`, which
+// later versions ignore; it becomes `patterns:` / `files: [...]`.
+func migrateConfigPatterns(content string) string {
+	lines := strings.Split(content, "\n")
+	section := ""
+	for i, line := range lines {
+		if strings.HasSuffix(line, ":") && !strings.HasPrefix(line, " ") {
+			section = strings.TrimSuffix(line, ":")
+			continue
+		}
+		if section != "testing" {
+			continue
+		}
+		if list, ok := strings.CutPrefix(line, "  patterns: ["); ok {
+			lines[i] = "  patterns:\n    files: [" + list
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// migrateStateScores: schema 1 stored a review score as `<stage>: N`; it
+// becomes a score block with an unknown (zero) time.
+func migrateStateScores(content string) string {
+	lines := strings.Split(content, "\n")
+	inScores := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		switch {
+		case indent == 4:
+			inScores = trimmed == "scores:"
+		case indent < 4 && trimmed != "":
+			inScores = false
+		case inScores && indent == 6:
+			stage, val, ok := strings.Cut(trimmed, ": ")
+			if n, err := strconv.Atoi(val); ok && err == nil {
+				lines[i] = "      " + stage + ":\n        score: " + strconv.Itoa(n) +
+					"\n        at: \"" + time.Time{}.Format(time.RFC3339Nano) + "\""
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// taskStatusAliases maps schema 1 spellings to task statuses.
+var taskStatusAliases = map[string]string{
+	"todo": "TODO", "open": "TODO",
+	"wip": "WIP", "doing": "WIP", "in_progress": "WIP", "in-progress": "WIP",
+	"done": "DONE", "closed": "DONE",
+}
+
+// migrateTaskFields: schema 1 allowed lowercase and alias statuses (which
+// task next skipped) and tasks without a priority (which sorted before A).
+// Statuses are normalized and a missing priority becomes B.
+func migrateTaskFields(content string) string {
+	var out []string
+	hasPriority := true
+	// endTask adds the missing priority inside the task just read, before
+	// any blank lines (loadTasks ends a task at a blank line).
+	endTask := func() {
+		if !hasPriority {
+			end := len(out)
+			for end > 0 && strings.TrimSpace(out[end-1]) == "" {
+				end--
+			}
+			out = append(out[:end], append([]string{"    priority: B"}, out[end:]...)...)
+		}
+		hasPriority = true
+	}
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "- id: "):
+			endTask()
+			hasPriority = false
+		case !strings.HasPrefix(line, "  ") && trimmed != "":
+			endTask()
+		case strings.HasPrefix(trimmed, "priority: "):
+			hasPriority = true
+		}
+		if status, ok := strings.CutPrefix(trimmed, "status: "); ok {
+			if s, ok := taskStatusAliases[strings.ToLower(stripQuotes(status))]; ok {
+				line = "    status: " + s
+			}
+		}
+		out = append(out, line)
+	}
+	endTask()
+	return strings.Join(out, "\n")
+}
+
+// migrateStatePaths: before schema 3, test mappings and unmapped files
+// written on Windows kept backslashes, which unix checkouts never matched;
+// they become forward slashes.
+func migrateStatePaths(content string) string {
+	lines := strings.Split(content, "\n")
+	inList := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		switch {
+		case indent == 0 || indent == 4:
+			inList = trimmed == "unmapped:" || trimmed == "tests:"
+		case inList && strings.HasPrefix(trimmed, "- "):
+			lines[i] = strings.Repeat(" ", indent) + slashPath(trimmed)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SchemaStatus is the on-disk schema of one versioned file.
+type SchemaStatus struct {
+	File    string
+	Version int
+	// Outdated is true when the file is older than SchemaVersion.
+	Outdated bool
+}
+
+// MigrateResult describes a `ptsd migrate` run.
+type MigrateResult struct {
+	Files []SchemaStatus
+	// Snapshot names the backup taken before rewriting; empty when nothing
+	// was rewritten.
+	Snapshot string
+}
+
+// CheckSchema reports the schema of each versioned file that exists.
+func CheckSchema(projectDir string) ([]SchemaStatus, error) {
+	var out []SchemaStatus
+	for _, name := range schemaFiles {
+		data, err := os.ReadFile(filepath.Join(projectDir, ".ptsd", name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("err:io %w", err)
+		}
+		v := schemaVersion(string(data))
+		out = append(out, SchemaStatus{File: name, Version: v, Outdated: v < SchemaVersion})
+	}
+	return out, nil
+}
+
+// Migrate rewrites outdated versioned files to SchemaVersion. The whole of
+// .ptsd/ is snapshotted first (restore with ptsd restore <name>). dryRun
+// only reports what would change. Files from a newer ptsd are an error.
+func Migrate(projectDir string, dryRun bool) (MigrateResult, error) {
+	if _, err := os.Stat(filepath.Join(projectDir, ".ptsd")); err != nil {
+		return MigrateResult{}, fmt.Errorf("err:config .ptsd not found: run ptsd init")
+	}
+	files, err := CheckSchema(projectDir)
+	if err != nil {
+		return MigrateResult{}, err
+	}
+	result := MigrateResult{Files: files}
+	upgraded := make(map[string]string)
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(projectDir, ".ptsd", f.File))
+		if err != nil {
+			return MigrateResult{}, fmt.Errorf("err:io %w", err)
+		}
+		content, err := upgradeSchema(f.File, string(data))
+		if err != nil {
+			return MigrateResult{}, err
+		}
+		if f.Outdated {
+			upgraded[f.File] = content
+		}
+	}
+	if len(upgraded) == 0 || dryRun {
+		return result, nil
+	}
+
+	name, err := CreateSnapshot(projectDir, "pre-migrate-"+time.Now().UTC().Format("20060102-150405"))
+	if err != nil {
+		return MigrateResult{}, err
+	}
+	result.Snapshot = name
+	for _, file := range schemaFiles {
+		content, ok := upgraded[file]
+		if !ok {
+			continue
+		}
+		if file == "state.yaml" {
+			err = signFileWrite(projectDir, file, []byte(content))
+		} else if werr := writeFileAtomic(filepath.Join(projectDir, ".ptsd", file), []byte(content), 0644); werr != nil {
+			err = fmt.Errorf("err:io %w", werr)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
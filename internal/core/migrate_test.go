@@ -0,0 +1,159 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const legacyTasks = `tasks:
+  - id: T-1
+    feature: auth
+    title: login
+    status: in_progress
+
+  - id: T-2
+    feature: auth
+    title: logout
+    status: todo
+    priority: A
+`
+
+const legacyState = `features:
+  auth:
+    stage: impl
+    hashes:
+      prd: abc
+    scores:
+      prd: 8
+`
+
+func writePtsdFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadersUpgradeLegacySchema(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	writePtsdFile(t, dir, "tasks.yaml", legacyTasks)
+	writePtsdFile(t, dir, "state.yaml", legacyState)
+	writePtsdFile(t, dir, "ptsd.yaml", "project:\n  name: legacy\ntesting:\n  patterns: [\"**/*.spec.ts\"]\n")
+
+	tasks, err := ListTasks(dir, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || tasks[0].Status != "WIP" || tasks[0].Priority != "B" || tasks[1].Status != "TODO" {
+		t.Errorf("expected normalized legacy tasks, got %+v", tasks)
+	}
+
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc := state.Features["auth"].Scores["prd"]; sc.Value != 8 {
+		t.Errorf("expected legacy score 8, got %+v", sc)
+	}
+
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Testing.Patterns.Files) != 1 || cfg.Testing.Patterns.Files[0] != "**/*.spec.ts" {
+		t.Errorf("expected legacy testing.patterns to be read, got %v", cfg.Testing.Patterns.Files)
+	}
+}
+
+func TestLoadersRefuseNewerSchema(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	writePtsdFile(t, dir, "tasks.yaml", "schema: 99\ntasks: []\n")
+
+	_, err := ListTasks(dir, "", "")
+	if err == nil || !strings.Contains(err.Error(), "err:config tasks.yaml has schema 99") {
+		t.Errorf("expected newer-schema error, got %v", err)
+	}
+	if _, err := Migrate(dir, false); err == nil {
+		t.Error("expected migrate to refuse a newer schema")
+	}
+}
+
+func TestMigrateRewritesWithSnapshot(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	writePtsdFile(t, dir, "tasks.yaml", legacyTasks)
+	writePtsdFile(t, dir, "state.yaml", legacyState)
+
+	dry, err := Migrate(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dry.Snapshot != "" {
+		t.Errorf("dry run must not snapshot, got %q", dry.Snapshot)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "tasks.yaml")); string(data) != legacyTasks {
+		t.Errorf("dry run must not rewrite tasks.yaml, got:\n%s", data)
+	}
+
+	result, err := Migrate(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result.Snapshot, "pre-migrate-") {
+		t.Errorf("expected a pre-migrate snapshot, got %q", result.Snapshot)
+	}
+	if _, err := os.Stat(snapshotPath(dir, result.Snapshot)); err != nil {
+		t.Errorf("snapshot archive missing: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "tasks.yaml"))
+	for _, want := range []string{"schema: 3\n", "    status: WIP\n    priority: B\n\n", "    status: TODO\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("migrated tasks.yaml missing %q:\n%s", want, data)
+		}
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, ".ptsd", "state.yaml"))
+	if !strings.Contains(string(data), "      prd:\n        score: 8\n") {
+		t.Errorf("migrated state.yaml missing score block:\n%s", data)
+	}
+
+	files, _ := CheckSchema(dir)
+	for _, f := range files {
+		if f.Outdated {
+			t.Errorf("%s still outdated after migrate", f.File)
+		}
+	}
+	again, err := Migrate(dir, false)
+	if err != nil || again.Snapshot != "" {
+		t.Errorf("second migrate should be a no-op, got %+v, %v", again, err)
+	}
+}
+
+func TestMigrateStatePathsToForwardSlashes(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	writePtsdFile(t, dir, "state.yaml", "schema: 2\nfeatures:\n  auth:\n    stage: tests\n    tests:\n      - internal\\auth\\auth_test.go\n      - bdd\\auth.feature::Login::internal\\auth\\login_test.go\nunmapped:\n  - pkg\\util\\util_test.go\n")
+
+	if _, err := Migrate(dir, false); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".ptsd", "state.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `\`) {
+		t.Errorf("expected forward slashes after migrate, got:\n%s", data)
+	}
+	for _, want := range []string{"schema: 3\n", "      - internal/auth/auth_test.go\n", "      - bdd/auth.feature::Login::internal/auth/login_test.go\n", "  - pkg/util/util_test.go\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in migrated state, got:\n%s", want, data)
+		}
+	}
+}
+
+func TestParseTestMappingAcceptsBackslashes(t *testing.T) {
+	m := parseTestMapping(`bdd\auth.feature#login::internal\auth\login_test.go`)
+	if m.BDD != "bdd/auth.feature" || m.Scenario != "login" || m.File != "internal/auth/login_test.go" {
+		t.Errorf("expected slash-normalized mapping, got %+v", m)
+	}
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"fmt"
+	"net"
+)
+
+// Air-gapped mode. With network.air_gapped set, every code path that can
+// open a connection off the machine goes through CheckNetwork or
+// CheckListenAddr and fails with err:config instead: remote push/pull, seed
+// snapshot --request, and review serve on anything but loopback. The setting
+// is read from the merged config, so an extends policy can turn it on; the
+// policy is then no longer refreshed either (see extendsResolver). The daemon
+// socket and git hooks are local and unaffected.
+
+// CheckNetwork refuses what, an operation that needs the network, when the
+// project is air-gapped. A missing or unreadable config is not air-gapped.
+func CheckNetwork(projectDir, what string) error {
+	if cfg, err := LoadConfig(projectDir); err == nil && cfg.Network.AirGapped {
+		return fmt.Errorf("err:config %s needs the network, which network.air_gapped disables", what)
+	}
+	return nil
+}
+
+// CheckListenAddr allows an air-gapped project to listen on loopback only;
+// an empty host (all interfaces) counts as network access.
+func CheckListenAddr(projectDir, what, addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("err:user invalid listen address %q", addr)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return CheckNetwork(projectDir, what+" on "+addr)
+}
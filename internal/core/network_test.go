@@ -0,0 +1,101 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAirGapped(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	dir := setupProjectWithFeatures(t, "catalog:in-progress")
+	InitSeed(dir, "catalog")
+	cfg := "remote:\n  url: " + srv.URL + "\nseed_requests:\n  products:\n    url: " + srv.URL + "/products\nnetwork:\n  air_gapped: true\n"
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte(cfg), 0644)
+
+	isAirGapped := func(err error) bool {
+		return err != nil && strings.HasPrefix(err.Error(), "err:config") && strings.Contains(err.Error(), "network.air_gapped")
+	}
+	if _, err := RemotePush(dir); !isAirGapped(err) {
+		t.Errorf("remote push: %v", err)
+	}
+	if _, err := RemotePull(dir); !isAirGapped(err) {
+		t.Errorf("remote pull: %v", err)
+	}
+	if _, err := SnapshotSeed(dir, "catalog", "products.json", SeedSnapshotOptions{Request: "products"}); !isAirGapped(err) {
+		t.Errorf("seed request: %v", err)
+	}
+	if err := ServeReviews(dir, "0.0.0.0:0", "tok", nil, nil); !isAirGapped(err) {
+		t.Errorf("review serve on all interfaces: %v", err)
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("air-gapped project made %d requests", n)
+	}
+
+	for _, addr := range []string{"127.0.0.1:8787", "[::1]:8787", "localhost:8787"} {
+		if err := CheckListenAddr(dir, "review serve", addr); err != nil {
+			t.Errorf("loopback %s: %v", addr, err)
+		}
+	}
+
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("remote:\n  url: "+srv.URL+"\n"), 0644)
+	if err := CheckListenAddr(dir, "review serve", ":8787"); err != nil {
+		t.Errorf("not air-gapped: %v", err)
+	}
+	if _, err := RemotePull(dir); err != nil {
+		t.Errorf("remote pull without air_gapped: %v", err)
+	}
+	if hits.Load() == 0 {
+		t.Error("expected remote pull to reach the server")
+	}
+}
+
+func TestAirGappedByExtendsPolicy(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte("network:\n  air_gapped: true\n"))
+	}))
+	defer srv.Close()
+
+	dir := setupProjectWithFeatures(t, "catalog:in-progress")
+	os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte("network:\n  air_gapped: true\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("extends: policy.yaml\nremote:\n  url: "+srv.URL+"\n"), 0644)
+
+	if _, err := RemotePull(dir); err == nil || !strings.Contains(err.Error(), "network.air_gapped") {
+		t.Errorf("remote pull under an air-gapped policy: %v", err)
+	}
+	if err := CheckListenAddr(dir, "review serve", ":8787"); err == nil {
+		t.Error("review serve on all interfaces allowed under an air-gapped policy")
+	}
+
+	// A fetched policy that air-gaps the project is not refreshed over the
+	// network once cached.
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("extends: "+srv.URL+"/policy.yaml\n"), 0644)
+	if cfg, err := LoadConfig(dir); err != nil || !cfg.Network.AirGapped {
+		t.Fatalf("expected the fetched policy to air-gap the project, got %+v %v", cfg, err)
+	}
+	entries, _ := os.ReadDir(extendsCacheDir(dir))
+	old := time.Now().Add(-2 * ExtendsTTL)
+	for _, e := range entries {
+		os.Chtimes(filepath.Join(extendsCacheDir(dir), e.Name()), old, old)
+	}
+	before := hits.Load()
+	if cfg, err := LoadConfig(dir); err != nil || !cfg.Network.AirGapped {
+		t.Errorf("expected the cached policy, got %+v %v", cfg, err)
+	}
+	if hits.Load() != before {
+		t.Error("air-gapped project refreshed its extends policy over the network")
+	}
+}
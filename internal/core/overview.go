@@ -0,0 +1,145 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FeatureOverview joins everything ptsd knows about a feature: the registry
+// entry, pipeline state, review status, acceptance-criteria coverage and task
+// counts. It backs `ptsd feature list --with-state`.
+type FeatureOverview struct {
+	Feature
+	Stage  string
+	Hashes map[string]string
+	Scores map[string]ScoreEntry
+	// Review is the review-status.yaml entry; zero when never reviewed.
+	Review    ReviewStatusEntry
+	TestFiles []string
+	// Criteria is the number of PRD acceptance criteria, Covered how many of
+	// them have a scenario, Scenarios the number of BDD scenarios.
+	Criteria  int
+	Covered   int
+	Scenarios int
+	// Tasks counts the feature's tasks by status (TODO, WIP, DONE).
+	Tasks map[string]int
+	// Score is the review score of the current stage, 0 when unreviewed.
+	Score int
+	// Progress is the share of the feature's pipeline stages behind it, in
+	// percent; an implemented feature is at 100.
+	Progress int
+	// LastActivity is the newest event or review score for the feature;
+	// zero when there is none.
+	LastActivity time.Time
+}
+
+// FeatureOverviews returns an overview of every feature matching
+// statusFilter (empty for all), in registry order.
+func FeatureOverviews(projectDir string, statusFilter string) ([]FeatureOverview, error) {
+	features, err := ListFeatures(projectDir, statusFilter)
+	if err != nil {
+		return nil, err
+	}
+	state, _ := LoadState(projectDir)
+	reviews, _ := loadReviewStatus(projectDir)
+	tasks, _ := loadTasks(projectDir)
+	events, _ := LoadEvents(projectDir)
+
+	lastEvent := make(map[string]time.Time)
+	for _, e := range events {
+		if e.Feature != "" && e.At.After(lastEvent[e.Feature]) {
+			lastEvent[e.Feature] = e.At
+		}
+	}
+
+	taskCounts := make(map[string]map[string]int)
+	for _, t := range tasks {
+		if taskCounts[t.Feature] == nil {
+			taskCounts[t.Feature] = make(map[string]int)
+		}
+		taskCounts[t.Feature][t.Status]++
+	}
+
+	out := make([]FeatureOverview, 0, len(features))
+	for _, f := range features {
+		o := FeatureOverview{Feature: f, Review: reviews[f.ID], Tasks: taskCounts[f.ID], LastActivity: lastEvent[f.ID]}
+		if o.Tasks == nil {
+			o.Tasks = make(map[string]int)
+		}
+
+		if state != nil {
+			fs := state.Features[f.ID]
+			o.Stage, o.Hashes, o.Scores = fs.Stage, fs.Hashes, fs.Scores
+			if list, ok := fs.Tests.([]string); ok {
+				_, o.TestFiles = splitTestMappings(list)
+			}
+			o.Score = fs.Scores[fs.Stage].Value
+			for _, sc := range fs.Scores {
+				if sc.Timestamp.After(o.LastActivity) {
+					o.LastActivity = sc.Timestamp
+				}
+			}
+		}
+		stages := FeatureStages(projectDir, f.ID)
+		if f.Status == "implemented" {
+			o.Progress = 100
+		} else if rank := stageRank(stages, o.Stage); rank > 0 {
+			o.Progress = rank * 100 / len(stages)
+		}
+
+		m, err := TraceFeature(projectDir, f.ID)
+		if err == nil {
+			o.Criteria = len(m.Criteria)
+			o.Covered = o.Criteria - len(m.Uncovered())
+		} else {
+			m = traceScenariosOnly(projectDir, f.ID)
+		}
+		o.Scenarios = len(m.Scenarios)
+
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+// FeatureSortKeys are the orders `feature list --sort` accepts.
+var FeatureSortKeys = []string{"id", "status", "stage", "coverage", "last-activity", "progress"}
+
+// featureStatusOrder ranks statuses from least to most done.
+var featureStatusOrder = map[string]int{"planned": 0, "in-progress": 1, "implemented": 2, "deferred": 3}
+
+// SortFeatureOverviews orders overviews by key (see FeatureSortKeys),
+// ascending: least advanced, least covered or longest idle first. Ties keep
+// registry order. Features without acceptance criteria sort before any
+// coverage, and features without activity before any timestamp.
+func SortFeatureOverviews(projectDir string, overviews []FeatureOverview, key string) error {
+	stages := PipelineStages(projectDir)
+	var less func(a, b FeatureOverview) bool
+	switch key {
+	case "id":
+		less = func(a, b FeatureOverview) bool { return a.ID < b.ID }
+	case "status":
+		less = func(a, b FeatureOverview) bool { return featureStatusOrder[a.Status] < featureStatusOrder[b.Status] }
+	case "stage":
+		less = func(a, b FeatureOverview) bool { return stageRank(stages, a.Stage) < stageRank(stages, b.Stage) }
+	case "coverage":
+		less = func(a, b FeatureOverview) bool { return a.CoverageRatio() < b.CoverageRatio() }
+	case "last-activity":
+		less = func(a, b FeatureOverview) bool { return a.LastActivity.Before(b.LastActivity) }
+	case "progress":
+		less = func(a, b FeatureOverview) bool { return a.Progress < b.Progress }
+	default:
+		return fmt.Errorf("err:user unknown sort key %q: use %s", key, strings.Join(FeatureSortKeys, "|"))
+	}
+	sort.SliceStable(overviews, func(i, j int) bool { return less(overviews[i], overviews[j]) })
+	return nil
+}
+
+// CoverageRatio is Covered/Criteria, -1 when the feature has no criteria.
+func (o FeatureOverview) CoverageRatio() float64 {
+	if o.Criteria == 0 {
+		return -1
+	}
+	return float64(o.Covered) / float64(o.Criteria)
+}
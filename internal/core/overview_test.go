@@ -0,0 +1,93 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeatureOverviews(t *testing.T) {
+	bdd := "@feature:login\nFeature: Login\n  @ac:AC-1\n  Scenario: Happy path\n    Given a user\n  Scenario: Remember me\n    Given a user\n"
+	dir := setupTraceFeature(t, tracePRD, bdd)
+	setupState(t, dir, map[string]string{"login": "bdd"})
+	setupTasks(t, dir,
+		Task{ID: "T-1", Feature: "login", Title: "a", Status: "TODO", Priority: "A"},
+		Task{ID: "T-2", Feature: "login", Title: "b", Status: "DONE", Priority: "B"},
+		Task{ID: "T-3", Feature: "login", Title: "c", Status: "DONE", Priority: "B"},
+	)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "review-status.yaml"),
+		[]byte("features:\n  login:\n    stage: bdd\n    tests: absent\n    review: pending\n    issues: 0\n"), 0644)
+
+	overviews, err := FeatureOverviews(dir, "")
+	if err != nil {
+		t.Fatalf("FeatureOverviews failed: %v", err)
+	}
+	if len(overviews) != 1 {
+		t.Fatalf("expected 1 overview, got %d", len(overviews))
+	}
+	o := overviews[0]
+	if o.ID != "login" || o.Stage != "bdd" {
+		t.Errorf("unexpected identity/stage: %+v", o)
+	}
+	if o.Review.Review != "pending" || o.Review.Tests != "absent" {
+		t.Errorf("review status not joined: %+v", o.Review)
+	}
+	if o.Criteria != 3 || o.Covered != 1 || o.Scenarios != 2 {
+		t.Errorf("coverage = %d/%d scenarios=%d, want 1/3 and 2", o.Covered, o.Criteria, o.Scenarios)
+	}
+	if o.Tasks["TODO"] != 1 || o.Tasks["DONE"] != 2 || o.Tasks["WIP"] != 0 {
+		t.Errorf("task counts = %v", o.Tasks)
+	}
+
+	// Filter by status.
+	if got, _ := FeatureOverviews(dir, "implemented"); len(got) != 0 {
+		t.Errorf("status filter ignored: %+v", got)
+	}
+}
+
+func TestSortFeatureOverviews(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "alpha:in-progress", "beta:implemented", "gamma:planned")
+	setupState(t, dir, map[string]string{"alpha": "tests", "gamma": "seed"})
+	at := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	AppendEvent(dir, Event{At: at, Type: EventStage, Feature: "gamma", Stage: "seed"})
+	AppendEvent(dir, Event{At: at.Add(time.Hour), Type: EventStage, Feature: "alpha", Stage: "tests"})
+
+	overviews, err := FeatureOverviews(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	progress := map[string]int{}
+	for _, o := range overviews {
+		progress[o.ID] = o.Progress
+	}
+	if progress["alpha"] != 60 || progress["beta"] != 100 || progress["gamma"] != 20 {
+		t.Errorf("unexpected progress: %v", progress)
+	}
+
+	ids := func() string {
+		var out []string
+		for _, o := range overviews {
+			out = append(out, o.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	for key, want := range map[string]string{
+		"progress":      "gamma,alpha,beta",
+		"status":        "gamma,alpha,beta",
+		"stage":         "beta,gamma,alpha",
+		"last-activity": "beta,gamma,alpha",
+		"id":            "alpha,beta,gamma",
+	} {
+		if err := SortFeatureOverviews(dir, overviews, key); err != nil {
+			t.Fatalf("sort %s: %v", key, err)
+		}
+		if got := ids(); got != want {
+			t.Errorf("sort %s = %s, want %s", key, got, want)
+		}
+	}
+	if err := SortFeatureOverviews(dir, overviews, "size"); err == nil || !strings.Contains(err.Error(), "err:user") {
+		t.Errorf("expected err:user for an unknown key, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Parse cache. A daemon serves many hook and context requests from one
+// process, so while it runs (see ServeDaemon) it keeps config, features,
+// state and BDD files in memory. A file whose mtime and size are unchanged
+// is not read again (readFileCached), and parsed values are memoized by
+// content, so edits made outside the daemon are seen on the next request
+// while unchanged files cost a stat. Every hit returns a copy, because
+// callers mutate what they load.
+
+var (
+	parseCacheOn   atomic.Bool
+	parseCacheHits atomic.Int64
+)
+
+// parseCacheLimit bounds each memo; a full memo starts over.
+const parseCacheLimit = 512
+
+// ParseCacheHits is how many loads the cache has answered without parsing.
+func ParseCacheHits() int64 {
+	return parseCacheHits.Load()
+}
+
+// EnableParseCache turns content memoization of parsed files on or off.
+func EnableParseCache(on bool) {
+	parseCacheOn.Store(on)
+	if !on {
+		files.mu.Lock()
+		files.entries = nil
+		files.mu.Unlock()
+		configMemo.reset()
+		featuresMemo.reset()
+		stateMemo.reset()
+		bddMemo.reset()
+	}
+}
+
+// racyWindow is how recently a file may have been modified and still be
+// read again on every call: a second write within a coarse mtime tick, with
+// the same size, would otherwise go unseen.
+const racyWindow = time.Second
+
+type cachedFile struct {
+	modTime time.Time
+	size    int64
+	data    []byte
+}
+
+var files struct {
+	mu      sync.Mutex
+	entries map[string]cachedFile
+}
+
+// readFileCached is os.ReadFile, except that while the cache is on a file
+// whose mtime and size are unchanged since it was last read comes from
+// memory.
+func readFileCached(path string) ([]byte, error) {
+	if !parseCacheOn.Load() {
+		return os.ReadFile(path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files.mu.Lock()
+	e, ok := files.entries[path]
+	files.mu.Unlock()
+	if ok && e.modTime.Equal(info.ModTime()) && e.size == info.Size() {
+		return e.data, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(info.ModTime()) > racyWindow && int64(len(data)) == info.Size() {
+		files.mu.Lock()
+		if files.entries == nil || len(files.entries) >= parseCacheLimit {
+			files.entries = make(map[string]cachedFile)
+		}
+		files.entries[path] = cachedFile{modTime: info.ModTime(), size: info.Size(), data: data}
+		files.mu.Unlock()
+	}
+	return data, nil
+}
+
+type parsed[T any] struct {
+	value T
+	err   error
+}
+
+type parseMemo[T any] struct {
+	mu      sync.Mutex
+	entries map[string]parsed[T]
+	clone   func(T) T
+}
+
+func (m *parseMemo[T]) reset() {
+	m.mu.Lock()
+	m.entries = nil
+	m.mu.Unlock()
+}
+
+// get returns parse(content), from the memo when the cache is on.
+func (m *parseMemo[T]) get(content string, parse func(string) (T, error)) (T, error) {
+	if !parseCacheOn.Load() {
+		return parse(content)
+	}
+	m.mu.Lock()
+	e, ok := m.entries[content]
+	m.mu.Unlock()
+	if ok {
+		parseCacheHits.Add(1)
+	} else {
+		v, err := parse(content)
+		e = parsed[T]{v, err}
+		m.mu.Lock()
+		if m.entries == nil || len(m.entries) >= parseCacheLimit {
+			m.entries = make(map[string]parsed[T])
+		}
+		m.entries[content] = e
+		m.mu.Unlock()
+	}
+	return m.clone(e.value), e.err
+}
+
+var (
+	configMemo   = &parseMemo[*Config]{clone: cloneConfig}
+	featuresMemo = &parseMemo[[]Feature]{clone: cloneFeatures}
+	stateMemo    = &parseMemo[*State]{clone: cloneState}
+	bddMemo      = &parseMemo[FeatureFileData]{clone: cloneFeatureFile}
+)
+
+func cloneConfig(c *Config) *Config {
+	if c == nil {
+		return nil
+	}
+	out := *c
+	out.Testing.Patterns.Files = slices.Clone(c.Testing.Patterns.Files)
+	out.Hooks.Scopes = slices.Clone(c.Hooks.Scopes)
+	out.Hooks.Types = slices.Clone(c.Hooks.Types)
+	out.Pipeline.Stages = slices.Clone(c.Pipeline.Stages)
+	out.SeedRequests = maps.Clone(c.SeedRequests)
+	if c.Features != nil {
+		out.Features = make(map[string]FeatureOverride, len(c.Features))
+		for id, o := range c.Features {
+			o.Patterns = slices.Clone(o.Patterns)
+			out.Features[id] = o
+		}
+	}
+	if c.Milestones != nil {
+		out.Milestones = make(map[string]Milestone, len(c.Milestones))
+		for name, m := range c.Milestones {
+			m.Features = slices.Clone(m.Features)
+			out.Milestones[name] = m
+		}
+	}
+	return &out
+}
+
+func cloneFeatures(features []Feature) []Feature {
+	out := slices.Clone(features)
+	for i := range out {
+		out[i].DependsOn = slices.Clone(out[i].DependsOn)
+	}
+	return out
+}
+
+func cloneState(s *State) *State {
+	if s == nil {
+		return nil
+	}
+	out := &State{Features: make(map[string]FeatureState, len(s.Features)), Unmapped: slices.Clone(s.Unmapped)}
+	for id, fs := range s.Features {
+		fs.Hashes = maps.Clone(fs.Hashes)
+		if fs.Scores != nil {
+			scores := make(map[string]ScoreEntry, len(fs.Scores))
+			for stage, e := range fs.Scores {
+				e.Reviewers = maps.Clone(e.Reviewers)
+				scores[stage] = e
+			}
+			fs.Scores = scores
+		}
+		if tests, ok := fs.Tests.([]string); ok {
+			fs.Tests = slices.Clone(tests)
+		}
+		out.Features[id] = fs
+	}
+	return out
+}
+
+func cloneFeatureFile(ff FeatureFileData) FeatureFileData {
+	ff.Scenarios = slices.Clone(ff.Scenarios)
+	for i := range ff.Scenarios {
+		ff.Scenarios[i].Steps = slices.Clone(ff.Scenarios[i].Steps)
+		ff.Scenarios[i].Tags = slices.Clone(ff.Scenarios[i].Tags)
+	}
+	return ff
+}
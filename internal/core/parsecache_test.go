@@ -0,0 +1,82 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCache(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	cfgPath := filepath.Join(dir, ".ptsd", "ptsd.yaml")
+	os.WriteFile(cfgPath, []byte("testing:\n  patterns:\n    files: [\"**/*_test.go\"]\n"), 0644)
+	EnableParseCache(true)
+	t.Cleanup(func() { EnableParseCache(false) })
+
+	hits := ParseCacheHits()
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Testing.Patterns.Files[0] = "mutated"
+	again, _ := LoadConfig(dir)
+	if ParseCacheHits() != hits+1 {
+		t.Errorf("second load of unchanged ptsd.yaml was not a cache hit")
+	}
+	if again.Testing.Patterns.Files[0] != "**/*_test.go" {
+		t.Errorf("cached config shares state with a caller: %v", again.Testing.Patterns.Files)
+	}
+
+	os.WriteFile(cfgPath, []byte("testing:\n  runner: go test ./...\n"), 0644)
+	if cfg, _ := LoadConfig(dir); cfg.Testing.Runner != "go test ./..." {
+		t.Errorf("edited ptsd.yaml not reloaded: runner %q", cfg.Testing.Runner)
+	}
+
+	state, _ := LoadState(dir)
+	state.Features["auth"] = FeatureState{Stage: "impl"}
+	if state, _ := LoadState(dir); state.Features["auth"].Stage == "impl" {
+		t.Error("cached state shares state with a caller")
+	}
+	features, _ := loadFeatures(dir)
+	features[0].Status = "implemented"
+	if features, _ := loadFeatures(dir); features[0].Status != "in-progress" {
+		t.Error("cached features share state with a caller")
+	}
+
+	EnableParseCache(false)
+	hits = ParseCacheHits()
+	LoadConfig(dir)
+	LoadConfig(dir)
+	if ParseCacheHits() != hits {
+		t.Error("cache answered while disabled")
+	}
+}
+
+func TestReadFileCachedSkipsUnchangedFiles(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	featPath := filepath.Join(dir, ".ptsd", "features.yaml")
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(featPath, old, old)
+	EnableParseCache(true)
+	t.Cleanup(func() { EnableParseCache(false) })
+
+	if features, err := loadFeatures(dir); err != nil || features[0].Status != "in-progress" {
+		t.Fatalf("unexpected features %+v %v", features, err)
+	}
+
+	// Same size and mtime: the daemon answers from memory without reading.
+	data, _ := os.ReadFile(featPath)
+	os.WriteFile(featPath, []byte(strings.Replace(string(data), "in-progress", "in-progresX", 1)), 0644)
+	os.Chtimes(featPath, old, old)
+	if features, _ := loadFeatures(dir); features[0].Status != "in-progress" {
+		t.Errorf("expected the file kept in memory, got %q", features[0].Status)
+	}
+
+	// A real edit moves the mtime and is seen on the next load.
+	os.WriteFile(featPath, []byte(strings.Replace(string(data), "in-progress", "implemented", 1)), 0644)
+	if features, _ := loadFeatures(dir); features[0].Status != "implemented" {
+		t.Errorf("edited features.yaml not reloaded: %q", features[0].Status)
+	}
+}
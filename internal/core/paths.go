@@ -0,0 +1,113 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Default artifact locations; paths.prd, paths.bdd and paths.seeds in
+// ptsd.yaml override them.
+const (
+	DefaultPRDPath  = ".ptsd/docs/PRD.md"
+	DefaultBDDDir   = ".ptsd/bdd"
+	DefaultSeedsDir = ".ptsd/seeds"
+)
+
+// cleanArtifactPath normalizes a paths.<key> value, which must stay inside
+// the project.
+func cleanArtifactPath(key, value string) (string, error) {
+	p := path.Clean(filepath.ToSlash(value))
+	if value == "" || path.IsAbs(p) || filepath.IsAbs(value) || p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("err:config invalid paths.%s: %s (must be a path inside the project)", key, value)
+	}
+	if key == "prd" && !strings.HasSuffix(p, ".md") {
+		return "", fmt.Errorf("err:config invalid paths.prd: %s (must be a .md file)", value)
+	}
+	return p, nil
+}
+
+// artifactPaths returns the project's artifact locations, the defaults when
+// ptsd.yaml cannot be loaded.
+func artifactPaths(projectDir string) PathsConfig {
+	cfg, err := LoadConfig(projectDir)
+	if err != nil {
+		return PathsConfig{PRD: DefaultPRDPath, BDD: DefaultBDDDir, Seeds: DefaultSeedsDir}
+	}
+	return cfg.Paths
+}
+
+// prdMainRel is the PRD file init creates and new sections are added to.
+func prdMainRel(projectDir string) string {
+	return artifactPaths(projectDir).PRD
+}
+
+// prdDirRel is the directory scanned for PRD parts.
+func prdDirRel(projectDir string) string {
+	return path.Dir(prdMainRel(projectDir))
+}
+
+// bddDirRel is the directory holding <feature>.feature files.
+func bddDirRel(projectDir string) string {
+	return artifactPaths(projectDir).BDD
+}
+
+func bddDirPath(projectDir string) string {
+	return filepath.Join(projectDir, filepath.FromSlash(bddDirRel(projectDir)))
+}
+
+// bddFileRel is featureID's BDD file, project-relative.
+func bddFileRel(projectDir, featureID string) string {
+	return bddDirRel(projectDir) + "/" + featureID + ".feature"
+}
+
+func bddFilePath(projectDir, featureID string) string {
+	return filepath.Join(projectDir, filepath.FromSlash(bddFileRel(projectDir, featureID)))
+}
+
+// seedsDirRel is the directory holding one seed directory per feature.
+func seedsDirRel(projectDir string) string {
+	return artifactPaths(projectDir).Seeds
+}
+
+// seedDirRel is featureID's seed directory, project-relative.
+func seedDirRel(projectDir, featureID string) string {
+	return seedsDirRel(projectDir) + "/" + featureID
+}
+
+func seedDirPath(projectDir, featureID string) string {
+	return filepath.Join(projectDir, filepath.FromSlash(seedDirRel(projectDir, featureID)))
+}
+
+func seedManifestPath(projectDir, featureID string) string {
+	return filepath.Join(seedDirPath(projectDir, featureID), "seed.yaml")
+}
+
+// bddFileFeature returns the feature ID of a project-relative path to a BDD
+// file, "" when rel is not one.
+func bddFileFeature(projectDir, rel string) string {
+	name, ok := strings.CutPrefix(filepath.ToSlash(rel), bddDirRel(projectDir)+"/")
+	if !ok || strings.Contains(name, "/") || !strings.HasSuffix(name, ".feature") {
+		return ""
+	}
+	return strings.TrimSuffix(name, ".feature")
+}
+
+// seedFileFeature returns the feature ID of a project-relative path inside a
+// seed directory and the path below it, ok false when rel is not one.
+func seedFileFeature(projectDir, rel string) (featureID, file string, ok bool) {
+	name, found := strings.CutPrefix(filepath.ToSlash(rel), seedsDirRel(projectDir)+"/")
+	if !found {
+		return "", "", false
+	}
+	featureID, file, _ = strings.Cut(name, "/")
+	return featureID, file, featureID != ""
+}
+
+// isPRDFile reports whether a project-relative path is a PRD part: a
+// markdown file directly in the PRD directory.
+func isPRDFile(projectDir, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	return path.Dir(rel) == prdDirRel(projectDir) && strings.HasSuffix(rel, ".md")
+}
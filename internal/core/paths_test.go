@@ -0,0 +1,109 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArtifactPathsDefaultAndConfigured(t *testing.T) {
+	dir := writeTestConfig(t, "project:\n  name: X\n")
+	if got := artifactPaths(dir); got != (PathsConfig{PRD: DefaultPRDPath, BDD: DefaultBDDDir, Seeds: DefaultSeedsDir}) {
+		t.Errorf("expected defaults, got %+v", got)
+	}
+
+	dir = writeTestConfig(t, "paths:\n  prd: ./docs/PRD.md\n  bdd: features/\n  seeds: testdata/seeds\n")
+	if got := artifactPaths(dir); got != (PathsConfig{PRD: "docs/PRD.md", BDD: "features", Seeds: "testdata/seeds"}) {
+		t.Errorf("expected cleaned custom paths, got %+v", got)
+	}
+	if got := bddFileRel(dir, "auth"); got != "features/auth.feature" {
+		t.Errorf("bddFileRel = %s", got)
+	}
+	if got := seedDirRel(dir, "auth"); got != "testdata/seeds/auth" {
+		t.Errorf("seedDirRel = %s", got)
+	}
+}
+
+func TestArtifactPathsRejectOutsideProject(t *testing.T) {
+	for _, bad := range []string{"paths:\n  bdd: ../features\n", "paths:\n  seeds: /tmp/seeds\n", "paths:\n  prd: docs/PRD.txt\n", "paths:\n  bdd: .\n"} {
+		if _, err := parseConfig(bad); err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+			t.Errorf("%q: expected err:config, got %v", bad, err)
+		}
+	}
+
+	dir := writeTestConfig(t, "project:\n  name: X\n")
+	if _, err := SetConfigValue(dir, "paths.bdd", "../features"); err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Errorf("expected err:user, got %v", err)
+	}
+	if _, err := SetConfigValue(dir, "paths.bdd", "features"); err != nil {
+		t.Fatalf("SetConfigValue: %v", err)
+	}
+	if got, _ := GetConfigValue(dir, "paths.bdd"); got != "features" {
+		t.Errorf("paths.bdd = %s", got)
+	}
+}
+
+func TestCustomArtifactPathsDriveThePipeline(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range map[string]string{
+		".ptsd/ptsd.yaml":     "paths:\n  prd: docs/PRD.md\n  bdd: features\n  seeds: testdata/seeds\n",
+		".ptsd/features.yaml": "features:\n  - id: auth\n    status: in-progress\n  - id: billing\n    status: planned\n",
+		"docs/PRD.md":         "# PRD\n\n<!-- feature:auth -->\n## Auth\n",
+		"docs/billing.md":     "<!-- feature:billing -->\n## Billing\n",
+	} {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := PRDFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(files, ","); got != "docs/PRD.md,docs/billing.md" {
+		t.Errorf("PRDFiles = %s", got)
+	}
+	if got := prdFileFor(dir, "billing"); got != "docs/billing.md" {
+		t.Errorf("billing anchor in %q", got)
+	}
+
+	if err := InitSeed(dir, "auth"); err != nil {
+		t.Fatalf("InitSeed: %v", err)
+	}
+	if !fileExists(filepath.Join(dir, "testdata", "seeds", "auth", "seed.yaml")) {
+		t.Error("seed.yaml not written under paths.seeds")
+	}
+	if err := AddBDD(dir, "auth"); err != nil {
+		t.Fatalf("AddBDD: %v", err)
+	}
+	if !fileExists(filepath.Join(dir, "features", "auth.feature")) {
+		t.Error("feature file not written under paths.bdd")
+	}
+	if fileExists(filepath.Join(dir, ".ptsd", "bdd")) || fileExists(filepath.Join(dir, ".ptsd", "seeds")) {
+		t.Error("default artifact directories should not be created")
+	}
+
+	if r := GateCheck(dir, "docs/billing.md"); !r.Allowed {
+		t.Errorf("PRD part should always be editable: %+v", r)
+	}
+	if r := GateCheck(dir, "features/billing.feature"); r.Allowed || r.Feature != "billing" {
+		t.Errorf("BDD file without a seed should be blocked for billing: %+v", r)
+	}
+	if r := GateCheck(dir, "testdata/seeds/nope/data.json"); r.Allowed || r.Feature != "nope" {
+		t.Errorf("seed file without a PRD anchor should be blocked: %+v", r)
+	}
+
+	for path, want := range map[string]string{"docs/PRD.md": "PRD", "features/auth.feature": "BDD", "testdata/seeds/auth/seed.yaml": "SEED"} {
+		if got, err := ClassifyFile(dir, path); err != nil || got != want {
+			t.Errorf("ClassifyFile(%s) = %s, %v; want %s", path, got, err, want)
+		}
+	}
+	if feature, stage, _ := classifyForTracking(dir, "features/auth.feature"); feature != "auth" || stage != "bdd" {
+		t.Errorf("classifyForTracking = %s %s", feature, stage)
+	}
+}
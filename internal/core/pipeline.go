@@ -1,18 +1,35 @@
 package core
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type ValidationError struct {
 	Feature  string
 	Category string
+	Rule     string // check that produced it: prd-anchor, prd-size, bdd-seed, bdd-tests, lite-tests, review-gate, regression, mock
+	Severity string // "warn" for findings that do not indicate broken pipeline order; empty is an error
 	Message  string
 }
 
+// IsWarning reports whether e is a warning rather than an error.
+func (e ValidationError) IsWarning() bool {
+	return e.Severity == "warn"
+}
+
 func Validate(projectDir string) ([]ValidationError, error) {
+	return ValidateWithLimits(projectDir, ProjectWalkLimits(projectDir))
+}
+
+// ValidateWithLimits is Validate with explicit bounds on the test-file walks.
+func ValidateWithLimits(projectDir string, limits WalkLimits) ([]ValidationError, error) {
+	// Drain debounced auto-track updates so gates see the latest stages.
+	_, _ = FlushAutoTrack(projectDir)
+
 	features, err := loadFeatures(projectDir)
 	if err != nil {
 		return nil, err
@@ -33,11 +50,21 @@ func Validate(projectDir string) ([]ValidationError, error) {
 			errors = append(errors, ValidationError{
 				Feature:  e.FeatureID,
 				Category: "pipeline",
+				Rule:     "prd-anchor",
 				Message:  "has no prd anchor",
 			})
 		}
 	}
 
+	for _, big := range OversizedPRDFiles(projectDir) {
+		errors = append(errors, ValidationError{
+			Category: "pipeline",
+			Rule:     "prd-size",
+			Severity: "warn",
+			Message:  fmt.Sprintf("%s is %dKB, over prd.max_kb (%dKB): %s", big.File, big.KB, big.MaxKB, big.Suggestion()),
+		})
+	}
+
 	// Load state and review-status for per-feature stage check
 	state, _ := LoadState(projectDir)
 	reviewStatus, _ := loadReviewStatus(projectDir)
@@ -48,16 +75,35 @@ func Validate(projectDir string) ([]ValidationError, error) {
 			continue
 		}
 
-		bddPath := filepath.Join(projectDir, ".ptsd", "bdd", f.ID+".feature")
+		// Lite features skip seed and BDD but still need tests once they
+		// reach the tests stage or are marked implemented.
+		if f.Pipeline == PipelineLite {
+			stage := ""
+			if rs, ok := reviewStatus[f.ID]; ok {
+				stage = rs.Stage
+			}
+			if (stage == "tests" || stage == "impl" || f.Status == "implemented") && !hasTestsForFeature(projectDir, f.ID, state, limits) {
+				errors = append(errors, ValidationError{
+					Feature:  f.ID,
+					Category: "pipeline",
+					Rule:     "lite-tests",
+					Message:  "lite pipeline: has no tests",
+				})
+			}
+			continue
+		}
+
+		bddPath := bddFilePath(projectDir, f.ID)
 		hasBDD := fileExists(bddPath)
 
-		seedPath := filepath.Join(projectDir, ".ptsd", "seeds", f.ID, "seed.yaml")
+		seedPath := seedManifestPath(projectDir, f.ID)
 		hasSeed := fileExists(seedPath)
 
 		if hasBDD && !hasSeed {
 			errors = append(errors, ValidationError{
 				Feature:  f.ID,
 				Category: "pipeline",
+				Rule:     "bdd-seed",
 				Message:  "has bdd but no seed",
 			})
 		}
@@ -68,11 +114,12 @@ func Validate(projectDir string) ([]ValidationError, error) {
 			currentStage = rs.Stage
 		}
 		if hasBDD && currentStage != "prd" && currentStage != "seed" && currentStage != "bdd" {
-			hasTests := hasTestsForFeature(projectDir, f.ID, state)
+			hasTests := hasTestsForFeature(projectDir, f.ID, state, limits)
 			if !hasTests {
 				errors = append(errors, ValidationError{
 					Feature:  f.ID,
 					Category: "pipeline",
+					Rule:     "bdd-tests",
 					Message:  "has bdd but no tests",
 				})
 			}
@@ -96,6 +143,7 @@ func Validate(projectDir string) ([]ValidationError, error) {
 			errors = append(errors, ValidationError{
 				Feature:  f.ID,
 				Category: "pipeline",
+				Rule:     "review-gate",
 				Message:  "review gate check failed: " + err.Error(),
 			})
 			continue
@@ -104,34 +152,72 @@ func Validate(projectDir string) ([]ValidationError, error) {
 			errors = append(errors, ValidationError{
 				Feature:  f.ID,
 				Category: "pipeline",
+				Rule:     "review-gate",
 				Message:  "review gate not passed for stage " + fs.Stage,
 			})
 		}
 	}
 
+	// Check feature dependencies
+	errors = append(errors, checkDependencies(projectDir, features, reviewStatus)...)
+
+	// Check per-feature config overrides
+	errors = append(errors, checkFeatureOverrides(projectDir, features)...)
+
+	// Check gate exemptions
+	errors = append(errors, checkExemptions(projectDir, time.Now())...)
+
+	// Check review staleness (review.stale_after)
+	errors = append(errors, checkStaleReviews(projectDir, time.Now())...)
+
 	// Check regressions
 	regressions, _ := CheckRegressions(projectDir)
 	for _, r := range regressions {
+		severity := ""
+		if r.Severity == "warn" {
+			severity = "warn"
+		}
 		errors = append(errors, ValidationError{
 			Feature:  r.Feature,
 			Category: "pipeline",
+			Rule:     "regression",
+			Severity: severity,
 			Message:  r.Message,
 		})
 	}
 
 	// Check for mock patterns in test files
-	mockErrors := scanForMocks(projectDir)
+	mockErrors, err := scanForMocks(projectDir, limits)
+	if err != nil {
+		return nil, err
+	}
 	errors = append(errors, mockErrors...)
 
 	return errors, nil
 }
 
+// LiteFeatures returns the IDs of active features using pipeline: lite, so
+// validate output can show which features were checked in lite mode.
+func LiteFeatures(projectDir string) ([]string, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, f := range features {
+		if f.Pipeline == PipelineLite && f.Status != "planned" && f.Status != "deferred" {
+			ids = append(ids, f.ID)
+		}
+	}
+	return ids, nil
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
 
-func hasTestsForFeature(projectDir string, featureID string, state *State) bool {
+func hasTestsForFeature(projectDir string, featureID string, state *State, limits WalkLimits) bool {
 	// Check state for test mappings
 	if state != nil {
 		if fs, ok := state.Features[featureID]; ok {
@@ -141,40 +227,43 @@ func hasTestsForFeature(projectDir string, featureID string, state *State) bool
 		}
 	}
 
+	// A features_config patterns override names the feature's own test files.
+	var patterns []string
+	if cfg, err := LoadConfig(projectDir); err == nil {
+		patterns = cfg.Features[featureID].Patterns
+	}
+
 	// Fallback: walk project for test files specific to this feature
 	found := false
-	filepath.Walk(projectDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() && strings.Contains(path, ".ptsd") {
-			return filepath.SkipDir
-		}
+	walkProject(projectDir, limits, func(path string, info os.FileInfo) error {
 		base := filepath.Base(path)
 		if strings.Contains(base, featureID) &&
 			(strings.HasSuffix(path, "_test.go") || strings.HasSuffix(path, ".test.ts") || strings.HasSuffix(path, ".test.js")) {
 			found = true
 			return filepath.SkipAll
 		}
+		if len(patterns) > 0 {
+			rel, _ := filepath.Rel(projectDir, path)
+			for _, p := range patterns {
+				if matchesTestPattern(filepath.ToSlash(rel), p) {
+					found = true
+					return filepath.SkipAll
+				}
+			}
+		}
 		return nil
 	})
 	return found
 }
 
-func scanForMocks(projectDir string) []ValidationError {
+func scanForMocks(projectDir string, limits WalkLimits) ([]ValidationError, error) {
 	var errors []ValidationError
 	mockPatterns := []string{
 		"vi.mock", "jest.mock", "unittest.mock",
 		"gomock", "testify/mock", "mock.Mock",
 	}
 
-	filepath.Walk(projectDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() && strings.Contains(path, ".ptsd") {
-			return filepath.SkipDir
-		}
+	err := walkProject(projectDir, limits, func(path string, info os.FileInfo) error {
 		if !strings.HasSuffix(path, "_test.go") && !strings.HasSuffix(path, ".test.ts") && !strings.HasSuffix(path, ".test.js") {
 			return nil
 		}
@@ -190,6 +279,7 @@ func scanForMocks(projectDir string) []ValidationError {
 				errors = append(errors, ValidationError{
 					Feature:  "",
 					Category: "pipeline",
+					Rule:     "mock",
 					Message:  "mock detected in " + relPath,
 				})
 				break
@@ -198,5 +288,5 @@ func scanForMocks(projectDir string) []ValidationError {
 		return nil
 	})
 
-	return errors
+	return errors, err
 }
@@ -220,6 +220,47 @@ func TestValidatePassesWithSufficientReviewScore(t *testing.T) {
 	}
 }
 
+func TestLiteFeatureSkipsSeedAndBDD(t *testing.T) {
+	dir := setupProjectWithFeature(t, "bump-deps", func(base string) {
+		writeFeaturesYAML(t, base, `- id: bump-deps
+  title: "Bump deps"
+  status: in-progress
+  pipeline: lite
+`)
+		createPRDAnchor(t, base, "bump-deps")
+	})
+
+	errors, err := Validate(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(errors) > 0 {
+		t.Errorf("lite feature without seed/bdd should pass, got: %v", errors)
+	}
+
+	lite, err := LiteFeatures(dir)
+	if err != nil || len(lite) != 1 || lite[0] != "bump-deps" {
+		t.Errorf("expected LiteFeatures [bump-deps], got %v (%v)", lite, err)
+	}
+}
+
+func TestLiteFeatureStillRequiresTests(t *testing.T) {
+	dir := setupProjectWithFeature(t, "bump-deps", func(base string) {
+		writeFeaturesYAML(t, base, `- id: bump-deps
+  title: "Bump deps"
+  status: implemented
+  pipeline: lite
+`)
+		createPRDAnchor(t, base, "bump-deps")
+	})
+
+	errors, err := Validate(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertHasError(t, errors, "bump-deps", "pipeline", "lite pipeline: has no tests")
+}
+
 func TestValidateAutoTriggersRegressionDetection(t *testing.T) {
 	dir := t.TempDir()
 	base := filepath.Join(dir, ".ptsd")
@@ -266,6 +307,43 @@ func TestValidateAutoTriggersRegressionDetection(t *testing.T) {
 	}
 }
 
+func TestValidateMarksStaleDownstreamAsWarning(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, ".ptsd")
+	createDirs(t, base)
+
+	writeFeaturesYAML(t, base, `- id: user-auth
+  title: "User Auth"
+  status: active
+`)
+	createPRDAnchor(t, base, "user-auth")
+	createBDD(t, base, "user-auth")
+	createSeed(t, base, "user-auth")
+	testDir := filepath.Join(dir, "internal", "core")
+	os.MkdirAll(testDir, 0755)
+	os.WriteFile(filepath.Join(testDir, "user-auth_test.go"), []byte("package core\n"), 0644)
+	setupFeatureFiles(t, dir, "user-auth", "seed", "bdd", "test")
+	setState(t, dir, "user-auth", "impl", nil, nil)
+
+	f, _ := os.OpenFile(filepath.Join(base, "bdd", "user-auth.feature"), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("\n  Scenario: added later\n    Given x\n")
+	f.Close()
+
+	errors, err := Validate(dir)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	for _, e := range errors {
+		if e.Rule == "regression" {
+			if !e.IsWarning() {
+				t.Errorf("bdd change at impl should be a warning: %+v", e)
+			}
+			return
+		}
+	}
+	t.Errorf("expected a regression finding, got %+v", errors)
+}
+
 // Helpers
 
 func setupCleanProject(t *testing.T) string {
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+)
+
+// External subcommands. Like git and kubectl, `ptsd <name>` runs the
+// executable ptsd-<name> from PATH when <name> is not a built-in command, so
+// teams can add commands without forking the dispatcher. The plugin gets the
+// project root and agent mode in its environment (PTSD_PROJECT_ROOT,
+// PTSD_AGENT_MODE), --agent as its first argument in agent mode, and a
+// PluginContext as JSON on stdin. Its exit code is ptsd's.
+
+var pluginNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// PluginContext is the JSON document a plugin reads on stdin.
+type PluginContext struct {
+	Version string `json:"ptsd_version"`
+	// ProjectRoot is the directory holding .ptsd/ptsd.yaml, "" outside a
+	// project.
+	ProjectRoot string          `json:"project_root"`
+	Agent       bool            `json:"agent"`
+	Command     string          `json:"command"`
+	Args        []string        `json:"args"`
+	Features    []PluginFeature `json:"features"`
+}
+
+// PluginFeature is a registered feature and its pipeline stage.
+type PluginFeature struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Stage  string `json:"stage"`
+}
+
+// FindPlugin returns the path of the ptsd-<name> executable on PATH.
+func FindPlugin(name string) (string, bool) {
+	if !pluginNameRe.MatchString(name) {
+		return "", false
+	}
+	path, err := exec.LookPath("ptsd-" + name)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// ProjectRoot returns the directory at or above dir holding .ptsd/ptsd.yaml.
+func ProjectRoot(dir string) (string, error) {
+	cfgPath, err := findConfigPath(dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(filepath.Dir(cfgPath)), nil
+}
+
+// NewPluginContext describes the invocation of plugin command from dir.
+// Outside a project the root is empty and there are no features.
+func NewPluginContext(dir, version, command string, args []string, agent bool) PluginContext {
+	ctx := PluginContext{Version: version, Agent: agent, Command: command, Args: args, Features: []PluginFeature{}}
+	if ctx.Args == nil {
+		ctx.Args = []string{}
+	}
+	root, err := ProjectRoot(dir)
+	if err != nil {
+		return ctx
+	}
+	ctx.ProjectRoot = root
+	features, err := loadFeatures(root)
+	if err != nil {
+		return ctx
+	}
+	state, _ := LoadState(root)
+	for _, f := range features {
+		pf := PluginFeature{ID: f.ID, Status: f.Status}
+		if state != nil {
+			pf.Stage = state.Features[f.ID].Stage
+		}
+		ctx.Features = append(ctx.Features, pf)
+	}
+	return ctx
+}
+
+// RunPlugin runs the plugin at path from dir with ctx on stdin and the
+// caller's arguments, passing its output through. It returns the plugin's
+// exit code; err is set only when the plugin could not be run.
+func RunPlugin(path, dir string, ctx PluginContext, stdout, stderr io.Writer) (int, error) {
+	blob, err := json.Marshal(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("err:io %w", err)
+	}
+	args := ctx.Args
+	if ctx.Agent {
+		args = append([]string{"--agent"}, args...)
+	}
+	agentMode := "0"
+	if ctx.Agent {
+		agentMode = "1"
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(append(blob, '\n'))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(),
+		"PTSD_PROJECT_ROOT="+ctx.ProjectRoot,
+		"PTSD_AGENT_MODE="+agentMode,
+		"PTSD_VERSION="+ctx.Version,
+	)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if code := exitErr.ExitCode(); code > 0 {
+				return code, nil
+			}
+			return 1, nil // killed by a signal
+		}
+		return 0, fmt.Errorf("err:io cannot run %s: %w", filepath.Base(path), err)
+	}
+	return 0, nil
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePlugin puts an executable ptsd-<name> shell script on PATH.
+func writePlugin(t *testing.T, name, script string) string {
+	t.Helper()
+	bin := t.TempDir()
+	path := filepath.Join(bin, "ptsd-"+name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return path
+}
+
+func TestFindPlugin(t *testing.T) {
+	path := writePlugin(t, "hello", "exit 0\n")
+	if got, ok := FindPlugin("hello"); !ok || got != path {
+		t.Errorf("FindPlugin(hello) = %s, %v", got, ok)
+	}
+	if _, ok := FindPlugin("missing"); ok {
+		t.Error("expected no plugin for missing")
+	}
+	if _, ok := FindPlugin("../hello"); ok {
+		t.Error("plugin names must not be paths")
+	}
+}
+
+func TestRunPluginPassesContext(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("project:\n  name: X\n"), 0644)
+	sub := filepath.Join(dir, "web")
+	os.MkdirAll(sub, 0755)
+	path := writePlugin(t, "hello", "echo \"args=$* root=$PTSD_PROJECT_ROOT agent=$PTSD_AGENT_MODE\"\ncat\nexit 3\n")
+
+	ctx := NewPluginContext(sub, "v1.2.3", "hello", []string{"one", "two"}, true)
+	if ctx.ProjectRoot != dir || len(ctx.Features) != 1 || ctx.Features[0].ID != "auth" {
+		t.Fatalf("unexpected context: %+v", ctx)
+	}
+	var stdout, stderr bytes.Buffer
+	code, err := RunPlugin(path, sub, ctx, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("RunPlugin: %v", err)
+	}
+	if code != 3 {
+		t.Errorf("expected the plugin's exit code 3, got %d", code)
+	}
+	first, blob, _ := strings.Cut(stdout.String(), "\n")
+	if first != "args=--agent one two root="+dir+" agent=1" {
+		t.Errorf("unexpected invocation: %q", first)
+	}
+	var got PluginContext
+	if err := json.Unmarshal([]byte(blob), &got); err != nil {
+		t.Fatalf("stdin is not JSON: %v\n%s", err, blob)
+	}
+	if got.Version != "v1.2.3" || got.Command != "hello" || !got.Agent || strings.Join(got.Args, ",") != "one,two" {
+		t.Errorf("unexpected context on stdin: %+v", got)
+	}
+}
+
+func TestNewPluginContextOutsideProject(t *testing.T) {
+	ctx := NewPluginContext(t.TempDir(), "dev", "hello", nil, false)
+	if ctx.ProjectRoot != "" || ctx.Features == nil || ctx.Args == nil {
+		t.Errorf("expected an empty project context, got %+v", ctx)
+	}
+}
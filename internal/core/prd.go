@@ -3,18 +3,25 @@ package core
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 type PRDError struct {
 	Type      string
 	FeatureID string
+	// Files lists the PRD files holding the anchor, for duplicate-anchor.
+	Files []string
 }
 
 type PRDSection struct {
 	FeatureID string
+	// File is the project-relative PRD file holding the section.
+	File      string
 	StartLine int
 	EndLine   int
 	Content   string
@@ -23,6 +30,92 @@ type PRDSection struct {
 const anchorPrefix = "<!-- feature:"
 const anchorSuffix = " -->"
 
+// The PRD may be split across the markdown files next to the main PRD file
+// (.ptsd/docs/*.md by default, say one file per domain; see paths.prd). Every
+// one is scanned for feature anchors; the main file comes first, the rest in
+// name order. A section runs from its anchor to the next anchor in the same
+// file.
+
+// PRDAnchor locates one feature anchor.
+type PRDAnchor struct {
+	FeatureID string
+	File      string // project-relative
+	Line      int
+}
+
+// PRDFiles returns the project-relative markdown files in the PRD directory,
+// the main PRD file first. It fails with err:io when there are none.
+func PRDFiles(projectDir string) ([]string, error) {
+	mainFile := prdMainRel(projectDir)
+	dir := path.Dir(mainFile)
+	matches, err := filepath.Glob(filepath.Join(projectDir, filepath.FromSlash(dir), "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	var files []string
+	hasMain := false
+	for _, m := range matches {
+		file := path.Join(dir, filepath.Base(m))
+		if file == mainFile {
+			hasMain = true
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	if hasMain {
+		files = append([]string{mainFile}, files...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("err:io no PRD files in %s (expected %s)", dir, path.Base(mainFile))
+	}
+	return files, nil
+}
+
+// PRDIndex lists every feature anchor across the PRD files, in file and
+// line order: which file owns which anchor.
+func PRDIndex(projectDir string) ([]PRDAnchor, error) {
+	files, err := PRDFiles(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var index []PRDAnchor
+	for _, file := range files {
+		anchors, err := indexPRDFile(projectDir, file)
+		if err != nil {
+			return nil, err
+		}
+		index = append(index, anchors...)
+	}
+	return index, nil
+}
+
+// parseAnchor returns the feature ID of an anchor line.
+func parseAnchor(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, anchorPrefix) && strings.HasSuffix(line, anchorSuffix) && len(line) > len(anchorPrefix)+len(anchorSuffix) {
+		return line[len(anchorPrefix) : len(line)-len(anchorSuffix)], true
+	}
+	return "", false
+}
+
+// prdPathFor is the absolute path of the PRD file owning featureID's anchor,
+// the main PRD file when no file does.
+func prdPathFor(projectDir, featureID string) string {
+	file := prdFileFor(projectDir, featureID)
+	if file == "" {
+		file = prdMainRel(projectDir)
+	}
+	return filepath.Join(projectDir, filepath.FromSlash(file))
+}
+
+// prdFileFor returns the PRD file owning featureID's anchor, "" when none
+// does.
+func prdFileFor(projectDir, featureID string) string {
+	a, _, _ := prdAnchorFor(projectDir, featureID)
+	return a.File
+}
+
 func CheckPRDAnchors(projectDir string) ([]PRDError, error) {
 	anchors, err := extractAnchors(projectDir)
 	if err != nil {
@@ -58,39 +151,90 @@ func CheckPRDAnchors(projectDir string) ([]PRDError, error) {
 		}
 	}
 
+	// An anchor in two files makes its section ambiguous.
+	index, _ := PRDIndex(projectDir)
+	owners := make(map[string][]string)
+	var order []string
+	for _, a := range index {
+		if len(owners[a.FeatureID]) == 0 {
+			order = append(order, a.FeatureID)
+		}
+		if !containsString(owners[a.FeatureID], a.File) {
+			owners[a.FeatureID] = append(owners[a.FeatureID], a.File)
+		}
+	}
+	for _, id := range order {
+		if len(owners[id]) > 1 {
+			errs = append(errs, PRDError{Type: "duplicate-anchor", FeatureID: id, Files: owners[id]})
+		}
+	}
+
 	return errs, nil
 }
 
+// ExtractPRDSection returns the lines after featureID's anchor. The section
+// ends at the next anchor; when it opens with a heading ("### Auth"), also at
+// the next heading of the same or a higher level, so trailing chapters such
+// as "## Appendix" are not attributed to the last feature. Headings inside
+// fenced code blocks do not count.
 func ExtractPRDSection(projectDir string, featureID string) (PRDSection, error) {
-	prdPath := filepath.Join(projectDir, ".ptsd", "docs", "PRD.md")
-	f, err := os.Open(prdPath)
+	anchor, offset, ok := prdAnchorFor(projectDir, featureID)
+	if !ok {
+		if _, err := PRDFiles(projectDir); err != nil {
+			return PRDSection{}, err
+		}
+		return PRDSection{}, fmt.Errorf("err:pipeline anchor not found for %s", featureID)
+	}
+	file := anchor.File
+	f, err := os.Open(filepath.Join(projectDir, file))
 	if err != nil {
 		return PRDSection{}, fmt.Errorf("err:io %w", err)
 	}
 	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return PRDSection{}, fmt.Errorf("err:io %w", err)
+	}
 
-	target := anchorPrefix + featureID + anchorSuffix
-	scanner := bufio.NewScanner(f)
-	lineNum := 0
-	found := false
-	startLine := 0
+	r := bufio.NewReader(f)
+	startLine := anchor.Line
+	lineNum := startLine
 	var contentLines []string
+	// ownLevel is the level of the section's opening heading: 0 while only
+	// blank lines were read, -1 when it opens with anything else.
+	ownLevel := 0
+	inFence := false
 
-	for scanner.Scan() {
+	for {
+		raw, readErr := r.ReadString('\n')
+		if raw == "" && readErr != nil {
+			if readErr != io.EOF {
+				return PRDSection{}, fmt.Errorf("err:io %w", readErr)
+			}
+			break
+		}
 		lineNum++
-		line := scanner.Text()
+		line := strings.TrimSuffix(strings.TrimSuffix(raw, "\n"), "\r")
 
-		if !found {
-			if strings.TrimSpace(line) == target {
-				found = true
-				startLine = lineNum
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		level := 0
+		if !inFence {
+			level, _ = markdownHeading(trimmed)
+		}
+		closesSection := level > 0 && ownLevel > 0 && level <= ownLevel
+		if ownLevel == 0 && trimmed != "" {
+			ownLevel = level
+			if level == 0 {
+				ownLevel = -1
 			}
-			continue
 		}
 
-		if strings.Contains(line, anchorPrefix) {
+		if strings.Contains(line, anchorPrefix) || closesSection {
 			return PRDSection{
 				FeatureID: featureID,
+				File:      file,
 				StartLine: startLine,
 				EndLine:   lineNum - 1,
 				Content:   strings.Join(contentLines, "\n"),
@@ -98,34 +242,29 @@ func ExtractPRDSection(projectDir string, featureID string) (PRDSection, error)
 		}
 
 		contentLines = append(contentLines, line)
-	}
-
-	if !found {
-		return PRDSection{}, fmt.Errorf("err:pipeline anchor not found for %s", featureID)
+		if readErr == io.EOF {
+			break
+		}
 	}
 
 	return PRDSection{
 		FeatureID: featureID,
+		File:      file,
 		StartLine: startLine,
 		EndLine:   lineNum,
 		Content:   strings.Join(contentLines, "\n"),
 	}, nil
 }
 
+// extractAnchors returns the feature IDs anchored in any PRD file.
 func extractAnchors(projectDir string) ([]string, error) {
-	prdPath := filepath.Join(projectDir, ".ptsd", "docs", "PRD.md")
-	data, err := os.ReadFile(prdPath)
+	index, err := PRDIndex(projectDir)
 	if err != nil {
-		return nil, fmt.Errorf("err:io %w", err)
+		return nil, err
 	}
-
 	var anchors []string
-	for _, line := range strings.Split(string(data), "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, anchorPrefix) && strings.HasSuffix(line, anchorSuffix) {
-			id := line[len(anchorPrefix) : len(line)-len(anchorSuffix)]
-			anchors = append(anchors, id)
-		}
+	for _, a := range index {
+		anchors = append(anchors, a.FeatureID)
 	}
 	return anchors, nil
 }
@@ -178,3 +317,107 @@ func readActiveFeatureIDs(projectDir string) ([]string, error) {
 	}
 	return ids, nil
 }
+
+// orphanedAnchors returns the orphaned anchors among ids (all of them when
+// ids is empty). Naming an anchor that is not orphaned is a user error.
+func orphanedAnchors(projectDir string, ids []string) ([]string, error) {
+	errs, err := CheckPRDAnchors(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var orphans []string
+	isOrphan := make(map[string]bool)
+	for _, e := range errs {
+		if e.Type == "orphaned-anchor" && !isOrphan[e.FeatureID] {
+			isOrphan[e.FeatureID] = true
+			orphans = append(orphans, e.FeatureID)
+		}
+	}
+	if len(ids) == 0 {
+		return orphans, nil
+	}
+	for _, id := range ids {
+		if !isOrphan[id] {
+			return nil, fmt.Errorf("err:user %s is not an orphaned anchor", id)
+		}
+	}
+	return ids, nil
+}
+
+// RegisterOrphanedAnchors registers a planned feature for each orphaned PRD
+// anchor in ids (all when empty), titled after the section's first heading.
+// It returns the registered IDs.
+func RegisterOrphanedAnchors(projectDir string, ids []string) ([]string, error) {
+	orphans, err := orphanedAnchors(projectDir, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range orphans {
+		title := id
+		if section, err := ExtractPRDSection(projectDir, id); err == nil {
+			for _, line := range strings.Split(section.Content, "\n") {
+				if _, text := markdownHeading(strings.TrimSpace(line)); text != "" {
+					title = text
+					break
+				}
+			}
+		}
+		if err := AddFeature(projectDir, id, title); err != nil {
+			return nil, err
+		}
+	}
+	return orphans, nil
+}
+
+// RemoveOrphanedAnchors deletes the anchor comment of each orphaned PRD
+// anchor in ids (all when empty), leaving the section text in place. It
+// returns the removed IDs.
+func RemoveOrphanedAnchors(projectDir string, ids []string) ([]string, error) {
+	orphans, err := orphanedAnchors(projectDir, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(orphans) == 0 {
+		return nil, nil
+	}
+	remove := make(map[string]bool)
+	for _, id := range orphans {
+		remove[anchorPrefix+id+anchorSuffix] = true
+	}
+
+	files, err := PRDFiles(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		path := filepath.Join(projectDir, file)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("err:io %w", err)
+		}
+		var kept []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if !remove[strings.TrimSpace(line)] {
+				kept = append(kept, line)
+			}
+		}
+		if len(kept) == strings.Count(string(data), "\n")+1 {
+			continue
+		}
+		if err := writeFileAtomic(path, []byte(strings.Join(kept, "\n")), 0644); err != nil {
+			return nil, fmt.Errorf("err:io %w", err)
+		}
+	}
+	return orphans, nil
+}
+
+// GetPRDSection returns the markdown of featureID's PRD section (see
+// ExtractPRDSection) without surrounding blank lines: the feature's
+// requirements without the rest of the PRD.
+func GetPRDSection(projectDir string, featureID string) (string, error) {
+	section, err := ExtractPRDSection(projectDir, featureID)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(section.Content, "\n"), nil
+}
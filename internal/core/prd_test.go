@@ -136,3 +136,185 @@ func TestExtractPRDSectionNotFound(t *testing.T) {
 		t.Fatal("expected error for missing anchor")
 	}
 }
+
+func setupOrphanedAnchors(t *testing.T) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	docsDir := filepath.Join(dir, ".ptsd", "docs")
+	if err := os.MkdirAll(docsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	prd := "# PRD\n<!-- feature:user-auth -->\nAuth\n<!-- feature:billing -->\n### Billing & Invoices\nMoney.\n<!-- feature:search -->\nFind things.\n"
+	if err := os.WriteFile(filepath.Join(docsDir, "PRD.md"), []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRegisterOrphanedAnchors(t *testing.T) {
+	dir := setupOrphanedAnchors(t)
+
+	if _, err := RegisterOrphanedAnchors(dir, []string{"user-auth"}); err == nil {
+		t.Error("expected error for an anchor that is not orphaned")
+	}
+
+	registered, err := RegisterOrphanedAnchors(dir, nil)
+	if err != nil {
+		t.Fatalf("RegisterOrphanedAnchors failed: %v", err)
+	}
+	if strings.Join(registered, ",") != "billing,search" {
+		t.Errorf("registered = %v", registered)
+	}
+	features, _ := loadFeatures(dir)
+	titles := map[string]string{}
+	for _, f := range features {
+		titles[f.ID] = f.Title + "/" + f.Status
+	}
+	if titles["billing"] != "Billing & Invoices/planned" || titles["search"] != "search/planned" {
+		t.Errorf("unexpected features: %v", titles)
+	}
+	if errs, _ := CheckPRDAnchors(dir); len(errs) != 0 {
+		t.Errorf("expected clean check, got %v", errs)
+	}
+}
+
+func TestRemoveOrphanedAnchors(t *testing.T) {
+	dir := setupOrphanedAnchors(t)
+
+	removed, err := RemoveOrphanedAnchors(dir, []string{"search"})
+	if err != nil {
+		t.Fatalf("RemoveOrphanedAnchors failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "search" {
+		t.Errorf("removed = %v", removed)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "docs", "PRD.md"))
+	content := string(data)
+	if strings.Contains(content, "feature:search") || !strings.Contains(content, "Find things.") {
+		t.Errorf("anchor should go, text should stay:\n%s", content)
+	}
+	errs, _ := CheckPRDAnchors(dir)
+	if len(errs) != 1 || errs[0].FeatureID != "billing" {
+		t.Errorf("expected only billing left orphaned, got %v", errs)
+	}
+}
+
+func TestPRDSplitAcrossFiles(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress", "catalog:in-progress", "billing:in-progress")
+	docsDir := filepath.Join(dir, ".ptsd", "docs")
+	if err := os.MkdirAll(docsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"PRD.md":     "# PRD\n<!-- feature:user-auth -->\nAuth section\n",
+		"shop.md":    "# Shop\n<!-- feature:catalog -->\n### Catalog\nList products\n<!-- feature:billing -->\nBilling section\n",
+		"notes.txt":  "<!-- feature:ignored -->\n",
+		"archive.md": "# Old notes, no anchors\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(docsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs, err := CheckPRDAnchors(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("expected 0 errors, got %v", errs)
+	}
+
+	index, err := PRDIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PRDAnchor{
+		{FeatureID: "user-auth", File: ".ptsd/docs/PRD.md", Line: 2},
+		{FeatureID: "catalog", File: ".ptsd/docs/shop.md", Line: 2},
+		{FeatureID: "billing", File: ".ptsd/docs/shop.md", Line: 5},
+	}
+	if len(index) != len(want) {
+		t.Fatalf("index = %v, want %v", index, want)
+	}
+	for i := range want {
+		if index[i] != want[i] {
+			t.Errorf("index[%d] = %v, want %v", i, index[i], want[i])
+		}
+	}
+
+	section, err := ExtractPRDSection(dir, "catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if section.File != ".ptsd/docs/shop.md" || section.StartLine != 2 || section.EndLine != 4 {
+		t.Errorf("section = %s:%d-%d, want .ptsd/docs/shop.md:2-4", section.File, section.StartLine, section.EndLine)
+	}
+	if !strings.Contains(section.Content, "List products") || strings.Contains(section.Content, "Billing") {
+		t.Errorf("unexpected section content: %q", section.Content)
+	}
+
+	detail, err := ShowFeature(dir, "billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.PRDAnchor != "shop.md:l5" {
+		t.Errorf("PRDAnchor = %q, want shop.md:l5", detail.PRDAnchor)
+	}
+}
+
+func TestCheckPRDAnchorsDuplicateAcrossFiles(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	docsDir := filepath.Join(dir, ".ptsd", "docs")
+	if err := os.MkdirAll(docsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"PRD.md", "auth.md"} {
+		if err := os.WriteFile(filepath.Join(docsDir, name), []byte("<!-- feature:user-auth -->\nAuth\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs, err := CheckPRDAnchors(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Type != "duplicate-anchor" || errs[0].FeatureID != "user-auth" {
+		t.Fatalf("expected one duplicate-anchor for user-auth, got %v", errs)
+	}
+	if strings.Join(errs[0].Files, ",") != ".ptsd/docs/PRD.md,.ptsd/docs/auth.md" {
+		t.Errorf("Files = %v", errs[0].Files)
+	}
+}
+
+func TestExtractPRDSectionEndsAtHeading(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress", "catalog:in-progress")
+	docsDir := filepath.Join(dir, ".ptsd", "docs")
+	if err := os.MkdirAll(docsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	prd := "# PRD\n\n## Features\n\n<!-- feature:user-auth -->\n### User auth\n\nLogin.\n\n#### Acceptance\n\n```\n# not a heading\n```\n\n<!-- feature:catalog -->\n### Catalog\n\nList products.\n\n## Appendix\n\nGlossary.\n"
+	if err := os.WriteFile(filepath.Join(docsDir, "PRD.md"), []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := GetPRDSection(dir, "user-auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(auth, "### User auth") || !strings.Contains(auth, "#### Acceptance") || !strings.Contains(auth, "# not a heading") {
+		t.Errorf("unexpected user-auth section: %q", auth)
+	}
+
+	catalog, err := GetPRDSection(dir, "catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if catalog != "### Catalog\n\nList products." {
+		t.Errorf("catalog section = %q, want it to stop before ## Appendix", catalog)
+	}
+	section, _ := ExtractPRDSection(dir, "catalog")
+	if section.EndLine != 20 {
+		t.Errorf("EndLine = %d, want 20", section.EndLine)
+	}
+}
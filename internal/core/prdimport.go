@@ -0,0 +1,269 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PRDProposal is one section of an external spec that `prd import` would
+// turn into a feature: a proposed ID and anchor, the heading as title, and
+// the section body. Conflict is set when the ID cannot be registered.
+type PRDProposal struct {
+	ID        string
+	Title     string
+	Level     int // heading level of the section in the source
+	StartLine int
+	EndLine   int
+	Content   string
+	Conflict  string
+}
+
+// prdSectionLevel is the heading depth imported sections get in PRD.md,
+// below the template's "## Features".
+const prdSectionLevel = 3
+
+// ProposePRDImport splits an external Markdown spec into one proposal per
+// heading at level (1-6). With level 0 the shallowest heading level that
+// occurs more than once is used, so a single "# Title" does not swallow the
+// whole document. Text before the first section heading is not imported.
+func ProposePRDImport(projectDir string, content string, level int) ([]PRDProposal, error) {
+	if level < 0 || level > 6 {
+		return nil, fmt.Errorf("err:user heading level must be 1-6")
+	}
+
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[string]bool)
+	for _, f := range features {
+		registered[f.ID] = true
+	}
+	anchors, _ := extractAnchors(projectDir)
+	anchored := make(map[string]bool)
+	for _, a := range anchors {
+		anchored[a] = true
+	}
+
+	lines := strings.Split(content, "\n")
+	if level == 0 {
+		level = sectionHeadingLevel(lines)
+	}
+	if level == 0 {
+		return nil, fmt.Errorf("err:validation no headings found to split on")
+	}
+
+	var proposals []PRDProposal
+	used := make(map[string]bool)
+	var cur *PRDProposal
+	var body []string
+	flush := func(end int) {
+		if cur == nil {
+			return
+		}
+		for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+			body = body[:len(body)-1]
+			end--
+		}
+		cur.EndLine = end
+		cur.Content = strings.Join(body, "\n")
+		proposals = append(proposals, *cur)
+		cur, body = nil, nil
+	}
+
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if !inFence {
+			if lvl, title := markdownHeading(line); lvl > 0 && lvl <= level {
+				flush(i)
+				if lvl < level {
+					continue
+				}
+				id := uniqueSlug(slugifyHeading(title), used)
+				cur = &PRDProposal{ID: id, Title: title, Level: level, StartLine: i + 1}
+				switch {
+				case id == "":
+					cur.Conflict = "heading has no usable characters for an ID"
+				case registered[id]:
+					cur.Conflict = "feature already registered"
+				case anchored[id]:
+					cur.Conflict = "PRD already has this anchor"
+				}
+				continue
+			}
+		}
+		if cur != nil {
+			body = append(body, line)
+		}
+	}
+	flush(len(lines))
+	return proposals, nil
+}
+
+// ApplyPRDImport appends each proposal to the main PRD file under a
+// <!-- feature:<id> --> anchor and registers it as a planned feature.
+// Proposals with a Conflict are rejected before anything is written.
+func ApplyPRDImport(projectDir string, proposals []PRDProposal) error {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return err
+	}
+	registered := make(map[string]bool)
+	for _, f := range features {
+		registered[f.ID] = true
+	}
+	for _, p := range proposals {
+		if p.Conflict != "" {
+			return fmt.Errorf("err:validation cannot import %s: %s", p.ID, p.Conflict)
+		}
+		if !validFeatureID.MatchString(p.ID) {
+			return fmt.Errorf("err:validation invalid feature ID %q: must be ASCII slug (a-z0-9 with hyphens)", p.ID)
+		}
+		if registered[p.ID] {
+			return fmt.Errorf("err:validation feature %s already exists", p.ID)
+		}
+		registered[p.ID] = true
+	}
+	if len(proposals) == 0 {
+		return nil
+	}
+
+	prdPath := filepath.Join(projectDir, filepath.FromSlash(prdMainRel(projectDir)))
+	existing, err := os.ReadFile(prdPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("err:io %w", err)
+	}
+
+	var b strings.Builder
+	b.Write(existing)
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		b.WriteString("\n")
+	}
+	for _, p := range proposals {
+		b.WriteString("\n" + anchorPrefix + p.ID + anchorSuffix + "\n")
+		b.WriteString(strings.Repeat("#", prdSectionLevel) + " " + p.Title + "\n")
+		if p.Content != "" {
+			b.WriteString(shiftHeadings(p.Content, prdSectionLevel-p.Level) + "\n")
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(prdPath), 0755); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	if err := writeFileAtomic(prdPath, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+
+	for _, p := range proposals {
+		features = append(features, Feature{ID: p.ID, Title: p.Title, Status: "planned"})
+	}
+	return saveFeatures(projectDir, features)
+}
+
+// sectionHeadingLevel returns the shallowest heading level used more than
+// once, falling back to the shallowest level present; 0 if there are none.
+func sectionHeadingLevel(lines []string) int {
+	var counts [7]int
+	inFence := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if lvl, _ := markdownHeading(line); lvl > 0 && !inFence {
+			counts[lvl]++
+		}
+	}
+	for lvl := 1; lvl <= 6; lvl++ {
+		if counts[lvl] > 1 {
+			return lvl
+		}
+	}
+	for lvl := 1; lvl <= 6; lvl++ {
+		if counts[lvl] > 0 {
+			return lvl
+		}
+	}
+	return 0
+}
+
+// markdownHeading parses an ATX heading ("## Title"), returning its level
+// and text, or 0 if line is not a heading.
+func markdownHeading(line string) (int, string) {
+	lvl := 0
+	for lvl < len(line) && line[lvl] == '#' {
+		lvl++
+	}
+	if lvl == 0 || lvl > 6 || lvl >= len(line) || line[lvl] != ' ' {
+		return 0, ""
+	}
+	return lvl, strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[lvl:]), "#"))
+}
+
+// shiftHeadings moves every ATX heading in content by delta levels, clamped to 1-6.
+func shiftHeadings(content string, delta int) string {
+	if delta == 0 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		lvl, title := markdownHeading(line)
+		if lvl == 0 || inFence {
+			continue
+		}
+		lvl = min(max(lvl+delta, 1), 6)
+		lines[i] = strings.Repeat("#", lvl) + " " + title
+	}
+	return strings.Join(lines, "\n")
+}
+
+// slugifyHeading turns "2.1 User Login (OAuth)" into "user-login-oauth":
+// leading section numbers are dropped and the result is capped at 40
+// characters on a word boundary.
+func slugifyHeading(title string) string {
+	fields := strings.Fields(title)
+	for len(fields) > 0 && strings.Trim(fields[0], "0123456789.") == "" {
+		fields = fields[1:]
+	}
+
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.Join(fields, " ")) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > 40 {
+		slug = slug[:40]
+		if i := strings.LastIndex(slug, "-"); i > 0 {
+			slug = slug[:i]
+		}
+	}
+	return slug
+}
+
+func uniqueSlug(slug string, used map[string]bool) string {
+	if slug == "" {
+		return ""
+	}
+	id := slug
+	for n := 2; used[id]; n++ {
+		id = slug + "-" + strconv.Itoa(n)
+	}
+	used[id] = true
+	return id
+}
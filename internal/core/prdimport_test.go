@@ -0,0 +1,109 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const externalSpec = `# Acme Spec
+
+Intro text that is not a feature.
+
+## 1. User Login (OAuth)
+Users sign in with Google.
+
+### Errors
+Expired tokens redirect to login.
+
+## Billing
+Monthly invoices.
+
+` + "```" + `
+## not a heading inside a fence
+` + "```" + `
+
+## Billing
+Duplicate heading.
+`
+
+func TestProposePRDImport(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "billing-2")
+
+	proposals, err := ProposePRDImport(dir, externalSpec, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(proposals) != 3 {
+		t.Fatalf("expected 3 proposals, got %+v", proposals)
+	}
+
+	if p := proposals[0]; p.ID != "user-login-oauth" || p.Title != "1. User Login (OAuth)" || p.Level != 2 || p.StartLine != 5 {
+		t.Errorf("unexpected first proposal: %+v", p)
+	}
+	if !strings.Contains(proposals[0].Content, "### Errors") {
+		t.Errorf("subsections must stay in the section, got %q", proposals[0].Content)
+	}
+	if !strings.Contains(proposals[1].Content, "## not a heading inside a fence") {
+		t.Errorf("fenced lines must not split sections, got %q", proposals[1].Content)
+	}
+	if p := proposals[2]; p.ID != "billing-2" || p.Conflict != "feature already registered" {
+		t.Errorf("expected deduplicated conflicting ID, got %+v", p)
+	}
+}
+
+func TestApplyPRDImport(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir)
+	prdPath := filepath.Join(dir, ".ptsd", "docs", "PRD.md")
+	if err := os.MkdirAll(filepath.Dir(prdPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(prdPath, []byte("# PRD\n\n## Features\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	proposals, err := ProposePRDImport(dir, externalSpec, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ApplyPRDImport(dir, proposals[:2]); err != nil {
+		t.Fatalf("ApplyPRDImport failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(prdPath)
+	prd := string(data)
+	for _, want := range []string{
+		"<!-- feature:user-login-oauth -->\n### 1. User Login (OAuth)\nUsers sign in with Google.",
+		"#### Errors",
+		"<!-- feature:billing -->\n### Billing",
+	} {
+		if !strings.Contains(prd, want) {
+			t.Errorf("PRD.md missing %q:\n%s", want, prd)
+		}
+	}
+
+	errs, err := CheckPRDAnchors(dir)
+	if err != nil || len(errs) != 0 {
+		t.Errorf("expected anchors and features to agree, got %v %v", errs, err)
+	}
+	features, _ := loadFeatures(dir)
+	if len(features) != 2 || features[0].Title != "1. User Login (OAuth)" || features[0].Status != "planned" {
+		t.Errorf("unexpected features: %+v", features)
+	}
+}
+
+func TestApplyPRDImportRejectsConflict(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "billing")
+
+	err := ApplyPRDImport(dir, []PRDProposal{{ID: "billing", Title: "Billing", Level: 2}})
+	if err == nil || !strings.HasPrefix(err.Error(), "err:validation") {
+		t.Errorf("expected err:validation, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, ".ptsd", "docs", "PRD.md")); statErr == nil {
+		t.Error("PRD.md must not be written when a proposal is rejected")
+	}
+}
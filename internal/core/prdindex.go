@@ -0,0 +1,153 @@
+package core
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// PRD anchor index. Each PRD file is read once, streaming, to find its
+// anchors and the byte offset each section starts at; the result is cached
+// per file by content hash (re-checked when size or mtime changed), so
+// looking up many features in one run does not rescan a large PRD, and
+// ExtractPRDSection seeks straight to the section.
+
+type prdFileIndex struct {
+	size      int64
+	modTime   time.Time
+	indexedAt time.Time
+	hash      string
+	anchors   []PRDAnchor
+	// offsets[i] is the byte offset of the line after anchors[i].
+	offsets []int64
+}
+
+// prdRacyWindow is how long after its mtime a file's cached index is only
+// trusted once its hash was checked again.
+const prdRacyWindow = 2 * time.Second
+
+var prdIndexCache = struct {
+	sync.Mutex
+	files map[string]prdFileIndex
+}{files: make(map[string]prdFileIndex)}
+
+// indexPRDFile returns the anchors of one project-relative PRD file.
+func indexPRDFile(projectDir, file string) ([]PRDAnchor, error) {
+	idx, err := loadPRDFileIndex(projectDir, file)
+	return idx.anchors, err
+}
+
+func loadPRDFileIndex(projectDir, file string) (prdFileIndex, error) {
+	path := filepath.Join(projectDir, filepath.FromSlash(file))
+	info, err := os.Stat(path)
+	if err != nil {
+		return prdFileIndex{}, fmt.Errorf("err:io %w", err)
+	}
+	prdIndexCache.Lock()
+	cached, ok := prdIndexCache.files[path]
+	prdIndexCache.Unlock()
+	// Like git's racy-clean check: a file modified just before it was
+	// indexed may change again within the same mtime tick, so re-hash it.
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) && cached.indexedAt.Sub(cached.modTime) > prdRacyWindow {
+		return cached, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return prdFileIndex{}, fmt.Errorf("err:io %w", err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	r := bufio.NewReader(io.TeeReader(f, h))
+	idx := prdFileIndex{size: info.Size(), modTime: info.ModTime(), indexedAt: time.Now()}
+	var offset int64
+	for lineNum := 1; ; lineNum++ {
+		line, err := r.ReadString('\n')
+		offset += int64(len(line))
+		if id, ok := parseAnchor(line); ok {
+			idx.anchors = append(idx.anchors, PRDAnchor{FeatureID: id, File: file, Line: lineNum})
+			idx.offsets = append(idx.offsets, offset)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return prdFileIndex{}, fmt.Errorf("err:io %w", err)
+		}
+	}
+	idx.hash = hex.EncodeToString(h.Sum(nil))
+	if ok && cached.hash == idx.hash {
+		idx.anchors, idx.offsets = cached.anchors, cached.offsets // touched, not changed
+	}
+
+	prdIndexCache.Lock()
+	prdIndexCache.files[path] = idx
+	prdIndexCache.Unlock()
+	return idx, nil
+}
+
+// prdAnchorFor returns featureID's anchor, the first one when several files
+// hold it, and the byte offset its section starts at.
+func prdAnchorFor(projectDir, featureID string) (PRDAnchor, int64, bool) {
+	files, err := PRDFiles(projectDir)
+	if err != nil {
+		return PRDAnchor{}, 0, false
+	}
+	for _, file := range files {
+		idx, err := loadPRDFileIndex(projectDir, file)
+		if err != nil {
+			return PRDAnchor{}, 0, false
+		}
+		for i, a := range idx.anchors {
+			if a.FeatureID == featureID {
+				return a, idx.offsets[i], true
+			}
+		}
+	}
+	return PRDAnchor{}, 0, false
+}
+
+// PRDSize is a PRD file larger than prd.max_kb.
+type PRDSize struct {
+	File  string // project-relative
+	KB    int64
+	MaxKB int
+}
+
+// Suggestion tells how to bring an oversized PRD file under the limit.
+func (s PRDSize) Suggestion() string {
+	return fmt.Sprintf("split %s into one .md file per domain in %s (every .md there is scanned for anchors), or raise prd.max_kb", s.File, filepath.ToSlash(filepath.Dir(s.File)))
+}
+
+// OversizedPRDFiles returns the PRD files over prd.max_kb (default
+// DefaultPRDMaxKB; 0 disables the check).
+func OversizedPRDFiles(projectDir string) []PRDSize {
+	maxKB := DefaultPRDMaxKB
+	if cfg, err := LoadConfig(projectDir); err == nil {
+		maxKB = cfg.PRD.MaxKB
+	}
+	if maxKB <= 0 {
+		return nil
+	}
+	files, err := PRDFiles(projectDir)
+	if err != nil {
+		return nil
+	}
+	var out []PRDSize
+	for _, file := range files {
+		info, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(file)))
+		if err != nil {
+			continue
+		}
+		if kb := info.Size() / 1024; kb > int64(maxKB) {
+			out = append(out, PRDSize{File: file, KB: kb, MaxKB: maxKB})
+		}
+	}
+	return out
+}
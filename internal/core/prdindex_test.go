@@ -0,0 +1,116 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPRDIndexLargeFile(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress", "catalog:in-progress")
+	docsDir := filepath.Join(dir, ".ptsd", "docs")
+	if err := os.MkdirAll(docsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	prdPath := filepath.Join(docsDir, "PRD.md")
+	long := strings.Repeat("x", 200*1024)
+	prd := "# PRD\n<!-- feature:user-auth -->\n" + long + "\n<!-- feature:catalog -->\nCatalog section\r\nmore\n"
+	if err := os.WriteFile(prdPath, []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	section, err := ExtractPRDSection(dir, "catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if section.StartLine != 4 || section.Content != "Catalog section\nmore" {
+		t.Errorf("section = %d %q", section.StartLine, section.Content)
+	}
+	section, err = ExtractPRDSection(dir, "user-auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if section.Content != long {
+		t.Errorf("user-auth section has %d bytes, want %d", len(section.Content), len(long))
+	}
+
+	// Same size, different content: the racy check must re-index.
+	edited := strings.Replace(prd, "Catalog section", "Catalog changed", 1)
+	if err := os.WriteFile(prdPath, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	section, err = ExtractPRDSection(dir, "catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(section.Content, "Catalog changed") {
+		t.Errorf("stale section after edit: %q", section.Content)
+	}
+}
+
+func TestOversizedPRDFiles(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	docsDir := filepath.Join(dir, ".ptsd", "docs")
+	if err := os.MkdirAll(docsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	prd := "<!-- feature:user-auth -->\n" + strings.Repeat("line of requirements\n", 200)
+	if err := os.WriteFile(filepath.Join(docsDir, "PRD.md"), []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if big := OversizedPRDFiles(dir); len(big) != 0 {
+		t.Fatalf("default limit: got %v", big)
+	}
+
+	cfg := filepath.Join(dir, ".ptsd", "ptsd.yaml")
+	if err := os.WriteFile(cfg, []byte("prd:\n  max_kb: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	big := OversizedPRDFiles(dir)
+	if len(big) != 1 || big[0].File != ".ptsd/docs/PRD.md" || big[0].MaxKB != 1 {
+		t.Fatalf("got %v", big)
+	}
+	if !strings.Contains(big[0].Suggestion(), "split .ptsd/docs/PRD.md") {
+		t.Errorf("suggestion = %q", big[0].Suggestion())
+	}
+
+	errs, err := Validate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, e := range errs {
+		if e.Rule == "prd-size" {
+			found = true
+			if e.Severity != "warn" {
+				t.Errorf("prd-size severity = %q, want warn", e.Severity)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no prd-size warning in %v", errs)
+	}
+
+	checks, err := Doctor(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found = false
+	for _, c := range checks {
+		if c.Check == "prd" && c.Status == "warn" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("no prd doctor warning in %v", checks)
+	}
+
+	if err := os.WriteFile(cfg, []byte("prd:\n  max_kb: 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if big := OversizedPRDFiles(dir); len(big) != 0 {
+		t.Errorf("max_kb 0 should disable the check, got %v", big)
+	}
+}
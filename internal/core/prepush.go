@@ -0,0 +1,182 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Push policy (hooks.pre_push: true). A successful `ptsd validate` records
+// the tree it validated — the index, which becomes the next commit's tree —
+// in .git/ptsd-validated. Validation reads the working tree, so the index is
+// only recorded when it matched the working tree as validation started. The
+// pre-push hook refuses commits whose tree never passed validation, which is
+// what `git commit --no-verify` produces.
+
+// validatedLogLimit caps how many validated trees are remembered.
+const validatedLogLimit = 2000
+
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// UnvalidatedCommit is a commit about to be pushed whose tree never passed
+// `ptsd validate`.
+type UnvalidatedCommit struct {
+	Hash    string
+	Subject string
+}
+
+func gitOutput(projectDir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = projectDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("err:git git %s failed", args[0])
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func validatedLogPath(projectDir string) (string, error) {
+	gitDir, err := gitOutput(projectDir, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "ptsd-validated"), nil
+}
+
+func loadValidatedTrees(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(data))
+}
+
+// validationIgnores reports whether a changed or untracked path (relative to
+// the project) is one ptsd writes on its own and validation does not read:
+// its logs, and the runtime files snapshots skip too.
+func validationIgnores(path string) bool {
+	rest, ok := strings.CutPrefix(path, ".ptsd/")
+	if !ok {
+		return false
+	}
+	switch rest {
+	case "events.yaml", reviewLogFile, "signatures.yaml", "remote-sync.yaml":
+		return true
+	}
+	top, _, _ := strings.Cut(rest, "/")
+	return strings.HasPrefix(top, "hooks.log") || snapshotSkips(top)
+}
+
+// ValidationTree returns the index tree if validating the working tree now
+// validates it: no tracked file has unstaged changes and .ptsd has no
+// untracked files, ptsd's own logs and runtime files aside. Otherwise, and
+// outside a git repository, it returns "". Call it before validation writes
+// anything.
+func ValidationTree(projectDir string) string {
+	tree, err := gitOutput(projectDir, "write-tree")
+	if err != nil {
+		return ""
+	}
+	modified, err := gitOutput(projectDir, "diff", "--name-only", "--relative")
+	if err != nil {
+		return ""
+	}
+	untracked, err := gitOutput(projectDir, "ls-files", "--others", "--exclude-standard", "--", ".ptsd")
+	if err != nil {
+		return ""
+	}
+	for _, f := range strings.Split(modified+"\n"+untracked, "\n") {
+		if f != "" && !validationIgnores(f) {
+			return ""
+		}
+	}
+	return tree
+}
+
+// RecordValidatedTree remembers tree (see ValidationTree) as validated. An
+// empty tree, or a project outside a git repository, records nothing.
+func RecordValidatedTree(projectDir, tree string) error {
+	if tree == "" {
+		return nil
+	}
+	path, err := validatedLogPath(projectDir)
+	if err != nil {
+		return nil
+	}
+
+	trees := loadValidatedTrees(path)
+	for _, t := range trees {
+		if t == tree {
+			return nil
+		}
+	}
+	trees = append(trees, tree)
+	if len(trees) > validatedLogLimit {
+		trees = trees[len(trees)-validatedLogLimit:]
+	}
+	if err := writeFileAtomic(path, []byte(strings.Join(trees, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// CheckPushValidated reads git's pre-push input ("<local ref> <local sha>
+// <remote ref> <remote sha>" per line) and returns the commits being pushed
+// whose tree was never recorded by RecordValidatedTree.
+func CheckPushValidated(projectDir string, input string) ([]UnvalidatedCommit, error) {
+	path, err := validatedLogPath(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	validated := make(map[string]bool)
+	for _, t := range loadValidatedTrees(path) {
+		validated[t] = true
+	}
+
+	var bad []UnvalidatedCommit
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || fields[1] == zeroSHA {
+			continue // malformed, or a branch deletion
+		}
+		localSHA, remoteSHA := fields[1], fields[3]
+
+		// New branch: everything not already on a remote. Otherwise: what the
+		// remote does not have yet.
+		args := []string{"rev-list", "--format=%T %s", localSHA, "--not", "--remotes"}
+		if remoteSHA != zeroSHA {
+			args = []string{"rev-list", "--format=%T %s", remoteSHA + ".." + localSHA}
+		}
+		out, err := gitOutput(projectDir, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		lines := strings.Split(out, "\n")
+		for i := 0; i+1 < len(lines); i += 2 {
+			hash := strings.TrimPrefix(lines[i], "commit ")
+			tree, subject, _ := strings.Cut(lines[i+1], " ")
+			if seen[hash] || validated[tree] {
+				continue
+			}
+			seen[hash] = true
+			bad = append(bad, UnvalidatedCommit{Hash: hash, Subject: subject})
+		}
+	}
+	return bad, nil
+}
+
+// GeneratePrePushHook installs .git/hooks/pre-push when hooks.pre_push is
+// enabled; otherwise it leaves any existing hook alone.
+func GeneratePrePushHook(projectDir string) error {
+	cfg, err := LoadConfig(projectDir)
+	if err != nil || !cfg.Hooks.PrePush {
+		return nil
+	}
+	return installGitHook(projectDir, "pre-push", "hooks pre-push")
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckPushValidated(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	for k, v := range map[string]string{
+		"GIT_AUTHOR_NAME": "test", "GIT_AUTHOR_EMAIL": "test@test.com",
+		"GIT_COMMITTER_NAME": "test", "GIT_COMMITTER_EMAIL": "test@test.com",
+	} {
+		t.Setenv(k, v)
+	}
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init")
+
+	// Validated commit: tree recorded before committing, as pre-commit does.
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	git("add", ".")
+	if err := RecordValidatedTree(dir, ValidationTree(dir)); err != nil {
+		t.Fatalf("RecordValidatedTree failed: %v", err)
+	}
+	git("commit", "--no-verify", "-m", "validated")
+	good := git("rev-parse", "HEAD")
+
+	input := "refs/heads/main " + good + " refs/heads/main " + zeroSHA + "\n"
+	bad, err := CheckPushValidated(dir, input)
+	if err != nil {
+		t.Fatalf("CheckPushValidated failed: %v", err)
+	}
+	if len(bad) != 0 {
+		t.Errorf("expected validated commit to pass, got %+v", bad)
+	}
+
+	// Bypassed commit: nothing recorded its tree.
+	os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644)
+	git("add", "b.txt")
+	git("commit", "--no-verify", "-m", "sneaky")
+	head := git("rev-parse", "HEAD")
+
+	input = "refs/heads/main " + head + " refs/heads/main " + good + "\n"
+	bad, err = CheckPushValidated(dir, input)
+	if err != nil {
+		t.Fatalf("CheckPushValidated failed: %v", err)
+	}
+	if len(bad) != 1 || bad[0].Hash != head || bad[0].Subject != "sneaky" {
+		t.Errorf("expected only the bypassed commit, got %+v", bad)
+	}
+
+	// Branch deletions push no commits.
+	if bad, _ := CheckPushValidated(dir, "(delete) "+zeroSHA+" refs/heads/old "+good+"\n"); len(bad) != 0 {
+		t.Errorf("expected deletion to pass, got %+v", bad)
+	}
+}
+
+func TestValidationTreeNeedsIndexToMatchWorkingTree(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("broken"), 0644)
+	git("add", ".")
+
+	// An unstaged fix hides the broken staged content.
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("fixed"), 0644)
+	if tree := ValidationTree(dir); tree != "" {
+		t.Errorf("expected no tree while a.txt has unstaged changes, got %s", tree)
+	}
+	git("add", "a.txt")
+
+	// An untracked artifact validation sees but the commit would not have.
+	os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "auth.feature"), []byte("Feature: auth\n"), 0644)
+	if tree := ValidationTree(dir); tree != "" {
+		t.Errorf("expected no tree with an untracked .ptsd file, got %s", tree)
+	}
+	git("add", ".ptsd")
+
+	// ptsd's own log appends do not count.
+	os.WriteFile(filepath.Join(dir, ".ptsd", "events.yaml"), []byte("events: []\n"), 0644)
+	if tree := ValidationTree(dir); tree == "" {
+		t.Error("expected the index tree once it matches the working tree")
+	}
+}
+
+func TestGeneratePrePushHookRequiresOptIn(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	hookPath := filepath.Join(dir, ".git", "hooks", "pre-push")
+
+	if err := GeneratePrePushHook(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(hookPath); err == nil {
+		t.Fatal("pre-push hook must not be installed without hooks.pre_push")
+	}
+
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("hooks:\n  pre_push: true\n"), 0644)
+	if err := GeneratePrePushHook(dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(hookPath)
+	if err != nil || !strings.Contains(string(data), "hooks pre-push") {
+		t.Errorf("expected pre-push hook calling ptsd hooks pre-push, got %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ProgressEvent is one step of a long-running command, streamed as a JSON
+// line by `--progress jsonl`. Percent is -1 while the total is unknown.
+type ProgressEvent struct {
+	Phase   string `json:"phase"`
+	Percent int    `json:"percent"`
+	File    string `json:"file,omitempty"`
+	Passed  int    `json:"passed,omitempty"`
+	Failed  int    `json:"failed,omitempty"`
+}
+
+// ProgressFunc receives progress events. A nil ProgressFunc disables reporting.
+type ProgressFunc func(ProgressEvent)
+
+func (p ProgressFunc) emit(e ProgressEvent) {
+	if p != nil {
+		p(e)
+	}
+}
+
+// JSONLProgress returns a ProgressFunc writing one JSON object per line to w.
+func JSONLProgress(w io.Writer) ProgressFunc {
+	enc := json.NewEncoder(w)
+	return func(e ProgressEvent) {
+		enc.Encode(e)
+	}
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func collectProgress(events *[]ProgressEvent) ProgressFunc {
+	return func(e ProgressEvent) { *events = append(*events, e) }
+}
+
+func TestAdoptReportsProgress(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "features"), 0755)
+	os.WriteFile(filepath.Join(dir, "features", "auth.feature"), []byte("@feature:auth\nFeature: Auth\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "auth_test.go"), []byte("package x\n"), 0644)
+
+	var events []ProgressEvent
+	if err := AdoptProjectWithProgress(dir, collectProgress(&events)); err != nil {
+		t.Fatalf("AdoptProjectWithProgress: %v", err)
+	}
+
+	var phases []string
+	files := map[string]bool{}
+	for _, e := range events {
+		if e.Percent >= 0 {
+			phases = append(phases, e.Phase)
+		}
+		if e.File != "" {
+			files[e.File] = true
+		}
+	}
+	if got := strings.Join(phases, ","); got != "scan-bdd,scan-tests,import,done" {
+		t.Errorf("unexpected phase sequence %s", got)
+	}
+	if !files[filepath.Join("features", "auth.feature")] || !files["auth_test.go"] {
+		t.Errorf("expected per-file events, got %v", files)
+	}
+	if last := events[len(events)-1]; last.Percent != 100 {
+		t.Errorf("last event must be 100%%, got %+v", last)
+	}
+}
+
+func TestRunTestsStreamsResultLines(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".ptsd"), 0755)
+	os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\necho '--- PASS: TestA (0.00s)'\necho '--- FAIL: TestB (0.01s)'\nexit 1\n"), 0755)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("project:\n  name: X\ntesting:\n  runner: ./run.sh\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte("features: {}\n"), 0644)
+
+	var events []ProgressEvent
+	results, err := RunTestsWithProgress(dir, "", collectProgress(&events))
+	if err != nil {
+		t.Fatalf("RunTestsWithProgress: %v", err)
+	}
+	if results.Passed != 1 || results.Failed != 1 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+
+	var names []string
+	for _, e := range events {
+		if e.File != "" {
+			names = append(names, e.File)
+		}
+	}
+	if got := strings.Join(names, ","); got != "TestA,TestB" {
+		t.Errorf("expected streamed TestA,TestB, got %s (%+v)", got, events)
+	}
+	if last := events[len(events)-1]; last.Phase != "done" || last.Failed != 1 {
+		t.Errorf("unexpected final event %+v", last)
+	}
+}
+
+func TestJSONLProgressWritesOneObjectPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	p := JSONLProgress(&buf)
+	p(ProgressEvent{Phase: "scan-bdd", Percent: 0})
+	p(ProgressEvent{Phase: "scan-bdd", Percent: -1, File: "a.feature"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	var e ProgressEvent
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil || e.File != "a.feature" {
+		t.Errorf("bad jsonl line %q: %v", lines[1], err)
+	}
+}
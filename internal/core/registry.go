@@ -3,17 +3,34 @@ package core
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Feature struct {
 	ID     string
 	Title  string
 	Status string
+	// Pipeline is "" for the full pipeline or PipelineLite.
+	Pipeline string
+	// DeferReason and Revisit (YYYY-MM-DD, optional) are set by DeferFeature.
+	DeferReason string
+	Revisit     string
+	// Parent is the feature this one was split from (SplitFeature).
+	Parent string
+	// DependsOn lists features that must be implemented before this one
+	// moves past BDD (see depends.go).
+	DependsOn []string
 }
 
+// PipelineLite marks a feature (chores, refactors) that skips the seed and BDD
+// stages but still needs tests and review.
+const PipelineLite = "lite"
+
 type FeatureDetail struct {
 	ID            string
 	Status        string
@@ -21,6 +38,10 @@ type FeatureDetail struct {
 	SeedStatus    string
 	ScenarioCount int
 	TestCount     int
+	Pipeline      string
+	Parent        string
+	Children      []string
+	DependsOn     []string
 }
 
 var validFeatureID = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
@@ -33,6 +54,23 @@ var validStatuses = map[string]bool{
 }
 
 func AddFeature(projectDir string, id string, title string) error {
+	return AddFeatureWithPipeline(projectDir, id, title, "")
+}
+
+// AddFeatureWithPipeline registers a feature in the given pipeline mode
+// ("" or "full" for the full pipeline, "lite").
+func AddFeatureWithPipeline(projectDir string, id string, title string, mode string) error {
+	return AddFeatureWithParent(projectDir, id, title, mode, "")
+}
+
+// AddFeatureWithParent registers a feature as a child of parent ("" for a
+// top-level feature).
+func AddFeatureWithParent(projectDir string, id string, title string, mode string, parent string) error {
+	mode, err := normalizePipelineMode(mode)
+	if err != nil {
+		return err
+	}
+
 	if !validFeatureID.MatchString(id) {
 		return fmt.Errorf("err:validation invalid feature ID %q: must be ASCII slug (a-z0-9 with hyphens)", id)
 	}
@@ -48,10 +86,58 @@ func AddFeature(projectDir string, id string, title string) error {
 		}
 	}
 
-	features = append(features, Feature{ID: id, Title: title, Status: "planned"})
+	if err := checkFeatureParent(features, id, parent); err != nil {
+		return err
+	}
+
+	features = append(features, Feature{ID: id, Title: title, Status: "planned", Pipeline: mode, Parent: parent})
 	return saveFeatures(projectDir, features)
 }
 
+// SetFeaturePipeline switches a feature between the full and lite pipelines.
+func SetFeaturePipeline(projectDir string, id string, mode string) error {
+	mode, err := normalizePipelineMode(mode)
+	if err != nil {
+		return err
+	}
+
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return err
+	}
+	for i := range features {
+		if features[i].ID == id {
+			features[i].Pipeline = mode
+			return saveFeatures(projectDir, features)
+		}
+	}
+	return fmt.Errorf("err:validation feature %s not found", id)
+}
+
+func normalizePipelineMode(mode string) (string, error) {
+	switch mode {
+	case "", "full":
+		return "", nil
+	case PipelineLite:
+		return PipelineLite, nil
+	}
+	return "", fmt.Errorf("err:validation invalid pipeline %q: must be full|lite", mode)
+}
+
+// isLiteFeature reports whether a feature is registered with pipeline: lite.
+func isLiteFeature(projectDir string, id string) bool {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return false
+	}
+	for _, f := range features {
+		if f.ID == id {
+			return f.Pipeline == PipelineLite
+		}
+	}
+	return false
+}
+
 func ListFeatures(projectDir string, statusFilter string) ([]Feature, error) {
 	features, err := loadFeatures(projectDir)
 	if err != nil {
@@ -89,36 +175,42 @@ func ShowFeature(projectDir string, id string) (FeatureDetail, error) {
 	}
 
 	detail := FeatureDetail{
-		ID:     found.ID,
-		Status: found.Status,
+		ID:       found.ID,
+		Status:   found.Status,
+		Pipeline: found.Pipeline,
+		Parent:   found.Parent,
+	}
+	detail.DependsOn = found.DependsOn
+	for _, c := range featureChildren(features, id) {
+		detail.Children = append(detail.Children, c.ID)
 	}
 
-	seedDir := filepath.Join(projectDir, ".ptsd", "seeds", id)
+	seedDir := seedDirPath(projectDir, id)
 	if info, err := os.Stat(seedDir); err == nil && info.IsDir() {
 		detail.SeedStatus = "ok"
 	} else {
 		detail.SeedStatus = "missing"
 	}
 
-	bddFile := filepath.Join(projectDir, ".ptsd", "bdd", id+".feature")
+	bddFile := bddFilePath(projectDir, id)
 	if data, err := os.ReadFile(bddFile); err == nil {
-		for _, line := range strings.Split(string(data), "\n") {
-			if strings.HasPrefix(strings.TrimSpace(line), "Scenario:") {
-				detail.ScenarioCount++
-			}
-		}
+		ff, _ := parseFeatureContent(string(data))
+		detail.ScenarioCount = len(ff.Scenarios)
 	}
 
 	detail.TestCount = readTestCount(projectDir, id)
 
-	prdPath := filepath.Join(projectDir, ".ptsd", "docs", "PRD.md")
-	if data, err := os.ReadFile(prdPath); err == nil {
-		anchor := "<!-- feature:" + id + " -->"
-		for i, line := range strings.Split(string(data), "\n") {
-			if strings.TrimSpace(line) == anchor {
-				detail.PRDAnchor = fmt.Sprintf("l%d", i+1)
-				break
+	// Main PRD anchors read "l12"; anchors in other PRD parts name the file.
+	if index, err := PRDIndex(projectDir); err == nil {
+		for _, a := range index {
+			if a.FeatureID != id {
+				continue
+			}
+			detail.PRDAnchor = fmt.Sprintf("l%d", a.Line)
+			if a.File != prdMainRel(projectDir) {
+				detail.PRDAnchor = path.Base(a.File) + ":" + detail.PRDAnchor
 			}
+			break
 		}
 	}
 
@@ -147,6 +239,9 @@ func UpdateFeatureStatus(projectDir string, id string, newStatus string) error {
 	}
 
 	if newStatus == "implemented" {
+		if pending := incompleteDescendants(features, id); len(pending) > 0 {
+			return fmt.Errorf("err:pipeline children of %s not implemented: %s", id, strings.Join(pending, ", "))
+		}
 		statePath := filepath.Join(projectDir, ".ptsd", "state.yaml")
 		data, err := os.ReadFile(statePath)
 		if err != nil {
@@ -159,9 +254,81 @@ func UpdateFeatureStatus(projectDir string, id string, newStatus string) error {
 	}
 
 	features[idx].Status = newStatus
+	if newStatus != "deferred" {
+		features[idx].DeferReason = ""
+		features[idx].Revisit = ""
+	}
 	return saveFeatures(projectDir, features)
 }
 
+// revisitLayout is the date format for deferred features' revisit dates.
+const revisitLayout = "2006-01-02"
+
+// DeferFeature parks a feature: status becomes deferred and the reason and
+// optional revisit date are recorded in features.yaml.
+func DeferFeature(projectDir string, id string, reason string, revisit string) error {
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("err:validation defer reason required for %s", id)
+	}
+	if revisit != "" {
+		if _, err := time.Parse(revisitLayout, revisit); err != nil {
+			return fmt.Errorf("err:validation invalid revisit date %q: must be YYYY-MM-DD", revisit)
+		}
+	}
+
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return err
+	}
+	for i := range features {
+		if features[i].ID == id {
+			features[i].Status = "deferred"
+			features[i].DeferReason = reason
+			features[i].Revisit = revisit
+			return saveFeatures(projectDir, features)
+		}
+	}
+	return fmt.Errorf("err:validation feature %s not found", id)
+}
+
+// UndeferFeature returns a deferred feature to planned and clears its reason
+// and revisit date.
+func UndeferFeature(projectDir string, id string) error {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return err
+	}
+	for i := range features {
+		if features[i].ID != id {
+			continue
+		}
+		if features[i].Status != "deferred" {
+			return fmt.Errorf("err:validation feature %s is not deferred", id)
+		}
+		features[i].Status = "planned"
+		features[i].DeferReason = ""
+		features[i].Revisit = ""
+		return saveFeatures(projectDir, features)
+	}
+	return fmt.Errorf("err:validation feature %s not found", id)
+}
+
+// DueForRevisit returns deferred features whose revisit date is on or before now.
+func DueForRevisit(projectDir string, now time.Time) ([]Feature, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	today := now.Format(revisitLayout)
+	var due []Feature
+	for _, f := range features {
+		if f.Status == "deferred" && f.Revisit != "" && f.Revisit <= today {
+			due = append(due, f)
+		}
+	}
+	return due, nil
+}
+
 func RemoveFeature(projectDir string, id string) error {
 	features, err := loadFeatures(projectDir)
 	if err != nil {
@@ -187,29 +354,54 @@ func RemoveFeature(projectDir string, id string) error {
 
 func loadFeatures(projectDir string) ([]Feature, error) {
 	featPath := filepath.Join(projectDir, ".ptsd", "features.yaml")
-	data, err := os.ReadFile(featPath)
+	data, err := readFileCached(featPath)
 	if err != nil {
 		return nil, fmt.Errorf("err:io %w", err)
 	}
+	return featuresMemo.get(string(data), parseFeatures)
+}
 
+func parseFeatures(content string) ([]Feature, error) {
 	var features []Feature
-	lines := strings.Split(string(data), "\n")
+	lines := strings.Split(content, "\n")
 	for i := 0; i < len(lines); i++ {
 		trimmed := strings.TrimSpace(lines[i])
 		if strings.HasPrefix(trimmed, "- id: ") {
 			f := Feature{ID: strings.TrimPrefix(trimmed, "- id: ")}
+			inDeps := false
 			for j := i + 1; j < len(lines); j++ {
 				next := strings.TrimSpace(lines[j])
 				if strings.HasPrefix(next, "- id: ") || next == "" {
 					break
 				}
+				// Block-style depends_on items: "- other-feature".
+				if inDeps && strings.HasPrefix(next, "- ") {
+					f.DependsOn = append(f.DependsOn, strings.TrimSpace(strings.TrimPrefix(next, "- ")))
+					continue
+				}
+				inDeps = false
+				if strings.HasPrefix(next, "depends_on:") {
+					f.DependsOn = parseFeatureList(strings.TrimPrefix(next, "depends_on:"))
+					inDeps = len(f.DependsOn) == 0
+				}
 				if strings.HasPrefix(next, "title: ") {
-					f.Title = strings.TrimPrefix(next, "title: ")
-					f.Title = strings.Trim(f.Title, "\"")
+					f.Title = unquoteFeatureField(strings.TrimPrefix(next, "title: "))
 				}
 				if strings.HasPrefix(next, "status: ") {
 					f.Status = strings.TrimPrefix(next, "status: ")
 				}
+				if strings.HasPrefix(next, "pipeline: ") {
+					f.Pipeline = strings.TrimPrefix(next, "pipeline: ")
+				}
+				if strings.HasPrefix(next, "defer_reason: ") {
+					f.DeferReason = unquoteFeatureField(strings.TrimPrefix(next, "defer_reason: "))
+				}
+				if strings.HasPrefix(next, "revisit: ") {
+					f.Revisit = strings.TrimPrefix(next, "revisit: ")
+				}
+				if strings.HasPrefix(next, "parent: ") {
+					f.Parent = strings.TrimPrefix(next, "parent: ")
+				}
 			}
 			features = append(features, f)
 		}
@@ -225,15 +417,58 @@ func saveFeatures(projectDir string, features []Feature) error {
 	b.WriteString("features:\n")
 	for _, f := range features {
 		b.WriteString("  - id: " + f.ID + "\n")
-		title := f.Title
-		if strings.ContainsAny(title, " :\"'#") {
-			title = "\"" + strings.ReplaceAll(title, "\"", "\\\"") + "\""
-		}
-		b.WriteString("    title: " + title + "\n")
+		b.WriteString("    title: " + quoteFeatureField(f.Title) + "\n")
 		b.WriteString("    status: " + f.Status + "\n")
+		if f.Pipeline != "" {
+			b.WriteString("    pipeline: " + f.Pipeline + "\n")
+		}
+		if f.DeferReason != "" {
+			b.WriteString("    defer_reason: " + quoteFeatureField(f.DeferReason) + "\n")
+		}
+		if f.Revisit != "" {
+			b.WriteString("    revisit: " + f.Revisit + "\n")
+		}
+		if f.Parent != "" {
+			b.WriteString("    parent: " + f.Parent + "\n")
+		}
+		if len(f.DependsOn) > 0 {
+			b.WriteString("    depends_on: [" + strings.Join(f.DependsOn, ", ") + "]\n")
+		}
 	}
 
-	return os.WriteFile(featPath, []byte(b.String()), 0644)
+	return writeFileAtomic(featPath, []byte(b.String()), 0644)
+}
+
+// parseFeatureList parses a flow list ("[a, b]") or a single bare value.
+func parseFeatureList(v string) []string {
+	v = strings.TrimSpace(v)
+	v = strings.TrimSuffix(strings.TrimPrefix(v, "["), "]")
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.Trim(strings.TrimSpace(item), "\"'"); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// quoteFeatureField double-quotes free-text values (titles, defer reasons).
+func quoteFeatureField(v string) string {
+	if strings.ContainsAny(v, " :\"'#\\") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// unquoteFeatureField undoes quoteFeatureField. A hand-edited value that is
+// not a valid quoted string only loses its surrounding quotes.
+func unquoteFeatureField(v string) string {
+	if strings.HasPrefix(v, "\"") {
+		if unq, err := strconv.Unquote(v); err == nil {
+			return unq
+		}
+	}
+	return strings.Trim(v, "\"")
 }
 
 func readTestCount(projectDir string, featureID string) int {
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAddFeature(t *testing.T) {
@@ -278,3 +279,120 @@ func setTestsPassing(t *testing.T, dir string, id string) {
 		t.Fatal(err)
 	}
 }
+
+func TestAddFeatureLitePipelineRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	setupFeaturesYAML(t, dir)
+
+	if err := AddFeatureWithPipeline(dir, "bump-deps", "Bump deps", "lite"); err != nil {
+		t.Fatalf("AddFeatureWithPipeline failed: %v", err)
+	}
+	if err := AddFeature(dir, "user-auth", "User Auth"); err != nil {
+		t.Fatal(err)
+	}
+
+	features, _ := ListFeatures(dir, "")
+	if features[0].Pipeline != PipelineLite || features[1].Pipeline != "" {
+		t.Errorf("unexpected pipelines: %+v", features)
+	}
+	if got := strings.Join(FeatureStages(dir, "bump-deps"), ","); got != "prd,tests,impl" {
+		t.Errorf("expected lite stages prd,tests,impl, got %s", got)
+	}
+
+	if err := SetFeaturePipeline(dir, "bump-deps", "full"); err != nil {
+		t.Fatal(err)
+	}
+	if isLiteFeature(dir, "bump-deps") {
+		t.Error("expected full pipeline after switch")
+	}
+
+	err := SetFeaturePipeline(dir, "bump-deps", "tiny")
+	if err == nil || !strings.HasPrefix(err.Error(), "err:validation") {
+		t.Errorf("expected err:validation for unknown pipeline, got %v", err)
+	}
+}
+
+func TestLiteFeatureGateAllowsTestsWithoutBDD(t *testing.T) {
+	dir := t.TempDir()
+	setupFeaturesYAML(t, dir)
+	AddFeatureWithPipeline(dir, "bump-deps", "Bump deps", "lite")
+
+	if res := GateCheck(dir, "internal/deps/bump-deps_test.go"); !res.Allowed {
+		t.Errorf("lite feature tests should not require BDD: %s", res.Reason)
+	}
+	if err := RecordReview(dir, "bump-deps", "bdd", 8); err == nil {
+		t.Error("bdd review must be rejected for a lite feature")
+	}
+}
+
+func TestDeferAndUndeferFeature(t *testing.T) {
+	dir := t.TempDir()
+	setupFeaturesYAML(t, dir)
+	AddFeature(dir, "billing", "Billing")
+
+	if err := DeferFeature(dir, "billing", "waiting on vendor: Q3", "2026-01-15"); err != nil {
+		t.Fatalf("DeferFeature failed: %v", err)
+	}
+	features, _ := ListFeatures(dir, "")
+	f := features[0]
+	if f.Status != "deferred" || f.DeferReason != "waiting on vendor: Q3" || f.Revisit != "2026-01-15" {
+		t.Errorf("unexpected deferred feature: %+v", f)
+	}
+
+	due, _ := DueForRevisit(dir, time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC))
+	if len(due) != 0 {
+		t.Errorf("not due before revisit date, got %v", due)
+	}
+	due, _ = DueForRevisit(dir, time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC))
+	if len(due) != 1 || due[0].ID != "billing" {
+		t.Errorf("expected billing due on revisit date, got %v", due)
+	}
+
+	if err := UndeferFeature(dir, "billing"); err != nil {
+		t.Fatalf("UndeferFeature failed: %v", err)
+	}
+	features, _ = ListFeatures(dir, "")
+	if features[0].Status != "planned" || features[0].DeferReason != "" || features[0].Revisit != "" {
+		t.Errorf("undefer must reset status and clear defer fields: %+v", features[0])
+	}
+	if err := UndeferFeature(dir, "billing"); err == nil {
+		t.Error("expected error undeferring a planned feature")
+	}
+}
+
+func TestDeferReasonSurvivesRepeatedSaves(t *testing.T) {
+	dir := t.TempDir()
+	setupFeaturesYAML(t, dir)
+	AddFeature(dir, "billing", `Billing "v2" \ EU`)
+
+	reason := `waiting on "api" team`
+	if err := DeferFeature(dir, "billing", reason, ""); err != nil {
+		t.Fatal(err)
+	}
+	// Every add rewrites features.yaml from what was loaded.
+	AddFeature(dir, "search", "Search")
+	AddFeature(dir, "export", "Export")
+
+	features, _ := ListFeatures(dir, "")
+	for _, f := range features {
+		if f.ID == "billing" && (f.DeferReason != reason || f.Title != `Billing "v2" \ EU`) {
+			t.Errorf("quoted fields changed across saves: reason=%q title=%q", f.DeferReason, f.Title)
+		}
+	}
+}
+
+func TestDeferFeatureValidation(t *testing.T) {
+	dir := t.TempDir()
+	setupFeaturesYAML(t, dir)
+	AddFeature(dir, "billing", "Billing")
+
+	if err := DeferFeature(dir, "billing", "", ""); err == nil {
+		t.Error("expected error for empty reason")
+	}
+	if err := DeferFeature(dir, "billing", "later", "next week"); err == nil || !strings.HasPrefix(err.Error(), "err:validation") {
+		t.Errorf("expected err:validation for bad date, got %v", err)
+	}
+	if err := DeferFeature(dir, "missing", "later", ""); err == nil {
+		t.Error("expected error for unknown feature")
+	}
+}
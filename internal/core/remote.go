@@ -0,0 +1,336 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// remoteFiles are the mutable pipeline files shared through the remote backend.
+// Artifacts (PRD, seeds, BDD) stay in git; only state that churns on every
+// review/test/task mutation is synced.
+var remoteFiles = []string{"state.yaml", "review-status.yaml", "tasks.yaml", "events.yaml"}
+
+// RemoteSyncEntry is the last synced version of a file: the backend ETag and
+// the sha256 of the content we uploaded or downloaded.
+type RemoteSyncEntry struct {
+	Name string
+	ETag string
+	Hash string
+}
+
+// RemoteResult reports what a push or pull did per file.
+type RemoteResult struct {
+	Pushed    []string
+	Pulled    []string
+	Unchanged []string
+}
+
+var remoteClient = &http.Client{Timeout: 30 * time.Second}
+
+func remoteSyncPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "remote-sync.yaml")
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func loadRemoteConfig(projectDir string) (RemoteConfig, error) {
+	cfg, err := LoadConfig(projectDir)
+	if err != nil {
+		return RemoteConfig{}, err
+	}
+	if cfg.Remote.URL == "" {
+		return RemoteConfig{}, fmt.Errorf("err:config remote.url not set in .ptsd/ptsd.yaml")
+	}
+	// Signatures use a per-machine key: another clone's signed events and
+	// state can never verify here, and re-signing them would vouch for
+	// writes this machine never saw.
+	if cfg.Audit.Sign {
+		return RemoteConfig{}, fmt.Errorf("err:config remote sync cannot be used with audit.sign: signatures are per machine")
+	}
+	if err := CheckNetwork(projectDir, "remote sync"); err != nil {
+		return RemoteConfig{}, err
+	}
+	return cfg.Remote, nil
+}
+
+// RemotePush uploads local state files that changed since the last sync.
+// Uploads are conditional (If-Match on the last known ETag, If-None-Match: *
+// for new objects), so a concurrent update by another clone fails with
+// err:pipeline instead of being overwritten. A backend that sends no ETags
+// is checked by content hash before the upload instead.
+func RemotePush(projectDir string) (RemoteResult, error) {
+	var result RemoteResult
+
+	remote, err := loadRemoteConfig(projectDir)
+	if err != nil {
+		return result, err
+	}
+
+	synced, err := loadRemoteSync(projectDir)
+	if err != nil {
+		return result, err
+	}
+
+	var conflicts []string
+	for _, name := range remoteFiles {
+		data, err := os.ReadFile(filepath.Join(projectDir, ".ptsd", name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("err:io %w", err)
+		}
+
+		hash := contentHash(data)
+		prev := synced[name]
+		if prev.Hash == hash {
+			result.Unchanged = append(result.Unchanged, name)
+			continue
+		}
+
+		req, err := remoteRequest(remote, http.MethodPut, name, data)
+		if err != nil {
+			return result, err
+		}
+		switch {
+		case prev.ETag != "":
+			req.Header.Set("If-Match", prev.ETag)
+		case prev.Hash != "":
+			// The backend sent no ETag last time: compare its content with
+			// what we last synced instead.
+			changed, err := remoteChanged(remote, name, prev.Hash)
+			if err != nil {
+				return result, err
+			}
+			if changed {
+				conflicts = append(conflicts, name)
+				continue
+			}
+		default:
+			req.Header.Set("If-None-Match", "*")
+		}
+
+		resp, err := remoteClient.Do(req)
+		if err != nil {
+			return result, fmt.Errorf("err:io remote push %s: %w", name, err)
+		}
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusPreconditionFailed:
+			conflicts = append(conflicts, name)
+			continue
+		case resp.StatusCode >= 300:
+			return result, fmt.Errorf("err:io remote push %s: %s", name, resp.Status)
+		}
+
+		synced[name] = RemoteSyncEntry{Name: name, ETag: resp.Header.Get("ETag"), Hash: hash}
+		result.Pushed = append(result.Pushed, name)
+	}
+
+	if err := saveRemoteSync(projectDir, synced); err != nil {
+		return result, err
+	}
+	if len(conflicts) > 0 {
+		return result, fmt.Errorf("err:pipeline remote changed since last sync: %s — run ptsd remote pull first", strings.Join(conflicts, ", "))
+	}
+	return result, nil
+}
+
+// RemotePull downloads state files that changed on the backend. A file that
+// changed both locally and remotely since the last sync, or that differs from
+// the remote on the first pull, is a conflict and is left untouched.
+func RemotePull(projectDir string) (RemoteResult, error) {
+	var result RemoteResult
+
+	remote, err := loadRemoteConfig(projectDir)
+	if err != nil {
+		return result, err
+	}
+
+	synced, err := loadRemoteSync(projectDir)
+	if err != nil {
+		return result, err
+	}
+
+	var conflicts []string
+	for _, name := range remoteFiles {
+		localPath := filepath.Join(projectDir, ".ptsd", name)
+		prev := synced[name]
+
+		req, err := remoteRequest(remote, http.MethodGet, name, nil)
+		if err != nil {
+			return result, err
+		}
+		if prev.ETag != "" {
+			req.Header.Set("If-None-Match", prev.ETag)
+		}
+
+		resp, err := remoteClient.Do(req)
+		if err != nil {
+			return result, fmt.Errorf("err:io remote pull %s: %w", name, err)
+		}
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusNotModified || resp.StatusCode == http.StatusNotFound:
+			result.Unchanged = append(result.Unchanged, name)
+			continue
+		case resp.StatusCode >= 300:
+			return result, fmt.Errorf("err:io remote pull %s: %s", name, resp.Status)
+		case readErr != nil:
+			return result, fmt.Errorf("err:io remote pull %s: %w", name, readErr)
+		}
+
+		remoteHash := contentHash(body)
+		if prev.Hash != "" && remoteHash == prev.Hash {
+			// The backend sent no ETag to compare, but its content is what
+			// we last synced: any local difference is ours to push.
+			synced[name] = RemoteSyncEntry{Name: name, ETag: resp.Header.Get("ETag"), Hash: remoteHash}
+			result.Unchanged = append(result.Unchanged, name)
+			continue
+		}
+		local, err := os.ReadFile(localPath)
+		if err == nil {
+			localHash := contentHash(local)
+			if localHash == remoteHash {
+				synced[name] = RemoteSyncEntry{Name: name, ETag: resp.Header.Get("ETag"), Hash: remoteHash}
+				result.Unchanged = append(result.Unchanged, name)
+				continue
+			}
+			// With no sync record yet, a differing local file is local work
+			// the remote never saw, not a stale copy.
+			if prev.Hash == "" || localHash != prev.Hash {
+				conflicts = append(conflicts, name)
+				continue
+			}
+		}
+
+		if err := writeFileAtomic(localPath, body, 0644); err != nil {
+			return result, fmt.Errorf("err:io %w", err)
+		}
+		synced[name] = RemoteSyncEntry{Name: name, ETag: resp.Header.Get("ETag"), Hash: remoteHash}
+		result.Pulled = append(result.Pulled, name)
+	}
+
+	if err := saveRemoteSync(projectDir, synced); err != nil {
+		return result, err
+	}
+	if len(conflicts) > 0 {
+		return result, fmt.Errorf("err:pipeline local and remote both changed: %s — resolve manually", strings.Join(conflicts, ", "))
+	}
+	return result, nil
+}
+
+// remoteChanged reports whether the backend's copy of name no longer has the
+// content hash we last synced.
+func remoteChanged(remote RemoteConfig, name, hash string) (bool, error) {
+	req, err := remoteRequest(remote, http.MethodGet, name, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("err:io remote push %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return true, nil
+	case resp.StatusCode >= 300:
+		return false, fmt.Errorf("err:io remote push %s: %s", name, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("err:io remote push %s: %w", name, err)
+	}
+	return contentHash(body) != hash, nil
+}
+
+func remoteRequest(remote RemoteConfig, method, name string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, remote.URL+"/"+name, reader)
+	if err != nil {
+		return nil, fmt.Errorf("err:config invalid remote.url: %v", err)
+	}
+	if remote.TokenEnv != "" {
+		if token := os.Getenv(remote.TokenEnv); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/yaml")
+	}
+	return req, nil
+}
+
+func loadRemoteSync(projectDir string) (map[string]RemoteSyncEntry, error) {
+	entries := make(map[string]RemoteSyncEntry)
+
+	data, err := os.ReadFile(remoteSyncPath(projectDir))
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+
+	var cur *RemoteSyncEntry
+	flush := func() {
+		if cur != nil && cur.Name != "" {
+			entries[cur.Name] = *cur
+		}
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "- name:") {
+			flush()
+			cur = &RemoteSyncEntry{Name: strings.TrimSpace(strings.TrimPrefix(trimmed, "- name:"))}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "etag:") {
+			cur.ETag = strings.ReplaceAll(stripQuotes(strings.TrimSpace(strings.TrimPrefix(trimmed, "etag:"))), `\"`, `"`)
+		} else if strings.HasPrefix(trimmed, "hash:") {
+			cur.Hash = strings.TrimSpace(strings.TrimPrefix(trimmed, "hash:"))
+		}
+	}
+	flush()
+
+	return entries, nil
+}
+
+func saveRemoteSync(projectDir string, entries map[string]RemoteSyncEntry) error {
+	var b strings.Builder
+	b.WriteString("files:\n")
+	for _, name := range remoteFiles {
+		e, ok := entries[name]
+		if !ok {
+			continue
+		}
+		b.WriteString("  - name: " + name + "\n")
+		b.WriteString("    etag: \"" + strings.ReplaceAll(e.ETag, `"`, `\"`) + "\"\n")
+		b.WriteString("    hash: " + e.Hash + "\n")
+	}
+	if err := writeFileAtomic(remoteSyncPath(projectDir), []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeObjectStore is a minimal ETag-aware object store honoring If-Match / If-None-Match.
+func fakeObjectStore(t *testing.T) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	etag := func(b []byte) string {
+		sum := sha256.Sum256(b)
+		return `"` + hex.EncodeToString(sum[:8]) + `"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		obj, exists := objects[r.URL.Path]
+
+		switch r.Method {
+		case http.MethodGet:
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.Header.Get("If-None-Match") == etag(obj) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag(obj))
+			w.Write(obj)
+		case http.MethodPut:
+			if m := r.Header.Get("If-Match"); m != "" && (!exists || m != etag(obj)) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			if r.Header.Get("If-None-Match") == "*" && exists {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+			w.Header().Set("ETag", etag(body))
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func setupRemoteProject(t *testing.T, url string) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	cfg := "project:\n  name: Remote\nremote:\n  url: " + url + "/proj\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRemotePushPull(t *testing.T) {
+	srv := fakeObjectStore(t)
+	a := setupRemoteProject(t, srv.URL)
+	b := setupRemoteProject(t, srv.URL)
+
+	state := "features:\n  auth:\n    stage: seed\n"
+	os.WriteFile(filepath.Join(a, ".ptsd", "state.yaml"), []byte(state), 0644)
+
+	res, err := RemotePush(a)
+	if err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if len(res.Pushed) != 1 || res.Pushed[0] != "state.yaml" {
+		t.Errorf("expected state.yaml pushed, got %+v", res)
+	}
+
+	res, err = RemotePull(b)
+	if err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+	if len(res.Pulled) != 1 {
+		t.Errorf("expected 1 file pulled, got %+v", res)
+	}
+	got, _ := os.ReadFile(filepath.Join(b, ".ptsd", "state.yaml"))
+	if string(got) != state {
+		t.Errorf("expected pulled state, got %q", got)
+	}
+
+	// Second pull is a no-op (304).
+	res, err = RemotePull(b)
+	if err != nil {
+		t.Fatalf("second pull failed: %v", err)
+	}
+	if len(res.Pulled) != 0 {
+		t.Errorf("expected nothing pulled, got %+v", res)
+	}
+}
+
+func TestRemotePushConflict(t *testing.T) {
+	srv := fakeObjectStore(t)
+	a := setupRemoteProject(t, srv.URL)
+	b := setupRemoteProject(t, srv.URL)
+
+	os.WriteFile(filepath.Join(a, ".ptsd", "state.yaml"), []byte("features:\n  auth:\n    stage: seed\n"), 0644)
+	if _, err := RemotePush(a); err != nil {
+		t.Fatal(err)
+	}
+
+	os.WriteFile(filepath.Join(b, ".ptsd", "state.yaml"), []byte("features:\n  auth:\n    stage: bdd\n"), 0644)
+	_, err := RemotePush(b)
+	if err == nil || !strings.HasPrefix(err.Error(), "err:pipeline") {
+		t.Fatalf("expected err:pipeline conflict, got %v", err)
+	}
+}
+
+func TestRemotePullConflictKeepsLocal(t *testing.T) {
+	srv := fakeObjectStore(t)
+	a := setupRemoteProject(t, srv.URL)
+	b := setupRemoteProject(t, srv.URL)
+
+	os.WriteFile(filepath.Join(a, ".ptsd", "state.yaml"), []byte("v1\n"), 0644)
+	RemotePush(a)
+	RemotePull(b)
+
+	os.WriteFile(filepath.Join(a, ".ptsd", "state.yaml"), []byte("v2-remote\n"), 0644)
+	RemotePush(a)
+	os.WriteFile(filepath.Join(b, ".ptsd", "state.yaml"), []byte("v2-local\n"), 0644)
+
+	_, err := RemotePull(b)
+	if err == nil || !strings.Contains(err.Error(), "state.yaml") {
+		t.Fatalf("expected conflict on state.yaml, got %v", err)
+	}
+	got, _ := os.ReadFile(filepath.Join(b, ".ptsd", "state.yaml"))
+	if string(got) != "v2-local\n" {
+		t.Errorf("local file must be kept on conflict, got %q", got)
+	}
+}
+
+func TestRemoteNotConfigured(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("project:\n  name: X\n"), 0644)
+
+	_, err := RemotePush(dir)
+	if err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Errorf("expected err:config, got %v", err)
+	}
+}
+
+func TestRemoteFirstPullKeepsDifferingLocal(t *testing.T) {
+	srv := fakeObjectStore(t)
+	a := setupRemoteProject(t, srv.URL)
+	b := setupRemoteProject(t, srv.URL)
+
+	os.WriteFile(filepath.Join(a, ".ptsd", "state.yaml"), []byte("remote\n"), 0644)
+	if _, err := RemotePush(a); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(b, ".ptsd", "state.yaml"), []byte("local work\n"), 0644)
+
+	_, err := RemotePull(b)
+	if err == nil || !strings.HasPrefix(err.Error(), "err:pipeline") || !strings.Contains(err.Error(), "state.yaml") {
+		t.Fatalf("expected conflict on state.yaml, got %v", err)
+	}
+	got, _ := os.ReadFile(filepath.Join(b, ".ptsd", "state.yaml"))
+	if string(got) != "local work\n" {
+		t.Errorf("first pull must not overwrite local work, got %q", got)
+	}
+}
+
+// plainObjectStore is a backend that sends no ETags and ignores conditions.
+func plainObjectStore(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			obj, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(obj)
+		case http.MethodPut:
+			objects[r.URL.Path], _ = io.ReadAll(r.Body)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRemoteWithoutETags(t *testing.T) {
+	srv := plainObjectStore(t)
+	a := setupRemoteProject(t, srv.URL)
+	b := setupRemoteProject(t, srv.URL)
+	statePath := filepath.Join(a, ".ptsd", "state.yaml")
+
+	os.WriteFile(statePath, []byte("v1\n"), 0644)
+	if _, err := RemotePush(a); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(statePath, []byte("v2\n"), 0644)
+	if res, err := RemotePush(a); err != nil || len(res.Pushed) != 1 {
+		t.Fatalf("expected a second push without ETags to succeed, got %+v %v", res, err)
+	}
+
+	// Another clone's upload is still caught, by content.
+	os.Remove(filepath.Join(b, ".ptsd", "state.yaml"))
+	if _, err := RemotePull(b); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(b, ".ptsd", "state.yaml"), []byte("v3 from b\n"), 0644)
+	if _, err := RemotePush(b); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(statePath, []byte("v3 from a\n"), 0644)
+	if _, err := RemotePush(a); err == nil || !strings.HasPrefix(err.Error(), "err:pipeline") {
+		t.Errorf("expected conflict after a concurrent upload, got %v", err)
+	}
+}
+
+func TestRemotePullWithoutETagsKeepsLocalEdit(t *testing.T) {
+	srv := plainObjectStore(t)
+	dir := setupRemoteProject(t, srv.URL)
+	statePath := filepath.Join(dir, ".ptsd", "state.yaml")
+	os.WriteFile(statePath, []byte("v1\n"), 0644)
+	if _, err := RemotePush(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the local copy changed: nothing to pull, nothing in conflict.
+	os.WriteFile(statePath, []byte("v2 local\n"), 0644)
+	res, err := RemotePull(dir)
+	if err != nil {
+		t.Fatalf("expected no conflict for a local-only edit, got %v", err)
+	}
+	if len(res.Pulled) != 0 {
+		t.Errorf("expected nothing pulled, got %v", res.Pulled)
+	}
+	if data, _ := os.ReadFile(statePath); string(data) != "v2 local\n" {
+		t.Errorf("local edit lost: %q", data)
+	}
+	if _, err := RemotePush(dir); err != nil {
+		t.Errorf("expected the local edit to push, got %v", err)
+	}
+}
+
+func TestRemoteRefusedWithSigning(t *testing.T) {
+	srv := fakeObjectStore(t)
+	a := setupRemoteProject(t, srv.URL)
+	os.WriteFile(filepath.Join(a, ".ptsd", "state.yaml"), []byte("from a\n"), 0644)
+	if _, err := RemotePush(a); err != nil {
+		t.Fatal(err)
+	}
+
+	b := setupRemoteProject(t, srv.URL)
+	t.Setenv(signingKeyEnv, filepath.Join(t.TempDir(), "signing.key"))
+	cfgPath := filepath.Join(b, ".ptsd", "ptsd.yaml")
+	cfg, _ := os.ReadFile(cfgPath)
+	os.WriteFile(cfgPath, append(cfg, "audit:\n  sign: true\n"...), 0644)
+	if err := RecordReview(b, "auth", "prd", 5); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := RemotePull(b); err == nil || !strings.Contains(err.Error(), "err:config remote sync cannot be used with audit.sign") {
+		t.Errorf("expected signed pull refused, got %v", err)
+	}
+	if _, err := RemotePush(b); err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Errorf("expected signed push refused, got %v", err)
+	}
+	if got := verifyMessages(t, b); got != "" {
+		t.Errorf("expected signed files untouched, got:\n%s", got)
+	}
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"sort"
+	"time"
+)
+
+// Digest summarizes pipeline activity from the event log over a time window.
+type Digest struct {
+	Since          time.Time
+	Until          time.Time
+	Advanced       []Event // stage events
+	Reviews        int
+	AvgScore       float64
+	StageAvgScores map[string]float64
+	TestsAdded     int
+	Regressions    []Event
+	TasksCompleted []Event
+}
+
+// BuildDigest aggregates events recorded in [since, until).
+func BuildDigest(projectDir string, since, until time.Time) (Digest, error) {
+	events, err := LoadEvents(projectDir)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	d := Digest{Since: since, Until: until, StageAvgScores: make(map[string]float64)}
+	total := 0
+	stageTotals := make(map[string]int)
+	stageCounts := make(map[string]int)
+
+	for _, e := range EventsSince(events, since, until) {
+		switch e.Type {
+		case EventStage:
+			d.Advanced = append(d.Advanced, e)
+		case EventReview:
+			d.Reviews++
+			total += e.Score
+			stageTotals[e.Stage] += e.Score
+			stageCounts[e.Stage]++
+		case EventTest:
+			d.TestsAdded++
+		case EventRegression:
+			d.Regressions = append(d.Regressions, e)
+		case EventTask:
+			if e.Detail == "DONE" {
+				d.TasksCompleted = append(d.TasksCompleted, e)
+			}
+		}
+	}
+
+	if d.Reviews > 0 {
+		d.AvgScore = float64(total) / float64(d.Reviews)
+	}
+	for stage, n := range stageCounts {
+		d.StageAvgScores[stage] = float64(stageTotals[stage]) / float64(n)
+	}
+
+	sort.SliceStable(d.Advanced, func(i, j int) bool { return d.Advanced[i].At.Before(d.Advanced[j].At) })
+
+	return d, nil
+}
+
+// StageDuration is the time a feature spent in one pipeline stage.
+type StageDuration struct {
+	Feature  string
+	Stage    string
+	Entered  time.Time
+	Duration time.Duration
+	Current  bool // feature is still in this stage
+	Stuck    bool // current stage exceeded the stuck threshold
+	Done     bool // last stage of a finished feature, closed at its last event
+}
+
+// StageDurations derives per-stage durations from stage events. The latest stage
+// of each feature is measured up to now and flagged Stuck when older than
+// stuckAfter, unless the feature is finished (in its final stage, or
+// implemented): then it is closed at the feature's last recorded event.
+func StageDurations(projectDir string, now time.Time, stuckAfter time.Duration) ([]StageDuration, error) {
+	events, err := LoadEvents(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	byFeature := make(map[string][]Event)
+	lastEvent := make(map[string]time.Time)
+	var order []string
+	for _, e := range events {
+		if e.Feature != "" && e.At.After(lastEvent[e.Feature]) {
+			lastEvent[e.Feature] = e.At
+		}
+		if e.Type != EventStage || e.Feature == "" {
+			continue
+		}
+		if _, ok := byFeature[e.Feature]; !ok {
+			order = append(order, e.Feature)
+		}
+		byFeature[e.Feature] = append(byFeature[e.Feature], e)
+	}
+	sort.Strings(order)
+
+	implemented := make(map[string]bool)
+	if features, err := loadFeatures(projectDir); err == nil {
+		for _, f := range features {
+			implemented[f.ID] = f.Status == "implemented"
+		}
+	}
+
+	var out []StageDuration
+	for _, id := range order {
+		stages := byFeature[id]
+		sort.SliceStable(stages, func(i, j int) bool { return stages[i].At.Before(stages[j].At) })
+		last := stages[len(stages)-1].Stage
+		featureStages := FeatureStages(projectDir, id)
+		finished := implemented[id] || (len(featureStages) > 0 && last == featureStages[len(featureStages)-1])
+		for i, e := range stages {
+			d := StageDuration{Feature: id, Stage: e.Stage, Entered: e.At}
+			switch {
+			case i+1 < len(stages):
+				d.Duration = stages[i+1].At.Sub(e.At)
+			case finished:
+				d.Duration = lastEvent[id].Sub(e.At)
+				d.Done = true
+			default:
+				d.Duration = now.Sub(e.At)
+				d.Current = true
+				d.Stuck = stuckAfter > 0 && d.Duration > stuckAfter
+			}
+			out = append(out, d)
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildDigest(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:in-progress")
+	now := time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{At: now.AddDate(0, 0, -10), Type: EventReview, Feature: "auth", Stage: "prd", Score: 2},
+		{At: now.AddDate(0, 0, -3), Type: EventStage, Feature: "auth", Stage: "seed"},
+		{At: now.AddDate(0, 0, -3), Type: EventReview, Feature: "auth", Stage: "seed", Score: 8},
+		{At: now.AddDate(0, 0, -2), Type: EventReview, Feature: "billing", Stage: "seed", Score: 6},
+		{At: now.AddDate(0, 0, -2), Type: EventReview, Feature: "billing", Stage: "prd", Score: 10},
+		{At: now.AddDate(0, 0, -1), Type: EventTest, Feature: "auth", Detail: "auth_test.go"},
+		{At: now.AddDate(0, 0, -1), Type: EventRegression, Feature: "billing", Stage: "prd", Detail: "prd changed"},
+		{At: now.AddDate(0, 0, -1), Type: EventTask, Feature: "auth", Task: "T-1", Detail: "DONE"},
+		{At: now.AddDate(0, 0, -1), Type: EventTask, Feature: "auth", Task: "T-2", Detail: "WIP"},
+	}
+	for _, e := range events {
+		if err := AppendEvent(dir, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := BuildDigest(dir, now.AddDate(0, 0, -7), now)
+	if err != nil {
+		t.Fatalf("BuildDigest failed: %v", err)
+	}
+
+	if len(d.Advanced) != 1 || d.Advanced[0].Stage != "seed" {
+		t.Errorf("expected 1 stage advance to seed, got %+v", d.Advanced)
+	}
+	if d.Reviews != 3 {
+		t.Errorf("expected 3 reviews in window, got %d", d.Reviews)
+	}
+	if d.AvgScore != 8 {
+		t.Errorf("expected avg 8, got %v", d.AvgScore)
+	}
+	if d.StageAvgScores["seed"] != 7 || d.StageAvgScores["prd"] != 10 {
+		t.Errorf("unexpected per-stage averages: %v", d.StageAvgScores)
+	}
+	if d.TestsAdded != 1 {
+		t.Errorf("expected 1 test added, got %d", d.TestsAdded)
+	}
+	if len(d.Regressions) != 1 {
+		t.Errorf("expected 1 regression, got %d", len(d.Regressions))
+	}
+	if len(d.TasksCompleted) != 1 || d.TasksCompleted[0].Task != "T-1" {
+		t.Errorf("expected T-1 completed, got %+v", d.TasksCompleted)
+	}
+}
+
+func TestBuildDigestEmptyLog(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	now := time.Now()
+
+	d, err := BuildDigest(dir, now.AddDate(0, 0, -7), now)
+	if err != nil {
+		t.Fatalf("BuildDigest failed: %v", err)
+	}
+	if d.Reviews != 0 || len(d.Advanced) != 0 || d.AvgScore != 0 {
+		t.Errorf("expected empty digest, got %+v", d)
+	}
+}
+
+func TestStageDurations(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:in-progress")
+	now := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	for _, e := range []Event{
+		{At: now.Add(-20 * day), Type: EventStage, Feature: "auth", Stage: "prd"},
+		{At: now.Add(-18 * day), Type: EventStage, Feature: "auth", Stage: "seed"},
+		{At: now.Add(-15 * day), Type: EventStage, Feature: "auth", Stage: "bdd"},
+		{At: now.Add(-2 * day), Type: EventStage, Feature: "billing", Stage: "prd"},
+		{At: now.Add(-1 * day), Type: EventReview, Feature: "billing", Stage: "prd", Score: 9},
+	} {
+		if err := AppendEvent(dir, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	durations, err := StageDurations(dir, now, 7*day)
+	if err != nil {
+		t.Fatalf("StageDurations failed: %v", err)
+	}
+	if len(durations) != 4 {
+		t.Fatalf("expected 4 stage durations, got %d: %+v", len(durations), durations)
+	}
+
+	auth := durations[:3]
+	if auth[0].Stage != "prd" || auth[0].Duration != 2*day || auth[0].Current {
+		t.Errorf("unexpected auth prd duration: %+v", auth[0])
+	}
+	if auth[1].Duration != 3*day {
+		t.Errorf("expected auth seed 3d, got %v", auth[1].Duration)
+	}
+	if !auth[2].Current || !auth[2].Stuck || auth[2].Duration != 15*day {
+		t.Errorf("expected auth bdd current and stuck after 15d, got %+v", auth[2])
+	}
+
+	billing := durations[3]
+	if billing.Feature != "billing" || !billing.Current || billing.Stuck {
+		t.Errorf("expected billing prd current and not stuck, got %+v", billing)
+	}
+}
+
+func TestStageDurationsFinishedFeaturesAreNotStuck(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:implemented")
+	now := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	for _, e := range []Event{
+		{At: now.Add(-30 * day), Type: EventStage, Feature: "auth", Stage: "tests"},
+		{At: now.Add(-25 * day), Type: EventStage, Feature: "auth", Stage: "impl"},
+		{At: now.Add(-24 * day), Type: EventReview, Feature: "auth", Stage: "impl", Score: 9},
+		{At: now.Add(-20 * day), Type: EventStage, Feature: "billing", Stage: "bdd"},
+	} {
+		if err := AppendEvent(dir, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	durations, err := StageDurations(dir, now, 7*day)
+	if err != nil {
+		t.Fatalf("StageDurations failed: %v", err)
+	}
+	if len(durations) != 3 {
+		t.Fatalf("expected 3 stage durations, got %+v", durations)
+	}
+	if impl := durations[1]; impl.Stage != "impl" || !impl.Done || impl.Current || impl.Stuck || impl.Duration != day {
+		t.Errorf("expected auth impl closed at its last event after 1d, got %+v", impl)
+	}
+	if bdd := durations[2]; bdd.Feature != "billing" || !bdd.Done || bdd.Current || bdd.Stuck || bdd.Duration != 0 {
+		t.Errorf("expected implemented billing closed, got %+v", bdd)
+	}
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,7 +17,69 @@ type ReviewStatusEntry struct {
 	Tests      string
 	Review     string
 	Issues     int
-	IssuesList []string
+	IssuesList []ReviewIssue
+}
+
+// Review issue severities, most severe first. A stage scored below
+// review.min_score is a blocker; reviewer issues default to major.
+const (
+	SeverityBlocker = "blocker"
+	SeverityMajor   = "major"
+	SeverityMinor   = "minor"
+)
+
+// ReviewSeverities lists the valid issue severities, most severe first.
+var ReviewSeverities = []string{SeverityBlocker, SeverityMajor, SeverityMinor}
+
+// ReviewIssue is one problem a review found, listed in review-status.yaml
+// until the next review of the feature.
+type ReviewIssue struct {
+	Severity string
+	Text     string
+}
+
+// ParseReviewIssue reads "severity: text" (`--issue "minor: typo in AC-2"`).
+// Text without a severity prefix is a major issue.
+func ParseReviewIssue(s string) ReviewIssue {
+	if sev, text, ok := strings.Cut(s, ":"); ok && containsString(ReviewSeverities, strings.ToLower(strings.TrimSpace(sev))) {
+		return ReviewIssue{Severity: strings.ToLower(strings.TrimSpace(sev)), Text: strings.TrimSpace(text)}
+	}
+	return ReviewIssue{Severity: SeverityMajor, Text: strings.TrimSpace(s)}
+}
+
+// LoadReviewIssues reads a JSON file of review issues: an array whose items
+// are {"severity": ..., "text": ...} objects or "severity: text" strings.
+func LoadReviewIssues(path string) ([]ReviewIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("err:user cannot read issues file: %v", err)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("err:user invalid issues file %s: want a JSON array: %v", path, err)
+	}
+	var issues []ReviewIssue
+	for i, raw := range items {
+		var text string
+		if json.Unmarshal(raw, &text) == nil {
+			issues = append(issues, ParseReviewIssue(text))
+			continue
+		}
+		var obj struct {
+			Severity string `json:"severity"`
+			Text     string `json:"text"`
+		}
+		if err := json.Unmarshal(raw, &obj); err != nil || obj.Text == "" {
+			return nil, fmt.Errorf("err:user invalid issue %d in %s: want a string or {\"severity\", \"text\"}", i+1, path)
+		}
+		issues = append(issues, ReviewIssue{Severity: strings.ToLower(obj.Severity), Text: obj.Text})
+	}
+	return issues, nil
+}
+
+// cleanIssueText folds an issue onto one line that can be double-quoted in YAML.
+func cleanIssueText(text string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(text, "\"", "'")), " ")
 }
 
 func loadReviewStatus(projectDir string) (map[string]ReviewStatusEntry, error) {
@@ -80,21 +143,30 @@ func parseReviewStatus(content string) map[string]ReviewStatusEntry {
 			continue
 		}
 
-		if indent == 6 && inIssuesList && strings.HasPrefix(trimmed, "- ") {
-			e := entries[currentFeature]
+		if !inIssuesList {
+			continue
+		}
+		e := entries[currentFeature]
+		if indent == 6 && strings.HasPrefix(trimmed, "- ") {
 			item := strings.TrimPrefix(trimmed, "- ")
-			item = strings.Trim(item, "\"")
-			e.IssuesList = append(e.IssuesList, item)
-			entries[currentFeature] = e
+			if sev, ok := strings.CutPrefix(item, "severity: "); ok {
+				e.IssuesList = append(e.IssuesList, ReviewIssue{Severity: sev})
+			} else {
+				// Plain string items predate severities.
+				e.IssuesList = append(e.IssuesList, ReviewIssue{Severity: SeverityMajor, Text: strings.Trim(item, "\"")})
+			}
+		} else if indent == 8 && len(e.IssuesList) > 0 {
+			if text, ok := strings.CutPrefix(trimmed, "text: "); ok {
+				e.IssuesList[len(e.IssuesList)-1].Text = strings.Trim(text, "\"")
+			}
 		}
+		entries[currentFeature] = e
 	}
 
 	return entries
 }
 
 func saveReviewStatus(projectDir string, entries map[string]ReviewStatusEntry) error {
-	rsPath := filepath.Join(projectDir, ".ptsd", "review-status.yaml")
-
 	var b strings.Builder
 	b.WriteString("features:\n")
 
@@ -114,30 +186,91 @@ func saveReviewStatus(projectDir string, entries map[string]ReviewStatusEntry) e
 		if e.Issues > 0 && len(e.IssuesList) > 0 {
 			b.WriteString("    issues_list:\n")
 			for _, issue := range e.IssuesList {
-				b.WriteString("      - \"" + issue + "\"\n")
+				b.WriteString("      - severity: " + issue.Severity + "\n")
+				b.WriteString("        text: \"" + issue.Text + "\"\n")
 			}
 		}
 	}
 
-	return os.WriteFile(rsPath, []byte(b.String()), 0644)
+	return signFileWrite(projectDir, "review-status.yaml", []byte(b.String()))
 }
 
-var validReviewStages = map[string]bool{
-	"prd":   true,
-	"seed":  true,
-	"bdd":   true,
-	"tests": true,
-	"impl":  true,
+// StageScore is one stage's review score.
+type StageScore struct {
+	Stage string
+	Score int
+}
+
+// ReviewMeta is what a reviewer adds to a score: who reviewed (LeaseOwner
+// when empty), and the issues found, which are listed in review-status.yaml.
+type ReviewMeta struct {
+	Reviewer string
+	Issues   []ReviewIssue
 }
 
 func RecordReview(projectDir string, featureID string, stage string, score int) error {
-	if score < 0 || score > 10 {
-		return fmt.Errorf("err:user score must be 0-10, got %d", score)
+	return RecordReviews(projectDir, featureID, []StageScore{{Stage: stage, Score: score}})
+}
+
+// RecordReviews records several stage scores for one feature; see
+// RecordReviewsWithMeta.
+func RecordReviews(projectDir string, featureID string, scores []StageScore) error {
+	return RecordReviewsWithMeta(projectDir, featureID, scores, ReviewMeta{})
+}
+
+// RecordReviewsWithMeta records several stage scores for one feature with a
+// single write of state.yaml and review-status.yaml. Every score is validated
+// before anything is written. Each score replaces the reviewer's previous one
+// for the stage, and every stage's gate is re-evaluated against the review
+// policy (ReviewGate). The feature's review passes only if every gate does;
+// each failing stage becomes a blocker issue (and, with auto_redo, a redo
+// task), and stages still short of review.require reviewers leave it pending.
+// The reviewer's own issues, with their severities, are listed after those.
+// Every score is also appended to the review history (review-log.yaml).
+func RecordReviewsWithMeta(projectDir string, featureID string, scores []StageScore, meta ReviewMeta) error {
+	if len(scores) == 0 {
+		return fmt.Errorf("err:user no scores given")
+	}
+	stages := FeatureStages(projectDir, featureID)
+	seen := make(map[string]bool)
+	for _, sc := range scores {
+		if sc.Score < 0 || sc.Score > 10 {
+			return fmt.Errorf("err:user score must be 0-10, got %d", sc.Score)
+		}
+		if stageRank(stages, sc.Stage) < 0 {
+			return fmt.Errorf("err:user invalid stage %q: must be %s", sc.Stage, strings.Join(stages, "|"))
+		}
+		if seen[sc.Stage] {
+			return fmt.Errorf("err:user stage %s scored twice", sc.Stage)
+		}
+		seen[sc.Stage] = true
+	}
+	var reviewerIssues []ReviewIssue
+	for _, issue := range meta.Issues {
+		if issue.Severity == "" {
+			issue.Severity = SeverityMajor
+		}
+		if !containsString(ReviewSeverities, issue.Severity) {
+			return fmt.Errorf("err:user invalid issue severity %q: must be %s", issue.Severity, strings.Join(ReviewSeverities, "|"))
+		}
+		// One quoted line each in review-status.yaml.
+		if issue.Text = cleanIssueText(issue.Text); issue.Text != "" {
+			reviewerIssues = append(reviewerIssues, issue)
+		}
 	}
+	// Pipeline order, so stage events read as the feature advancing.
+	scores = append([]StageScore(nil), scores...)
+	sort.SliceStable(scores, func(i, j int) bool {
+		return stageRank(stages, scores[i].Stage) < stageRank(stages, scores[j].Stage)
+	})
 
-	if !validReviewStages[stage] {
-		return fmt.Errorf("err:user invalid stage %q: must be prd|seed|bdd|tests|impl", stage)
+	cfg, err := LoadConfig(projectDir)
+	if err != nil {
+		// No config means default min_score=7
+		cfg = &Config{Review: ReviewConfig{MinScore: 7}}
 	}
+	cfg = cfg.ForFeature(featureID)
+	reviewer := reviewerName(meta.Reviewer)
 
 	state, err := LoadState(projectDir)
 	if err != nil {
@@ -155,14 +288,25 @@ func RecordReview(projectDir string, featureID string, stage string, score int)
 		fs.Scores = make(map[string]ScoreEntry)
 	}
 
-	fs.Scores[stage] = ScoreEntry{
-		Value:     score,
-		Timestamp: time.Now(),
-	}
-
-	// Advance stage in state.yaml (advance-only, never regress)
-	if stageOrder[stage] > stageOrder[fs.Stage] {
-		fs.Stage = stage
+	now := time.Now()
+	var advancedTo []string
+	gates := make(map[string]ReviewGateResult)
+	for _, sc := range scores {
+		reviewers := map[string]int{reviewer: sc.Score}
+		for name, score := range fs.Scores[sc.Stage].Reviewers {
+			if name != reviewer {
+				reviewers[name] = score
+			}
+		}
+		entry := ScoreEntry{Value: sc.Score, Timestamp: now, Reviewers: reviewers}
+		fs.Scores[sc.Stage] = entry
+		gates[sc.Stage] = evaluateReviewGate(entry, true, cfg.Review)
+
+		// Advance stage in state.yaml (advance-only, never regress)
+		if stageRank(stages, sc.Stage) > stageRank(stages, fs.Stage) {
+			fs.Stage = sc.Stage
+			advancedTo = append(advancedTo, sc.Stage)
+		}
 	}
 
 	state.Features[featureID] = fs
@@ -170,14 +314,25 @@ func RecordReview(projectDir string, featureID string, stage string, score int)
 	if err := writeState(projectDir, state); err != nil {
 		return err
 	}
+	if seen["bdd"] {
+		// Baseline for `ptsd bdd diff`.
+		if err := storeReviewedBDD(projectDir, featureID); err != nil {
+			return err
+		}
+	}
 
-	// Update review-status.yaml
-	cfg, err := LoadConfig(projectDir)
-	if err != nil {
-		// No config means default min_score=7
-		cfg = &Config{Review: ReviewConfig{MinScore: 7}}
+	detail := ""
+	if meta.Reviewer != "" {
+		detail = "reviewer=" + meta.Reviewer
+	}
+	for _, sc := range scores {
+		recordEvent(projectDir, Event{Type: EventReview, Feature: featureID, Stage: sc.Stage, Score: sc.Score, Detail: detail})
+	}
+	for _, stage := range advancedTo {
+		recordEvent(projectDir, Event{Type: EventStage, Feature: featureID, Stage: stage})
 	}
 
+	// Update review-status.yaml
 	rs, err := loadReviewStatus(projectDir)
 	if err != nil {
 		return fmt.Errorf("err:io failed to load review-status: %w", err)
@@ -186,31 +341,75 @@ func RecordReview(projectDir string, featureID string, stage string, score int)
 	entry, ok := rs[featureID]
 	if !ok {
 		entry = ReviewStatusEntry{
-			Stage:  stage,
+			Stage:  scores[0].Stage,
 			Tests:  "absent",
 			Review: "pending",
 		}
 	}
 
-	if score >= cfg.Review.MinScore {
-		entry.Review = "passed"
-		entry.Issues = 0
-		entry.IssuesList = nil
-	} else {
+	var failed []StageScore
+	pending := false
+	for _, sc := range scores {
+		switch gate := gates[sc.Stage]; {
+		case gate.Failed():
+			failed = append(failed, StageScore{Stage: sc.Stage, Score: gate.Score})
+		case !gate.Passed:
+			pending = true
+		}
+	}
+	entry.Review = "passed"
+	if pending {
+		entry.Review = "pending"
+	}
+	entry.IssuesList = nil
+	if len(failed) > 0 {
 		entry.Review = "failed"
-		entry.Issues = 1
-		entry.IssuesList = []string{fmt.Sprintf("score %d below min %d at %s stage", score, cfg.Review.MinScore, stage)}
+		for _, sc := range failed {
+			entry.IssuesList = append(entry.IssuesList, ReviewIssue{Severity: SeverityBlocker,
+				Text: fmt.Sprintf("score %d below min %d at %s stage", sc.Score, cfg.Review.MinScore, sc.Stage)})
+		}
 	}
+	entry.IssuesList = append(entry.IssuesList, reviewerIssues...)
+	entry.Issues = len(entry.IssuesList)
 
 	rs[featureID] = entry
 	if err := saveReviewStatus(projectDir, rs); err != nil {
 		return fmt.Errorf("err:io failed to save review-status: %w", err)
 	}
 
+	records := make([]ReviewRecord, 0, len(scores))
+	for _, sc := range scores {
+		verdict := "passed"
+		if sc.Score < cfg.Review.MinScore {
+			verdict = "failed"
+		}
+		records = append(records, ReviewRecord{At: now, Feature: featureID, Stage: sc.Stage, Score: sc.Score,
+			MinScore: cfg.Review.MinScore, Verdict: verdict, Reviewer: reviewer, Issues: reviewerIssues})
+	}
+	if err := appendReviewLog(projectDir, records); err != nil {
+		return err
+	}
+
+	if cfg.Review.GitNotes {
+		for _, sc := range scores {
+			verdict := "passed"
+			if sc.Score < cfg.Review.MinScore {
+				verdict = "failed"
+			}
+			// Best effort: an unborn HEAD or missing git must not block the review.
+			_ = appendReviewNote(projectDir, ReviewNote{
+				Feature: featureID,
+				Stage:   sc.Stage,
+				Score:   sc.Score,
+				Verdict: verdict,
+				At:      now,
+			})
+		}
+	}
+
 	// Auto-redo check
 
-	if cfg.Review.AutoRedo && score < cfg.Review.MinScore {
-		title := fmt.Sprintf("redo %s for %s", stage, featureID)
+	if cfg.Review.AutoRedo && len(failed) > 0 {
 		tasks, _ := loadTasks(projectDir)
 
 		maxNum := 0
@@ -223,14 +422,16 @@ func RecordReview(projectDir string, featureID string, stage string, score int)
 				}
 			}
 		}
-		redoTask := Task{
-			ID:       fmt.Sprintf("T-%d", maxNum+1),
-			Feature:  featureID,
-			Title:    title,
-			Status:   "TODO",
-			Priority: "A",
+		for _, sc := range failed {
+			maxNum++
+			tasks = append(tasks, Task{
+				ID:       fmt.Sprintf("T-%d", maxNum),
+				Feature:  featureID,
+				Title:    fmt.Sprintf("redo %s for %s", sc.Stage, featureID),
+				Status:   "TODO",
+				Priority: "A",
+			})
 		}
-		tasks = append(tasks, redoTask)
 
 		if err := saveTasks(projectDir, tasks); err != nil {
 			return fmt.Errorf("err:io failed to save redo task: %w", err)
@@ -240,27 +441,97 @@ func RecordReview(projectDir string, featureID string, stage string, score int)
 	return nil
 }
 
-func CheckReviewGate(projectDir string, featureID string, stage string) (bool, error) {
+// ReviewGateResult is a stage's review gate under the review policy.
+type ReviewGateResult struct {
+	Score     int // consensus score of Reviewers under Strategy
+	Reviewers int // distinct reviewers who scored the stage
+	Require   int
+	MinScore  int
+	Strategy  string
+	Passed    bool
+}
+
+// Failed reports a gate that more reviews cannot pass: the consensus is
+// below min_score once enough reviewers scored, or with the min strategy
+// as soon as any reviewer scored below it.
+func (g ReviewGateResult) Failed() bool {
+	if g.Reviewers == 0 || g.Score >= g.MinScore {
+		return false
+	}
+	return g.Reviewers >= g.Require || g.Strategy == ReviewStrategyMin
+}
+
+// Verdict is pass, fail, or pending while the stage waits for reviewers.
+func (g ReviewGateResult) Verdict() string {
+	switch {
+	case g.Passed:
+		return "pass"
+	case g.Failed():
+		return "fail"
+	case g.Reviewers < g.Require:
+		return "pending"
+	}
+	return "fail"
+}
+
+// ReviewGate evaluates a stage's gate: review.require distinct reviewers
+// must have scored it, and their scores combined by review.strategy must
+// meet review.min_score.
+func ReviewGate(projectDir string, featureID string, stage string) (ReviewGateResult, error) {
 	cfg, err := LoadConfig(projectDir)
 	if err != nil {
 		// No config means default min_score=7
 		cfg = &Config{Review: ReviewConfig{MinScore: 7}}
 	}
+	cfg = cfg.ForFeature(featureID)
 
 	state, err := LoadState(projectDir)
 	if err != nil {
-		return false, err
+		return ReviewGateResult{}, err
 	}
 
-	fs, ok := state.Features[featureID]
-	if !ok {
-		return false, nil
-	}
+	entry, ok := state.Features[featureID].Scores[stage]
+	return evaluateReviewGate(entry, ok, cfg.Review), nil
+}
 
-	score, ok := fs.Scores[stage]
-	if !ok {
-		return false, nil
+func CheckReviewGate(projectDir string, featureID string, stage string) (bool, error) {
+	gate, err := ReviewGate(projectDir, featureID, stage)
+	return gate.Passed, err
+}
+
+// evaluateReviewGate applies the review policy to one stage's scores. A
+// score recorded before reviewers were tracked counts as one reviewer.
+func evaluateReviewGate(entry ScoreEntry, scored bool, review ReviewConfig) ReviewGateResult {
+	g := ReviewGateResult{Require: max(review.Require, 1), MinScore: review.MinScore, Strategy: review.Strategy}
+	if g.Strategy == "" {
+		g.Strategy = ReviewStrategyLatest
 	}
+	if !scored {
+		return g
+	}
+	g.Score = entry.Value
+	g.Reviewers = max(len(entry.Reviewers), 1)
+	if len(entry.Reviewers) > 0 && g.Strategy != ReviewStrategyLatest {
+		total, lowest := 0, 10
+		for _, score := range entry.Reviewers {
+			total += score
+			lowest = min(lowest, score)
+		}
+		g.Score = lowest
+		if g.Strategy == ReviewStrategyAvg {
+			g.Score = total / len(entry.Reviewers)
+		}
+	}
+	g.Passed = g.Reviewers >= g.Require && g.Score >= g.MinScore
+	return g
+}
 
-	return score.Value >= cfg.Review.MinScore, nil
+// reviewerName is the name a review is filed under in state.yaml: the given
+// reviewer, or LeaseOwner when none was given.
+func reviewerName(name string) string {
+	name = strings.ReplaceAll(strings.TrimSpace(name), "\"", "'")
+	if name == "" {
+		return LeaseOwner()
+	}
+	return name
 }
@@ -3,6 +3,7 @@ package core
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -348,3 +349,174 @@ func TestAutoRedoTaskOnLowScore(t *testing.T) {
 		t.Errorf("tasks.yaml should contain redo/retry/rework task, got:\n%s", content)
 	}
 }
+
+func TestRecordReviewsBulk(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	os.WriteFile(filepath.Join(ptsdDir, "ptsd.yaml"), []byte("review:\n  min_score: 7\n  auto_redo: true\n"), 0644)
+	os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte("features:\n  user-auth:\n    stage: prd\n    hashes: {}\n    scores: {}\n"), 0644)
+	os.WriteFile(filepath.Join(ptsdDir, "tasks.yaml"), []byte("tasks: []\n"), 0644)
+
+	// Invalid input writes nothing.
+	if err := RecordReviews(dir, "user-auth", []StageScore{{"prd", 8}, {"nope", 8}}); err == nil {
+		t.Fatal("expected error for unknown stage")
+	}
+	if err := RecordReviews(dir, "user-auth", []StageScore{{"prd", 8}, {"prd", 9}}); err == nil {
+		t.Fatal("expected error for a stage scored twice")
+	}
+	if state, _ := LoadState(dir); len(state.Features["user-auth"].Scores) != 0 {
+		t.Fatal("rejected input must not record any score")
+	}
+
+	if err := RecordReviews(dir, "user-auth", []StageScore{{"bdd", 5}, {"prd", 8}, {"seed", 6}}); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := state.Features["user-auth"]
+	if fs.Stage != "bdd" || fs.Scores["prd"].Value != 8 || fs.Scores["seed"].Value != 6 || fs.Scores["bdd"].Value != 5 {
+		t.Errorf("state = %+v", fs)
+	}
+
+	rs, _ := loadReviewStatus(dir)
+	if e := rs["user-auth"]; e.Review != "failed" || e.Issues != 2 {
+		t.Errorf("review status = %+v, want failed with 2 issues", e)
+	}
+
+	tasks, _ := loadTasks(dir)
+	if len(tasks) != 2 || tasks[0].Title != "redo seed for user-auth" || tasks[1].ID != "T-2" {
+		t.Errorf("redo tasks = %+v", tasks)
+	}
+}
+
+func TestRecordReviewHonorsFeatureMinScore(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "payments:planned", "docs:planned")
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	os.WriteFile(filepath.Join(ptsdDir, "ptsd.yaml"), []byte("review:\n  min_score: 7\nfeatures_config:\n  payments:\n    min_score: 9\n"), 0644)
+	os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte("features: {}\n"), 0644)
+
+	if err := RecordReview(dir, "payments", "prd", 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordReview(dir, "docs", "prd", 8); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := loadReviewStatus(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs["payments"].Review != "failed" || len(rs["payments"].IssuesList) != 1 || !strings.Contains(rs["payments"].IssuesList[0].Text, "below min 9") {
+		t.Errorf("payments = %+v, want failed against min 9", rs["payments"])
+	}
+	if rs["docs"].Review != "passed" {
+		t.Errorf("docs = %+v, want passed against the project min 7", rs["docs"])
+	}
+	if ok, _ := CheckReviewGate(dir, "payments", "prd"); ok {
+		t.Error("review gate must use the feature's min_score")
+	}
+}
+
+func TestReviewGateConsensus(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("review:\n  min_score: 7\n  require: 2\n  strategy: avg\n"), 0644)
+
+	if err := RecordReviewsWithMeta(dir, "auth", []StageScore{{"prd", 9}}, ReviewMeta{Reviewer: "claude-opus"}); err != nil {
+		t.Fatal(err)
+	}
+	gate, _ := ReviewGate(dir, "auth", "prd")
+	if gate.Passed || gate.Verdict() != "pending" || gate.Reviewers != 1 || gate.Require != 2 {
+		t.Errorf("one of two reviewers: %+v", gate)
+	}
+	if rs, _ := loadReviewStatus(dir); rs["auth"].Review != "pending" {
+		t.Errorf("review status = %q, want pending", rs["auth"].Review)
+	}
+
+	if err := RecordReviewsWithMeta(dir, "auth", []StageScore{{"prd", 6}}, ReviewMeta{Reviewer: "human"}); err != nil {
+		t.Fatal(err)
+	}
+	gate, _ = ReviewGate(dir, "auth", "prd")
+	if !gate.Passed || gate.Score != 7 || gate.Reviewers != 2 {
+		t.Errorf("avg of 9 and 6 should pass at 7: %+v", gate)
+	}
+	state, _ := LoadState(dir)
+	if got := state.Features["auth"].Scores["prd"]; got.Value != 6 || !reflect.DeepEqual(got.Reviewers, map[string]int{"claude-opus": 9, "human": 6}) {
+		t.Errorf("state score = %+v", got)
+	}
+
+	// A reviewer's new score replaces their old one.
+	if err := RecordReviewsWithMeta(dir, "auth", []StageScore{{"prd", 3}}, ReviewMeta{Reviewer: "human"}); err != nil {
+		t.Fatal(err)
+	}
+	if gate, _ = ReviewGate(dir, "auth", "prd"); !gate.Failed() || gate.Score != 6 {
+		t.Errorf("avg of 9 and 3: %+v", gate)
+	}
+	if rs, _ := loadReviewStatus(dir); rs["auth"].Review != "failed" || rs["auth"].IssuesList[0].Text != "score 6 below min 7 at prd stage" {
+		t.Errorf("review status = %+v", rs["auth"])
+	}
+
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("review:\n  require: 3\n  strategy: min\n"), 0644)
+	if gate, _ = ReviewGate(dir, "auth", "prd"); gate.Verdict() != "fail" || gate.Score != 3 {
+		t.Errorf("min strategy fails on any low score before quorum: %+v", gate)
+	}
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("review:\n  strategy: majority\n"), 0644)
+	if _, err := LoadConfig(dir); err == nil || !strings.HasPrefix(err.Error(), "err:config invalid review.strategy") {
+		t.Errorf("expected err:config for an unknown strategy, got %v", err)
+	}
+}
+
+func TestRecordReviewIssuesWithSeverities(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+
+	issues := []ReviewIssue{ParseReviewIssue("minor: typo in AC-2"), ParseReviewIssue("no \"expired token\" scenario")}
+	if err := RecordReviewsWithMeta(dir, "auth", []StageScore{{"prd", 5}}, ReviewMeta{Issues: issues}); err != nil {
+		t.Fatal(err)
+	}
+	rs, _ := loadReviewStatus(dir)
+	want := []ReviewIssue{
+		{SeverityBlocker, "score 5 below min 7 at prd stage"},
+		{SeverityMinor, "typo in AC-2"},
+		{SeverityMajor, "no 'expired token' scenario"},
+	}
+	if e := rs["auth"]; e.Issues != 3 || !reflect.DeepEqual(e.IssuesList, want) {
+		t.Errorf("issues_list = %+v, want %+v", e.IssuesList, want)
+	}
+
+	err := RecordReviewsWithMeta(dir, "auth", []StageScore{{"prd", 8}}, ReviewMeta{Issues: []ReviewIssue{{"urgent", "x"}}})
+	if err == nil || !strings.HasPrefix(err.Error(), "err:user invalid issue severity") {
+		t.Errorf("expected err:user for an unknown severity, got %v", err)
+	}
+	if err := RecordReview(dir, "auth", "prd", 8); err != nil {
+		t.Fatal(err)
+	}
+	if rs, _ := loadReviewStatus(dir); len(rs["auth"].IssuesList) != 0 {
+		t.Errorf("a passing review should close the open issues: %+v", rs["auth"])
+	}
+}
+
+func TestParseReviewStatusReadsPlainIssueStrings(t *testing.T) {
+	rs := parseReviewStatus("features:\n  auth:\n    stage: seed\n    review: failed\n    issues: 1\n    issues_list:\n      - \"low quality\"\n")
+	if got := rs["auth"].IssuesList; len(got) != 1 || got[0] != (ReviewIssue{SeverityMajor, "low quality"}) {
+		t.Errorf("issues_list = %+v", got)
+	}
+}
+
+func TestLoadReviewIssues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.json")
+	os.WriteFile(path, []byte(`[{"severity": "Blocker", "text": "no error cases"}, "minor: naming", "vague AC-3"]`), 0644)
+	issues, err := LoadReviewIssues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ReviewIssue{{SeverityBlocker, "no error cases"}, {SeverityMinor, "naming"}, {SeverityMajor, "vague AC-3"}}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("issues = %+v, want %+v", issues, want)
+	}
+
+	os.WriteFile(path, []byte(`{"text": "not an array"}`), 0644)
+	if _, err := LoadReviewIssues(path); err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Errorf("expected err:user, got %v", err)
+	}
+}
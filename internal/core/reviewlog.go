@@ -0,0 +1,153 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Review history. state.yaml keeps only the latest score per stage, so every
+// recorded review is also appended to .ptsd/review-log.yaml with its
+// reviewer, verdict and issues: the audit trail of how a feature got
+// through the gate. Entries are never rewritten; `ptsd review history`
+// prints them.
+
+const reviewLogFile = "review-log.yaml"
+
+// ReviewRecord is one stage score as it was recorded.
+type ReviewRecord struct {
+	At       time.Time
+	Feature  string
+	Stage    string
+	Score    int
+	MinScore int
+	Verdict  string // passed or failed
+	Reviewer string
+	Issues   []ReviewIssue
+}
+
+func reviewLogPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", reviewLogFile)
+}
+
+// appendReviewLog adds records to review-log.yaml, signed like state.yaml
+// when audit.sign is on.
+func appendReviewLog(projectDir string, records []ReviewRecord) error {
+	existing, err := os.ReadFile(reviewLogPath(projectDir))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("err:io %w", err)
+	}
+	var b strings.Builder
+	if len(existing) == 0 {
+		b.WriteString("reviews:\n")
+	} else {
+		b.Write(existing)
+	}
+	for _, r := range records {
+		b.WriteString("  - at: \"" + r.At.UTC().Format(time.RFC3339Nano) + "\"\n")
+		b.WriteString("    feature: " + r.Feature + "\n")
+		b.WriteString("    stage: " + r.Stage + "\n")
+		b.WriteString("    score: " + strconv.Itoa(r.Score) + "\n")
+		b.WriteString("    min_score: " + strconv.Itoa(r.MinScore) + "\n")
+		b.WriteString("    verdict: " + r.Verdict + "\n")
+		if r.Reviewer != "" {
+			b.WriteString("    reviewer: \"" + strings.ReplaceAll(r.Reviewer, "\"", "'") + "\"\n")
+		}
+		if len(r.Issues) > 0 {
+			b.WriteString("    issues:\n")
+			for _, issue := range r.Issues {
+				b.WriteString("      - severity: " + issue.Severity + "\n")
+				b.WriteString("        text: \"" + issue.Text + "\"\n")
+			}
+		}
+	}
+	if err := signFileWrite(projectDir, reviewLogFile, []byte(b.String())); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// ReviewHistory returns featureID's recorded reviews, oldest first, limited
+// to one stage when stage is set. A missing log means no history.
+func ReviewHistory(projectDir, featureID, stage string) ([]ReviewRecord, error) {
+	data, err := os.ReadFile(reviewLogPath(projectDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	var out []ReviewRecord
+	for _, r := range parseReviewLog(string(data)) {
+		if r.Feature == featureID && (stage == "" || r.Stage == stage) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func parseReviewLog(content string) []ReviewRecord {
+	var records []ReviewRecord
+	var cur *ReviewRecord
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "reviews:" || trimmed == "issues:" {
+			continue
+		}
+		if strings.HasPrefix(line, "      - ") {
+			if cur != nil {
+				item := strings.TrimPrefix(trimmed, "- ")
+				if sev, ok := strings.CutPrefix(item, "severity: "); ok {
+					cur.Issues = append(cur.Issues, ReviewIssue{Severity: sev})
+				} else {
+					cur.Issues = append(cur.Issues, ReviewIssue{Severity: SeverityMajor, Text: stripQuotes(item)})
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "        text: ") {
+			if cur != nil && len(cur.Issues) > 0 {
+				cur.Issues[len(cur.Issues)-1].Text = stripQuotes(strings.TrimPrefix(trimmed, "text: "))
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") {
+			if cur != nil {
+				records = append(records, *cur)
+			}
+			cur = &ReviewRecord{}
+			trimmed = strings.TrimPrefix(trimmed, "- ")
+		}
+		if cur == nil {
+			continue
+		}
+		key, val, ok := strings.Cut(trimmed, ": ")
+		if !ok {
+			continue
+		}
+		val = stripQuotes(val)
+		switch key {
+		case "at":
+			cur.At, _ = time.Parse(time.RFC3339Nano, val)
+		case "feature":
+			cur.Feature = val
+		case "stage":
+			cur.Stage = val
+		case "score":
+			cur.Score, _ = strconv.Atoi(val)
+		case "min_score":
+			cur.MinScore, _ = strconv.Atoi(val)
+		case "verdict":
+			cur.Verdict = val
+		case "reviewer":
+			cur.Reviewer = val
+		}
+	}
+	if cur != nil {
+		records = append(records, *cur)
+	}
+	return records
+}
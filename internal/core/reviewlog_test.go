@@ -0,0 +1,64 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReviewHistoryKeepsEveryScore(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:planned")
+	t.Setenv("PTSD_AGENT", "agent-1")
+
+	if err := RecordReview(dir, "auth", "prd", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordReviewsWithMeta(dir, "auth", []StageScore{{Stage: "prd", Score: 8}, {Stage: "seed", Score: 6}},
+		ReviewMeta{Reviewer: "alice", Issues: []ReviewIssue{{Severity: SeverityMinor, Text: "seed lacks the \"expired\" case"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordReview(dir, "billing", "prd", 9); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := ReviewHistory(dir, "auth", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 auth reviews, got %+v", history)
+	}
+	first, second, third := history[0], history[1], history[2]
+	if first.Stage != "prd" || first.Score != 5 || first.Verdict != "failed" || first.MinScore != 7 || first.Reviewer != "agent-1" || first.At.IsZero() {
+		t.Errorf("unexpected first review: %+v", first)
+	}
+	if second.Score != 8 || second.Verdict != "passed" || second.Reviewer != "alice" {
+		t.Errorf("unexpected second review: %+v", second)
+	}
+	if third.Stage != "seed" || len(third.Issues) != 1 || third.Issues[0] != (ReviewIssue{SeverityMinor, "seed lacks the 'expired' case"}) {
+		t.Errorf("unexpected third review: %+v", third)
+	}
+
+	prd, _ := ReviewHistory(dir, "auth", "prd")
+	if len(prd) != 2 {
+		t.Errorf("expected 2 prd reviews, got %d", len(prd))
+	}
+	if none, err := ReviewHistory(setupProjectWithFeatures(t), "auth", ""); err != nil || len(none) != 0 {
+		t.Errorf("expected no history without a log, got %v, %v", none, err)
+	}
+}
+
+func TestReviewLogIsProtectedFromDirectEdits(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	if err := RecordReview(dir, "auth", "prd", 8); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "review-log.yaml"))
+	if !strings.HasPrefix(string(data), "reviews:\n  - at: ") {
+		t.Errorf("unexpected review-log.yaml:\n%s", data)
+	}
+	if r := GateCheck(dir, filepath.Join(dir, ".ptsd", "review-log.yaml")); r.Allowed {
+		t.Error("direct edits to review-log.yaml should be blocked")
+	}
+}
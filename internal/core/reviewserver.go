@@ -0,0 +1,144 @@
+package core
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReviewSubmission is the JSON body an external reviewer POSTs to
+// `ptsd review serve`.
+type ReviewSubmission struct {
+	Feature string `json:"feature"`
+	Stage   string `json:"stage"`
+	Score   int    `json:"score"`
+	// Issues are "severity: text" or plain text (major).
+	Issues   []string `json:"issues,omitempty"`
+	Reviewer string   `json:"reviewer,omitempty"`
+}
+
+// ReviewReceipt is the response to an accepted submission. The verdict is
+// the stage's review gate (see ReviewGate), as `ptsd review` reports it, not
+// this one score.
+type ReviewReceipt struct {
+	Feature   string `json:"feature"`
+	Stage     string `json:"stage"`
+	Score     int    `json:"score"`
+	Verdict   string `json:"verdict"` // pass | fail | pending
+	Consensus int    `json:"consensus"`
+	Reviewers int    `json:"reviewers"`
+	Require   int    `json:"require"`
+}
+
+// reviewBodyLimit caps the size of a submission body.
+const reviewBodyLimit = 1 << 20
+
+// ReviewHandler returns the HTTP handler behind `ptsd review serve`. It
+// accepts POST /review with a ReviewSubmission, authenticated by
+// "Authorization: Bearer <token>", and records it with RecordReviewsWithMeta.
+// Submissions are recorded one at a time. onRecord, if set, is called after
+// each recorded review.
+func ReviewHandler(projectDir, token string, onRecord func(ReviewSubmission, ReviewReceipt)) http.Handler {
+	var mu sync.Mutex
+	mux := http.NewServeMux()
+	mux.HandleFunc("/review", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			reviewHTTPError(w, http.StatusMethodNotAllowed, "err:user use POST")
+			return
+		}
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			reviewHTTPError(w, http.StatusUnauthorized, "err:user invalid or missing bearer token")
+			return
+		}
+
+		var sub ReviewSubmission
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, reviewBodyLimit))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&sub); err != nil {
+			reviewHTTPError(w, http.StatusBadRequest, "err:user bad review JSON: "+err.Error())
+			return
+		}
+		if sub.Feature == "" || sub.Stage == "" {
+			reviewHTTPError(w, http.StatusBadRequest, "err:user feature and stage are required")
+			return
+		}
+
+		var issues []ReviewIssue
+		for _, issue := range sub.Issues {
+			issues = append(issues, ParseReviewIssue(issue))
+		}
+		mu.Lock()
+		var gate ReviewGateResult
+		unlock, err := LockProject(projectDir)
+		if err == nil {
+			err = RecordReviewsWithMeta(projectDir, sub.Feature, []StageScore{{Stage: sub.Stage, Score: sub.Score}},
+				ReviewMeta{Reviewer: sub.Reviewer, Issues: issues})
+			if err == nil {
+				gate, err = ReviewGate(projectDir, sub.Feature, sub.Stage)
+			}
+			unlock()
+		}
+		mu.Unlock()
+		if err != nil {
+			status := http.StatusInternalServerError
+			if strings.HasPrefix(err.Error(), "err:user") {
+				status = http.StatusBadRequest
+			}
+			reviewHTTPError(w, status, err.Error())
+			return
+		}
+
+		receipt := ReviewReceipt{Feature: sub.Feature, Stage: sub.Stage, Score: sub.Score, Verdict: gate.Verdict(),
+			Consensus: gate.Score, Reviewers: gate.Reviewers, Require: gate.Require}
+		if onRecord != nil {
+			onRecord(sub, receipt)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(receipt)
+	})
+	return mux
+}
+
+func reviewHTTPError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+// ServeReviews listens on addr and serves ReviewHandler until stop is closed.
+// An empty token is refused: the receiver writes review state, so it never
+// runs unauthenticated.
+func ServeReviews(projectDir, addr, token string, onRecord func(ReviewSubmission, ReviewReceipt), stop <-chan struct{}) error {
+	if _, err := os.Stat(filepath.Join(projectDir, ".ptsd")); err != nil {
+		return fmt.Errorf("err:config .ptsd not found")
+	}
+	if token == "" {
+		return fmt.Errorf("err:config review token is empty: set the env var named by review.token_env")
+	}
+	if err := CheckListenAddr(projectDir, "review serve", addr); err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	srv := &http.Server{Handler: ReviewHandler(projectDir, token, onRecord), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-stop
+		srv.Close()
+	}()
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReviewHandler(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	os.WriteFile(filepath.Join(ptsdDir, "ptsd.yaml"), []byte("review:\n  min_score: 7\n"), 0644)
+	os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte("features:\n  user-auth:\n    stage: prd\n    hashes: {}\n    scores: {}\n"), 0644)
+
+	var recorded []ReviewSubmission
+	srv := httptest.NewServer(ReviewHandler(dir, "s3cret", func(sub ReviewSubmission, _ ReviewReceipt) {
+		recorded = append(recorded, sub)
+	}))
+	defer srv.Close()
+
+	post := func(token, body string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/review", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	body := `{"feature":"user-auth","stage":"prd","score":5,"issues":["AC-2 is \"vague\"","no error cases"],"reviewer":"alice"}`
+	if resp := post("", body); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("no token: status %d, want 401", resp.StatusCode)
+	}
+	if resp := post("wrong", body); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("wrong token: status %d, want 401", resp.StatusCode)
+	}
+	if resp, _ := http.Get(srv.URL + "/review"); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status %d, want 405", resp.StatusCode)
+	}
+	if resp := post("s3cret", `{"feature":"user-auth"`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad JSON: status %d, want 400", resp.StatusCode)
+	}
+	if resp := post("s3cret", `{"feature":"user-auth","stage":"nope","score":8}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown stage: status %d, want 400", resp.StatusCode)
+	}
+	if len(recorded) != 0 {
+		t.Fatalf("rejected requests must not record, got %+v", recorded)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/review", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var receipt ReviewReceipt
+	if err := json.NewDecoder(resp.Body).Decode(&receipt); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || receipt.Verdict != "fail" || receipt.Score != 5 {
+		t.Errorf("status %d receipt %+v, want 200 with verdict fail", resp.StatusCode, receipt)
+	}
+	if len(recorded) != 1 || recorded[0].Reviewer != "alice" {
+		t.Errorf("onRecord got %+v", recorded)
+	}
+
+	state, _ := LoadState(dir)
+	if state.Features["user-auth"].Scores["prd"].Value != 5 {
+		t.Errorf("score not recorded: %+v", state.Features["user-auth"])
+	}
+	rs, _ := loadReviewStatus(dir)
+	e := rs["user-auth"]
+	if e.Review != "failed" || e.Issues != 3 || e.IssuesList[1] != (ReviewIssue{SeverityMajor, "AC-2 is 'vague'"}) || e.IssuesList[2] != (ReviewIssue{SeverityMajor, "no error cases"}) {
+		t.Errorf("review status = %+v, want the min-score issue plus the reviewer's two", e)
+	}
+	events, _ := os.ReadFile(filepath.Join(ptsdDir, "events.yaml"))
+	if !strings.Contains(string(events), "reviewer=alice") {
+		t.Errorf("review event should name the reviewer:\n%s", events)
+	}
+}
+
+func TestReviewHandlerConsensus(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	os.WriteFile(filepath.Join(ptsdDir, "ptsd.yaml"), []byte("review:\n  min_score: 7\n  require: 2\n"), 0644)
+	os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte("features:\n  user-auth:\n    stage: prd\n    hashes: {}\n    scores: {}\n"), 0644)
+
+	srv := httptest.NewServer(ReviewHandler(dir, "s3cret", nil))
+	defer srv.Close()
+
+	post := func(body string) ReviewReceipt {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/review", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var receipt ReviewReceipt
+		if err := json.NewDecoder(resp.Body).Decode(&receipt); err != nil {
+			t.Fatal(err)
+		}
+		return receipt
+	}
+
+	// One passing webhook is a single reviewer: the gate stays pending.
+	r := post(`{"feature":"user-auth","stage":"prd","score":9,"reviewer":"alice"}`)
+	if r.Verdict != "pending" || r.Reviewers != 1 || r.Require != 2 {
+		t.Errorf("first receipt = %+v, want pending with 1 of 2 reviewers", r)
+	}
+	if ok, _ := CheckReviewGate(dir, "user-auth", "prd"); ok {
+		t.Error("a single webhook review must not pass a gate that requires two reviewers")
+	}
+
+	r = post(`{"feature":"user-auth","stage":"prd","score":8,"reviewer":"bob"}`)
+	if r.Verdict != "pass" || r.Reviewers != 2 {
+		t.Errorf("second receipt = %+v, want pass with 2 reviewers", r)
+	}
+	if ok, _ := CheckReviewGate(dir, "user-auth", "prd"); !ok {
+		t.Error("gate should pass once two reviewers agree")
+	}
+}
+
+func TestServeReviewsRequiresToken(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	err := ServeReviews(dir, "127.0.0.1:0", "", nil, make(chan struct{}))
+	if err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Errorf("err = %v, want err:config for an empty token", err)
+	}
+}
@@ -31,7 +31,7 @@ func InitSeed(projectDir string, featureID string) error {
 		return fmt.Errorf("err:validation feature %s not found", featureID)
 	}
 
-	seedDir := filepath.Join(projectDir, ".ptsd", "seeds", featureID)
+	seedDir := seedDirPath(projectDir, featureID)
 	seedPath := filepath.Join(seedDir, "seed.yaml")
 
 	if _, err := os.Stat(seedPath); err == nil {
@@ -43,7 +43,7 @@ func InitSeed(projectDir string, featureID string) error {
 	}
 
 	seedYAML := "feature: " + featureID + "\nfiles:\n"
-	if err := os.WriteFile(seedPath, []byte(seedYAML), 0644); err != nil {
+	if err := writeFileAtomic(seedPath, []byte(seedYAML), 0644); err != nil {
 		return fmt.Errorf("err:io %w", err)
 	}
 
@@ -55,7 +55,7 @@ func AddSeedFile(projectDir string, featureID string, filePath string, fileType
 		return fmt.Errorf("err:validation invalid seed type %q: must be data|fixture|schema", fileType)
 	}
 
-	seedDir := filepath.Join(projectDir, ".ptsd", "seeds", featureID)
+	seedDir := seedDirPath(projectDir, featureID)
 	seedYAMLPath := filepath.Join(seedDir, "seed.yaml")
 
 	if _, err := os.Stat(seedYAMLPath); os.IsNotExist(err) {
@@ -101,7 +101,7 @@ func AddSeedFile(projectDir string, featureID string, filePath string, fileType
 	}
 	content += entry
 
-	if err := os.WriteFile(seedYAMLPath, []byte(content), 0644); err != nil {
+	if err := writeFileAtomic(seedYAMLPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("err:io %w", err)
 	}
 
@@ -119,7 +119,7 @@ func CheckSeeds(projectDir string) ([]string, error) {
 		if f.Status == "planned" || f.Status == "deferred" {
 			continue
 		}
-		seedDir := filepath.Join(projectDir, ".ptsd", "seeds", f.ID)
+		seedDir := seedDirPath(projectDir, f.ID)
 		seedPath := filepath.Join(seedDir, "seed.yaml")
 		if _, err := os.Stat(seedPath); os.IsNotExist(err) {
 			problems = append(problems, f.ID+" has no seed")
@@ -131,10 +131,17 @@ func CheckSeeds(projectDir string) ([]string, error) {
 		if err != nil {
 			return nil, fmt.Errorf("err:io %w", err)
 		}
+		sums := seedChecksums(string(data))
 		for _, file := range parseSeedManifestFiles(string(data)) {
 			filePath := filepath.Join(seedDir, file)
-			if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			content, err := os.ReadFile(filePath)
+			if os.IsNotExist(err) {
 				problems = append(problems, f.ID+" seed manifest references missing file: "+file)
+				continue
+			}
+			// Snapshotted seeds carry a checksum; hand edits show up as drift.
+			if sum, ok := sums[file]; ok && err == nil && contentHash(content) != sum {
+				problems = append(problems, f.ID+" seed "+file+" does not match its recorded sha256 (re-run ptsd seed snapshot)")
 			}
 		}
 	}
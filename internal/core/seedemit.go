@@ -0,0 +1,209 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Seed accessors. `ptsd seed emit <feature> --lang go|ts|py` writes a small
+// module with one constant per seed file, so tests load golden data through
+// generated names instead of hardcoded relative paths. The module locates
+// the feature's seed directory relative to itself at run time (Go cannot embed
+// files outside its package), and is regenerated after seed changes.
+
+// SeedEmitLangs are the languages seed emit generates for.
+var SeedEmitLangs = []string{"go", "ts", "py"}
+
+// seedEmitMarker starts the first line of every generated module; emit only
+// overwrites files that carry it.
+const seedEmitMarker = "Code generated by ptsd seed emit"
+
+// SeedEmit describes a generated accessor module.
+type SeedEmit struct {
+	Feature string
+	Lang    string
+	Path    string // project-relative
+	Files   []SeedEmitFile
+}
+
+// SeedEmitFile is one seed file and its constant in the generated module.
+type SeedEmitFile struct {
+	File  string
+	Const string
+}
+
+// defaultSeedEmitPath is where a module goes without --out.
+func defaultSeedEmitPath(featureID, lang string) string {
+	switch lang {
+	case "go":
+		return "internal/testseeds/" + goSeedPackage(featureID) + "/seeds.go"
+	case "ts":
+		return "test/seeds/" + featureID + ".ts"
+	default:
+		return "tests/seeds/" + strings.ReplaceAll(featureID, "-", "_") + ".py"
+	}
+}
+
+// EmitSeedAccessors generates the accessor module for featureID's seeds at
+// out (project-relative; empty for the language default).
+func EmitSeedAccessors(projectDir, featureID, lang, out string) (SeedEmit, error) {
+	valid := false
+	for _, l := range SeedEmitLangs {
+		valid = valid || l == lang
+	}
+	if !valid {
+		return SeedEmit{}, fmt.Errorf("err:user invalid --lang %q: must be %s", lang, strings.Join(SeedEmitLangs, "|"))
+	}
+	seedDir := seedDirPath(projectDir, featureID)
+	manifest, err := os.ReadFile(filepath.Join(seedDir, "seed.yaml"))
+	if err != nil {
+		return SeedEmit{}, fmt.Errorf("err:validation seed not initialized for %s", featureID)
+	}
+	entries := seedManifestEntries(string(manifest))
+	if len(entries) == 0 {
+		return SeedEmit{}, fmt.Errorf("err:validation seed manifest for %s lists no files", featureID)
+	}
+
+	if out == "" {
+		out = defaultSeedEmitPath(featureID, lang)
+	}
+	out = filepath.ToSlash(filepath.Clean(out))
+	if filepath.IsAbs(out) || strings.HasPrefix(out, "../") {
+		return SeedEmit{}, fmt.Errorf("err:user --out must be inside the project: %s", out)
+	}
+	emit := SeedEmit{Feature: featureID, Lang: lang, Path: out}
+	seen := make(map[string]string)
+	for _, e := range entries {
+		name := seedConstName(e[0], lang)
+		if prev, ok := seen[name]; ok {
+			return SeedEmit{}, fmt.Errorf("err:validation seed files %s and %s both map to %s", prev, e[0], name)
+		}
+		seen[name] = e[0]
+		emit.Files = append(emit.Files, SeedEmitFile{File: e[0], Const: name})
+	}
+
+	target := filepath.Join(projectDir, filepath.FromSlash(out))
+	if data, err := os.ReadFile(target); err == nil {
+		first, _, _ := strings.Cut(string(data), "\n")
+		if !strings.Contains(first, seedEmitMarker) {
+			return SeedEmit{}, fmt.Errorf("err:validation %s exists and was not generated by ptsd seed emit: choose another --out", out)
+		}
+	}
+	rel, err := filepath.Rel(filepath.Dir(target), seedDir)
+	if err != nil {
+		return SeedEmit{}, fmt.Errorf("err:io %w", err)
+	}
+	rel = filepath.ToSlash(rel)
+
+	var content string
+	switch lang {
+	case "go":
+		content = renderGoSeeds(emit, goSeedPackage(filepath.Base(filepath.Dir(target))), rel)
+	case "ts":
+		content = renderTSSeeds(emit, rel)
+	case "py":
+		content = renderPySeeds(emit, rel)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return SeedEmit{}, fmt.Errorf("err:io %w", err)
+	}
+	if err := writeFileAtomic(target, []byte(content), 0644); err != nil {
+		return SeedEmit{}, fmt.Errorf("err:io %w", err)
+	}
+	return emit, nil
+}
+
+// seedWords splits a seed file name into lowercase words: "users-v2.json"
+// gives users, v2, json.
+func seedWords(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// seedConstName is the generated constant for a seed file: UsersJSON (go),
+// usersJson (ts), USERS_JSON (py).
+func seedConstName(file, lang string) string {
+	words := seedWords(file)
+	var name string
+	switch lang {
+	case "py":
+		name = strings.ToUpper(strings.Join(words, "_"))
+	default:
+		for i, w := range words {
+			switch {
+			case lang == "go" && (w == "json" || w == "yaml" || w == "csv" || w == "xml" || w == "id" || w == "url"):
+				w = strings.ToUpper(w)
+			case i > 0 || lang == "go":
+				w = strings.ToUpper(w[:1]) + w[1:]
+			}
+			name += w
+		}
+	}
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		prefix := map[string]string{"go": "Seed", "ts": "seed", "py": "SEED_"}[lang]
+		name = prefix + name
+	}
+	return name
+}
+
+// goSeedPackage turns a feature ID or directory name into a Go package name.
+func goSeedPackage(s string) string {
+	name := strings.Join(seedWords(s), "")
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "seed" + name
+	}
+	return name
+}
+
+func renderGoSeeds(emit SeedEmit, pkg, rel string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s %s; DO NOT EDIT.\n\n", seedEmitMarker, emit.Feature)
+	fmt.Fprintf(&b, "// Package %s gives tests the golden seed data of feature %s.\n", pkg, emit.Feature)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import (\n\t\"os\"\n\t\"path/filepath\"\n\t\"runtime\"\n)\n\n")
+	b.WriteString("// Seed file names, relative to Dir.\nconst (\n")
+	for _, f := range emit.Files {
+		fmt.Fprintf(&b, "\t%s = %s\n", f.Const, strconv.Quote(f.File))
+	}
+	b.WriteString(")\n\n")
+	fmt.Fprintf(&b, "// Dir is the absolute path of the %s seed directory.\n", emit.Feature)
+	b.WriteString("var Dir = func() string {\n\t_, self, _, _ := runtime.Caller(0)\n")
+	fmt.Fprintf(&b, "\treturn filepath.Join(filepath.Dir(self), %s)\n}()\n\n", strconv.Quote(rel))
+	b.WriteString("// Path returns the absolute path of a seed file.\nfunc Path(name string) string {\n\treturn filepath.Join(Dir, name)\n}\n\n")
+	b.WriteString("// Read returns a seed file's contents and panics if it cannot be read.\nfunc Read(name string) []byte {\n")
+	b.WriteString("\tdata, err := os.ReadFile(Path(name))\n\tif err != nil {\n\t\tpanic(err)\n\t}\n\treturn data\n}\n")
+	return b.String()
+}
+
+func renderTSSeeds(emit SeedEmit, rel string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s %s; DO NOT EDIT.\n", seedEmitMarker, emit.Feature)
+	b.WriteString("import { readFileSync } from \"node:fs\";\nimport { fileURLToPath } from \"node:url\";\n\n")
+	fmt.Fprintf(&b, "/** Absolute path of the %s seed directory. */\n", emit.Feature)
+	fmt.Fprintf(&b, "export const seedDir = fileURLToPath(new URL(%s, import.meta.url));\n\n", strconv.Quote(rel+"/"))
+	b.WriteString("/** Absolute paths of the seed files. */\nexport const seeds = {\n")
+	for _, f := range emit.Files {
+		fmt.Fprintf(&b, "  %s: seedDir + %s,\n", f.Const, strconv.Quote(f.File))
+	}
+	b.WriteString("} as const;\n\n")
+	b.WriteString("/** Reads a seed file as UTF-8 text. */\nexport function readSeed(name: keyof typeof seeds): string {\n  return readFileSync(seeds[name], \"utf8\");\n}\n")
+	return b.String()
+}
+
+func renderPySeeds(emit SeedEmit, rel string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s %s; DO NOT EDIT.\n", seedEmitMarker, emit.Feature)
+	fmt.Fprintf(&b, "\"\"\"Golden seed data of feature %s.\"\"\"\n\n", emit.Feature)
+	b.WriteString("from pathlib import Path\n\n")
+	fmt.Fprintf(&b, "SEED_DIR = (Path(__file__).resolve().parent / %s).resolve()\n\n", strconv.Quote(rel))
+	for _, f := range emit.Files {
+		fmt.Fprintf(&b, "%s = SEED_DIR / %s\n", f.Const, strconv.Quote(f.File))
+	}
+	b.WriteString("\n\ndef read_seed(path: Path) -> str:\n    \"\"\"Read a seed file as UTF-8 text.\"\"\"\n    return path.read_text(encoding=\"utf-8\")\n")
+	return b.String()
+}
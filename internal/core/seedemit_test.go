@@ -0,0 +1,106 @@
+package core
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupEmitSeeds(t *testing.T) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	if err := InitSeed(dir, "user-auth"); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"users.json": `[{"id":1}]`, "bad-logins.csv": "user,attempts\n"} {
+		src := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := AddSeedFile(dir, "user-auth", src, "data", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestEmitSeedAccessorsGo(t *testing.T) {
+	dir := setupEmitSeeds(t)
+	emit, err := EmitSeedAccessors(dir, "user-auth", "go", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if emit.Path != "internal/testseeds/userauth/seeds.go" {
+		t.Errorf("Path = %q", emit.Path)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, emit.Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(data)
+	if _, err := parser.ParseFile(token.NewFileSet(), "seeds.go", src, 0); err != nil {
+		t.Fatalf("generated Go does not parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"package userauth",
+		`UsersJSON = "users.json"`,
+		`BadLoginsCSV = "bad-logins.csv"`,
+		`filepath.Join(filepath.Dir(self), "../../../.ptsd/seeds/user-auth")`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected %q in generated Go:\n%s", want, src)
+		}
+	}
+
+	// Regenerating overwrites the generated file.
+	if _, err := EmitSeedAccessors(dir, "user-auth", "go", ""); err != nil {
+		t.Errorf("regenerate failed: %v", err)
+	}
+}
+
+func TestEmitSeedAccessorsTSAndPy(t *testing.T) {
+	dir := setupEmitSeeds(t)
+	if _, err := EmitSeedAccessors(dir, "user-auth", "ts", "web/test/fixtures.ts"); err != nil {
+		t.Fatal(err)
+	}
+	ts, _ := os.ReadFile(filepath.Join(dir, "web", "test", "fixtures.ts"))
+	for _, want := range []string{`new URL("../../.ptsd/seeds/user-auth/", import.meta.url)`, `usersJson: seedDir + "users.json"`, `badLoginsCsv:`} {
+		if !strings.Contains(string(ts), want) {
+			t.Errorf("expected %q in generated TS:\n%s", want, ts)
+		}
+	}
+
+	emit, err := EmitSeedAccessors(dir, "user-auth", "py", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	py, _ := os.ReadFile(filepath.Join(dir, emit.Path))
+	for _, want := range []string{`Path(__file__).resolve().parent / "../../.ptsd/seeds/user-auth"`, `USERS_JSON = SEED_DIR / "users.json"`, "def read_seed"} {
+		if !strings.Contains(string(py), want) {
+			t.Errorf("expected %q in generated Python:\n%s", want, py)
+		}
+	}
+}
+
+func TestEmitSeedAccessorsErrors(t *testing.T) {
+	dir := setupEmitSeeds(t)
+	if _, err := EmitSeedAccessors(dir, "user-auth", "rust", ""); err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Errorf("expected err:user for an unknown language, got %v", err)
+	}
+	if _, err := EmitSeedAccessors(dir, "missing", "go", ""); err == nil || !strings.HasPrefix(err.Error(), "err:validation") {
+		t.Errorf("expected err:validation without a seed, got %v", err)
+	}
+	if _, err := EmitSeedAccessors(dir, "user-auth", "go", "../outside.go"); err == nil {
+		t.Error("expected an error for --out outside the project")
+	}
+	handwritten := filepath.Join(dir, "seeds.go")
+	if err := os.WriteFile(handwritten, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := EmitSeedAccessors(dir, "user-auth", "go", "seeds.go"); err == nil {
+		t.Error("expected a refusal to overwrite a file ptsd did not generate")
+	}
+}
@@ -0,0 +1,732 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Seed scaffolding. `ptsd seed scaffold <feature> --from <file>` reads the
+// type definitions of a Go or TypeScript file and writes one skeleton seed
+// file per top-level type: a JSON array holding a single record with every
+// field named as it serializes and a plausible value of the right type. The
+// agent then replaces the values and adds edge-case records.
+
+// SeedScaffold reports the files written by ScaffoldSeeds.
+type SeedScaffold struct {
+	Feature string
+	From    string
+	Files   []SeedScaffoldFile
+}
+
+// SeedScaffoldFile is one skeleton seed file.
+type SeedScaffoldFile struct {
+	File   string
+	Type   string
+	Fields int
+	// Skipped is set when the file already existed and was left alone.
+	Skipped bool
+}
+
+// scaffoldShape is a type definition reduced to what its JSON looks like.
+type scaffoldShape struct {
+	// Kind is object, array, string, int, float, number (int or float by
+	// field name), bool, time, literal, ref or null.
+	Kind    string
+	Fields  []scaffoldField // object
+	Elem    *scaffoldShape  // array
+	Ref     string          // ref: a named type of the same file
+	Literal any             // literal
+}
+
+// scaffoldField is an object member. An empty Name embeds the fields of
+// Shape (Go embedding, TypeScript extends).
+type scaffoldField struct {
+	Name  string
+	Shape *scaffoldShape
+}
+
+// scaffoldTypes are the named types of a source file in declaration order.
+type scaffoldTypes struct {
+	order []string
+	defs  map[string]*scaffoldShape
+}
+
+func (t *scaffoldTypes) add(name string, shape *scaffoldShape) {
+	if _, ok := t.defs[name]; !ok {
+		t.order = append(t.order, name)
+	}
+	t.defs[name] = shape
+}
+
+// ScaffoldSeeds writes skeleton seed files for the types declared in from
+// (project-relative or absolute) and lists them in the feature's manifest.
+// Existing seed files are never overwritten.
+func ScaffoldSeeds(projectDir, featureID, from string) (SeedScaffold, error) {
+	result := SeedScaffold{Feature: featureID, From: from}
+	seedDir := seedDirPath(projectDir, featureID)
+	manifestPath := filepath.Join(seedDir, "seed.yaml")
+	manifest, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return result, fmt.Errorf("err:validation seed not initialized for %s", featureID)
+	}
+	if err != nil {
+		return result, fmt.Errorf("err:io %w", err)
+	}
+
+	src := from
+	if !filepath.IsAbs(src) {
+		src = filepath.Join(projectDir, src)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return result, fmt.Errorf("err:io %w", err)
+	}
+	var types scaffoldTypes
+	switch ext := filepath.Ext(from); ext {
+	case ".go":
+		types, err = parseGoScaffoldTypes(src, data)
+	case ".ts", ".tsx":
+		types = parseTSScaffoldTypes(string(data))
+	default:
+		return result, fmt.Errorf("err:user --from must be a .go or .ts file: %s", from)
+	}
+	if err != nil {
+		return result, err
+	}
+	roots := scaffoldRoots(types)
+	if len(roots) == 0 {
+		return result, fmt.Errorf("err:validation no struct or interface types found in %s", from)
+	}
+
+	listed := make(map[string]bool)
+	for _, e := range seedManifestEntries(string(manifest)) {
+		listed[e[0]] = true
+	}
+	content := string(manifest)
+	for _, name := range roots {
+		file := strings.Join(nameWords(name), "_") + ".json"
+		value, _ := types.resolve(types.defs[name], map[string]bool{name: true}, "").(seedObject)
+		out := SeedScaffoldFile{File: file, Type: name, Fields: len(value)}
+		path := filepath.Join(seedDir, file)
+		if _, err := os.Stat(path); err == nil {
+			out.Skipped = true
+			result.Files = append(result.Files, out)
+			continue
+		}
+		body, err := json.MarshalIndent([]any{value}, "", "  ")
+		if err != nil {
+			return result, fmt.Errorf("err:io %w", err)
+		}
+		if err := writeFileAtomic(path, append(body, '\n'), 0644); err != nil {
+			return result, fmt.Errorf("err:io %w", err)
+		}
+		if !listed[file] {
+			content += "  - path: " + file + "\n    type: data\n    description: \"skeleton of " + name + " from " + filepath.ToSlash(from) + "\"\n"
+		}
+		result.Files = append(result.Files, out)
+	}
+	if err := writeFileAtomic(manifestPath, []byte(content), 0644); err != nil {
+		return result, fmt.Errorf("err:io %w", err)
+	}
+	return result, nil
+}
+
+// scaffoldRoots returns the object types no other type refers to, so a User
+// with an embedded Address gives one user.json. A type referring to itself
+// (User.Manager) still counts as a root; if every object type is referenced
+// by another (a cycle), all of them are roots.
+func scaffoldRoots(types scaffoldTypes) []string {
+	referenced := make(map[string]bool)
+	for _, name := range types.order {
+		var walk func(s *scaffoldShape)
+		walk = func(s *scaffoldShape) {
+			if s == nil {
+				return
+			}
+			if s.Kind == "ref" && s.Ref != name {
+				referenced[s.Ref] = true
+			}
+			walk(s.Elem)
+			for _, f := range s.Fields {
+				walk(f.Shape)
+			}
+		}
+		walk(types.defs[name])
+	}
+	var roots, objects []string
+	for _, name := range types.order {
+		if types.defs[name].Kind != "object" {
+			continue
+		}
+		objects = append(objects, name)
+		if !referenced[name] {
+			roots = append(roots, name)
+		}
+	}
+	if len(roots) == 0 {
+		return objects
+	}
+	return roots
+}
+
+// seedObject is a JSON object that keeps its keys in declaration order.
+type seedObject []seedMember
+
+type seedMember struct {
+	Key   string
+	Value any
+}
+
+func (o seedObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.Key)
+		value, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// resolve builds the example value of shape for a field called field.
+// seen holds the named types being expanded; a type reached again through
+// itself becomes null.
+func (t scaffoldTypes) resolve(shape *scaffoldShape, seen map[string]bool, field string) any {
+	switch shape.Kind {
+	case "ref":
+		def, ok := t.defs[shape.Ref]
+		if !ok || seen[shape.Ref] {
+			return nil
+		}
+		seen[shape.Ref] = true
+		defer delete(seen, shape.Ref)
+		return t.resolve(def, seen, field)
+	case "object":
+		obj := seedObject{}
+		for _, f := range shape.Fields {
+			if f.Name != "" {
+				obj = append(obj, seedMember{f.Name, t.resolve(f.Shape, seen, f.Name)})
+				continue
+			}
+			if embedded, ok := t.resolve(f.Shape, seen, field).(seedObject); ok {
+				obj = append(obj, embedded...)
+			}
+		}
+		return obj
+	case "array":
+		return []any{t.resolve(shape.Elem, seen, field)}
+	case "literal":
+		return shape.Literal
+	case "null":
+		return nil
+	default:
+		return seedScalar(shape.Kind, field)
+	}
+}
+
+// nameWords splits an identifier into lowercase words: "createdAt",
+// "created_at" and "CreatedAt" all give created, at; "HTTPCode" gives http,
+// code.
+func nameWords(s string) []string {
+	var words []string
+	var cur []rune
+	runes := []rune(s)
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, strings.ToLower(string(cur)))
+			cur = nil
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(cur) > 0:
+			prevLower := unicode.IsLower(cur[len(cur)-1]) || unicode.IsDigit(cur[len(cur)-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return words
+}
+
+// seedScalar picks a plausible value of kind for a field, going by the
+// words of its name.
+func seedScalar(kind, field string) any {
+	words := nameWords(field)
+	last := ""
+	if len(words) > 0 {
+		last = words[len(words)-1]
+	}
+	has := func(names ...string) bool {
+		for _, w := range words {
+			for _, n := range names {
+				if w == n {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	floatName := has("price", "amount", "total", "cost", "balance", "lat", "latitude", "lng", "lon", "longitude", "rate", "ratio", "percent", "score")
+	if kind == "number" {
+		kind = "int"
+		if floatName {
+			kind = "float"
+		}
+	}
+
+	switch kind {
+	case "time":
+		return "2024-01-15T09:30:00Z"
+	case "bool":
+		return len(words) > 0 && (has("active", "enabled", "verified", "visible") || words[0] == "is" || words[0] == "has" || words[0] == "can")
+	case "int":
+		switch {
+		case has("price", "amount", "total", "cost", "cents", "balance"):
+			return 1999
+		case has("count", "qty", "quantity", "size", "limit"):
+			return 3
+		case has("age"):
+			return 34
+		case has("year"):
+			return 2024
+		case has("port"):
+			return 8080
+		}
+		return 1
+	case "float":
+		switch {
+		case has("price", "amount", "total", "cost", "balance"):
+			return 19.99
+		case has("lat", "latitude"):
+			return 52.52
+		case has("lng", "lon", "longitude"):
+			return 13.405
+		case has("rate", "ratio", "percent", "score"):
+			return 0.25
+		}
+		return 1.5
+	}
+
+	switch {
+	case has("email"):
+		return "ann.lee@example.com"
+	case has("url", "uri", "link", "website", "href", "avatar"):
+		return "https://example.com"
+	case has("phone"):
+		return "+1-555-0100"
+	case last == "id" || last == "uuid" || last == "guid":
+		return "7c9e6679-7425-40de-944b-e07072f3a1b1"
+	case last == "date" || last == "day":
+		return "2024-01-15"
+	case last == "at" || has("time", "timestamp"):
+		return "2024-01-15T09:30:00Z"
+	case has("first", "given"):
+		return "Ann"
+	case has("last", "family", "surname"):
+		return "Lee"
+	case last == "name" && (len(words) == 1 || has("full", "display", "user", "person", "author", "customer")):
+		return "Ann Lee"
+	case has("username", "login", "handle"):
+		return "annlee"
+	case has("password", "secret"):
+		return "correct-horse-battery"
+	case has("token"):
+		return "tok_4f9a1c2b"
+	case has("status", "state"):
+		return "active"
+	case has("currency"):
+		return "USD"
+	case has("country"):
+		return "US"
+	case has("city"):
+		return "Berlin"
+	case has("lang", "language", "locale"):
+		return "en"
+	case has("color", "colour"):
+		return "#3366ff"
+	}
+	if len(words) == 0 {
+		return "example"
+	}
+	return "example " + strings.Join(words, " ")
+}
+
+// parseGoScaffoldTypes reads the type declarations of a Go file. Fields
+// serialize under their json tag, unexported and `json:"-"` fields are left
+// out, and typed constants supply the example of their named type
+// (type Role string; const RoleAdmin Role = "admin").
+func parseGoScaffoldTypes(path string, src []byte) (scaffoldTypes, error) {
+	types := scaffoldTypes{defs: make(map[string]*scaffoldShape)}
+	file, err := parser.ParseFile(token.NewFileSet(), path, src, 0)
+	if err != nil {
+		return types, fmt.Errorf("err:validation cannot parse %s: %v", filepath.Base(path), err)
+	}
+	consts := make(map[string]any)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.TypeParams == nil {
+					types.add(s.Name.Name, goShape(s.Type))
+				}
+			case *ast.ValueSpec:
+				ident, ok := s.Type.(*ast.Ident)
+				if gen.Tok != token.CONST || !ok || len(s.Values) == 0 || consts[ident.Name] != nil {
+					continue
+				}
+				if v := goLiteral(s.Values[0]); v != nil {
+					consts[ident.Name] = v
+				}
+			}
+		}
+	}
+	for name, v := range consts {
+		if def, ok := types.defs[name]; ok && def.Kind != "object" {
+			types.defs[name] = &scaffoldShape{Kind: "literal", Literal: v}
+		}
+	}
+	return types, nil
+}
+
+func goLiteral(expr ast.Expr) any {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return nil
+	}
+	switch lit.Kind {
+	case token.STRING:
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil
+		}
+		return s
+	case token.INT:
+		n, err := strconv.ParseInt(lit.Value, 0, 64)
+		if err != nil {
+			return nil
+		}
+		return n
+	case token.FLOAT:
+		f, err := strconv.ParseFloat(lit.Value, 64)
+		if err != nil {
+			return nil
+		}
+		return f
+	}
+	return nil
+}
+
+func goShape(expr ast.Expr) *scaffoldShape {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch e.Name {
+		case "string":
+			return &scaffoldShape{Kind: "string"}
+		case "bool":
+			return &scaffoldShape{Kind: "bool"}
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune":
+			return &scaffoldShape{Kind: "int"}
+		case "float32", "float64":
+			return &scaffoldShape{Kind: "float"}
+		case "any", "error", "complex64", "complex128":
+			return &scaffoldShape{Kind: "null"}
+		}
+		return &scaffoldShape{Kind: "ref", Ref: e.Name}
+	case *ast.StarExpr:
+		return goShape(e.X)
+	case *ast.ArrayType:
+		if ident, ok := e.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &scaffoldShape{Kind: "string"} // base64 in JSON
+		}
+		return &scaffoldShape{Kind: "array", Elem: goShape(e.Elt)}
+	case *ast.MapType:
+		return &scaffoldShape{Kind: "object"}
+	case *ast.SelectorExpr:
+		switch pkg, _ := e.X.(*ast.Ident); {
+		case pkg != nil && pkg.Name == "time" && e.Sel.Name == "Time":
+			return &scaffoldShape{Kind: "time"}
+		case pkg != nil && pkg.Name == "time" && e.Sel.Name == "Duration":
+			return &scaffoldShape{Kind: "literal", Literal: 1000000000}
+		case e.Sel.Name == "UUID":
+			return &scaffoldShape{Kind: "literal", Literal: "7c9e6679-7425-40de-944b-e07072f3a1b1"}
+		}
+		return &scaffoldShape{Kind: "null"}
+	case *ast.StructType:
+		shape := &scaffoldShape{Kind: "object"}
+		for _, f := range e.Fields.List {
+			tag := ""
+			if f.Tag != nil {
+				if t, err := strconv.Unquote(f.Tag.Value); err == nil {
+					tag = reflectTagJSON(t)
+				}
+			}
+			if tag == "-" {
+				continue
+			}
+			if len(f.Names) == 0 {
+				shape.Fields = append(shape.Fields, scaffoldField{Name: tag, Shape: goShape(f.Type)})
+				continue
+			}
+			for _, n := range f.Names {
+				if !n.IsExported() {
+					continue
+				}
+				name := n.Name
+				if tag != "" {
+					name = tag
+				}
+				shape.Fields = append(shape.Fields, scaffoldField{Name: name, Shape: goShape(f.Type)})
+			}
+		}
+		return shape
+	}
+	return &scaffoldShape{Kind: "null"}
+}
+
+// reflectTagJSON returns the name part of a struct tag's json key ("-" to
+// skip the field, "" when it keeps its Go name).
+func reflectTagJSON(tag string) string {
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		key, rest, ok := strings.Cut(tag, ":\"")
+		if !ok {
+			return ""
+		}
+		end := strings.Index(rest, "\"")
+		if end < 0 {
+			return ""
+		}
+		if key == "json" {
+			name, _, _ := strings.Cut(rest[:end], ",")
+			if name == "-" && strings.HasPrefix(rest[:end], "-,") {
+				return "-" // `json:"-,"` names the field "-"
+			}
+			return name
+		}
+		tag = rest[end+1:]
+	}
+	return ""
+}
+
+var (
+	tsCommentRe   = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+	tsInterfaceRe = regexp.MustCompile(`\binterface\s+([A-Za-z_$][\w$]*)(?:\s*<[^{]*>)?\s*(?:extends\s+([^{]+))?\{`)
+	tsTypeRe      = regexp.MustCompile(`\btype\s+([A-Za-z_$][\w$]*)\s*=\s*`)
+	tsEnumRe      = regexp.MustCompile(`\benum\s+([A-Za-z_$][\w$]*)\s*\{`)
+	tsIdentRe     = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+	tsPropertyRe  = regexp.MustCompile(`^(?:readonly\s+)?(["']?)([A-Za-z_$][\w$-]*)(["']?)\s*(\?)?\s*:\s*(.+)$`)
+)
+
+// parseTSScaffoldTypes reads interfaces, object type aliases, literal union
+// aliases and enums from TypeScript source. It is a lightweight scanner, not
+// a parser: generics, mapped and conditional types come out as null.
+func parseTSScaffoldTypes(src string) scaffoldTypes {
+	types := scaffoldTypes{defs: make(map[string]*scaffoldShape)}
+	src = tsCommentRe.ReplaceAllString(src, "")
+
+	for _, m := range tsInterfaceRe.FindAllStringSubmatchIndex(src, -1) {
+		name := src[m[2]:m[3]]
+		body := tsBlock(src, m[1]-1)
+		shape := tsObject(body)
+		if m[4] >= 0 {
+			var parents []scaffoldField
+			for _, p := range strings.Split(src[m[4]:m[5]], ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					parents = append(parents, scaffoldField{Shape: tsShape(p)})
+				}
+			}
+			shape.Fields = append(parents, shape.Fields...)
+		}
+		types.add(name, shape)
+	}
+	for _, m := range tsTypeRe.FindAllStringSubmatchIndex(src, -1) {
+		rest := src[m[1]:]
+		var expr string
+		if strings.HasPrefix(rest, "{") {
+			expr = "{" + tsBlock(src, m[1]) + "}"
+		} else {
+			expr, _, _ = strings.Cut(rest, ";")
+			if i := strings.Index(expr, "\n\n"); i >= 0 {
+				expr = expr[:i]
+			}
+		}
+		types.add(src[m[2]:m[3]], tsShape(expr))
+	}
+	for _, m := range tsEnumRe.FindAllStringSubmatchIndex(src, -1) {
+		first, _, _ := strings.Cut(tsBlock(src, m[1]-1), ",")
+		key, value, ok := strings.Cut(first, "=")
+		var lit any = strings.TrimSpace(key)
+		if ok {
+			lit = tsLiteral(strings.TrimSpace(value))
+		}
+		types.add(src[m[2]:m[3]], &scaffoldShape{Kind: "literal", Literal: lit})
+	}
+	return types
+}
+
+// tsBlock returns the text between the brace at open and its match.
+func tsBlock(src string, open int) string {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch src[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return src[open+1 : i]
+			}
+		}
+	}
+	return src[open+1:]
+}
+
+// tsSplit splits s at sep characters outside brackets, braces, parens,
+// angle brackets and quotes.
+func tsSplit(s string, seps string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case strings.IndexByte("{[(<", c) >= 0:
+			depth++
+		case strings.IndexByte("}])>", c) >= 0 && !(c == '>' && i > 0 && s[i-1] == '='):
+			depth--
+		case depth == 0 && strings.IndexByte(seps, c) >= 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func tsObject(body string) *scaffoldShape {
+	shape := &scaffoldShape{Kind: "object"}
+	for _, member := range tsSplit(body, ";,\n") {
+		member = strings.TrimSpace(member)
+		m := tsPropertyRe.FindStringSubmatch(member)
+		if m == nil || m[1] != m[3] {
+			continue // index signatures, methods, blank lines
+		}
+		shape.Fields = append(shape.Fields, scaffoldField{Name: m[2], Shape: tsShape(m[5])})
+	}
+	return shape
+}
+
+func tsShape(expr string) *scaffoldShape {
+	expr = strings.TrimSpace(expr)
+	var options []string
+	for _, opt := range tsSplit(expr, "|") {
+		opt = strings.TrimSpace(opt)
+		if opt != "" && opt != "null" && opt != "undefined" {
+			options = append(options, opt)
+		}
+	}
+	if len(options) == 0 {
+		return &scaffoldShape{Kind: "null"}
+	}
+	expr = options[0]
+	if len(tsSplit(expr, "&")) > 1 {
+		shape := &scaffoldShape{Kind: "object"}
+		for _, part := range tsSplit(expr, "&") {
+			shape.Fields = append(shape.Fields, scaffoldField{Shape: tsShape(part)})
+		}
+		return shape
+	}
+
+	switch {
+	case strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}"):
+		return tsObject(expr[1 : len(expr)-1])
+	case strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")"):
+		return tsShape(expr[1 : len(expr)-1])
+	case strings.HasSuffix(expr, "[]"):
+		return &scaffoldShape{Kind: "array", Elem: tsShape(strings.TrimSuffix(expr, "[]"))}
+	case strings.HasPrefix(expr, "[") && strings.HasSuffix(expr, "]"):
+		first := tsSplit(expr[1:len(expr)-1], ",")[0]
+		return &scaffoldShape{Kind: "array", Elem: tsShape(first)}
+	case (strings.HasPrefix(expr, "Array<") || strings.HasPrefix(expr, "ReadonlyArray<")) && strings.HasSuffix(expr, ">"):
+		_, inner, _ := strings.Cut(expr[:len(expr)-1], "<")
+		return &scaffoldShape{Kind: "array", Elem: tsShape(inner)}
+	case strings.HasPrefix(expr, "Record<"):
+		return &scaffoldShape{Kind: "object"}
+	}
+	if lit := tsLiteral(expr); lit != nil {
+		return &scaffoldShape{Kind: "literal", Literal: lit}
+	}
+	switch expr {
+	case "string":
+		return &scaffoldShape{Kind: "string"}
+	case "number":
+		return &scaffoldShape{Kind: "number"}
+	case "bigint":
+		return &scaffoldShape{Kind: "int"}
+	case "boolean":
+		return &scaffoldShape{Kind: "bool"}
+	case "Date":
+		return &scaffoldShape{Kind: "time"}
+	}
+	if tsIdentRe.MatchString(expr) {
+		return &scaffoldShape{Kind: "ref", Ref: expr}
+	}
+	return &scaffoldShape{Kind: "null"}
+}
+
+// tsLiteral returns the value of a string, number or boolean literal type,
+// or nil.
+func tsLiteral(expr string) any {
+	if len(expr) >= 2 && (expr[0] == '"' || expr[0] == '\'') && expr[len(expr)-1] == expr[0] {
+		return expr[1 : len(expr)-1]
+	}
+	switch expr {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if n, err := strconv.ParseInt(expr, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(expr, 64); err == nil {
+		return f
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupScaffoldSeed(t *testing.T, source, content string) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	if err := InitSeed(dir, "auth"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, source), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func readSeed(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, ".ptsd", "seeds", "auth", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestScaffoldSeedsFromGo(t *testing.T) {
+	dir := setupScaffoldSeed(t, "models.go", `package models
+
+import "time"
+
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+)
+
+type Base struct {
+	ID        int64     `+"`json:\"id\"`"+`
+	CreatedAt time.Time `+"`json:\"created_at\"`"+`
+}
+
+type Address struct {
+	City    string
+	Country string `+"`json:\"country,omitempty\"`"+`
+}
+
+type User struct {
+	Base
+	Email    string            `+"`json:\"email\"`"+`
+	FullName string            `+"`json:\"full_name\"`"+`
+	Role     Role              `+"`json:\"role\"`"+`
+	Active   bool              `+"`json:\"active\"`"+`
+	Address  *Address          `+"`json:\"address\"`"+`
+	Tags     []string          `+"`json:\"tags\"`"+`
+	Meta     map[string]string `+"`json:\"meta\"`"+`
+	Manager  *User             `+"`json:\"manager\"`"+`
+	password string
+	Secret   string `+"`json:\"-\"`"+`
+}
+`)
+	result, err := ScaffoldSeeds(dir, "auth", "models.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Files) != 1 || result.Files[0].File != "user.json" || result.Files[0].Type != "User" || result.Files[0].Fields != 10 {
+		t.Fatalf("expected user.json only, got %+v", result.Files)
+	}
+
+	got := readSeed(t, dir, "user.json")
+	want := `[
+  {
+    "id": 1,
+    "created_at": "2024-01-15T09:30:00Z",
+    "email": "ann.lee@example.com",
+    "full_name": "Ann Lee",
+    "role": "admin",
+    "active": true,
+    "address": {
+      "City": "Berlin",
+      "country": "US"
+    },
+    "tags": [
+      "example tags"
+    ],
+    "meta": {},
+    "manager": null
+  }
+]
+`
+	if got != want {
+		t.Errorf("unexpected seed:\n%s", got)
+	}
+	manifest := readSeed(t, dir, "seed.yaml")
+	if !strings.Contains(manifest, "- path: user.json\n    type: data\n    description: \"skeleton of User from models.go\"") {
+		t.Errorf("expected manifest entry, got:\n%s", manifest)
+	}
+	if v, err := ValidateSeed(dir, "auth"); err != nil || len(v.Problems) != 0 {
+		t.Errorf("expected scaffold to validate, got %v %+v", err, v.Problems)
+	}
+}
+
+func TestScaffoldSeedsFromTypeScript(t *testing.T) {
+	dir := setupScaffoldSeed(t, "types.ts", `// Domain types.
+export enum Status { Open = "open", Closed = "closed" }
+
+export interface Entity {
+  readonly id: string;
+}
+
+/* An order line. */
+export interface OrderItem {
+  sku: string
+  quantity: number
+  unitPrice: number
+}
+
+export interface Order extends Entity {
+  status: Status;
+  items: OrderItem[];
+  note?: string | null;
+  placedAt: Date;
+  shipping: { city: string; zip: string };
+  channel: "web" | "store";
+  total(): number;
+  [key: string]: unknown;
+}
+
+export type Coupon = {
+  code: string,
+  percent: number,
+}
+`)
+	result, err := ScaffoldSeeds(dir, "auth", "types.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, f := range result.Files {
+		files = append(files, f.File+"="+f.Type)
+	}
+	if strings.Join(files, " ") != "order.json=Order coupon.json=Coupon" {
+		t.Fatalf("unexpected files: %v", files)
+	}
+
+	got := readSeed(t, dir, "order.json")
+	for _, want := range []string{
+		`"id": "7c9e6679-7425-40de-944b-e07072f3a1b1"`,
+		`"status": "open"`,
+		`"sku": "example sku"`,
+		`"quantity": 3`,
+		`"unitPrice": 19.99`,
+		`"note": "example note"`,
+		`"placedAt": "2024-01-15T09:30:00Z"`,
+		`"city": "Berlin"`,
+		`"channel": "web"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s in order.json:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "total") || strings.Contains(got, "key") {
+		t.Errorf("methods and index signatures should be skipped:\n%s", got)
+	}
+	if got := readSeed(t, dir, "coupon.json"); !strings.Contains(got, `"percent": 0.25`) {
+		t.Errorf("unexpected coupon.json:\n%s", got)
+	}
+}
+
+func TestScaffoldSeedsKeepsExistingFiles(t *testing.T) {
+	dir := setupScaffoldSeed(t, "models.go", "package models\n\ntype User struct {\n\tName string\n}\n")
+	existing := filepath.Join(dir, ".ptsd", "seeds", "auth", "user.json")
+	if err := os.WriteFile(existing, []byte(`[{"Name": "Real"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ScaffoldSeeds(dir, "auth", "models.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Files) != 1 || !result.Files[0].Skipped {
+		t.Fatalf("expected skipped file, got %+v", result.Files)
+	}
+	if got := readSeed(t, dir, "user.json"); got != `[{"Name": "Real"}]` {
+		t.Errorf("existing seed overwritten: %s", got)
+	}
+}
+
+func TestScaffoldSeedsErrors(t *testing.T) {
+	dir := setupScaffoldSeed(t, "consts.go", "package models\n\nconst Max = 3\n")
+	if _, err := ScaffoldSeeds(dir, "auth", "consts.go"); err == nil || !strings.HasPrefix(err.Error(), "err:validation no struct") {
+		t.Errorf("expected no-types error, got %v", err)
+	}
+	if _, err := ScaffoldSeeds(dir, "auth", "schema.sql"); err == nil || !strings.HasPrefix(err.Error(), "err:") {
+		t.Errorf("expected error for unsupported file, got %v", err)
+	}
+	if _, err := ScaffoldSeeds(dir, "other", "consts.go"); err == nil || !strings.Contains(err.Error(), "seed not initialized") {
+		t.Errorf("expected seed-not-initialized error, got %v", err)
+	}
+}
+
+func TestNameWords(t *testing.T) {
+	for in, want := range map[string]string{
+		"createdAt":  "created at",
+		"created_at": "created at",
+		"HTTPCode":   "http code",
+		"OrderItem":  "order item",
+		"userID":     "user id",
+	} {
+		if got := strings.Join(nameWords(in), " "); got != want {
+			t.Errorf("nameWords(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SeedSnapshotOptions selects what `ptsd seed snapshot` captures: a shell
+// command's stdout, or the response body of a seed_requests.<name> entry.
+type SeedSnapshotOptions struct {
+	Command string
+	Request string
+	Type    string // data|fixture; defaults to fixture
+}
+
+// SeedSnapshot reports a captured seed file.
+type SeedSnapshot struct {
+	Path   string // relative to the feature's seed directory
+	Hash   string // sha256 of the content, also recorded in seed.yaml
+	Bytes  int
+	Source string
+}
+
+// SnapshotSeed runs the command or request in opts, stores its output as
+// .ptsd/seeds/<feature>/<name> and records it in seed.yaml with its source
+// and checksum. Re-running refreshes the file and its entry in place.
+func SnapshotSeed(projectDir, featureID, name string, opts SeedSnapshotOptions) (SeedSnapshot, error) {
+	if opts.Type == "" {
+		opts.Type = "fixture"
+	}
+	if opts.Type != "data" && opts.Type != "fixture" {
+		return SeedSnapshot{}, fmt.Errorf("err:validation invalid seed snapshot type %q: must be data|fixture", opts.Type)
+	}
+	if name == "" || name != filepath.Base(name) || name == "seed.yaml" {
+		return SeedSnapshot{}, fmt.Errorf("err:user invalid seed file name %q", name)
+	}
+	if (opts.Command == "") == (opts.Request == "") {
+		return SeedSnapshot{}, fmt.Errorf("err:user seed snapshot needs either a command or --request <name>")
+	}
+
+	seedDir := seedDirPath(projectDir, featureID)
+	manifestPath := filepath.Join(seedDir, "seed.yaml")
+	manifest, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return SeedSnapshot{}, fmt.Errorf("err:validation seed not initialized for %s", featureID)
+	}
+	if err != nil {
+		return SeedSnapshot{}, fmt.Errorf("err:io %w", err)
+	}
+
+	var out []byte
+	var source string
+	if opts.Command != "" {
+		source = opts.Command
+		out, err = captureCommand(projectDir, opts.Command)
+	} else {
+		source = "request:" + opts.Request
+		out, err = captureRequest(projectDir, opts.Request)
+	}
+	if err != nil {
+		return SeedSnapshot{}, err
+	}
+
+	if err := writeFileAtomic(filepath.Join(seedDir, name), out, 0644); err != nil {
+		return SeedSnapshot{}, fmt.Errorf("err:io %w", err)
+	}
+	snap := SeedSnapshot{Path: name, Hash: contentHash(out), Bytes: len(out), Source: source}
+	content := setSeedManifestEntry(string(manifest), name, opts.Type, snap.Source, snap.Hash)
+	if err := writeFileAtomic(manifestPath, []byte(content), 0644); err != nil {
+		return SeedSnapshot{}, fmt.Errorf("err:io %w", err)
+	}
+	return snap, nil
+}
+
+func captureCommand(projectDir, command string) ([]byte, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = projectDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("err:io seed snapshot command failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+func captureRequest(projectDir, name string) ([]byte, error) {
+	cfg, err := LoadConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := cfg.SeedRequests[name]
+	if !ok || r.URL == "" {
+		return nil, fmt.Errorf("err:config seed_requests.%s.url not set in .ptsd/ptsd.yaml", name)
+	}
+	if err := CheckNetwork(projectDir, "seed request "+name); err != nil {
+		return nil, err
+	}
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if r.Body != "" {
+		body = strings.NewReader(r.Body)
+	}
+	req, err := http.NewRequest(method, r.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("err:config seed_requests.%s: %v", name, err)
+	}
+	if r.TokenEnv != "" {
+		if token := os.Getenv(r.TokenEnv); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("err:io seed request %s: %v", name, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("err:io seed request %s: %v", name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("err:io seed request %s: %s", name, resp.Status)
+	}
+	return data, nil
+}
+
+// setSeedManifestEntry adds or replaces the seed.yaml entry for path.
+func setSeedManifestEntry(manifest, path, typ, source, hash string) string {
+	entry := []string{
+		"  - path: " + path,
+		"    type: " + typ,
+		"    source: " + quoteFeatureField(source),
+		"    sha256: " + hash,
+	}
+
+	lines := strings.Split(strings.TrimRight(manifest, "\n"), "\n")
+	var out []string
+	replaced := false
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "- path: "+path {
+			out = append(out, entry...)
+			replaced = true
+			for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "    ") {
+				i++
+			}
+			continue
+		}
+		out = append(out, lines[i])
+	}
+	if !replaced {
+		out = append(out, entry...)
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// seedChecksums returns the sha256 recorded per file in a seed manifest.
+func seedChecksums(manifest string) map[string]string {
+	sums := make(map[string]string)
+	current := ""
+	for _, line := range strings.Split(manifest, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "- path: "):
+			current = strings.TrimSpace(strings.TrimPrefix(trimmed, "- path: "))
+		case strings.HasPrefix(trimmed, "sha256: ") && current != "":
+			sums[current] = strings.TrimSpace(strings.TrimPrefix(trimmed, "sha256: "))
+		}
+	}
+	return sums
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotSeedCommand(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	if err := InitSeed(dir, "user-auth"); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := SnapshotSeed(dir, "user-auth", "users.json", SeedSnapshotOptions{Command: `printf '{"id": 1}'`})
+	if err != nil {
+		t.Fatalf("SnapshotSeed failed: %v", err)
+	}
+	if snap.Bytes != 9 || snap.Hash != contentHash([]byte(`{"id": 1}`)) {
+		t.Errorf("unexpected snapshot: %+v", snap)
+	}
+	seedDir := filepath.Join(dir, ".ptsd", "seeds", "user-auth")
+	if data, _ := os.ReadFile(filepath.Join(seedDir, "users.json")); string(data) != `{"id": 1}` {
+		t.Errorf("captured content = %q", data)
+	}
+
+	// Re-snapshot replaces the manifest entry instead of appending.
+	if _, err := SnapshotSeed(dir, "user-auth", "users.json", SeedSnapshotOptions{Command: `printf '{"id": 2}'`, Type: "data"}); err != nil {
+		t.Fatal(err)
+	}
+	manifest, _ := os.ReadFile(filepath.Join(seedDir, "seed.yaml"))
+	if strings.Count(string(manifest), "- path: users.json") != 1 {
+		t.Errorf("expected a single entry:\n%s", manifest)
+	}
+	for _, want := range []string{"type: data", `source: "printf '{\"id\": 2}'"`, "sha256: " + contentHash([]byte(`{"id": 2}`))} {
+		if !strings.Contains(string(manifest), want) {
+			t.Errorf("manifest missing %q:\n%s", want, manifest)
+		}
+	}
+
+	if _, err := CheckSeeds(dir); err != nil {
+		t.Errorf("fresh snapshot should pass CheckSeeds: %v", err)
+	}
+	os.WriteFile(filepath.Join(seedDir, "users.json"), []byte(`{"id": 3}`), 0644)
+	if _, err := CheckSeeds(dir); err == nil || !strings.Contains(err.Error(), "sha256") {
+		t.Errorf("hand-edited snapshot should fail CheckSeeds, got %v", err)
+	}
+	findings, _ := Lint(dir)
+	drift := false
+	for _, f := range findings {
+		drift = drift || (f.Check == "seed" && strings.Contains(f.Message, "sha256"))
+	}
+	if !drift {
+		t.Errorf("lint should report checksum drift, got %+v", findings)
+	}
+}
+
+func TestSnapshotSeedCommandFailure(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "user-auth:in-progress")
+	InitSeed(dir, "user-auth")
+
+	_, err := SnapshotSeed(dir, "user-auth", "out.txt", SeedSnapshotOptions{Command: "echo boom >&2; exit 3"})
+	if err == nil || !strings.HasPrefix(err.Error(), "err:io") || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected err:io with stderr, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, ".ptsd", "seeds", "user-auth", "out.txt")); statErr == nil {
+		t.Error("failed command must not write a seed file")
+	}
+}
+
+func TestSnapshotSeedRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`[{"sku": "A-1"}]`))
+	}))
+	defer srv.Close()
+	t.Setenv("SEED_TOKEN", "s3cret")
+
+	dir := setupProjectWithFeatures(t, "catalog:in-progress")
+	InitSeed(dir, "catalog")
+	cfg := "seed_requests:\n  products:\n    url: " + srv.URL + "/products\n    method: post\n    token_env: SEED_TOKEN\n"
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte(cfg), 0644)
+
+	snap, err := SnapshotSeed(dir, "catalog", "products.json", SeedSnapshotOptions{Request: "products"})
+	if err != nil {
+		t.Fatalf("SnapshotSeed failed: %v", err)
+	}
+	if snap.Source != "request:products" {
+		t.Errorf("source = %q", snap.Source)
+	}
+
+	if _, err := SnapshotSeed(dir, "catalog", "x.json", SeedSnapshotOptions{Request: "missing"}); err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Errorf("expected err:config for unknown request, got %v", err)
+	}
+}
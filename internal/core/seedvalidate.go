@@ -0,0 +1,543 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Seed validation. `ptsd seed validate <feature>` checks that every file in
+// seed.yaml exists and parses as its format (by extension: .json, .yaml/.yml,
+// .csv; other files are only checked for existence), and validates JSON
+// files against a JSON Schema named by the entry's `schema:` field:
+//
+//	files:
+//	  - path: users.json
+//	    type: data
+//	    schema: users.schema.json
+//
+// Schemas support the commonly used keywords: type, enum, const, required,
+// properties, additionalProperties, items, min/maxItems, min/maxLength,
+// pattern, minimum, maximum, exclusiveMinimum/Maximum, allOf, anyOf, oneOf
+// and local $ref (#/definitions/..., #/$defs/...).
+
+// SeedProblem is one problem with a seed file.
+type SeedProblem struct {
+	Feature string
+	File    string
+	Message string
+}
+
+// SeedValidation is the result of validating one feature's seeds.
+type SeedValidation struct {
+	Feature  string
+	Files    int
+	Problems []SeedProblem
+}
+
+// seedSchemas returns the schema file named per seed file in a manifest.
+func seedSchemas(manifest string) map[string]string {
+	schemas := make(map[string]string)
+	current := ""
+	for _, line := range strings.Split(manifest, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "- path: "):
+			current = strings.TrimSpace(strings.TrimPrefix(trimmed, "- path: "))
+		case strings.HasPrefix(trimmed, "schema: ") && current != "":
+			schemas[current] = stripQuotes(strings.TrimSpace(strings.TrimPrefix(trimmed, "schema: ")))
+		}
+	}
+	return schemas
+}
+
+// ValidateSeed checks featureID's seed files: presence, format and schema.
+func ValidateSeed(projectDir, featureID string) (SeedValidation, error) {
+	result := SeedValidation{Feature: featureID}
+	seedDir := seedDirPath(projectDir, featureID)
+	data, err := os.ReadFile(filepath.Join(seedDir, "seed.yaml"))
+	if os.IsNotExist(err) {
+		return result, fmt.Errorf("err:validation seed not initialized for %s", featureID)
+	}
+	if err != nil {
+		return result, fmt.Errorf("err:io %w", err)
+	}
+	files := parseSeedManifestFiles(string(data))
+	result.Files = len(files)
+	schemas := seedSchemas(string(data))
+	for _, file := range files {
+		for _, msg := range seedFileProblems(seedDir, file, schemas[file]) {
+			result.Problems = append(result.Problems, SeedProblem{Feature: featureID, File: file, Message: msg})
+		}
+	}
+	return result, nil
+}
+
+// ValidateSeeds validates every feature that has a seed manifest.
+func ValidateSeeds(projectDir string) ([]SeedValidation, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var out []SeedValidation
+	for _, f := range features {
+		if _, err := os.Stat(seedManifestPath(projectDir, f.ID)); err != nil {
+			continue
+		}
+		v, err := ValidateSeed(projectDir, f.ID)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// seedFileProblems checks one manifest entry; schema is the entry's schema
+// file, if any.
+func seedFileProblems(seedDir, file, schema string) []string {
+	content, err := os.ReadFile(filepath.Join(seedDir, file))
+	if os.IsNotExist(err) {
+		return []string{"missing file"}
+	}
+	if err != nil {
+		return []string{"cannot read: " + err.Error()}
+	}
+	if err := checkSeedFormat(file, content); err != nil {
+		return []string{err.Error()}
+	}
+	if schema == "" {
+		return nil
+	}
+	if ext := strings.ToLower(filepath.Ext(file)); ext != ".json" {
+		return []string{"schema " + schema + " given, but schema validation supports JSON seed files only"}
+	}
+	schemaData, err := os.ReadFile(filepath.Join(seedDir, schema))
+	if err != nil {
+		return []string{"schema " + schema + " not found"}
+	}
+	var root map[string]any
+	if err := json.Unmarshal(schemaData, &root); err != nil {
+		return []string{"schema " + schema + " is not a JSON object: " + err.Error()}
+	}
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return []string{err.Error()}
+	}
+	v := &schemaValidator{root: root}
+	v.check(root, doc, "$")
+	return v.problems
+}
+
+// checkSeedFormat parses content as the format its extension names.
+func checkSeedFormat(file string, content []byte) error {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		var v any
+		if err := json.Unmarshal(content, &v); err != nil {
+			if se, ok := err.(*json.SyntaxError); ok {
+				line, col := offsetPosition(content, se.Offset)
+				return fmt.Errorf("invalid JSON at line %d col %d: %s", line, col, se.Error())
+			}
+			return fmt.Errorf("invalid JSON: %s", err.Error())
+		}
+	case ".yaml", ".yml":
+		if err := checkYAMLSyntax(string(content)); err != nil {
+			return err
+		}
+	case ".csv":
+		if _, err := csv.NewReader(bytes.NewReader(content)).ReadAll(); err != nil {
+			return fmt.Errorf("invalid CSV: %s", strings.TrimPrefix(err.Error(), "record on "))
+		}
+	}
+	return nil
+}
+
+// offsetPosition converts a byte offset into a 1-based line and column.
+func offsetPosition(content []byte, offset int64) (int, int) {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// checkYAMLSyntax catches the mistakes that break seed YAML without a full
+// YAML parser: tab indentation, unterminated quotes, unclosed flow
+// collections and lines that are neither "key: value", list items nor
+// block-scalar content.
+func checkYAMLSyntax(content string) error {
+	blockIndent := -1 // indent of a `key: |` line while reading its block
+	for i, line := range strings.Split(content, "\n") {
+		n := i + 1
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if blockIndent >= 0 {
+			if trimmed == "" || indent > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" || trimmed == "..." {
+			continue
+		}
+		if strings.Contains(line[:indent], "\t") {
+			return fmt.Errorf("invalid YAML at line %d: tab in indentation", n)
+		}
+		item := trimmed
+		for strings.HasPrefix(item, "- ") || item == "-" {
+			item = strings.TrimSpace(strings.TrimPrefix(item, "-"))
+		}
+		if item == "" {
+			continue
+		}
+		if err := checkYAMLScalar(item); err != nil {
+			return fmt.Errorf("invalid YAML at line %d: %s", n, err.Error())
+		}
+		_, value, isMap := cutYAMLKey(item)
+		if !isMap {
+			if item == trimmed && !strings.HasPrefix(item, "[") && !strings.HasPrefix(item, "{") && !strings.HasPrefix(item, "\"") && !strings.HasPrefix(item, "'") && i > 0 {
+				// A bare word on its own line is only valid as a
+				// continuation of a multi-line plain scalar, which seeds
+				// do not use.
+				return fmt.Errorf("invalid YAML at line %d: expected \"key: value\" or \"- item\", got %q", n, trimmed)
+			}
+			continue
+		}
+		if v := strings.TrimSpace(value); strings.HasPrefix(v, "|") || strings.HasPrefix(v, ">") {
+			blockIndent = indent
+			if item != trimmed {
+				blockIndent = indent + (len(trimmed) - len(item))
+			}
+		}
+	}
+	return nil
+}
+
+// cutYAMLKey splits "key: value" outside quotes; isMap is false when item
+// is not a mapping entry.
+func cutYAMLKey(item string) (key, value string, isMap bool) {
+	if strings.HasPrefix(item, "[") || strings.HasPrefix(item, "{") {
+		return "", "", false
+	}
+	quote := byte(0)
+	for i := 0; i < len(item); i++ {
+		c := item[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 {
+				quote = c
+			}
+		case c == ':' && (i+1 == len(item) || item[i+1] == ' '):
+			return item[:i], item[i+1:], true
+		case c == '#' && i > 0 && item[i-1] == ' ':
+			return "", "", false
+		}
+	}
+	return "", "", false
+}
+
+// checkYAMLScalar reports unterminated quotes and unbalanced flow brackets
+// on one line.
+func checkYAMLScalar(s string) error {
+	var stack []byte
+	quote := byte(0)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			switch {
+			case quote == '"' && c == '\\':
+				i++
+			case quote == '\'' && c == '\'' && i+1 < len(s) && s[i+1] == '\'':
+				i++
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			// Quotes open a scalar only at its start, not inside a word
+			// like "don't".
+			if i == 0 || strings.ContainsRune(" [{,:", rune(s[i-1])) {
+				quote = c
+			}
+		case '#':
+			if i > 0 && s[i-1] == ' ' {
+				s = s[:i]
+			}
+		case '[', '{':
+			if len(stack) > 0 || i == 0 || strings.HasSuffix(strings.TrimRight(s[:i], " "), ":") || strings.HasSuffix(strings.TrimRight(s[:i], " "), "-") {
+				stack = append(stack, c)
+			}
+		case ']', '}':
+			if len(stack) == 0 {
+				continue
+			}
+			open := stack[len(stack)-1]
+			if (open == '[') != (c == ']') {
+				return fmt.Errorf("mismatched %q", string(c))
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if quote != 0 {
+		return fmt.Errorf("unterminated %s-quoted string", map[byte]string{'"': "double", '\'': "single"}[quote])
+	}
+	if len(stack) > 0 {
+		return fmt.Errorf("unclosed %q", string(stack[len(stack)-1]))
+	}
+	return nil
+}
+
+// schemaValidator checks a JSON document against a JSON Schema subset.
+type schemaValidator struct {
+	root     map[string]any
+	problems []string
+}
+
+func (v *schemaValidator) fail(path, format string, args ...any) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+// resolve follows a local $ref.
+func (v *schemaValidator) resolve(schema map[string]any) map[string]any {
+	for depth := 0; depth < 32; depth++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return schema
+		}
+		target := any(v.root)
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			m, ok := target.(map[string]any)
+			if !ok {
+				return map[string]any{}
+			}
+			target = m[part]
+		}
+		next, ok := target.(map[string]any)
+		if !ok {
+			return map[string]any{}
+		}
+		schema = next
+	}
+	return schema
+}
+
+// jsonType names the JSON Schema type of a decoded value.
+func jsonType(x any) string {
+	switch t := x.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return "integer"
+		}
+		if f, err := t.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+	return "unknown"
+}
+
+func schemaNumber(x any) (float64, bool) {
+	switch t := x.(type) {
+	case float64:
+		return t, true
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// check validates doc against schema; path is a JSONPath-like location.
+func (v *schemaValidator) check(schema map[string]any, doc any, path string) {
+	schema = v.resolve(schema)
+	if len(v.problems) > 50 {
+		return
+	}
+
+	if t, ok := schema["type"]; ok {
+		var allowed []string
+		switch tt := t.(type) {
+		case string:
+			allowed = []string{tt}
+		case []any:
+			for _, a := range tt {
+				if s, ok := a.(string); ok {
+					allowed = append(allowed, s)
+				}
+			}
+		}
+		actual := jsonType(doc)
+		match := false
+		for _, a := range allowed {
+			match = match || a == actual || (a == "number" && actual == "integer")
+		}
+		if !match {
+			v.fail(path, "expected %s, got %s", strings.Join(allowed, " or "), actual)
+			return
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			found = found || jsonEqual(e, doc)
+		}
+		if !found {
+			v.fail(path, "value %s is not one of the allowed values", compactJSON(doc))
+		}
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(c, doc) {
+		v.fail(path, "value %s must be %s", compactJSON(doc), compactJSON(c))
+	}
+
+	switch t := doc.(type) {
+	case map[string]any:
+		v.checkObject(schema, t, path)
+	case []any:
+		if n, ok := schemaNumber(schema["minItems"]); ok && float64(len(t)) < n {
+			v.fail(path, "has %d items, fewer than minItems %v", len(t), n)
+		}
+		if n, ok := schemaNumber(schema["maxItems"]); ok && float64(len(t)) > n {
+			v.fail(path, "has %d items, more than maxItems %v", len(t), n)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range t {
+				v.check(items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case string:
+		length := len([]rune(t))
+		if n, ok := schemaNumber(schema["minLength"]); ok && float64(length) < n {
+			v.fail(path, "is shorter than minLength %v", n)
+		}
+		if n, ok := schemaNumber(schema["maxLength"]); ok && float64(length) > n {
+			v.fail(path, "is longer than maxLength %v", n)
+		}
+		if p, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(p); err != nil {
+				v.fail(path, "schema pattern %q does not compile", p)
+			} else if !re.MatchString(t) {
+				v.fail(path, "%q does not match pattern %q", t, p)
+			}
+		}
+	case json.Number:
+		f, _ := t.Float64()
+		if n, ok := schemaNumber(schema["minimum"]); ok && f < n {
+			v.fail(path, "%s is less than minimum %v", t, n)
+		}
+		if n, ok := schemaNumber(schema["maximum"]); ok && f > n {
+			v.fail(path, "%s is greater than maximum %v", t, n)
+		}
+		if n, ok := schemaNumber(schema["exclusiveMinimum"]); ok && f <= n {
+			v.fail(path, "%s must be greater than %v", t, n)
+		}
+		if n, ok := schemaNumber(schema["exclusiveMaximum"]); ok && f >= n {
+			v.fail(path, "%s must be less than %v", t, n)
+		}
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, s := range all {
+			if sub, ok := s.(map[string]any); ok {
+				v.check(sub, doc, path)
+			}
+		}
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		list, ok := schema[key].([]any)
+		if !ok {
+			continue
+		}
+		matches := 0
+		for _, s := range list {
+			sub, ok := s.(map[string]any)
+			if !ok {
+				continue
+			}
+			probe := &schemaValidator{root: v.root}
+			probe.check(sub, doc, path)
+			if len(probe.problems) == 0 {
+				matches++
+			}
+		}
+		switch {
+		case matches == 0:
+			v.fail(path, "matches none of the %s schemas", key)
+		case key == "oneOf" && matches > 1:
+			v.fail(path, "matches %d oneOf schemas, expected exactly 1", matches)
+		}
+	}
+}
+
+func (v *schemaValidator) checkObject(schema map[string]any, obj map[string]any, path string) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, present := obj[name]; !present {
+					v.fail(path, "missing required property %q", name)
+				}
+			}
+		}
+	}
+	props, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if sub, ok := props[k].(map[string]any); ok {
+			v.check(sub, obj[k], path+"."+k)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				v.fail(path, "unexpected property %q", k)
+			}
+		case map[string]any:
+			v.check(extra, obj[k], path+"."+k)
+		}
+	}
+}
+
+func jsonEqual(a, b any) bool {
+	return compactJSON(a) == compactJSON(b)
+}
+
+func compactJSON(x any) string {
+	if n, ok := x.(json.Number); ok {
+		f, _ := n.Float64()
+		x = f
+	}
+	out, _ := json.Marshal(x)
+	return string(out)
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const userSchema = `{
+  "type": "array",
+  "minItems": 1,
+  "items": {"$ref": "#/$defs/user"},
+  "$defs": {
+    "user": {
+      "type": "object",
+      "required": ["id", "email"],
+      "additionalProperties": false,
+      "properties": {
+        "id": {"type": "integer", "minimum": 1},
+        "email": {"type": "string", "pattern": "^[^@]+@[^@]+$"},
+        "role": {"enum": ["admin", "member"]}
+      }
+    }
+  }
+}`
+
+func writeSeedFiles(t *testing.T, dir, feature, manifest string, files map[string]string) {
+	t.Helper()
+	seedDir := filepath.Join(dir, ".ptsd", "seeds", feature)
+	if err := os.MkdirAll(seedDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files["seed.yaml"] = manifest
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(seedDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func seedMessages(v SeedValidation) string {
+	var out []string
+	for _, p := range v.Problems {
+		out = append(out, p.File+": "+p.Message)
+	}
+	return strings.Join(out, "\n")
+}
+
+func TestValidateSeedValid(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	manifest := "feature: auth\nfiles:\n  - path: users.json\n    type: data\n    schema: users.schema.json\n  - path: users.schema.json\n    type: schema\n  - path: roles.yaml\n    type: data\n  - path: logins.csv\n    type: fixture\n"
+	writeSeedFiles(t, dir, "auth", manifest, map[string]string{
+		"users.json":        `[{"id": 1, "email": "ann@example.com", "role": "admin"}, {"id": 2, "email": "bob@example.com"}]`,
+		"users.schema.json": userSchema,
+		"roles.yaml":        "# roles\nroles:\n  - name: admin\n    note: \"can't be removed\"\n    tags: [a, b]\n  - name: member\n    description: |\n      Plain: member\n      no key here\n",
+		"logins.csv":        "user,attempts\nann,1\nbob,3\n",
+	})
+
+	v, err := ValidateSeed(dir, "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Files != 4 || len(v.Problems) != 0 {
+		t.Fatalf("expected 4 clean files, got files=%d problems:\n%s", v.Files, seedMessages(v))
+	}
+}
+
+func TestValidateSeedProblems(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	manifest := "feature: auth\nfiles:\n  - path: users.json\n    schema: users.schema.json\n  - path: broken.json\n  - path: roles.yaml\n  - path: logins.csv\n  - path: gone.json\n"
+	writeSeedFiles(t, dir, "auth", manifest, map[string]string{
+		"users.json":        `[{"id": 0, "email": "nope", "role": "root", "extra": true}, {"email": "a@b.c"}]`,
+		"users.schema.json": userSchema,
+		"broken.json":       "{\n  \"id\": 1,\n  \"name\": \"x\"\n  \"oops\": 2\n}\n",
+		"roles.yaml":        "roles:\n\t- name: admin\n",
+		"logins.csv":        "user,attempts\nann,1,extra\n",
+	})
+
+	v, err := ValidateSeed(dir, "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := seedMessages(v)
+	for _, want := range []string{
+		"users.json: $[0].email: \"nope\" does not match pattern",
+		"users.json: $[0].id: 0 is less than minimum 1",
+		"users.json: $[0].role: value \"root\" is not one of the allowed values",
+		"users.json: $[0]: unexpected property \"extra\"",
+		"users.json: $[1]: missing required property \"id\"",
+		"broken.json: invalid JSON at line 4 col 4",
+		"roles.yaml: invalid YAML at line 2: tab in indentation",
+		"logins.csv: invalid CSV",
+		"gone.json: missing file",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in problems:\n%s", want, got)
+		}
+	}
+}
+
+func TestCheckYAMLSyntax(t *testing.T) {
+	for content, want := range map[string]string{
+		"name: \"unterminated\n":                "unterminated double-quoted string",
+		"tags: [a, b\n":                         "unclosed \"[\"",
+		"users:\n  - name: ann\n  just words\n": "expected \"key: value\"",
+		"key: value\nother: {a: 1}\n":           "",
+	} {
+		err := checkYAMLSyntax(content)
+		switch {
+		case want == "" && err != nil:
+			t.Errorf("checkYAMLSyntax(%q) = %v, want nil", content, err)
+		case want != "" && (err == nil || !strings.Contains(err.Error(), want)):
+			t.Errorf("checkYAMLSyntax(%q) = %v, want %q", content, err, want)
+		}
+	}
+}
+
+func TestLintReportsBrokenSeedData(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	writeSeedFiles(t, dir, "auth", "feature: auth\nfiles:\n  - path: users.json\n", map[string]string{"users.json": "{oops"})
+	findings, err := Lint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range findings {
+		if f.Check == "seed" && f.Severity == "error" && strings.HasPrefix(f.Message, "seed users.json: invalid JSON") {
+			return
+		}
+	}
+	t.Errorf("expected a seed error for broken JSON, got %+v", findings)
+}
@@ -0,0 +1,254 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tamper evidence (audit.sign: true). Every event appended to events.yaml
+// carries sig = HMAC(key, previous sig + event), chaining the log, and each
+// write of state.yaml / review-status.yaml records an HMAC of the file in
+// .ptsd/signatures.yaml. The key lives outside the repo, so an edit made
+// without it — by hand or by an agent — no longer verifies.
+
+// EventTamper records that a signed file changed outside ptsd. It is written
+// before ptsd re-signs the file, so the evidence survives the next update.
+const EventTamper = "tamper"
+
+// signingKeyEnv overrides the key location (a file path).
+const signingKeyEnv = "PTSD_SIGNING_KEY"
+
+// signedFiles are the .ptsd files whose content is signed on every write.
+var signedFiles = []string{"state.yaml", "review-status.yaml", reviewLogFile}
+
+func signaturesPath(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "signatures.yaml")
+}
+
+// SigningKeyPath returns where the HMAC key is kept: $PTSD_SIGNING_KEY, or
+// ptsd/signing.key under the user config directory.
+func SigningKeyPath() (string, error) {
+	if p := os.Getenv(signingKeyEnv); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("err:config cannot locate signing key: %v", err)
+	}
+	return filepath.Join(dir, "ptsd", "signing.key"), nil
+}
+
+// signingKey loads the key, generating a random one on first use when create is set.
+func signingKey(create bool) ([]byte, error) {
+	path, err := SigningKeyPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, decErr := hex.DecodeString(strings.TrimSpace(string(data)))
+		if decErr != nil || len(key) == 0 {
+			return nil, fmt.Errorf("err:config invalid signing key in %s", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	if !create {
+		return nil, fmt.Errorf("err:config no signing key at %s", path)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	if err := writeFileAtomic(path, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+	return key, nil
+}
+
+// signingEnabled reports whether the project has audit.sign: true.
+func signingEnabled(projectDir string) bool {
+	cfg, err := LoadConfig(projectDir)
+	return err == nil && cfg.Audit.Sign
+}
+
+func hmacHex(key []byte, parts ...string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// canonicalEvent is the signed form of an event: its fields as stored.
+func canonicalEvent(e Event) string {
+	return strings.Join([]string{
+		e.At.UTC().Format(time.RFC3339Nano), e.Type, e.Feature, e.Stage, e.Task,
+		strconv.Itoa(e.Score), e.Detail,
+	}, "|")
+}
+
+func loadSignatures(projectDir string) map[string]string {
+	sigs := make(map[string]string)
+	data, err := os.ReadFile(signaturesPath(projectDir))
+	if err != nil {
+		return sigs
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), ": "); ok {
+			sigs[key] = value
+		}
+	}
+	return sigs
+}
+
+func saveSignatures(projectDir string, sigs map[string]string) error {
+	var b strings.Builder
+	for _, k := range sortedKeys(sigs) {
+		b.WriteString(k + ": " + sigs[k] + "\n")
+	}
+	if err := writeFileAtomic(signaturesPath(projectDir), []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// signEvent fills e.Sig, chaining it to the last signed event, and records
+// the new chain head. Called by AppendEvent before the entry is written.
+func signEvent(projectDir string, e *Event) error {
+	key, err := signingKey(true)
+	if err != nil {
+		return err
+	}
+	sigs := loadSignatures(projectDir)
+	e.Sig = hmacHex(key, sigs["events_head"], canonicalEvent(*e))
+	sigs["events_head"] = e.Sig
+	return saveSignatures(projectDir, sigs)
+}
+
+// signFileWrite writes content to .ptsd/<name> and records its signature.
+// If the file on disk no longer matches its last signature, a tamper event is
+// logged first.
+func signFileWrite(projectDir string, name string, content []byte) error {
+	path := filepath.Join(projectDir, ".ptsd", name)
+	if !signingEnabled(projectDir) {
+		return writeFileAtomic(path, content, 0644)
+	}
+	key, err := signingKey(true)
+	if err != nil {
+		return err
+	}
+
+	if prev, ok := loadSignatures(projectDir)[name]; ok {
+		if current, err := os.ReadFile(path); err == nil && hmacHex(key, string(current)) != prev {
+			recordEvent(projectDir, Event{Type: EventTamper, Detail: name + " changed outside ptsd"})
+		}
+	}
+
+	if err := writeFileAtomic(path, content, 0644); err != nil {
+		return err
+	}
+	sigs := loadSignatures(projectDir)
+	sigs[name] = hmacHex(key, string(content))
+	return saveSignatures(projectDir, sigs)
+}
+
+// dropSignature forgets the signature of a signed file that was removed, so
+// verify-log does not report it missing.
+func dropSignature(projectDir, name string) error {
+	sigs := loadSignatures(projectDir)
+	if _, ok := sigs[name]; !ok {
+		return nil
+	}
+	delete(sigs, name)
+	return saveSignatures(projectDir, sigs)
+}
+
+// VerifyFinding is one problem found by VerifyLog.
+type VerifyFinding struct {
+	Subject string // "events.yaml #<n>" or a signed file name
+	Message string
+}
+
+// VerifyResult summarizes VerifyLog.
+type VerifyResult struct {
+	Events   int
+	Signed   int
+	Findings []VerifyFinding
+}
+
+// VerifyLog checks the event chain and the signed files against the local
+// key. Events before the first signed entry (history from before signing was
+// enabled) are accepted; an unsigned or mismatching entry after it, a chain
+// head that is not the last entry, a signed file whose content changed, and
+// recorded tamper events are all reported.
+func VerifyLog(projectDir string) (VerifyResult, error) {
+	var result VerifyResult
+	key, err := signingKey(false)
+	if err != nil {
+		return result, err
+	}
+
+	events, err := LoadEvents(projectDir)
+	if err != nil {
+		return result, err
+	}
+	result.Events = len(events)
+
+	prev := ""
+	signing := false
+	for i, e := range events {
+		subject := "events.yaml #" + strconv.Itoa(i+1)
+		if e.Type == EventTamper {
+			result.Findings = append(result.Findings, VerifyFinding{Subject: subject, Message: "recorded tamper: " + e.Detail})
+		}
+		if e.Sig == "" {
+			if signing {
+				result.Findings = append(result.Findings, VerifyFinding{Subject: subject, Message: "unsigned entry in signed log"})
+			}
+			continue
+		}
+		signing = true
+		result.Signed++
+		if hmacHex(key, prev, canonicalEvent(e)) != e.Sig {
+			result.Findings = append(result.Findings, VerifyFinding{Subject: subject, Message: "signature mismatch (entry edited, or one before it removed)"})
+		}
+		prev = e.Sig
+	}
+
+	sigs := loadSignatures(projectDir)
+	if head := sigs["events_head"]; head != "" && head != prev {
+		result.Findings = append(result.Findings, VerifyFinding{Subject: "events.yaml", Message: "chain head missing (entries removed from the end)"})
+	}
+
+	names := append([]string(nil), signedFiles...)
+	sort.Strings(names)
+	for _, name := range names {
+		want, ok := sigs[name]
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(projectDir, ".ptsd", name))
+		if err != nil {
+			result.Findings = append(result.Findings, VerifyFinding{Subject: name, Message: "signed file missing"})
+			continue
+		}
+		if hmacHex(key, string(data)) != want {
+			result.Findings = append(result.Findings, VerifyFinding{Subject: name, Message: "content does not match signature"})
+		}
+	}
+	return result, nil
+}
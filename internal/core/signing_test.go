@@ -0,0 +1,141 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupSignedProject(t *testing.T) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	t.Setenv(signingKeyEnv, filepath.Join(t.TempDir(), "signing.key"))
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("audit:\n  sign: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func verifyMessages(t *testing.T, dir string) string {
+	t.Helper()
+	result, err := VerifyLog(dir)
+	if err != nil {
+		t.Fatalf("VerifyLog failed: %v", err)
+	}
+	var msgs []string
+	for _, f := range result.Findings {
+		msgs = append(msgs, f.Subject+": "+f.Message)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func TestSignedEventChainVerifies(t *testing.T) {
+	dir := setupSignedProject(t)
+	for _, stage := range []string{"prd", "seed", "bdd"} {
+		if err := AppendEvent(dir, Event{Type: EventStage, Feature: "auth", Stage: stage, Detail: `say "hi"`}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := VerifyLog(dir)
+	if err != nil {
+		t.Fatalf("VerifyLog failed: %v", err)
+	}
+	if result.Events != 3 || result.Signed != 3 || len(result.Findings) != 0 {
+		t.Errorf("expected clean chain of 3, got %+v", result)
+	}
+}
+
+func TestVerifyLogDetectsEditedAndRemovedEvents(t *testing.T) {
+	dir := setupSignedProject(t)
+	AppendEvent(dir, Event{Type: EventReview, Feature: "auth", Stage: "prd", Score: 4})
+	AppendEvent(dir, Event{Type: EventStage, Feature: "auth", Stage: "seed"})
+	AppendEvent(dir, Event{Type: EventStage, Feature: "auth", Stage: "bdd"})
+
+	path := eventsPath(dir)
+	data, _ := os.ReadFile(path)
+	edited := strings.Replace(string(data), "score: 4", "score: 9", 1)
+	os.WriteFile(path, []byte(edited), 0644)
+	if got := verifyMessages(t, dir); !strings.Contains(got, "events.yaml #1: signature mismatch") {
+		t.Errorf("expected edited entry reported, got:\n%s", got)
+	}
+
+	// Drop the last entry: the chain head no longer matches.
+	truncated := string(data)[:strings.LastIndex(string(data), "  - at: ")]
+	os.WriteFile(path, []byte(truncated), 0644)
+	if got := verifyMessages(t, dir); !strings.Contains(got, "chain head missing") {
+		t.Errorf("expected truncation reported, got:\n%s", got)
+	}
+}
+
+func TestSignedStateTamperIsRecorded(t *testing.T) {
+	dir := setupSignedProject(t)
+	if err := RecordReview(dir, "auth", "prd", 5); err != nil {
+		t.Fatalf("RecordReview failed: %v", err)
+	}
+	if got := verifyMessages(t, dir); got != "" {
+		t.Fatalf("expected clean verify after ptsd writes, got:\n%s", got)
+	}
+
+	rsPath := filepath.Join(dir, ".ptsd", "review-status.yaml")
+	data, _ := os.ReadFile(rsPath)
+	os.WriteFile(rsPath, []byte(strings.Replace(string(data), "review: failed", "review: passed", 1)), 0644)
+	if got := verifyMessages(t, dir); !strings.Contains(got, "review-status.yaml: content does not match signature") {
+		t.Errorf("expected hand edit reported, got:\n%s", got)
+	}
+
+	// The next ptsd write re-signs the file but leaves a tamper event behind.
+	if err := RecordReview(dir, "auth", "prd", 6); err != nil {
+		t.Fatalf("RecordReview failed: %v", err)
+	}
+	got := verifyMessages(t, dir)
+	if strings.Contains(got, "content does not match") || !strings.Contains(got, "recorded tamper: review-status.yaml changed outside ptsd") {
+		t.Errorf("expected tamper event after re-sign, got:\n%s", got)
+	}
+}
+
+func TestVerifyLogWithoutKey(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	t.Setenv(signingKeyEnv, filepath.Join(t.TempDir(), "missing.key"))
+
+	_, err := VerifyLog(dir)
+	if err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Errorf("expected err:config without a key, got %v", err)
+	}
+}
+
+func TestUndoKeepsSignedFilesVerified(t *testing.T) {
+	dir := setupSignedProject(t)
+	review := func(score int) {
+		t.Helper()
+		finish, err := BeginHistory(dir, "review auth prd")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := RecordReview(dir, "auth", "prd", score); err != nil {
+			t.Fatalf("RecordReview failed: %v", err)
+		}
+		finish()
+	}
+	review(5)
+	review(6)
+
+	if _, err := Undo(dir, 1); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if got := verifyMessages(t, dir); got != "" {
+		t.Errorf("expected clean verify after undo, got:\n%s", got)
+	}
+
+	// Back to before the first review: review-status.yaml did not exist yet.
+	if _, err := Undo(dir, 2); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ptsd", "review-status.yaml")); !os.IsNotExist(err) {
+		t.Fatalf("expected review-status.yaml removed, got %v", err)
+	}
+	if got := verifyMessages(t, dir); got != "" {
+		t.Errorf("expected clean verify after undoing to an unsigned state, got:\n%s", got)
+	}
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Near-duplicate detection for `ptsd feature add`. Agents working across
+// sessions tend to re-register a feature under a new ID ("user-login" next
+// to "login"); titles, IDs and PRD section headings are compared after
+// normalization and close matches are reported before the add.
+
+// SimilarityThreshold is the score at which a feature counts as a likely
+// duplicate.
+const SimilarityThreshold = 0.75
+
+// SimilarFeature is an existing feature that resembles a new one.
+type SimilarFeature struct {
+	ID    string
+	Title string
+	// Score is the best similarity in [0, 1]; Match says what matched:
+	// "title", "id" or "prd" (the existing feature's PRD heading).
+	Score float64
+	Match string
+}
+
+// similarStopWords carry no meaning in feature titles.
+var similarStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true,
+	"for": true, "to": true, "in": true, "on": true, "with": true, "by": true,
+	"feature": true, "support": true,
+}
+
+// SimilarFeatures returns registered features (other than id) whose title,
+// ID or PRD heading is at least SimilarityThreshold similar to the given
+// id and title, most similar first.
+func SimilarFeatures(projectDir, id, title string) ([]SimilarFeature, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var out []SimilarFeature
+	for _, f := range features {
+		if f.ID == id {
+			continue
+		}
+		best := SimilarFeature{ID: f.ID, Title: f.Title}
+		consider := func(score float64, match string) {
+			if score > best.Score {
+				best.Score, best.Match = score, match
+			}
+		}
+		consider(textSimilarity(title, f.Title), "title")
+		consider(textSimilarity(id, f.ID), "id")
+		if sec, err := ExtractPRDSection(projectDir, f.ID); err == nil {
+			if h := prdHeading(sec.Content); h != "" {
+				consider(textSimilarity(title, h), "prd")
+			}
+		}
+		if best.Score >= SimilarityThreshold {
+			out = append(out, best)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out, nil
+}
+
+// prdHeading is the first markdown heading of a PRD section.
+func prdHeading(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if h, ok := strings.CutPrefix(strings.TrimSpace(line), "#"); ok {
+			return strings.TrimSpace(strings.TrimLeft(h, "#"))
+		}
+	}
+	return ""
+}
+
+// textSimilarity compares two titles or IDs: the better of word-set overlap
+// (order-insensitive, so "User auth" matches "Auth for users") and character
+// bigram overlap (so "log-in" matches "login").
+func textSimilarity(a, b string) float64 {
+	ta, tb := similarTokens(a, true), similarTokens(b, true)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	words := dice(ta, tb)
+	chars := dice(bigrams(strings.Join(similarTokens(a, false), "")), bigrams(strings.Join(similarTokens(b, false), "")))
+	return max(words, chars)
+}
+
+// similarTokens lowercases s, splits it into words, optionally drops stop
+// words and trims a trailing plural s. Word lists are sorted only when stop
+// words are dropped; the bigram comparison needs the original order.
+func similarTokens(s string, dropStop bool) []string {
+	var out []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if dropStop && similarStopWords[w] {
+			continue
+		}
+		if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		out = append(out, w)
+	}
+	if dropStop {
+		sort.Strings(out)
+	}
+	return out
+}
+
+func bigrams(s string) []string {
+	r := []rune(s)
+	if len(r) < 2 {
+		return []string{s}
+	}
+	out := make([]string, 0, len(r)-1)
+	for i := 0; i+1 < len(r); i++ {
+		out = append(out, string(r[i:i+2]))
+	}
+	return out
+}
+
+// dice is the Sørensen–Dice coefficient of two multisets.
+func dice(a, b []string) float64 {
+	counts := make(map[string]int, len(a))
+	for _, s := range a {
+		counts[s]++
+	}
+	common := 0
+	for _, s := range b {
+		if counts[s] > 0 {
+			counts[s]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTextSimilarity(t *testing.T) {
+	cases := []struct {
+		a, b string
+		dup  bool
+	}{
+		{"User authentication", "Authentication for users", true},
+		{"User log-in", "User login", true},
+		{"billing", "billing-v2", true},
+		{"Payment processing", "Payment refunds", false},
+		{"Cart", "Card", false},
+		{"Export to CSV", "Import from CSV", false},
+	}
+	for _, c := range cases {
+		score := textSimilarity(c.a, c.b)
+		if (score >= SimilarityThreshold) != c.dup {
+			t.Errorf("textSimilarity(%q, %q) = %.2f, duplicate=%v expected", c.a, c.b, score, c.dup)
+		}
+	}
+}
+
+func TestSimilarFeatures(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:planned", "cart:planned", "checkout:planned")
+	os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte(`features:
+  - id: auth
+    title: User authentication
+    status: planned
+  - id: cart
+    title: Cart
+    status: planned
+  - id: checkout
+    title: Checkout
+    status: planned
+`), 0644)
+	os.MkdirAll(filepath.Join(dir, ".ptsd", "docs"), 0755)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "docs", "PRD.md"),
+		[]byte("# PRD\n<!-- feature:checkout -->\n## Order placement and payment\nUsers pay.\n"), 0644)
+
+	got, err := SimilarFeatures(dir, "user-auth", "Authenticating users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != "auth" {
+		t.Fatalf("expected auth as the only similar feature, got %+v", got)
+	}
+
+	got, _ = SimilarFeatures(dir, "place-order", "Order placement & payment")
+	if len(got) != 1 || got[0].ID != "checkout" || got[0].Match != "prd" {
+		t.Errorf("expected a PRD heading match on checkout, got %+v", got)
+	}
+
+	if got, _ := SimilarFeatures(dir, "reports", "Sales reports"); len(got) != 0 {
+		t.Errorf("expected no similar features, got %+v", got)
+	}
+}
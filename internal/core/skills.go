@@ -35,8 +35,9 @@ var standardSkillFiles = []string{
 // Filename format: <stage>-<feature>.md
 // projectDir is the root directory containing .ptsd/.
 func GenerateSkill(projectDir, stage, featureID string) error {
-	if !validSkillStages[stage] {
-		return fmt.Errorf("err:user invalid stage %q: must be prd|seed|bdd|tests|impl", stage)
+	stages := PipelineStages(projectDir)
+	if stageRank(stages, stage) < 0 {
+		return fmt.Errorf("err:user invalid stage %q: must be %s", stage, strings.Join(stages, "|"))
 	}
 
 	skillsDir := filepath.Join(projectDir, ".ptsd", "skills")
@@ -55,16 +56,26 @@ func GenerateSkill(projectDir, stage, featureID string) error {
 	sb.WriteString("## Instructions\n\nFollow the PTSD pipeline for the " + stage + " stage.\n")
 	content := sb.String()
 
-	if err := os.WriteFile(skillPath, []byte(content), 0644); err != nil {
+	if err := writeFileAtomic(skillPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("err:io %w", err)
 	}
 
 	return nil
 }
 
-// GenerateAllSkills generates all standard pipeline skills into .ptsd/skills/.
-// This is called by ptsd init.
+// GenerateAllSkills generates all standard pipeline skills into .ptsd/skills/,
+// overwriting local edits, and records their hashes in the generated manifest.
 func GenerateAllSkills(projectDir string) error {
+	m := loadGenManifest(projectDir)
+	m.force = true
+	if err := generateAllSkills(projectDir, m); err != nil {
+		return err
+	}
+	return m.save()
+}
+
+// generateAllSkills writes the standard skills through the manifest m.
+func generateAllSkills(projectDir string, m *genManifest) error {
 	skillsDir := filepath.Join(projectDir, ".ptsd", "skills")
 	if err := os.MkdirAll(skillsDir, 0755); err != nil {
 		return fmt.Errorf("err:io %w", err)
@@ -75,18 +86,41 @@ func GenerateAllSkills(projectDir string) error {
 		if err != nil {
 			return fmt.Errorf("err:io %w", err)
 		}
-		skillPath := filepath.Join(skillsDir, filename)
-		if err := os.WriteFile(skillPath, []byte(content), 0644); err != nil {
-			return fmt.Errorf("err:io %w", err)
+		if err := m.write(filepath.Join(skillsDir, filename), content, 0644); err != nil {
+			return err
+		}
+	}
+
+	for _, stage := range PipelineStages(projectDir) {
+		if !IsCustomStage(stage) {
+			continue
+		}
+		if err := m.write(filepath.Join(skillsDir, "review-"+stage+".md"), customStageSkill(stage), 0644); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// customStageSkill renders the review skill for a configured custom stage.
+func customStageSkill(stage string) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.WriteString("name: review-" + stage + "\n")
+	sb.WriteString("description: Use when reviewing the " + stage + " stage of a feature before advancing\n")
+	sb.WriteString("---\n\n")
+	sb.WriteString("## Instructions\n\n")
+	sb.WriteString("The " + stage + " stage is a review-only stage configured in pipeline.stages.\n")
+	sb.WriteString("Check the feature against the team's " + stage + " criteria, then record the verdict:\n\n")
+	sb.WriteString("    ptsd review <feature> " + stage + " <score> --agent\n\n")
+	sb.WriteString("Later stages stay blocked until this review passes.\n")
+	return sb.String()
+}
+
 // generateClaudeSkills generates .claude/skills/<name>/SKILL.md for each standard skill.
 // This enables Claude Code auto-discovery of skills.
-func generateClaudeSkills(dir string) error {
+func generateClaudeSkills(dir string, m *genManifest) error {
 	for _, filename := range standardSkillFiles {
 		name := strings.TrimSuffix(filename, ".md")
 		skillDir := filepath.Join(dir, ".claude", "skills", name)
@@ -97,9 +131,21 @@ func generateClaudeSkills(dir string) error {
 		if err != nil {
 			return fmt.Errorf("err:io %w", err)
 		}
-		if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0644); err != nil {
+		if err := m.write(filepath.Join(skillDir, "SKILL.md"), content, 0644); err != nil {
+			return err
+		}
+	}
+	for _, stage := range PipelineStages(dir) {
+		if !IsCustomStage(stage) {
+			continue
+		}
+		skillDir := filepath.Join(dir, ".claude", "skills", "review-"+stage)
+		if err := os.MkdirAll(skillDir, 0755); err != nil {
 			return fmt.Errorf("err:io %w", err)
 		}
+		if err := m.write(filepath.Join(skillDir, "SKILL.md"), customStageSkill(stage), 0644); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -274,7 +274,7 @@ func TestWorkflowSkillExists(t *testing.T) {
 func TestGenerateClaudeSkillsCreatesDirectories(t *testing.T) {
 	dir := t.TempDir()
 
-	err := generateClaudeSkills(dir)
+	err := generateClaudeSkills(dir, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -291,7 +291,7 @@ func TestGenerateClaudeSkillsCreatesDirectories(t *testing.T) {
 func TestClaudeSkillContentHasFrontmatter(t *testing.T) {
 	dir := t.TempDir()
 
-	if err := generateClaudeSkills(dir); err != nil {
+	if err := generateClaudeSkills(dir, nil); err != nil {
 		t.Fatal(err)
 	}
 
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var snapshotNameRe = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Snapshot describes a stored .ptsd snapshot archive.
+type Snapshot struct {
+	Name    string
+	Created time.Time
+	Size    int64
+}
+
+func snapshotsDir(projectDir string) string {
+	return filepath.Join(projectDir, ".ptsd", "snapshots")
+}
+
+func snapshotPath(projectDir, name string) string {
+	return filepath.Join(snapshotsDir(projectDir), name+".tar.gz")
+}
+
+// snapshotSkips reports whether a top-level .ptsd entry is left out of
+// snapshots and untouched by a restore: the snapshots themselves, the undo
+// history (so `ptsd undo` after a restore undoes the restore), and runtime
+// files owned by live processes (the project lock the restore is holding, the
+// daemon socket, the extends cache, in-flight temp files, the auto-track queue).
+func snapshotSkips(name string) bool {
+	switch name {
+	case "snapshots", ".history", lockFile, "daemon.sock", ".extends":
+		return true
+	}
+	return strings.HasPrefix(name, "autotrack-") ||
+		(strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".tmp"))
+}
+
+// CreateSnapshot archives everything under .ptsd/ (except snapshots/ itself
+// and runtime files) into .ptsd/snapshots/<name>.tar.gz. An empty name uses a timestamp.
+func CreateSnapshot(projectDir, name string) (string, error) {
+	if name == "" {
+		name = time.Now().UTC().Format("20060102-150405")
+	}
+	if !snapshotNameRe.MatchString(name) {
+		return "", fmt.Errorf("err:user invalid snapshot name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+
+	ptsdDir := filepath.Join(projectDir, ".ptsd")
+	if _, err := os.Stat(ptsdDir); err != nil {
+		return "", fmt.Errorf("err:config .ptsd not found")
+	}
+
+	dest := snapshotPath(projectDir, name)
+	if _, err := os.Stat(dest); err == nil {
+		return "", fmt.Errorf("err:user snapshot %s already exists", name)
+	}
+	if err := os.MkdirAll(snapshotsDir(projectDir), 0755); err != nil {
+		return "", fmt.Errorf("err:io %w", err)
+	}
+
+	if err := archiveDir(ptsdDir, dest, snapshotSkips); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// archiveDir writes every regular file under srcDir, except top-level entries
+// matched by skip (if non-nil), to a gzipped tar at dest. A failed archive is
+// removed.
+func archiveDir(srcDir, dest string, skip func(name string) bool) error {
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	walkErr := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(srcDir, path)
+		if rel == "." {
+			return nil
+		}
+		if skip != nil && filepath.Dir(rel) == "." && skip(rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, src)
+		src.Close()
+		return err
+	})
+
+	closeErr := tw.Close()
+	if err := gz.Close(); closeErr == nil {
+		closeErr = err
+	}
+	if err := f.Close(); closeErr == nil {
+		closeErr = err
+	}
+	if walkErr != nil || closeErr != nil {
+		os.Remove(dest)
+		if walkErr != nil {
+			return fmt.Errorf("err:io %w", walkErr)
+		}
+		return fmt.Errorf("err:io %w", closeErr)
+	}
+	return nil
+}
+
+// ListSnapshots returns stored snapshots, oldest first.
+func ListSnapshots(projectDir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(snapshotsDir(projectDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+
+	var snaps []Snapshot
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".tar.gz") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		snaps = append(snaps, Snapshot{
+			Name:    strings.TrimSuffix(e.Name(), ".tar.gz"),
+			Created: info.ModTime(),
+			Size:    info.Size(),
+		})
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Created.Before(snaps[j].Created) })
+	return snaps, nil
+}
+
+// RestoreSnapshot replaces the contents of .ptsd/ (except snapshots/ and
+// runtime files) with the named snapshot. Files created after the snapshot are removed.
+func RestoreSnapshot(projectDir, name string) error {
+	if !snapshotNameRe.MatchString(name) {
+		return fmt.Errorf("err:user invalid snapshot name %q", name)
+	}
+
+	f, err := os.Open(snapshotPath(projectDir, name))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("err:user snapshot %s not found", name)
+	}
+	if err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("err:io corrupt snapshot %s: %w", name, err)
+	}
+	defer gz.Close()
+
+	// Read the whole archive before touching the tree so a corrupt snapshot
+	// leaves the current state intact.
+	files := make(map[string][]byte)
+	modes := make(map[string]os.FileMode)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("err:io corrupt snapshot %s: %w", name, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		rel := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(rel) {
+			return fmt.Errorf("err:io snapshot %s contains unsafe path %s", name, hdr.Name)
+		}
+		if snapshotSkips(strings.SplitN(hdr.Name, "/", 2)[0]) {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("err:io corrupt snapshot %s: %w", name, err)
+		}
+		files[rel] = data
+		modes[rel] = os.FileMode(hdr.Mode).Perm()
+	}
+
+	ptsdDir := filepath.Join(projectDir, ".ptsd")
+	entries, err := os.ReadDir(ptsdDir)
+	if err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	for _, e := range entries {
+		if snapshotSkips(e.Name()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(ptsdDir, e.Name())); err != nil {
+			return fmt.Errorf("err:io %w", err)
+		}
+	}
+
+	for rel, data := range files {
+		dest := filepath.Join(ptsdDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("err:io %w", err)
+		}
+		if err := writeFileAtomic(dest, data, modes[rel]); err != nil {
+			return fmt.Errorf("err:io %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRestoreRoundTrip(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte("features:\n  auth:\n    stage: seed\n"), 0644)
+	os.MkdirAll(filepath.Join(ptsdDir, "seeds", "auth"), 0755)
+	os.WriteFile(filepath.Join(ptsdDir, "seeds", "auth", "seed.yaml"), []byte("feature: auth\n"), 0644)
+
+	name, err := CreateSnapshot(dir, "before-split")
+	if err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+	if name != "before-split" {
+		t.Errorf("expected name before-split, got %s", name)
+	}
+
+	// Mutate state after snapshot.
+	os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte("features: {}\n"), 0644)
+	os.RemoveAll(filepath.Join(ptsdDir, "seeds", "auth"))
+	os.WriteFile(filepath.Join(ptsdDir, "tasks.yaml"), []byte("tasks:\n"), 0644)
+
+	if err := RestoreSnapshot(dir, "before-split"); err != nil {
+		t.Fatalf("RestoreSnapshot failed: %v", err)
+	}
+
+	state, _ := os.ReadFile(filepath.Join(ptsdDir, "state.yaml"))
+	if !strings.Contains(string(state), "stage: seed") {
+		t.Errorf("state.yaml not restored, got %q", state)
+	}
+	if _, err := os.Stat(filepath.Join(ptsdDir, "seeds", "auth", "seed.yaml")); err != nil {
+		t.Errorf("seed not restored: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ptsdDir, "tasks.yaml")); !os.IsNotExist(err) {
+		t.Errorf("files created after snapshot must be removed")
+	}
+	if _, err := os.Stat(snapshotPath(dir, "before-split")); err != nil {
+		t.Errorf("snapshot archive must survive restore: %v", err)
+	}
+}
+
+func TestCreateSnapshotRejectsDuplicateAndBadName(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+
+	if _, err := CreateSnapshot(dir, "../evil"); err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Errorf("expected err:user for bad name, got %v", err)
+	}
+	if _, err := CreateSnapshot(dir, "one"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateSnapshot(dir, "one"); err == nil {
+		t.Error("expected error for duplicate snapshot")
+	}
+
+	snaps, err := ListSnapshots(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 1 || snaps[0].Name != "one" {
+		t.Errorf("expected one snapshot, got %+v", snaps)
+	}
+}
+
+func TestRestoreSnapshotMissing(t *testing.T) {
+	dir := setupProjectWithFeatures(t)
+	err := RestoreSnapshot(dir, "nope")
+	if err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Errorf("expected err:user, got %v", err)
+	}
+}
+
+func TestRestoreSnapshotLeavesRuntimeFiles(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	runtime := []string{lockFile, "daemon.sock", filepath.Join(".extends", "base.yaml"), "autotrack-pending"}
+	write := func(content string) {
+		for _, rel := range runtime {
+			os.MkdirAll(filepath.Dir(filepath.Join(ptsdDir, rel)), 0755)
+			os.WriteFile(filepath.Join(ptsdDir, rel), []byte(content), 0644)
+		}
+	}
+
+	write("before")
+	if _, err := CreateSnapshot(dir, "runtime"); err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+	write("after")
+	lockInfo, _ := os.Stat(filepath.Join(ptsdDir, lockFile))
+
+	if err := RestoreSnapshot(dir, "runtime"); err != nil {
+		t.Fatalf("RestoreSnapshot failed: %v", err)
+	}
+	for _, rel := range runtime {
+		data, err := os.ReadFile(filepath.Join(ptsdDir, rel))
+		if err != nil || string(data) != "after" {
+			t.Errorf("%s replaced by restore: %q %v", rel, data, err)
+		}
+	}
+	if info, _ := os.Stat(filepath.Join(ptsdDir, lockFile)); !os.SameFile(lockInfo, info) {
+		t.Error("restore recreated the held project lock file")
+	}
+}
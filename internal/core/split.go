@@ -0,0 +1,416 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SplitOptions selects what SplitFeature moves from the parent feature into
+// the new child feature.
+type SplitOptions struct {
+	Title     string   // child title; defaults to the child ID
+	Scenarios []string // BDD scenario IDs or titles
+	Seeds     []string // seed file names from the parent's seed.yaml
+	Tasks     []string // task IDs
+}
+
+// SplitResult reports what SplitFeature moved.
+type SplitResult struct {
+	Scenarios []string // scenario IDs
+	Seeds     []string
+	Tasks     []string
+	Mappings  int  // scenario test mappings moved in state.yaml
+	PRD       bool // a PRD anchor was added for the child
+}
+
+// SplitFeature carves a child feature out of parentID: the selected BDD
+// scenarios, seed files and tasks move to childID, scenario test mappings
+// follow their scenarios, the child is registered with `parent: <parentID>`,
+// and a PRD anchor for it is added right after the parent's section.
+// Everything is checked before anything is written.
+func SplitFeature(projectDir, parentID, childID string, opts SplitOptions) (SplitResult, error) {
+	if !validFeatureID.MatchString(childID) {
+		return SplitResult{}, fmt.Errorf("err:validation invalid feature ID %q: must be ASCII slug (a-z0-9 with hyphens)", childID)
+	}
+	if childID == parentID {
+		return SplitResult{}, fmt.Errorf("err:validation feature %s already exists", childID)
+	}
+	if len(opts.Scenarios)+len(opts.Seeds)+len(opts.Tasks) == 0 {
+		return SplitResult{}, fmt.Errorf("err:user nothing to split: pass --scenario, --seed or --task")
+	}
+	if opts.Title == "" {
+		opts.Title = childID
+	}
+
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return SplitResult{}, err
+	}
+	var parent *Feature
+	for i := range features {
+		switch features[i].ID {
+		case parentID:
+			parent = &features[i]
+		case childID:
+			return SplitResult{}, fmt.Errorf("err:validation feature %s already exists", childID)
+		}
+	}
+	if parent == nil {
+		return SplitResult{}, fmt.Errorf("err:validation feature %s not found", parentID)
+	}
+
+	var res SplitResult
+
+	// BDD: partition the parent's scenarios.
+	bddDir := bddDirPath(projectDir)
+	parentBDD := filepath.Join(bddDir, parentID+".feature")
+	childBDD := filepath.Join(bddDir, childID+".feature")
+	var keptBDD, movedBDD string
+	if len(opts.Scenarios) > 0 {
+		data, err := os.ReadFile(parentBDD)
+		if err != nil {
+			return SplitResult{}, fmt.Errorf("err:validation %s has no BDD file", parentID)
+		}
+		if fileExists(childBDD) {
+			return SplitResult{}, fmt.Errorf("err:validation %s already exists", bddFileRel(projectDir, childID))
+		}
+		keptBDD, movedBDD, res.Scenarios, err = splitScenarios(string(data), opts.Scenarios)
+		if err != nil {
+			return SplitResult{}, err
+		}
+		movedBDD = "@feature:" + childID + "\nFeature: " + opts.Title + "\n\n" + movedBDD
+	}
+
+	// Seeds: the files must be listed in the parent's manifest.
+	parentSeedDir := seedDirPath(projectDir, parentID)
+	childSeedDir := seedDirPath(projectDir, childID)
+	var parentManifest string
+	if len(opts.Seeds) > 0 {
+		data, err := os.ReadFile(filepath.Join(parentSeedDir, "seed.yaml"))
+		if err != nil {
+			return SplitResult{}, fmt.Errorf("err:validation seed not initialized for %s", parentID)
+		}
+		parentManifest = string(data)
+		listed := make(map[string]bool)
+		for _, e := range seedManifestEntries(parentManifest) {
+			listed[e[0]] = true
+		}
+		for _, name := range opts.Seeds {
+			if !listed[name] {
+				return SplitResult{}, fmt.Errorf("err:validation seed file %s not in %s seed.yaml", name, parentID)
+			}
+		}
+		if fileExists(filepath.Join(childSeedDir, "seed.yaml")) {
+			return SplitResult{}, fmt.Errorf("err:validation seed already initialized for %s", childID)
+		}
+	}
+
+	// Tasks: each must belong to the parent.
+	var tasks []Task
+	if len(opts.Tasks) > 0 {
+		if tasks, err = loadTasks(projectDir); err != nil {
+			return SplitResult{}, err
+		}
+		for _, id := range opts.Tasks {
+			found := false
+			for _, t := range tasks {
+				if t.ID == id {
+					if t.Feature != parentID {
+						return SplitResult{}, fmt.Errorf("err:validation task %s belongs to %s, not %s", id, t.Feature, parentID)
+					}
+					found = true
+				}
+			}
+			if !found {
+				return SplitResult{}, fmt.Errorf("err:user task %s not found", id)
+			}
+		}
+	}
+
+	// Checks passed; write.
+	features = append(features, Feature{ID: childID, Title: opts.Title, Status: parent.Status, Pipeline: parent.Pipeline, Parent: parentID})
+	if err := saveFeatures(projectDir, features); err != nil {
+		return SplitResult{}, fmt.Errorf("err:io %w", err)
+	}
+
+	if len(opts.Scenarios) > 0 {
+		if err := writeFile(parentBDD, keptBDD); err != nil {
+			return res, err
+		}
+		if err := writeFile(childBDD, movedBDD); err != nil {
+			return res, err
+		}
+		// Mappings may name a moved scenario or a moved outline as a whole.
+		refs := append([]string{}, res.Scenarios...)
+		childFF, _ := parseFeatureContent(movedBDD)
+		for _, sc := range childFF.Scenarios {
+			if sc.Outline != "" && !containsString(refs, sc.Outline) {
+				refs = append(refs, sc.Outline)
+			}
+		}
+		n, err := moveScenarioMappings(projectDir, parentID, childID, refs)
+		if err != nil {
+			return res, err
+		}
+		res.Mappings = n
+	}
+
+	if len(opts.Seeds) > 0 {
+		if err := os.MkdirAll(childSeedDir, 0755); err != nil {
+			return res, fmt.Errorf("err:io %w", err)
+		}
+		childManifest := "feature: " + childID + "\nfiles:\n"
+		for _, name := range opts.Seeds {
+			var entry []string
+			parentManifest, entry = takeSeedManifestEntry(parentManifest, name)
+			childManifest += strings.Join(entry, "\n") + "\n"
+			if err := os.Rename(filepath.Join(parentSeedDir, name), filepath.Join(childSeedDir, name)); err != nil && !os.IsNotExist(err) {
+				return res, fmt.Errorf("err:io %w", err)
+			}
+			res.Seeds = append(res.Seeds, name)
+		}
+		if err := writeFile(filepath.Join(parentSeedDir, "seed.yaml"), parentManifest); err != nil {
+			return res, err
+		}
+		if err := writeFile(filepath.Join(childSeedDir, "seed.yaml"), childManifest); err != nil {
+			return res, err
+		}
+	}
+
+	if len(opts.Tasks) > 0 {
+		for i := range tasks {
+			if containsString(opts.Tasks, tasks[i].ID) {
+				tasks[i].Feature = childID
+				res.Tasks = append(res.Tasks, tasks[i].ID)
+			}
+		}
+		if err := saveTasks(projectDir, tasks); err != nil {
+			return res, err
+		}
+	}
+
+	added, err := addChildPRDAnchor(projectDir, parentID, childID, opts.Title)
+	if err != nil {
+		return res, err
+	}
+	res.PRD = added
+	return res, nil
+}
+
+// splitScenarios moves the referenced scenarios (with their tags) out of a
+// feature file. Moved scenarios without an @id tag get one, so test mappings
+// keyed by scenario ID stay valid. Returns the remaining file, the moved
+// scenario blocks and their scenario IDs (outline expansions included).
+func splitScenarios(content string, refs []string) (kept, moved string, ids []string, err error) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	// A block starts at its tag lines and ends where the next one starts.
+	var starts []int
+	for i, line := range lines {
+		if _, ok := scenarioLine(strings.TrimSpace(line)); ok {
+			start := i
+			for start > 0 {
+				prev := strings.TrimSpace(lines[start-1])
+				if !strings.HasPrefix(prev, "@") || strings.HasPrefix(prev, "@feature:") {
+					break
+				}
+				start--
+			}
+			starts = append(starts, start)
+		}
+	}
+	if len(starts) == 0 {
+		return "", "", nil, fmt.Errorf("err:validation feature file has no scenarios")
+	}
+
+	// A block is one scenario or one outline with all its expansions; ids
+	// lists every scenario ID it yields.
+	type block struct {
+		lines []string
+		id    string
+		ids   []string
+	}
+	all := FeatureFileData{}
+	var blocks []block
+	for i, start := range starts {
+		end := len(lines)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		b := block{lines: lines[start:end]}
+		name := ""
+		for _, line := range b.lines {
+			trimmed := strings.TrimSpace(line)
+			if n, ok := scenarioLine(trimmed); ok {
+				name = n
+				break
+			}
+			for _, tag := range strings.Fields(trimmed) {
+				if strings.HasPrefix(tag, scenarioIDTag) {
+					b.id = strings.TrimPrefix(tag, scenarioIDTag)
+				}
+			}
+		}
+		if b.id == "" {
+			b.id = scenarioHashID(name)
+		}
+		ff, _ := parseFeatureContent("Feature:\n" + strings.Join(b.lines, "\n"))
+		scenarios := ff.Scenarios
+		if len(scenarios) == 0 {
+			// An outline without Examples rows.
+			scenarios = []ScenarioData{{ID: b.id, Name: name, Title: name}}
+		}
+		for _, sc := range scenarios {
+			b.ids = append(b.ids, sc.ID)
+		}
+		all.Scenarios = append(all.Scenarios, scenarios...)
+		blocks = append(blocks, b)
+	}
+
+	// Selecting one expansion of an outline moves the whole outline.
+	selected := make(map[string]bool)
+	for _, ref := range refs {
+		sc, ok := findScenario(all, ref)
+		if !ok {
+			return "", "", nil, fmt.Errorf("err:validation scenario %q not found", ref)
+		}
+		if sc.Outline != "" {
+			selected[sc.Outline] = true
+		} else {
+			selected[sc.ID] = true
+		}
+	}
+
+	keptLines := append([]string{}, lines[:starts[0]]...)
+	var movedLines []string
+	for _, b := range blocks {
+		if !selected[b.id] {
+			keptLines = append(keptLines, b.lines...)
+			continue
+		}
+		ids = append(ids, b.ids...)
+		blockLines := b.lines
+		if !strings.Contains(strings.Join(blockLines, "\n"), scenarioIDTag) {
+			for j, line := range blockLines {
+				if _, ok := scenarioLine(strings.TrimSpace(line)); ok {
+					indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+					pinned := append([]string{}, blockLines[:j]...)
+					pinned = append(pinned, indent+scenarioIDTag+b.id)
+					blockLines = append(pinned, blockLines[j:]...)
+					break
+				}
+			}
+		}
+		movedLines = append(movedLines, trimBlankTail(blockLines)...)
+		movedLines = append(movedLines, "")
+	}
+
+	kept = strings.Join(trimBlankTail(keptLines), "\n") + "\n"
+	moved = strings.Join(trimBlankTail(movedLines), "\n") + "\n"
+	return kept, moved, ids, nil
+}
+
+func trimBlankTail(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// takeSeedManifestEntry removes the entry for path from a seed manifest and
+// returns the manifest without it and the entry's lines.
+func takeSeedManifestEntry(manifest, path string) (string, []string) {
+	lines := strings.Split(strings.TrimRight(manifest, "\n"), "\n")
+	var out, entry []string
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "- path: "+path {
+			entry = append(entry, lines[i])
+			for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "    ") {
+				i++
+				entry = append(entry, lines[i])
+			}
+			continue
+		}
+		out = append(out, lines[i])
+	}
+	return strings.Join(out, "\n") + "\n", entry
+}
+
+// moveScenarioMappings moves the parent's test mappings for the given
+// scenario IDs to the child, pointing them at the child's BDD file.
+func moveScenarioMappings(projectDir, parentID, childID string, ids []string) (int, error) {
+	state, err := LoadState(projectDir)
+	if err != nil {
+		return 0, nil
+	}
+	pfs, ok := state.Features[parentID]
+	if !ok {
+		return 0, nil
+	}
+	mappings, _ := pfs.Tests.([]string)
+	if len(mappings) == 0 {
+		return 0, nil
+	}
+
+	childBDD := bddFileRel(projectDir, childID)
+	var kept, moved []string
+	for _, s := range mappings {
+		if m := parseTestMapping(s); m.Scenario != "" && containsString(ids, m.Scenario) {
+			m.BDD = childBDD
+			moved = append(moved, m.String())
+			continue
+		}
+		kept = append(kept, s)
+	}
+	if len(moved) == 0 {
+		return 0, nil
+	}
+
+	pfs.Tests = kept
+	state.Features[parentID] = pfs
+	cfs := state.Features[childID]
+	if cfs.Hashes == nil {
+		cfs.Hashes = make(map[string]string)
+	}
+	if cfs.Scores == nil {
+		cfs.Scores = make(map[string]ScoreEntry)
+	}
+	existing, _ := cfs.Tests.([]string)
+	cfs.Tests = append(existing, moved...)
+	state.Features[childID] = cfs
+	if err := writeState(projectDir, state); err != nil {
+		return 0, err
+	}
+	return len(moved), nil
+}
+
+// addChildPRDAnchor inserts a section for the child right after the parent's
+// PRD section, in the same PRD file. It reports false when the parent has no PRD anchor.
+func addChildPRDAnchor(projectDir, parentID, childID, title string) (bool, error) {
+	section, err := ExtractPRDSection(projectDir, parentID)
+	if err != nil {
+		return false, nil
+	}
+	prdPath := filepath.Join(projectDir, filepath.FromSlash(section.File))
+	data, err := os.ReadFile(prdPath)
+	if err != nil {
+		return false, fmt.Errorf("err:io %w", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	end := section.EndLine
+	if end > len(lines) {
+		end = len(lines)
+	}
+	head := trimBlankTail(append([]string{}, lines[:end]...))
+	child := []string{"", anchorPrefix + childID + anchorSuffix, "### " + title, "", "Split from " + parentID + ".", ""}
+	out := append(head, child...)
+	if rest := lines[end:]; len(rest) > 0 {
+		for len(rest) > 0 && strings.TrimSpace(rest[0]) == "" {
+			rest = rest[1:]
+		}
+		out = append(out, rest...)
+	}
+	return true, writeFile(prdPath, strings.Join(trimBlankTail(out), "\n")+"\n")
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const splitBDD = `@feature:checkout
+Feature: Checkout
+
+  Scenario: Pay by card
+    Given a cart
+    Then the card is charged
+
+  @id:sc-refund
+  Scenario: Refund order
+    Given a paid order
+    Then money is returned
+
+  Scenario: Cancel refund
+    Given a pending refund
+    Then it is cancelled
+`
+
+func setupSplitProject(t *testing.T) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t, "checkout:in-progress")
+	ptsd := filepath.Join(dir, ".ptsd")
+	files := map[string]string{
+		"bdd/checkout.feature":       splitBDD,
+		"seeds/checkout/seed.yaml":   "feature: checkout\nfiles:\n  - path: cart.json\n    type: data\n  - path: refund.json\n    type: data\n",
+		"seeds/checkout/cart.json":   "{}\n",
+		"seeds/checkout/refund.json": "{}\n",
+		"docs/PRD.md":                "# PRD\n\n<!-- feature:checkout -->\n## Checkout\n\nPay and refund.\n\n<!-- feature:other -->\n## Other\n",
+		"state.yaml":                 "features:\n  checkout:\n    stage: tests\n    hashes:\n    scores:\n    tests:\n      - .ptsd/bdd/checkout.feature#sc-refund::refund_test.go\n      - .ptsd/bdd/checkout.feature::checkout_test.go\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(ptsd, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setupTasks(t, dir,
+		Task{ID: "T-1", Feature: "checkout", Title: "card", Status: "TODO", Priority: "A"},
+		Task{ID: "T-2", Feature: "checkout", Title: "refunds", Status: "TODO", Priority: "B"},
+	)
+	return dir
+}
+
+func TestSplitFeature(t *testing.T) {
+	dir := setupSplitProject(t)
+
+	res, err := SplitFeature(dir, "checkout", "refunds", SplitOptions{
+		Title:     "Refunds",
+		Scenarios: []string{"sc-refund", "Cancel refund"},
+		Seeds:     []string{"refund.json"},
+		Tasks:     []string{"T-2"},
+	})
+	if err != nil {
+		t.Fatalf("SplitFeature: %v", err)
+	}
+	if len(res.Scenarios) != 2 || len(res.Seeds) != 1 || len(res.Tasks) != 1 || res.Mappings != 1 || !res.PRD {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	features, err := loadFeatures(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(features) != 2 || features[1].ID != "refunds" || features[1].Parent != "checkout" || features[1].Status != "in-progress" {
+		t.Fatalf("child not registered: %+v", features)
+	}
+
+	parent, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "bdd", "checkout.feature"))
+	if strings.Contains(string(parent), "Refund order") || !strings.Contains(string(parent), "Pay by card") {
+		t.Errorf("parent BDD not split:\n%s", parent)
+	}
+	child, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "bdd", "refunds.feature"))
+	ff, _ := parseFeatureContent(string(child))
+	if ff.Tag != "refunds" || len(ff.Scenarios) != 2 {
+		t.Fatalf("child BDD wrong:\n%s", child)
+	}
+	// The untagged scenario keeps the ID it had in the parent.
+	if ff.Scenarios[1].ID != scenarioHashID("Cancel refund") {
+		t.Errorf("moved scenario ID = %s, want %s", ff.Scenarios[1].ID, scenarioHashID("Cancel refund"))
+	}
+
+	if !fileExists(filepath.Join(dir, ".ptsd", "seeds", "refunds", "refund.json")) ||
+		fileExists(filepath.Join(dir, ".ptsd", "seeds", "checkout", "refund.json")) {
+		t.Error("seed file not moved")
+	}
+	manifest, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "seeds", "checkout", "seed.yaml"))
+	if strings.Contains(string(manifest), "refund.json") {
+		t.Errorf("parent manifest still lists refund.json:\n%s", manifest)
+	}
+
+	tasks, _ := loadTasks(dir)
+	if tasks[1].Feature != "refunds" || tasks[0].Feature != "checkout" {
+		t.Errorf("tasks not reassigned: %+v", tasks)
+	}
+
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved, _ := state.Features["refunds"].Tests.([]string)
+	if len(moved) != 1 || moved[0] != ".ptsd/bdd/refunds.feature#sc-refund::refund_test.go" {
+		t.Errorf("child mappings = %v", moved)
+	}
+
+	section, err := ExtractPRDSection(dir, "refunds")
+	if err != nil || !strings.Contains(section.Content, "Split from checkout.") {
+		t.Errorf("child PRD section missing: %v %q", err, section.Content)
+	}
+	if section, _ := ExtractPRDSection(dir, "checkout"); !strings.Contains(section.Content, "Pay and refund.") {
+		t.Errorf("parent PRD section changed: %q", section.Content)
+	}
+}
+
+func TestSplitFeatureValidatesBeforeWriting(t *testing.T) {
+	dir := setupSplitProject(t)
+
+	_, err := SplitFeature(dir, "checkout", "refunds", SplitOptions{
+		Scenarios: []string{"sc-refund"},
+		Tasks:     []string{"T-9"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "err:user") {
+		t.Fatalf("expected err:user for unknown task, got %v", err)
+	}
+	if fileExists(filepath.Join(dir, ".ptsd", "bdd", "refunds.feature")) {
+		t.Error("child BDD written despite error")
+	}
+	if features, _ := loadFeatures(dir); len(features) != 1 {
+		t.Errorf("child registered despite error: %+v", features)
+	}
+}
+
+func TestSplitFeatureErrors(t *testing.T) {
+	dir := setupSplitProject(t)
+	cases := []struct {
+		name   string
+		parent string
+		child  string
+		opts   SplitOptions
+		want   string
+	}{
+		{"nothing", "checkout", "refunds", SplitOptions{}, "err:user"},
+		{"bad id", "checkout", "Refunds", SplitOptions{Tasks: []string{"T-1"}}, "err:validation"},
+		{"unknown parent", "nope", "refunds", SplitOptions{Tasks: []string{"T-1"}}, "err:validation"},
+		{"existing child", "checkout", "checkout", SplitOptions{Tasks: []string{"T-1"}}, "already exists"},
+		{"unknown scenario", "checkout", "refunds", SplitOptions{Scenarios: []string{"Ship it"}}, "not found"},
+		{"unlisted seed", "checkout", "refunds", SplitOptions{Seeds: []string{"ship.json"}}, "not in checkout seed.yaml"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := SplitFeature(dir, tc.parent, tc.child, tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultStages is the built-in pipeline. Each built-in stage owns an artifact
+// (PRD anchor, seed, BDD, tests, implementation).
+var DefaultStages = []string{"prd", "seed", "bdd", "tests", "impl"}
+
+var builtinStages = map[string]bool{
+	"prd":   true,
+	"seed":  true,
+	"bdd":   true,
+	"tests": true,
+	"impl":  true,
+}
+
+var stageNameRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// validateStages checks a configured pipeline.stages list.
+func validateStages(stages []string) error {
+	seen := make(map[string]bool)
+	next := 0
+	for _, s := range stages {
+		if !stageNameRe.MatchString(s) {
+			return fmt.Errorf("err:config invalid stage name %q", s)
+		}
+		if seen[s] {
+			return fmt.Errorf("err:config duplicate stage %q", s)
+		}
+		seen[s] = true
+		if builtinStages[s] {
+			if next >= len(DefaultStages) || DefaultStages[next] != s {
+				return fmt.Errorf("err:config pipeline.stages must keep %s in order", strings.Join(DefaultStages, ", "))
+			}
+			next++
+		}
+	}
+	if next != len(DefaultStages) {
+		return fmt.Errorf("err:config pipeline.stages must include %s", strings.Join(DefaultStages, ", "))
+	}
+	return nil
+}
+
+// PipelineStages returns the configured stage order, or DefaultStages.
+func PipelineStages(projectDir string) []string {
+	if cfg, err := LoadConfig(projectDir); err == nil && len(cfg.Pipeline.Stages) > 0 {
+		return cfg.Pipeline.Stages
+	}
+	return DefaultStages
+}
+
+// FeatureStages returns the stage order for one feature: the configured
+// pipeline, without seed and bdd when the feature uses pipeline: lite.
+func FeatureStages(projectDir, featureID string) []string {
+	stages := PipelineStages(projectDir)
+	if !isLiteFeature(projectDir, featureID) {
+		return stages
+	}
+	return liteStages(stages)
+}
+
+func liteStages(stages []string) []string {
+	var out []string
+	for _, s := range stages {
+		if s != "seed" && s != "bdd" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// IsCustomStage reports whether s is a configured review-only stage.
+func IsCustomStage(s string) bool {
+	return s != "" && !builtinStages[s]
+}
+
+// stageRank returns the position of s in stages; "" and unknown stages rank -1.
+func stageRank(stages []string, s string) int {
+	for i, v := range stages {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// baseStage maps a stage to the nearest built-in stage at or before it, so
+// artifact-based logic keeps working for features sitting in a custom stage.
+// Stages outside the pipeline are returned unchanged.
+func baseStage(stages []string, s string) string {
+	rank := stageRank(stages, s)
+	if !IsCustomStage(s) || rank < 0 {
+		return s
+	}
+	for i := rank; i >= 0; i-- {
+		if builtinStages[stages[i]] {
+			return stages[i]
+		}
+	}
+	return ""
+}
+
+// nextStage returns the stage after s ("" for the last stage). A feature with
+// no stage yet is treated as sitting at prd.
+func nextStage(stages []string, s string) string {
+	if s == "" {
+		s = "prd"
+	}
+	i := stageRank(stages, s)
+	if i < 0 || i+1 >= len(stages) {
+		return ""
+	}
+	return stages[i+1]
+}
+
+// customStageGate blocks work on a built-in stage until every custom stage
+// configured before it has a passing review.
+func customStageGate(projectDir, featureID, target string) (blocked bool, reason string) {
+	stages := PipelineStages(projectDir)
+	for _, s := range stages {
+		if s == target {
+			break
+		}
+		if !IsCustomStage(s) {
+			continue
+		}
+		if passed, _ := CheckReviewGate(projectDir, featureID, s); !passed {
+			return true, "review " + s + " not passed for " + featureID + " — run: ptsd review " + featureID + " " + s + " <score>"
+		}
+	}
+	return false, ""
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const designPipelineConfig = "project:\n  name: X\npipeline:\n  stages: [prd, design, seed, bdd, tests, impl]\n"
+
+// setupDesignPipeline creates a project with a review-only "design" stage
+// between prd and seed.
+func setupDesignPipeline(t *testing.T, features ...string) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t, features...)
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte(designPipelineConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestPipelineStagesDefault(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth")
+	if got := strings.Join(PipelineStages(dir), ","); got != "prd,seed,bdd,tests,impl" {
+		t.Errorf("expected default stages, got %s", got)
+	}
+}
+
+func TestPipelineStagesFromConfig(t *testing.T) {
+	dir := setupDesignPipeline(t)
+	if got := strings.Join(PipelineStages(dir), ","); got != "prd,design,seed,bdd,tests,impl" {
+		t.Errorf("unexpected stages: %s", got)
+	}
+}
+
+func TestValidateStagesRejectsBadPipelines(t *testing.T) {
+	cases := map[string][]string{
+		"missing built-in": {"prd", "seed", "bdd", "impl"},
+		"reordered":        {"prd", "bdd", "seed", "tests", "impl"},
+		"duplicate":        {"prd", "design", "design", "seed", "bdd", "tests", "impl"},
+		"bad name":         {"prd", "Design", "seed", "bdd", "tests", "impl"},
+	}
+	for name, stages := range cases {
+		err := validateStages(stages)
+		if err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+			t.Errorf("%s: expected err:config, got %v", name, err)
+		}
+	}
+	if err := validateStages([]string{"prd", "design", "seed", "bdd", "tests", "impl", "security"}); err != nil {
+		t.Errorf("valid pipeline rejected: %v", err)
+	}
+}
+
+func TestLoadConfigRejectsInvalidPipeline(t *testing.T) {
+	dir := writeTestConfig(t, "project:\n  name: X\npipeline:\n  stages: [prd, seed]\n")
+	if _, err := LoadConfig(dir); err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Errorf("expected err:config, got %v", err)
+	}
+}
+
+func TestRecordReviewCustomStage(t *testing.T) {
+	dir := setupDesignPipeline(t, "auth:in-progress")
+
+	if err := RecordReview(dir, "auth", "design", 8); err != nil {
+		t.Fatalf("RecordReview design: %v", err)
+	}
+	if passed, _ := CheckReviewGate(dir, "auth", "design"); !passed {
+		t.Error("design review should pass")
+	}
+
+	err := RecordReview(dir, "auth", "security", 8)
+	if err == nil || !strings.Contains(err.Error(), "prd|design|seed|bdd|tests|impl") {
+		t.Errorf("expected unknown stage error listing pipeline, got %v", err)
+	}
+}
+
+func TestContextSuggestsCustomStageReview(t *testing.T) {
+	dir := setupDesignPipeline(t, "auth:in-progress")
+	if err := RecordReview(dir, "auth", "prd", 8); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := BuildContext(dir)
+	if err != nil {
+		t.Fatalf("BuildContext: %v", err)
+	}
+	found := false
+	for _, l := range result.Lines {
+		if l.Feature == "auth" && l.Type == ContextNext && l.Action == "review-design" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected next review-design for auth, got %+v", result.Lines)
+	}
+}
+
+func TestGateCheckBlocksUntilCustomStageReviewed(t *testing.T) {
+	dir := setupDesignPipeline(t, "auth:in-progress")
+
+	res := GateCheck(dir, ".ptsd/seeds/auth/seed.yaml")
+	if res.Allowed {
+		t.Fatal("seed write must be blocked before design review")
+	}
+	if !strings.Contains(res.Reason, "ptsd review auth design") {
+		t.Errorf("reason should point at the design review, got %q", res.Reason)
+	}
+
+	if err := RecordReview(dir, "auth", "design", 8); err != nil {
+		t.Fatal(err)
+	}
+	if res := GateCheck(dir, ".ptsd/seeds/auth/seed.yaml"); !res.Allowed {
+		t.Errorf("seed write should be allowed after design review: %s", res.Reason)
+	}
+}
+
+func TestGenerateSkillsForCustomStage(t *testing.T) {
+	dir := setupDesignPipeline(t)
+	if err := GenerateAllSkills(dir); err != nil {
+		t.Fatalf("GenerateAllSkills: %v", err)
+	}
+	skills, err := ListSkills(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, s := range skills {
+		if s.ID == "review-design" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected review-design skill for custom stage")
+	}
+}
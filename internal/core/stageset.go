@@ -0,0 +1,131 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StageChange reports a stage set by hand with `ptsd stage set`.
+type StageChange struct {
+	Feature string
+	From    string
+	To      string
+	Why     string
+	// Forced lists the guards that failed and were overridden with --force.
+	Forced []string
+}
+
+// SetStage moves featureID to stage in state.yaml and review-status.yaml,
+// for recovering from states reviews and auto-tracking cannot fix. The
+// target stage's guards must hold unless force is set; why is required and
+// recorded with the stage event.
+func SetStage(projectDir, featureID, stage, why string, force bool) (StageChange, error) {
+	change := StageChange{Feature: featureID, To: stage, Why: strings.TrimSpace(why)}
+	if change.Why == "" {
+		return change, fmt.Errorf("err:user --why is required: say why the stage is set by hand")
+	}
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return change, err
+	}
+	found := false
+	for _, f := range features {
+		found = found || f.ID == featureID
+	}
+	if !found {
+		return change, fmt.Errorf("err:validation feature %s not found", featureID)
+	}
+	stages := FeatureStages(projectDir, featureID)
+	if stageRank(stages, stage) < 0 {
+		return change, fmt.Errorf("err:user invalid stage %q: must be %s", stage, strings.Join(stages, "|"))
+	}
+
+	state, err := LoadState(projectDir)
+	if err != nil {
+		return change, err
+	}
+	fs, ok := state.Features[featureID]
+	if !ok {
+		fs = FeatureState{Hashes: make(map[string]string), Scores: make(map[string]ScoreEntry)}
+	}
+	change.From = fs.Stage
+	if fs.Stage == stage {
+		return change, fmt.Errorf("err:validation %s is already at stage %s", featureID, stage)
+	}
+
+	if guards := stageGuards(projectDir, featureID, stages, stage, state); len(guards) > 0 {
+		if !force {
+			return change, fmt.Errorf("err:pipeline cannot set %s to %s: %s (fix them, or pass --force)", featureID, stage, strings.Join(guards, "; "))
+		}
+		change.Forced = guards
+	}
+
+	fs.Stage = stage
+	state.Features[featureID] = fs
+	if err := writeState(projectDir, state); err != nil {
+		return change, err
+	}
+	rs, err := loadReviewStatus(projectDir)
+	if err != nil {
+		return change, err
+	}
+	entry, ok := rs[featureID]
+	if !ok {
+		entry = ReviewStatusEntry{Tests: "absent", Review: "pending"}
+	}
+	entry.Stage = stage
+	rs[featureID] = entry
+	if err := saveReviewStatus(projectDir, rs); err != nil {
+		return change, fmt.Errorf("err:io failed to save review-status: %w", err)
+	}
+
+	from := change.From
+	if from == "" {
+		from = "none"
+	}
+	detail := "set from " + from + ": " + change.Why
+	if len(change.Forced) > 0 {
+		detail += " (forced: " + strings.Join(change.Forced, "; ") + ")"
+	}
+	if err := AppendEvent(projectDir, Event{Type: EventStage, Feature: featureID, Stage: stage, Detail: detail}); err != nil {
+		return change, err
+	}
+	return change, nil
+}
+
+// stageGuards returns why featureID cannot sit at stage: every built-in stage
+// up to it needs its artifact, every stage before it a passing review, and
+// past BDD the feature's dependencies must be implemented.
+func stageGuards(projectDir, featureID string, stages []string, stage string, state *State) []string {
+	var guards []string
+	target := stageRank(stages, stage)
+	for i, s := range stages[:target+1] {
+		switch s {
+		case "prd":
+			if prdFileFor(projectDir, featureID) == "" {
+				guards = append(guards, "no PRD anchor")
+			}
+		case "seed":
+			if !fileExists(seedManifestPath(projectDir, featureID)) {
+				guards = append(guards, "no seed.yaml")
+			}
+		case "bdd":
+			if !fileExists(bddFilePath(projectDir, featureID)) {
+				guards = append(guards, "no BDD feature file")
+			}
+		case "tests":
+			if !hasTestsForFeature(projectDir, featureID, state, ProjectWalkLimits(projectDir)) {
+				guards = append(guards, "no tests")
+			}
+		}
+		if i < target {
+			if passed, _ := CheckReviewGate(projectDir, featureID, s); !passed {
+				guards = append(guards, s+" review not passed")
+			}
+		}
+	}
+	if blocked, reason := dependencyGate(projectDir, featureID, stage); blocked {
+		guards = append(guards, reason)
+	}
+	return guards
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetStageGuards(t *testing.T) {
+	dir := setupProjectWithFeature(t, "user-auth", func(base string) {
+		writeFeaturesYAML(t, base, "  - id: user-auth\n    status: in-progress\n")
+		createPRDAnchor(t, base, "user-auth")
+	})
+
+	_, err := SetStage(dir, "user-auth", "bdd", "recovering after restore", false)
+	if err == nil || !strings.HasPrefix(err.Error(), "err:pipeline") {
+		t.Fatalf("expected pipeline error, got %v", err)
+	}
+	for _, want := range []string{"no seed.yaml", "no BDD feature file", "prd review not passed", "seed review not passed"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+	if state, _ := LoadState(dir); state.Features["user-auth"].Stage != "" {
+		t.Errorf("stage changed despite failed guards: %q", state.Features["user-auth"].Stage)
+	}
+
+	if _, err := SetStage(dir, "user-auth", "bdd", "", false); err == nil || !strings.Contains(err.Error(), "--why is required") {
+		t.Errorf("expected --why error, got %v", err)
+	}
+	if _, err := SetStage(dir, "user-auth", "deploy", "x", false); err == nil || !strings.HasPrefix(err.Error(), "err:user invalid stage") {
+		t.Errorf("expected invalid stage error, got %v", err)
+	}
+	if _, err := SetStage(dir, "nope", "prd", "x", false); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected unknown feature error, got %v", err)
+	}
+}
+
+func TestSetStageRecordsEvent(t *testing.T) {
+	dir := setupProjectWithFeature(t, "user-auth", func(base string) {
+		writeFeaturesYAML(t, base, "  - id: user-auth\n    status: in-progress\n")
+		createPRDAnchor(t, base, "user-auth")
+		createSeed(t, base, "user-auth")
+	})
+	if err := RecordReview(dir, "user-auth", "prd", 8); err != nil {
+		t.Fatal(err)
+	}
+
+	change, err := SetStage(dir, "user-auth", "seed", "state.yaml lost the seed advance", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.From != "prd" || change.To != "seed" || len(change.Forced) != 0 {
+		t.Errorf("unexpected change: %+v", change)
+	}
+	state, _ := LoadState(dir)
+	if state.Features["user-auth"].Stage != "seed" {
+		t.Errorf("expected stage seed in state.yaml, got %q", state.Features["user-auth"].Stage)
+	}
+	rs, _ := loadReviewStatus(dir)
+	if rs["user-auth"].Stage != "seed" {
+		t.Errorf("expected stage seed in review-status.yaml, got %q", rs["user-auth"].Stage)
+	}
+	events, _ := LoadEvents(dir)
+	last := events[len(events)-1]
+	if last.Type != EventStage || last.Stage != "seed" || last.Detail != "set from prd: state.yaml lost the seed advance" {
+		t.Errorf("unexpected event: %+v", last)
+	}
+
+	if _, err := SetStage(dir, "user-auth", "seed", "again", false); err == nil || !strings.Contains(err.Error(), "already at stage seed") {
+		t.Errorf("expected already-at-stage error, got %v", err)
+	}
+}
+
+func TestSetStageForce(t *testing.T) {
+	dir := setupProjectWithFeature(t, "user-auth", func(base string) {
+		writeFeaturesYAML(t, base, "  - id: user-auth\n    status: in-progress\n")
+		createPRDAnchor(t, base, "user-auth")
+	})
+	change, err := SetStage(dir, "user-auth", "seed", "seed lives in another repo", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(change.Forced, "; ") != "prd review not passed; no seed.yaml" {
+		t.Errorf("unexpected forced guards: %v", change.Forced)
+	}
+	events, _ := LoadEvents(dir)
+	if d := events[len(events)-1].Detail; !strings.Contains(d, "(forced: prd review not passed; no seed.yaml)") {
+		t.Errorf("expected forced guards in event detail, got %q", d)
+	}
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Review staleness. With review.stale_after set, a stage whose last passing
+// review is older than the threshold is flagged by validate (warning) and
+// context, even when none of its files changed, so long-lived features get
+// re-reviewed periodically. Scores without a known time (migrated from
+// schema 1) are never flagged.
+
+// StaleReview is a passing review older than review.stale_after.
+type StaleReview struct {
+	Feature    string
+	Stage      string
+	Score      int
+	ReviewedAt time.Time
+	Age        time.Duration
+}
+
+// parseAge parses review.stale_after: a Go duration ("720h") or a whole
+// number of days or weeks ("30d", "2w").
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if num, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.Atoi(num)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// formatAge renders a duration in whole days when it is one ("30d"), else
+// as a Go duration.
+func formatAge(d time.Duration) string {
+	if d > 0 && d%(24*time.Hour) == 0 {
+		return strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	}
+	return d.String()
+}
+
+// approxAge renders an age in whole days, or minutes below a day.
+func approxAge(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return formatAge(d.Truncate(24 * time.Hour))
+	}
+	return d.Round(time.Minute).String()
+}
+
+// StaleReviews lists passing reviews of active features older than
+// review.stale_after at now, in feature then pipeline order. It returns
+// nothing when stale_after is not set.
+func StaleReviews(projectDir string, now time.Time) ([]StaleReview, error) {
+	cfg, err := LoadConfig(projectDir)
+	if err != nil || cfg.Review.StaleAfter <= 0 {
+		return nil, nil
+	}
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	state, err := LoadState(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []StaleReview
+	for _, f := range features {
+		if f.Status == "planned" || f.Status == "deferred" {
+			continue
+		}
+		minScore := cfg.ForFeature(f.ID).Review.MinScore
+		stages := FeatureStages(projectDir, f.ID)
+		var stale []StaleReview
+		for stage, sc := range state.Features[f.ID].Scores {
+			if sc.Value < minScore || sc.Timestamp.IsZero() {
+				continue
+			}
+			if age := now.Sub(sc.Timestamp); age > cfg.Review.StaleAfter {
+				stale = append(stale, StaleReview{Feature: f.ID, Stage: stage, Score: sc.Value, ReviewedAt: sc.Timestamp, Age: age})
+			}
+		}
+		sort.Slice(stale, func(i, j int) bool {
+			return stageRank(stages, stale[i].Stage) < stageRank(stages, stale[j].Stage)
+		})
+		out = append(out, stale...)
+	}
+	return out, nil
+}
+
+// checkStaleReviews reports stale reviews as validate warnings.
+func checkStaleReviews(projectDir string, now time.Time) []ValidationError {
+	stale, _ := StaleReviews(projectDir, now)
+	if len(stale) == 0 {
+		return nil
+	}
+	cfg, _ := LoadConfig(projectDir)
+	var errs []ValidationError
+	for _, s := range stale {
+		errs = append(errs, ValidationError{
+			Feature:  s.Feature,
+			Category: "pipeline",
+			Rule:     "stale-review",
+			Severity: "warn",
+			Message: fmt.Sprintf("%s review passed %s ago (review.stale_after %s): re-review with ptsd review %s %s <score>",
+				s.Stage, approxAge(s.Age), formatAge(cfg.Review.StaleAfter), s.Feature, s.Stage),
+		})
+	}
+	return errs
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupStaleProject(t *testing.T, staleAfter string) (string, time.Time) {
+	t.Helper()
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:deferred")
+	writePtsdFile(t, dir, "ptsd.yaml", "project:\n  name: Stale\nreview:\n  min_score: 7\n  stale_after: "+staleAfter+"\n")
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	state := &State{Features: map[string]FeatureState{
+		"auth": {Stage: "bdd", Hashes: map[string]string{}, Scores: map[string]ScoreEntry{
+			"prd":  {Value: 9, Timestamp: now.AddDate(0, 0, -45)},
+			"seed": {Value: 8, Timestamp: now.AddDate(0, 0, -10)},
+			"bdd":  {Value: 4, Timestamp: now.AddDate(0, 0, -60)}, // failing, not a pass
+			"impl": {Value: 9},                                    // unknown time
+		}},
+		"billing": {Stage: "prd", Hashes: map[string]string{}, Scores: map[string]ScoreEntry{
+			"prd": {Value: 9, Timestamp: now.AddDate(0, 0, -90)},
+		}},
+	}}
+	if err := writeState(dir, state); err != nil {
+		t.Fatal(err)
+	}
+	return dir, now
+}
+
+func TestStaleReviews(t *testing.T) {
+	dir, now := setupStaleProject(t, "30d")
+	stale, err := StaleReviews(dir, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 1 || stale[0].Feature != "auth" || stale[0].Stage != "prd" || stale[0].Score != 9 {
+		t.Fatalf("expected only auth/prd to be stale, got %+v", stale)
+	}
+	if stale[0].Age != 45*24*time.Hour {
+		t.Errorf("Age = %v, want 45 days", stale[0].Age)
+	}
+
+	warnings := checkStaleReviews(dir, now)
+	if len(warnings) != 1 || warnings[0].Rule != "stale-review" || !warnings[0].IsWarning() {
+		t.Fatalf("expected one stale-review warning, got %+v", warnings)
+	}
+	if !strings.Contains(warnings[0].Message, "prd review passed 45d ago (review.stale_after 30d)") {
+		t.Errorf("unexpected message: %s", warnings[0].Message)
+	}
+}
+
+func TestStaleReviewsWeeksAndDisabled(t *testing.T) {
+	dir, now := setupStaleProject(t, "1w")
+	stale, _ := StaleReviews(dir, now)
+	if len(stale) != 2 {
+		t.Errorf("expected prd and seed stale after 1w, got %+v", stale)
+	}
+
+	writePtsdFile(t, dir, "ptsd.yaml", "project:\n  name: Stale\n")
+	if stale, _ := StaleReviews(dir, now); len(stale) != 0 {
+		t.Errorf("expected nothing without review.stale_after, got %+v", stale)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	for in, want := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "2w": 14 * 24 * time.Hour, "36h": 36 * time.Hour} {
+		if got, err := parseAge(in); err != nil || got != want {
+			t.Errorf("parseAge(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "soon", "-3d", "1.5d"} {
+		if _, err := parseAge(in); err == nil {
+			t.Errorf("parseAge(%q): expected an error", in)
+		}
+	}
+
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	writePtsdFile(t, dir, "ptsd.yaml", "project:\n  name: Bad\nreview:\n  stale_after: monthly\n")
+	if _, err := LoadConfig(dir); err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Errorf("expected err:config for an invalid stale_after, got %v", err)
+	}
+}
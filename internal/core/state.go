@@ -22,10 +22,16 @@ type FeatureState struct {
 type ScoreEntry struct {
 	Value     int
 	Timestamp time.Time
+	// Reviewers holds each named reviewer's latest score for the stage.
+	// Value is the most recent score; ReviewGate combines them.
+	Reviewers map[string]int
 }
 
 type State struct {
 	Features map[string]FeatureState
+	// Unmapped lists test files adopt discovered that no feature maps yet
+	// (see UnmappedTests).
+	Unmapped []string
 }
 
 type RegressionWarning struct {
@@ -38,8 +44,32 @@ type RegressionWarning struct {
 }
 
 func LoadState(projectDir string) (*State, error) {
+	state, err := readStateFile(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	normalizeStatePaths(state)
+	return state, nil
+}
+
+// normalizeStatePaths rewrites the paths in test mappings and unmapped
+// files with forward slashes (see slashPath).
+func normalizeStatePaths(state *State) {
+	for _, fs := range state.Features {
+		if tests, ok := fs.Tests.([]string); ok {
+			for i, t := range tests {
+				tests[i] = slashPath(t)
+			}
+		}
+	}
+	for i, f := range state.Unmapped {
+		state.Unmapped[i] = slashPath(f)
+	}
+}
+
+func readStateFile(projectDir string) (*State, error) {
 	statePath := filepath.Join(projectDir, ".ptsd", "state.yaml")
-	data, err := os.ReadFile(statePath)
+	data, err := readFileCached(statePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return &State{Features: make(map[string]FeatureState)}, nil
@@ -47,7 +77,13 @@ func LoadState(projectDir string) (*State, error) {
 		return nil, fmt.Errorf("err:io %w", err)
 	}
 
-	return parseState(string(data))
+	return stateMemo.get(string(data), func(data string) (*State, error) {
+		content, err := upgradeSchema("state.yaml", data)
+		if err != nil {
+			return nil, err
+		}
+		return parseState(content)
+	})
 }
 
 func parseState(content string) (*State, error) {
@@ -57,6 +93,7 @@ func parseState(content string) (*State, error) {
 	var currentFeature string
 	var currentSection string
 	var currentScoreStage string
+	inUnmapped := false
 
 	for i := 0; i < len(lines); i++ {
 		line := lines[i]
@@ -68,6 +105,20 @@ func parseState(content string) (*State, error) {
 
 		indent := len(line) - len(strings.TrimLeft(line, " "))
 
+		if indent == 0 {
+			inUnmapped = trimmed == "unmapped:"
+			if inUnmapped {
+				currentFeature = ""
+			}
+			continue
+		}
+		if inUnmapped {
+			if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+				state.Unmapped = append(state.Unmapped, item)
+			}
+			continue
+		}
+
 		if indent == 2 && strings.HasSuffix(trimmed, ":") && !strings.Contains(trimmed, " ") {
 			currentFeature = strings.TrimSuffix(trimmed, ":")
 			if _, ok := state.Features[currentFeature]; !ok {
@@ -124,6 +175,20 @@ func parseState(content string) (*State, error) {
 			continue
 		}
 
+		if indent == 10 && currentSection == "scores" && currentScoreStage != "" {
+			if name, val, ok := strings.Cut(trimmed, ": "); ok {
+				n, _ := strconv.Atoi(val)
+				fs := state.Features[currentFeature]
+				entry := fs.Scores[currentScoreStage]
+				if entry.Reviewers == nil {
+					entry.Reviewers = make(map[string]int)
+				}
+				entry.Reviewers[stripQuotes(name)] = n
+				fs.Scores[currentScoreStage] = entry
+			}
+			continue
+		}
+
 		if indent == 8 && currentSection == "scores" && currentScoreStage != "" {
 			if strings.HasPrefix(trimmed, "score: ") {
 				val, _ := strconv.Atoi(strings.TrimPrefix(trimmed, "score: "))
@@ -187,12 +252,12 @@ func SyncState(projectDir string) error {
 			}
 		}
 
-		seedPath := filepath.Join(projectDir, ".ptsd", "seeds", f.ID, "seed.yaml")
+		seedPath := seedManifestPath(projectDir, f.ID)
 		if h, err := computeFileHash(seedPath); err == nil {
 			fs.Hashes["seed"] = h
 		}
 
-		bddPath := filepath.Join(projectDir, ".ptsd", "bdd", f.ID+".feature")
+		bddPath := bddFilePath(projectDir, f.ID)
 		if h, err := computeFileHash(bddPath); err == nil {
 			fs.Hashes["bdd"] = h
 		}
@@ -202,8 +267,7 @@ func SyncState(projectDir string) error {
 			fs.Hashes["test"] = h
 		}
 
-		prdPath := filepath.Join(projectDir, ".ptsd", "docs", "PRD.md")
-		if h, err := computeFileHash(prdPath); err == nil {
+		if h, err := computeFileHash(prdPathFor(projectDir, f.ID)); err == nil {
 			fs.Hashes["prd"] = h
 		}
 
@@ -218,12 +282,55 @@ func CheckRegressions(projectDir string) ([]RegressionWarning, error) {
 	if err != nil {
 		return nil, err
 	}
+	if _, changed := detectRegressions(projectDir, state); !changed {
+		return nil, nil
+	}
+
+	// Something moved: check again under the project lock and persist.
+	unlock, err := LockProject(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if state, err = LoadState(projectDir); err != nil {
+		return nil, err
+	}
+	warnings, changed := detectRegressions(projectDir, state)
+	if !changed {
+		return nil, nil
+	}
 
-	var warnings []RegressionWarning
+	if err := writeState(projectDir, state); err != nil {
+		return warnings, fmt.Errorf("err:io failed to persist state: %w", err)
+	}
+
+	for _, w := range warnings {
+		recordEvent(projectDir, Event{Type: EventRegression, Feature: w.Feature, Stage: w.FileType, Detail: w.Message})
+		if w.Severity == "error" {
+			// The downgrade starts a new stage for stage durations.
+			recordEvent(projectDir, Event{Type: EventStage, Feature: w.Feature, Stage: w.FileType, Detail: "regression: " + w.Message})
+		}
+	}
+
+	return warnings, nil
+}
+
+// detectRegressions compares the artifact hashes in state with the files,
+// in feature order, and applies the downgrades and new hashes to state
+// without writing it; changed reports whether any hash moved.
+// CheckRegressions persists them; context only reports.
+func detectRegressions(projectDir string, state *State) (warnings []RegressionWarning, changed bool) {
 	stageOrder := map[string]int{"prd": 0, "seed": 1, "bdd": 2, "test": 3, "impl": 4}
 
-	for featureID, fs := range state.Features {
-		currentStageIdx, ok := stageOrder[fs.Stage]
+	stages := PipelineStages(projectDir)
+	featureIDs := make([]string, 0, len(state.Features))
+	for id := range state.Features {
+		featureIDs = append(featureIDs, id)
+	}
+	sort.Strings(featureIDs)
+	for _, featureID := range featureIDs {
+		fs := state.Features[featureID]
+		currentStageIdx, ok := stageOrder[baseStage(stages, fs.Stage)]
 		if !ok {
 			continue
 		}
@@ -235,11 +342,10 @@ func CheckRegressions(projectDir string) ([]RegressionWarning, error) {
 			stageIdx int
 		}
 
-		ptsdDir := filepath.Join(projectDir, ".ptsd")
 		checks := []hashCheck{
-			{"prd", filepath.Join(ptsdDir, "docs", "PRD.md"), "prd", 0},
-			{"seed", filepath.Join(ptsdDir, "seeds", featureID, "seed.yaml"), "seed", 1},
-			{"bdd", filepath.Join(ptsdDir, "bdd", featureID+".feature"), "bdd", 2},
+			{"prd", prdPathFor(projectDir, featureID), "prd", 0},
+			{"seed", seedManifestPath(projectDir, featureID), "seed", 1},
+			{"bdd", bddFilePath(projectDir, featureID), "bdd", 2},
 			{"test", filepath.Join(projectDir, "internal", "core", featureID+"_test.go"), "test", 3},
 		}
 
@@ -257,6 +363,7 @@ func CheckRegressions(projectDir string) ([]RegressionWarning, error) {
 			if newHash == oldHash {
 				continue
 			}
+			changed = true
 
 			if c.stageIdx < currentStageIdx {
 				if c.fileType == "prd" {
@@ -305,16 +412,19 @@ func CheckRegressions(projectDir string) ([]RegressionWarning, error) {
 		}
 	}
 
-	if err := writeState(projectDir, state); err != nil {
-		return warnings, fmt.Errorf("err:io failed to persist state: %w", err)
-	}
-
-	return warnings, nil
+	return warnings, changed
 }
 
 type ProjectStatusResult struct {
 	Features    map[string]FeatureState
 	Regressions []RegressionWarning
+	// Revisit lists deferred features whose revisit date has passed.
+	Revisit []Feature
+	// Epics rolls up every feature with children.
+	Epics []EpicRollup
+	// ETAs and Milestones estimate completion from stage history.
+	ETAs       []FeatureETA
+	Milestones []MilestoneETA
 }
 
 // ComputeStageFromArtifacts determines a feature's pipeline stage by checking on-disk artifacts.
@@ -330,13 +440,7 @@ func ComputeStageFromArtifacts(projectDir, featureID string) string {
 
 	// tests: test files exist
 	hasTests := false
-	filepath.Walk(projectDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			if info != nil && info.IsDir() && (strings.Contains(path, ".ptsd") || strings.Contains(path, ".git")) {
-				return filepath.SkipDir
-			}
-			return nil
-		}
+	walkProject(projectDir, ProjectWalkLimits(projectDir), func(path string, info os.FileInfo) error {
 		base := filepath.Base(path)
 		if strings.Contains(base, featureID) && strings.HasSuffix(path, "_test.go") {
 			hasTests = true
@@ -349,45 +453,65 @@ func ComputeStageFromArtifacts(projectDir, featureID string) string {
 	}
 
 	// bdd
-	bddPath := filepath.Join(projectDir, ".ptsd", "bdd", featureID+".feature")
+	bddPath := bddFilePath(projectDir, featureID)
 	if _, err := os.Stat(bddPath); err == nil {
 		return "bdd"
 	}
 
 	// seed
-	seedPath := filepath.Join(projectDir, ".ptsd", "seeds", featureID, "seed.yaml")
+	seedPath := seedManifestPath(projectDir, featureID)
 	if _, err := os.Stat(seedPath); err == nil {
 		return "seed"
 	}
 
 	// prd anchor
-	prdPath := filepath.Join(projectDir, ".ptsd", "docs", "PRD.md")
-	if data, err := os.ReadFile(prdPath); err == nil {
-		anchor := "<!-- feature:" + featureID + " -->"
-		if strings.Contains(string(data), anchor) {
-			return "prd"
-		}
+	if prdFileFor(projectDir, featureID) != "" {
+		return "prd"
 	}
 
 	return ""
 }
 
 // ProjectStatus returns current feature states and auto-triggers regression detection.
+// State is only written, under the project lock, when something changed.
 func ProjectStatus(projectDir string) (ProjectStatusResult, error) {
-	state, err := LoadState(projectDir)
-	if err != nil {
+	if _, err := LoadState(projectDir); err != nil {
 		return ProjectStatusResult{}, err
 	}
 
-	regressions, _ := CheckRegressions(projectDir)
+	regressions, err := CheckRegressions(projectDir)
+	if err != nil {
+		return ProjectStatusResult{}, err
+	}
 
 	// Reload state after regression check (CheckRegressions may update stages/hashes).
-	state, err = LoadState(projectDir)
+	state, err := LoadState(projectDir)
 	if err != nil {
 		return ProjectStatusResult{}, err
 	}
 
-	// Fill in missing stages from on-disk artifacts
+	if fillComputedStages(projectDir, state) {
+		unlock, err := LockProject(projectDir)
+		if err != nil {
+			return ProjectStatusResult{}, err
+		}
+		if fresh, err := LoadState(projectDir); err == nil && fillComputedStages(projectDir, fresh) {
+			_ = writeState(projectDir, fresh)
+			state = fresh
+		}
+		unlock()
+	}
+
+	now := time.Now()
+	revisit, _ := DueForRevisit(projectDir, now)
+	epics, _ := EpicRollups(projectDir)
+	etas, milestones, _ := EstimateCompletion(projectDir, now)
+	return ProjectStatusResult{Features: state.Features, Regressions: regressions, Revisit: revisit, Epics: epics, ETAs: etas, Milestones: milestones}, nil
+}
+
+// fillComputedStages fills in missing stages from on-disk artifacts and
+// reports whether any were set.
+func fillComputedStages(projectDir string, state *State) bool {
 	features, _ := loadFeatures(projectDir)
 	stateUpdated := false
 	for _, f := range features {
@@ -408,17 +532,13 @@ func ProjectStatus(projectDir string) (ProjectStatusResult, error) {
 		}
 	}
 
-	if stateUpdated {
-		_ = writeState(projectDir, state)
-	}
-
-	return ProjectStatusResult{Features: state.Features, Regressions: regressions}, nil
+	return stateUpdated
 }
 
 func writeState(projectDir string, state *State) error {
-	statePath := filepath.Join(projectDir, ".ptsd", "state.yaml")
-
+	normalizeStatePaths(state)
 	var b strings.Builder
+	b.WriteString(schemaHeader())
 	b.WriteString("features:\n")
 
 	// Sort feature keys for deterministic output
@@ -454,6 +574,17 @@ func writeState(projectDir string, state *State) error {
 			b.WriteString("      " + stage + ":\n")
 			b.WriteString("        score: " + strconv.Itoa(entry.Value) + "\n")
 			b.WriteString("        at: \"" + entry.Timestamp.Format(time.RFC3339Nano) + "\"\n")
+			if len(entry.Reviewers) > 0 {
+				b.WriteString("        reviewers:\n")
+				names := make([]string, 0, len(entry.Reviewers))
+				for name := range entry.Reviewers {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					b.WriteString("          \"" + name + "\": " + strconv.Itoa(entry.Reviewers[name]) + "\n")
+				}
+			}
 		}
 
 		if fs.Tests != nil {
@@ -466,7 +597,14 @@ func writeState(projectDir string, state *State) error {
 		}
 	}
 
-	return os.WriteFile(statePath, []byte(b.String()), 0644)
+	if len(state.Unmapped) > 0 {
+		b.WriteString("unmapped:\n")
+		for _, f := range state.Unmapped {
+			b.WriteString("  - " + f + "\n")
+		}
+	}
+
+	return signFileWrite(projectDir, "state.yaml", []byte(b.String()))
 }
 
 func computeFileHash(path string) (string, error) {
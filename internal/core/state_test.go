@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -113,6 +114,12 @@ func TestPRDChangeCreatesRedoTasks(t *testing.T) {
 	if !found {
 		t.Error("expected prd regression for user-auth")
 	}
+
+	// The downgrade is a stage change for stage durations.
+	events, _ := LoadEvents(dir)
+	if n := len(events); n == 0 || events[n-1].Type != EventStage || events[n-1].Stage != "prd" {
+		t.Errorf("expected a prd stage event for the downgrade, got %+v", events)
+	}
 }
 
 func TestSeedChangeWarning(t *testing.T) {
@@ -347,3 +354,45 @@ func appendFile(t *testing.T, path, content string) {
 		t.Fatalf("cannot write %s: %v", path, err)
 	}
 }
+
+func TestCheckRegressionsLeavesCleanStateAlone(t *testing.T) {
+	dir := t.TempDir()
+	setupFeatureFiles(t, dir, "user-auth", "seed", "bdd", "test")
+	setState(t, dir, "user-auth", "bdd", nil, nil)
+	if _, err := CheckRegressions(dir); err != nil {
+		t.Fatal(err)
+	}
+	statePath := filepath.Join(dir, ".ptsd", "state.yaml")
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(statePath, old, old)
+
+	if _, err := CheckRegressions(dir); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(statePath); !info.ModTime().Equal(old) {
+		t.Error("CheckRegressions rewrote state.yaml with nothing changed")
+	}
+}
+
+func TestProjectStatusLocksToPersistRegressions(t *testing.T) {
+	dir := t.TempDir()
+	setupFeatureFiles(t, dir, "user-auth", "seed", "bdd", "test")
+	setState(t, dir, "user-auth", "impl", nil, nil)
+	SetLockWait(0)
+	defer SetLockWait(DefaultLockWait)
+
+	// Another process holding the project lock.
+	release, ok, err := tryLockFile(filepath.Join(dir, ".ptsd", lockFile))
+	if err != nil || !ok {
+		t.Fatalf("cannot take the lock: ok=%v err=%v", ok, err)
+	}
+	defer release()
+	bddPath := filepath.Join(dir, ".ptsd", "bdd", "user-auth.feature")
+	appendFile(t, bddPath, "\n# modified scenario")
+	if _, err := ProjectStatus(dir); err == nil || !strings.Contains(err.Error(), "project is locked") {
+		t.Errorf("expected status to need the project lock, got %v", err)
+	}
+	if _, err := CheckRegressions(dir); err == nil || !strings.Contains(err.Error(), "project is locked") {
+		t.Errorf("expected the regression check to need the project lock, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task comments. `ptsd task comment <id> "text"` appends a timestamped note
+// to the task in tasks.yaml, so the rationale behind a task survives the
+// session that wrote it: task show and context --for-task print the log
+// for whoever picks the task up next.
+
+// TaskComment is one entry of a task's discussion log.
+type TaskComment struct {
+	At     time.Time
+	Author string
+	Text   string
+}
+
+// CommentTask appends a comment by author ($PTSD_AGENT or user@host when
+// empty) to task id.
+func CommentTask(projectDir, id, author, text string) (TaskComment, error) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return TaskComment{}, fmt.Errorf("err:user comment text is required")
+	}
+	if author == "" {
+		author = LeaseOwner()
+	}
+
+	tasks, err := loadTasks(projectDir)
+	if err != nil {
+		return TaskComment{}, err
+	}
+	c := TaskComment{At: time.Now().UTC().Truncate(time.Second), Author: author, Text: text}
+	for i := range tasks {
+		if tasks[i].ID == id {
+			tasks[i].Comments = append(tasks[i].Comments, c)
+			if err := saveTasks(projectDir, tasks); err != nil {
+				return TaskComment{}, fmt.Errorf("err:io %w", err)
+			}
+			return c, nil
+		}
+	}
+	return TaskComment{}, fmt.Errorf("err:validation task %s not found", id)
+}
+
+// GetTask returns task id with its comments.
+func GetTask(projectDir, id string) (Task, error) {
+	tasks, err := loadTasks(projectDir)
+	if err != nil {
+		return Task{}, err
+	}
+	for _, t := range tasks {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return Task{}, fmt.Errorf("err:validation task %s not found", id)
+}
+
+func writeTaskComments(b *strings.Builder, comments []TaskComment) {
+	if len(comments) == 0 {
+		return
+	}
+	b.WriteString("    comments:\n")
+	for _, c := range comments {
+		b.WriteString("      - at: " + c.At.UTC().Format(time.RFC3339) + "\n")
+		b.WriteString("        author: " + strconv.Quote(c.Author) + "\n")
+		b.WriteString("        text: " + strconv.Quote(c.Text) + "\n")
+	}
+}
+
+// parseTaskCommentLine applies one trimmed line of a task's comments block.
+func parseTaskCommentLine(comments []TaskComment, line string) []TaskComment {
+	if rest, ok := strings.CutPrefix(line, "- "); ok {
+		comments = append(comments, TaskComment{})
+		line = rest
+	}
+	if len(comments) == 0 {
+		return comments
+	}
+	key, val, ok := strings.Cut(line, ": ")
+	if !ok {
+		return comments
+	}
+	if unq, err := strconv.Unquote(val); err == nil {
+		val = unq
+	}
+	c := &comments[len(comments)-1]
+	switch key {
+	case "at":
+		c.At, _ = time.Parse(time.RFC3339, val)
+	case "author":
+		c.Author = val
+	case "text":
+		c.Text = val
+	}
+	return comments
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommentTaskKeepsLogThroughTaskWrites(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "user-auth")
+	if _, err := AddTask(dir, "user-auth", "Implement login", "A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AddTask(dir, "user-auth", "Implement logout", "B"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CommentTask(dir, "T-1", "alice", "JWT over sessions: \"stateless\"\nscales better"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PTSD_AGENT", "agent-1")
+	if _, err := CommentTask(dir, "T-1", "", "refresh tokens left for T-2"); err != nil {
+		t.Fatal(err)
+	}
+	// Unrelated writes keep the comments.
+	if err := UpdateTask(dir, "T-2", "WIP"); err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := GetTask(dir, "T-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(task.Comments) != 2 {
+		t.Fatalf("expected 2 comments, got %+v", task.Comments)
+	}
+	first, second := task.Comments[0], task.Comments[1]
+	if first.Author != "alice" || first.Text != "JWT over sessions: \"stateless\" scales better" || first.At.IsZero() {
+		t.Errorf("unexpected first comment: %+v", first)
+	}
+	if second.Author != "agent-1" || second.Text != "refresh tokens left for T-2" {
+		t.Errorf("unexpected second comment: %+v", second)
+	}
+	if task.Title != "Implement login" || task.Priority != "A" {
+		t.Errorf("comments must not disturb task fields: %+v", task)
+	}
+	if other, _ := GetTask(dir, "T-2"); len(other.Comments) != 0 || other.Status != "WIP" {
+		t.Errorf("unexpected T-2: %+v", other)
+	}
+}
+
+func TestCommentTaskErrors(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "user-auth")
+	if _, err := AddTask(dir, "user-auth", "Implement login", "A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CommentTask(dir, "T-9", "", "hello"); err == nil || !strings.HasPrefix(err.Error(), "err:validation") {
+		t.Errorf("expected err:validation for an unknown task, got %v", err)
+	}
+	if _, err := CommentTask(dir, "T-1", "", "  "); err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Errorf("expected err:user for an empty comment, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TaskContext is the context for a single task: `ptsd context --for-task`.
+type TaskContext struct {
+	Task    Task
+	Feature Feature
+	Stage   string
+	// PRD is the feature's PRD section; PRDLines its "start-end" range in
+	// PRDFile.
+	PRD      string
+	PRDFile  string
+	PRDLines string
+	// Gates are the pipeline stages not yet done (current, blocked, pending).
+	Gates     []PipelineStage
+	Scenarios []TracedScenario
+	Uncovered []AcceptanceCriterion
+	// TestStatus and TestFailures come from the last `ptsd test run <feature>`.
+	TestStatus   string
+	TestFailures []string
+	// Files are the paths the task will likely touch: the artifact of the
+	// current stage, mapped test files and implementation files named after
+	// the feature.
+	Files []string
+}
+
+// BuildTaskContext gathers the context for taskID from its feature.
+func BuildTaskContext(projectDir, taskID string) (TaskContext, error) {
+	tasks, err := loadTasks(projectDir)
+	if err != nil {
+		return TaskContext{}, err
+	}
+	var tc TaskContext
+	found := false
+	for _, t := range tasks {
+		if t.ID == taskID {
+			tc.Task, found = t, true
+			break
+		}
+	}
+	if !found {
+		return TaskContext{}, fmt.Errorf("err:user task %s not found", taskID)
+	}
+
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return TaskContext{}, err
+	}
+	for _, f := range features {
+		if f.ID == tc.Task.Feature {
+			tc.Feature = f
+		}
+	}
+	if tc.Feature.ID == "" {
+		return TaskContext{}, fmt.Errorf("err:validation task %s references unknown feature %s", taskID, tc.Task.Feature)
+	}
+	id := tc.Feature.ID
+
+	if section, err := ExtractPRDSection(projectDir, id); err == nil {
+		tc.PRD = strings.TrimSpace(section.Content)
+		tc.PRDFile = section.File
+		tc.PRDLines = fmt.Sprintf("%d-%d", section.StartLine, section.EndLine)
+	}
+
+	pipeline, err := FeaturePipeline(projectDir, id)
+	if err != nil {
+		return TaskContext{}, err
+	}
+	for _, s := range pipeline {
+		if s.State == "current" || s.State == "blocked" {
+			tc.Stage = s.Stage
+		}
+		if s.State != "done" {
+			tc.Gates = append(tc.Gates, s)
+		}
+	}
+	if tc.Stage == "" && len(pipeline) > 0 {
+		tc.Stage = pipeline[len(pipeline)-1].Stage
+	}
+
+	m, err := TraceFeature(projectDir, id)
+	if err != nil {
+		m = traceScenariosOnly(projectDir, id)
+	}
+	tc.Scenarios = m.Scenarios
+	tc.Uncovered = m.Uncovered()
+
+	var testFiles []string
+	if state, err := LoadState(projectDir); err == nil {
+		fs := state.Features[id]
+		tc.TestStatus = fs.Hashes["test_status"]
+		if failures := fs.Hashes["test_failures"]; failures != "" {
+			tc.TestFailures = strings.Split(failures, ", ")
+		}
+		if list, ok := fs.Tests.([]string); ok {
+			_, testFiles = splitTestMappings(list)
+		}
+	}
+
+	tc.Files = taskFiles(projectDir, id, tc.Stage, testFiles, features)
+	return tc, nil
+}
+
+// taskFiles lists the files a task at stage is likely to touch.
+func taskFiles(projectDir, featureID, stage string, testFiles []string, features []Feature) []string {
+	var files []string
+	switch stage {
+	case "prd":
+		file := prdFileFor(projectDir, featureID)
+		if file == "" {
+			file = prdMainRel(projectDir)
+		}
+		files = append(files, file)
+	case "seed":
+		files = append(files, seedDirRel(projectDir, featureID)+"/seed.yaml")
+	case "bdd":
+		files = append(files, bddFileRel(projectDir, featureID))
+	}
+	files = append(files, testFiles...)
+
+	// Implementation files whose name maps to this feature, the same way
+	// gate-check attributes an edited file.
+	if out, err := gitOutput(projectDir, "ls-files", "--cached", "--others", "--exclude-standard"); err == nil {
+		var impl []string
+		for _, rel := range strings.Split(out, "\n") {
+			if rel == "" || !isImplFile(rel) || containsString(testFiles, rel) {
+				continue
+			}
+			base := filepath.Base(rel)
+			name := strings.TrimSuffix(base, filepath.Ext(base))
+			if strings.HasSuffix(name, "_test") || strings.Contains(name, ".test") || strings.Contains(name, ".spec") {
+				continue
+			}
+			if matchFeatureID(name, features) == featureID {
+				impl = append(impl, rel)
+			}
+		}
+		sort.Strings(impl)
+		files = append(files, impl...)
+	}
+	return files
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildTaskContext(t *testing.T) {
+	bdd := "@feature:login\nFeature: Login\n  @ac:AC-1\n  Scenario: Happy path\n    Given a user\n"
+	dir := setupTraceFeature(t, tracePRD, bdd)
+	setupTasks(t, dir, Task{ID: "T-1", Feature: "login", Title: "Lock accounts", Status: "TODO", Priority: "A"})
+	state := "features:\n  login:\n    stage: tests\n    hashes:\n      test_status: failing\n      test_failures: TestLocked, TestWrongPassword\n    scores:\n    tests:\n      - .ptsd/bdd/login.feature::login_test.go\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tc, err := BuildTaskContext(dir, "T-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tc.Feature.ID != "login" || !strings.Contains(tc.PRD, "Locked accounts are refused") {
+		t.Errorf("expected the login PRD section, got %q", tc.PRD)
+	}
+	if len(tc.Scenarios) != 1 || tc.Scenarios[0].Title != "Happy path" {
+		t.Errorf("unexpected scenarios: %+v", tc.Scenarios)
+	}
+	if len(tc.Uncovered) != 2 {
+		t.Errorf("expected 2 uncovered criteria, got %+v", tc.Uncovered)
+	}
+	if tc.TestStatus != "failing" || strings.Join(tc.TestFailures, ",") != "TestLocked,TestWrongPassword" {
+		t.Errorf("unexpected test results: %s %v", tc.TestStatus, tc.TestFailures)
+	}
+	if len(tc.Gates) == 0 || tc.Gates[0].State == "done" {
+		t.Errorf("expected unmet gates, got %+v", tc.Gates)
+	}
+	if !containsString(tc.Files, "login_test.go") {
+		t.Errorf("expected mapped test file among files, got %v", tc.Files)
+	}
+
+	if _, err := BuildTaskContext(dir, "T-9"); err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Errorf("expected err:user for unknown task, got %v", err)
+	}
+}
+
+func TestFailureNames(t *testing.T) {
+	got := failureNames([]string{"TestA", "", "exit status 1: boom\nmore output"})
+	if got != "TestA, exit status 1 boom" {
+		t.Errorf("unexpected failure names: %q", got)
+	}
+}
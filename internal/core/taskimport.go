@@ -0,0 +1,220 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskImportOptions controls ImportTasksMarkdown.
+type TaskImportOptions struct {
+	Feature  string // feature for items not under a feature heading
+	Priority string // priority for items without an (A)/(B)/(C) marker; default B
+	DryRun   bool
+}
+
+// TaskImportSkip is a Markdown line that did not become a task.
+type TaskImportSkip struct {
+	Line   int
+	Text   string
+	Reason string
+}
+
+// TaskImportResult lists the tasks created (or that would be, on dry run)
+// and every skipped or ambiguous line.
+type TaskImportResult struct {
+	Added   []Task
+	Skipped []TaskImportSkip
+}
+
+// ImportTasksMarkdown turns a Markdown checklist into tasks.yaml entries.
+//
+//	## user-auth            heading naming a feature ID or title
+//	- [ ] Login form (A)    TODO, priority A
+//	- [x] Session store     DONE
+//	- [ ] Audit log @audit  inline @<feature> overrides the heading
+//
+// Items before any feature heading, or under a heading that matches no
+// feature, use opts.Feature. Items under a heading whose title matches several
+// features are skipped unless they carry @<feature>. Skipped items, duplicates
+// of existing tasks, and plain list items are reported in Skipped rather than
+// failing the import.
+func ImportTasksMarkdown(projectDir string, content string, opts TaskImportOptions) (TaskImportResult, error) {
+	var result TaskImportResult
+
+	priority := opts.Priority
+	if priority == "" {
+		priority = "B"
+	}
+	if !validTaskPriorities[priority] {
+		return result, fmt.Errorf("err:validation invalid priority %q: must be A|B|C", priority)
+	}
+
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return result, err
+	}
+	known := make(map[string]bool)
+	for _, f := range features {
+		known[f.ID] = true
+	}
+	if opts.Feature != "" && !known[opts.Feature] {
+		return result, fmt.Errorf("err:validation feature %s not found", opts.Feature)
+	}
+
+	tasks, err := loadTasks(projectDir)
+	if err != nil {
+		return result, err
+	}
+	existing := make(map[string]bool)
+	for _, t := range tasks {
+		existing[t.Feature+"\x00"+unquoteTaskTitle(t.Title)] = true
+	}
+	next := maxTaskNum(tasks) + 1
+
+	current := opts.Feature
+	headingProblem := ""
+	for i, raw := range strings.Split(content, "\n") {
+		lineNo := i + 1
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			heading := strings.TrimSpace(strings.TrimLeft(line, "#"))
+			id, matches := matchFeatureHeading(heading, features)
+			switch {
+			case id != "":
+				current, headingProblem = id, ""
+			case len(matches) > 1:
+				current, headingProblem = "", fmt.Sprintf("heading %q is ambiguous: %s", heading, strings.Join(matches, ", "))
+			case opts.Feature != "":
+				current, headingProblem = opts.Feature, ""
+			default:
+				current, headingProblem = "", fmt.Sprintf("heading %q matches no feature", heading)
+			}
+			continue
+		}
+
+		marker, text, ok := splitListItem(line)
+		if !ok {
+			continue
+		}
+		skip := func(reason string) {
+			result.Skipped = append(result.Skipped, TaskImportSkip{Line: lineNo, Text: line, Reason: reason})
+		}
+
+		status := ""
+		switch marker {
+		case "[ ]":
+			status = "TODO"
+		case "[x]", "[X]":
+			status = "DONE"
+		case "":
+			skip("not a checklist item")
+			continue
+		default:
+			skip("unknown checkbox " + marker)
+			continue
+		}
+
+		feature := current
+		inline := false
+		itemPriority := priority
+		var words []string
+		for _, w := range strings.Fields(text) {
+			switch {
+			case strings.HasPrefix(w, "@") && len(w) > 1:
+				feature, inline = w[1:], true
+			case len(w) == 3 && (w[0] == '(' && w[2] == ')' || w[0] == '[' && w[2] == ']') && validTaskPriorities[string(w[1])]:
+				itemPriority = string(w[1])
+			default:
+				words = append(words, w)
+			}
+		}
+		title := strings.Join(words, " ")
+
+		switch {
+		case title == "":
+			skip("empty title")
+			continue
+		case !inline && headingProblem != "":
+			skip(headingProblem)
+			continue
+		case feature == "":
+			skip("no feature: add a feature heading, @<feature>, or --feature")
+			continue
+		case !known[feature]:
+			skip("unknown feature " + feature)
+			continue
+		case existing[feature+"\x00"+title]:
+			skip("duplicate of existing task")
+			continue
+		}
+		existing[feature+"\x00"+title] = true
+
+		t := Task{
+			ID:       fmt.Sprintf("T-%d", next),
+			Feature:  feature,
+			Title:    title,
+			Status:   status,
+			Priority: itemPriority,
+		}
+		next++
+		result.Added = append(result.Added, t)
+	}
+
+	if opts.DryRun || len(result.Added) == 0 {
+		return result, nil
+	}
+	if err := saveTasks(projectDir, append(tasks, result.Added...)); err != nil {
+		return result, fmt.Errorf("err:io %w", err)
+	}
+	return result, nil
+}
+
+// matchFeatureHeading resolves a Markdown heading to a feature ID. The
+// heading may be the feature ID, "<id>: anything", or the feature title.
+// When several titles match, id is empty and matches lists them.
+func matchFeatureHeading(heading string, features []Feature) (id string, matches []string) {
+	candidate := heading
+	if idx := strings.Index(heading, ":"); idx > 0 {
+		candidate = strings.TrimSpace(heading[:idx])
+	}
+	for _, f := range features {
+		if f.ID == heading || f.ID == candidate {
+			return f.ID, nil
+		}
+	}
+	for _, f := range features {
+		if strings.EqualFold(f.Title, heading) {
+			matches = append(matches, f.ID)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], matches
+	}
+	return "", matches
+}
+
+// splitListItem parses "- [ ] text" (also * and +). marker is the checkbox,
+// or "" for a plain list item; ok is false for non-list lines.
+func splitListItem(line string) (marker string, text string, ok bool) {
+	if len(line) < 2 || !strings.ContainsRune("-*+", rune(line[0])) || line[1] != ' ' {
+		return "", "", false
+	}
+	rest := strings.TrimSpace(line[2:])
+	if len(rest) >= 3 && rest[0] == '[' && rest[2] == ']' {
+		return rest[:3], strings.TrimSpace(rest[3:]), true
+	}
+	return "", rest, true
+}
+
+// unquoteTaskTitle undoes the quoting saveTasks applies to titles with
+// YAML-significant characters.
+func unquoteTaskTitle(title string) string {
+	if len(title) >= 2 && title[0] == '"' && title[len(title)-1] == '"' {
+		return strings.ReplaceAll(title[1:len(title)-1], "\\\"", "\"")
+	}
+	return title
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportTasksMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "user-auth", "billing")
+	setupTasks(t, dir, Task{ID: "T-4", Feature: "billing", Title: "Invoice PDF", Status: "TODO", Priority: "B"})
+
+	md := `# Sprint plan
+
+## user-auth
+- [ ] Login form (A)
+- [x] Session store
+- plain note
+
+## billing: payments
+- [ ] Invoice PDF
+- [ ] Refunds [C]
+- [ ] Audit hook @user-auth
+
+## Someday
+- [ ] Dark mode
+`
+	result, err := ImportTasksMarkdown(dir, md, TaskImportOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Task{
+		{ID: "T-5", Feature: "user-auth", Title: "Login form", Status: "TODO", Priority: "A"},
+		{ID: "T-6", Feature: "user-auth", Title: "Session store", Status: "DONE", Priority: "B"},
+		{ID: "T-7", Feature: "billing", Title: "Refunds", Status: "TODO", Priority: "C"},
+		{ID: "T-8", Feature: "user-auth", Title: "Audit hook", Status: "TODO", Priority: "B"},
+	}
+	if len(result.Added) != len(want) {
+		t.Fatalf("expected %d tasks, got %+v", len(want), result.Added)
+	}
+	for i, w := range want {
+		if !reflect.DeepEqual(result.Added[i], w) {
+			t.Errorf("task %d: expected %+v, got %+v", i, w, result.Added[i])
+		}
+	}
+
+	var reasons []string
+	for _, s := range result.Skipped {
+		reasons = append(reasons, s.Reason)
+	}
+	got := strings.Join(reasons, "|")
+	if got != `not a checklist item|duplicate of existing task|heading "Someday" matches no feature` {
+		t.Errorf("unexpected skip reasons: %s", got)
+	}
+
+	tasks, _ := loadTasks(dir)
+	if len(tasks) != 5 {
+		t.Errorf("expected 5 tasks saved, got %d", len(tasks))
+	}
+}
+
+func TestImportTasksMarkdownDryRunAndDefaultFeature(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "user-auth")
+
+	result, err := ImportTasksMarkdown(dir, "- [ ] Write docs\n", TaskImportOptions{Feature: "user-auth", DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Added) != 1 || result.Added[0].Feature != "user-auth" {
+		t.Errorf("expected one user-auth task, got %+v", result.Added)
+	}
+	if tasks, _ := loadTasks(dir); len(tasks) != 0 {
+		t.Errorf("dry run must not write tasks.yaml, got %d tasks", len(tasks))
+	}
+}
+
+func TestImportTasksMarkdownAmbiguousHeading(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir)
+	content := "features:\n  - id: a\n    title: Search\n    status: planned\n  - id: b\n    title: search\n    status: planned\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ImportTasksMarkdown(dir, "## Search\n- [ ] Index\n", TaskImportOptions{Feature: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Added) != 0 || len(result.Skipped) != 1 || !strings.Contains(result.Skipped[0].Reason, "ambiguous: a, b") {
+		t.Errorf("expected ambiguous heading skip, got %+v", result)
+	}
+}
+
+func TestImportTasksMarkdownUnknownFeature(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "user-auth")
+
+	_, err := ImportTasksMarkdown(dir, "- [ ] x\n", TaskImportOptions{Feature: "nope"})
+	if err == nil || !strings.HasPrefix(err.Error(), "err:validation") {
+		t.Errorf("expected err:validation, got %v", err)
+	}
+}
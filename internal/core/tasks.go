@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Task struct {
@@ -15,6 +16,8 @@ type Task struct {
 	Title    string
 	Status   string
 	Priority string
+	// Comments is the task's discussion log, oldest first.
+	Comments []TaskComment
 }
 
 var validTaskStatuses = map[string]bool{
@@ -59,18 +62,8 @@ func AddTask(projectDir string, featureID string, title string, priority string)
 		return Task{}, err
 	}
 
-	maxNum := 0
-	for _, t := range tasks {
-		if strings.HasPrefix(t.ID, "T-") {
-			n, err := strconv.Atoi(t.ID[2:])
-			if err == nil && n > maxNum {
-				maxNum = n
-			}
-		}
-	}
-
 	task := Task{
-		ID:       fmt.Sprintf("T-%d", maxNum+1),
+		ID:       fmt.Sprintf("T-%d", maxTaskNum(tasks)+1),
 		Feature:  featureID,
 		Title:    title,
 		Status:   "TODO",
@@ -85,6 +78,20 @@ func AddTask(projectDir string, featureID string, title string, priority string)
 	return task, nil
 }
 
+// maxTaskNum returns the highest N among T-N task IDs, or 0.
+func maxTaskNum(tasks []Task) int {
+	maxNum := 0
+	for _, t := range tasks {
+		if strings.HasPrefix(t.ID, "T-") {
+			n, err := strconv.Atoi(t.ID[2:])
+			if err == nil && n > maxNum {
+				maxNum = n
+			}
+		}
+	}
+	return maxNum
+}
+
 func ListTasks(projectDir string, featureFilter string, statusFilter string) ([]Task, error) {
 	tasks, err := loadTasks(projectDir)
 	if err != nil {
@@ -115,19 +122,27 @@ func UpdateTask(projectDir string, id string, status string) error {
 		return err
 	}
 
-	found := false
+	var updated *Task
 	for i := range tasks {
 		if tasks[i].ID == id {
 			tasks[i].Status = status
-			found = true
+			updated = &tasks[i]
 			break
 		}
 	}
-	if !found {
+	if updated == nil {
 		return fmt.Errorf("err:validation task %s not found", id)
 	}
 
-	return saveTasks(projectDir, tasks)
+	if err := saveTasks(projectDir, tasks); err != nil {
+		return err
+	}
+
+	if status != "WIP" {
+		_, _ = ReleaseTask(projectDir, id) // finished or handed back: the claim ends
+	}
+	recordEvent(projectDir, Event{Type: EventTask, Feature: updated.Feature, Task: id, Detail: status})
+	return nil
 }
 
 type TaskNextResult struct {
@@ -137,8 +152,12 @@ type TaskNextResult struct {
 
 // taskUnblocked returns true if the task's feature has reached the impl stage
 // (or has no state entry / no stage set). Tasks for features still progressing
-// through earlier pipeline stages (prd, seed, bdd, test) are blocked.
-func taskUnblocked(t Task, state *State) bool {
+// through earlier pipeline stages (prd, seed, bdd, test), or waiting on
+// dependencies that are not implemented, are blocked.
+func taskUnblocked(t Task, state *State, waiting map[string]bool) bool {
+	if waiting[t.Feature] {
+		return false
+	}
 	if state == nil || t.Feature == "" {
 		return true
 	}
@@ -149,6 +168,16 @@ func taskUnblocked(t Task, state *State) bool {
 	return false
 }
 
+// Task scheduling modes for tasks.scheduling.
+const (
+	SchedulingPriority = "priority"
+	SchedulingFair     = "fair"
+)
+
+// TaskNext returns up to limit unblocked TODO tasks. With tasks.scheduling:
+// fair, features take turns so one feature's backlog cannot starve the rest.
+// Tasks claimed by an active lease are skipped; WIP tasks whose lease expired
+// are offered again.
 func TaskNext(projectDir string, limit int) ([]Task, error) {
 	tasks, err := loadTasks(projectDir)
 	if err != nil {
@@ -156,17 +185,26 @@ func TaskNext(projectDir string, limit int) ([]Task, error) {
 	}
 
 	state, _ := LoadState(projectDir)
+	waiting := waitingOnDependencies(projectDir)
+	leases := taskLeases(projectDir)
+	now := time.Now()
 
 	var todo []Task
 	for _, t := range tasks {
-		if t.Status == "TODO" && taskUnblocked(t, state) {
+		l, leased := leases[t.ID]
+		available := t.Status == "TODO" && (!leased || l.Expired(now))
+		reclaimable := t.Status == "WIP" && leased && l.Expired(now)
+		if (available || reclaimable) && taskUnblocked(t, state, waiting) {
 			todo = append(todo, t)
 		}
 	}
 
-	sort.Slice(todo, func(i, j int) bool {
+	sort.SliceStable(todo, func(i, j int) bool {
 		return todo[i].Priority < todo[j].Priority
 	})
+	if cfg, err := LoadConfig(projectDir); err == nil && cfg.Tasks.Scheduling == SchedulingFair {
+		todo = roundRobinByFeature(todo)
+	}
 
 	if limit > 0 && len(todo) > limit {
 		todo = todo[:limit]
@@ -175,6 +213,31 @@ func TaskNext(projectDir string, limit int) ([]Task, error) {
 	return todo, nil
 }
 
+// roundRobinByFeature interleaves priority-sorted tasks one feature at a
+// time. Features take turns in the order of their best task, and each
+// feature's tasks keep their priority order.
+func roundRobinByFeature(tasks []Task) []Task {
+	var order []string
+	queues := make(map[string][]Task)
+	for _, t := range tasks {
+		if _, ok := queues[t.Feature]; !ok {
+			order = append(order, t.Feature)
+		}
+		queues[t.Feature] = append(queues[t.Feature], t)
+	}
+
+	out := make([]Task, 0, len(tasks))
+	for len(out) < len(tasks) {
+		for _, f := range order {
+			if q := queues[f]; len(q) > 0 {
+				out = append(out, q[0])
+				queues[f] = q[1:]
+			}
+		}
+	}
+	return out
+}
+
 // TaskNextWithRegressions returns the next tasks and auto-triggers regression detection.
 func TaskNextWithRegressions(projectDir string, limit int) (TaskNextResult, error) {
 	tasks, err := TaskNext(projectDir, limit)
@@ -197,8 +260,13 @@ func loadTasks(projectDir string) ([]Task, error) {
 		return nil, fmt.Errorf("err:io %w", err)
 	}
 
+	content, err := upgradeSchema("tasks.yaml", string(data))
+	if err != nil {
+		return nil, err
+	}
+
 	var tasks []Task
-	lines := strings.Split(string(data), "\n")
+	lines := strings.Split(content, "\n")
 	for i := 0; i < len(lines); i++ {
 		trimmed := strings.TrimSpace(lines[i])
 		if strings.HasPrefix(trimmed, "- id: ") {
@@ -220,6 +288,9 @@ func loadTasks(projectDir string) ([]Task, error) {
 				if strings.HasPrefix(next, "priority: ") {
 					t.Priority = strings.TrimPrefix(next, "priority: ")
 				}
+				if strings.HasPrefix(lines[j], "      ") {
+					t.Comments = parseTaskCommentLine(t.Comments, next)
+				}
 			}
 			tasks = append(tasks, t)
 		}
@@ -232,6 +303,7 @@ func saveTasks(projectDir string, tasks []Task) error {
 	tasksPath := filepath.Join(projectDir, ".ptsd", "tasks.yaml")
 
 	var b strings.Builder
+	b.WriteString(schemaHeader())
 	b.WriteString("tasks:\n")
 	for _, t := range tasks {
 		b.WriteString("  - id: " + t.ID + "\n")
@@ -243,7 +315,8 @@ func saveTasks(projectDir string, tasks []Task) error {
 		b.WriteString("    title: " + title + "\n")
 		b.WriteString("    status: " + t.Status + "\n")
 		b.WriteString("    priority: " + t.Priority + "\n")
+		writeTaskComments(&b, t.Comments)
 	}
 
-	return os.WriteFile(tasksPath, []byte(b.String()), 0644)
+	return writeFileAtomic(tasksPath, []byte(b.String()), 0644)
 }
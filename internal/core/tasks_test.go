@@ -3,6 +3,7 @@ package core
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -95,6 +96,53 @@ func TestTaskNextWithLimit(t *testing.T) {
 	}
 }
 
+func TestTaskNextFairScheduling(t *testing.T) {
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "auth", "billing", "search")
+	setupTasks(t, dir,
+		Task{ID: "T-1", Feature: "auth", Title: "T1", Status: "TODO", Priority: "A"},
+		Task{ID: "T-2", Feature: "auth", Title: "T2", Status: "TODO", Priority: "A"},
+		Task{ID: "T-3", Feature: "auth", Title: "T3", Status: "TODO", Priority: "A"},
+		Task{ID: "T-4", Feature: "billing", Title: "T4", Status: "TODO", Priority: "B"},
+		Task{ID: "T-5", Feature: "search", Title: "T5", Status: "TODO", Priority: "C"},
+		Task{ID: "T-6", Feature: "billing", Title: "T6", Status: "TODO", Priority: "C"},
+	)
+
+	ids := func(tasks []Task) string {
+		var out []string
+		for _, t := range tasks {
+			out = append(out, t.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tasks, err := TaskNext(dir, 3)
+	if err != nil {
+		t.Fatalf("TaskNext failed: %v", err)
+	}
+	if got := ids(tasks); got != "T-1,T-2,T-3" {
+		t.Errorf("priority scheduling: expected T-1,T-2,T-3, got %s", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("project:\n  name: X\ntasks:\n  scheduling: fair\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err = TaskNext(dir, 0)
+	if err != nil {
+		t.Fatalf("TaskNext failed: %v", err)
+	}
+	if got := ids(tasks); got != "T-1,T-4,T-5,T-2,T-6,T-3" {
+		t.Errorf("fair scheduling: expected T-1,T-4,T-5,T-2,T-6,T-3, got %s", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("tasks:\n  scheduling: random\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(dir); err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Errorf("expected err:config for unknown scheduling, got %v", err)
+	}
+}
+
 func TestTaskNextWhenAllDone(t *testing.T) {
 	dir := t.TempDir()
 	setupTaskFeatures(t, dir, "user-auth")
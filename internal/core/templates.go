@@ -3,20 +3,146 @@ package core
 import (
 	"bytes"
 	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"text/template"
+	"text/template/parse"
 )
 
 //go:embed templates/*
 var templateFS embed.FS
 
-// renderTemplate executes a named template with the given data.
+// Template overrides. A file in the templates directory ($PTSD_TEMPLATES, or
+// ptsd/templates under the user config directory) named like a built-in
+// template (claude.md.tmpl, hooks/gate.sh, ...) replaces it when ptsd
+// renders scaffolding. `ptsd templates check` validates overrides against
+// the variables each template receives so a typo fails there, not in init.
+
+// templatesDirEnv overrides the template override directory.
+const templatesDirEnv = "PTSD_TEMPLATES"
+
+// TemplateVar is a variable a template can reference as {{.Name}}.
+type TemplateVar struct {
+	Name        string
+	Description string
+}
+
+// templateSpec describes a rendered template: its name relative to
+// templates/ and a zero value of the data it is executed with.
+type templateSpec struct {
+	name string
+	data any
+	vars []TemplateVar
+}
+
+// ptsdYAMLData is rendered into ptsd.yaml on init.
+type ptsdYAMLData struct {
+	Schema                               int
+	Name, Profile, Runner, Scopes, Types string
+}
+
+// prdData is rendered into the initial PRD.
+type prdData struct{ Name string }
+
+// hookData is rendered into the Claude Code hook scripts.
+type hookData struct {
+	Bin    string
+	Schema int
+}
+
+// settingsData is rendered into .claude/settings.json.
+type settingsData struct{ ContextHook, GateHook, TrackHook string }
+
+var hookVars = []TemplateVar{
+	{"Bin", "absolute path of the ptsd binary"},
+	{"Schema", "state schema version the hooks need"},
+}
+
+var templateSpecs = []templateSpec{
+	{"claude.md.tmpl", claudeMDData{}, []TemplateVar{
+		{"Name", "project name"},
+		{"Runner", "test runner command, may be empty"},
+		{"MinScore", "review.min_score"},
+		{"Scopes", "comma-separated commit scopes"},
+		{"Types", "comma-separated commit types"},
+	}},
+	{"hooks/context.sh", hookData{}, hookVars},
+	{"hooks/gate.sh", hookData{}, hookVars},
+	{"hooks/track.sh", hookData{}, hookVars},
+	{"prd.md.tmpl", prdData{}, []TemplateVar{{"Name", "project name"}}},
+	{"ptsd.yaml.tmpl", ptsdYAMLData{}, []TemplateVar{
+		{"Schema", "state schema version"},
+		{"Name", "project name"},
+		{"Profile", "scaffolding profile"},
+		{"Runner", "detected test runner, may be empty"},
+		{"Scopes", "comma-separated default commit scopes"},
+		{"Types", "comma-separated default commit types"},
+	}},
+	{"settings.json.tmpl", settingsData{}, []TemplateVar{
+		{"ContextHook", "path of ptsd-context.sh"},
+		{"GateHook", "path of ptsd-gate.sh"},
+		{"TrackHook", "path of ptsd-track.sh"},
+	}},
+}
+
+// TemplatesDir returns the template override directory.
+func TemplatesDir() (string, error) {
+	if p := os.Getenv(templatesDirEnv); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("err:config cannot locate templates directory: %v", err)
+	}
+	return filepath.Join(dir, "ptsd", "templates"), nil
+}
+
+// templateOverride returns the override file for a built-in template name
+// ("templates/claude.md.tmpl"), "" when there is none.
+func templateOverride(name string) string {
+	dir, err := TemplatesDir()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(name, "templates/")))
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return ""
+	}
+	return path
+}
+
+// renderTemplate executes a named template with the given data, preferring
+// the user's override. A broken override is a config error.
 func renderTemplate(name string, data any) (string, error) {
+	if path := templateOverride(name); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("err:io %w", err)
+		}
+		out, err := executeTemplate(name, string(raw), data)
+		if err != nil {
+			return "", fmt.Errorf("err:config template override %s: %v (run ptsd templates check)", path, err)
+		}
+		return out, nil
+	}
+
 	raw, err := templateFS.ReadFile(name)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("err:io %w", err)
 	}
+	out, err := executeTemplate(name, string(raw), data)
+	if err != nil {
+		return "", fmt.Errorf("err:io %w", err)
+	}
+	return out, nil
+}
 
-	tmpl, err := template.New(name).Parse(string(raw))
+func executeTemplate(name, text string, data any) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
 	if err != nil {
 		return "", err
 	}
@@ -36,3 +162,171 @@ func readTemplate(name string) (string, error) {
 	}
 	return string(raw), nil
 }
+
+// TemplateInfo describes a rendered template for `ptsd templates list`.
+type TemplateInfo struct {
+	Name      string
+	Variables []TemplateVar
+	// Override is the path of the user's override, "" for the built-in.
+	Override string
+}
+
+// ListTemplates returns the templates ptsd renders, sorted by name.
+func ListTemplates() []TemplateInfo {
+	var out []TemplateInfo
+	for _, s := range templateSpecs {
+		out = append(out, TemplateInfo{Name: s.name, Variables: s.vars, Override: templateOverride("templates/" + s.name)})
+	}
+	return out
+}
+
+// TemplateProblem is one finding of CheckTemplates.
+type TemplateProblem struct {
+	Name    string // relative to the templates directory
+	Path    string
+	Message string
+}
+
+// CheckTemplates validates every file in the override directory: it must
+// name a built-in template, parse, reference only that template's
+// variables and execute against its data. A missing directory has no
+// problems.
+func CheckTemplates() ([]TemplateProblem, int, error) {
+	dir, err := TemplatesDir()
+	if err != nil {
+		return nil, 0, err
+	}
+	specs := make(map[string]templateSpec)
+	for _, s := range templateSpecs {
+		specs[s.name] = s
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("err:io %w", err)
+	}
+	sort.Strings(files)
+
+	var problems []TemplateProblem
+	for _, path := range files {
+		rel, _ := filepath.Rel(dir, path)
+		name := filepath.ToSlash(rel)
+		spec, ok := specs[name]
+		if !ok {
+			problems = append(problems, TemplateProblem{Name: name, Path: path, Message: "not a ptsd template, ignored"})
+			continue
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, 0, fmt.Errorf("err:io %w", err)
+		}
+		for _, msg := range checkTemplate(spec, string(raw)) {
+			problems = append(problems, TemplateProblem{Name: name, Path: path, Message: msg})
+		}
+	}
+	return problems, len(files), nil
+}
+
+// checkTemplate reports parse errors, references to undefined variables
+// (also in branches a sample run would skip) and execution errors.
+func checkTemplate(spec templateSpec, text string) []string {
+	tmpl, err := template.New(spec.name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return []string{"parse error: " + strings.TrimPrefix(err.Error(), "template: ")}
+	}
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(spec.data)
+	for i := 0; i < t.NumField(); i++ {
+		fields[t.Field(i).Name] = true
+	}
+
+	var msgs []string
+	seen := make(map[string]bool)
+	undefined := func(name string) {
+		if !fields[name] && !seen[name] {
+			seen[name] = true
+			msgs = append(msgs, fmt.Sprintf("undefined variable .%s (available: %s)", name, strings.Join(templateVarNames(spec), ", ")))
+		}
+	}
+	walkTemplateFields(tmpl.Tree.Root, true, undefined)
+	if len(msgs) > 0 {
+		return msgs
+	}
+
+	if err := tmpl.Execute(&bytes.Buffer{}, spec.data); err != nil {
+		msgs = append(msgs, "execution error: "+strings.TrimPrefix(err.Error(), "template: "))
+	}
+	return msgs
+}
+
+func templateVarNames(spec templateSpec) []string {
+	var names []string
+	for _, v := range spec.vars {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+// walkTemplateFields calls undefined for the first identifier of every
+// .Field and $.Field reference. Inside range and with, dot is no longer the
+// template data, so only $.Field is checked there.
+func walkTemplateFields(node parse.Node, topDot bool, undefined func(string)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			walkTemplateFields(c, topDot, undefined)
+		}
+	case *parse.ActionNode:
+		walkTemplateFields(n.Pipe, topDot, undefined)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			walkTemplateFields(c, topDot, undefined)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			walkTemplateFields(a, topDot, undefined)
+		}
+	case *parse.ChainNode:
+		walkTemplateFields(n.Node, topDot, undefined)
+	case *parse.FieldNode:
+		if topDot {
+			undefined(n.Ident[0])
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			undefined(n.Ident[1])
+		}
+	case *parse.IfNode:
+		walkTemplateFields(n.Pipe, topDot, undefined)
+		walkTemplateFields(n.List, topDot, undefined)
+		walkTemplateFields(n.ElseList, topDot, undefined)
+	case *parse.RangeNode:
+		walkTemplateFields(n.Pipe, topDot, undefined)
+		walkTemplateFields(n.List, false, undefined)
+		walkTemplateFields(n.ElseList, topDot, undefined)
+	case *parse.WithNode:
+		walkTemplateFields(n.Pipe, topDot, undefined)
+		walkTemplateFields(n.List, false, undefined)
+		walkTemplateFields(n.ElseList, topDot, undefined)
+	case *parse.TemplateNode:
+		walkTemplateFields(n.Pipe, topDot, undefined)
+	}
+}
@@ -1,3 +1,8 @@
 #!/bin/sh
-{{.Bin}} context --agent 2>/dev/null
+PTSD_BIN='{{.Bin}}'
+if [ ! -x "$PTSD_BIN" ] || ! "$PTSD_BIN" verify-hooks --schema {{.Schema}} >/dev/null 2>&1; then
+  echo "err:config ptsd hooks run $PTSD_BIN, which is missing or older than schema {{.Schema}}; install ptsd, then run: ptsd init --force"
+  exit 0
+fi
+"$PTSD_BIN" context --agent 2>/dev/null
 exit 0
@@ -1,2 +1,7 @@
 #!/bin/sh
-{{.Bin}} hooks pre-tool-use --agent
+PTSD_BIN='{{.Bin}}'
+if [ ! -x "$PTSD_BIN" ] || ! "$PTSD_BIN" verify-hooks --schema {{.Schema}} >/dev/null 2>&1; then
+  echo "err:config ptsd hooks run $PTSD_BIN, which is missing or older than schema {{.Schema}}; install ptsd, then run: ptsd init --force" >&2
+  exit 2
+fi
+"$PTSD_BIN" hooks pre-tool-use --agent
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeTemplateOverride(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTemplateSpecsMatchData(t *testing.T) {
+	for _, s := range templateSpecs {
+		if _, err := templateFS.ReadFile("templates/" + s.name); err != nil {
+			t.Errorf("%s: no embedded template", s.name)
+		}
+		typ := reflect.TypeOf(s.data)
+		if typ.NumField() != len(s.vars) {
+			t.Errorf("%s: %d fields but %d documented variables", s.name, typ.NumField(), len(s.vars))
+		}
+		for _, v := range s.vars {
+			if _, ok := typ.FieldByName(v.Name); !ok {
+				t.Errorf("%s: documented variable %s is not a field of %s", s.name, v.Name, typ)
+			}
+		}
+		raw, _ := readTemplate("templates/" + s.name)
+		if msgs := checkTemplate(s, raw); len(msgs) > 0 {
+			t.Errorf("built-in %s fails its own check: %v", s.name, msgs)
+		}
+	}
+}
+
+func TestOverrideReplacesBuiltinTemplate(t *testing.T) {
+	tmplDir := t.TempDir()
+	t.Setenv(templatesDirEnv, tmplDir)
+	writeTemplateOverride(t, tmplDir, "prd.md.tmpl", "# {{.Name}} requirements\n")
+
+	dir := t.TempDir()
+	setupGitDir(t, dir)
+	initProject(t, dir, "Shop")
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".ptsd", "docs", "PRD.md"))
+	if string(data) != "# Shop requirements\n" {
+		t.Errorf("override not used, PRD.md = %q", data)
+	}
+	infos := ListTemplates()
+	for _, info := range infos {
+		if (info.Name == "prd.md.tmpl") != (info.Override != "") {
+			t.Errorf("unexpected override for %s: %q", info.Name, info.Override)
+		}
+	}
+}
+
+func TestBrokenOverrideFailsInitWithConfigError(t *testing.T) {
+	tmplDir := t.TempDir()
+	t.Setenv(templatesDirEnv, tmplDir)
+	writeTemplateOverride(t, tmplDir, "prd.md.tmpl", "# {{.Title}}\n")
+
+	dir := t.TempDir()
+	setupGitDir(t, dir)
+	_, err := InitProject(dir, "Shop")
+	if err == nil || !strings.HasPrefix(err.Error(), "err:config template override") {
+		t.Errorf("expected err:config, got %v", err)
+	}
+}
+
+func TestCheckTemplatesReportsProblems(t *testing.T) {
+	tmplDir := t.TempDir()
+	t.Setenv(templatesDirEnv, tmplDir)
+	writeTemplateOverride(t, tmplDir, "claude.md.tmpl", "{{.Name}}{{if .Runner}} {{.Runnr}}{{end}}\n")
+	writeTemplateOverride(t, tmplDir, "hooks/gate.sh", "{{.Bin\n")
+	writeTemplateOverride(t, tmplDir, "prd.md.tmpl", "{{with .Name}}{{.Len}}{{end}} {{$.Name}}\n")
+	writeTemplateOverride(t, tmplDir, "notes.txt", "hello\n")
+
+	problems, checked, err := CheckTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checked != 4 {
+		t.Errorf("expected 4 files checked, got %d", checked)
+	}
+	got := make(map[string]string)
+	for _, p := range problems {
+		got[p.Name] = p.Message
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected problems in 3 files, got %+v", problems)
+	}
+	if !strings.HasPrefix(got["claude.md.tmpl"], "undefined variable .Runnr") {
+		t.Errorf("claude.md.tmpl: %q", got["claude.md.tmpl"])
+	}
+	if !strings.HasPrefix(got["hooks/gate.sh"], "parse error") {
+		t.Errorf("hooks/gate.sh: %q", got["hooks/gate.sh"])
+	}
+	if !strings.Contains(got["notes.txt"], "not a ptsd template") {
+		t.Errorf("notes.txt: %q", got["notes.txt"])
+	}
+
+	t.Setenv(templatesDirEnv, filepath.Join(tmplDir, "missing"))
+	if problems, checked, err := CheckTemplates(); err != nil || checked != 0 || len(problems) != 0 {
+		t.Errorf("missing directory should be clean: %v %d %v", problems, checked, err)
+	}
+}
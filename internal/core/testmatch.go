@@ -0,0 +1,154 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Scenario-to-test matching for `ptsd test match`. Test names in the files
+// already mapped to a feature (Go Test funcs, JS/TS it()/test() titles,
+// Python test_ functions) are compared with the titles of the scenarios no
+// mapping covers yet; each scenario gets at most its best test and each
+// test at most one scenario, highest confidence first.
+
+// TestMatchThreshold is the default confidence a candidate needs.
+const TestMatchThreshold = 0.5
+
+// TestMatch is a candidate mapping of one scenario to one test function.
+type TestMatch struct {
+	Scenario string // scenario ID
+	Title    string
+	File     string
+	Func     string
+	// Confidence is the name similarity in [0, 1].
+	Confidence float64
+	// Mapped is true once the candidate is written to state.yaml.
+	Mapped bool
+}
+
+// TestMatchResult reports MatchTests: the candidates in scenario order and
+// the titles of uncovered scenarios no test matched.
+type TestMatchResult struct {
+	Feature   string
+	Matches   []TestMatch
+	Unmatched []string
+}
+
+// MatchTests proposes function-level mappings for featureID's uncovered
+// scenarios from the test functions in its mapped test files. Candidates
+// at or above minConfidence are written as `test map --func` mappings
+// unless dryRun is set.
+func MatchTests(projectDir, featureID string, minConfidence float64, dryRun bool) (TestMatchResult, error) {
+	result := TestMatchResult{Feature: featureID}
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return result, err
+	}
+	if !slices.ContainsFunc(features, func(f Feature) bool { return f.ID == featureID }) {
+		return result, fmt.Errorf("err:validation feature %s not found", featureID)
+	}
+	data, err := os.ReadFile(bddFilePath(projectDir, featureID))
+	if err != nil {
+		return result, fmt.Errorf("err:pipeline no BDD file for %s", featureID)
+	}
+	ff, err := parseFeatureContent(string(data))
+	if err != nil {
+		return result, err
+	}
+	state, err := LoadState(projectDir)
+	if err != nil {
+		return result, err
+	}
+	mappings, _ := state.Features[featureID].Tests.([]string)
+	files, err := featureTestFiles(projectDir, featureID)
+	if err != nil {
+		return result, err
+	}
+	if len(files) == 0 {
+		return result, fmt.Errorf("err:pipeline no test files mapped to %s: map one with ptsd test map", featureID)
+	}
+
+	// Tests already mapped to a scenario are not offered again.
+	taken := make(map[string]bool)
+	for _, s := range mappings {
+		if m := parseTestMapping(s); m.Func != "" {
+			taken[m.File+"::"+m.Func] = true
+		}
+	}
+	type testFunc struct{ file, name string }
+	var funcs []testFunc
+	for _, file := range files {
+		for _, name := range testFunctions(filepath.Join(projectDir, file)) {
+			if !taken[file+"::"+name] {
+				funcs = append(funcs, testFunc{file, name})
+			}
+		}
+	}
+
+	covered := coveredScenarios(ff, mappings)
+	var open []ScenarioData
+	for _, sc := range ff.Scenarios {
+		if !covered[sc.ID] {
+			open = append(open, sc)
+		}
+	}
+
+	var candidates []TestMatch
+	for _, sc := range open {
+		for _, fn := range funcs {
+			if score := testNameSimilarity(sc.Title, fn.name, featureID); score >= minConfidence {
+				candidates = append(candidates, TestMatch{Scenario: sc.ID, Title: sc.Title, File: fn.file, Func: fn.name, Confidence: score})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Confidence > candidates[j].Confidence })
+	matched := make(map[string]TestMatch)
+	used := make(map[string]bool)
+	for _, c := range candidates {
+		key := c.File + "::" + c.Func
+		if _, ok := matched[c.Scenario]; ok || used[key] {
+			continue
+		}
+		matched[c.Scenario] = c
+		used[key] = true
+	}
+
+	bddRel := bddFileRel(projectDir, featureID)
+	for _, sc := range open {
+		m, ok := matched[sc.ID]
+		if !ok {
+			result.Unmatched = append(result.Unmatched, sc.Title)
+			continue
+		}
+		if !dryRun {
+			if _, err := MapScenarioTestFunc(projectDir, bddRel, m.Scenario, m.File, m.Func); err != nil {
+				return result, err
+			}
+			m.Mapped = true
+		}
+		result.Matches = append(result.Matches, m)
+	}
+	return result, nil
+}
+
+// testNameSimilarity compares a scenario title with a test name after
+// dropping the Test/test_ prefix, splitting camel case and underscores, and
+// ignoring words of the feature ID (TestAuthLoginFails for "Login fails").
+func testNameSimilarity(title, testName, featureID string) float64 {
+	name := strings.TrimPrefix(strings.TrimPrefix(testName, "Test"), "test_")
+	words := nameWords(name)
+	var kept []string
+	for _, w := range words {
+		if !slices.Contains(nameWords(featureID), w) {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) > 0 {
+		words = kept
+	}
+	return textSimilarity(title, strings.Join(words, " "))
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupTestMatchProject(t *testing.T) string {
+	t.Helper()
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	bdd := "@feature:auth\nFeature: Auth\n\n" +
+		"  Scenario: Successful login\n    Given a user\n\n" +
+		"  Scenario: Login with wrong password fails\n    Given a user\n\n" +
+		"  Scenario: Session expires after an hour\n    Given a session\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "auth.feature"), []byte(bdd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src := "package auth\n\nfunc TestAuthLoginSuccess(t *testing.T) {}\n\nfunc TestWrongPasswordRejected(t *testing.T) {}\n\nfunc TestHashing(t *testing.T) {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "auth_test.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := MapTest(dir, ".ptsd/bdd/auth.feature", "auth_test.go"); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestMatchTestsDryRunProposesWithoutWriting(t *testing.T) {
+	dir := setupTestMatchProject(t)
+
+	result, err := MatchTests(dir, "auth", TestMatchThreshold, true)
+	if err != nil {
+		t.Fatalf("MatchTests: %v", err)
+	}
+	if len(result.Matches) != 2 {
+		t.Fatalf("expected 2 candidates, got %+v", result.Matches)
+	}
+	if m := result.Matches[0]; m.Title != "Successful login" || m.Func != "TestAuthLoginSuccess" || m.Mapped || m.Confidence < TestMatchThreshold {
+		t.Errorf("unexpected first candidate: %+v", m)
+	}
+	if m := result.Matches[1]; m.Title != "Login with wrong password fails" || m.Func != "TestWrongPasswordRejected" {
+		t.Errorf("unexpected second candidate: %+v", m)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0] != "Session expires after an hour" {
+		t.Errorf("expected the session scenario to be unmatched, got %v", result.Unmatched)
+	}
+
+	coverage, _ := CheckTestCoverage(dir)
+	if len(coverage) != 1 || coverage[0].Covered != 0 {
+		t.Errorf("dry run should not map anything: %+v", coverage)
+	}
+}
+
+func TestMatchTestsWritesMappings(t *testing.T) {
+	dir := setupTestMatchProject(t)
+
+	result, err := MatchTests(dir, "auth", TestMatchThreshold, false)
+	if err != nil {
+		t.Fatalf("MatchTests: %v", err)
+	}
+	for _, m := range result.Matches {
+		if !m.Mapped {
+			t.Errorf("candidate not mapped: %+v", m)
+		}
+	}
+	state, _ := LoadState(dir)
+	tests, _ := state.Features["auth"].Tests.([]string)
+	if !strings.HasSuffix(strings.Join(tests, "\n"), "::auth_test.go::TestWrongPasswordRejected") {
+		t.Errorf("expected function-level mappings in state, got %v", tests)
+	}
+	coverage, _ := CheckTestCoverage(dir)
+	if len(coverage) != 1 || coverage[0].Covered != 2 {
+		t.Errorf("expected 2 covered scenarios, got %+v", coverage)
+	}
+
+	// A second run only reports what is still open.
+	result, err = MatchTests(dir, "auth", TestMatchThreshold, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Matches) != 0 || len(result.Unmatched) != 1 {
+		t.Errorf("expected only the unmatched scenario on rerun, got %+v", result)
+	}
+}
+
+func TestMatchTestsNeedsMappedTestFiles(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "auth.feature"), []byte("@feature:auth\nFeature: Auth\n\n  Scenario: Login\n    Given x\n"), 0644)
+
+	if _, err := MatchTests(dir, "auth", TestMatchThreshold, true); err == nil || !strings.HasPrefix(err.Error(), "err:pipeline no test files") {
+		t.Errorf("expected err:pipeline, got %v", err)
+	}
+	if _, err := MatchTests(dir, "nope", TestMatchThreshold, true); err == nil || !strings.HasPrefix(err.Error(), "err:validation") {
+		t.Errorf("expected err:validation, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// runTestsParallel partitions the mapped test files by feature and runs one
+// runner process per feature, at most workers at a time. Results are merged
+// into one TestResults with a per-feature breakdown, and each feature's
+// test_status is recorded as if it had been run on its own. A failure
+// threshold counts failures across all processes and kills every running
+// one when it is reached; features not yet started are skipped.
+func runTestsParallel(projectDir string, cfg *Config, workers, maxFailures int, progress ProgressFunc) (TestResults, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return TestResults{}, err
+	}
+	type job struct {
+		feature string
+		files   []string
+	}
+	var jobs []job
+	for _, f := range features {
+		files, err := featureTestFiles(projectDir, f.ID)
+		if err != nil {
+			return TestResults{}, err
+		}
+		if len(files) > 0 {
+			jobs = append(jobs, job{f.ID, files})
+		}
+	}
+	if len(jobs) == 0 {
+		return TestResults{}, fmt.Errorf("err:test no test files mapped to any feature")
+	}
+
+	progress.emit(ProgressEvent{Phase: "test", Percent: 0})
+
+	var (
+		mu             sync.Mutex
+		passed, failed int
+		stopped        bool
+		running        = make(map[*exec.Cmd]int)
+	)
+	results := make([]TestResults, len(jobs))
+	ran := make([]bool, len(jobs))
+	killed := make([]bool, len(jobs))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(jobs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				mu.Lock()
+				if stopped {
+					mu.Unlock()
+					continue
+				}
+				mu.Unlock()
+
+				jobCfg := cfg.ForFeature(jobs[i].feature)
+				cmd := newRunnerCmd(projectDir, jobCfg.Testing.Runner+" "+strings.Join(jobs[i].files, " "))
+				started := func() {
+					mu.Lock()
+					defer mu.Unlock()
+					running[cmd] = i
+					if stopped {
+						killed[i] = true
+						killProcessGroup(cmd)
+					}
+				}
+				seen := 0
+				r := runTestCommand(projectDir, jobCfg, cmd, started, func(name string, ok bool) bool {
+					mu.Lock()
+					defer mu.Unlock()
+					if stopped {
+						return true
+					}
+					seen++
+					if ok {
+						passed++
+					} else {
+						failed++
+					}
+					progress.emit(ProgressEvent{Phase: "test", Percent: -1, File: name, Passed: passed, Failed: failed})
+					if maxFailures > 0 && failed >= maxFailures {
+						stopped = true
+						for other, j := range running {
+							if other != cmd {
+								killed[j] = true
+								killProcessGroup(other)
+							}
+						}
+					}
+					return stopped
+				})
+
+				mu.Lock()
+				delete(running, cmd)
+				if killed[i] && seen == 0 {
+					// Killed before reporting anything: the exit code says
+					// nothing about this feature's tests.
+					r = TestResults{}
+				}
+				results[i], ran[i] = r, true
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range jobs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	var merged TestResults
+	for i, j := range jobs {
+		if !ran[i] {
+			continue
+		}
+		r := results[i]
+		merged.Total += r.Total
+		merged.Passed += r.Passed
+		merged.Failed += r.Failed
+		merged.Failures = append(merged.Failures, r.Failures...)
+		merged.Cases = append(merged.Cases, r.Cases...)
+		// Runners overlap, so the longest one approximates the wall time.
+		if r.Duration > merged.Duration {
+			merged.Duration = r.Duration
+		}
+		merged.Features = append(merged.Features, FeatureTestResults{
+			Feature: j.feature, Total: r.Total, Passed: r.Passed, Failed: r.Failed, Failures: r.Failures,
+		})
+		// A feature cut short by another's failures has no verdict of its own.
+		if !killed[i] || r.Failed > 0 {
+			updateStateWithResults(projectDir, j.feature, r)
+		}
+	}
+	if stopped {
+		merged.StoppedAfter = maxFailures
+	}
+	progress.emit(ProgressEvent{Phase: "done", Percent: 100, Passed: merged.Passed, Failed: merged.Failed})
+	return merged, nil
+}
@@ -0,0 +1,310 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Reporter adapters. Besides Go and TAP result lines, runTestCommand
+// understands the JSON report `jest --json` and `vitest run --reporter=json`
+// print (vitest's format is Jest-compatible) and the event stream of
+// `go test -json`. A report yields one TestCase per test, with its file,
+// duration and failure message, instead of bare counts.
+
+// TestCase is one test from a structured runner report.
+type TestCase struct {
+	Name     string // full name: describe blocks and title
+	File     string
+	Status   string // passed | failed | skipped
+	Duration time.Duration
+	Message  string // first failure message, for failed tests
+}
+
+// jestReport is the subset of the Jest/Vitest JSON report ptsd reads.
+type jestReport struct {
+	NumTotalTests *int  `json:"numTotalTests"`
+	StartTime     int64 `json:"startTime"`
+	TestResults   []struct {
+		Name             string `json:"name"`
+		StartTime        int64  `json:"startTime"`
+		EndTime          int64  `json:"endTime"`
+		Message          string `json:"message"`
+		Status           string `json:"status"`
+		AssertionResults []struct {
+			FullName        string   `json:"fullName"`
+			Title           string   `json:"title"`
+			Status          string   `json:"status"`
+			Duration        *float64 `json:"duration"`
+			FailureMessages []string `json:"failureMessages"`
+		} `json:"assertionResults"`
+	} `json:"testResults"`
+}
+
+// parseJestJSON finds a Jest/Vitest JSON report in runner output, which may
+// also hold log lines, and converts it. ok is false when there is none.
+func parseJestJSON(output string) (results TestResults, ok bool) {
+	key := strings.Index(output, `"numTotalTests"`)
+	if key < 0 {
+		return TestResults{}, false
+	}
+	start := strings.LastIndex(output[:key], "{")
+	if start < 0 {
+		return TestResults{}, false
+	}
+	var report jestReport
+	if err := json.NewDecoder(strings.NewReader(output[start:])).Decode(&report); err != nil || report.NumTotalTests == nil {
+		return TestResults{}, false
+	}
+
+	var end int64
+	for _, file := range report.TestResults {
+		if file.EndTime > end {
+			end = file.EndTime
+		}
+		for _, a := range file.AssertionResults {
+			name := a.FullName
+			if name == "" {
+				name = a.Title
+			}
+			tc := TestCase{Name: name, File: file.Name}
+			if a.Duration != nil {
+				tc.Duration = time.Duration(*a.Duration * float64(time.Millisecond))
+			}
+			switch a.Status {
+			case "passed":
+				tc.Status = "passed"
+				results.Passed++
+			case "failed":
+				tc.Status = "failed"
+				if len(a.FailureMessages) > 0 {
+					tc.Message = firstLine(a.FailureMessages[0])
+				}
+				results.Failed++
+				results.Failures = append(results.Failures, name)
+			default: // pending, skipped, todo, disabled
+				tc.Status = "skipped"
+			}
+			results.Cases = append(results.Cases, tc)
+		}
+		// A file that failed to load reports no assertions, only a message.
+		if len(file.AssertionResults) == 0 && file.Status == "failed" {
+			results.Failed++
+			results.Failures = append(results.Failures, file.Name)
+			results.Cases = append(results.Cases, TestCase{Name: file.Name, File: file.Name, Status: "failed", Message: firstLine(file.Message)})
+		}
+	}
+	results.Total = results.Passed + results.Failed
+	if report.StartTime > 0 && end > report.StartTime {
+		results.Duration = time.Duration(end-report.StartTime) * time.Millisecond
+	}
+	return results, true
+}
+
+// goTestEvent is one line of `go test -json` output (cmd/test2json).
+type goTestEvent struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+	Output  string    `json:"Output"`
+}
+
+// goTestEventLine parses one test2json line. ok is false for anything else,
+// including a JSON object without an Action.
+func goTestEventLine(line string) (ev goTestEvent, ok bool) {
+	if !strings.HasPrefix(line, "{") || !strings.Contains(line, `"Action"`) {
+		return goTestEvent{}, false
+	}
+	if err := json.Unmarshal([]byte(line), &ev); err != nil || ev.Action == "" {
+		return goTestEvent{}, false
+	}
+	return ev, true
+}
+
+// goFileLine matches the file:line prefix t.Error and t.Fatal put on failure
+// output, e.g. "    auth_test.go:42: want 200, got 500".
+var goFileLine = regexp.MustCompile(`^\s*([\w.-]+_test\.go):\d+: `)
+
+// parseGoTestJSON converts `go test -json` output. Every test and subtest is
+// a case, as with --- PASS/FAIL lines. module is the go.mod module path: a
+// failure's _test.go file is reported relative to the project root (so it
+// matches the test files mapped to features), otherwise File is the package.
+// A package that fails without running tests (a build error) is one failed
+// case. ok is false when the output holds no test2json events.
+func parseGoTestJSON(output, module string) (results TestResults, ok bool) {
+	type key struct{ pkg, test string }
+	outputs := make(map[key][]string)
+	tested := make(map[string]bool)
+	var first, last time.Time
+	for _, line := range strings.Split(output, "\n") {
+		ev, isEvent := goTestEventLine(strings.TrimSpace(line))
+		if !isEvent {
+			continue
+		}
+		ok = true
+		if !ev.Time.IsZero() {
+			if first.IsZero() || ev.Time.Before(first) {
+				first = ev.Time
+			}
+			if ev.Time.After(last) {
+				last = ev.Time
+			}
+		}
+		k := key{ev.Package, ev.Test}
+		switch ev.Action {
+		case "output":
+			outputs[k] = append(outputs[k], ev.Output)
+			continue
+		case "pass", "fail", "skip":
+		default:
+			continue
+		}
+		if ev.Test == "" {
+			// Package result: only a failure with no test results is
+			// reported, as the package's own case.
+			if ev.Action == "fail" && !tested[ev.Package] {
+				results.Failed++
+				results.Failures = append(results.Failures, ev.Package)
+				results.Cases = append(results.Cases, TestCase{Name: ev.Package, File: ev.Package, Status: "failed",
+					Message: goFailureMessage(outputs[k])})
+			}
+			continue
+		}
+		tested[ev.Package] = true
+		tc := TestCase{Name: ev.Test, File: ev.Package, Duration: time.Duration(ev.Elapsed * float64(time.Second))}
+		switch ev.Action {
+		case "pass":
+			tc.Status = "passed"
+			results.Passed++
+		case "fail":
+			tc.Status = "failed"
+			tc.Message = goFailureMessage(outputs[k])
+			if file := goFailureFile(outputs[k]); file != "" {
+				tc.File = goTestFilePath(ev.Package, module, file)
+			}
+			results.Failed++
+			results.Failures = append(results.Failures, ev.Test)
+		case "skip":
+			tc.Status = "skipped"
+		}
+		results.Cases = append(results.Cases, tc)
+	}
+	results.Total = results.Passed + results.Failed
+	if last.After(first) {
+		results.Duration = last.Sub(first)
+	}
+	return results, ok
+}
+
+// goFailureMessage returns the first line of a test's output that is not a
+// === RUN/PAUSE/CONT or --- FAIL marker, trimmed.
+func goFailureMessage(lines []string) string {
+	for _, l := range lines {
+		t := strings.TrimSpace(l)
+		if t == "" || t == "FAIL" || strings.HasPrefix(t, "=== ") || strings.HasPrefix(t, "--- ") {
+			continue
+		}
+		return t
+	}
+	return ""
+}
+
+// goFailureFile returns the _test.go file named by the first file:line
+// failure line of a test's output.
+func goFailureFile(lines []string) string {
+	for _, l := range lines {
+		if m := goFileLine.FindStringSubmatch(l); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// goTestFilePath joins a test file to its package directory relative to the
+// module root, or to the import path when the package is outside module.
+func goTestFilePath(pkg, module, file string) string {
+	if module != "" {
+		if pkg == module {
+			return file
+		}
+		if rel, found := strings.CutPrefix(pkg, module+"/"); found {
+			return path.Join(rel, file)
+		}
+	}
+	return path.Join(pkg, file)
+}
+
+// goModulePath reads the module path from projectDir/go.mod; "" when there
+// is none.
+func goModulePath(projectDir string) string {
+	data, err := os.ReadFile(filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, found := strings.CutPrefix(strings.TrimSpace(line), "module "); found {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// firstLine returns the first non-blank line of s, trimmed, with ANSI color
+// codes removed (Jest colors its failure messages).
+func firstLine(s string) string {
+	s = stripANSI(s)
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b[") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '[' {
+			j := i + 2
+			for j < len(s) && (s[j] < '@' || s[j] > '~') {
+				j++
+			}
+			i = j
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// slowestCase returns the longest-running case with a known duration.
+func slowestCase(cases []TestCase) (TestCase, bool) {
+	var slowest TestCase
+	found := false
+	for _, c := range cases {
+		if c.Duration > 0 && (!found || c.Duration > slowest.Duration) {
+			slowest, found = c, true
+		}
+	}
+	return slowest, found
+}
+
+// skippedCount counts skipped cases.
+func skippedCount(cases []TestCase) int {
+	n := 0
+	for _, c := range cases {
+		if c.Status == "skipped" {
+			n++
+		}
+	}
+	return n
+}
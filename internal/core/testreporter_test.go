@@ -0,0 +1,174 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const jestReportJSON = `{"numFailedTestSuites":1,"numPassedTests":2,"numFailedTests":1,"numPendingTests":1,"numTotalTests":4,"startTime":1700000000000,"success":false,"testResults":[{"name":"/app/src/auth.test.ts","status":"failed","startTime":1700000000100,"endTime":1700000001350,"message":"","assertionResults":[{"ancestorTitles":["auth"],"fullName":"auth logs in","title":"logs in","status":"passed","duration":12,"failureMessages":[]},{"ancestorTitles":["auth"],"fullName":"auth rejects bad password","title":"rejects bad password","status":"failed","duration":830.5,"failureMessages":["\u001b[31mError: expected 401, got 200\u001b[39m\n    at Object.<anonymous> (auth.test.ts:14:5)"]},{"ancestorTitles":["auth"],"fullName":"auth logs out","title":"logs out","status":"pending","duration":null,"failureMessages":[]}]},{"name":"/app/src/cart.test.ts","status":"passed","startTime":1700000000100,"endTime":1700000000400,"message":"","assertionResults":[{"ancestorTitles":[],"fullName":"adds items","title":"adds items","status":"passed","duration":3,"failureMessages":[]}]}]}`
+
+func TestParseJestJSON(t *testing.T) {
+	// jest --json prints the report on stdout while logs go to stderr; the
+	// runner sees both.
+	output := "PASS src/cart.test.ts\nconsole.log ok 1\n" + jestReportJSON + "\nDone in 1.4s.\n"
+
+	r, ok := parseJestJSON(output)
+	if !ok {
+		t.Fatal("report not recognized")
+	}
+	if r.Total != 3 || r.Passed != 2 || r.Failed != 1 || len(r.Cases) != 4 {
+		t.Fatalf("counts = %d/%d/%d cases=%d", r.Total, r.Passed, r.Failed, len(r.Cases))
+	}
+	if len(r.Failures) != 1 || r.Failures[0] != "auth rejects bad password" {
+		t.Errorf("failures = %v", r.Failures)
+	}
+	failed := r.Cases[1]
+	if failed.File != "/app/src/auth.test.ts" || failed.Message != "Error: expected 401, got 200" || failed.Duration != 830500*time.Microsecond {
+		t.Errorf("failed case = %+v", failed)
+	}
+	if r.Cases[2].Status != "skipped" {
+		t.Errorf("pending test should be skipped, got %q", r.Cases[2].Status)
+	}
+	if r.Duration != 1350*time.Millisecond {
+		t.Errorf("duration = %s, want 1.35s", r.Duration)
+	}
+
+	if _, ok := parseJestJSON("ok 1 - plain TAP\n"); ok {
+		t.Error("TAP output must not parse as a JSON report")
+	}
+}
+
+func TestParseJestJSONSuiteLoadFailure(t *testing.T) {
+	// Vitest reports a file that fails to import with no assertions.
+	report := `{"numTotalTestSuites":1,"numTotalTests":0,"startTime":1,"testResults":[{"name":"/app/broken.test.ts","status":"failed","message":"Failed to load url ./missing","assertionResults":[]}]}`
+	r, ok := parseJestJSON(report)
+	if !ok || r.Failed != 1 || r.Cases[0].Message != "Failed to load url ./missing" {
+		t.Errorf("suite failure not reported: %+v ok=%v", r, ok)
+	}
+}
+
+func TestRunTestsJestJSONUpdatesState(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	os.MkdirAll(filepath.Join(dir, "tests"), 0755)
+	os.WriteFile(filepath.Join(dir, "tests", "report.json"), []byte(jestReportJSON), 0644)
+	os.WriteFile(filepath.Join(dir, "tests", "auth.test.ts"), []byte("test('x')\n"), 0644)
+	// Stands in for `npx jest --json`: prints the report, exits 1.
+	os.WriteFile(filepath.Join(dir, "tests", "run.sh"), []byte("#!/bin/sh\ncat tests/report.json\nexit 1\n"), 0755)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("testing:\n  runner: ./tests/run.sh\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte("features:\n  auth:\n    stage: test\n    hashes:\n    scores:\n    tests:\n      - tests/auth.test.ts\n"), 0644)
+
+	results, err := RunTests(dir, "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.Failed != 1 || len(results.Cases) != 4 {
+		t.Fatalf("results = %+v", results)
+	}
+
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := state.Features["auth"].Hashes
+	for key, want := range map[string]string{
+		"test_status":   "failing",
+		"test_results":  "passed:2 failed:1",
+		"test_failures": "auth rejects bad password",
+		"test_duration": "1.35s",
+		"test_skipped":  "1",
+		"test_slowest":  "auth rejects bad password 831ms",
+	} {
+		if h[key] != want {
+			t.Errorf("%s = %q, want %q", key, h[key], want)
+		}
+	}
+
+	// A plain runner no longer reports details: they are cleared.
+	os.WriteFile(filepath.Join(dir, "tests", "run.sh"), []byte("#!/bin/sh\necho 'ok 1 - x'\n"), 0755)
+	if _, err := RunTests(dir, "auth"); err != nil {
+		t.Fatal(err)
+	}
+	state, _ = LoadState(dir)
+	if h := state.Features["auth"].Hashes; h["test_duration"] != "" || h["test_slowest"] != "" || !strings.HasPrefix(h["test_results"], "passed:1") {
+		t.Errorf("stale report details kept: %v", h)
+	}
+}
+
+const goTestJSON = `{"Time":"2026-01-05T10:00:00Z","Action":"start","Package":"example.com/app/auth"}
+{"Time":"2026-01-05T10:00:00.1Z","Action":"run","Package":"example.com/app/auth","Test":"TestLogin"}
+{"Time":"2026-01-05T10:00:00.1Z","Action":"output","Package":"example.com/app/auth","Test":"TestLogin","Output":"=== RUN   TestLogin\n"}
+{"Time":"2026-01-05T10:00:00.2Z","Action":"output","Package":"example.com/app/auth","Test":"TestLogin","Output":"--- PASS: TestLogin (0.12s)\n"}
+{"Time":"2026-01-05T10:00:00.2Z","Action":"pass","Package":"example.com/app/auth","Test":"TestLogin","Elapsed":0.12}
+{"Time":"2026-01-05T10:00:00.3Z","Action":"output","Package":"example.com/app/auth","Test":"TestLogout","Output":"    logout_test.go:17: session still valid\n"}
+{"Time":"2026-01-05T10:00:00.3Z","Action":"output","Package":"example.com/app/auth","Test":"TestLogout","Output":"--- FAIL: TestLogout (0.30s)\n"}
+{"Time":"2026-01-05T10:00:00.3Z","Action":"fail","Package":"example.com/app/auth","Test":"TestLogout","Elapsed":0.3}
+{"Time":"2026-01-05T10:00:00.3Z","Action":"skip","Package":"example.com/app/auth","Test":"TestSSO","Elapsed":0}
+{"Time":"2026-01-05T10:00:00.4Z","Action":"fail","Package":"example.com/app/auth","Elapsed":0.4}
+{"Time":"2026-01-05T10:00:01Z","Action":"output","Package":"example.com/app/billing","Output":"billing.go:3:1: syntax error\n"}
+{"Time":"2026-01-05T10:00:01.5Z","Action":"fail","Package":"example.com/app/billing","Elapsed":0}
+`
+
+func TestParseGoTestJSON(t *testing.T) {
+	results, ok := parseGoTestJSON(goTestJSON, "example.com/app")
+	if !ok {
+		t.Fatal("go test -json output not recognized")
+	}
+	if results.Total != 3 || results.Passed != 1 || results.Failed != 2 {
+		t.Errorf("counts = %d total %d passed %d failed, want 3/1/2", results.Total, results.Passed, results.Failed)
+	}
+	if results.Duration != 1500*time.Millisecond {
+		t.Errorf("duration = %v, want 1.5s", results.Duration)
+	}
+	if len(results.Cases) != 4 {
+		t.Fatalf("cases = %+v", results.Cases)
+	}
+	logout := results.Cases[1]
+	if logout.Status != "failed" || logout.File != "auth/logout_test.go" || logout.Message != "logout_test.go:17: session still valid" || logout.Duration != 300*time.Millisecond {
+		t.Errorf("failed case = %+v", logout)
+	}
+	if results.Cases[2].Status != "skipped" {
+		t.Errorf("TestSSO = %+v, want skipped", results.Cases[2])
+	}
+	// A package that fails to build is a failure of its own.
+	if build := results.Cases[3]; build.Name != "example.com/app/billing" || build.Message != "billing.go:3:1: syntax error" {
+		t.Errorf("build failure case = %+v", build)
+	}
+	if !slices.Equal(results.Failures, []string{"TestLogout", "example.com/app/billing"}) {
+		t.Errorf("failures = %v", results.Failures)
+	}
+
+	if _, ok := parseGoTestJSON("--- PASS: TestX (0.00s)\nok  \texample.com/app\n", ""); ok {
+		t.Error("plain go test output must not be taken for -json")
+	}
+}
+
+func TestTestResultLineGoTestJSON(t *testing.T) {
+	name, passed, ok := testResultLine(`{"Action":"fail","Package":"p","Test":"TestLogout","Elapsed":0.3}`)
+	if !ok || passed || name != "TestLogout" {
+		t.Errorf("fail event = %q %v %v", name, passed, ok)
+	}
+	if _, _, ok := testResultLine(`{"Action":"output","Package":"p","Test":"TestLogout","Output":"--- PASS: TestLogout\n"}`); ok {
+		t.Error("output events are not results")
+	}
+}
+
+func TestRunTestsGoTestJSON(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress")
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "events.json"), []byte(goTestJSON), 0644)
+	// Stands in for `go test -json ./...`.
+	os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\ncat events.json\nexit 1\n"), 0755)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "ptsd.yaml"), []byte("testing:\n  runner: ./run.sh\n"), 0644)
+
+	results, err := RunTests(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.Passed != 1 || results.Failed != 2 || results.Cases[1].File != "auth/logout_test.go" {
+		t.Errorf("results = %+v", results)
+	}
+}
@@ -1,11 +1,16 @@
 package core
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 )
 
 type TestResults struct {
@@ -13,18 +18,118 @@ type TestResults struct {
 	Passed   int
 	Failed   int
 	Failures []string
+	// StoppedAfter is the failure threshold that cut the run short; the
+	// counts above are partial. 0 when the runner finished.
+	StoppedAfter int
+	// Features breaks a parallel run down per feature, in registry order; a
+	// sequential run lists the features run with their own runner.
+	Features []FeatureTestResults
+	// Cases and Duration come from a structured runner report (Jest or
+	// Vitest JSON, go test -json); they are empty for Go text, TAP and
+	// exit-code results.
+	Cases    []TestCase
+	Duration time.Duration
+}
+
+// FeatureTestResults are the results of one feature's runner process in a
+// parallel run.
+type FeatureTestResults struct {
+	Feature  string
+	Total    int
+	Passed   int
+	Failed   int
+	Failures []string
+}
+
+// TestRunOptions tunes RunTestsWithOptions.
+type TestRunOptions struct {
+	Progress ProgressFunc
+	// MaxFailures stops the runner after this many failed tests; 0 falls
+	// back to testing.max_failures.
+	MaxFailures int
+	// Parallel runs up to this many runner processes at once, one per
+	// feature with mapped tests. 0 or 1 runs the runner once.
+	Parallel int
 }
 
 type CoverageEntry struct {
 	Feature string
 	Status  string
+	// Scenarios counts the feature file's scenarios and Covered those with
+	// a scenario- or function-level mapping; Uncovered names the rest by
+	// title. Whole-file mappings cover no scenario in particular.
+	Scenarios int
+	Covered   int
+	Uncovered []string
+}
+
+// testMapping is one entry of a feature's tests list in state.yaml:
+//
+//	<test-file>                                      found by adopt
+//	<bdd-file>::<test-file>                          whole feature file
+//	<bdd-file>#<scenario-id>::<test-file>            one scenario
+//	<bdd-file>#<scenario-id>::<test-file>::<func>    one test function
+type testMapping struct {
+	BDD      string
+	Scenario string
+	File     string
+	Func     string
+}
+
+func parseTestMapping(s string) testMapping {
+	s = slashPath(s)
+	bddRef, rest, ok := strings.Cut(s, "::")
+	if !ok {
+		return testMapping{File: s}
+	}
+	var m testMapping
+	m.BDD, m.Scenario, _ = strings.Cut(bddRef, "#")
+	m.File, m.Func, _ = strings.Cut(rest, "::")
+	return m
+}
+
+func (m testMapping) String() string {
+	if m.BDD == "" {
+		return m.File
+	}
+	s := m.BDD
+	if m.Scenario != "" {
+		s += "#" + m.Scenario
+	}
+	s += "::" + m.File
+	if m.Func != "" {
+		s += "::" + m.Func
+	}
+	return s
 }
 
 func MapTest(projectDir string, bddFile string, testFile string) error {
+	return MapScenarioTest(projectDir, bddFile, "", testFile)
+}
+
+// MapScenarioTest maps a test file to one scenario of a BDD file. The scenario
+// is referenced by its stable ID (or title, resolved to the ID), so the
+// mapping is stored as <bdd-file>#<scenario-id>::<test-file>. An empty
+// scenario maps the whole file.
+func MapScenarioTest(projectDir string, bddFile string, scenario string, testFile string) error {
+	_, err := MapScenarioTestFunc(projectDir, bddFile, scenario, testFile, "")
+	return err
+}
+
+// MapScenarioTestFunc is MapScenarioTest down to one test function of the
+// file (a Go/Python function name or a JS/TS test title), stored as
+// <bdd-file>#<scenario-id>::<test-file>::<func>. It returns the mapping.
+func MapScenarioTestFunc(projectDir string, bddFile string, scenario string, testFile string, testFunc string) (string, error) {
+	if testFunc != "" && scenario == "" {
+		return "", fmt.Errorf("err:user a test function is mapped to a scenario: add --scenario")
+	}
+	// One spelling per path, so ./t/a_test.go or t\a_test.go is the same
+	// mapping as t/a_test.go and not a second one.
+	bddFile, testFile = slashPath(filepath.Clean(bddFile)), slashPath(filepath.Clean(testFile))
 	bddPath := filepath.Join(projectDir, bddFile)
 	data, err := os.ReadFile(bddPath)
 	if err != nil {
-		return fmt.Errorf("err:io %w", err)
+		return "", fmt.Errorf("err:io %w", err)
 	}
 
 	featureID := ""
@@ -36,18 +141,21 @@ func MapTest(projectDir string, bddFile string, testFile string) error {
 		}
 	}
 	if featureID == "" {
-		return fmt.Errorf("err:validation no @feature tag in %s", bddFile)
+		return "", fmt.Errorf("err:validation no @feature tag in %s", bddFile)
 	}
 
 	// Verify test file exists
 	testPath := filepath.Join(projectDir, testFile)
 	if _, err := os.Stat(testPath); os.IsNotExist(err) {
-		return fmt.Errorf("err:validation test file %s not found", testFile)
+		return "", fmt.Errorf("err:validation test file %s not found", testFile)
+	}
+	if testFunc != "" && !slices.Contains(testFunctions(testPath), testFunc) {
+		return "", fmt.Errorf("err:validation test function %s not found in %s", testFunc, testFile)
 	}
 
 	state, err := LoadState(projectDir)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	fs, ok := state.Features[featureID]
@@ -58,7 +166,16 @@ func MapTest(projectDir string, bddFile string, testFile string) error {
 		}
 	}
 
-	mapping := bddFile + "::" + testFile
+	m := testMapping{BDD: bddFile, File: testFile, Func: testFunc}
+	if scenario != "" {
+		ff, _ := parseFeatureContent(string(data))
+		sc, ok := findScenario(ff, scenario)
+		if !ok {
+			return "", fmt.Errorf("err:validation scenario %q not found in %s", scenario, bddFile)
+		}
+		m.Scenario = sc.ID
+	}
+	mapping := m.String()
 
 	// Check for duplicate
 	var testsList []string
@@ -66,7 +183,7 @@ func MapTest(projectDir string, bddFile string, testFile string) error {
 		if existing, ok := fs.Tests.([]string); ok {
 			for _, t := range existing {
 				if t == mapping {
-					return nil // already mapped
+					return mapping, nil // already mapped: no state change, no event
 				}
 			}
 			testsList = existing
@@ -75,12 +192,40 @@ func MapTest(projectDir string, bddFile string, testFile string) error {
 	testsList = append(testsList, mapping)
 	fs.Tests = testsList
 	state.Features[featureID] = fs
+	state.Unmapped = slices.DeleteFunc(state.Unmapped, func(f string) bool { return f == testFile })
+
+	if err := writeState(projectDir, state); err != nil {
+		return "", err
+	}
+
+	recordEvent(projectDir, Event{Type: EventTest, Feature: featureID, Detail: testFile})
+	return mapping, nil
+}
 
-	return writeState(projectDir, state)
+// UnmappedTests returns the test files adopt recorded as unmapped that still
+// exist and that no feature maps yet, in discovery order.
+func UnmappedTests(projectDir string) ([]string, error) {
+	state, err := LoadState(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, f := range state.Unmapped {
+		if _, err := os.Stat(filepath.Join(projectDir, f)); err != nil {
+			continue
+		}
+		if !mappedByAnyFeature(state, f) {
+			out = append(out, f)
+		}
+	}
+	return out, nil
 }
 
+// CheckTestCoverage reports, per BDD feature file, which scenarios have a
+// scenario- or function-level test mapping. A feature is covered when every
+// scenario has one, partial when it has any test mapping at all.
 func CheckTestCoverage(projectDir string) ([]CoverageEntry, error) {
-	bddDir := filepath.Join(projectDir, ".ptsd", "bdd")
+	bddDir := bddDirPath(projectDir)
 	entries, err := os.ReadDir(bddDir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -106,52 +251,100 @@ func CheckTestCoverage(projectDir string) ([]CoverageEntry, error) {
 			continue
 		}
 
-		featureID := ""
-		scenarioCount := 0
-		for _, line := range strings.Split(string(data), "\n") {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "@feature:") {
-				featureID = strings.TrimPrefix(line, "@feature:")
-			}
-			if strings.HasPrefix(line, "Scenario:") {
-				scenarioCount++
-			}
-		}
+		ff, _ := parseFeatureContent(string(data))
+		featureID := ff.Tag
 		if featureID == "" {
 			continue
 		}
 
-		// Count test mappings from state
-		testCount := 0
+		var mappings []string
 		if fs, ok := state.Features[featureID]; ok {
-			if tests, ok := fs.Tests.([]string); ok {
-				testCount = len(tests)
+			mappings, _ = fs.Tests.([]string)
+		}
+		covered := coveredScenarios(ff, mappings)
+		entry := CoverageEntry{Feature: featureID, Scenarios: len(ff.Scenarios), Covered: len(covered)}
+		for _, sc := range ff.Scenarios {
+			if !covered[sc.ID] {
+				entry.Uncovered = append(entry.Uncovered, sc.Title)
 			}
 		}
 
-		status := "no-tests"
-		if testCount > 0 && testCount >= scenarioCount {
-			status = "covered"
-		} else if testCount > 0 {
-			status = "partial"
+		entry.Status = "no-tests"
+		if len(mappings) > 0 && len(entry.Uncovered) == 0 {
+			entry.Status = "covered"
+		} else if len(mappings) > 0 {
+			entry.Status = "partial"
 		}
 
-		coverage = append(coverage, CoverageEntry{Feature: featureID, Status: status})
+		coverage = append(coverage, entry)
 	}
 
 	return coverage, nil
 }
 
+// coveredScenarios returns the IDs of the scenarios in ff that a mapping
+// names. A mapping to an outline covers each of its expansions.
+func coveredScenarios(ff FeatureFileData, mappings []string) map[string]bool {
+	live := make(map[string]bool)
+	for _, sc := range ff.Scenarios {
+		live[sc.ID] = true
+	}
+	covered := make(map[string]bool)
+	for _, s := range mappings {
+		id := parseTestMapping(s).Scenario
+		if id == "" {
+			continue
+		}
+		if live[id] {
+			covered[id] = true
+		}
+		for _, sc := range ff.Scenarios {
+			if sc.Outline == id {
+				covered[sc.ID] = true
+			}
+		}
+	}
+	return covered
+}
+
 func RunTests(projectDir string, featureFilter string) (TestResults, error) {
+	return RunTestsWithProgress(projectDir, featureFilter, nil)
+}
+
+// RunTestsWithProgress is RunTests streaming a progress event for every test
+// result line (Go or TAP) as the runner prints it.
+func RunTestsWithProgress(projectDir string, featureFilter string, progress ProgressFunc) (TestResults, error) {
+	return RunTestsWithOptions(projectDir, featureFilter, TestRunOptions{Progress: progress})
+}
+
+// RunTestsWithOptions runs the configured test runner. With a failure
+// threshold, the runner's process group is killed as soon as that many failed
+// result lines have streamed by and the partial results are returned. With
+// Parallel > 1 and no feature filter, each feature's mapped test files get
+// their own runner process (see runTestsParallel).
+func RunTestsWithOptions(projectDir string, featureFilter string, opts TestRunOptions) (TestResults, error) {
+	progress := opts.Progress
 	cfg, err := LoadConfig(projectDir)
 	if err != nil {
 		return TestResults{}, err
 	}
+	if featureFilter != "" {
+		cfg = cfg.ForFeature(featureFilter)
+	}
 
 	if cfg.Testing.Runner == "" {
 		return TestResults{}, fmt.Errorf("err:config no test runner configured")
 	}
 
+	maxFailures := opts.MaxFailures
+	if maxFailures == 0 {
+		maxFailures = cfg.Testing.MaxFailures
+	}
+
+	if opts.Parallel > 1 && featureFilter == "" {
+		return runTestsParallel(projectDir, cfg, opts.Parallel, maxFailures, progress)
+	}
+
 	runner := cfg.Testing.Runner
 
 	// When a feature filter is specified, extract that feature's test files
@@ -167,9 +360,96 @@ func RunTests(projectDir string, featureFilter string) (TestResults, error) {
 		runner = runner + " " + strings.Join(testFiles, " ")
 	}
 
+	progress.emit(ProgressEvent{Phase: "test", Percent: 0})
+
+	var passed, failed int
+	stopped := false
+	results := runTestCommand(projectDir, cfg, newRunnerCmd(projectDir, runner), nil, func(name string, ok bool) bool {
+		if ok {
+			passed++
+		} else {
+			failed++
+		}
+		progress.emit(ProgressEvent{Phase: "test", Percent: -1, File: name, Passed: passed, Failed: failed})
+		if maxFailures > 0 && failed >= maxFailures {
+			stopped = true
+		}
+		return stopped
+	})
+	if stopped {
+		results.StoppedAfter = maxFailures
+	}
+
+	// Features with their own runner are not covered by the project runner:
+	// run each on its mapped test files and merge the results.
+	if featureFilter == "" && !stopped {
+		runOverriddenFeatures(projectDir, cfg, &results)
+	}
+
+	// Update state with results
+	updateStateWithResults(projectDir, featureFilter, results)
+	progress.emit(ProgressEvent{Phase: "done", Percent: 100, Passed: results.Passed, Failed: results.Failed})
+
+	return results, nil
+}
+
+// runOverriddenFeatures runs every feature with a features_config runner
+// override on its mapped test files, records each feature's results, and
+// merges them into results with a per-feature breakdown.
+func runOverriddenFeatures(projectDir string, cfg *Config, results *TestResults) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return
+	}
+	for _, f := range features {
+		if cfg.Features[f.ID].Runner == "" {
+			continue
+		}
+		files, err := featureTestFiles(projectDir, f.ID)
+		if err != nil || len(files) == 0 {
+			continue
+		}
+		fcfg := cfg.ForFeature(f.ID)
+		r := runTestCommand(projectDir, fcfg, newRunnerCmd(projectDir, fcfg.Testing.Runner+" "+strings.Join(files, " ")), nil,
+			func(string, bool) bool { return false })
+		updateStateWithResults(projectDir, f.ID, r)
+		results.Total += r.Total
+		results.Passed += r.Passed
+		results.Failed += r.Failed
+		results.Failures = append(results.Failures, r.Failures...)
+		results.Cases = append(results.Cases, r.Cases...)
+		results.Duration += r.Duration
+		results.Features = append(results.Features, FeatureTestResults{
+			Feature: f.ID, Total: r.Total, Passed: r.Passed, Failed: r.Failed, Failures: r.Failures,
+		})
+	}
+}
+
+// newRunnerCmd builds the shell command for one runner invocation in its own
+// process group.
+func newRunnerCmd(projectDir, runner string) *exec.Cmd {
 	cmd := exec.Command("sh", "-c", runner)
 	cmd.Dir = projectDir
-	output, err := cmd.CombinedOutput()
+	setProcessGroup(cmd)
+	return cmd
+}
+
+// runTestCommand runs one runner invocation and parses its output. started,
+// if set, is called once the process is running. onResult is called for
+// every result line as it streams; once it returns true the runner's process
+// group is killed and the partial output is parsed.
+func runTestCommand(projectDir string, cfg *Config, cmd *exec.Cmd, started func(), onResult func(name string, passed bool) bool) TestResults {
+	output, err := runStreaming(cmd, started, func(line string) bool {
+		name, ok, isResult := testResultLine(line)
+		if !isResult {
+			return false
+		}
+		if onResult(name, ok) {
+			killProcessGroup(cmd)
+			return true
+		}
+		return false
+	})
 
 	// Parse results based on adapter selection
 	var results TestResults
@@ -191,19 +471,89 @@ func RunTests(projectDir string, featureFilter string) (TestResults, error) {
 		}
 	}
 
-	// Detect Go test output by presence of === RUN or --- PASS/FAIL markers
+	// A JSON report is the richest source; otherwise detect Go test output
+	// by presence of === RUN or --- PASS/FAIL markers. go test -json is
+	// checked first since its events carry those markers as output.
 	outStr := string(output)
-	if strings.Contains(outStr, "=== RUN") || strings.Contains(outStr, "--- PASS:") || strings.Contains(outStr, "--- FAIL:") {
+	if report, ok := parseJestJSON(outStr); ok {
+		results = report
+	} else if report, ok := parseGoTestJSON(outStr, goModulePath(projectDir)); ok {
+		results = report
+	} else if strings.Contains(outStr, "=== RUN") || strings.Contains(outStr, "--- PASS:") || strings.Contains(outStr, "--- FAIL:") {
 		results = parseGoTestOutput(outStr)
 	} else if strings.Contains(outStr, "ok ") || strings.Contains(outStr, "not ok ") {
 		// Override with TAP parse if we got TAP-like output
 		results = parseTAPOutput(outStr)
 	}
+	return results
+}
 
-	// Update state with results
-	updateStateWithResults(projectDir, featureFilter, results)
+// runStreaming runs cmd with stdout and stderr combined, calling onLine for
+// each output line as it arrives, and returns the output. Once onLine returns
+// true the rest of the output is drained but not kept. started, if set, is
+// called right after the process starts.
+func runStreaming(cmd *exec.Cmd, started func(), onLine func(string) bool) ([]byte, error) {
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		pw.Close()
+		return nil, err
+	}
+	if started != nil {
+		started()
+	}
+	waitErr := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		waitErr <- err
+	}()
+
+	var buf bytes.Buffer
+	reader := bufio.NewReader(pr)
+	done := false
+	for {
+		line, err := reader.ReadString('\n')
+		if !done {
+			buf.WriteString(line)
+			if line != "" {
+				done = onLine(strings.TrimSpace(line))
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return buf.Bytes(), <-waitErr
+}
 
-	return results, nil
+// testResultLine recognizes a single Go (--- PASS/FAIL or a go test -json
+// pass/fail event) or TAP (ok/not ok) result line and returns the test name
+// and outcome.
+func testResultLine(line string) (name string, passed bool, ok bool) {
+	if ev, isEvent := goTestEventLine(line); isEvent {
+		if ev.Test == "" || (ev.Action != "pass" && ev.Action != "fail") {
+			return "", false, false
+		}
+		return ev.Test, ev.Action == "pass", true
+	}
+	switch {
+	case strings.HasPrefix(line, "--- PASS: "):
+		name, passed = strings.TrimPrefix(line, "--- PASS: "), true
+	case strings.HasPrefix(line, "--- FAIL: "):
+		name = strings.TrimPrefix(line, "--- FAIL: ")
+	case strings.HasPrefix(line, "not ok "):
+		name = strings.TrimPrefix(line, "not ok ")
+	case strings.HasPrefix(line, "ok "):
+		name, passed = strings.TrimPrefix(line, "ok "), true
+	default:
+		return "", false, false
+	}
+	if idx := strings.Index(name, " ("); idx > 0 {
+		name = name[:idx]
+	}
+	return name, passed, true
 }
 
 func containsKnownRunner(runner string) bool {
@@ -255,8 +605,8 @@ func parseGoTestOutput(output string) TestResults {
 	return results
 }
 
-// featureTestFiles extracts test file paths mapped to a feature from state.
-// Mappings use the format "bddFile::testFile"; this returns only the test file parts.
+// featureTestFiles extracts the test files mapped to a feature from state,
+// each once, in mapping order.
 func featureTestFiles(projectDir string, featureID string) ([]string, error) {
 	state, err := LoadState(projectDir)
 	if err != nil {
@@ -272,12 +622,8 @@ func featureTestFiles(projectDir string, featureID string) ([]string, error) {
 	}
 	var files []string
 	for _, m := range mappings {
-		parts := strings.SplitN(m, "::", 2)
-		if len(parts) == 2 {
-			files = append(files, parts[1])
-		} else {
-			// Plain test file path (no bdd:: prefix)
-			files = append(files, m)
+		if file := parseTestMapping(m).File; !slices.Contains(files, file) {
+			files = append(files, file)
 		}
 	}
 	return files, nil
@@ -309,8 +655,46 @@ func updateStateWithResults(projectDir string, featureFilter string, results Tes
 		} else {
 			fs.Hashes["test_status"] = "failing"
 		}
+		if names := failureNames(results.Failures); names != "" {
+			fs.Hashes["test_failures"] = names
+		} else {
+			delete(fs.Hashes, "test_failures")
+		}
+		// Details only a structured report provides; cleared when the
+		// runner no longer reports them.
+		delete(fs.Hashes, "test_duration")
+		delete(fs.Hashes, "test_skipped")
+		delete(fs.Hashes, "test_slowest")
+		if results.Duration > 0 {
+			fs.Hashes["test_duration"] = results.Duration.Round(time.Millisecond).String()
+		}
+		if n := skippedCount(results.Cases); n > 0 {
+			fs.Hashes["test_skipped"] = fmt.Sprint(n)
+		}
+		if c, ok := slowestCase(results.Cases); ok {
+			fs.Hashes["test_slowest"] = failureNames([]string{c.Name}) + " " + c.Duration.Round(time.Millisecond).String()
+		}
 		state.Features[featureFilter] = fs
 	}
 
 	writeState(projectDir, state)
 }
+
+// failureNames flattens failure names into one state.yaml value: first line
+// of each, at most 10, joined by ", ".
+func failureNames(failures []string) string {
+	var names []string
+	for _, f := range failures {
+		name, _, _ := strings.Cut(strings.TrimSpace(f), "\n")
+		name = strings.ReplaceAll(strings.TrimSpace(name), ": ", " ")
+		if name == "" {
+			continue
+		}
+		if len(names) == 10 {
+			names = append(names, "...")
+			break
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestMapBDDToTestFile(t *testing.T) {
@@ -465,3 +466,365 @@ func TestNoTestRunnerConfigured(t *testing.T) {
 	}
 }
 
+
+func TestScenarioMappingCoverageUsesStableIDs(t *testing.T) {
+	dir := t.TempDir()
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	bddDir := filepath.Join(ptsdDir, "bdd")
+	os.MkdirAll(bddDir, 0755)
+	os.MkdirAll(filepath.Join(dir, "tests"), 0755)
+	os.WriteFile(filepath.Join(dir, "tests", "auth.test.ts"), []byte("// test"), 0644)
+	os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte("features: {}\n"), 0644)
+
+	bddFile := ".ptsd/bdd/user-auth.feature"
+	os.WriteFile(filepath.Join(dir, bddFile), []byte("@feature:user-auth\nFeature: User Auth\n  @id:login\n  Scenario: Login\n  @id:logout\n  Scenario: Logout\n"), 0644)
+
+	if err := MapScenarioTest(dir, bddFile, "Login", "tests/auth.test.ts"); err != nil {
+		t.Fatalf("map by title: %v", err)
+	}
+	if err := MapScenarioTest(dir, bddFile, "logout", "tests/auth.test.ts"); err != nil {
+		t.Fatalf("map by id: %v", err)
+	}
+	if err := MapScenarioTest(dir, bddFile, "missing", "tests/auth.test.ts"); err == nil {
+		t.Error("expected error for unknown scenario")
+	}
+
+	data, _ := os.ReadFile(filepath.Join(ptsdDir, "state.yaml"))
+	if !strings.Contains(string(data), bddFile+"#login::tests/auth.test.ts") {
+		t.Errorf("expected id-based mapping in state, got:\n%s", data)
+	}
+
+	// Renaming a scenario keeps its explicit ID, so coverage holds.
+	os.WriteFile(filepath.Join(dir, bddFile), []byte("@feature:user-auth\nFeature: User Auth\n  @id:login\n  Scenario: Sign in\n  @id:logout\n  Scenario: Sign out\n"), 0644)
+	coverage, err := CheckTestCoverage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coverage) != 1 || coverage[0].Status != "covered" {
+		t.Errorf("expected covered after rename, got %+v", coverage)
+	}
+}
+
+func TestRunTestsStopsAfterMaxFailures(t *testing.T) {
+	dir := t.TempDir()
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	if err := os.MkdirAll(filepath.Join(dir, "tests"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(ptsdDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	testScript := `#!/bin/sh
+echo "ok 1 - pass"
+echo "not ok 2 - first"
+echo "not ok 3 - second"
+sleep 30
+echo "ok 4 - too late"
+`
+	if err := os.WriteFile(filepath.Join(dir, "tests", "run.sh"), []byte(testScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configYAML := "project:\n  name: TestApp\ntesting:\n  runner: ./tests/run.sh\n  max_failures: 2\n"
+	if err := os.WriteFile(filepath.Join(ptsdDir, "ptsd.yaml"), []byte(configYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	results, err := RunTests(dir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("run was not stopped early (%s)", time.Since(start))
+	}
+	if results.StoppedAfter != 2 || results.Passed != 1 || results.Failed != 2 {
+		t.Errorf("expected partial results stopped after 2 failures, got %+v", results)
+	}
+
+	results, err = RunTestsWithOptions(dir, "", TestRunOptions{MaxFailures: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results.StoppedAfter != 1 || results.Failed != 1 {
+		t.Errorf("fail-fast must stop at the first failure, got %+v", results)
+	}
+}
+
+func TestRunTestsParallelPerFeature(t *testing.T) {
+	dir := t.TempDir()
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	if err := os.MkdirAll(filepath.Join(dir, "tests"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(ptsdDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each test file name decides the outcome: *fail* files fail.
+	testScript := `#!/bin/sh
+for f in "$@"; do
+  case "$f" in
+    *fail*) echo "not ok 1 - $f" ;;
+    *) echo "ok 1 - $f" ;;
+  esac
+done
+`
+	if err := os.WriteFile(filepath.Join(dir, "tests", "run.sh"), []byte(testScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configYAML := "project:\n  name: TestApp\ntesting:\n  runner: ./tests/run.sh\n"
+	if err := os.WriteFile(filepath.Join(ptsdDir, "ptsd.yaml"), []byte(configYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	featuresYAML := "features:\n  - id: user-auth\n    status: in-progress\n  - id: data-sync\n    status: in-progress\n  - id: billing\n    status: planned\n"
+	if err := os.WriteFile(filepath.Join(ptsdDir, "features.yaml"), []byte(featuresYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stateYAML := `features:
+  user-auth:
+    tests:
+      - .ptsd/bdd/user-auth.feature::tests/auth.test.ts
+      - .ptsd/bdd/user-auth.feature::tests/login.test.ts
+  data-sync:
+    tests:
+      - .ptsd/bdd/data-sync.feature::tests/sync-fail.test.ts
+`
+	if err := os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte(stateYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := RunTestsWithOptions(dir, "", TestRunOptions{Parallel: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results.Total != 3 || results.Passed != 2 || results.Failed != 1 {
+		t.Errorf("expected merged 2 passed / 1 failed, got %+v", results)
+	}
+	if len(results.Features) != 2 {
+		t.Fatalf("expected a breakdown for the 2 mapped features, got %+v", results.Features)
+	}
+	auth, sync := results.Features[0], results.Features[1]
+	if auth.Feature != "user-auth" || auth.Passed != 2 || auth.Failed != 0 {
+		t.Errorf("user-auth breakdown = %+v", auth)
+	}
+	if sync.Feature != "data-sync" || sync.Failed != 1 {
+		t.Errorf("data-sync breakdown = %+v", sync)
+	}
+
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Features["user-auth"].Hashes["test_status"]; got != "passing" {
+		t.Errorf("user-auth test_status = %q, want passing", got)
+	}
+	if got := state.Features["data-sync"].Hashes["test_status"]; got != "failing" {
+		t.Errorf("data-sync test_status = %q, want failing", got)
+	}
+}
+
+func TestRunTestsParallelStopsAllAfterMaxFailures(t *testing.T) {
+	dir := t.TempDir()
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	if err := os.MkdirAll(filepath.Join(dir, "tests"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(ptsdDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	testScript := `#!/bin/sh
+case "$1" in
+  *fail*) echo "not ok 1 - $1" ;;
+  *) sleep 30; echo "ok 1 - $1" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "tests", "run.sh"), []byte(testScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	configYAML := "project:\n  name: TestApp\ntesting:\n  runner: ./tests/run.sh\n"
+	if err := os.WriteFile(filepath.Join(ptsdDir, "ptsd.yaml"), []byte(configYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	featuresYAML := "features:\n  - id: slow\n    status: in-progress\n  - id: broken\n    status: in-progress\n"
+	if err := os.WriteFile(filepath.Join(ptsdDir, "features.yaml"), []byte(featuresYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stateYAML := "features:\n  slow:\n    tests:\n      - tests/slow.test.ts\n  broken:\n    tests:\n      - tests/broken-fail.test.ts\n"
+	if err := os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte(stateYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	results, err := RunTestsWithOptions(dir, "", TestRunOptions{Parallel: 2, MaxFailures: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("slow feature's runner was not killed (%s)", time.Since(start))
+	}
+	if results.StoppedAfter != 1 || results.Failed != 1 {
+		t.Errorf("expected a run stopped after 1 failure, got %+v", results)
+	}
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Features["slow"].Hashes["test_status"]; got != "" {
+		t.Errorf("cut-short feature must keep its test_status, got %q", got)
+	}
+}
+
+func TestCheckTestCoverageOutlineExpansions(t *testing.T) {
+	dir := t.TempDir()
+	bddDir := filepath.Join(dir, ".ptsd", "bdd")
+	if err := os.MkdirAll(bddDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bddDir, "refunds.feature"), []byte(outlineBDD), 0644); err != nil {
+		t.Fatal(err)
+	}
+	write := func(tests string) {
+		state := "features:\n  refunds:\n    tests:\n" + tests
+		if err := os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte(state), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One expansion mapped: 1 of 3 scenarios.
+	write("      - .ptsd/bdd/refunds.feature#refund-1::refund_test.go\n")
+	coverage, err := CheckTestCoverage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coverage) != 1 || coverage[0].Status != "partial" {
+		t.Errorf("expected partial coverage, got %+v", coverage)
+	}
+
+	// The outline covers both expansions.
+	write("      - .ptsd/bdd/refunds.feature#refund::refund_test.go\n      - .ptsd/bdd/refunds.feature#" + scenarioHashID("Cancel refund") + "::cancel_test.go\n")
+	coverage, err = CheckTestCoverage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coverage) != 1 || coverage[0].Status != "covered" {
+		t.Errorf("expected outline mapping to cover its expansions, got %+v", coverage)
+	}
+}
+
+func TestMapScenarioTestFunc(t *testing.T) {
+	dir := t.TempDir()
+	ptsdDir := filepath.Join(dir, ".ptsd")
+	os.MkdirAll(filepath.Join(ptsdDir, "bdd"), 0755)
+	os.WriteFile(filepath.Join(ptsdDir, "state.yaml"), []byte("features: {}\n"), 0644)
+	bddFile := ".ptsd/bdd/user-auth.feature"
+	os.WriteFile(filepath.Join(dir, bddFile), []byte("@feature:user-auth\nFeature: User Auth\n  @id:login\n  Scenario: Login\n  @id:logout\n  Scenario: Logout\n  Scenario: Register\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "auth_test.go"), []byte("package auth\n\nfunc TestLogin(t *testing.T) {}\n\nfunc TestLogout(t *testing.T) {}\n"), 0644)
+
+	mapping, err := MapScenarioTestFunc(dir, bddFile, "Login", "auth_test.go", "TestLogin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mapping != bddFile+"#login::auth_test.go::TestLogin" {
+		t.Errorf("unexpected mapping %q", mapping)
+	}
+	if _, err := MapScenarioTestFunc(dir, bddFile, "logout", "auth_test.go", "TestLogout"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MapScenarioTestFunc(dir, bddFile, "logout", "auth_test.go", "TestMissing"); err == nil || !strings.Contains(err.Error(), "test function TestMissing not found") {
+		t.Errorf("expected unknown function error, got %v", err)
+	}
+	if _, err := MapScenarioTestFunc(dir, bddFile, "", "auth_test.go", "TestLogin"); err == nil || !strings.HasPrefix(err.Error(), "err:user") {
+		t.Errorf("expected --scenario error, got %v", err)
+	}
+
+	files, err := featureTestFiles(dir, "user-auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(files, " ") != "auth_test.go" {
+		t.Errorf("expected the test file once, got %v", files)
+	}
+
+	coverage, err := CheckTestCoverage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coverage) != 1 {
+		t.Fatalf("expected one coverage entry, got %+v", coverage)
+	}
+	c := coverage[0]
+	if c.Status != "partial" || c.Scenarios != 3 || c.Covered != 2 || strings.Join(c.Uncovered, ",") != "Register" {
+		t.Errorf("unexpected coverage: %+v", c)
+	}
+}
+
+func TestMapTestRecordsEventOnlyWhenAdded(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".ptsd", "bdd"), 0755)
+	os.MkdirAll(filepath.Join(dir, "tests"), 0755)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte("features: {}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "user-auth.feature"), []byte("@feature:user-auth\nFeature: User Auth\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "tests", "auth_test.go"), []byte("package auth\n"), 0644)
+
+	for _, testFile := range []string{"tests/auth_test.go", "tests/auth_test.go", "./tests/auth_test.go"} {
+		if err := MapTest(dir, ".ptsd/bdd/user-auth.feature", testFile); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state, err := LoadState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tests := state.Features["user-auth"].Tests.([]string); len(tests) != 1 {
+		t.Errorf("expected one mapping, got %v", tests)
+	}
+	events, err := LoadEvents(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, e := range events {
+		if e.Type == EventTest {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("expected one test event, got %d", n)
+	}
+}
+
+func TestCheckTestCoverageIgnoresWholeFileMappings(t *testing.T) {
+	dir := t.TempDir()
+	bddDir := filepath.Join(dir, ".ptsd", "bdd")
+	os.MkdirAll(bddDir, 0755)
+	os.WriteFile(filepath.Join(bddDir, "search.feature"), []byte("@feature:search\nFeature: Search\n  Scenario: Find by name\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte("features:\n  search:\n    tests:\n      - .ptsd/bdd/search.feature::search_test.go\n"), 0644)
+
+	coverage, err := CheckTestCoverage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coverage) != 1 || coverage[0].Status != "partial" || strings.Join(coverage[0].Uncovered, ",") != "Find by name" {
+		t.Errorf("expected the scenario reported uncovered, got %+v", coverage)
+	}
+}
+
+func TestParseTestMapping(t *testing.T) {
+	for _, s := range []string{
+		"tests/a_test.go",
+		".ptsd/bdd/a.feature::tests/a_test.go",
+		".ptsd/bdd/a.feature#login::tests/a_test.go",
+		".ptsd/bdd/a.feature#login::tests/a.test.ts::logs in",
+	} {
+		if got := parseTestMapping(s).String(); got != s {
+			t.Errorf("round trip of %q gave %q", s, got)
+		}
+	}
+	m := parseTestMapping(".ptsd/bdd/a.feature#login::tests/a.test.ts::logs in")
+	if m.BDD != ".ptsd/bdd/a.feature" || m.Scenario != "login" || m.File != "tests/a.test.ts" || m.Func != "logs in" {
+		t.Errorf("unexpected parse: %+v", m)
+	}
+}
@@ -0,0 +1,192 @@
+package core
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// acceptanceTag links a BDD scenario to a PRD acceptance criterion:
+// @ac:AC-2 (or @ac:2).
+const acceptanceTag = "@ac:"
+
+// AcceptanceCriterion is one bullet from a PRD section's acceptance criteria.
+type AcceptanceCriterion struct {
+	ID        string // explicit "AC-3:" prefix, or AC-<position>
+	Text      string
+	Scenarios []string // IDs of scenarios tracing to it
+}
+
+// TracedScenario is one BDD scenario with the criteria it traces to.
+type TracedScenario struct {
+	ID       string
+	Title    string
+	Criteria []string
+	Unknown  []string // @ac tags naming no criterion
+}
+
+// TraceMatrix relates a feature's acceptance criteria to its BDD scenarios.
+type TraceMatrix struct {
+	Feature   string
+	Criteria  []AcceptanceCriterion
+	Scenarios []TracedScenario
+}
+
+// Uncovered returns criteria with no scenario.
+func (m TraceMatrix) Uncovered() []AcceptanceCriterion {
+	var out []AcceptanceCriterion
+	for _, c := range m.Criteria {
+		if len(c.Scenarios) == 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Untraced returns scenarios that trace to no criterion.
+func (m TraceMatrix) Untraced() []TracedScenario {
+	var out []TracedScenario
+	for _, s := range m.Scenarios {
+		if len(s.Criteria) == 0 {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// TraceFeature builds the acceptance-criteria matrix for a feature. A
+// scenario traces to a criterion through an @ac:<id> tag or, when it has no
+// such tag, by having the criterion's text as its title. A missing BDD file
+// leaves every criterion uncovered.
+func TraceFeature(projectDir string, featureID string) (TraceMatrix, error) {
+	m := TraceMatrix{Feature: featureID}
+
+	section, err := ExtractPRDSection(projectDir, featureID)
+	if err != nil {
+		return m, err
+	}
+	m.Criteria = parseAcceptanceCriteria(section.Content)
+
+	data, err := os.ReadFile(bddFilePath(projectDir, featureID))
+	if err != nil {
+		return m, nil
+	}
+	ff, _ := parseFeatureContent(string(data))
+
+	index := make(map[string]int)
+	for i, c := range m.Criteria {
+		index[c.ID] = i
+	}
+	for _, sc := range ff.Scenarios {
+		ts := TracedScenario{ID: sc.ID, Title: sc.Title}
+		tagged := false
+		for _, tag := range sc.Tags {
+			if !strings.HasPrefix(tag, acceptanceTag) {
+				continue
+			}
+			tagged = true
+			id := normalizeCriterionID(strings.TrimPrefix(tag, acceptanceTag))
+			if i, ok := index[id]; ok {
+				ts.Criteria = append(ts.Criteria, id)
+				m.Criteria[i].Scenarios = append(m.Criteria[i].Scenarios, sc.ID)
+			} else {
+				ts.Unknown = append(ts.Unknown, id)
+			}
+		}
+		if !tagged {
+			for i, c := range m.Criteria {
+				if strings.EqualFold(strings.TrimSpace(sc.Title), c.Text) {
+					ts.Criteria = append(ts.Criteria, c.ID)
+					m.Criteria[i].Scenarios = append(m.Criteria[i].Scenarios, sc.ID)
+				}
+			}
+		}
+		m.Scenarios = append(m.Scenarios, ts)
+	}
+	return m, nil
+}
+
+// parseAcceptanceCriteria returns the list items following a line that
+// mentions "acceptance criteria" (a heading, bold label or plain line),
+// up to the next heading.
+func parseAcceptanceCriteria(content string) []AcceptanceCriterion {
+	var criteria []AcceptanceCriterion
+	inList := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.Contains(strings.ToLower(trimmed), "acceptance criteria") {
+			inList = true
+			continue
+		}
+		if !inList {
+			continue
+		}
+		if lvl, _ := markdownHeading(trimmed); lvl > 0 {
+			inList = false
+			continue
+		}
+		text, ok := listItemText(trimmed)
+		if !ok {
+			continue
+		}
+		id := "AC-" + strconv.Itoa(len(criteria)+1)
+		if explicit, rest, ok := splitCriterionID(text); ok {
+			id, text = explicit, rest
+		}
+		criteria = append(criteria, AcceptanceCriterion{ID: id, Text: text})
+	}
+	return criteria
+}
+
+// listItemText strips a "-", "*", "+" or "1." list marker and an optional
+// checkbox from line.
+func listItemText(line string) (string, bool) {
+	var rest string
+	switch {
+	case len(line) > 2 && strings.ContainsRune("-*+", rune(line[0])) && line[1] == ' ':
+		rest = line[2:]
+	default:
+		dot := strings.IndexAny(line, ".)")
+		if dot <= 0 || dot+1 >= len(line) || line[dot+1] != ' ' {
+			return "", false
+		}
+		if _, err := strconv.Atoi(line[:dot]); err != nil {
+			return "", false
+		}
+		rest = line[dot+2:]
+	}
+	rest = strings.TrimSpace(rest)
+	if len(rest) >= 3 && rest[0] == '[' && rest[2] == ']' {
+		rest = strings.TrimSpace(rest[3:])
+	}
+	return rest, rest != ""
+}
+
+// splitCriterionID splits "AC-3: text" (also "**AC-3** text", "AC-3 - text").
+func splitCriterionID(text string) (id string, rest string, ok bool) {
+	t := strings.TrimLeft(text, "*_[")
+	if len(t) < 4 || !strings.EqualFold(t[:3], "AC-") {
+		return "", text, false
+	}
+	end := 3
+	for end < len(t) && unicode.IsDigit(rune(t[end])) {
+		end++
+	}
+	if end == 3 {
+		return "", text, false
+	}
+	rest = strings.TrimLeft(t[end:], "*_]:.)- ")
+	return "AC-" + t[3:end], strings.TrimSpace(rest), true
+}
+
+// normalizeCriterionID maps "2", "ac-2" and "AC-2" to "AC-2".
+func normalizeCriterionID(id string) string {
+	if _, err := strconv.Atoi(id); err == nil {
+		return "AC-" + id
+	}
+	if len(id) > 3 && strings.EqualFold(id[:3], "AC-") {
+		return "AC-" + id[3:]
+	}
+	return id
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupTraceFeature(t *testing.T, prd string, bdd string) string {
+	t.Helper()
+	dir := t.TempDir()
+	setupTaskFeatures(t, dir, "login")
+	docs := filepath.Join(dir, ".ptsd", "docs")
+	if err := os.MkdirAll(docs, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(docs, "PRD.md"), []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if bdd != "" {
+		bddDir := filepath.Join(dir, ".ptsd", "bdd")
+		if err := os.MkdirAll(bddDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(bddDir, "login.feature"), []byte(bdd), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const tracePRD = `# PRD
+<!-- feature:login -->
+### Login
+Users sign in.
+
+**Acceptance criteria**
+- Valid credentials open a session
+- [ ] Wrong password returns err:validation
+- AC-7: Locked accounts are refused
+
+### Non-goals
+- SSO
+<!-- feature:other -->
+`
+
+func TestTraceFeature(t *testing.T) {
+	bdd := `@feature:login
+Feature: Login
+  @ac:AC-1
+  Scenario: Happy path
+    Given a user
+  @ac:2 @ac:AC-9
+  Scenario: Bad password
+    Given a user
+  Scenario: Wrong password returns err:validation
+    Given a user
+  Scenario: Remember me
+    Given a user
+`
+	dir := setupTraceFeature(t, tracePRD, bdd)
+
+	m, err := TraceFeature(dir, "login")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(m.Criteria) != 3 {
+		t.Fatalf("expected 3 criteria (non-goals excluded), got %+v", m.Criteria)
+	}
+	if c := m.Criteria[2]; c.ID != "AC-7" || c.Text != "Locked accounts are refused" {
+		t.Errorf("expected explicit AC-7, got %+v", c)
+	}
+	if got := len(m.Criteria[1].Scenarios); got != 2 {
+		t.Errorf("AC-2 should be covered by tag and by title match, got %v", m.Criteria[1].Scenarios)
+	}
+
+	uncovered := m.Uncovered()
+	if len(uncovered) != 1 || uncovered[0].ID != "AC-7" {
+		t.Errorf("expected AC-7 uncovered, got %+v", uncovered)
+	}
+	untraced := m.Untraced()
+	if len(untraced) != 1 || untraced[0].Title != "Remember me" {
+		t.Errorf("expected Remember me untraced, got %+v", untraced)
+	}
+	if unknown := m.Scenarios[1].Unknown; len(unknown) != 1 || unknown[0] != "AC-9" {
+		t.Errorf("expected unknown AC-9 on Bad password, got %v", unknown)
+	}
+}
+
+func TestTraceFeatureWithoutBDD(t *testing.T) {
+	dir := setupTraceFeature(t, tracePRD, "")
+
+	m, err := TraceFeature(dir, "login")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.Uncovered()) != 3 || len(m.Scenarios) != 0 {
+		t.Errorf("expected all criteria uncovered, got %+v", m)
+	}
+}
+
+func TestTraceFeatureMissingAnchor(t *testing.T) {
+	dir := setupTraceFeature(t, "# PRD\n", "")
+
+	_, err := TraceFeature(dir, "login")
+	if err == nil || !strings.HasPrefix(err.Error(), "err:pipeline") {
+		t.Errorf("expected err:pipeline, got %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FeatureTrace links one feature's PRD criteria, BDD scenarios, tests and
+// commits for `ptsd report trace`.
+type FeatureTrace struct {
+	Feature   string
+	Title     string
+	Status    string
+	HasPRD    bool
+	Criteria  []AcceptanceCriterion
+	Scenarios []ScenarioTrace
+	Tests     []TestTrace
+	Commits   []CommitTrace
+}
+
+// ScenarioTrace is a scenario with its criteria and the test files mapped to
+// it via `ptsd test map --scenario`.
+type ScenarioTrace struct {
+	ID       string
+	Title    string
+	Criteria []string
+	Tests    []string
+}
+
+// TestTrace is a test file mapped to the feature and the test functions
+// found in it.
+type TestTrace struct {
+	File      string
+	Functions []string
+}
+
+// CommitTrace is a commit attributed to the feature, by its [scope] or a
+// "Ptsd-Feature: <id>" (or "Feature: <id>") trailer.
+type CommitTrace struct {
+	Hash    string
+	At      time.Time // author date
+	Subject string
+	Source  string   // scope | trailer
+	Tasks   []string // Ptsd-Task trailers
+}
+
+// featureTrailers attribute a commit to features regardless of its scope;
+// the prepare-commit-msg hook writes the first.
+var featureTrailers = []string{TrailerFeature + ":", "Feature:"}
+
+// BuildTraceability returns a FeatureTrace for each requested feature, or
+// for every registered feature when ids is empty. A feature without a PRD
+// anchor is reported with HasPRD false; commits are empty outside a git repo.
+func BuildTraceability(projectDir string, ids []string) ([]FeatureTrace, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]Feature)
+	for _, f := range features {
+		byID[f.ID] = f
+	}
+	if len(ids) == 0 {
+		for _, f := range features {
+			ids = append(ids, f.ID)
+		}
+	}
+
+	state, _ := LoadState(projectDir)
+	commits := featureCommits(projectDir)
+
+	var traces []FeatureTrace
+	for _, id := range ids {
+		f, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("err:validation feature %s not found", id)
+		}
+		tr := FeatureTrace{Feature: f.ID, Title: f.Title, Status: f.Status}
+
+		m, err := TraceFeature(projectDir, f.ID)
+		if err == nil {
+			tr.HasPRD = true
+			tr.Criteria = m.Criteria
+		} else {
+			m = traceScenariosOnly(projectDir, f.ID)
+		}
+
+		var mappings []string
+		if state != nil {
+			if list, ok := state.Features[f.ID].Tests.([]string); ok {
+				mappings = list
+			}
+		}
+		scenarioTests, files := splitTestMappings(mappings)
+		for _, s := range m.Scenarios {
+			tr.Scenarios = append(tr.Scenarios, ScenarioTrace{ID: s.ID, Title: s.Title, Criteria: s.Criteria, Tests: scenarioTests[s.ID]})
+		}
+		for _, file := range files {
+			tr.Tests = append(tr.Tests, TestTrace{File: file, Functions: testFunctions(filepath.Join(projectDir, file))})
+		}
+		tr.Commits = commits[f.ID]
+		traces = append(traces, tr)
+	}
+	return traces, nil
+}
+
+// traceScenariosOnly lists a feature's scenarios when it has no PRD section.
+func traceScenariosOnly(projectDir, featureID string) TraceMatrix {
+	m := TraceMatrix{Feature: featureID}
+	data, err := os.ReadFile(bddFilePath(projectDir, featureID))
+	if err != nil {
+		return m
+	}
+	ff, _ := parseFeatureContent(string(data))
+	for _, sc := range ff.Scenarios {
+		m.Scenarios = append(m.Scenarios, TracedScenario{ID: sc.ID, Title: sc.Title})
+	}
+	return m
+}
+
+// splitTestMappings turns state test entries (see testMapping) into
+// per-scenario tests ("file", or "file::func" for a function mapping) and the
+// sorted set of all test files.
+func splitTestMappings(mappings []string) (map[string][]string, []string) {
+	byScenario := make(map[string][]string)
+	seen := make(map[string]bool)
+	var files []string
+	for _, s := range mappings {
+		m := parseTestMapping(s)
+		if m.Scenario != "" {
+			test := m.File
+			if m.Func != "" {
+				test += "::" + m.Func
+			}
+			byScenario[m.Scenario] = append(byScenario[m.Scenario], test)
+		}
+		if !seen[m.File] {
+			seen[m.File] = true
+			files = append(files, m.File)
+		}
+	}
+	sort.Strings(files)
+	return byScenario, files
+}
+
+var testFuncPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^func (Test\w+)\(`),                 // Go
+	regexp.MustCompile(`^\s*(?:it|test)\(\s*["'](.+?)["']`), // JS/TS
+	regexp.MustCompile(`^\s*def (test_\w+)\(`),              // Python
+}
+
+// testFunctions lists the test function names (or test titles) in a file.
+func testFunctions(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		for _, re := range testFuncPatterns {
+			if m := re.FindStringSubmatch(line); m != nil {
+				names = append(names, m[1])
+				break
+			}
+		}
+	}
+	return names
+}
+
+// FeatureCommits returns the commits attributed to a registered feature,
+// newest first; none outside a git repo.
+func FeatureCommits(projectDir, featureID string) ([]CommitTrace, error) {
+	features, err := loadFeatures(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range features {
+		if f.ID == featureID {
+			return featureCommits(projectDir)[featureID], nil
+		}
+	}
+	return nil, fmt.Errorf("err:validation feature %s not found", featureID)
+}
+
+// featureCommits maps feature IDs to the commits that name them, newest
+// first. It returns nil when git is unavailable or projectDir is not a repo.
+func featureCommits(projectDir string) map[string][]CommitTrace {
+	cmd := exec.Command("git", "log", "--format=%H%x1f%aI%x1f%s%x1f%b%x1e")
+	cmd.Dir = projectDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	result := make(map[string][]CommitTrace)
+	for _, rec := range strings.Split(string(out), "\x1e") {
+		parts := strings.Split(strings.TrimLeft(rec, "\n"), "\x1f")
+		if len(parts) < 4 {
+			continue
+		}
+		hash, subject, body := parts[0], parts[2], parts[3]
+		at, _ := time.Parse(time.RFC3339, parts[1])
+
+		var trailerIDs, tasks []string
+		for _, line := range strings.Split(body, "\n") {
+			line = strings.TrimSpace(line)
+			if v, ok := strings.CutPrefix(line, TrailerTask+":"); ok {
+				tasks = append(tasks, strings.TrimSpace(v))
+				continue
+			}
+			for _, prefix := range featureTrailers {
+				if v, ok := strings.CutPrefix(line, prefix); ok {
+					trailerIDs = append(trailerIDs, strings.Split(v, ",")...)
+					break
+				}
+			}
+		}
+
+		attributed := make(map[string]bool)
+		if scope, _, _, err := ParseCommitMessage(subject); err == nil && scope != "" {
+			attributed[scope] = true
+			result[scope] = append(result[scope], CommitTrace{Hash: hash, At: at, Subject: subject, Source: "scope", Tasks: tasks})
+		}
+		for _, id := range trailerIDs {
+			id = strings.TrimSpace(id)
+			if id != "" && !attributed[id] {
+				attributed[id] = true
+				result[id] = append(result[id], CommitTrace{Hash: hash, At: at, Subject: subject, Source: "trailer", Tasks: tasks})
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildTraceability(t *testing.T) {
+	prd := "# PRD\n<!-- feature:login -->\nAcceptance criteria:\n- Valid credentials open a session\n- Wrong password is refused\n"
+	bdd := "@feature:login\nFeature: Login\n  @id:ok @ac:AC-1\n  Scenario: Happy path\n    Given a user\n  @id:bad\n  Scenario: Wrong password is refused\n    Given a user\n"
+	dir := setupTraceFeature(t, prd, bdd)
+
+	for k, v := range map[string]string{
+		"GIT_AUTHOR_NAME": "test", "GIT_AUTHOR_EMAIL": "test@test.com",
+		"GIT_COMMITTER_NAME": "test", "GIT_COMMITTER_EMAIL": "test@test.com",
+	} {
+		t.Setenv(k, v)
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init")
+	git("commit", "--allow-empty", "-m", "[login] feat: session store")
+	git("commit", "--allow-empty", "-m", "[infra] chore: bump deps\n\nFeature: login, billing")
+	git("commit", "--allow-empty", "-m", "[billing] feat: invoices")
+
+	if err := os.WriteFile(filepath.Join(dir, "login_test.go"), []byte("package x\n\nfunc TestLogin(t *testing.T) {}\nfunc TestLogout(t *testing.T) {}\nfunc helper() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := "features:\n  login:\n    stage: tests\n    tests:\n      - .ptsd/bdd/login.feature#ok::login_test.go\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "state.yaml"), []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	traces, err := BuildTraceability(dir, []string{"login"})
+	if err != nil {
+		t.Fatalf("BuildTraceability failed: %v", err)
+	}
+	if len(traces) != 1 {
+		t.Fatalf("expected one trace, got %d", len(traces))
+	}
+	tr := traces[0]
+
+	if !tr.HasPRD || len(tr.Criteria) != 2 || len(tr.Criteria[1].Scenarios) != 1 {
+		t.Errorf("unexpected criteria: %+v", tr.Criteria)
+	}
+	if len(tr.Scenarios) != 2 || len(tr.Scenarios[0].Tests) != 1 || tr.Scenarios[0].Tests[0] != "login_test.go" {
+		t.Errorf("expected scenario ok mapped to login_test.go, got %+v", tr.Scenarios)
+	}
+	if len(tr.Tests) != 1 || len(tr.Tests[0].Functions) != 2 || tr.Tests[0].Functions[1] != "TestLogout" {
+		t.Errorf("unexpected tests: %+v", tr.Tests)
+	}
+	if len(tr.Commits) != 2 || tr.Commits[0].Source != "trailer" || tr.Commits[1].Subject != "[login] feat: session store" {
+		t.Errorf("unexpected commits: %+v", tr.Commits)
+	}
+}
+
+func TestBuildTraceabilityUnknownFeature(t *testing.T) {
+	dir := setupTraceFeature(t, "# PRD\n", "")
+
+	if _, err := BuildTraceability(dir, []string{"nope"}); err == nil {
+		t.Fatal("expected error for unknown feature")
+	}
+	traces, err := BuildTraceability(dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(traces) != 1 || traces[0].HasPRD || len(traces[0].Commits) != 0 {
+		t.Errorf("expected login without PRD and no commits outside git, got %+v", traces)
+	}
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Commit trailers. The prepare-commit-msg hook appends a Ptsd-Feature
+// trailer for each feature the staged files belong to and a Ptsd-Task
+// trailer for the WIP task being worked on, so a commit stays attributable
+// whatever its [scope]. `ptsd trace <id> --commits` and `ptsd report trace`
+// read them back.
+
+const (
+	TrailerFeature = "Ptsd-Feature"
+	TrailerTask    = "Ptsd-Task"
+)
+
+// CommitTrailers returns the features and tasks a commit of stagedFiles
+// should name. Features come from the auto-track classification of each
+// file. Tasks are the WIP tasks of those features, preferring ones this
+// agent holds a lease on and skipping ones another agent holds; with no
+// classified file, a single such WIP task is used along with its feature.
+func CommitTrailers(projectDir string, stagedFiles []string) (features, tasks []string) {
+	seen := make(map[string]bool)
+	for _, f := range stagedFiles {
+		if id, _, _ := classifyForTracking(projectDir, f); id != "" && !seen[id] {
+			seen[id] = true
+			features = append(features, id)
+		}
+	}
+	sort.Strings(features)
+
+	all, _ := loadTasks(projectDir)
+	leases := taskLeases(projectDir)
+	owner, now := LeaseOwner(), time.Now()
+	var wip, mine []Task
+	for _, t := range all {
+		if t.Status != "WIP" {
+			continue
+		}
+		if l, ok := leases[t.ID]; ok && !l.Expired(now) {
+			if l.Owner != owner {
+				continue
+			}
+			mine = append(mine, t)
+		}
+		wip = append(wip, t)
+	}
+	if len(mine) > 0 {
+		wip = mine
+	}
+
+	if len(features) == 0 {
+		if len(wip) == 1 {
+			return []string{wip[0].Feature}, []string{wip[0].ID}
+		}
+		return nil, nil
+	}
+	for _, t := range wip {
+		if seen[t.Feature] {
+			tasks = append(tasks, t.ID)
+		}
+	}
+	return features, tasks
+}
+
+// AddCommitTrailers is the prepare-commit-msg hook: it appends trailers for
+// the staged files to msgFile, leaving trailers already present alone. Git
+// passes source "merge", "squash" or "commit" (amend, -c/-C) for messages
+// that are not new; those are left untouched.
+func AddCommitTrailers(projectDir, msgFile, source string) (features, tasks []string, err error) {
+	if source == "merge" || source == "squash" || source == "commit" {
+		return nil, nil, nil
+	}
+	features, tasks = CommitTrailers(projectDir, getStagedFiles(projectDir))
+	if len(features) == 0 && len(tasks) == 0 {
+		return nil, nil, nil
+	}
+
+	args := []string{"interpret-trailers", "--in-place", "--if-exists", "addIfDifferent"}
+	for _, id := range features {
+		args = append(args, "--trailer", TrailerFeature+": "+id)
+	}
+	for _, id := range tasks {
+		args = append(args, "--trailer", TrailerTask+": "+id)
+	}
+	cmd := exec.Command("git", append(args, msgFile)...)
+	cmd.Dir = projectDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, nil, fmt.Errorf("err:git interpret-trailers failed: %s", strings.TrimSpace(string(out)))
+	}
+	return features, tasks, nil
+}
+
+func GeneratePrepareCommitMsgHook(projectDir string) error {
+	return installGitHook(projectDir, "prepare-commit-msg", "hooks prepare-commit-msg \"$1\" \"$2\"")
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddCommitTrailers(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:in-progress")
+	tasks := "tasks:\n  - id: T-1\n    feature: auth\n    title: login\n    status: WIP\n    priority: A\n" +
+		"  - id: T-2\n    feature: billing\n    title: invoices\n    status: WIP\n    priority: A\n" +
+		"  - id: T-3\n    feature: auth\n    title: logout\n    status: TODO\n    priority: B\n"
+	if err := os.WriteFile(filepath.Join(dir, ".ptsd", "tasks.yaml"), []byte(tasks), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]string{
+		"GIT_AUTHOR_NAME": "test", "GIT_AUTHOR_EMAIL": "test@test.com",
+		"GIT_COMMITTER_NAME": "test", "GIT_COMMITTER_EMAIL": "test@test.com",
+	} {
+		t.Setenv(k, v)
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init")
+	if err := os.WriteFile(filepath.Join(dir, "auth.go"), []byte("package x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "auth.go")
+
+	msgFile := filepath.Join(dir, "COMMIT_MSG")
+	os.WriteFile(msgFile, []byte("[IMPL] add login\n"), 0644)
+	features, taskIDs, err := AddCommitTrailers(dir, msgFile, "message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(features, []string{"auth"}) || !reflect.DeepEqual(taskIDs, []string{"T-1"}) {
+		t.Errorf("trailers = %v %v, want auth and T-1", features, taskIDs)
+	}
+	want := "[IMPL] add login\n\nPtsd-Feature: auth\nPtsd-Task: T-1\n"
+	if data, _ := os.ReadFile(msgFile); string(data) != want {
+		t.Errorf("message = %q, want %q", data, want)
+	}
+	if _, _, err := AddCommitTrailers(dir, msgFile, "message"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(msgFile); string(data) != want {
+		t.Errorf("trailers added twice: %q", data)
+	}
+
+	amend := filepath.Join(dir, "AMEND_MSG")
+	os.WriteFile(amend, []byte("[IMPL] old\n"), 0644)
+	if _, _, err := AddCommitTrailers(dir, amend, "commit"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(amend); string(data) != "[IMPL] old\n" {
+		t.Errorf("an amended message must be left alone: %q", data)
+	}
+
+	git("commit", "-q", "-F", msgFile)
+	commits, err := FeatureCommits(dir, "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Source != "trailer" || !reflect.DeepEqual(commits[0].Tasks, []string{"T-1"}) || commits[0].At.IsZero() {
+		t.Errorf("unexpected commits: %+v", commits)
+	}
+	if commits, _ := FeatureCommits(dir, "billing"); len(commits) != 0 {
+		t.Errorf("billing has no commits, got %+v", commits)
+	}
+	if _, err := FeatureCommits(dir, "nope"); err == nil || !strings.HasPrefix(err.Error(), "err:validation") {
+		t.Errorf("expected err:validation for an unknown feature, got %v", err)
+	}
+}
+
+func TestCommitTrailersWithoutClassifiedFiles(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:in-progress", "billing:in-progress")
+	tasks := "tasks:\n  - id: T-1\n    feature: auth\n    title: login\n    status: WIP\n    priority: A\n"
+	os.WriteFile(filepath.Join(dir, ".ptsd", "tasks.yaml"), []byte(tasks), 0644)
+
+	features, taskIDs := CommitTrailers(dir, []string{".ptsd/tasks.yaml"})
+	if !reflect.DeepEqual(features, []string{"auth"}) || !reflect.DeepEqual(taskIDs, []string{"T-1"}) {
+		t.Errorf("a single WIP task should be used: %v %v", features, taskIDs)
+	}
+
+	tasks += "  - id: T-2\n    feature: billing\n    title: invoices\n    status: WIP\n    priority: A\n"
+	os.WriteFile(filepath.Join(dir, ".ptsd", "tasks.yaml"), []byte(tasks), 0644)
+	if features, taskIDs := CommitTrailers(dir, nil); features != nil || taskIDs != nil {
+		t.Errorf("two WIP tasks are ambiguous: %v %v", features, taskIDs)
+	}
+
+	// A lease held by this agent picks the task.
+	t.Setenv("PTSD_AGENT", "agent-b")
+	if _, err := ClaimTask(dir, "T-2", "agent-b", DefaultLeaseTTL, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if features, taskIDs := CommitTrailers(dir, nil); !reflect.DeepEqual(taskIDs, []string{"T-2"}) || !reflect.DeepEqual(features, []string{"billing"}) {
+		t.Errorf("expected the leased task T-2, got %v %v", features, taskIDs)
+	}
+}
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Validation cache. The pre-commit hook runs `ptsd validate` on every
+// commit; when nothing it reads has changed since the last passing run, the
+// result is reused. The key covers the staged tree, unstaged and untracked
+// working-tree files, every .ptsd file (including untracked state), the
+// walk limits, and the ptsd binary itself. Only passing results are cached,
+// in .git/ptsd-validate-cache.
+
+// validateCacheSkip are .ptsd entries that change without affecting validation.
+var validateCacheSkip = map[string]bool{
+	"hooks.log":   true,
+	"snapshots":   true,
+	".history":    true, // recorded before validate runs
+	"daemon.sock": true,
+	".lock":       true, // validate holds it while it runs
+	"events.yaml": true, // validate itself appends to it
+}
+
+func validateCachePath(projectDir string) (string, error) {
+	gitDir, err := gitOutput(projectDir, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "ptsd-validate-cache"), nil
+}
+
+// ValidationCacheKey hashes everything a validation run depends on. It fails
+// outside a git repository, where there is no cheap way to detect changes.
+func ValidationCacheKey(projectDir string, limits WalkLimits) (string, error) {
+	tree, err := gitOutput(projectDir, "write-tree")
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "tree %s\nlimits %+v\n", tree, limits)
+	// Gate exemptions expire by date alone.
+	if exemptions, _ := LoadGateExemptions(projectDir); len(exemptions) > 0 {
+		fmt.Fprintf(h, "date %s\n", time.Now().Format(revisitLayout))
+	}
+	if info, err := os.Stat(ptsdBinaryPath()); err == nil {
+		fmt.Fprintf(h, "ptsd %d %d\n", info.Size(), info.ModTime().UnixNano())
+	}
+
+	// Working-tree files git's index does not describe: unstaged edits and
+	// untracked files. .ptsd is hashed separately below.
+	modified, err := gitOutput(projectDir, "diff", "--name-only")
+	if err != nil {
+		return "", err
+	}
+	untracked, err := gitOutput(projectDir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return "", err
+	}
+	var paths []string
+	for _, p := range strings.Split(modified+"\n"+untracked, "\n") {
+		if p != "" && !strings.HasPrefix(p, ".ptsd/") {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		hashFileInto(h, projectDir, p)
+	}
+
+	ptsdDir := filepath.Join(projectDir, ".ptsd")
+	err = filepath.WalkDir(ptsdDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if path != ptsdDir && validateCacheSkip[d.Name()] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			rel, _ := filepath.Rel(projectDir, path)
+			hashFileInto(h, projectDir, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("err:io %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFileInto writes the path and its content (or a deletion marker) to h.
+func hashFileInto(h io.Writer, projectDir, rel string) {
+	fmt.Fprintf(h, "file %s\n", rel)
+	f, err := os.Open(filepath.Join(projectDir, rel))
+	if err != nil {
+		fmt.Fprintln(h, "missing")
+		return
+	}
+	defer f.Close()
+	io.Copy(h, f)
+	fmt.Fprintln(h)
+}
+
+// ValidationCached reports whether the last passing validation had this key.
+func ValidationCached(projectDir string, key string) bool {
+	path, err := validateCachePath(projectDir)
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	return err == nil && strings.TrimSpace(string(data)) == key
+}
+
+// SaveValidationCache records key as the last passing validation.
+func SaveValidationCache(projectDir string, key string) error {
+	path, err := validateCachePath(projectDir)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, []byte(key+"\n"), 0644); err != nil {
+		return fmt.Errorf("err:io %w", err)
+	}
+	return nil
+}
+
+// ValidateWithCache runs ValidateWithLimits unless the last passing run had
+// the same cache key, in which case it returns no errors and cached=true.
+// Outside git it simply validates.
+func ValidateWithCache(projectDir string, limits WalkLimits) (errs []ValidationError, cached bool, err error) {
+	if key, err := ValidationCacheKey(projectDir, limits); err == nil && ValidationCached(projectDir, key) {
+		return nil, true, nil
+	}
+
+	errs, err = ValidateWithLimits(projectDir, limits)
+	if err != nil || len(errs) > 0 {
+		return errs, false, err
+	}
+
+	// Validation may flush autotrack state into .ptsd, so key the result on
+	// the tree as it stands afterwards.
+	if key, err := ValidationCacheKey(projectDir, limits); err == nil {
+		_ = SaveValidationCache(projectDir, key)
+	}
+	return nil, false, nil
+}
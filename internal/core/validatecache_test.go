@@ -0,0 +1,120 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidationCacheKey(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:planned")
+	limits := DefaultWalkLimits
+
+	if _, err := ValidationCacheKey(dir, limits); err == nil {
+		t.Fatal("expected error outside a git repository")
+	}
+
+	cmd := exec.Command("git", "init")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	gitAdd := func(path string) {
+		t.Helper()
+		cmd := exec.Command("git", "add", path)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git add failed: %v\n%s", err, out)
+		}
+	}
+
+	key := func() string {
+		t.Helper()
+		k, err := ValidationCacheKey(dir, limits)
+		if err != nil {
+			t.Fatalf("ValidationCacheKey failed: %v", err)
+		}
+		return k
+	}
+
+	base := key()
+	if again := key(); again != base {
+		t.Fatal("key should be stable when nothing changes")
+	}
+
+	// Untracked file.
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644)
+	k1 := key()
+	if k1 == base {
+		t.Error("untracked file should change the key")
+	}
+
+	// Staging the same content changes the index tree.
+	gitAdd("main.go")
+	k2 := key()
+	if k2 == k1 {
+		t.Error("staging a file should change the key")
+	}
+
+	// Unstaged edit to a tracked file.
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	k3 := key()
+	if k3 == k2 {
+		t.Error("unstaged edit should change the key")
+	}
+
+	// .ptsd changes count even when untracked.
+	os.WriteFile(filepath.Join(dir, ".ptsd", "tasks.yaml"), []byte("tasks:\n"), 0644)
+	k4 := key()
+	if k4 == k3 {
+		t.Error(".ptsd change should change the key")
+	}
+
+	// The hook log is noise.
+	os.WriteFile(filepath.Join(dir, ".ptsd", "hooks.log"), []byte("x\n"), 0644)
+	if key() != k4 {
+		t.Error("hooks.log should not affect the key")
+	}
+
+	// Walk limits are part of the key.
+	limits.MaxDepth = 2
+	if key() == k4 {
+		t.Error("walk limits should change the key")
+	}
+}
+
+func TestValidateWithCache(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:planned")
+	cmd := exec.Command("git", "init")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+
+	errs, cached, err := ValidateWithCache(dir, DefaultWalkLimits)
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("expected clean validation, got errs=%v err=%v", errs, err)
+	}
+	if cached {
+		t.Error("first run should not be cached")
+	}
+
+	_, cached, _ = ValidateWithCache(dir, DefaultWalkLimits)
+	if !cached {
+		t.Error("second run with no changes should be cached")
+	}
+
+	// Breaking a feature invalidates the cache and the failure is not stored.
+	os.WriteFile(filepath.Join(dir, ".ptsd", "features.yaml"),
+		[]byte("features:\n  - id: auth\n    status: in-progress\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".ptsd", "bdd", "auth.feature"), []byte("Feature: Auth\n"), 0644)
+	errs, cached, _ = ValidateWithCache(dir, DefaultWalkLimits)
+	if cached || len(errs) == 0 {
+		t.Fatalf("expected uncached failure, got cached=%v errs=%v", cached, errs)
+	}
+	_, cached, _ = ValidateWithCache(dir, DefaultWalkLimits)
+	if cached {
+		t.Error("failed validation must not be cached")
+	}
+}
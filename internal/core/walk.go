@@ -0,0 +1,147 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WalkLimits bounds a discovery walk so pathological trees (huge vendored
+// directories, symlink loops) fail fast instead of hanging.
+type WalkLimits struct {
+	MaxDepth    int           // directory levels below the root; 0 = unlimited
+	MaxFiles    int           // files visited before the walk fails; 0 = unlimited
+	MaxFileSize int64         // larger files are skipped; 0 = unlimited
+	Timeout     time.Duration // 0 = unlimited
+}
+
+// DefaultWalkLimits apply when ptsd.yaml has no discovery section.
+var DefaultWalkLimits = WalkLimits{
+	MaxFiles:    200000,
+	MaxFileSize: 10 << 20,
+	Timeout:     60 * time.Second,
+}
+
+// ProjectWalkLimits returns DefaultWalkLimits overridden by the discovery
+// section of the project config.
+func ProjectWalkLimits(projectDir string) WalkLimits {
+	limits := DefaultWalkLimits
+	cfg, err := LoadConfig(projectDir)
+	if err != nil {
+		return limits
+	}
+	d := cfg.Discovery
+	if d.MaxDepth > 0 {
+		limits.MaxDepth = d.MaxDepth
+	}
+	if d.MaxFiles > 0 {
+		limits.MaxFiles = d.MaxFiles
+	}
+	if d.MaxFileKB > 0 {
+		limits.MaxFileSize = int64(d.MaxFileKB) << 10
+	}
+	if d.Timeout > 0 {
+		limits.Timeout = time.Duration(d.Timeout) * time.Second
+	}
+	return limits
+}
+
+// walkSkipDirs are never descended into by discovery walks.
+var walkSkipDirs = map[string]bool{
+	".git":         true,
+	".ptsd":        true,
+	"node_modules": true,
+}
+
+// walkProject calls fn for every regular file under root within limits.
+// Symlinked directories are followed once; a link back into an already
+// visited directory is skipped. fn may return filepath.SkipAll to stop early.
+// Exceeding MaxFiles or Timeout returns an err:io diagnostic.
+func walkProject(root string, limits WalkLimits, fn func(path string, info os.FileInfo) error) error {
+	w := &projectWalker{
+		root:    root,
+		limits:  limits,
+		fn:      fn,
+		visited: make(map[string]bool),
+	}
+	if limits.Timeout > 0 {
+		w.deadline = time.Now().Add(limits.Timeout)
+	}
+	err := w.walkDir(root, 0)
+	if errors.Is(err, filepath.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+type projectWalker struct {
+	root     string
+	limits   WalkLimits
+	fn       func(path string, info os.FileInfo) error
+	visited  map[string]bool
+	files    int
+	deadline time.Time
+}
+
+func (w *projectWalker) walkDir(dir string, depth int) error {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil
+	}
+	if w.visited[real] {
+		return nil
+	}
+	w.visited[real] = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		info, err := os.Stat(path) // follows symlinks
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			if walkSkipDirs[e.Name()] {
+				continue
+			}
+			if w.limits.MaxDepth > 0 && depth+1 > w.limits.MaxDepth {
+				continue
+			}
+			if err := w.walkDir(path, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		w.files++
+		if w.limits.MaxFiles > 0 && w.files > w.limits.MaxFiles {
+			return fmt.Errorf("err:io discovery stopped: more than %d files under %s (raise discovery.max_files or pass --max-depth)", w.limits.MaxFiles, w.root)
+		}
+		if !w.deadline.IsZero() && w.files%256 == 0 && time.Now().After(w.deadline) {
+			return fmt.Errorf("err:io discovery timed out after %s in %s (raise discovery.timeout or pass --max-depth)", w.limits.Timeout, w.rel(dir))
+		}
+		if w.limits.MaxFileSize > 0 && info.Size() > w.limits.MaxFileSize {
+			continue
+		}
+		if err := w.fn(path, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *projectWalker) rel(path string) string {
+	if r, err := filepath.Rel(w.root, path); err == nil && !strings.HasPrefix(r, "..") {
+		return r
+	}
+	return path
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeWalkFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func collectWalk(t *testing.T, root string, limits WalkLimits) ([]string, error) {
+	t.Helper()
+	var files []string
+	err := walkProject(root, limits, func(path string, info os.FileInfo) error {
+		rel, _ := filepath.Rel(root, path)
+		files = append(files, rel)
+		return nil
+	})
+	return files, err
+}
+
+func TestWalkProjectSymlinkLoop(t *testing.T) {
+	dir := t.TempDir()
+	writeWalkFile(t, filepath.Join(dir, "a", "a_test.go"), "package a")
+	if err := os.Symlink(dir, filepath.Join(dir, "a", "loop")); err != nil {
+		t.Skip("symlinks not supported")
+	}
+
+	files, err := collectWalk(t, dir, WalkLimits{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 1 || files[0] != filepath.Join("a", "a_test.go") {
+		t.Errorf("expected only a/a_test.go, got %v", files)
+	}
+}
+
+func TestWalkProjectMaxFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"one.go", "two.go", "three.go"} {
+		writeWalkFile(t, filepath.Join(dir, name), "package x")
+	}
+
+	_, err := collectWalk(t, dir, WalkLimits{MaxFiles: 2})
+	if err == nil {
+		t.Fatal("expected error when file limit is exceeded")
+	}
+	if !strings.HasPrefix(err.Error(), "err:io") || !strings.Contains(err.Error(), "max_files") {
+		t.Errorf("expected err:io diagnostic naming max_files, got: %v", err)
+	}
+}
+
+func TestWalkProjectMaxDepthAndSize(t *testing.T) {
+	dir := t.TempDir()
+	writeWalkFile(t, filepath.Join(dir, "top_test.go"), "package x")
+	writeWalkFile(t, filepath.Join(dir, "a", "mid_test.go"), "package a")
+	writeWalkFile(t, filepath.Join(dir, "a", "b", "deep_test.go"), "package b")
+	writeWalkFile(t, filepath.Join(dir, "big_test.go"), strings.Repeat("x", 2048))
+	writeWalkFile(t, filepath.Join(dir, "node_modules", "dep_test.go"), "package dep")
+
+	files, err := collectWalk(t, dir, WalkLimits{MaxDepth: 1, MaxFileSize: 1024})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := strings.Join(files, ",")
+	if got != filepath.Join("a", "mid_test.go")+",top_test.go" {
+		t.Errorf("unexpected walk result: %s", got)
+	}
+}
+
+func TestProjectWalkLimitsFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeWalkFile(t, filepath.Join(dir, ".ptsd", "ptsd.yaml"), "discovery:\n  max_depth: 3\n  max_file_kb: 4\n  timeout: 5\n")
+
+	limits := ProjectWalkLimits(dir)
+	if limits.MaxDepth != 3 || limits.MaxFileSize != 4096 || limits.Timeout.Seconds() != 5 {
+		t.Errorf("unexpected limits: %+v", limits)
+	}
+	if limits.MaxFiles != DefaultWalkLimits.MaxFiles {
+		t.Errorf("expected default max_files, got %d", limits.MaxFiles)
+	}
+
+	writeWalkFile(t, filepath.Join(dir, ".ptsd", "ptsd.yaml"), "discovery:\n  max_files: lots\n")
+	if _, err := LoadConfig(dir); err == nil || !strings.HasPrefix(err.Error(), "err:config") {
+		t.Errorf("expected err:config for non-numeric max_files, got: %v", err)
+	}
+}
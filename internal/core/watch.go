@@ -0,0 +1,157 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Watch mode for `ptsd validate --watch`. The watcher polls instead of using
+// OS file notifications: it fingerprints .ptsd/, BDD files and test files by
+// size and modification time and re-validates when the fingerprint changes.
+
+// DefaultWatchInterval is how often validate --watch polls for changes.
+const DefaultWatchInterval = time.Second
+
+// WatchEvent is the result of one validation run in watch mode. Added and
+// Resolved are relative to the previous run; the first run reports every
+// error as added.
+type WatchEvent struct {
+	Changed  []string // files changed since the previous run; nil on the first
+	Errors   []ValidationError
+	Added    []ValidationError
+	Resolved []ValidationError
+	Err      error // validation could not run
+}
+
+// WatchValidate validates projectDir, then re-validates every time a watched
+// file changes, calling fn after each run. It polls every interval until stop
+// is closed.
+func WatchValidate(projectDir string, limits WalkLimits, interval time.Duration, stop <-chan struct{}, fn func(WatchEvent)) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	var prev []ValidationError
+	run := func(changed []string) map[string]string {
+		errs, err := ValidateWithLimits(projectDir, limits)
+		ev := WatchEvent{Changed: changed, Err: err}
+		if err == nil {
+			ev.Errors = errs
+			ev.Added, ev.Resolved = diffValidationErrors(prev, errs)
+			prev = errs
+		}
+		fn(ev)
+		// Fingerprint after the run so validation's own writes (auto-track
+		// flushes) do not trigger another one.
+		return watchFingerprint(projectDir, limits)
+	}
+
+	last := run(nil)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			cur := watchFingerprint(projectDir, limits)
+			if changed := changedPaths(last, cur); len(changed) > 0 {
+				last = run(changed)
+			}
+		}
+	}
+}
+
+// watchFingerprint maps every watched file to its size and mtime.
+func watchFingerprint(projectDir string, limits WalkLimits) map[string]string {
+	files := make(map[string]string)
+	stamp := func(rel string, info os.FileInfo) {
+		files[filepath.ToSlash(rel)] = fmt.Sprintf("%d %d", info.Size(), info.ModTime().UnixNano())
+	}
+
+	ptsdDir := filepath.Join(projectDir, ".ptsd")
+	filepath.WalkDir(ptsdDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if validateCacheSkip[name] || strings.HasPrefix(name, "hooks.log") || strings.HasPrefix(name, "autotrack-") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			rel, _ := filepath.Rel(projectDir, path)
+			stamp(rel, info)
+		}
+		return nil
+	})
+
+	patterns := []string{"**/*_test.go"}
+	if cfg, err := LoadConfig(projectDir); err == nil {
+		patterns = cfg.TestPatterns()
+	}
+	walkProject(projectDir, limits, func(path string, info os.FileInfo) error {
+		rel, _ := filepath.Rel(projectDir, path)
+		rel = filepath.ToSlash(rel)
+		if strings.HasSuffix(rel, ".feature") {
+			stamp(rel, info)
+			return nil
+		}
+		for _, p := range patterns {
+			if matchesTestPattern(rel, p) {
+				stamp(rel, info)
+				break
+			}
+		}
+		return nil
+	})
+	return files
+}
+
+// changedPaths lists files added, removed or modified between fingerprints.
+func changedPaths(prev, cur map[string]string) []string {
+	var changed []string
+	for path, stamp := range cur {
+		if prev[path] != stamp {
+			changed = append(changed, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := cur[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// diffValidationErrors returns the errors in cur that were not in prev, and
+// those in prev that are gone from cur.
+func diffValidationErrors(prev, cur []ValidationError) (added, resolved []ValidationError) {
+	seen := make(map[ValidationError]bool, len(prev))
+	for _, e := range prev {
+		seen[e] = true
+	}
+	now := make(map[ValidationError]bool, len(cur))
+	for _, e := range cur {
+		now[e] = true
+		if !seen[e] {
+			added = append(added, e)
+		}
+	}
+	for _, e := range prev {
+		if !now[e] {
+			resolved = append(resolved, e)
+		}
+	}
+	return added, resolved
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchValidateRerunsOnChange(t *testing.T) {
+	dir := setupProjectWithFeatures(t, "auth:planned")
+
+	events := make(chan WatchEvent, 4)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		WatchValidate(dir, DefaultWalkLimits, 10*time.Millisecond, stop, func(ev WatchEvent) {
+			select {
+			case events <- ev:
+			default:
+			}
+		})
+		close(done)
+	}()
+	defer func() {
+		close(stop)
+		<-done
+	}()
+
+	first := <-events
+	if first.Err != nil || first.Changed != nil {
+		t.Fatalf("unexpected first run: %+v", first)
+	}
+
+	bddDir := filepath.Join(dir, ".ptsd", "bdd")
+	if err := os.MkdirAll(bddDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bddDir, "auth.feature"), []byte("@feature:auth\nFeature: Auth\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case ev := <-events:
+		if !containsString(ev.Changed, ".ptsd/bdd/auth.feature") {
+			t.Errorf("expected the BDD file among changed paths, got %v", ev.Changed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no re-validation after a BDD change")
+	}
+}
+
+func TestDiffValidationErrors(t *testing.T) {
+	a := ValidationError{Feature: "auth", Category: "pipeline", Message: "no seed"}
+	b := ValidationError{Feature: "auth", Category: "pipeline", Message: "no bdd"}
+	c := ValidationError{Feature: "pay", Category: "pipeline", Message: "no prd"}
+
+	added, resolved := diffValidationErrors([]ValidationError{a, b}, []ValidationError{b, c})
+	if len(added) != 1 || added[0] != c || len(resolved) != 1 || resolved[0] != a {
+		t.Errorf("unexpected diff: added=%v resolved=%v", added, resolved)
+	}
+}
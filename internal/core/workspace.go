@@ -0,0 +1,45 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// skipWorkspaceDirs are never descended into when discovering workspaces.
+var skipWorkspaceDirs = map[string]bool{
+	".git":         true,
+	".ptsd":        true,
+	".claude":      true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// DiscoverWorkspaces returns every directory under root (including root itself)
+// that contains .ptsd/ptsd.yaml, sorted by path.
+func DiscoverWorkspaces(root string) ([]string, error) {
+	var workspaces []string
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && skipWorkspaceDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if fileExists(filepath.Join(path, ".ptsd", "ptsd.yaml")) {
+			workspaces = append(workspaces, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("err:io %w", err)
+	}
+
+	sort.Strings(workspaces)
+	return workspaces, nil
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoverWorkspaces(t *testing.T) {
+	root := t.TempDir()
+	for _, ws := range []string{"", "services/api", "web", "node_modules/dep"} {
+		ptsdDir := filepath.Join(root, ws, ".ptsd")
+		if err := os.MkdirAll(ptsdDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(ptsdDir, "ptsd.yaml"), []byte("project:\n  name: x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Directory without config is not a workspace.
+	if err := os.MkdirAll(filepath.Join(root, "docs", ".ptsd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DiscoverWorkspaces(root)
+	if err != nil {
+		t.Fatalf("DiscoverWorkspaces failed: %v", err)
+	}
+
+	want := []string{root, filepath.Join(root, "services", "api"), filepath.Join(root, "web")}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("workspace %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+}
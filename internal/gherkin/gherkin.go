@@ -0,0 +1,504 @@
+// Package gherkin parses Gherkin feature files into an AST: features, rules,
+// backgrounds, scenarios and scenario outlines with their Examples tables,
+// steps with doc strings and data tables, and tags. Scenario outlines expand
+// into one Example per Examples row.
+//
+// Only the English keywords are supported. The parser is forgiving: it keeps
+// going after an error and returns everything it could parse along with the
+// first error.
+package gherkin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Document is a parsed .feature file.
+type Document struct {
+	Feature *Feature
+}
+
+// Tag is a tag such as @smoke or @id:login-ok, including the "@".
+type Tag struct {
+	Name string
+	Line int
+}
+
+// Feature is the root of a document.
+type Feature struct {
+	Tags        []Tag
+	Keyword     string
+	Name        string
+	Description string
+	Line        int
+	Background  *Background
+	Scenarios   []*Scenario
+	Rules       []*Rule
+}
+
+// Rule groups scenarios under a business rule.
+type Rule struct {
+	Tags        []Tag
+	Keyword     string
+	Name        string
+	Description string
+	Line        int
+	Background  *Background
+	Scenarios   []*Scenario
+}
+
+// Background holds steps run before every scenario of its feature or rule.
+type Background struct {
+	Keyword     string
+	Name        string
+	Description string
+	Line        int
+	Steps       []*Step
+}
+
+// Scenario is a Scenario (Example) or a Scenario Outline (Scenario
+// Template); outlines carry Examples.
+type Scenario struct {
+	Tags        []Tag
+	Keyword     string
+	Name        string
+	Description string
+	Line        int
+	Steps       []*Step
+	Examples    []*Examples
+}
+
+// Step is a Given/When/Then/And/But/* step with an optional argument.
+type Step struct {
+	Keyword   string // "Given", "When", "Then", "And", "But" or "*"
+	Text      string
+	Line      int
+	DocString *DocString
+	DataTable *DataTable
+}
+
+// DocString is a """ or ``` delimited block argument.
+type DocString struct {
+	Delimiter string
+	MediaType string
+	Content   string
+	Line      int
+}
+
+// DataTable is a table argument of a step.
+type DataTable struct {
+	Rows []*TableRow
+}
+
+// TableRow is one row of a data table or Examples table.
+type TableRow struct {
+	Cells []string
+	Line  int
+}
+
+// Examples is an Examples (Scenarios) table of a scenario outline.
+type Examples struct {
+	Tags        []Tag
+	Keyword     string
+	Name        string
+	Description string
+	Line        int
+	Header      *TableRow
+	Rows        []*TableRow
+}
+
+// Error is a parse error at a line of the source.
+type Error struct {
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// IsOutline reports whether s is a Scenario Outline.
+func (s *Scenario) IsOutline() bool {
+	return s.Keyword == "Scenario Outline" || s.Keyword == "Scenario Template" || len(s.Examples) > 0
+}
+
+// AllScenarios returns the feature's scenarios followed by those of each
+// rule, in file order.
+func (f *Feature) AllScenarios() []*Scenario {
+	all := append([]*Scenario{}, f.Scenarios...)
+	for _, r := range f.Rules {
+		all = append(all, r.Scenarios...)
+	}
+	return all
+}
+
+var (
+	featureKeywords    = []string{"Feature"}
+	ruleKeywords       = []string{"Rule"}
+	backgroundKeywords = []string{"Background"}
+	outlineKeywords    = []string{"Scenario Outline", "Scenario Template"}
+	scenarioKeywords   = []string{"Scenario", "Example"}
+	examplesKeywords   = []string{"Examples", "Scenarios"}
+	stepKeywords       = []string{"Given", "When", "Then", "And", "But"}
+)
+
+// parser holds the state of one Parse call.
+type parser struct {
+	doc  Document
+	err  error
+	tags []Tag
+
+	rule       *Rule
+	background *Background
+	scenario   *Scenario
+	examples   *Examples
+	step       *Step
+	// desc receives free text after a keyword line until the first step,
+	// table or nested keyword.
+	desc *string
+	// table is where the next table row goes: a step's data table or the
+	// current Examples.
+	tableStep     *Step
+	tableExamples *Examples
+}
+
+// Parse parses src. The document holds everything parsed up to the end of
+// the source even when err is non-nil.
+func Parse(src string) (*Document, error) {
+	p := &parser{}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line, num := lines[i], i+1
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			continue
+
+		case strings.HasPrefix(trimmed, `"""`) || strings.HasPrefix(trimmed, "```"):
+			i = p.docString(lines, i)
+
+		case strings.HasPrefix(trimmed, "#"):
+			continue
+
+		case strings.HasPrefix(trimmed, "@"):
+			p.tagLine(trimmed, num)
+
+		case strings.HasPrefix(trimmed, "|"):
+			p.tableRow(trimmed, num)
+
+		default:
+			if kw, name, ok := keywordLine(trimmed, featureKeywords); ok {
+				p.feature(kw, name, num)
+			} else if kw, name, ok := keywordLine(trimmed, ruleKeywords); ok {
+				p.startRule(kw, name, num)
+			} else if kw, name, ok := keywordLine(trimmed, backgroundKeywords); ok {
+				p.startBackground(kw, name, num)
+			} else if kw, name, ok := keywordLine(trimmed, outlineKeywords); ok {
+				p.startScenario(kw, name, num)
+			} else if kw, name, ok := keywordLine(trimmed, scenarioKeywords); ok {
+				p.startScenario(kw, name, num)
+			} else if kw, name, ok := keywordLine(trimmed, examplesKeywords); ok {
+				p.startExamples(kw, name, num)
+			} else if kw, text, ok := stepLine(trimmed); ok {
+				p.addStep(kw, text, num)
+			} else {
+				p.text(trimmed, num)
+			}
+		}
+	}
+	if len(p.tags) > 0 {
+		p.fail(p.tags[0].Line, "tags are not followed by a feature, rule, scenario or examples")
+	}
+	return &p.doc, p.err
+}
+
+func (p *parser) fail(line int, format string, args ...any) {
+	if p.err == nil {
+		p.err = &Error{Line: line, Msg: fmt.Sprintf(format, args...)}
+	}
+}
+
+// takeTags returns and clears the pending tags.
+func (p *parser) takeTags() []Tag {
+	tags := p.tags
+	p.tags = nil
+	return tags
+}
+
+// ensureFeature returns the feature, creating an unnamed one (and recording
+// an error) when a scenario or background appears before any Feature line.
+func (p *parser) ensureFeature(line int) *Feature {
+	if p.doc.Feature == nil {
+		p.fail(line, "expected Feature before this line")
+		p.doc.Feature = &Feature{Line: line}
+	}
+	return p.doc.Feature
+}
+
+func (p *parser) feature(kw, name string, line int) {
+	if p.doc.Feature != nil {
+		p.fail(line, "only one Feature per file")
+		return
+	}
+	p.doc.Feature = &Feature{Tags: p.takeTags(), Keyword: kw, Name: name, Line: line}
+	p.reset()
+	p.desc = &p.doc.Feature.Description
+}
+
+func (p *parser) reset() {
+	p.background, p.scenario, p.examples, p.step = nil, nil, nil, nil
+	p.tableStep, p.tableExamples, p.desc = nil, nil, nil
+}
+
+func (p *parser) startRule(kw, name string, line int) {
+	f := p.ensureFeature(line)
+	r := &Rule{Tags: p.takeTags(), Keyword: kw, Name: name, Line: line}
+	f.Rules = append(f.Rules, r)
+	p.reset()
+	p.rule = r
+	p.desc = &r.Description
+}
+
+func (p *parser) startBackground(kw, name string, line int) {
+	f := p.ensureFeature(line)
+	if len(p.tags) > 0 {
+		p.fail(line, "Background cannot be tagged")
+		p.tags = nil
+	}
+	b := &Background{Keyword: kw, Name: name, Line: line}
+	target := &f.Background
+	if p.rule != nil {
+		target = &p.rule.Background
+	}
+	if *target != nil {
+		p.fail(line, "only one Background per feature or rule")
+	}
+	if p.scenarioCount() > 0 {
+		p.fail(line, "Background must come before the first scenario")
+	}
+	*target = b
+	p.reset()
+	p.background = b
+	p.desc = &b.Description
+}
+
+// scenarioCount is the number of scenarios in the current feature or rule.
+func (p *parser) scenarioCount() int {
+	if p.rule != nil {
+		return len(p.rule.Scenarios)
+	}
+	if p.doc.Feature == nil {
+		return 0
+	}
+	return len(p.doc.Feature.Scenarios)
+}
+
+func (p *parser) startScenario(kw, name string, line int) {
+	f := p.ensureFeature(line)
+	s := &Scenario{Tags: p.takeTags(), Keyword: kw, Name: name, Line: line}
+	if p.rule != nil {
+		p.rule.Scenarios = append(p.rule.Scenarios, s)
+	} else {
+		f.Scenarios = append(f.Scenarios, s)
+	}
+	p.reset()
+	p.scenario = s
+	p.desc = &s.Description
+}
+
+func (p *parser) startExamples(kw, name string, line int) {
+	if p.scenario == nil || !p.scenario.IsOutline() {
+		p.fail(line, "%s outside a Scenario Outline", kw)
+		p.tags = nil
+		return
+	}
+	ex := &Examples{Tags: p.takeTags(), Keyword: kw, Name: name, Line: line}
+	p.scenario.Examples = append(p.scenario.Examples, ex)
+	p.examples, p.step, p.tableStep = ex, nil, nil
+	p.tableExamples = ex
+	p.desc = &ex.Description
+}
+
+func (p *parser) addStep(kw, text string, line int) {
+	if len(p.tags) > 0 {
+		p.fail(line, "steps cannot be tagged")
+		p.tags = nil
+	}
+	st := &Step{Keyword: kw, Text: text, Line: line}
+	switch {
+	case p.examples != nil:
+		p.fail(line, "step after Examples")
+		return
+	case p.scenario != nil:
+		p.scenario.Steps = append(p.scenario.Steps, st)
+	case p.background != nil:
+		p.background.Steps = append(p.background.Steps, st)
+	default:
+		p.fail(line, "step outside a scenario or background")
+		return
+	}
+	p.step, p.tableStep, p.tableExamples, p.desc = st, st, nil, nil
+}
+
+func (p *parser) text(text string, line int) {
+	if p.desc == nil {
+		p.fail(line, "unexpected text %q", text)
+		return
+	}
+	if *p.desc != "" {
+		*p.desc += "\n"
+	}
+	*p.desc += text
+}
+
+func (p *parser) tagLine(trimmed string, line int) {
+	for _, field := range strings.Fields(trimmed) {
+		if strings.HasPrefix(field, "#") {
+			break
+		}
+		if !strings.HasPrefix(field, "@") || len(field) == 1 {
+			p.fail(line, "invalid tag %q", field)
+			continue
+		}
+		p.tags = append(p.tags, Tag{Name: field, Line: line})
+	}
+}
+
+func (p *parser) tableRow(trimmed string, line int) {
+	p.desc = nil
+	cells, ok := splitCells(trimmed)
+	if !ok {
+		p.fail(line, "table row must end with |")
+	}
+	row := &TableRow{Cells: cells, Line: line}
+	switch {
+	case p.tableExamples != nil:
+		ex := p.tableExamples
+		if ex.Header == nil {
+			ex.Header = row
+			return
+		}
+		if len(cells) != len(ex.Header.Cells) {
+			p.fail(line, "examples row has %d cells, header has %d", len(cells), len(ex.Header.Cells))
+		}
+		ex.Rows = append(ex.Rows, row)
+	case p.tableStep != nil:
+		st := p.tableStep
+		if st.DocString != nil {
+			p.fail(line, "step has both a doc string and a data table")
+			return
+		}
+		if st.DataTable == nil {
+			st.DataTable = &DataTable{}
+		} else if len(cells) != len(st.DataTable.Rows[0].Cells) {
+			p.fail(line, "table row has %d cells, first row has %d", len(cells), len(st.DataTable.Rows[0].Cells))
+		}
+		st.DataTable.Rows = append(st.DataTable.Rows, row)
+	default:
+		p.fail(line, "table row outside a step or Examples")
+	}
+}
+
+// docString consumes the doc string opening at lines[start] and returns the
+// index of its closing delimiter.
+func (p *parser) docString(lines []string, start int) int {
+	open := lines[start]
+	trimmed := strings.TrimSpace(open)
+	delim := trimmed[:3]
+	indent := len(open) - len(strings.TrimLeft(open, " \t"))
+	ds := &DocString{Delimiter: delim, MediaType: strings.TrimSpace(trimmed[3:]), Line: start + 1}
+
+	end := -1
+	var content []string
+	for i := start + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == delim {
+			end = i
+			break
+		}
+		line := lines[i]
+		// Strip the opening delimiter's indentation, as far as it goes.
+		strip := 0
+		for strip < indent && strip < len(line) && (line[strip] == ' ' || line[strip] == '\t') {
+			strip++
+		}
+		content = append(content, line[strip:])
+	}
+	ds.Content = strings.Join(content, "\n")
+	if end == -1 {
+		p.fail(start+1, "doc string is not closed")
+		end = len(lines) - 1
+	}
+
+	st := p.tableStep
+	switch {
+	case st == nil:
+		p.fail(start+1, "doc string outside a step")
+	case st.DocString != nil || st.DataTable != nil:
+		p.fail(start+1, "step already has an argument")
+	default:
+		st.DocString = ds
+	}
+	return end
+}
+
+// keywordLine matches "<keyword>: <name>" for one of keywords.
+func keywordLine(trimmed string, keywords []string) (kw, name string, ok bool) {
+	for _, k := range keywords {
+		if strings.HasPrefix(trimmed, k+":") {
+			return k, strings.TrimSpace(trimmed[len(k)+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// stepLine matches "<step keyword> <text>".
+func stepLine(trimmed string) (kw, text string, ok bool) {
+	if strings.HasPrefix(trimmed, "* ") {
+		return "*", strings.TrimSpace(trimmed[2:]), true
+	}
+	for _, k := range stepKeywords {
+		if strings.HasPrefix(trimmed, k+" ") {
+			return k, strings.TrimSpace(trimmed[len(k)+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// splitCells splits "| a | b |" into its trimmed cells, honouring the \|, \n
+// and \\ escapes. ok is false when the row does not end with a pipe.
+func splitCells(row string) ([]string, bool) {
+	row = strings.TrimPrefix(row, "|")
+	var cells []string
+	var cell strings.Builder
+	closed := false
+	for i := 0; i < len(row); i++ {
+		c := row[i]
+		switch {
+		case c == '\\' && i+1 < len(row):
+			i++
+			switch row[i] {
+			case '|':
+				cell.WriteByte('|')
+			case 'n':
+				cell.WriteByte('\n')
+			case '\\':
+				cell.WriteByte('\\')
+			default:
+				cell.WriteByte('\\')
+				cell.WriteByte(row[i])
+			}
+			closed = false
+		case c == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			closed = true
+		default:
+			cell.WriteByte(c)
+			if c != ' ' && c != '\t' {
+				closed = false
+			}
+		}
+	}
+	return cells, closed
+}
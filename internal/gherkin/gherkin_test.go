@@ -0,0 +1,169 @@
+package gherkin
+
+import (
+	"strings"
+	"testing"
+)
+
+const fullFeature = `# language: en
+@feature:checkout @billing
+Feature: Checkout
+  Customers pay for the cart.
+
+  Background:
+    Given a cart with:
+      | item  | price |
+      | apple | 1.50  |
+
+  @id:pay-card @smoke
+  Scenario: Pay by card
+    When the customer pays with "visa"
+    Then the receipt reads:
+      """text
+      Paid 1.50 by <method>
+      """
+    And the order is closed
+
+  Scenario Outline: Refund <amount>
+    Given a paid order of <amount>
+    When a refund of <amount> is requested
+    Then the status is <status>
+
+    @fast
+    Examples: small
+      | amount | status   |
+      | 1      | refunded |
+      | 5      | refunded |
+
+    Examples: large
+      | amount | status  |
+      | 500    | pending |
+
+  Rule: Gift cards
+    Background:
+      Given gift cards are enabled
+
+    Example: Pay by gift card
+      * the customer pays with a gift card
+      But no change is given
+`
+
+func TestParseFullFeature(t *testing.T) {
+	doc, err := Parse(fullFeature)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	f := doc.Feature
+	if f == nil || f.Name != "Checkout" || f.Line != 3 {
+		t.Fatalf("feature = %+v", f)
+	}
+	if len(f.Tags) != 2 || f.Tags[0].Name != "@feature:checkout" {
+		t.Errorf("feature tags = %+v", f.Tags)
+	}
+	if f.Description != "Customers pay for the cart." {
+		t.Errorf("description = %q", f.Description)
+	}
+
+	bg := f.Background
+	if bg == nil || len(bg.Steps) != 1 || bg.Steps[0].DataTable == nil || len(bg.Steps[0].DataTable.Rows) != 2 {
+		t.Fatalf("background = %+v", bg)
+	}
+	if got := bg.Steps[0].DataTable.Rows[1].Cells; got[0] != "apple" || got[1] != "1.50" {
+		t.Errorf("data table row = %v", got)
+	}
+
+	if len(f.Scenarios) != 2 || len(f.Rules) != 1 {
+		t.Fatalf("scenarios=%d rules=%d", len(f.Scenarios), len(f.Rules))
+	}
+	pay := f.Scenarios[0]
+	if pay.IsOutline() || len(pay.Tags) != 2 || len(pay.Steps) != 3 {
+		t.Errorf("pay scenario = %+v", pay)
+	}
+	ds := pay.Steps[1].DocString
+	if ds == nil || ds.MediaType != "text" || ds.Content != "Paid 1.50 by <method>" {
+		t.Errorf("doc string = %+v", ds)
+	}
+
+	outline := f.Scenarios[1]
+	if !outline.IsOutline() || len(outline.Examples) != 2 {
+		t.Fatalf("outline = %+v", outline)
+	}
+	if ex := outline.Examples[0]; ex.Name != "small" || len(ex.Tags) != 1 || len(ex.Rows) != 2 {
+		t.Errorf("examples = %+v", ex)
+	}
+
+	rule := f.Rules[0]
+	if rule.Name != "Gift cards" || rule.Background == nil || len(rule.Scenarios) != 1 {
+		t.Fatalf("rule = %+v", rule)
+	}
+	steps := rule.Scenarios[0].Steps
+	if len(steps) != 2 || steps[0].Keyword != "*" || steps[1].Keyword != "But" {
+		t.Errorf("rule scenario steps = %+v", steps)
+	}
+
+	if n := len(f.AllScenarios()); n != 3 {
+		t.Errorf("AllScenarios = %d, want 3", n)
+	}
+}
+
+func TestExpandOutline(t *testing.T) {
+	doc, err := Parse(fullFeature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	examples := doc.Feature.Scenarios[1].Expand()
+	if len(examples) != 3 {
+		t.Fatalf("expected 3 expansions, got %d", len(examples))
+	}
+	last := examples[2]
+	if last.Index != 3 || last.Name != "Refund 500" || last.Line != 33 {
+		t.Errorf("last expansion = %+v", last)
+	}
+	if got := last.Steps[2].Text; got != "the status is pending" {
+		t.Errorf("substituted step = %q", got)
+	}
+	if len(examples[0].Tags) != 1 || examples[0].Tags[0].Name != "@fast" {
+		t.Errorf("examples tags not carried: %+v", examples[0].Tags)
+	}
+	if doc.Feature.Scenarios[0].Expand() != nil {
+		t.Error("plain scenario must not expand")
+	}
+}
+
+func TestParseCellEscapes(t *testing.T) {
+	cells, ok := splitCells(`| a \| b | c\nd | e\\ |`)
+	if !ok || len(cells) != 3 || cells[0] != "a | b" || cells[1] != "c\nd" || cells[2] != `e\` {
+		t.Errorf("cells = %q ok=%v", cells, ok)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		name, src, want string
+	}{
+		{"no feature", "Scenario: x\n  Given a\n", "line 1: expected Feature"},
+		{"ragged examples", "Feature: f\n  Scenario Outline: o\n    Given <a>\n    Examples:\n      | a |\n      | 1 | 2 |\n", "line 6: examples row has 2 cells"},
+		{"examples outside outline", "Feature: f\n  Scenario: s\n    Given a\n    Examples:\n", "line 4: Examples outside a Scenario Outline"},
+		{"unclosed doc string", "Feature: f\n  Scenario: s\n    Given a\n      \"\"\"\n      text\n", "line 4: doc string is not closed"},
+		{"stray text", "Feature: f\n  Scenario: s\n    Given a\n    oops\n", `line 4: unexpected text "oops"`},
+		{"dangling tags", "Feature: f\n  @wip\n", "line 2: tags are not followed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.src)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+				t.Errorf("got %v, want prefix %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseKeepsGoingAfterError(t *testing.T) {
+	doc, err := Parse("Scenario: first\n  Given a\n\nScenario: second\n  Given b\n")
+	if err == nil {
+		t.Fatal("expected an error for the missing Feature")
+	}
+	if doc.Feature == nil || len(doc.Feature.Scenarios) != 2 {
+		t.Errorf("expected both scenarios despite the error, got %+v", doc.Feature)
+	}
+}
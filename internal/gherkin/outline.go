@@ -0,0 +1,89 @@
+package gherkin
+
+import "strings"
+
+// Example is one expansion of a scenario outline: the outline with the
+// <placeholders> of its name and steps replaced by one Examples row.
+type Example struct {
+	// Index numbers the expansions of an outline from 1, across all of its
+	// Examples tables.
+	Index int
+	Name  string
+	Line  int   // line of the Examples row
+	Tags  []Tag // the Examples table's tags
+	Steps []*Step
+}
+
+// Expand returns one Example per Examples row of an outline, in file order.
+// It returns nil for a plain scenario.
+func (s *Scenario) Expand() []Example {
+	var out []Example
+	for _, ex := range s.Examples {
+		if ex.Header == nil {
+			continue
+		}
+		for _, row := range ex.Rows {
+			values := make(map[string]string, len(ex.Header.Cells))
+			for i, name := range ex.Header.Cells {
+				if i < len(row.Cells) {
+					values[name] = row.Cells[i]
+				}
+			}
+			e := Example{Index: len(out) + 1, Name: substitute(s.Name, values), Line: row.Line, Tags: ex.Tags}
+			for _, st := range s.Steps {
+				e.Steps = append(e.Steps, substituteStep(st, values))
+			}
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func substituteStep(st *Step, values map[string]string) *Step {
+	out := &Step{Keyword: st.Keyword, Text: substitute(st.Text, values), Line: st.Line}
+	if st.DocString != nil {
+		ds := *st.DocString
+		ds.Content = substitute(ds.Content, values)
+		out.DocString = &ds
+	}
+	if st.DataTable != nil {
+		out.DataTable = &DataTable{}
+		for _, row := range st.DataTable.Rows {
+			cells := make([]string, len(row.Cells))
+			for i, c := range row.Cells {
+				cells[i] = substitute(c, values)
+			}
+			out.DataTable.Rows = append(out.DataTable.Rows, &TableRow{Cells: cells, Line: row.Line})
+		}
+	}
+	return out
+}
+
+// substitute replaces each <name> in text with its value; unknown
+// placeholders are left as they are.
+func substitute(text string, values map[string]string) string {
+	if !strings.Contains(text, "<") {
+		return text
+	}
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(text, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(text[open:], '>')
+		if end < 0 {
+			break
+		}
+		name := text[open+1 : open+end]
+		b.WriteString(text[:open])
+		if v, ok := values[name]; ok {
+			b.WriteString(v)
+		} else {
+			b.WriteString(text[open : open+end+1])
+		}
+		text = text[open+end+1:]
+	}
+	b.WriteString(text)
+	return b.String()
+}
@@ -46,6 +46,25 @@ type TestResultsView struct {
 	Failed   int
 	Duration string
 	Failures []string
+	// StoppedAfter is the failure threshold that ended the run early.
+	StoppedAfter int
+	// Features is the per-feature breakdown of a parallel run.
+	Features []FeatureTestView
+	// FailureDetails carry file and message for failures from a structured
+	// runner report.
+	FailureDetails []TestFailureView
+}
+
+type TestFailureView struct {
+	Name    string
+	File    string
+	Message string
+}
+
+type FeatureTestView struct {
+	Feature string
+	Passed  int
+	Failed  int
 }
 
 type Renderer interface {
@@ -124,5 +143,17 @@ func (r *AgentRenderer) RenderTestResults(results TestResultsView) string {
 	if len(results.Failures) > 0 {
 		out += " fail:" + strings.Join(results.Failures, ",")
 	}
+	if results.StoppedAfter > 0 {
+		out += fmt.Sprintf(" stopped:max-failures=%d", results.StoppedAfter)
+	}
+	if results.Duration != "" {
+		out += " duration:" + results.Duration
+	}
+	for _, f := range results.FailureDetails {
+		out += fmt.Sprintf("\nfailure:%s file:%s %s", f.Name, f.File, f.Message)
+	}
+	for _, f := range results.Features {
+		out += fmt.Sprintf("\nfeature:%s pass:%d fail:%d", f.Feature, f.Passed, f.Failed)
+	}
 	return out
 }
@@ -220,6 +220,38 @@ func TestRenderTestResults(t *testing.T) {
 			},
 			contains: []string{"pass:3", "fail:2", "fail:TestAuth,TestLogin"},
 		},
+		{
+			name: "stopped early",
+			results: TestResultsView{
+				Total: 2, Passed: 1, Failed: 1,
+				Failures:     []string{"TestAuth"},
+				StoppedAfter: 1,
+			},
+			contains: []string{"pass:1", "fail:1", "stopped:max-failures=1"},
+		},
+		{
+			name: "parallel breakdown",
+			results: TestResultsView{
+				Total: 3, Passed: 2, Failed: 1,
+				Features: []FeatureTestView{
+					{Feature: "auth", Passed: 2},
+					{Feature: "sync", Failed: 1},
+				},
+			},
+			contains: []string{"pass:2 fail:1", "\nfeature:auth pass:2 fail:0", "\nfeature:sync pass:0 fail:1"},
+		},
+		{
+			name: "report details",
+			results: TestResultsView{
+				Total: 2, Passed: 1, Failed: 1,
+				Duration: "1.35s",
+				Failures: []string{"auth rejects bad password"},
+				FailureDetails: []TestFailureView{
+					{Name: "auth rejects bad password", File: "src/auth.test.ts", Message: "Error: expected 401"},
+				},
+			},
+			contains: []string{"duration:1.35s", "\nfailure:auth rejects bad password file:src/auth.test.ts Error: expected 401"},
+		},
 	}
 
 	r := &AgentRenderer{}
@@ -0,0 +1,138 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type DashboardCellView struct {
+	State string // done | current | blocked | pending | "" (not in pipeline)
+	Score int    // -1 when not reviewed
+}
+
+type DashboardRowView struct {
+	ID      string
+	Status  string
+	Cells   []DashboardCellView
+	Passed  int
+	Total   int
+	Review  string
+	Pending int
+}
+
+type DashboardView struct {
+	Stages []string
+	Rows   []DashboardRowView
+}
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// RenderDashboard renders the features × stages matrix: one row per feature
+// with a cell per pipeline stage (✓ done, ▶ current, ✗ blocked, · pending,
+// - not in the feature's pipeline) followed by the stage's review score,
+// then test pass counts, the review verdict and pending tasks. color adds
+// ANSI colors for a terminal.
+func RenderDashboard(v DashboardView, color bool) string {
+	idWidth, statusWidth := len("FEATURE"), len("STATUS")
+	for _, r := range v.Rows {
+		idWidth = max(idWidth, len(r.ID))
+		statusWidth = max(statusWidth, len(r.Status))
+	}
+	stageWidth := make([]int, len(v.Stages))
+	for i, s := range v.Stages {
+		stageWidth[i] = max(len(s), 4)
+	}
+
+	var b strings.Builder
+	header := []string{pad("FEATURE", idWidth), pad("STATUS", statusWidth)}
+	for i, s := range v.Stages {
+		header = append(header, pad(s, stageWidth[i]))
+	}
+	header = append(header, pad("TESTS", 7), pad("REVIEW", 7), "TASKS")
+	b.WriteString(strings.Join(header, "  ") + "\n")
+
+	var implemented, blocked, pending int
+	for _, r := range v.Rows {
+		if r.Status == "implemented" {
+			implemented++
+		}
+		pending += r.Pending
+		cols := []string{pad(r.ID, idWidth), pad(r.Status, statusWidth)}
+		rowBlocked := false
+		for i := range v.Stages {
+			cell := DashboardCellView{Score: -1}
+			if i < len(r.Cells) {
+				cell = r.Cells[i]
+			}
+			rowBlocked = rowBlocked || cell.State == "blocked"
+			cols = append(cols, dashboardCell(cell, stageWidth[i], color))
+		}
+		if rowBlocked {
+			blocked++
+		}
+		tests := "-"
+		if r.Total > 0 {
+			tests = fmt.Sprintf("%d/%d", r.Passed, r.Total)
+		}
+		testsCol := pad(tests, 7)
+		if color && r.Total > 0 {
+			c := ansiGreen
+			if r.Passed < r.Total {
+				c = ansiRed
+			}
+			testsCol = c + testsCol + ansiReset
+		}
+		review := r.Review
+		if review == "" {
+			review = "-"
+		}
+		tasks := "-"
+		if r.Pending > 0 {
+			tasks = fmt.Sprint(r.Pending)
+		}
+		cols = append(cols, testsCol, pad(review, 7), tasks)
+		b.WriteString(strings.TrimRight(strings.Join(cols, "  "), " ") + "\n")
+	}
+	fmt.Fprintf(&b, "\n%d features  %d implemented  %d blocked  %d pending tasks\n", len(v.Rows), implemented, blocked, pending)
+	b.WriteString("✓ done  ▶ current  ✗ blocked  · pending  - skipped; digits are review scores")
+	return b.String()
+}
+
+func dashboardCell(c DashboardCellView, width int, color bool) string {
+	var mark, code string
+	switch c.State {
+	case "done":
+		mark, code = "✓", ansiGreen
+	case "current":
+		mark, code = "▶", ansiYellow
+	case "blocked":
+		mark, code = "✗", ansiRed
+	case "pending":
+		mark, code = "·", ansiDim
+	default:
+		mark, code = "-", ansiDim
+	}
+	if c.Score >= 0 && c.State != "" {
+		mark += fmt.Sprint(c.Score)
+	}
+	s := pad(mark, width)
+	if color {
+		return code + s + ansiReset
+	}
+	return s
+}
+
+// pad right-pads s with spaces to width runes.
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -0,0 +1,38 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderDashboard(t *testing.T) {
+	v := DashboardView{
+		Stages: []string{"prd", "bdd", "impl"},
+		Rows: []DashboardRowView{
+			{ID: "auth", Status: "in-progress", Passed: 3, Total: 4, Review: "pending", Pending: 2,
+				Cells: []DashboardCellView{{State: "done", Score: 9}, {State: "current", Score: -1}, {State: "pending", Score: -1}}},
+			{ID: "chore", Status: "implemented",
+				Cells: []DashboardCellView{{State: "done", Score: -1}, {Score: -1}, {State: "blocked", Score: -1}}},
+		},
+	}
+	out := RenderDashboard(v, false)
+	lines := strings.Split(out, "\n")
+	if !strings.HasPrefix(lines[0], "FEATURE  STATUS       prd   bdd   impl  TESTS    REVIEW   TASKS") {
+		t.Errorf("header = %q", lines[0])
+	}
+	if lines[1] != "auth     in-progress  ✓9    ▶     ·     3/4      pending  2" {
+		t.Errorf("auth row = %q", lines[1])
+	}
+	if lines[2] != "chore    implemented  ✓     -     ✗     -        -        -" {
+		t.Errorf("chore row = %q", lines[2])
+	}
+	if !strings.Contains(out, "2 features  1 implemented  1 blocked  2 pending tasks") {
+		t.Errorf("missing summary in:\n%s", out)
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Error("color=false must not emit ANSI escapes")
+	}
+	if !strings.Contains(RenderDashboard(v, true), "\x1b[32m") {
+		t.Error("color=true must color done cells")
+	}
+}
@@ -0,0 +1,138 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+type GraphNodeView struct {
+	ID    string
+	Kind  string
+	Label string
+}
+
+type GraphEdgeView struct {
+	From    string
+	To      string
+	Label   string
+	Blocked bool
+}
+
+type GraphView struct {
+	Nodes []GraphNodeView
+	Edges []GraphEdgeView
+}
+
+// RenderDOT renders a graph in Graphviz DOT format.
+func RenderDOT(g GraphView) string {
+	var b strings.Builder
+	b.WriteString("digraph ptsd {\n  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		shape := "box"
+		if n.Kind == "feature" {
+			shape = "ellipse"
+		}
+		b.WriteString(fmt.Sprintf("  %q [label=%q shape=%s];\n", n.ID, n.Label, shape))
+	}
+	for _, e := range g.Edges {
+		var attrs []string
+		if e.Label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", e.Label))
+		}
+		if e.Blocked {
+			attrs = append(attrs, "style=dashed", "color=red")
+		}
+		line := fmt.Sprintf("  %q -> %q", e.From, e.To)
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, " ") + "]"
+		}
+		b.WriteString(line + ";\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// RenderMermaid renders a graph as a Mermaid flowchart.
+func RenderMermaid(g GraphView) string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		label := strings.ReplaceAll(n.Label, "\"", "'")
+		if n.Kind == "feature" {
+			b.WriteString(fmt.Sprintf("  %s([\"%s\"])\n", mermaidID(n.ID), label))
+		} else {
+			b.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", mermaidID(n.ID), label))
+		}
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Blocked {
+			arrow = "-.->"
+		}
+		if e.Label != "" {
+			if e.Blocked {
+				arrow = "-. " + e.Label + " .->"
+			} else {
+				arrow = "-- " + e.Label + " -->"
+			}
+		}
+		b.WriteString(fmt.Sprintf("  %s %s %s\n", mermaidID(e.From), arrow, mermaidID(e.To)))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// mermaidID converts an arbitrary ID into a Mermaid-safe node identifier.
+func mermaidID(id string) string {
+	var b strings.Builder
+	b.WriteString("n_")
+	for _, c := range id {
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+type PipelineStageView struct {
+	Stage  string
+	State  string // done | current | blocked | pending
+	Reason string
+}
+
+// RenderStateDiagram renders a feature pipeline as a Mermaid state diagram.
+func RenderStateDiagram(feature string, stages []PipelineStageView) string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	b.WriteString("  %% " + feature + "\n")
+	for i, s := range stages {
+		if i == 0 {
+			b.WriteString("  [*] --> " + s.Stage + "\n")
+		} else {
+			b.WriteString("  " + stages[i-1].Stage + " --> " + s.Stage + "\n")
+		}
+		if s.Reason != "" {
+			b.WriteString("  note right of " + s.Stage + ": " + s.Reason + "\n")
+		}
+	}
+	if len(stages) > 0 {
+		b.WriteString("  " + stages[len(stages)-1].Stage + " --> [*]\n")
+	}
+
+	b.WriteString("  classDef done fill:#c8e6c9\n")
+	b.WriteString("  classDef current fill:#fff59d\n")
+	b.WriteString("  classDef blocked fill:#ef9a9a\n")
+	for _, state := range []string{"done", "current", "blocked"} {
+		var ids []string
+		for _, s := range stages {
+			if s.State == state {
+				ids = append(ids, s.Stage)
+			}
+		}
+		if len(ids) > 0 {
+			b.WriteString("  class " + strings.Join(ids, ",") + " " + state + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func sampleGraph() GraphView {
+	return GraphView{
+		Nodes: []GraphNodeView{
+			{ID: "auth", Kind: "feature", Label: "auth (bdd)"},
+			{ID: "T-1", Kind: "task", Label: "T-1 [TODO] Login"},
+		},
+		Edges: []GraphEdgeView{{From: "auth", To: "T-1", Label: "blocked at bdd", Blocked: true}},
+	}
+}
+
+func TestRenderDOT(t *testing.T) {
+	out := RenderDOT(sampleGraph())
+	for _, want := range []string{
+		"digraph ptsd {",
+		`"auth" [label="auth (bdd)" shape=ellipse];`,
+		`"T-1" [label="T-1 [TODO] Login" shape=box];`,
+		`"auth" -> "T-1" [label="blocked at bdd" style=dashed color=red];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderMermaid(t *testing.T) {
+	out := RenderMermaid(sampleGraph())
+	for _, want := range []string{
+		"flowchart LR",
+		`n_auth(["auth (bdd)"])`,
+		`n_T_1["T-1 [TODO] Login"]`,
+		"n_auth -. blocked at bdd .-> n_T_1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderStateDiagram(t *testing.T) {
+	out := RenderStateDiagram("auth", []PipelineStageView{
+		{Stage: "prd", State: "done"},
+		{Stage: "seed", State: "done"},
+		{Stage: "bdd", State: "blocked", Reason: "missing seed"},
+		{Stage: "tests", State: "pending"},
+		{Stage: "impl", State: "pending"},
+	})
+	for _, want := range []string{
+		"stateDiagram-v2",
+		"[*] --> prd",
+		"seed --> bdd",
+		"impl --> [*]",
+		"note right of bdd: missing seed",
+		"class prd,seed done",
+		"class bdd blocked",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, " current\n") || strings.HasSuffix(out, " current") {
+		t.Errorf("no stage is current, got:\n%s", out)
+	}
+}